@@ -2,87 +2,62 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
-	awsconfig "github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
-	"github.com/gin-gonic/gin"
-
-	productHttp "github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http"
-	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/repository"
-	"github.com/tu-usuario/product-crud-hexagonal/internal/core/services"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/app"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/buildinfo"
 	appConfig "github.com/tu-usuario/product-crud-hexagonal/internal/platform/config"
 	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/logger"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/server"
 )
 
 func main() {
 	// Load configuration
-	cfg := appConfig.LoadConfig()
+	cfg, err := appConfig.LoadConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 
 	// Initialize logger
-	appLogger := logger.NewLogger(cfg)
-	appLogger.Info("Starting product service", "port", cfg.Port)
-
-	// AWS SDK Configuration
-	awsCfg, err := awsconfig.LoadDefaultConfig(context.TODO(), awsconfig.WithRegion(cfg.AWSRegion))
+	appLogger, err := logger.NewLogger(cfg)
 	if err != nil {
-		appLogger.Error("unable to load SDK config", "error", err)
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
+	appLogger.Info("Starting product service", "port", cfg.Port, "build", buildinfo.Get())
 
-	dbClient := dynamodb.NewFromConfig(awsCfg)
-
-	// Dependency Injection
-	productRepo := repository.NewDynamoDBRepository(dbClient, cfg.DynamoDBTable)
-	productService := services.NewProductService(productRepo, appLogger)
-	productHandler := productHttp.NewProductHandler(productService, appLogger)
-
-	// Router Setup
-	if cfg.LogLevel == "debug" {
-		gin.SetMode(gin.DebugMode)
-	} else {
-		gin.SetMode(gin.ReleaseMode)
+	application, err := app.New(context.Background(), cfg, appLogger)
+	if err != nil {
+		appLogger.Error("unable to start product service", "error", err)
+		os.Exit(1)
 	}
 
-	router := gin.Default()
-
-	// Middleware
-	router.Use(gin.Recovery())
+	// Background jobs, each run by a single elected instance
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	application.RunJobs(jobsCtx)
 
-	// Health check endpoint
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"status":    "UP",
-			"timestamp": time.Now().UTC(),
-		})
+	// Safe settings are reloaded on SIGHUP and every CONFIG_RELOAD_INTERVAL
+	go appConfig.Watch(jobsCtx, cfg, cfg.ConfigReloadInterval, application.Reload, func(err error) {
+		appLogger.Error("configuration reload failed, keeping the running settings", "error", err)
 	})
 
-	// API routes
-	v1 := router.Group("/api/v1")
-	{
-		products := v1.Group("/products")
-		{
-			products.POST("", productHandler.Create)
-			products.GET("", productHandler.List)
-			products.GET("/:id", productHandler.Get)
-			products.PUT("/:id", productHandler.Update)
-			products.DELETE("/:id", productHandler.Delete)
-		}
-	}
-
 	// Graceful Shutdown
-	srv := &http.Server{
-		Addr:    ":" + cfg.Port,
-		Handler: router,
+	srv, err := server.New(cfg, application.Router)
+	if err != nil {
+		appLogger.Error("unable to configure the HTTP server", "error", err)
+		os.Exit(1)
 	}
 
 	go func() {
-		appLogger.Info("Server starting", "port", cfg.Port)
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		appLogger.Info("Server starting", "port", cfg.Port, "tls", srv.TLSConfig != nil, "mtls", cfg.TLSClientCAFile != "")
+		if err := server.ListenAndServe(srv); err != nil && err != http.ErrServerClosed {
 			appLogger.Error("listen error", "error", err)
 			os.Exit(1)
 		}
@@ -92,6 +67,7 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	appLogger.Info("Shutting down server...")
+	stopJobs()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -99,6 +75,7 @@ func main() {
 		appLogger.Error("Server forced to shutdown", "error", err)
 		os.Exit(1)
 	}
+	application.Close(ctx)
 
 	appLogger.Info("Server exiting")
 }
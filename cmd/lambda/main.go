@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	ginadapter "github.com/awslabs/aws-lambda-go-api-proxy/gin"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/app"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/buildinfo"
+	appConfig "github.com/tu-usuario/product-crud-hexagonal/internal/platform/config"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/logger"
+)
+
+// shutdownTimeout stays under the 500ms Lambda leaves an internal extension
+// between SIGTERM and killing the execution environment
+const shutdownTimeout = 400 * time.Millisecond
+
+// Runs the API as a Lambda function behind API Gateway. The router is built
+// once per execution environment and reused across invocations.
+func main() {
+	cfg, err := appConfig.LoadConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	appLogger, err := logger.NewLogger(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	appLogger.Info("Starting product service on Lambda", "payload_version", cfg.APIGatewayPayloadVersion, "build", buildinfo.Get())
+
+	application, err := app.New(context.Background(), cfg, appLogger)
+	if err != nil {
+		appLogger.Error("unable to start product service", "error", err)
+		os.Exit(1)
+	}
+
+	// Background jobs are not started: a frozen execution environment cannot
+	// keep their schedule, so they stay with the long-running server, the
+	// outbox relay among them. Buffered analytics events and error reports
+	// are flushed when Lambda shuts the execution environment down, which it
+	// signals to the extension WithEnableSIGTERM registers.
+	shutdown := lambda.WithEnableSIGTERM(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		application.Close(ctx)
+	})
+	if cfg.APIGatewayPayloadVersion == "2.0" {
+		// HTTP APIs
+		lambda.StartWithOptions(ginadapter.NewV2(application.Router).ProxyWithContext, shutdown)
+		return
+	}
+	// REST APIs
+	lambda.StartWithOptions(ginadapter.New(application.Router).ProxyWithContext, shutdown)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/repository"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/buildinfo"
+	appConfig "github.com/tu-usuario/product-crud-hexagonal/internal/platform/config"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/logger"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/migrations"
+)
+
+func main() {
+	// Tables of the layout before products, reviews and categories shared
+	// one table, copied into it after it is provisioned
+	var legacy [3]string
+	flag.StringVar(&legacy[0], "copy-products-from", "", "copy the products of this legacy table")
+	flag.StringVar(&legacy[1], "copy-reviews-from", "", "copy the reviews of this legacy table")
+	flag.StringVar(&legacy[2], "copy-categories-from", "", "copy the categories of this legacy table")
+	flag.Parse()
+
+	// Load configuration
+	cfg, err := appConfig.LoadConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	// Initialize logger
+	appLogger, err := logger.NewLogger(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	appLogger.Info("Starting table migration", "table", cfg.DynamoDBTable, "build", buildinfo.Get())
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// AWS SDK Configuration
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(cfg.AWSRegion))
+	if err != nil {
+		appLogger.Error("unable to load SDK config", "error", err)
+		os.Exit(1)
+	}
+
+	dbClient := dynamodb.NewFromConfig(awsCfg)
+
+	// Provision the table and the indexes declared in code, then make
+	// existing items visible to them
+	if _, err := migrations.EnsureTable(ctx, dbClient, cfg.DynamoDBTable, repository.ExpectedSchema(), appLogger); err != nil {
+		appLogger.Error("failed to provision table", "error", err)
+		os.Exit(1)
+	}
+
+	productRepo := repository.NewDynamoDBRepository(dbClient, cfg.DynamoDBTable, repository.WithIndexShards(cfg.IndexShardsFor(cfg.DynamoDBTable)))
+	for i, kind := range []string{repository.LegacyProducts, repository.LegacyReviews, repository.LegacyCategories} {
+		if legacy[i] == "" {
+			continue
+		}
+		if _, err := productRepo.CopyLegacyTable(ctx, legacy[i], kind, appLogger); err != nil {
+			appLogger.Error("failed to copy legacy table", "source", legacy[i], "error", err)
+			os.Exit(1)
+		}
+	}
+	// The index backfill reads the renamed id
+	if _, err := productRepo.RenameLegacyAttributes(ctx, appLogger); err != nil {
+		appLogger.Error("failed to rename legacy product attributes", "error", err)
+		os.Exit(1)
+	}
+	if _, err := productRepo.BackfillIndexAttributes(ctx, appLogger); err != nil {
+		appLogger.Error("failed to backfill index attributes", "error", err)
+		os.Exit(1)
+	}
+	if _, err := productRepo.RecountCategories(ctx, appLogger); err != nil {
+		appLogger.Error("failed to recount category products", "error", err)
+		os.Exit(1)
+	}
+
+	appLogger.Info("Migration finished", "table", cfg.DynamoDBTable)
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/cli"
+)
+
+// Manages products from the command line through a running API
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := cli.Execute(ctx)
+	stop()
+	os.Exit(code)
+}
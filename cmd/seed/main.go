@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/seed"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/app"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/buildinfo"
+	appConfig "github.com/tu-usuario/product-crud-hexagonal/internal/platform/config"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/logger"
+)
+
+// Fills the catalog with fake products for demos and load tests, either
+// through the services against the configured table or through a running
+// API, and reports how fast the writes went
+func main() {
+	options := seed.DefaultOptions()
+	count := flag.Int("count", 1000, "how many products to create")
+	mode := flag.String("mode", "repository", "write through the services (repository) or a running API (api)")
+	apiURL := flag.String("api-url", "http://localhost:8080", "base URL of the API in api mode")
+	timeout := flag.Duration("timeout", 30*time.Second, "timeout of each API call")
+	batch := flag.Int("batch", 0, "products per call; 0 is 25 in repository mode and 1, single creates, in api mode")
+	concurrency := flag.Int("concurrency", 4, "calls in flight at once")
+	truncate := flag.Bool("truncate", false, "delete every product and category first, not only seeded ones")
+	flag.IntVar(&options.Categories, "categories", options.Categories, "categories to spread products over")
+	flag.Float64Var(&options.CategorySkew, "category-skew", options.CategorySkew, "Zipf exponent above 1 favoring the first categories; 0 spreads evenly")
+	flag.Int64Var(&options.MinPrice, "min-price", options.MinPrice, "lowest price, in minor units")
+	flag.Int64Var(&options.MaxPrice, "max-price", options.MaxPrice, "highest price, in minor units")
+	flag.StringVar(&options.Currency, "currency", options.Currency, "currency of the prices")
+	flag.StringVar(&options.PriceDistribution, "prices", options.PriceDistribution, "price distribution: uniform or lognormal")
+	flag.Float64Var(&options.DraftRatio, "drafts", options.DraftRatio, "share of products scheduled to publish later")
+	flag.Float64Var(&options.SKURatio, "skus", options.SKURatio, "share of products with an SKU")
+	flag.Uint64Var(&options.Seed, "seed", 0, "random seed for reproducible data; 0 picks one")
+	flag.Parse()
+
+	generator, err := seed.NewGenerator(options)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	cfg, err := appConfig.LoadConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	appLogger, err := logger.NewLogger(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var target seed.Target
+	// closeApp flushes what the services buffered, such as analytics
+	closeApp := func() {}
+	switch *mode {
+	case "repository":
+		appLogger.Info("Seeding through the services", "table", cfg.DynamoDBTable, "build", buildinfo.Get())
+		application, err := app.New(ctx, cfg, appLogger)
+		if err != nil {
+			appLogger.Error("unable to start seeding", "error", err)
+			os.Exit(1)
+		}
+		closeApp = func() {
+			closeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			application.Close(closeCtx)
+		}
+		target = seed.NewServiceTarget(application.Imports, application.Exports, application.BulkDeletes, application.Categories)
+		if *batch == 0 {
+			*batch = 25
+		}
+	case "api":
+		appLogger.Info("Seeding through the API", "url", *apiURL, "build", buildinfo.Get())
+		// The token is read from the environment so it stays out of the
+		// process list
+		target = seed.NewAPITarget(*apiURL, os.Getenv("SEED_API_TOKEN"), *timeout)
+		if *batch == 0 {
+			*batch = 1
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "unknown mode %q, want repository or api\n", *mode)
+		os.Exit(2)
+	}
+
+	if *truncate {
+		products, categories, err := seed.Truncate(ctx, target, appLogger)
+		if err != nil {
+			appLogger.Error("failed to truncate", "products", products, "categories", categories, "error", err)
+			closeApp()
+			os.Exit(1)
+		}
+		appLogger.Info("Truncated", "products", products, "categories", categories)
+	}
+
+	if *count == 0 {
+		closeApp()
+		return
+	}
+	report, err := seed.Run(ctx, target, generator, *count, *batch, *concurrency, appLogger)
+	closeApp()
+	appLogger.Info("Seeding finished",
+		"categories", report.Categories,
+		"created", report.Created,
+		"failed", report.Failed,
+		"elapsed", report.Elapsed.Round(time.Millisecond).String(),
+		"products_per_second", fmt.Sprintf("%.1f", report.Rate()),
+		"batch", *batch,
+		"latency_p50", report.P50.String(),
+		"latency_p95", report.P95.String(),
+		"latency_p99", report.P99.String(),
+		"latency_max", report.Max.String())
+	if err != nil {
+		appLogger.Error("seeding stopped", "error", err)
+		os.Exit(1)
+	}
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
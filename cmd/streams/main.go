@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/events"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/repository"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/search"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/services"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/buildinfo"
+	appConfig "github.com/tu-usuario/product-crud-hexagonal/internal/platform/config"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/logger"
+)
+
+// Publishes every change recorded in the products table's stream to
+// STREAM_TOPIC_ARN, whatever wrote it, and with SEARCH_INDEXING=stream
+// applies it to the OpenSearch index. Run a single instance: each copy
+// reads the whole stream. Shard checkpoints are kept in LOCKS_TABLE, so a
+// restart resumes where the last run stopped.
+func main() {
+	cfg, err := appConfig.LoadConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	appLogger, err := logger.NewLogger(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if cfg.StreamTopicARN == "" && cfg.SearchIndexing != "stream" {
+		appLogger.Error("STREAM_TOPIC_ARN is required unless SEARCH_INDEXING=stream")
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(cfg.AWSRegion))
+	if err != nil {
+		appLogger.Error("unable to load SDK config", "error", err)
+		os.Exit(1)
+	}
+
+	dbClient := dynamodb.NewFromConfig(awsCfg)
+	streamARN, err := repository.LatestStreamARN(ctx, dbClient, cfg.DynamoDBTable)
+	if err != nil {
+		appLogger.Error("unable to find table stream", "error", err)
+		os.Exit(1)
+	}
+	appLogger.Info("Starting stream worker", "stream", streamARN, "topic", cfg.StreamTopicARN,
+		"start_position", cfg.StreamStartPosition, "build", buildinfo.Get())
+
+	var publishers []ports.EventPublisher
+	if cfg.StreamTopicARN != "" {
+		publishers = append(publishers, events.NewSNSPublisher(sns.NewFromConfig(awsCfg), cfg.StreamTopicARN))
+	}
+	if cfg.SearchIndexing == "stream" {
+		openSearch, err := search.NewOpenSearchRepository(cfg.OpenSearchURL, cfg.OpenSearchIndex, &awsCfg, 5*time.Second)
+		if err != nil {
+			appLogger.Error("unable to set up OpenSearch", "error", err)
+			os.Exit(1)
+		}
+		publishers = append(publishers, services.NewSearchIndexPublisher(openSearch, appLogger))
+		appLogger.Info("OpenSearch indexing enabled", "index", cfg.OpenSearchIndex)
+	}
+
+	publisher := events.NewFanoutPublisher(publishers...)
+	checkpoints := repository.NewDynamoDBStreamCheckpoints(dbClient, cfg.LocksTable)
+	consumer := repository.NewStreamConsumer(dynamodbstreams.NewFromConfig(awsCfg), streamARN, publisher, checkpoints,
+		cfg.StreamPollInterval, cfg.StreamStartPosition, appLogger)
+	consumer.Run(ctx)
+
+	appLogger.Info("Stream worker exiting")
+}
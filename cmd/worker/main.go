@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/queue"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/app"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/buildinfo"
+	appConfig "github.com/tu-usuario/product-crud-hexagonal/internal/platform/config"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/logger"
+)
+
+// Creates products from the messages of IMPORT_QUEUE_URL, through the same
+// service and adapters as the HTTP API
+func main() {
+	cfg, err := appConfig.LoadConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	appLogger, err := logger.NewLogger(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if cfg.ImportQueueURL == "" {
+		appLogger.Error("IMPORT_QUEUE_URL is required")
+		os.Exit(1)
+	}
+	appLogger.Info("Starting product import worker", "queue", cfg.ImportQueueURL, "concurrency", cfg.WorkerConcurrency, "build", buildinfo.Get())
+
+	application, err := app.New(context.Background(), cfg, appLogger)
+	if err != nil {
+		appLogger.Error("unable to start product import worker", "error", err)
+		os.Exit(1)
+	}
+
+	handler := queue.NewProductImportHandler(application.Products, appLogger)
+	consumer := queue.NewConsumer(sqs.NewFromConfig(application.AWS), cfg.ImportQueueURL, cfg.ImportDLQURL,
+		handler, cfg.WorkerConcurrency, cfg.WorkerVisibilityTimeout, appLogger)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	// Returns once the signal arrived and in-flight messages are settled
+	consumer.Run(ctx)
+	appLogger.Info("Shutting down worker...")
+
+	closeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	application.Close(closeCtx)
+
+	appLogger.Info("Worker exiting")
+}
@@ -0,0 +1,23 @@
+package analytics
+
+import (
+	"context"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// FanoutPublisher hands every event to each of its publishers in turn
+type FanoutPublisher struct {
+	publishers []ports.AnalyticsPublisher
+}
+
+func NewFanoutPublisher(publishers ...ports.AnalyticsPublisher) *FanoutPublisher {
+	return &FanoutPublisher{publishers: publishers}
+}
+
+func (p *FanoutPublisher) Track(ctx context.Context, event domain.AnalyticsEvent) {
+	for _, publisher := range p.publishers {
+		publisher.Track(ctx, event)
+	}
+}
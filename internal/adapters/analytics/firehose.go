@@ -0,0 +1,148 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/firehose"
+	"github.com/aws/aws-sdk-go-v2/service/firehose/types"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+const (
+	// maxBatchRecords is the PutRecordBatch limit
+	maxBatchRecords = 500
+	// maxBatchBytes stays under the 4 MiB PutRecordBatch limit
+	maxBatchBytes = 4 << 20
+)
+
+// FirehosePublisher buffers events in memory and ships them to a Kinesis
+// Data Firehose delivery stream in batches from a background goroutine.
+// When the buffer is full new events are dropped rather than blocking the
+// request that produced them.
+type FirehosePublisher struct {
+	client        *firehose.Client
+	streamName    string
+	flushInterval time.Duration
+	logger        *slog.Logger
+
+	events chan domain.AnalyticsEvent
+	done   chan struct{}
+	// closing holds Track out while Close closes events, so no event is
+	// sent on the closed channel
+	closing sync.RWMutex
+	closed  bool
+	dropped atomic.Int64
+}
+
+func NewFirehosePublisher(client *firehose.Client, streamName string, bufferSize int, flushInterval time.Duration, logger *slog.Logger) *FirehosePublisher {
+	p := &FirehosePublisher{
+		client:        client,
+		streamName:    streamName,
+		flushInterval: flushInterval,
+		logger:        logger,
+		events:        make(chan domain.AnalyticsEvent, bufferSize),
+		done:          make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+func (p *FirehosePublisher) Track(ctx context.Context, event domain.AnalyticsEvent) {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+
+	p.closing.RLock()
+	defer p.closing.RUnlock()
+	if p.closed {
+		p.logger.WarnContext(ctx, "analytics publisher closed, dropping event", "type", event.Type, "dropped_total", p.dropped.Add(1))
+		return
+	}
+	select {
+	case p.events <- event:
+	default:
+		p.logger.WarnContext(ctx, "analytics buffer full, dropping event", "type", event.Type, "dropped_total", p.dropped.Add(1))
+	}
+}
+
+// Close stops accepting events and flushes what is buffered, giving up when
+// ctx is done. Events tracked after Close are dropped.
+func (p *FirehosePublisher) Close(ctx context.Context) error {
+	p.closing.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.events)
+	}
+	p.closing.Unlock()
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *FirehosePublisher) run() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.flushInterval)
+	defer ticker.Stop()
+
+	var batch []types.Record
+	batchBytes := 0
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		p.send(batch)
+		batch = nil
+		batchBytes = 0
+	}
+
+	for {
+		select {
+		case event, ok := <-p.events:
+			if !ok {
+				flush()
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				p.logger.Error("failed to marshal analytics event", "type", event.Type, "error", err)
+				continue
+			}
+			// Newline-delimited so the delivered S3 objects are JSON lines
+			data = append(data, '\n')
+			if len(batch) == maxBatchRecords || batchBytes+len(data) > maxBatchBytes {
+				flush()
+			}
+			batch = append(batch, types.Record{Data: data})
+			batchBytes += len(data)
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+func (p *FirehosePublisher) send(batch []types.Record) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	output, err := p.client.PutRecordBatch(ctx, &firehose.PutRecordBatchInput{
+		DeliveryStreamName: aws.String(p.streamName),
+		Records:            batch,
+	})
+	if err != nil {
+		p.logger.Error("failed to deliver analytics events", "records", len(batch), "error", err)
+		return
+	}
+	if failed := aws.ToInt32(output.FailedPutCount); failed > 0 {
+		p.logger.Warn("some analytics events were not delivered", "failed", failed, "records", len(batch))
+	}
+}
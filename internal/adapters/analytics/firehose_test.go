@@ -0,0 +1,26 @@
+package analytics
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+func TestFirehosePublisher_TrackAfterClose(t *testing.T) {
+	// Nothing is buffered, so no batch is ever sent and no client is needed
+	p := NewFirehosePublisher(nil, "events", 1, time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, p.Close(context.Background()))
+	require.NoError(t, p.Close(context.Background()), "closing twice is harmless")
+
+	assert.NotPanics(t, func() {
+		p.Track(context.Background(), domain.AnalyticsEvent{Type: domain.EventProductViewed})
+	})
+	assert.Equal(t, int64(1), p.dropped.Load())
+}
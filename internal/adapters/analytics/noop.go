@@ -0,0 +1,16 @@
+package analytics
+
+import (
+	"context"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// NoopPublisher discards events, for local runs without a delivery stream
+type NoopPublisher struct{}
+
+func NewNoopPublisher() *NoopPublisher {
+	return &NoopPublisher{}
+}
+
+func (p *NoopPublisher) Track(ctx context.Context, event domain.AnalyticsEvent) {}
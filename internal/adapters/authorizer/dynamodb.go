@@ -0,0 +1,95 @@
+package authorizer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// DynamoDBAuthorizer reads each role's actions from a table keyed by role,
+// so permissions change without a deploy. Roles are cached for cacheTTL to
+// keep the lookup off the request path; a role with no item grants nothing.
+type DynamoDBAuthorizer struct {
+	client    *dynamodb.Client
+	tableName string
+	cacheTTL  time.Duration
+	now       func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedRole
+}
+
+type cachedRole struct {
+	actions   []string
+	expiresAt time.Time
+}
+
+type roleItem struct {
+	Role    string   `dynamodbav:"role"`
+	Actions []string `dynamodbav:"actions,stringset"`
+}
+
+func NewDynamoDBAuthorizer(client *dynamodb.Client, tableName string, cacheTTL time.Duration) *DynamoDBAuthorizer {
+	return &DynamoDBAuthorizer{
+		client:    client,
+		tableName: tableName,
+		cacheTTL:  cacheTTL,
+		now:       time.Now,
+		cache:     make(map[string]cachedRole),
+	}
+}
+
+func (a *DynamoDBAuthorizer) Authorize(ctx context.Context, principal domain.Principal, action string) error {
+	policy := make(domain.Policy, len(principal.Roles))
+	for _, role := range principal.Roles {
+		actions, err := a.roleActions(ctx, role)
+		if err != nil {
+			return err
+		}
+		policy[role] = actions
+	}
+
+	if !policy.Allows(principal.Roles, action) {
+		return domain.ErrForbidden
+	}
+	return nil
+}
+
+func (a *DynamoDBAuthorizer) roleActions(ctx context.Context, role string) ([]string, error) {
+	now := a.now()
+	a.mu.Lock()
+	cached, ok := a.cache[role]
+	a.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.actions, nil
+	}
+
+	result, err := a.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(a.tableName),
+		Key: map[string]types.AttributeValue{
+			"role": &types.AttributeValueMemberS{Value: role},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get permissions of role %q: %w", role, err)
+	}
+
+	var item roleItem
+	if result.Item != nil {
+		if err := attributevalue.UnmarshalMap(result.Item, &item); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal permissions of role %q: %w", role, err)
+		}
+	}
+
+	a.mu.Lock()
+	a.cache[role] = cachedRole{actions: item.Actions, expiresAt: now.Add(a.cacheTTL)}
+	a.mu.Unlock()
+	return item.Actions, nil
+}
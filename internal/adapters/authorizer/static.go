@@ -0,0 +1,23 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// StaticAuthorizer enforces a policy fixed at startup
+type StaticAuthorizer struct {
+	policy domain.Policy
+}
+
+func NewStaticAuthorizer(policy domain.Policy) *StaticAuthorizer {
+	return &StaticAuthorizer{policy: policy}
+}
+
+func (a *StaticAuthorizer) Authorize(ctx context.Context, principal domain.Principal, action string) error {
+	if !a.policy.Allows(principal.Roles, action) {
+		return domain.ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,361 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/metrics"
+)
+
+const (
+	productKeyPrefix  = "product:"
+	allProductsPrefix = "products:all"
+)
+
+// allProductsKey caches the full listing of one tenant; the default
+// tenant keeps the key used before multi-tenancy
+func allProductsKey(tenant string) string {
+	if tenant == "" {
+		return allProductsPrefix
+	}
+	return allProductsPrefix + ":" + tenant
+}
+
+// RedisProductRepository is a read-through cache in front of another
+// ProductRepository. GetByID and List are served from Redis for up to ttl;
+// writes made through it invalidate the affected keys, and writes made
+// around it, such as stock adjustments, must go through an Invalidator on
+// the same Redis. Redis failures are logged and fall through to the
+// wrapped repository, so the cache can never take reads down.
+type RedisProductRepository struct {
+	next    ports.ProductRepository
+	client  *redis.Client
+	ttl     time.Duration
+	logger  *slog.Logger
+	metrics *metrics.Metrics
+}
+
+// Invalidator drops cached products, for the repositories that write
+// products without going through a RedisProductRepository
+type Invalidator struct {
+	client *redis.Client
+	logger *slog.Logger
+}
+
+func NewInvalidator(client *redis.Client, logger *slog.Logger) *Invalidator {
+	return &Invalidator{client: client, logger: logger}
+}
+
+// Invalidate drops the product and its tenant's full listing
+func (i *Invalidator) Invalidate(ctx context.Context, tenant, id string) {
+	invalidate(ctx, i.client, i.logger, tenant, id)
+}
+
+// Option customizes a RedisProductRepository
+type Option func(*RedisProductRepository)
+
+// WithMetrics counts cache hits and misses and the products each warming
+// run caches
+func WithMetrics(m *metrics.Metrics) Option {
+	return func(r *RedisProductRepository) {
+		r.metrics = m
+	}
+}
+
+func NewRedisProductRepository(next ports.ProductRepository, client *redis.Client, ttl time.Duration, logger *slog.Logger, opts ...Option) *RedisProductRepository {
+	r := &RedisProductRepository{
+		next:   next,
+		client: client,
+		ttl:    ttl,
+		logger: logger,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Ping checks the Redis connection for the readiness probe
+func (r *RedisProductRepository) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
+func (r *RedisProductRepository) Save(ctx context.Context, product domain.Product) error {
+	if err := r.next.Save(ctx, product); err != nil {
+		return err
+	}
+	r.invalidateAfterCommit(ctx, product.TenantID, product.ID)
+	return nil
+}
+
+// GetByID bypasses the cache for strongly consistent reads, which callers
+// request precisely because they must see the latest write
+func (r *RedisProductRepository) GetByID(ctx context.Context, id string) (domain.Product, error) {
+	if ports.ConsistentRead(ctx) {
+		return r.next.GetByID(ctx, id)
+	}
+
+	var product domain.Product
+	if r.get(ctx, productKeyPrefix+id, &product) {
+		// Entries are shared by ID, so the tenant is checked like the
+		// repository does
+		if product.TenantID != ports.TenantID(ctx) {
+			return domain.Product{}, domain.ErrNotFound
+		}
+		return product, nil
+	}
+	product, err := r.next.GetByID(ctx, id)
+	if err != nil {
+		return domain.Product{}, err
+	}
+	r.set(ctx, productKeyPrefix+id, product)
+	return product, nil
+}
+
+func (r *RedisProductRepository) Update(ctx context.Context, product domain.Product) error {
+	if err := r.next.Update(ctx, product); err != nil {
+		return err
+	}
+	r.invalidateAfterCommit(ctx, product.TenantID, product.ID)
+	return nil
+}
+
+func (r *RedisProductRepository) Delete(ctx context.Context, id string, version int64) error {
+	if err := r.next.Delete(ctx, id, version); err != nil {
+		return err
+	}
+	r.invalidateAfterCommit(ctx, ports.TenantID(ctx), id)
+	return nil
+}
+
+// DeleteBatch deletes through the wrapped repository, which must be a
+// ports.ProductBatchDeleter, and drops the cached copies of the products
+func (r *RedisProductRepository) DeleteBatch(ctx context.Context, products []domain.Product) ([]string, error) {
+	deleter, ok := r.next.(ports.ProductBatchDeleter)
+	if !ok {
+		return nil, errors.New("cached repository cannot delete in batches")
+	}
+	skipped, err := deleter.DeleteBatch(ctx, products)
+	if err != nil {
+		// Some batches may have been written already
+		for _, product := range products {
+			r.invalidate(ctx, ports.TenantID(ctx), product.ID)
+		}
+		return nil, err
+	}
+	kept := make(map[string]bool, len(skipped))
+	for _, id := range skipped {
+		kept[id] = true
+	}
+	for _, product := range products {
+		if !kept[product.ID] {
+			r.invalidate(ctx, ports.TenantID(ctx), product.ID)
+		}
+	}
+	return skipped, nil
+}
+
+// GetByIDs serves the cached products with a single MGET and reads the
+// others from the wrapped repository in one batch, caching them
+func (r *RedisProductRepository) GetByIDs(ctx context.Context, ids []string) ([]domain.Product, error) {
+	if ports.ConsistentRead(ctx) || len(ids) == 0 {
+		return r.next.GetByIDs(ctx, ids)
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = productKeyPrefix + id
+	}
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		r.logger.WarnContext(ctx, "cache read failed", "keys", len(keys), "error", err)
+		values = nil
+	}
+
+	products := make([]domain.Product, 0, len(ids))
+	var misses []string
+	for i, id := range ids {
+		var product domain.Product
+		if i < len(values) {
+			if data, ok := values[i].(string); ok && r.decode(ctx, keys[i], []byte(data), &product) {
+				r.observeLookup(true)
+				if product.TenantID == ports.TenantID(ctx) {
+					products = append(products, product)
+				}
+				continue
+			}
+		}
+		r.observeLookup(false)
+		misses = append(misses, id)
+	}
+	if len(misses) == 0 {
+		return products, nil
+	}
+
+	fetched, err := r.next.GetByIDs(ctx, misses)
+	if err != nil {
+		return nil, err
+	}
+	for _, product := range fetched {
+		r.set(ctx, productKeyPrefix+product.ID, product)
+	}
+	return append(products, fetched...), nil
+}
+
+func (r *RedisProductRepository) List(ctx context.Context) ([]domain.Product, error) {
+	if ports.ConsistentRead(ctx) {
+		return r.next.List(ctx)
+	}
+
+	key := allProductsKey(ports.TenantID(ctx))
+	var products []domain.Product
+	if r.get(ctx, key, &products) {
+		return products, nil
+	}
+	products, err := r.next.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.set(ctx, key, products)
+	return products, nil
+}
+
+// ListWithFilters is not cached: filter combinations are too many to
+// invalidate precisely, and listings already page through indexes
+func (r *RedisProductRepository) ListWithFilters(ctx context.Context, filters ports.ProductFilters) (*ports.ProductListResult, error) {
+	return r.next.ListWithFilters(ctx, filters)
+}
+
+// Count is not cached, like listings
+func (r *RedisProductRepository) Count(ctx context.Context, filters ports.ProductFilters) (int, error) {
+	return r.next.Count(ctx, filters)
+}
+
+// GetBySKU is not cached: an SKU moving between products would need its
+// own invalidation
+func (r *RedisProductRepository) GetBySKU(ctx context.Context, sku string) (domain.Product, error) {
+	return r.next.GetBySKU(ctx, sku)
+}
+
+// Warm caches the limit most recently updated published products of each
+// tenant, so the first reads after a deploy or a Redis restart are hits.
+// Every run rewrites the entries with a fresh TTL; a tenant that fails to
+// list is logged and skipped.
+func (r *RedisProductRepository) Warm(ctx context.Context, tenants []string, limit int) error {
+	warmed := 0
+	var failed error
+	for _, tenant := range tenants {
+		result, err := r.next.ListWithFilters(ports.WithTenant(ctx, tenant), ports.ProductFilters{
+			SortBy:    "updated_at",
+			SortOrder: "desc",
+			Limit:     limit,
+		})
+		if err != nil {
+			r.logger.ErrorContext(ctx, "failed to list products to warm the cache", "tenant", tenant, "error", err)
+			failed = err
+			continue
+		}
+
+		pipe := r.client.Pipeline()
+		for _, product := range result.Products {
+			key := productKeyPrefix + product.ID
+			if data, ok := r.encode(ctx, key, product); ok {
+				pipe.Set(ctx, key, data, r.ttl)
+			}
+		}
+		cmds, err := pipe.Exec(ctx)
+		if err != nil {
+			r.logger.WarnContext(ctx, "cache warming write failed", "tenant", tenant, "error", err)
+			failed = err
+		}
+		for _, cmd := range cmds {
+			if cmd.Err() == nil {
+				warmed++
+			}
+		}
+	}
+
+	if r.metrics != nil {
+		r.metrics.SetCacheWarmed(warmed)
+	}
+	r.logger.InfoContext(ctx, "product cache warmed", "products", warmed, "tenants", len(tenants))
+	return failed
+}
+
+// get reports whether key was cached, decoding it into dest
+func (r *RedisProductRepository) get(ctx context.Context, key string, dest interface{}) bool {
+	data, err := r.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			r.logger.WarnContext(ctx, "cache read failed", "key", key, "error", err)
+		}
+		r.observeLookup(false)
+		return false
+	}
+	hit := r.decode(ctx, key, data, dest)
+	r.observeLookup(hit)
+	return hit
+}
+
+func (r *RedisProductRepository) observeLookup(hit bool) {
+	if r.metrics != nil {
+		r.metrics.ObserveCacheLookup(hit)
+	}
+}
+
+// decode reports whether the cached entry data could be decoded into dest
+func (r *RedisProductRepository) decode(ctx context.Context, key string, data []byte, dest interface{}) bool {
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(dest); err != nil {
+		r.logger.WarnContext(ctx, "discarding unreadable cache entry", "key", key, "error", err)
+		return false
+	}
+	return true
+}
+
+// set caches value under key. Entries are gob-encoded rather than JSON so
+// fields hidden from API responses, such as the cost price, survive.
+func (r *RedisProductRepository) set(ctx context.Context, key string, value interface{}) {
+	data, ok := r.encode(ctx, key, value)
+	if !ok {
+		return
+	}
+	if err := r.client.Set(ctx, key, data, r.ttl).Err(); err != nil {
+		r.logger.WarnContext(ctx, "cache write failed", "key", key, "error", err)
+	}
+}
+
+// encode reports whether value could be encoded as the entry for key
+func (r *RedisProductRepository) encode(ctx context.Context, key string, value interface{}) ([]byte, bool) {
+	var data bytes.Buffer
+	if err := gob.NewEncoder(&data).Encode(value); err != nil {
+		r.logger.WarnContext(ctx, "cache encode failed", "key", key, "error", err)
+		return nil, false
+	}
+	return data.Bytes(), true
+}
+
+func (r *RedisProductRepository) invalidate(ctx context.Context, tenant, id string) {
+	invalidate(ctx, r.client, r.logger, tenant, id)
+}
+
+// invalidateAfterCommit invalidates once the write is visible: inside a
+// unit of work the wrapped repository only enlisted it, and invalidating
+// then would let a concurrent read cache the old product again
+func (r *RedisProductRepository) invalidateAfterCommit(ctx context.Context, tenant, id string) {
+	ports.AfterCommit(ctx, func() { r.invalidate(ctx, tenant, id) })
+}
+
+// invalidate drops the product and its tenant's full listing. A failure
+// leaves stale entries that expire after the TTL.
+func invalidate(ctx context.Context, client *redis.Client, logger *slog.Logger, tenant, id string) {
+	if err := client.Del(ctx, productKeyPrefix+id, allProductsKey(tenant)).Err(); err != nil {
+		logger.WarnContext(ctx, "cache invalidation failed", "id", id, "error", err)
+	}
+}
@@ -0,0 +1,230 @@
+package cache
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports/repotest"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/metrics"
+)
+
+type countingRepository struct {
+	ports.ProductRepository
+	products map[string]domain.Product
+	gets     int
+	batches  int
+	lists    int
+	filters  []ports.ProductFilters
+}
+
+func (c *countingRepository) GetByID(ctx context.Context, id string) (domain.Product, error) {
+	c.gets++
+	product, ok := c.products[id]
+	if !ok {
+		return domain.Product{}, domain.ErrNotFound
+	}
+	return product, nil
+}
+
+func (c *countingRepository) GetByIDs(ctx context.Context, ids []string) ([]domain.Product, error) {
+	c.batches++
+	var products []domain.Product
+	for _, id := range ids {
+		if product, ok := c.products[id]; ok {
+			products = append(products, product)
+		}
+	}
+	return products, nil
+}
+
+func (c *countingRepository) Update(ctx context.Context, product domain.Product) error {
+	c.products[product.ID] = product
+	return nil
+}
+
+func (c *countingRepository) List(ctx context.Context) ([]domain.Product, error) {
+	c.lists++
+	var products []domain.Product
+	for _, product := range c.products {
+		products = append(products, product)
+	}
+	return products, nil
+}
+
+// ListWithFilters answers the tenant's products, up to the limit
+func (c *countingRepository) ListWithFilters(ctx context.Context, filters ports.ProductFilters) (*ports.ProductListResult, error) {
+	c.filters = append(c.filters, filters)
+	var products []domain.Product
+	for _, product := range c.products {
+		if product.TenantID == ports.TenantID(ctx) && len(products) < filters.Limit {
+			products = append(products, product)
+		}
+	}
+	return &ports.ProductListResult{Products: products, TotalItems: len(products)}, nil
+}
+
+func newTestCache(t *testing.T) (*RedisProductRepository, *countingRepository, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	cost := domain.Money{Amount: 450, Currency: "USD"}
+	next := &countingRepository{products: map[string]domain.Product{
+		"1": {ID: "1", Name: "Lamp", Price: domain.Money{Amount: 1000, Currency: "USD"}, CostPrice: &cost, Version: 1},
+	}}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewRedisProductRepository(next, client, time.Minute, logger), next, server
+}
+
+func TestRedisProductRepository_Contract(t *testing.T) {
+	repotest.TestProductRepository(t, func(t *testing.T) ports.ProductRepository {
+		client := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+		logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+		return NewRedisProductRepository(repotest.NewMemoryRepository(), client, time.Minute, logger)
+	})
+}
+
+func TestRedisProductRepository_GetByID(t *testing.T) {
+	repo, next, server := newTestCache(t)
+	ctx := context.Background()
+
+	first, err := repo.GetByID(ctx, "1")
+	require.NoError(t, err)
+	second, err := repo.GetByID(ctx, "1")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, next.gets)
+	assert.Equal(t, first, second)
+	// Fields hidden from JSON responses are cached too
+	require.NotNil(t, second.CostPrice)
+	assert.Equal(t, domain.Money{Amount: 450, Currency: "USD"}, *second.CostPrice)
+	assert.Equal(t, time.Minute, server.TTL(productKeyPrefix+"1"))
+
+	// Consistent reads always go to the repository
+	_, err = repo.GetByID(ports.WithConsistentRead(ctx), "1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, next.gets)
+
+	// Misses are not cached
+	_, err = repo.GetByID(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.False(t, server.Exists(productKeyPrefix+"missing"))
+}
+
+func TestRedisProductRepository_GetByIDs(t *testing.T) {
+	repo, next, server := newTestCache(t)
+	ctx := context.Background()
+
+	products, err := repo.GetByIDs(ctx, []string{"1", "missing"})
+	require.NoError(t, err)
+	require.Len(t, products, 1)
+	assert.Equal(t, 1, next.batches)
+	assert.True(t, server.Exists(productKeyPrefix+"1"))
+
+	// Cached products are not read again; only the misses are
+	products, err = repo.GetByIDs(ctx, []string{"1", "missing"})
+	require.NoError(t, err)
+	require.Len(t, products, 1)
+	assert.Equal(t, "Lamp", products[0].Name)
+	assert.Equal(t, 2, next.batches)
+
+	next.products["2"] = domain.Product{ID: "2", Name: "Rug"}
+	products, err = repo.GetByIDs(ctx, []string{"1", "2"})
+	require.NoError(t, err)
+	assert.Len(t, products, 2)
+	assert.Equal(t, 3, next.batches)
+	products, err = repo.GetByIDs(ctx, []string{"1", "2"})
+	require.NoError(t, err)
+	assert.Len(t, products, 2)
+	assert.Equal(t, 3, next.batches, "fully cached batches never reach the repository")
+
+	// Entries cached for the default tenant are not served to another one
+	products, err = repo.GetByIDs(ports.WithTenant(ctx, "acme"), []string{"1"})
+	require.NoError(t, err)
+	assert.Empty(t, products)
+}
+
+func TestRedisProductRepository_InvalidatesOnWrite(t *testing.T) {
+	repo, next, server := newTestCache(t)
+	ctx := context.Background()
+
+	_, err := repo.GetByID(ctx, "1")
+	require.NoError(t, err)
+	_, err = repo.List(ctx)
+	require.NoError(t, err)
+	assert.True(t, server.Exists(allProductsKey("")))
+
+	require.NoError(t, repo.Update(ctx, domain.Product{ID: "1", Name: "Desk lamp", Price: domain.Money{Amount: 1200, Currency: "USD"}}))
+	assert.False(t, server.Exists(productKeyPrefix+"1"))
+	assert.False(t, server.Exists(allProductsKey("")))
+
+	product, err := repo.GetByID(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, "Desk lamp", product.Name)
+	assert.Equal(t, 2, next.gets)
+}
+
+func TestRedisProductRepository_Warm(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	next := &countingRepository{products: map[string]domain.Product{
+		"1": {ID: "1", Name: "Lamp"},
+		"2": {ID: "2", Name: "Rug", TenantID: "acme"},
+	}}
+	appMetrics := metrics.New()
+	repo := NewRedisProductRepository(next, client, time.Minute, slog.New(slog.NewTextHandler(io.Discard, nil)), WithMetrics(appMetrics))
+	ctx := context.Background()
+
+	require.NoError(t, repo.Warm(ctx, []string{"", "acme"}, 10))
+	require.Len(t, next.filters, 2)
+	assert.Equal(t, "updated_at", next.filters[0].SortBy)
+	assert.Equal(t, "desc", next.filters[0].SortOrder)
+	assert.Equal(t, 10, next.filters[0].Limit)
+	assert.Equal(t, time.Minute, server.TTL(productKeyPrefix+"1"))
+	assert.True(t, server.Exists(productKeyPrefix+"2"))
+
+	// Warmed products are served without reaching the repository
+	product, err := repo.GetByID(ports.WithTenant(ctx, "acme"), "2")
+	require.NoError(t, err)
+	assert.Equal(t, "Rug", product.Name)
+	_, err = repo.GetByID(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.Equal(t, 1, next.gets)
+
+	w := httptest.NewRecorder()
+	appMetrics.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(t, w.Body.String(), `product_api_cache_lookups_total{result="hit"} 1`)
+	assert.Contains(t, w.Body.String(), `product_api_cache_lookups_total{result="miss"} 1`)
+	assert.Contains(t, w.Body.String(), "product_api_cache_warmed_products 2")
+}
+
+func TestRedisProductRepository_RedisDown(t *testing.T) {
+	repo, next, server := newTestCache(t)
+	server.Close()
+
+	product, err := repo.GetByID(context.Background(), "1")
+	require.NoError(t, err)
+	assert.Equal(t, "Lamp", product.Name)
+	assert.Equal(t, 1, next.gets)
+}
+
+func TestRedisProductRepository_TenantIsolation(t *testing.T) {
+	repo, _, _ := newTestCache(t)
+	ctx := context.Background()
+
+	_, err := repo.GetByID(ctx, "1")
+	require.NoError(t, err)
+
+	// The entry cached for the default tenant is not served to another one
+	_, err = repo.GetByID(ports.WithTenant(ctx, "acme"), "1")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
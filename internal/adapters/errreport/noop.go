@@ -0,0 +1,17 @@
+package errreport
+
+import (
+	"context"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// NoopReporter drops panic reports, for runs without an error tracker; the
+// panics are still logged by the recovery middleware
+type NoopReporter struct{}
+
+func NewNoopReporter() *NoopReporter {
+	return &NoopReporter{}
+}
+
+func (r *NoopReporter) ReportPanic(ctx context.Context, report ports.PanicReport) {}
@@ -0,0 +1,67 @@
+// Package errreport ships panics recovered from request handlers to an
+// error tracker.
+package errreport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// defaultFlushTimeout bounds Close when its context has no deadline
+const defaultFlushTimeout = 5 * time.Second
+
+// SentryReporter sends panics to a Sentry project. Events are queued and
+// sent in the background by the SDK's transport.
+type SentryReporter struct {
+	hub *sentry.Hub
+}
+
+// NewSentryReporter reports to the project of dsn, tagging events with
+// environment and release
+func NewSentryReporter(dsn, environment, release string) (*SentryReporter, error) {
+	client, err := sentry.NewClient(sentry.ClientOptions{
+		Dsn:         dsn,
+		Environment: environment,
+		Release:     release,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up Sentry: %w", err)
+	}
+	return &SentryReporter{hub: sentry.NewHub(client, sentry.NewScope())}, nil
+}
+
+// ReportPanic must be called while recovering, so the stack trace the SDK
+// captures is the panicking one
+func (r *SentryReporter) ReportPanic(ctx context.Context, report ports.PanicReport) {
+	hub := r.hub.Clone()
+	scope := hub.Scope()
+	scope.SetTag("request_id", report.RequestID)
+	scope.SetTag("route", report.Route)
+	scope.SetTag("method", report.Method)
+	if report.Tenant != "" {
+		scope.SetTag("tenant", report.Tenant)
+	}
+	scope.SetContext("request", sentry.Context{
+		"method": report.Method,
+		"path":   report.Path,
+	})
+	hub.RecoverWithContext(ctx, report.Value)
+}
+
+// Close sends the events still queued, giving up when ctx is done
+func (r *SentryReporter) Close(ctx context.Context) error {
+	timeout := defaultFlushTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	if !r.hub.Flush(timeout) {
+		return errors.New("sentry events still queued")
+	}
+	return nil
+}
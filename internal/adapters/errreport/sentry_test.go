@@ -0,0 +1,68 @@
+package errreport
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+func TestSentryReporter(t *testing.T) {
+	envelopes := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			decompressed, err := gzip.NewReader(r.Body)
+			require.NoError(t, err)
+			body = decompressed
+		}
+		data, _ := io.ReadAll(body)
+		envelopes <- string(data)
+	}))
+	t.Cleanup(server.Close)
+
+	dsn := strings.Replace(server.URL, "://", "://public@", 1) + "/42"
+	reporter, err := NewSentryReporter(dsn, "staging", "1.4.0")
+	require.NoError(t, err)
+
+	func() {
+		defer func() {
+			value := recover()
+			reporter.ReportPanic(context.Background(), ports.PanicReport{
+				Value:     value,
+				RequestID: "req-9",
+				Method:    http.MethodPut,
+				Route:     "/api/v1/products/:id",
+				Path:      "/api/v1/products/p1",
+				Tenant:    "acme",
+			})
+		}()
+		panic("unexpected state")
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, reporter.Close(ctx))
+
+	select {
+	case envelope := <-envelopes:
+		for _, want := range []string{"unexpected state", `"request_id":"req-9"`, `"tenant":"acme"`, `"environment":"staging"`, `"release":"1.4.0"`} {
+			assert.Contains(t, envelope, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event sent")
+	}
+}
+
+func TestNewSentryReporter_InvalidDSN(t *testing.T) {
+	_, err := NewSentryReporter("not a dsn", "production", "dev")
+	assert.ErrorContains(t, err, "failed to set up Sentry")
+}
@@ -0,0 +1,31 @@
+package events
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// FanoutPublisher hands every event to each of its publishers. An event
+// one of them failed is delivered again to all of them, so each must
+// tolerate duplicates, as at-least-once consumers already do.
+type FanoutPublisher struct {
+	publishers []ports.EventPublisher
+}
+
+func NewFanoutPublisher(publishers ...ports.EventPublisher) *FanoutPublisher {
+	return &FanoutPublisher{publishers: publishers}
+}
+
+// Publish tries every publisher and reports their failures together
+func (p *FanoutPublisher) Publish(ctx context.Context, event domain.ProductEvent) error {
+	var errs []error
+	for _, publisher := range p.publishers {
+		if err := publisher.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,18 @@
+package events
+
+import (
+	"context"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// NoopPublisher discards events, for local runs without a topic
+type NoopPublisher struct{}
+
+func NewNoopPublisher() *NoopPublisher {
+	return &NoopPublisher{}
+}
+
+func (p *NoopPublisher) Publish(ctx context.Context, event domain.ProductEvent) error {
+	return nil
+}
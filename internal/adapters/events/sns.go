@@ -0,0 +1,68 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// eventTypeAttribute lets subscribers filter on the event type without
+// parsing the message body
+const eventTypeAttribute = "event_type"
+
+// SNSAPI is the subset of the SNS client used by the publisher
+type SNSAPI interface {
+	Publish(ctx context.Context, params *sns.PublishInput, optFns ...func(*sns.Options)) (*sns.PublishOutput, error)
+}
+
+// SNSPublisher publishes product events as JSON messages to an SNS topic
+type SNSPublisher struct {
+	client   SNSAPI
+	topicARN string
+}
+
+func NewSNSPublisher(client SNSAPI, topicARN string) *SNSPublisher {
+	return &SNSPublisher{
+		client:   client,
+		topicARN: topicARN,
+	}
+}
+
+func (p *SNSPublisher) Publish(ctx context.Context, event domain.ProductEvent) error {
+	input, err := publishInput(p.topicARN, event)
+	if err != nil {
+		return err
+	}
+	if _, err := p.client.Publish(ctx, input); err != nil {
+		return fmt.Errorf("failed to publish %s event for product %s: %w", event.Type, event.ProductID, err)
+	}
+	return nil
+}
+
+// publishInput builds the SNS message for an event. FIFO topics keep the
+// events of each product in order and drop duplicates of the same event.
+func publishInput(topicARN string, event domain.ProductEvent) (*sns.PublishInput, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s event: %w", event.Type, err)
+	}
+
+	input := &sns.PublishInput{
+		TopicArn: aws.String(topicARN),
+		Message:  aws.String(string(body)),
+		MessageAttributes: map[string]types.MessageAttributeValue{
+			eventTypeAttribute: {DataType: aws.String("String"), StringValue: aws.String(event.Type)},
+		},
+	}
+	if strings.HasSuffix(topicARN, ".fifo") {
+		input.MessageGroupId = aws.String(event.ProductID)
+		input.MessageDeduplicationId = aws.String(event.ID)
+	}
+	return input, nil
+}
@@ -0,0 +1,56 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+type fakeSNS struct {
+	inputs []*sns.PublishInput
+	err    error
+}
+
+func (f *fakeSNS) Publish(ctx context.Context, params *sns.PublishInput, optFns ...func(*sns.Options)) (*sns.PublishOutput, error) {
+	f.inputs = append(f.inputs, params)
+	return &sns.PublishOutput{}, f.err
+}
+
+func TestSNSPublisher_Publish(t *testing.T) {
+	product := &domain.Product{ID: "prod-1", Name: "Laptop", Price: domain.Money{Amount: 99900, Currency: "USD"}}
+	event := domain.NewProductEvent(domain.EventProductCreated, "prod-1", product, time.Now().UTC())
+
+	client := &fakeSNS{}
+	publisher := NewSNSPublisher(client, "arn:aws:sns:us-east-1:123456789012:products")
+	require.NoError(t, publisher.Publish(context.Background(), event))
+
+	require.Len(t, client.inputs, 1)
+	input := client.inputs[0]
+	assert.Equal(t, domain.EventProductCreated, aws.ToString(input.MessageAttributes[eventTypeAttribute].StringValue))
+	assert.Nil(t, input.MessageGroupId)
+
+	var decoded domain.ProductEvent
+	require.NoError(t, json.Unmarshal([]byte(aws.ToString(input.Message)), &decoded))
+	assert.Equal(t, event.ID, decoded.ID)
+	assert.Equal(t, "Laptop", decoded.Product.Name)
+
+	client.err = errors.New("throttled")
+	assert.ErrorContains(t, publisher.Publish(context.Background(), event), "throttled")
+}
+
+func TestPublishInput_FIFOTopic(t *testing.T) {
+	event := domain.NewProductEvent(domain.EventProductDeleted, "prod-1", nil, time.Now().UTC())
+
+	input, err := publishInput("arn:aws:sns:us-east-1:123456789012:products.fifo", event)
+	require.NoError(t, err)
+	assert.Equal(t, "prod-1", aws.ToString(input.MessageGroupId))
+	assert.Equal(t, event.ID, aws.ToString(input.MessageDeduplicationId))
+}
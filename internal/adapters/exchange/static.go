@@ -0,0 +1,55 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// StaticRates quotes fixed rates configured against a base currency, e.g.
+// EUR=0.92 for 0.92 euros per unit of the base. Rates between two non-base
+// currencies are crossed through the base.
+type StaticRates struct {
+	rates map[string]float64
+}
+
+// NewStaticRates parses CODE=RATE entries quoted against base
+func NewStaticRates(base string, entries []string) (*StaticRates, error) {
+	base = strings.ToUpper(base)
+	if _, ok := domain.CurrencyExponent(base); !ok {
+		return nil, fmt.Errorf("base currency %q: %w", base, domain.ErrUnsupportedCurrency)
+	}
+
+	rates := map[string]float64{base: 1}
+	for _, entry := range entries {
+		code, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("exchange rate %q must be CODE=RATE", entry)
+		}
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if _, ok := domain.CurrencyExponent(code); !ok {
+			return nil, fmt.Errorf("exchange rate %q: %w", entry, domain.ErrUnsupportedCurrency)
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("exchange rate %q must be a positive number", entry)
+		}
+		rates[code] = rate
+	}
+	return &StaticRates{rates: rates}, nil
+}
+
+func (r *StaticRates) Rate(ctx context.Context, from, to string) (float64, error) {
+	fromRate, ok := r.rates[from]
+	if !ok {
+		return 0, domain.ErrNoExchangeRate
+	}
+	toRate, ok := r.rates[to]
+	if !ok {
+		return 0, domain.ErrNoExchangeRate
+	}
+	return toRate / fromRate, nil
+}
@@ -0,0 +1,33 @@
+package exchange
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+func TestStaticRates(t *testing.T) {
+	rates, err := NewStaticRates("USD", []string{"eur=0.5", " GBP = 0.8 "})
+	require.NoError(t, err)
+
+	rate, err := rates.Rate(context.Background(), "USD", "EUR")
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, rate)
+
+	rate, err = rates.Rate(context.Background(), "EUR", "GBP")
+	require.NoError(t, err)
+	assert.InDelta(t, 1.6, rate, 1e-9)
+
+	_, err = rates.Rate(context.Background(), "USD", "JPY")
+	assert.ErrorIs(t, err, domain.ErrNoExchangeRate)
+}
+
+func TestNewStaticRates_Invalid(t *testing.T) {
+	for _, entries := range [][]string{{"EUR"}, {"XYZ=1"}, {"EUR=abc"}, {"EUR=0"}} {
+		_, err := NewStaticRates("USD", entries)
+		assert.Error(t, err, entries)
+	}
+}
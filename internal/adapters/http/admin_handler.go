@@ -0,0 +1,163 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/i18n"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+const (
+	defaultSearchTermLimit = 50
+	maxSearchTermLimit     = 100
+	// defaultSearchTermDays is the report period when from is omitted
+	defaultSearchTermDays = 7
+	dateLayout            = "2006-01-02"
+)
+
+type AdminHandler struct {
+	queryService ports.AdminQueryService
+	searchTerms  ports.SearchTermService
+	reports      ports.ReportService
+	logger       *slog.Logger
+}
+
+func NewAdminHandler(queryService ports.AdminQueryService, searchTerms ports.SearchTermService, reports ports.ReportService, logger *slog.Logger) *AdminHandler {
+	return &AdminHandler{
+		queryService: queryService,
+		searchTerms:  searchTerms,
+		reports:      reports,
+		logger:       logger,
+	}
+}
+
+type QueryRequest struct {
+	Statement  string        `json:"statement" binding:"required"`
+	Parameters []interface{} `json:"parameters"`
+	Limit      int           `json:"limit" binding:"omitempty,min=1,max=100"`
+	NextToken  string        `json:"next_token"`
+	Fields     []string      `json:"fields"`
+}
+
+type QueryResponse struct {
+	Items     []map[string]interface{} `json:"items"`
+	Count     int                      `json:"count"`
+	NextToken string                   `json:"next_token,omitempty"`
+}
+
+// Query executes a read-only PartiQL statement against the products table
+func (h *AdminHandler) Query(c *gin.Context) {
+	var req QueryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WarnContext(c.Request.Context(), "invalid request body", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, err.Error())})
+		return
+	}
+
+	query := ports.StatementQuery{
+		Statement:  req.Statement,
+		Parameters: req.Parameters,
+		Limit:      req.Limit,
+		NextToken:  req.NextToken,
+	}
+	result, err := h.queryService.Query(c.Request.Context(), query, req.Fields)
+	if err != nil {
+		if errors.Is(err, domain.ErrForbiddenQuery) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, err.Error())})
+			return
+		}
+		h.logger.ErrorContext(c.Request.Context(), "failed to execute admin query", "error", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, QueryResponse{
+		Items:     result.Items,
+		Count:     len(result.Items),
+		NextToken: result.NextToken,
+	})
+}
+
+type SearchTermsResponse struct {
+	From  string                   `json:"from"`
+	To    string                   `json:"to"`
+	Terms []domain.SearchTermStats `json:"terms"`
+}
+
+// SearchTerms reports the most searched terms, or the ones that found
+// nothing, between two days inclusive
+func (h *AdminHandler) SearchTerms(c *gin.Context) {
+	to := time.Now().UTC()
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse(dateLayout, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "to must be a date in YYYY-MM-DD format")})
+			return
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, -(defaultSearchTermDays - 1))
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse(dateLayout, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "from must be a date in YYYY-MM-DD format")})
+			return
+		}
+		from = parsed
+	}
+
+	limit := defaultSearchTermLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxSearchTermLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "limit must be between 1 and 100")})
+			return
+		}
+		limit = parsed
+	}
+
+	query := ports.SearchTermQuery{
+		From:            from,
+		To:              to,
+		ZeroResultsOnly: c.Query("zero_results") == "true",
+		Limit:           limit,
+	}
+	terms, err := h.searchTerms.Report(c.Request.Context(), query)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidRange) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, err.Error())})
+			return
+		}
+		h.logger.ErrorContext(c.Request.Context(), "failed to report search terms", "error", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SearchTermsResponse{
+		From:  from.Format(dateLayout),
+		To:    to.Format(dateLayout),
+		Terms: terms,
+	})
+}
+
+// MarginReport returns the latest background-generated profitability report
+func (h *AdminHandler) MarginReport(c *gin.Context) {
+	report, err := h.reports.MarginReport(c.Request.Context())
+	if err != nil {
+		if errors.Is(err, domain.ErrReportNotReady) {
+			c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, err.Error())})
+			return
+		}
+		h.logger.ErrorContext(c.Request.Context(), "failed to get margin report", "error", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
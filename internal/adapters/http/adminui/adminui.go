@@ -0,0 +1,49 @@
+// Package adminui embeds a small single-page admin UI for browsing,
+// searching, creating and editing products. The page holds no data of its
+// own: it calls the product API from the browser, with the token and keys
+// the operator enters, so it can see and do no more than they could.
+package adminui
+
+import (
+	"embed"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/i18n"
+)
+
+//go:embed static
+var assets embed.FS
+
+// contentSecurityPolicy keeps the page to its own scripts and the API it
+// is served with
+const contentSecurityPolicy = "default-src 'self'; img-src 'self' data: https:; frame-ancestors 'none'"
+
+// Handler serves the UI under a catch-all route such as /admin/ui/*any
+func Handler() gin.HandlerFunc {
+	static, _ := fs.Sub(assets, "static")
+	return func(c *gin.Context) {
+		name := strings.TrimPrefix(path.Clean(c.Param("any")), "/")
+		if name == "" {
+			name = "index.html"
+		}
+		data, err := fs.ReadFile(static, name)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, "not found")})
+			return
+		}
+		contentType := mime.TypeByExtension(path.Ext(name))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		c.Header("Content-Security-Policy", contentSecurityPolicy)
+		c.Header("X-Content-Type-Options", "nosniff")
+		// Assets are not versioned, so browsers revalidate after a deploy
+		c.Header("Cache-Control", "no-cache")
+		c.Data(http.StatusOK, contentType, data)
+	}
+}
@@ -0,0 +1,39 @@
+package adminui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/ui/*any", Handler())
+
+	for path, contentType := range map[string]string{
+		"/admin/ui/":           "text/html; charset=utf-8",
+		"/admin/ui/index.html": "text/html; charset=utf-8",
+		"/admin/ui/app.js":     "text/javascript; charset=utf-8",
+		"/admin/ui/app.css":    "text/css; charset=utf-8",
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusOK, w.Code, path)
+		assert.Equal(t, contentType, w.Header().Get("Content-Type"), path)
+		assert.Contains(t, w.Header().Get("Content-Security-Policy"), "default-src 'self'", path)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/ui/", nil))
+	assert.Contains(t, w.Body.String(), `<script src="app.js"></script>`)
+
+	for _, path := range []string{"/admin/ui/missing.js", "/admin/ui/../adminui.go", "/admin/ui/static/app.js"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusNotFound, w.Code, path)
+	}
+}
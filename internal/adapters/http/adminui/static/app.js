@@ -0,0 +1,312 @@
+"use strict";
+
+// The admin UI talks to the API it is served with. Credentials live in
+// sessionStorage, so they are gone when the tab is closed.
+(() => {
+  const api = "/api/v1";
+  const pageSize = 20;
+  const $ = (selector) => document.querySelector(selector);
+
+  const state = { page: 1, editing: null, categories: [] };
+
+  function settings() {
+    return JSON.parse(sessionStorage.getItem("settings") || "{}");
+  }
+
+  async function request(method, path, { body, etag } = {}) {
+    const s = settings();
+    const headers = { Accept: "application/json" };
+    if (body !== undefined) headers["Content-Type"] = "application/json";
+    if (s.token) headers.Authorization = "Bearer " + s.token;
+    if (s.adminKey) headers["X-Admin-Key"] = s.adminKey;
+    if (s.tenant) headers[s.tenantHeader || "X-Tenant-ID"] = s.tenant;
+    if (etag) headers["If-Match"] = etag;
+
+    const response = await fetch(api + path, {
+      method,
+      headers,
+      body: body === undefined ? undefined : JSON.stringify(body),
+    });
+    const text = await response.text();
+    const data = text ? JSON.parse(text) : null;
+    if (!response.ok) {
+      const error = new Error((data && data.error) || response.statusText);
+      error.status = response.status;
+      error.fields = (data && data.fields) || [];
+      throw error;
+    }
+    return { data, etag: response.headers.get("ETag") };
+  }
+
+  function describe(error) {
+    const lines = [error.message];
+    for (const field of error.fields || []) {
+      lines.push(field.field ? `${field.field}: ${field.message}` : field.message);
+    }
+    if (error.status === 412) lines.push("Someone else changed the product; reopen it to see their changes.");
+    return lines.join("\n");
+  }
+
+  function showMessage(text, isError) {
+    const message = $("#message");
+    message.textContent = text;
+    message.classList.toggle("error", Boolean(isError));
+    message.hidden = !text;
+  }
+
+  // Prices travel in minor units; the currency decides how many decimals
+  function fractionDigits(currency) {
+    try {
+      return new Intl.NumberFormat("en", { style: "currency", currency }).resolvedOptions().maximumFractionDigits;
+    } catch {
+      return 2;
+    }
+  }
+
+  function formatPrice(price) {
+    const digits = fractionDigits(price.currency);
+    const major = price.amount / 10 ** digits;
+    try {
+      return new Intl.NumberFormat(undefined, { style: "currency", currency: price.currency }).format(major);
+    } catch {
+      return `${major.toFixed(digits)} ${price.currency}`;
+    }
+  }
+
+  function decimalPrice(price) {
+    const digits = fractionDigits(price.currency);
+    return (price.amount / 10 ** digits).toFixed(digits);
+  }
+
+  // parsePrice turns "12.5" into minor units without going through floats
+  function parsePrice(value, currency) {
+    const digits = fractionDigits(currency);
+    const match = /^(\d+)(?:\.(\d+))?$/.exec(value.trim());
+    if (!match || (match[2] || "").length > digits) {
+      throw new Error(`Price must be a number with at most ${digits} decimals`);
+    }
+    return Number(match[1] + (match[2] || "").padEnd(digits, "0"));
+  }
+
+  function cell(row, text, className) {
+    const td = row.insertCell();
+    td.textContent = text;
+    if (className) td.className = className;
+    return td;
+  }
+
+  function button(label, className, onClick) {
+    const b = document.createElement("button");
+    b.type = "button";
+    b.textContent = label;
+    b.className = className;
+    b.addEventListener("click", onClick);
+    return b;
+  }
+
+  function categoryName(id) {
+    const category = state.categories.find((c) => c.id === id);
+    return category ? category.name : id || "";
+  }
+
+  function render(products) {
+    const body = $("#products");
+    body.replaceChildren();
+    if (products.length === 0) {
+      cell(body.insertRow(), "No products found", "empty").colSpan = 7;
+      return;
+    }
+    for (const product of products) {
+      const row = body.insertRow();
+      cell(row, product.name);
+      cell(row, formatPrice(product.price));
+      const status = document.createElement("span");
+      status.className = "status";
+      status.textContent = product.status || "published";
+      row.insertCell().append(status);
+      cell(row, categoryName(product.category_id));
+      cell(row, String(product.stock));
+      cell(row, new Date(product.updated_at).toLocaleString());
+      const actions = cell(row, "", "actions");
+      actions.append(
+        button("Edit", "secondary", () => openEditor(product.id)),
+        " ",
+        button("Delete", "danger", () => remove(product)),
+      );
+    }
+  }
+
+  async function load() {
+    const filters = new FormData($("#filters"));
+    const q = filters.get("q").trim();
+    showMessage("");
+    try {
+      if (q) {
+        // Search ranks by relevance across every field, in one page
+        const { data } = await request("GET", "/products/search?" + new URLSearchParams({ q, limit: 100 }));
+        render(data.products);
+        $("#page-info").textContent = `${data.count} results`;
+        $("#prev").disabled = $("#next").disabled = true;
+        return;
+      }
+      const [sortBy, sortOrder] = filters.get("sort").split(":");
+      const query = new URLSearchParams({ page: state.page, limit: pageSize, sort_by: sortBy, sort_order: sortOrder });
+      for (const key of ["status", "category_id"]) {
+        if (filters.get(key)) query.set(key, filters.get(key));
+      }
+      const { data } = await request("GET", "/products?" + query);
+      render(data.products);
+      const p = data.pagination;
+      $("#page-info").textContent = `Page ${p.current_page} of ${Math.max(p.total_pages, 1)} · ${p.total_items} products`;
+      $("#prev").disabled = !p.has_prev;
+      $("#next").disabled = !p.has_next;
+    } catch (error) {
+      render([]);
+      showMessage(describe(error), true);
+    }
+  }
+
+  async function loadCategories() {
+    try {
+      const { data } = await request("GET", "/categories");
+      state.categories = data.categories || [];
+    } catch {
+      state.categories = [];
+    }
+    for (const select of document.querySelectorAll('select[name="category_id"]')) {
+      select.length = 1;
+      for (const category of state.categories) {
+        select.add(new Option(category.name, category.id));
+      }
+    }
+  }
+
+  function fillForm(product) {
+    const form = $("#product-form");
+    form.elements.name.value = product.name || "";
+    form.elements.description.value = product.description || "";
+    form.elements.price.value = product.price ? decimalPrice(product.price) : "";
+    form.elements.currency.value = product.price ? product.price.currency : "USD";
+    form.elements.category_id.value = product.category_id || "";
+    form.elements.tags.value = (product.tags || []).join(", ");
+    form.elements.sku.value = product.sku || "";
+    form.elements.barcode.value = product.barcode || "";
+    $("#product-error").hidden = true;
+  }
+
+  function openCreator() {
+    state.editing = null;
+    $("#product-title").textContent = "New product";
+    fillForm({});
+    $("#product-dialog").showModal();
+  }
+
+  async function openEditor(id) {
+    try {
+      const { data, etag } = await request("GET", "/products/" + encodeURIComponent(id));
+      state.editing = { product: data, etag };
+      $("#product-title").textContent = "Edit " + data.name;
+      fillForm(data);
+      $("#product-dialog").showModal();
+    } catch (error) {
+      showMessage(describe(error), true);
+    }
+  }
+
+  async function save(event) {
+    event.preventDefault();
+    const form = event.target;
+    const error = $("#product-error");
+    try {
+      const currency = form.elements.currency.value.trim().toUpperCase();
+      // An update replaces the product, so fields the form does not show
+      // are sent back as they were
+      const previous = state.editing ? state.editing.product : {};
+      const body = {
+        name: form.elements.name.value.trim(),
+        description: form.elements.description.value.trim(),
+        price: { amount: parsePrice(form.elements.price.value, currency), currency },
+        category_id: form.elements.category_id.value || undefined,
+        tags: form.elements.tags.value.split(",").map((t) => t.trim()).filter(Boolean),
+        sku: form.elements.sku.value.trim() || undefined,
+        barcode: form.elements.barcode.value.trim() || undefined,
+        expires_at: previous.expires_at,
+        publish_at: previous.publish_at,
+        auto_archive_at: previous.auto_archive_at,
+      };
+      if (state.editing) {
+        await request("PUT", "/products/" + encodeURIComponent(previous.id), { body, etag: state.editing.etag });
+        showMessage(`Saved ${body.name}`);
+      } else {
+        const { data } = await request("POST", "/products", { body });
+        showMessage(`Created ${data.name}`);
+      }
+      $("#product-dialog").close();
+      load();
+    } catch (e) {
+      error.textContent = describe(e);
+      error.hidden = false;
+    }
+  }
+
+  async function remove(product) {
+    if (!confirm(`Delete ${product.name}?`)) return;
+    try {
+      await request("DELETE", "/products/" + encodeURIComponent(product.id), { etag: `"${product.version}"` });
+      showMessage(`Deleted ${product.name}`);
+      load();
+    } catch (error) {
+      showMessage(describe(error), true);
+    }
+  }
+
+  function openSettings() {
+    const form = $("#settings-form");
+    const s = settings();
+    for (const name of ["token", "adminKey", "tenant", "tenantHeader"]) {
+      form.elements[name].value = s[name] || "";
+    }
+    $("#settings-dialog").showModal();
+  }
+
+  function saveSettings(event) {
+    event.preventDefault();
+    const form = event.target;
+    const s = {};
+    for (const name of ["token", "adminKey", "tenant", "tenantHeader"]) {
+      s[name] = form.elements[name].value.trim();
+    }
+    sessionStorage.setItem("settings", JSON.stringify(s));
+    $("#settings-dialog").close();
+    loadCategories().then(load);
+  }
+
+  $("#filters").addEventListener("submit", (event) => {
+    event.preventDefault();
+    state.page = 1;
+    load();
+  });
+  for (const select of document.querySelectorAll("#filters select")) {
+    select.addEventListener("change", () => {
+      state.page = 1;
+      load();
+    });
+  }
+  $("#prev").addEventListener("click", () => {
+    state.page--;
+    load();
+  });
+  $("#next").addEventListener("click", () => {
+    state.page++;
+    load();
+  });
+  $("#new-product").addEventListener("click", openCreator);
+  $("#open-settings").addEventListener("click", openSettings);
+  $("#product-form").addEventListener("submit", save);
+  $("#settings-form").addEventListener("submit", saveSettings);
+  for (const close of document.querySelectorAll("[data-close]")) {
+    close.addEventListener("click", () => close.closest("dialog").close());
+  }
+
+  loadCategories().then(load);
+})();
@@ -0,0 +1,56 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/i18n"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+const (
+	defaultAuditLimit = 50
+	maxAuditLimit     = 100
+)
+
+type AuditHandler struct {
+	service ports.AuditService
+	logger  *slog.Logger
+}
+
+func NewAuditHandler(service ports.AuditService, logger *slog.Logger) *AuditHandler {
+	return &AuditHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// History returns the product's audit entries, newest first
+func (h *AuditHandler) History(c *gin.Context) {
+	id := c.Param("id")
+	limit := defaultAuditLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxAuditLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "limit must be between 1 and 100")})
+			return
+		}
+		limit = parsed
+	}
+
+	entries, err := h.service.History(c.Request.Context(), id, limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, err.Error())})
+			return
+		}
+		h.logger.ErrorContext(c.Request.Context(), "failed to get audit history", "id", id, "error", err)
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"product_id": id, "entries": entries})
+}
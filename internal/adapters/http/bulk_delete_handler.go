@@ -0,0 +1,121 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/i18n"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/middleware"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+type BulkDeleteHandler struct {
+	service ports.BulkDeleteService
+	logger  *slog.Logger
+}
+
+func NewBulkDeleteHandler(service ports.BulkDeleteService, logger *slog.Logger) *BulkDeleteHandler {
+	return &BulkDeleteHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// BulkDeleteFilter takes the filters of the product listing
+type BulkDeleteFilter struct {
+	Name     string         `json:"name"`
+	MinPrice domain.Decimal `json:"min_price" binding:"nonnegative"`
+	MaxPrice domain.Decimal `json:"max_price" binding:"nonnegative"`
+	// PriceCurrency is the currency of the price bounds, the default one
+	// when left out
+	PriceCurrency string   `json:"price_currency" binding:"omitempty,currency"`
+	CategoryID    string   `json:"category_id"`
+	Tags          []string `json:"tags"`
+	TagsMatch     string   `json:"tags_match" binding:"omitempty,oneof=any all"`
+	Status        string   `json:"status" binding:"omitempty,oneof=draft published active archived discontinued"`
+}
+
+// BulkDeleteRequest selects products either by ID or by filter. DryRun has
+// no default, so a delete is never run by leaving it out.
+type BulkDeleteRequest struct {
+	IDs               []string          `json:"ids" binding:"omitempty,max=1000,dive,required"`
+	Filter            *BulkDeleteFilter `json:"filter"`
+	DryRun            *bool             `json:"dry_run" binding:"required"`
+	ConfirmationToken string            `json:"confirmation_token"`
+}
+
+// BulkDelete previews or deletes the products selected. A dry run answers
+// how many products match and a confirmation token; the delete itself
+// sends the same selection with dry_run false and that token.
+func (h *BulkDeleteHandler) BulkDelete(c *gin.Context) {
+	var req BulkDeleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WarnContext(c.Request.Context(), "invalid request body", "error", err)
+		respondBindingError(c, "invalid request body", err)
+		return
+	}
+	if (len(req.IDs) == 0) == (req.Filter == nil) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "either ids or filter is required, not both")})
+		return
+	}
+
+	selection := ports.BulkDeleteSelection{IDs: req.IDs}
+	if req.Filter != nil {
+		filters, ok := bulkDeleteFilters(c, *req.Filter)
+		if !ok {
+			return
+		}
+		selection.Filters = &filters
+	}
+
+	summary, err := h.service.BulkDelete(c.Request.Context(), selection, *req.DryRun, req.ConfirmationToken)
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to bulk delete products", "dry_run", *req.DryRun, "error", err)
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
+
+// bulkDeleteFilters checks a filter like the listing checks its query. An
+// empty filter is refused, since it would select every product.
+func bulkDeleteFilters(c *gin.Context, filter BulkDeleteFilter) (ports.ProductFilters, bool) {
+	tags := make([]string, 0, len(filter.Tags))
+	for _, tag := range filter.Tags {
+		tags = append(tags, strings.ToLower(strings.TrimSpace(tag)))
+	}
+	tags = slices.DeleteFunc(tags, func(tag string) bool { return tag == "" })
+	filters := ports.ProductFilters{
+		Name:       filter.Name,
+		MinPrice:   filter.MinPrice,
+		MaxPrice:   filter.MaxPrice,
+		Currency:   strings.ToUpper(filter.PriceCurrency),
+		CategoryID: filter.CategoryID,
+		Tags:       tags,
+		TagMatch:   filter.TagsMatch,
+		Status:     domain.NormalizeStatus(filter.Status),
+	}
+
+	if filters.Name == "" && filters.MinPrice.IsZero() && filters.MaxPrice.IsZero() && filters.Currency == "" && filters.CategoryID == "" && len(tags) == 0 && filters.Status == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "filter must have at least one criterion")})
+		return filters, false
+	}
+	if !filters.MinPrice.IsZero() && !filters.MaxPrice.IsZero() && filters.MinPrice.Cmp(filters.MaxPrice) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "min_price cannot be greater than max_price")})
+		return filters, false
+	}
+	if filters.Status != "" && filters.Status != domain.StatusPublished && !middleware.IsAdmin(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": i18n.T(c, fmt.Sprintf("only admins may list %s products", filters.Status))})
+		return filters, false
+	}
+	if len(tags) > domain.MaxProductTags {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, fmt.Sprintf("tags cannot list more than %d tags", domain.MaxProductTags))})
+		return filters, false
+	}
+	return filters, true
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// stubBulkDeleteService records its arguments and answers a fixed token
+type stubBulkDeleteService struct {
+	selection    ports.BulkDeleteSelection
+	dryRun       bool
+	confirmation string
+}
+
+func (s *stubBulkDeleteService) BulkDelete(ctx context.Context, selection ports.BulkDeleteSelection, dryRun bool, confirmation string) (domain.BulkDeleteSummary, error) {
+	s.selection, s.dryRun, s.confirmation = selection, dryRun, confirmation
+	if dryRun {
+		return domain.BulkDeleteSummary{DryRun: true, Matched: 2, IDs: []string{"1", "2"}, ConfirmationToken: "token"}, nil
+	}
+	if confirmation != "token" {
+		return domain.BulkDeleteSummary{}, domain.ErrBulkDeleteChanged
+	}
+	return domain.BulkDeleteSummary{Matched: 2, Deleted: 2, IDs: []string{"1", "2"}}, nil
+}
+
+func setupBulkDeleteRouter(service ports.BulkDeleteService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/v1/products/bulk-delete", NewBulkDeleteHandler(service, slog.Default()).BulkDelete)
+	return router
+}
+
+func bulkDelete(router *gin.Engine, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/products/bulk-delete", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestBulkDeleteHandler_PreviewThenDelete(t *testing.T) {
+	service := &stubBulkDeleteService{}
+	router := setupBulkDeleteRouter(service)
+
+	w := bulkDelete(router, `{"filter":{"category_id":"electronics","tags":[" Sale "]},"dry_run":true}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, service.dryRun)
+	require.NotNil(t, service.selection.Filters)
+	assert.Equal(t, "electronics", service.selection.Filters.CategoryID)
+	assert.Equal(t, []string{"sale"}, service.selection.Filters.Tags)
+	var preview domain.BulkDeleteSummary
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &preview))
+	assert.Equal(t, 2, preview.Matched)
+	assert.Equal(t, "token", preview.ConfirmationToken)
+
+	w = bulkDelete(router, `{"ids":["1","2"],"dry_run":false,"confirmation_token":"token"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, service.dryRun)
+	assert.Equal(t, []string{"1", "2"}, service.selection.IDs)
+	assert.Nil(t, service.selection.Filters)
+
+	w = bulkDelete(router, `{"ids":["1","2"],"dry_run":false,"confirmation_token":"stale"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestBulkDeleteHandler_RejectsBadSelections(t *testing.T) {
+	router := setupBulkDeleteRouter(&stubBulkDeleteService{})
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"dry_run left out", `{"ids":["1"]}`},
+		{"no selection", `{"dry_run":true}`},
+		{"ids and filter", `{"ids":["1"],"filter":{"name":"laptop"},"dry_run":true}`},
+		{"empty filter", `{"filter":{},"dry_run":true}`},
+		{"inverted price range", `{"filter":{"min_price":10,"max_price":5},"dry_run":true}`},
+		{"unknown status", `{"filter":{"status":"gone"},"dry_run":true}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, http.StatusBadRequest, bulkDelete(router, tt.body).Code)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/i18n"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+type CategoryHandler struct {
+	service ports.CategoryService
+	logger  *slog.Logger
+}
+
+func NewCategoryHandler(service ports.CategoryService, logger *slog.Logger) *CategoryHandler {
+	return &CategoryHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+type CategoryRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+func (r CategoryRequest) toInput() ports.CategoryInput {
+	return ports.CategoryInput{
+		Name:        r.Name,
+		Description: r.Description,
+	}
+}
+
+func (h *CategoryHandler) Create(c *gin.Context) {
+	var req CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WarnContext(c.Request.Context(), "invalid request body", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, err.Error())})
+		return
+	}
+
+	category, err := h.service.Create(c.Request.Context(), req.toInput())
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidCategory) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, err.Error())})
+			return
+		}
+		h.logger.ErrorContext(c.Request.Context(), "failed to create category", "error", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, category)
+}
+
+func (h *CategoryHandler) Get(c *gin.Context) {
+	id := c.Param("id")
+	category, err := h.service.Get(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrCategoryNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, err.Error())})
+			return
+		}
+		h.logger.ErrorContext(c.Request.Context(), "failed to get category", "id", id, "error", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
+func (h *CategoryHandler) List(c *gin.Context) {
+	categories, err := h.service.List(c.Request.Context())
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to list categories", "error", err)
+		respondError(c, err)
+		return
+	}
+	if categories == nil {
+		categories = []domain.Category{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"categories": categories})
+}
+
+func (h *CategoryHandler) Update(c *gin.Context) {
+	id := c.Param("id")
+	var req CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WarnContext(c.Request.Context(), "invalid request body", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, err.Error())})
+		return
+	}
+
+	category, err := h.service.Update(c.Request.Context(), id, req.toInput())
+	if err != nil {
+		if errors.Is(err, domain.ErrCategoryNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, err.Error())})
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidCategory) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, err.Error())})
+			return
+		}
+		h.logger.ErrorContext(c.Request.Context(), "failed to update category", "id", id, "error", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
+func (h *CategoryHandler) Delete(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		if errors.Is(err, domain.ErrCategoryNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, err.Error())})
+			return
+		}
+		if errors.Is(err, domain.ErrCategoryInUse) {
+			c.JSON(http.StatusConflict, gin.H{"error": i18n.T(c, err.Error())})
+			return
+		}
+		h.logger.ErrorContext(c.Request.Context(), "failed to delete category", "id", id, "error", err)
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
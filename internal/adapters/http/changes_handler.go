@@ -0,0 +1,98 @@
+package http
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/i18n"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+type ChangesHandler struct {
+	service ports.ChangeService
+	logger  *slog.Logger
+}
+
+func NewChangesHandler(service ports.ChangeService, logger *slog.Logger) *ChangesHandler {
+	return &ChangesHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// ChangesRequest starts the change feed at an RFC 3339 time or at the
+// next_token of a previous read
+type ChangesRequest struct {
+	Since string `form:"since" binding:"required"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=1000"`
+}
+
+// ChangesResponse adds the checkpoint to read from next to the changes
+type ChangesResponse struct {
+	domain.ProductChanges
+	NextToken string `json:"next_token"`
+}
+
+// Changes lists the IDs of the products created, updated, hidden and deleted
+// since a checkpoint. Consumers keep the next_token of each response and
+// send it back as since, reading again right away while has_more is set.
+func (h *ChangesHandler) Changes(c *gin.Context) {
+	var req ChangesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondBindingError(c, "invalid query parameters", err)
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = 100
+	}
+	from, ok := parseCheckpoint(req.Since)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  i18n.T(c, "invalid query parameters"),
+			"fields": []FieldError{{Field: "since", Rule: "format", Message: i18n.T(c, "since must be an RFC 3339 timestamp or a next_token")}},
+		})
+		return
+	}
+
+	changes, next, err := h.service.Changes(c.Request.Context(), from, req.Limit)
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to read product changes", "error", err)
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ChangesResponse{ProductChanges: changes, NextToken: encodeCheckpoint(next)})
+}
+
+// parseCheckpoint reads since as a timestamp first and as a token otherwise.
+// Tokens are not secret, only positions in the feed, so they are plain
+// base64 JSON and never expire.
+func parseCheckpoint(since string) (ports.ChangeCheckpoint, bool) {
+	if t, err := time.Parse(time.RFC3339Nano, since); err == nil {
+		return ports.CheckpointAt(t), true
+	}
+	data, err := base64.RawURLEncoding.DecodeString(since)
+	if err != nil {
+		return ports.ChangeCheckpoint{}, false
+	}
+	var checkpoint ports.ChangeCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return ports.ChangeCheckpoint{}, false
+	}
+	if checkpoint.Updated, err = ports.ParseSortKey("updated_at", checkpoint.Updated.Value, checkpoint.Updated.ID); err != nil {
+		return ports.ChangeCheckpoint{}, false
+	}
+	if checkpoint.Deleted, err = ports.ParseSortKey("deleted_at", checkpoint.Deleted.Value, checkpoint.Deleted.ID); err != nil {
+		return ports.ChangeCheckpoint{}, false
+	}
+	return checkpoint, true
+}
+
+func encodeCheckpoint(checkpoint ports.ChangeCheckpoint) string {
+	data, _ := json.Marshal(checkpoint)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
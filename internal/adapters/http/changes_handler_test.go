@@ -0,0 +1,68 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// stubChangeService records the checkpoint it is asked from and moves it
+// to a fixed next one
+type stubChangeService struct {
+	from  ports.ChangeCheckpoint
+	limit int
+}
+
+func (s *stubChangeService) Changes(ctx context.Context, from ports.ChangeCheckpoint, limit int) (domain.ProductChanges, ports.ChangeCheckpoint, error) {
+	s.from, s.limit = from, limit
+	next := ports.ChangeCheckpoint{
+		Updated: ports.SortKey{Value: "2024-03-01T12:05:00Z", ID: "b"},
+		Deleted: ports.SortKey{Value: "2024-03-01T12:01:00Z", ID: "c"},
+	}
+	return domain.ProductChanges{Created: []string{"a"}, Updated: []string{"b"}, Deleted: []string{"c"}}, next, nil
+}
+
+func changes(router *gin.Engine, query string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/products/changes?"+query, nil))
+	return w
+}
+
+func TestChangesHandler_Changes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service := &stubChangeService{}
+	router := gin.New()
+	router.GET("/api/v1/products/changes", NewChangesHandler(service, slog.Default()).Changes)
+
+	w := changes(router, "since="+url.QueryEscape("2024-03-01T13:00:00+01:00"))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, ports.CheckpointAt(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)), service.from)
+	assert.Equal(t, 100, service.limit)
+	var response ChangesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []string{"a"}, response.Created)
+	assert.Equal(t, []string{"c"}, response.Deleted)
+	require.NotEmpty(t, response.NextToken)
+
+	// The token resumes from the checkpoint it was issued for
+	w = changes(router, "since="+response.NextToken+"&limit=10")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, ports.SortKey{Value: "2024-03-01T12:05:00Z", ID: "b"}, service.from.Updated)
+	assert.Equal(t, ports.SortKey{Value: "2024-03-01T12:01:00Z", ID: "c"}, service.from.Deleted)
+	assert.Equal(t, 10, service.limit)
+
+	for _, query := range []string{"", "since=yesterday", "since=2024-03-01T12:00:00Z&limit=5000"} {
+		assert.Equal(t, http.StatusBadRequest, changes(router, query).Code, query)
+	}
+}
@@ -0,0 +1,39 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/dto"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+type ColdStorageHandler struct {
+	service ports.ColdStorageService
+	logger  *slog.Logger
+}
+
+func NewColdStorageHandler(service ports.ColdStorageService, logger *slog.Logger) *ColdStorageHandler {
+	return &ColdStorageHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Restore puts a product moved to cold storage back in the table. A product
+// whose ID, SKU or barcode was taken in the meantime answers 409.
+func (h *ColdStorageHandler) Restore(c *gin.Context) {
+	id := c.Param("id")
+	product, err := h.service.Restore(c.Request.Context(), id)
+	if err != nil {
+		if respondDuplicate(c, err) {
+			return
+		}
+		h.logger.ErrorContext(c.Request.Context(), "failed to restore product from cold storage", "id", id, "error", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewAdminProductResponse(product))
+}
@@ -0,0 +1,113 @@
+package dto
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ProductFields are the product fields ?fields may select, by their v1
+// names. The ID is always returned.
+var ProductFields = []string{
+	"id", "name", "description", "price", "status", "category_id", "tags", "sku", "barcode",
+	"stock", "images", "version", "created_at", "updated_at", "expires_at", "publish_at",
+	"auto_archive_at", "moderation_status", "moderation_reasons", "translations",
+}
+
+// v2FieldKeys maps the v1 fields that v2 groups to the v2 key holding them
+var v2FieldKeys = map[string]string{
+	"stock":              "inventory",
+	"expires_at":         "schedule",
+	"publish_at":         "schedule",
+	"auto_archive_at":    "schedule",
+	"moderation_status":  "moderation",
+	"moderation_reasons": "moderation",
+}
+
+// FieldList splits the fields parameter into known product fields,
+// lowercased and without duplicates. It returns nil when no fields were
+// requested.
+func (r *ListProductsRequest) FieldList() ([]string, error) {
+	if strings.TrimSpace(r.Fields) == "" {
+		return nil, nil
+	}
+	var fields []string
+	for _, field := range strings.Split(r.Fields, ",") {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field == "" || slices.Contains(fields, field) {
+			continue
+		}
+		if !slices.Contains(ProductFields, field) {
+			return nil, fmt.Errorf("fields has unknown field %q; use %s", field, strings.Join(ProductFields, ", "))
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// responseKeys are the JSON keys kept for the selected fields: the fields
+// themselves in v1, or the groups holding them in v2. The ID and a
+// requested display price are always kept.
+func responseKeys(fields []string, v2 bool) map[string]bool {
+	keys := map[string]bool{"id": true, "display_price": true}
+	for _, field := range fields {
+		if key, ok := v2FieldKeys[field]; ok && v2 {
+			field = key
+		}
+		keys[field] = true
+	}
+	return keys
+}
+
+// selectKeys re-encodes each of items as a JSON object holding only keys
+func selectKeys[T any](items []T, keys map[string]bool) ([]map[string]json.RawMessage, error) {
+	selected := make([]map[string]json.RawMessage, len(items))
+	for i, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &selected[i]); err != nil {
+			return nil, err
+		}
+		for key := range selected[i] {
+			if !keys[key] {
+				delete(selected[i], key)
+			}
+		}
+	}
+	return selected, nil
+}
+
+// MarshalJSON leaves out the product fields that were not selected
+func (r ListProductsResponse) MarshalJSON() ([]byte, error) {
+	type plain ListProductsResponse
+	if len(r.Fields) == 0 {
+		return json.Marshal(plain(r))
+	}
+	products, err := selectKeys(r.Products, responseKeys(r.Fields, false))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		plain
+		Products []map[string]json.RawMessage `json:"products"`
+	}{plain(r), products})
+}
+
+// MarshalJSON leaves out the product fields that were not selected
+func (r ListProductsResponseV2) MarshalJSON() ([]byte, error) {
+	type plain ListProductsResponseV2
+	if len(r.Fields) == 0 {
+		return json.Marshal(plain(r))
+	}
+	data, err := selectKeys(r.Data, responseKeys(r.Fields, true))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		plain
+		Data []map[string]json.RawMessage `json:"data"`
+	}{plain(r), data})
+}
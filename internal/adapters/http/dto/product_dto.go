@@ -1,26 +1,61 @@
 package dto
 
 import (
+	"fmt"
+	"slices"
+	"strings"
 	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/cursor"
 )
 
 // ListProductsRequest represents query parameters for listing products
 type ListProductsRequest struct {
 	// Pagination
-	Page  int `form:"page" binding:"min=1"`
-	Limit int `form:"limit" binding:"min=1,max=100"`
+	Page   int    `form:"page" binding:"omitempty,min=1"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor string `form:"cursor"`
+	// AfterID and AfterValue continue a keyset listing after the product
+	// with that ID and sort field value
+	AfterID    string `form:"after_id"`
+	AfterValue string `form:"after_value"`
 
 	// Filters
-	Name     string  `form:"name"`
-	MinPrice float64 `form:"min_price" binding:"min=0"`
-	MaxPrice float64 `form:"max_price" binding:"min=0"`
+	Name     string         `form:"name"`
+	MinPrice domain.Decimal `form:"min_price" binding:"nonnegative"`
+	MaxPrice domain.Decimal `form:"max_price" binding:"nonnegative"`
+	// PriceCurrency lists only the products priced in one currency, the
+	// default one when prices are bounded or sorted without it
+	PriceCurrency string `form:"price_currency" binding:"omitempty,currency"`
+	// CategoryID lists only the products of one category
+	CategoryID string `form:"category_id"`
+	// Tags is a comma-separated list; TagsMatch says whether products need
+	// any (the default) or all of them
+	Tags      string `form:"tags"`
+	TagsMatch string `form:"tags_match" binding:"omitempty,oneof=any all"`
+	// Status lists the products in another status than published; active
+	// is another name for published
+	Status string `form:"status" binding:"omitempty,oneof=draft published active archived discontinued"`
+	// CreatedAfter, CreatedBefore, UpdatedAfter and UpdatedBefore are RFC
+	// 3339 timestamps bounding the creation and update times, inclusively
+	CreatedAfter  string `form:"created_after"`
+	CreatedBefore string `form:"created_before"`
+	UpdatedAfter  string `form:"updated_after"`
+	UpdatedBefore string `form:"updated_before"`
 
 	// Sorting
 	SortBy    string `form:"sort_by" binding:"omitempty,oneof=name price created_at updated_at"`
 	SortOrder string `form:"sort_order" binding:"omitempty,oneof=asc desc"`
+	// ThenBy is a comma-separated list of SortFields ordering the products
+	// tied on SortBy
+	ThenBy string `form:"then_by"`
 
-	// Field selection
+	// Field selection: a comma-separated list of ProductFields
 	Fields string `form:"fields"`
+
+	// Debugging
+	Explain bool `form:"explain"`
 }
 
 // ListProductsResponse represents the response structure for listing products
@@ -28,33 +63,95 @@ type ListProductsResponse struct {
 	Products       []ProductResponse `json:"products"`
 	Pagination     PaginationInfo    `json:"pagination"`
 	FiltersApplied FilterInfo        `json:"filters_applied,omitempty"`
+	Explain        *ExplainInfo      `json:"explain,omitempty"`
+	// Fields limits the product fields rendered; empty renders them all
+	Fields []string `json:"-"`
+}
+
+// ExplainInfo describes the access path the repository used for a list request
+type ExplainInfo struct {
+	Operation    string `json:"operation"`
+	Index        string `json:"index,omitempty"`
+	Partitions   int    `json:"partitions,omitempty"`
+	SortInMemory bool   `json:"sort_in_memory"`
+	Reason       string `json:"reason"`
 }
 
 // ProductResponse represents a product in API responses
 type ProductResponse struct {
-	ID          string    `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Price       float64   `json:"price"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID                string                `json:"id"`
+	Name              string                `json:"name"`
+	Description       string                `json:"description"`
+	Price             domain.Money          `json:"price"`
+	CreatedAt         time.Time             `json:"created_at"`
+	UpdatedAt         time.Time             `json:"updated_at"`
+	ExpiresAt         *time.Time            `json:"expires_at,omitempty"`
+	Status            string                `json:"status,omitempty"`
+	PublishAt         *time.Time            `json:"publish_at,omitempty"`
+	AutoArchiveAt     *time.Time            `json:"auto_archive_at,omitempty"`
+	ModerationStatus  string                `json:"moderation_status,omitempty"`
+	ModerationReasons []string              `json:"moderation_reasons,omitempty"`
+	Version           int64                 `json:"version"`
+	CategoryID        string                `json:"category_id,omitempty"`
+	Stock             int64                 `json:"stock"`
+	Rating            domain.RatingSummary  `json:"rating"`
+	FavoriteCount     int64                 `json:"favorite_count,omitempty"`
+	Images            []domain.ProductImage `json:"images,omitempty"`
+	Tags              []string              `json:"tags,omitempty"`
+	SKU               string                `json:"sku,omitempty"`
+	Barcode           string                `json:"barcode,omitempty"`
+	// Translations are every translation of the name and description; Name
+	// and Description are already in the best match for Accept-Language
+	Translations map[string]domain.Translation `json:"translations,omitempty"`
+	// DisplayPrice is the price converted to the currency the client asked
+	// for, when it differs from the product's
+	DisplayPrice *domain.Money `json:"display_price,omitempty"`
 }
 
 // PaginationInfo contains pagination metadata
 type PaginationInfo struct {
-	CurrentPage int  `json:"current_page"`
-	PerPage     int  `json:"per_page"`
-	TotalPages  int  `json:"total_pages"`
-	TotalItems  int  `json:"total_items"`
-	HasNext     bool `json:"has_next"`
-	HasPrev     bool `json:"has_prev"`
+	CurrentPage int    `json:"current_page"`
+	PerPage     int    `json:"per_page"`
+	TotalPages  int    `json:"total_pages"`
+	TotalItems  int    `json:"total_items"`
+	HasNext     bool   `json:"has_next"`
+	HasPrev     bool   `json:"has_prev"`
+	NextCursor  string `json:"next_cursor,omitempty"`
+	// NextAfterID and NextAfterValue are the keyset position of the last
+	// product, set when the page is full
+	NextAfterID    string `json:"next_after_id,omitempty"`
+	NextAfterValue string `json:"next_after_value,omitempty"`
+}
+
+// BatchGetRequest names the products to read with POST /products/batch-get
+type BatchGetRequest struct {
+	IDs []string `json:"ids" binding:"required,min=1,max=100,dive,required"`
+}
+
+// BatchGetResponse holds the products found, in the order they were asked
+// for, and the IDs that were not
+type BatchGetResponse struct {
+	Products []ProductResponse `json:"products"`
+	Missing  []string          `json:"missing"`
+}
+
+// CountResponse is how many products match the listing filters
+type CountResponse struct {
+	Count int `json:"count"`
 }
 
 // FilterInfo contains information about applied filters
 type FilterInfo struct {
-	Name     string  `json:"name,omitempty"`
-	MinPrice float64 `json:"min_price,omitempty"`
-	MaxPrice float64 `json:"max_price,omitempty"`
+	Name     string          `json:"name,omitempty"`
+	MinPrice *domain.Decimal `json:"min_price,omitempty"`
+	MaxPrice *domain.Decimal `json:"max_price,omitempty"`
+	// PriceCurrency is the currency listed prices are in, when restricted
+	PriceCurrency string `json:"price_currency,omitempty"`
+	// CategoryID is the category the listing was restricted to
+	CategoryID string   `json:"category_id,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	TagsMatch  string   `json:"tags_match,omitempty"`
+	Status     string   `json:"status,omitempty"`
 }
 
 // SetDefaults sets default values for the request
@@ -71,6 +168,7 @@ func (r *ListProductsRequest) SetDefaults() {
 	if r.SortOrder == "" {
 		r.SortOrder = "desc"
 	}
+	r.Status = domain.NormalizeStatus(r.Status)
 }
 
 // GetOffset calculates the offset for database queries
@@ -78,19 +176,160 @@ func (r *ListProductsRequest) GetOffset() int {
 	return (r.Page - 1) * r.Limit
 }
 
+// FilterHash fingerprints the filters and sort order a cursor is bound to
+func (r *ListProductsRequest) FilterHash() string {
+	return cursor.HashFilters(
+		r.Name,
+		r.MinPrice.String(),
+		r.MaxPrice.String(),
+		r.CategoryID,
+		strings.Join(r.TagList(), ","),
+		r.TagsMatch,
+		r.Status,
+		r.CreatedAfter,
+		r.CreatedBefore,
+		r.UpdatedAfter,
+		r.UpdatedBefore,
+		r.SortBy,
+		r.ThenBy,
+		r.SortOrder,
+	)
+}
+
 // HasFilters returns true if any filter is applied
 func (r *ListProductsRequest) HasFilters() bool {
-	return r.Name != "" || r.MinPrice > 0 || r.MaxPrice > 0
+	return r.Name != "" || !r.MinPrice.IsZero() || !r.MaxPrice.IsZero() || r.PriceCurrency != "" || r.CategoryID != "" || len(r.TagList()) > 0 || r.Status != "" ||
+		r.CreatedAfter != "" || r.CreatedBefore != "" || r.UpdatedAfter != "" || r.UpdatedBefore != ""
+}
+
+// SortFields are the fields sort_by and then_by may order listings by
+var SortFields = []string{"name", "price", "created_at", "updated_at"}
+
+// ThenByList splits the then_by parameter into sort fields, lowercased. A
+// field may not repeat sort_by or an earlier one.
+func (r *ListProductsRequest) ThenByList() ([]string, error) {
+	if strings.TrimSpace(r.ThenBy) == "" {
+		return nil, nil
+	}
+	sortBy := []string{r.SortBy}
+	for _, field := range strings.Split(r.ThenBy, ",") {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field == "" {
+			continue
+		}
+		if !slices.Contains(SortFields, field) {
+			return nil, fmt.Errorf("then_by has unknown field %q; use %s", field, strings.Join(SortFields, ", "))
+		}
+		if slices.Contains(sortBy, field) {
+			return nil, fmt.Errorf("then_by repeats the sort field %q", field)
+		}
+		sortBy = append(sortBy, field)
+	}
+	return sortBy[1:], nil
+}
+
+// TimeRange holds the parsed time range parameters; the ones left out are
+// zero
+type TimeRange struct {
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	UpdatedAfter  time.Time
+	UpdatedBefore time.Time
+}
+
+// TimeParamError is a time range parameter that does not parse
+type TimeParamError struct {
+	Param string
+}
+
+func (e *TimeParamError) Error() string {
+	return fmt.Sprintf("%s must be an RFC 3339 timestamp", e.Param)
+}
+
+// TimeRange parses the time range parameters. It answers a TimeParamError
+// for the first one that is not an RFC 3339 timestamp.
+func (r *ListProductsRequest) TimeRange() (TimeRange, error) {
+	var times TimeRange
+	params := []struct {
+		name  string
+		value string
+		time  *time.Time
+	}{
+		{"created_after", r.CreatedAfter, &times.CreatedAfter},
+		{"created_before", r.CreatedBefore, &times.CreatedBefore},
+		{"updated_after", r.UpdatedAfter, &times.UpdatedAfter},
+		{"updated_before", r.UpdatedBefore, &times.UpdatedBefore},
+	}
+	for _, param := range params {
+		if param.value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339Nano, param.value)
+		if err != nil {
+			return TimeRange{}, &TimeParamError{Param: param.name}
+		}
+		*param.time = t
+	}
+	return times, nil
+}
+
+// TagList splits the tags filter, normalized the way product tags are
+// stored
+func (r *ListProductsRequest) TagList() []string {
+	if r.Tags == "" {
+		return nil
+	}
+	tags := strings.Split(r.Tags, ",")
+	for i, tag := range tags {
+		tags[i] = strings.ToLower(strings.TrimSpace(tag))
+	}
+	return slices.DeleteFunc(tags, func(tag string) bool { return tag == "" })
+}
+
+// AdminProductResponse adds the confidential cost and margin fields that
+// only admins may see
+type AdminProductResponse struct {
+	ProductResponse
+	CostPrice *domain.Money `json:"cost_price,omitempty"`
+	Margin    *float64      `json:"margin,omitempty"`
+}
+
+// NewAdminProductResponse creates an admin product response from domain product
+func NewAdminProductResponse(product domain.Product) AdminProductResponse {
+	response := AdminProductResponse{
+		ProductResponse: NewProductResponse(product),
+		CostPrice:       product.CostPrice,
+	}
+	if margin, ok := product.Margin(); ok {
+		response.Margin = &margin
+	}
+	return response
 }
 
 // NewProductResponse creates a new product response from domain product
-func NewProductResponse(id, name, description string, price float64, createdAt, updatedAt time.Time) ProductResponse {
+func NewProductResponse(product domain.Product) ProductResponse {
 	return ProductResponse{
-		ID:          id,
-		Name:        name,
-		Description: description,
-		Price:       price,
-		CreatedAt:   createdAt,
-		UpdatedAt:   updatedAt,
+		ID:                product.ID,
+		Name:              product.Name,
+		Description:       product.Description,
+		Price:             product.Price,
+		CreatedAt:         product.CreatedAt,
+		UpdatedAt:         product.UpdatedAt,
+		ExpiresAt:         product.ExpiresAt,
+		Status:            product.Status,
+		PublishAt:         product.PublishAt,
+		AutoArchiveAt:     product.AutoArchiveAt,
+		ModerationStatus:  product.ModerationStatus,
+		ModerationReasons: product.ModerationReasons,
+		Version:           product.Version,
+		CategoryID:        product.CategoryID,
+		Stock:             product.Stock,
+		Rating:            product.Rating(),
+		FavoriteCount:     product.FavoriteCount,
+		Images:            product.Images,
+		Tags:              product.Tags,
+		SKU:               product.SKU,
+		Barcode:           product.Barcode,
+		Translations:      product.Translations,
 	}
 }
@@ -0,0 +1,141 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// ProductV2 is the v2 representation of a product. Compared with v1 the
+// stock, moderation, schedule and admin-only cost fields are grouped into
+// objects, and tags are always an array.
+type ProductV2 struct {
+	ID            string                        `json:"id"`
+	Name          string                        `json:"name"`
+	Description   string                        `json:"description"`
+	Price         domain.Money                  `json:"price"`
+	DisplayPrice  *domain.Money                 `json:"display_price,omitempty"`
+	Status        string                        `json:"status,omitempty"`
+	CategoryID    string                        `json:"category_id,omitempty"`
+	Tags          []string                      `json:"tags"`
+	SKU           string                        `json:"sku,omitempty"`
+	Barcode       string                        `json:"barcode,omitempty"`
+	Images        []domain.ProductImage         `json:"images,omitempty"`
+	Translations  map[string]domain.Translation `json:"translations,omitempty"`
+	Inventory     InventoryV2                   `json:"inventory"`
+	Rating        domain.RatingSummary          `json:"rating"`
+	FavoriteCount int64                         `json:"favorite_count,omitempty"`
+	Schedule      *ScheduleV2                   `json:"schedule,omitempty"`
+	Moderation    *ModerationV2                 `json:"moderation,omitempty"`
+	Cost          *CostV2                       `json:"cost,omitempty"`
+	Version       int64                         `json:"version"`
+	CreatedAt     time.Time                     `json:"created_at"`
+	UpdatedAt     time.Time                     `json:"updated_at"`
+}
+
+type InventoryV2 struct {
+	Stock int64 `json:"stock"`
+}
+
+// ScheduleV2 holds the lifecycle dates of a product; it is omitted when
+// none is set
+type ScheduleV2 struct {
+	PublishAt     *time.Time `json:"publish_at,omitempty"`
+	AutoArchiveAt *time.Time `json:"auto_archive_at,omitempty"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+}
+
+type ModerationV2 struct {
+	Status  string   `json:"status"`
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// CostV2 holds the confidential cost and margin only admins may see
+type CostV2 struct {
+	Price  domain.Money `json:"price"`
+	Margin *float64     `json:"margin,omitempty"`
+}
+
+// ProductEnvelopeV2 wraps a single product in v2 responses
+type ProductEnvelopeV2 struct {
+	Data ProductV2 `json:"data"`
+}
+
+// ListProductsResponseV2 is the v2 listing: the products under data and
+// everything about the listing itself under meta
+type ListProductsResponseV2 struct {
+	Data []ProductV2 `json:"data"`
+	Meta ListMetaV2  `json:"meta"`
+	// Fields limits the product fields rendered; empty renders them all
+	Fields []string `json:"-"`
+}
+
+type ListMetaV2 struct {
+	Pagination PaginationInfo `json:"pagination"`
+	Filters    *FilterInfo    `json:"filters,omitempty"`
+	Explain    *ExplainInfo   `json:"explain,omitempty"`
+}
+
+// NewProductV2 creates a v2 product from a domain product; withCost adds
+// the admin-only cost fields
+func NewProductV2(product domain.Product, withCost bool) ProductV2 {
+	response := ProductV2{
+		ID:            product.ID,
+		Name:          product.Name,
+		Description:   product.Description,
+		Price:         product.Price,
+		Status:        product.Status,
+		CategoryID:    product.CategoryID,
+		Tags:          product.Tags,
+		SKU:           product.SKU,
+		Barcode:       product.Barcode,
+		Images:        product.Images,
+		Translations:  product.Translations,
+		Inventory:     InventoryV2{Stock: product.Stock},
+		Rating:        product.Rating(),
+		FavoriteCount: product.FavoriteCount,
+		Version:       product.Version,
+		CreatedAt:     product.CreatedAt,
+		UpdatedAt:     product.UpdatedAt,
+	}
+	if response.Tags == nil {
+		response.Tags = []string{}
+	}
+	if product.PublishAt != nil || product.AutoArchiveAt != nil || product.ExpiresAt != nil {
+		response.Schedule = &ScheduleV2{
+			PublishAt:     product.PublishAt,
+			AutoArchiveAt: product.AutoArchiveAt,
+			ExpiresAt:     product.ExpiresAt,
+		}
+	}
+	if product.ModerationStatus != "" {
+		response.Moderation = &ModerationV2{Status: product.ModerationStatus, Reasons: product.ModerationReasons}
+	}
+	if withCost && product.CostPrice != nil {
+		response.Cost = &CostV2{Price: *product.CostPrice}
+		if margin, ok := product.Margin(); ok {
+			response.Cost.Margin = &margin
+		}
+	}
+	return response
+}
+
+// NewListProductsResponseV2 renders a v1 listing of products in the v2
+// shape, keeping the display prices it computed; filtered says whether
+// the listing's filters are reported
+func NewListProductsResponseV2(products []domain.Product, listing ListProductsResponse, filtered bool) ListProductsResponseV2 {
+	response := ListProductsResponseV2{
+		Data:   make([]ProductV2, len(products)),
+		Meta:   ListMetaV2{Pagination: listing.Pagination, Explain: listing.Explain},
+		Fields: listing.Fields,
+	}
+	for i, product := range products {
+		response.Data[i] = NewProductV2(product, false)
+		response.Data[i].DisplayPrice = listing.Products[i].DisplayPrice
+	}
+	if filtered {
+		filters := listing.FiltersApplied
+		response.Meta.Filters = &filters
+	}
+	return response
+}
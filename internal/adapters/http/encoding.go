@@ -0,0 +1,139 @@
+package http
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"io"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+const mimeCSV = "text/csv"
+
+// productEncoder writes products in a format other than JSON, for clients
+// that pull them into spreadsheets or systems without JSON support
+type productEncoder struct {
+	contentType string
+	// encode writes products; list is false for a single product, which
+	// is not wrapped in a collection
+	encode func(w io.Writer, products []domain.Product, list bool) error
+}
+
+// productEncoders are the formats GET product endpoints offer besides JSON,
+// by the media type clients name in Accept
+var productEncoders = map[string]productEncoder{
+	mimeCSV:          {contentType: "text/csv; charset=utf-8", encode: encodeProductsCSV},
+	binding.MIMEXML:  {contentType: "application/xml; charset=utf-8", encode: encodeProductsXML},
+	binding.MIMEXML2: {contentType: "application/xml; charset=utf-8", encode: encodeProductsXML},
+}
+
+// offeredFormats lists JSON first, so it answers requests without Accept,
+// with */* or with a vendor media type
+var offeredFormats = []string{binding.MIMEJSON, mimeCSV, binding.MIMEXML, binding.MIMEXML2}
+
+// respondEncoded answers with products in the format Accept asks for when
+// one of productEncoders is preferred over JSON, reporting whether it did
+func respondEncoded(c *gin.Context, status int, products []domain.Product, list bool) bool {
+	encoder, ok := productEncoders[c.NegotiateFormat(offeredFormats...)]
+	if !ok {
+		return false
+	}
+	var body bytes.Buffer
+	if err := encoder.encode(&body, products, list); err != nil {
+		respondError(c, err)
+		return true
+	}
+	c.Data(status, encoder.contentType, body.Bytes())
+	return true
+}
+
+// encodeProductsCSV writes the columns of an export, a header row first
+func encodeProductsCSV(w io.Writer, products []domain.Product, _ bool) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(exportColumns); err != nil {
+		return err
+	}
+	for _, product := range products {
+		if err := writer.Write(exportRow(product)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// xmlProduct has the fields of an export row, the cost price left out
+type xmlProduct struct {
+	XMLName     xml.Name   `xml:"product"`
+	ID          string     `xml:"id"`
+	Name        string     `xml:"name"`
+	Description string     `xml:"description,omitempty"`
+	Price       string     `xml:"price"`
+	Currency    string     `xml:"currency"`
+	Status      string     `xml:"status,omitempty"`
+	CategoryID  string     `xml:"category_id,omitempty"`
+	Stock       int64      `xml:"stock"`
+	Version     int64      `xml:"version"`
+	CreatedAt   time.Time  `xml:"created_at"`
+	UpdatedAt   time.Time  `xml:"updated_at"`
+	ExpiresAt   *time.Time `xml:"expires_at,omitempty"`
+	PublishAt   *time.Time `xml:"publish_at,omitempty"`
+	SKU         string     `xml:"sku,omitempty"`
+	Barcode     string     `xml:"barcode,omitempty"`
+}
+
+type xmlProducts struct {
+	XMLName  xml.Name     `xml:"products"`
+	Products []xmlProduct `xml:"product"`
+}
+
+func newXMLProduct(product domain.Product) xmlProduct {
+	return xmlProduct{
+		ID:          product.ID,
+		Name:        product.Name,
+		Description: product.Description,
+		Price:       product.Price.DecimalString(),
+		Currency:    product.Price.Currency,
+		Status:      product.Status,
+		CategoryID:  product.CategoryID,
+		Stock:       product.Stock,
+		Version:     product.Version,
+		CreatedAt:   product.CreatedAt.UTC(),
+		UpdatedAt:   product.UpdatedAt.UTC(),
+		ExpiresAt:   utcTime(product.ExpiresAt),
+		PublishAt:   utcTime(product.PublishAt),
+		SKU:         product.SKU,
+		Barcode:     product.Barcode,
+	}
+}
+
+// encodeProductsXML writes a <product> element, or a <products> element
+// holding one per product for listings
+func encodeProductsXML(w io.Writer, products []domain.Product, list bool) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	var document any
+	if list {
+		collection := xmlProducts{Products: make([]xmlProduct, len(products))}
+		for i, product := range products {
+			collection.Products[i] = newXMLProduct(product)
+		}
+		document = collection
+	} else {
+		document = newXMLProduct(products[0])
+	}
+	return xml.NewEncoder(w).Encode(document)
+}
+
+func utcTime(value *time.Time) *time.Time {
+	if value == nil {
+		return nil
+	}
+	utc := value.UTC()
+	return &utc
+}
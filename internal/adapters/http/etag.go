@@ -0,0 +1,75 @@
+package http
+
+import (
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/middleware"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// productETag is the entity tag of the representation of a product sent in
+// answer to c: the product's version, which every write bumps, and a hash
+// of what else shapes the body, namely the API version, the admin view,
+// the negotiated format, the display currency and the locale served. Each
+// representation gets its own strong tag, without hashing the body, and
+// every tag of a version still names it in If-Match.
+func productETag(c *gin.Context, product domain.Product, locale string) string {
+	variant := fnv.New32a()
+	fmt.Fprintf(variant, "%d|%t|%s|%s|%s", middleware.APIVersion(c), middleware.IsAdmin(c),
+		c.NegotiateFormat(offeredFormats...), strings.ToUpper(c.Query("currency")), locale)
+	return fmt.Sprintf(`"%d-%08x"`, product.Version, variant.Sum32())
+}
+
+// etagVersion extracts the version from a product entity tag, accepting
+// the weak form some proxies rewrite strong tags to and the bare version
+// tags of earlier releases
+func etagVersion(tag string) (int64, bool) {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, false
+	}
+	value, _, _ := strings.Cut(tag[1:len(tag)-1], "-")
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || version < 0 {
+		return 0, false
+	}
+	return version, true
+}
+
+// ifMatchVersions lists the product versions a comma-separated If-Match
+// header names. wildcard reports "*"; ok is false when an entry is not an
+// entity tag at all. Tags that are not product tags name no version.
+func ifMatchVersions(header string) (versions []int64, wildcard, ok bool) {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		switch {
+		case candidate == "*":
+			wildcard = true
+		case !strings.HasSuffix(candidate, `"`) || !strings.HasPrefix(strings.TrimPrefix(candidate, "W/"), `"`):
+			return nil, false, false
+		default:
+			if version, isProduct := etagVersion(candidate); isProduct {
+				versions = append(versions, version)
+			}
+		}
+	}
+	versions = slices.Compact(slices.Sorted(slices.Values(versions)))
+	return versions, wildcard, true
+}
+
+// etagListMatches reports whether a comma-separated If-None-Match or
+// If-Match header lists etag or is the "*" wildcard
+func etagListMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package http
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/i18n"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+// exportFlushRows is how many rows are buffered before they are sent to
+// the client as a chunk
+const exportFlushRows = 100
+
+// exportColumns are the CSV header; the confidential cost price is left out
+var exportColumns = []string{
+	"id", "name", "description", "price", "currency", "status", "category_id", "stock",
+	"version", "created_at", "updated_at", "expires_at", "publish_at", "sku", "barcode",
+}
+
+type ExportHandler struct {
+	service ports.ExportService
+	logger  *slog.Logger
+}
+
+func NewExportHandler(service ports.ExportService, logger *slog.Logger) *ExportHandler {
+	return &ExportHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// ExportRequest takes the same filters as the product listing
+type ExportRequest struct {
+	Format   string         `form:"format" binding:"omitempty,oneof=csv"`
+	Name     string         `form:"name"`
+	MinPrice domain.Decimal `form:"min_price" binding:"nonnegative"`
+	MaxPrice domain.Decimal `form:"max_price" binding:"nonnegative"`
+	// PriceCurrency is the currency of the price bounds, the default one
+	// when left out
+	PriceCurrency string `form:"price_currency" binding:"omitempty,currency"`
+	CategoryID    string `form:"category_id"`
+}
+
+// Export streams every product matching the filters as CSV. The response is
+// sent in chunks as the table is read, so its size is not known up front;
+// a failure halfway through can only cut the response short.
+func (h *ExportHandler) Export(c *gin.Context) {
+	var req ExportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondBindingError(c, "invalid query parameters", err)
+		return
+	}
+	if !req.MinPrice.IsZero() && !req.MaxPrice.IsZero() && req.MinPrice.Cmp(req.MaxPrice) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "min_price cannot be greater than max_price")})
+		return
+	}
+	filters := ports.ProductFilters{
+		Name:       req.Name,
+		MinPrice:   req.MinPrice,
+		MaxPrice:   req.MaxPrice,
+		Currency:   strings.ToUpper(req.PriceCurrency),
+		CategoryID: req.CategoryID,
+	}
+
+	writer := csv.NewWriter(c.Writer)
+	rows := 0
+	started := false
+	// The response starts with the first product, so a failing read before
+	// it can still be answered with an error status
+	start := func() error {
+		started = true
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", `attachment; filename="products.csv"`)
+		c.Status(http.StatusOK)
+		return writer.Write(exportColumns)
+	}
+	err := h.service.Export(c.Request.Context(), filters, func(product domain.Product) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if err := writer.Write(exportRow(product)); err != nil {
+			return err
+		}
+		rows++
+		if rows%exportFlushRows == 0 {
+			writer.Flush()
+			c.Writer.Flush()
+			return writer.Error()
+		}
+		return nil
+	})
+	if err == nil && !started {
+		err = start()
+	}
+	if err == nil {
+		writer.Flush()
+		err = writer.Error()
+	}
+	if err != nil {
+		if !started {
+			respondError(c, err)
+			return
+		}
+		// The status is already sent, so the export just ends early
+		h.logger.ErrorContext(c.Request.Context(), "product export interrupted", "rows", rows, "error", err)
+	}
+}
+
+func exportRow(product domain.Product) []string {
+	return []string{
+		product.ID,
+		csvText(product.Name),
+		csvText(product.Description),
+		product.Price.DecimalString(),
+		product.Price.Currency,
+		product.Status,
+		product.CategoryID,
+		strconv.FormatInt(product.Stock, 10),
+		strconv.FormatInt(product.Version, 10),
+		product.CreatedAt.UTC().Format(time.RFC3339),
+		product.UpdatedAt.UTC().Format(time.RFC3339),
+		csvTime(product.ExpiresAt),
+		csvTime(product.PublishAt),
+		csvText(product.SKU),
+		product.Barcode,
+	}
+}
+
+// csvText keeps spreadsheets from evaluating user text as a formula
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func csvTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.UTC().Format(time.RFC3339)
+}
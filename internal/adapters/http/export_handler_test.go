@@ -0,0 +1,82 @@
+package http
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// stubExportService hands out products and records the filters it got
+type stubExportService struct {
+	products []domain.Product
+	err      error
+	filters  ports.ProductFilters
+}
+
+func (s *stubExportService) Export(ctx context.Context, filters ports.ProductFilters, fn func(domain.Product) error) error {
+	s.filters = filters
+	for _, product := range s.products {
+		if err := fn(product); err != nil {
+			return err
+		}
+	}
+	return s.err
+}
+
+func setupExportRouter(service ports.ExportService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/products/export", NewExportHandler(service, slog.Default()).Export)
+	return router
+}
+
+func TestExportHandler_StreamsCSV(t *testing.T) {
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	service := &stubExportService{products: []domain.Product{
+		{ID: "1", Name: "Laptop", Description: "Gaming, 16\"", Price: domain.Money{Amount: 129950, Currency: "USD"}, Status: domain.StatusPublished, Stock: 3, Version: 2, CreatedAt: created, UpdatedAt: created, SKU: "LAP-1", Barcode: "4006381333931"},
+		{ID: "2", Name: "=HYPERLINK(\"x\")", Price: domain.Money{Amount: 1000, Currency: "USD"}, CreatedAt: created, UpdatedAt: created},
+	}}
+	router := setupExportRouter(service)
+
+	req, _ := http.NewRequest("GET", "/api/v1/products/export?format=csv&min_price=5&category_id=electronics", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, domain.NewDecimal(5, 0), service.filters.MinPrice)
+	assert.Equal(t, "electronics", service.filters.CategoryID)
+
+	records, err := csv.NewReader(w.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, exportColumns, records[0])
+	assert.Equal(t, []string{"1", "Laptop", "Gaming, 16\"", "1299.50", "USD", "published", "", "3", "2", "2024-03-01T12:00:00Z", "2024-03-01T12:00:00Z", "", "", "LAP-1", "4006381333931"}, records[1])
+	assert.Equal(t, "'=HYPERLINK(\"x\")", records[2][1])
+}
+
+func TestExportHandler_Errors(t *testing.T) {
+	router := setupExportRouter(&stubExportService{})
+	req, _ := http.NewRequest("GET", "/api/v1/products/export?format=xml", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Nothing has been sent yet, so the failure gets a status of its own
+	router = setupExportRouter(&stubExportService{err: errors.New("scan failed")})
+	req, _ = http.NewRequest("GET", "/api/v1/products/export", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
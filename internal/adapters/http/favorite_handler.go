@@ -0,0 +1,96 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/dto"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/i18n"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+const (
+	defaultFavoriteLimit = 20
+	maxFavoriteLimit     = 100
+)
+
+type FavoriteHandler struct {
+	service ports.FavoriteService
+	logger  *slog.Logger
+}
+
+func NewFavoriteHandler(service ports.FavoriteService, logger *slog.Logger) *FavoriteHandler {
+	return &FavoriteHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// favoriteResponse is one product of the caller's wish list
+type favoriteResponse struct {
+	Product     dto.ProductResponse `json:"product"`
+	FavoritedAt time.Time           `json:"favorited_at"`
+}
+
+// Add puts the product on the caller's wish list
+func (h *FavoriteHandler) Add(c *gin.Context) {
+	favorite, err := h.service.Add(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, favorite)
+}
+
+// Remove takes the product off the caller's wish list
+func (h *FavoriteHandler) Remove(c *gin.Context) {
+	if err := h.service.Remove(c.Request.Context(), c.Param("id")); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// List returns a page of the caller's favorite products
+func (h *FavoriteHandler) List(c *gin.Context) {
+	limit := defaultFavoriteLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxFavoriteLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "limit must be between 1 and 100")})
+			return
+		}
+		limit = parsed
+	}
+
+	page, err := h.service.List(c.Request.Context(), c.Query("after"), limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	favorites := make([]favoriteResponse, 0, len(page.Favorites))
+	for _, favorite := range page.Favorites {
+		favorites = append(favorites, favoriteResponse{
+			Product:     dto.NewProductResponse(favorite.Product),
+			FavoritedAt: favorite.FavoritedAt,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"favorites": favorites, "next_after": page.NextAfter})
+}
+
+func (h *FavoriteHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNoUser):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": i18n.T(c, err.Error())})
+	case errors.Is(err, domain.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, err.Error())})
+	default:
+		h.logger.ErrorContext(c.Request.Context(), "favorite request failed", "id", c.Param("id"), "error", err)
+		respondError(c, err)
+	}
+}
@@ -0,0 +1,59 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/buildinfo"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/health"
+)
+
+type HealthHandler struct {
+	checker *health.Checker
+	logger  *slog.Logger
+}
+
+func NewHealthHandler(checker *health.Checker, logger *slog.Logger) *HealthHandler {
+	return &HealthHandler{
+		checker: checker,
+		logger:  logger,
+	}
+}
+
+// Live reports that the process is serving requests, without touching any
+// dependency, so an outage elsewhere never gets the pod restarted
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":    health.StatusUp,
+		"timestamp": time.Now().UTC(),
+		"build":     buildinfo.Get(),
+	})
+}
+
+// Ready checks every configured dependency and answers 503 when a critical
+// one is down, so the pod is taken out of the load balancer until it
+// recovers. A degraded service still serves, so it stays ready.
+func (h *HealthHandler) Ready(c *gin.Context) {
+	report := h.checker.Run(c.Request.Context())
+	if report.Status != health.StatusUp {
+		h.logger.WarnContext(c.Request.Context(), "readiness check not up", "status", report.Status, "checks", report.Checks)
+	}
+	if report.Status == health.StatusDown {
+		c.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// Details checks the dependencies like Ready, and adds the success rate,
+// latencies and results of their recent checks
+func (h *HealthHandler) Details(c *gin.Context) {
+	details := h.checker.Details(c.Request.Context())
+	if details.Status == health.StatusDown {
+		c.JSON(http.StatusServiceUnavailable, details)
+		return
+	}
+	c.JSON(http.StatusOK, details)
+}
@@ -0,0 +1,162 @@
+package i18n
+
+// spanish translates the messages clients can receive. Field names,
+// values and codes stay as sent, so a client can still match them.
+var spanish = map[string]string{
+	// Domain errors
+	"product not found":                                              "producto no encontrado",
+	"product is not in cold storage":                                 "el producto no está en el almacenamiento en frío",
+	"product was modified concurrently":                              "el producto fue modificado simultáneamente",
+	"invalid product data":                                           "datos de producto inválidos",
+	"invalid pagination cursor":                                      "cursor de paginación inválido",
+	"value is already used by another product":                       "el valor ya lo usa otro producto",
+	"%s %q is already used by another product":                       "%s %q ya lo usa otro producto",
+	"product %s was deleted":                                         "el producto %s fue eliminado",
+	"product %s was replaced by %s":                                  "el producto %s fue reemplazado por %s",
+	"product no longer exists":                                       "el producto ya no existe",
+	"service temporarily unavailable":                                "servicio no disponible temporalmente",
+	"request timed out":                                              "la solicitud excedió el tiempo de espera",
+	"price suggestions are temporarily unavailable":                  "las sugerencias de precio no están disponibles temporalmente",
+	"no price suggestion for this product":                           "no hay sugerencia de precio para este producto",
+	"internal server error":                                          "error interno del servidor",
+	"not found":                                                      "no encontrado",
+	"content rejected by moderation":                                 "contenido rechazado por la moderación",
+	"product is not pending review":                                  "el producto no está pendiente de revisión",
+	"invalid status transition":                                      "transición de estado inválida",
+	"cannot move a product from %q to %q":                            "no se puede pasar un producto de %q a %q",
+	"only a draft can be scheduled for publishing, not a %s product": "solo un borrador se puede programar para publicarse, no un producto %s",
+	"insufficient stock":                                             "stock insuficiente",
+	"stock adjustment must be non-zero":                              "el ajuste de stock no puede ser cero",
+	"product already has the maximum number of images":               "el producto ya tiene la cantidad máxima de imágenes",
+	"image content type must be image/jpeg, image/png, image/webp or image/gif": "el tipo de contenido de la imagen debe ser image/jpeg, image/png, image/webp o image/gif",
+	"replacement product must exist and differ from the deleted one":            "el producto de reemplazo debe existir y ser distinto del eliminado",
+	"currency must be a supported ISO 4217 code":                                "la moneda debe ser un código ISO 4217 admitido",
+	"no exchange rate for the requested currency":                               "no hay tipo de cambio para la moneda solicitada",
+	"locale must be a BCP 47 language tag such as es or pt-BR":                  "el idioma debe ser una etiqueta BCP 47 como es o pt-BR",
+	"a bulk delete cannot remove more than %d products, narrow the selection":   "un borrado masivo no puede eliminar más de %d productos, acote la selección",
+	"the products selected changed since the dry run":                           "los productos seleccionados cambiaron desde la simulación",
+	"a bulk delete needs the confirmation_token of a dry run":                   "un borrado masivo necesita el confirmation_token de una simulación",
+	"category not found":                                 "categoría no encontrada",
+	"category does not exist":                            "la categoría no existe",
+	"category still has products":                        "la categoría todavía tiene productos",
+	"invalid category data":                              "datos de categoría inválidos",
+	"review not found":                                   "reseña no encontrada",
+	"review was modified concurrently":                   "la reseña fue modificada simultáneamente",
+	"only the author may change a review":                "solo el autor puede modificar una reseña",
+	"favorites require an authenticated user":            "los favoritos requieren un usuario autenticado",
+	"report has not been generated yet":                  "el informe todavía no se generó",
+	"search query must not be empty":                     "la búsqueda no puede estar vacía",
+	"invalid time range":                                 "rango de tiempo inválido",
+	"from must not be after to":                          "from no puede ser posterior a to",
+	"at most %d days can be reported":                    "se pueden informar como máximo %d días",
+	"invalid notification rule":                          "regla de notificación inválida",
+	"only read-only SELECT statements are allowed":       "solo se permiten sentencias SELECT de solo lectura",
+	"tenant ID must be 1-64 letters, digits, '-' or '_'": "el ID de inquilino debe tener de 1 a 64 letras, dígitos, '-' o '_'",
+	"not allowed to perform this action":                 "no tiene permiso para realizar esta acción",
+
+	// Product validation
+	"name is required":                                            "el nombre es obligatorio",
+	"price cannot be negative":                                    "el precio no puede ser negativo",
+	"cost_price cannot be negative":                               "cost_price no puede ser negativo",
+	"cost_price must be a number of at least 0":                   "cost_price debe ser un número mayor o igual a 0",
+	"cost_price can only be set by admins":                        "solo los administradores pueden establecer cost_price",
+	"invalid cost_price":                                          "cost_price inválido",
+	"cost_price must be given again when the price changes to %s": "cost_price debe indicarse de nuevo cuando el precio pasa a %s",
+	"cost_price must be given when the copy is priced in %s":      "cost_price debe indicarse cuando la copia tiene precio en %s",
+	"publish_at must be in the future":                            "publish_at debe estar en el futuro",
+	"expires_at must be in the future":                            "expires_at debe estar en el futuro",
+	"auto_archive_at must be in the future":                       "auto_archive_at debe estar en el futuro",
+	"a product cannot have more than %d tags":                     "un producto no puede tener más de %d etiquetas",
+	"a product cannot have more than %d translations":             "un producto no puede tener más de %d traducciones",
+	"tags cannot be longer than %d characters":                    "las etiquetas no pueden tener más de %d caracteres",
+	"tag %q cannot contain a comma":                               "la etiqueta %q no puede contener una coma",
+	"sku cannot be longer than %d characters":                     "el sku no puede tener más de %d caracteres",
+	"sku %q may only contain letters, digits, '-', '_' and '.'":   "el sku %q solo puede contener letras, dígitos, '-', '_' y '.'",
+	"barcode must have 8, 12, 13 or 14 digits":                    "el código de barras debe tener 8, 12, 13 o 14 dígitos",
+	"barcode %q may only contain digits":                          "el código de barras %q solo puede contener dígitos",
+	"barcode %q has an invalid check digit":                       "el código de barras %q tiene un dígito de control inválido",
+	"id cannot be longer than %d characters":                      "el id no puede tener más de %d caracteres",
+	"id %q must be a lowercase UUID":                              "el id %q debe ser un UUID en minúsculas",
+	"id %q does not match the product ID pattern %s":              "el id %q no coincide con el patrón de IDs de producto %s",
+	"amount %q is not a decimal number":                           "el importe %q no es un número decimal",
+	"amount %q is out of range":                                   "el importe %q está fuera de rango",
+	"amount %q has more than %d decimal places for %s":            "el importe %q tiene más de %d decimales para %s",
+	"%q is not a decimal number":                                  "%q no es un número decimal",
+	"%q is out of range":                                          "%q está fuera de rango",
+	"rating must be between %d and %d":                            "la calificación debe estar entre %d y %d",
+	"comment cannot be longer than %d characters":                 "el comentario no puede tener más de %d caracteres",
+
+	// Requests
+	"invalid request body":                                            "cuerpo de la solicitud inválido",
+	"invalid query parameters":                                        "parámetros de consulta inválidos",
+	"request body is required":                                        "el cuerpo de la solicitud es obligatorio",
+	"request body is not valid JSON":                                  "el cuerpo de la solicitud no es JSON válido",
+	"request does not match the API specification":                    "la solicitud no cumple la especificación de la API",
+	"API version %s is not supported; use 1 to %d":                    "la versión de la API %s no es compatible; use de 1 a %d",
+	"limit must be between %d and %d":                                 "limit debe estar entre %d y %d",
+	"page cannot exceed %d":                                           "page no puede superar %d",
+	"min_price cannot be greater than max_price":                      "min_price no puede ser mayor que max_price",
+	"created_after cannot be later than created_before":               "created_after no puede ser posterior a created_before",
+	"updated_after cannot be later than updated_before":               "updated_after no puede ser posterior a updated_before",
+	"since must be an RFC 3339 timestamp or a next_token":             "since debe ser una fecha RFC 3339 o un next_token",
+	"invalid change checkpoint":                                       "punto de control de cambios no válido",
+	"after_id and after_value must be given together":                 "after_id y after_value deben indicarse juntos",
+	"after_id and after_value cannot be combined with cursor or page": "after_id y after_value no se pueden combinar con cursor ni page",
+	"invalid after_value":                                             "after_value inválido",
+	"a price position must be a non-negative number, got %q":          "una posición de precio debe ser un número no negativo, se recibió %q",
+	"a %s position must be an RFC 3339 time, got %q":                  "una posición de %s debe ser una fecha RFC 3339, se recibió %q",
+	"%s must be an RFC 3339 timestamp":                                "%s debe ser una fecha RFC 3339",
+	"fields has unknown field %q; use %s":                             "fields tiene el campo desconocido %q; use %s",
+	"then_by has unknown field %q; use %s":                            "then_by tiene el campo desconocido %q; use %s",
+	"then_by repeats the sort field %q":                               "then_by repite el campo de orden %q",
+	"then_by cannot be combined with after_id and after_value":        "then_by no se puede combinar con after_id y after_value",
+	"tags cannot list more than %d tags":                              "tags no puede listar más de %d etiquetas",
+	"only admins may list %s products":                                "solo los administradores pueden listar productos %s",
+	"from must be a date in YYYY-MM-DD format":                        "from debe ser una fecha con formato AAAA-MM-DD",
+	"to must be a date in YYYY-MM-DD format":                          "to debe ser una fecha con formato AAAA-MM-DD",
+	"dry_run must be true or false":                                   "dry_run debe ser true o false",
+	"either ids or filter is required, not both":                      "se requiere ids o filter, no ambos",
+	"filter must have at least one criterion":                         "filter debe tener al menos un criterio",
+	"invalid cursor":                                                  "cursor inválido",
+	"cursor has expired":                                              "el cursor expiró",
+	"cursor does not match the current filters":                       "el cursor no coincide con los filtros actuales",
+
+	// Preconditions
+	"updates require an If-Match header with the product's ETag":          "las actualizaciones requieren un encabezado If-Match con el ETag del producto",
+	"product does not match If-Match":                                     "el producto no coincide con If-Match",
+	"If-Match must be the product's ETag or *":                            "If-Match debe ser el ETag del producto o *",
+	"If-None-Match on PUT must be * and cannot be combined with If-Match": "If-None-Match en PUT debe ser * y no se puede combinar con If-Match",
+	"product already exists":                                              "el producto ya existe",
+	"id in the body does not match the URL":                               "el id del cuerpo no coincide con la URL",
+
+	// Imports
+	"a multipart file field named file is required":                      "se requiere un campo de archivo multipart llamado file",
+	"format must be csv or ndjson":                                       "format debe ser csv o ndjson",
+	"import files cannot exceed %d rows or %d MB":                        "los archivos de importación no pueden superar %d filas o %d MB",
+	"price is required and must be greater than 0":                       "el precio es obligatorio y debe ser mayor que 0",
+	"CSV header must include name and price":                             "el encabezado CSV debe incluir name y price",
+	"unknown CSV column %q":                                              "columna CSV desconocida %q",
+	"NDJSON lines cannot exceed 1 MB":                                    "las líneas NDJSON no pueden superar 1 MB",
+	"id is not accepted in imports, imported products get generated IDs": "id no se acepta en importaciones, los productos importados reciben IDs generados",
+
+	// Authentication
+	"unauthorized":                               "no autorizado",
+	"missing bearer token":                       "falta el token bearer",
+	"invalid or expired token":                   "token inválido o vencido",
+	"token is not valid for this tenant":         "el token no es válido para este inquilino",
+	"token is not bound to a tenant":             "el token no está asociado a un inquilino",
+	"a bearer token is required to use a tenant": "se requiere un token bearer para usar un inquilino",
+
+	// Field rules, see ruleMessage
+	"%s is required":                                            "%s es obligatorio",
+	"%s must be at least %s":                                    "%s debe ser como mínimo %s",
+	"%s must be at most %s":                                     "%s debe ser como máximo %s",
+	"%s must be greater than %s":                                "%s debe ser mayor que %s",
+	"%s must be less than %s":                                   "%s debe ser menor que %s",
+	"%s must be one of: %s":                                     "%s debe ser uno de: %s",
+	"%s must be a %s":                                           "%s debe ser de tipo %s",
+	"%s must be a supported ISO 4217 code":                      "%s debe ser un código ISO 4217 admitido",
+	"%s must be a number or an object with amount and currency": "%s debe ser un número o un objeto con amount y currency",
+	"%s must have an amount greater than 0 and a supported ISO 4217 currency": "%s debe tener un importe mayor que 0 y una moneda ISO 4217 admitida",
+	"%s is invalid (%s)": "%s no es válido (%s)",
+}
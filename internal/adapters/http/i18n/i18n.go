@@ -0,0 +1,174 @@
+// Package i18n translates the messages of API errors into the language a
+// client asks for with Accept-Language. Messages are written in English,
+// which is also the fallback for languages without a catalog and for
+// messages a catalog does not know.
+package i18n
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
+)
+
+const languageContextKey = "i18n.language"
+
+// Supported lists the languages messages are available in. English comes
+// first, so it is chosen when nothing else matches.
+var Supported = []language.Tag{language.English, language.Spanish}
+
+var matcher = language.NewMatcher(Supported)
+
+// catalogs holds the translations of each language but English, keyed by
+// the English message
+var catalogs = map[language.Tag]*catalog{
+	language.Spanish: newCatalog(spanish),
+}
+
+// verb matches the fmt verbs of a catalog message, optionally with an
+// explicit argument index such as %[2]s so translations can reorder them
+var verb = regexp.MustCompile(`%(?:\[(\d+)\])?([sqdv])`)
+
+// catalog translates messages of one language. Messages without verbs are
+// looked up as they are; those with verbs are templates matched against
+// the formatted message, whose arguments carry over to the translation.
+type catalog struct {
+	exact     map[string]string
+	templates []template
+}
+
+type template struct {
+	pattern     *regexp.Regexp
+	translation string
+	// literal is the length of the message without its verbs
+	literal int
+}
+
+func newCatalog(messages map[string]string) *catalog {
+	c := &catalog{exact: map[string]string{}}
+	for message, translation := range messages {
+		if !verb.MatchString(message) {
+			c.exact[message] = translation
+			continue
+		}
+		c.templates = append(c.templates, template{
+			pattern:     templatePattern(message),
+			translation: translation,
+			literal:     len(verb.ReplaceAllString(message, "")),
+		})
+	}
+	// Longer templates are more specific: "%s must be at least %s" has to
+	// win over "%s must be %s"
+	sort.Slice(c.templates, func(i, j int) bool {
+		if c.templates[i].literal != c.templates[j].literal {
+			return c.templates[i].literal > c.templates[j].literal
+		}
+		return c.templates[i].pattern.String() < c.templates[j].pattern.String()
+	})
+	return c
+}
+
+// templatePattern turns a message with fmt verbs into a regexp capturing
+// the text each verb was formatted into
+func templatePattern(message string) *regexp.Regexp {
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	last := 0
+	for _, match := range verb.FindAllStringSubmatchIndex(message, -1) {
+		pattern.WriteString(regexp.QuoteMeta(message[last:match[0]]))
+		switch message[match[4]:match[5]] {
+		case "d":
+			pattern.WriteString(`(-?\d+)`)
+		case "q":
+			pattern.WriteString(`("(?:[^"\\]|\\.)*")`)
+		default:
+			pattern.WriteString(`(.+?)`)
+		}
+		last = match[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(message[last:]) + "$")
+	return regexp.MustCompile(pattern.String())
+}
+
+// translate returns the translation of message, or "" when the catalog
+// does not know it
+func (c *catalog) translate(message string) string {
+	if translation, ok := c.exact[message]; ok {
+		return translation
+	}
+	for _, t := range c.templates {
+		args := t.pattern.FindStringSubmatch(message)
+		if args == nil {
+			continue
+		}
+		next := 0
+		return verb.ReplaceAllStringFunc(t.translation, func(v string) string {
+			index := next
+			if sub := verb.FindStringSubmatch(v); sub[1] != "" {
+				index, _ = strconv.Atoi(sub[1])
+				index--
+			}
+			next++
+			if index < 0 || index >= len(args)-1 {
+				return v
+			}
+			return args[index+1]
+		})
+	}
+	return ""
+}
+
+// Translate returns message in lang. Messages that wrap a cause, such as
+// "invalid product data: name is required", are translated part by part;
+// parts the catalog does not know stay in English.
+func Translate(lang language.Tag, message string) string {
+	c, ok := catalogs[lang]
+	if !ok || message == "" {
+		return message
+	}
+	if translation, ok := c.exact[message]; ok {
+		return translation
+	}
+	// A known head is a wrapping error; otherwise the colon may belong to
+	// the message itself, as in "sort_by must be one of: name, price"
+	if head, cause, found := strings.Cut(message, ": "); found {
+		if translation := c.translate(head); translation != "" {
+			return translation + ": " + Translate(lang, cause)
+		}
+	}
+	if translation := c.translate(message); translation != "" {
+		return translation
+	}
+	if head, cause, found := strings.Cut(message, ": "); found {
+		return head + ": " + Translate(lang, cause)
+	}
+	return message
+}
+
+// Language is the supported language that best matches the request's
+// Accept-Language, English when none does
+func Language(c *gin.Context) language.Tag {
+	if lang, ok := c.Get(languageContextKey); ok {
+		return lang.(language.Tag)
+	}
+	lang := language.English
+	if header := c.GetHeader("Accept-Language"); header != "" {
+		accepted, _, err := language.ParseAcceptLanguage(header)
+		if err == nil && len(accepted) > 0 {
+			_, index, confidence := matcher.Match(accepted...)
+			if confidence != language.No {
+				lang = Supported[index]
+			}
+		}
+	}
+	c.Set(languageContextKey, lang)
+	return lang
+}
+
+// T translates message into the request's language
+func T(c *gin.Context, message string) string {
+	return Translate(Language(c), message)
+}
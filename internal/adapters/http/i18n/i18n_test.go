@@ -0,0 +1,48 @@
+package i18n
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{"product not found", "producto no encontrado"},
+		{"limit must be between 1 and 100", "limit debe estar entre 1 y 100"},
+		{`barcode "123" has an invalid check digit`, `el código de barras "123" tiene un dígito de control inválido`},
+		{"invalid product data: name is required", "datos de producto inválidos: el nombre es obligatorio"},
+		{"invalid product data: something new", "datos de producto inválidos: something new"},
+		{"sort_by must be one of: name, price", "sort_by debe ser uno de: name, price"},
+		{"price must be a number or an object with amount and currency", "price debe ser un número o un objeto con amount y currency"},
+		{"100% unknown", "100% unknown"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Translate(language.Spanish, tt.message), tt.message)
+	}
+	assert.Equal(t, "product not found", Translate(language.English, "product not found"))
+}
+
+func TestLanguage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	for header, want := range map[string]language.Tag{
+		"":                  language.English,
+		"es":                language.Spanish,
+		"es-AR,es;q=0.9":    language.Spanish,
+		"en-US, es;q=0.5":   language.English,
+		"fr, es;q=0.5":      language.Spanish,
+		"fr":                language.English,
+		"not a language!!!": language.English,
+	} {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/", nil)
+		c.Request.Header.Set("Accept-Language", header)
+		assert.Equal(t, want, Language(c), header)
+	}
+}
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/i18n"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+type ImageHandler struct {
+	service ports.ImageService
+	logger  *slog.Logger
+}
+
+func NewImageHandler(service ports.ImageService, logger *slog.Logger) *ImageHandler {
+	return &ImageHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// AddImageRequest names the content type of the image to upload
+type AddImageRequest struct {
+	ContentType string `json:"content_type" binding:"required"`
+}
+
+// AddImage records a new product image and returns the presigned URL the
+// client uploads it to
+func (h *ImageHandler) AddImage(c *gin.Context) {
+	id := c.Param("id")
+	var req AddImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, err.Error())})
+		return
+	}
+
+	image, upload, err := h.service.AddImage(c.Request.Context(), id, req.ContentType)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, err.Error())})
+		case errors.Is(err, domain.ErrUnsupportedImageType):
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, err.Error())})
+		case errors.Is(err, domain.ErrTooManyImages), errors.Is(err, domain.ErrConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": i18n.T(c, err.Error())})
+		default:
+			h.logger.ErrorContext(c.Request.Context(), "failed to add product image", "id", id, "error", err)
+			respondError(c, err)
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"image": image, "upload": upload})
+}
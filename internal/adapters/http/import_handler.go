@@ -0,0 +1,317 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/i18n"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/middleware"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+// maxImportBytes bounds the size of an uploaded import file
+const maxImportBytes = 10 << 20
+
+var (
+	errImportTooLarge = fmt.Errorf("import files cannot exceed %d rows or %d MB", domain.MaxImportRows, maxImportBytes>>20)
+	errImportFormat   = errors.New("format must be csv or ndjson")
+	errPriceRequired  = errors.New("price is required and must be greater than 0")
+	errNameRequired   = errors.New("name is required")
+)
+
+// importColumns are the CSV columns an import may have; name and price are
+// required
+var importColumns = map[string]bool{
+	"name": true, "description": true, "price": true, "currency": true, "category_id": true,
+	"expires_at": true, "publish_at": true, "auto_archive_at": true, "cost_price": true,
+	"tags": true, "sku": true, "barcode": true,
+}
+
+type ImportHandler struct {
+	service ports.ImportService
+	logger  *slog.Logger
+}
+
+func NewImportHandler(service ports.ImportService, logger *slog.Logger) *ImportHandler {
+	return &ImportHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Import creates products from an uploaded CSV or NDJSON file and reports
+// which rows were skipped and why. With dry_run=true it only validates.
+func (h *ImportHandler) Import(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes+1<<20)
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": i18n.T(c, errImportTooLarge.Error())})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "a multipart file field named file is required")})
+		return
+	}
+	defer file.Close()
+	if header.Size > maxImportBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": i18n.T(c, errImportTooLarge.Error())})
+		return
+	}
+
+	dryRun := false
+	if value := c.Query("dry_run"); value != "" || c.PostForm("dry_run") != "" {
+		if value == "" {
+			value = c.PostForm("dry_run")
+		}
+		dryRun, err = strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "dry_run must be true or false")})
+			return
+		}
+	}
+
+	format := c.PostForm("format")
+	if format == "" {
+		format = importFormat(header.Filename)
+	}
+	parse := parseCSVImport
+	switch format {
+	case "csv":
+	case "ndjson":
+		parse = parseNDJSONImport
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, errImportFormat.Error())})
+		return
+	}
+
+	rows, err := parse(file, middleware.IsAdmin(c))
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, errImportTooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		c.JSON(status, gin.H{"error": i18n.T(c, err.Error())})
+		return
+	}
+
+	summary, err := h.service.Import(c.Request.Context(), rows, dryRun)
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to import products", "rows", len(rows), "error", err)
+		if len(summary.Written) == 0 {
+			respondError(c, err)
+			return
+		}
+		// Some products were created: report them with the failure, so the
+		// rows left can be sent again without duplicating the others
+		status, message := errorStatus(err)
+		if status == http.StatusServiceUnavailable {
+			c.Header("Retry-After", "1")
+		}
+		c.JSON(status, importFailure{Error: i18n.T(c, message), ImportSummary: summary})
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
+
+// importFailure is the answer to an import that failed part way through
+type importFailure struct {
+	Error string `json:"error"`
+	domain.ImportSummary
+}
+
+// importFormat guesses the format of a file from its extension
+func importFormat(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return "csv"
+	case ".ndjson", ".jsonl":
+		return "ndjson"
+	}
+	return ""
+}
+
+// parseCSVImport reads a CSV file with a header row naming its columns.
+// Malformed CSV rejects the whole file, since the rows after it cannot be
+// trusted to line up.
+func parseCSVImport(r io.Reader, isAdmin bool) ([]ports.ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	columns, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+	index := map[string]int{}
+	for i, column := range columns {
+		column = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		if !importColumns[column] {
+			return nil, fmt.Errorf("unknown CSV column %q", column)
+		}
+		index[column] = i
+	}
+	if _, ok := index["name"]; !ok {
+		return nil, errors.New("CSV header must include name and price")
+	}
+	if _, ok := index["price"]; !ok {
+		return nil, errors.New("CSV header must include name and price")
+	}
+
+	var rows []ports.ImportRow
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		if row > domain.MaxImportRows {
+			return nil, errImportTooLarge
+		}
+
+		field := func(column string) string {
+			if i, ok := index[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		input, err := csvImportInput(field, isAdmin)
+		rows = append(rows, ports.ImportRow{Row: row, Input: input, Err: err})
+	}
+	return rows, nil
+}
+
+func csvImportInput(field func(string) string, isAdmin bool) (ports.ProductInput, error) {
+	input := ports.ProductInput{
+		Name:        field("name"),
+		Description: field("description"),
+		CategoryID:  field("category_id"),
+		SKU:         field("sku"),
+		Barcode:     field("barcode"),
+	}
+	if tags := field("tags"); tags != "" {
+		input.Tags = strings.Split(tags, ",")
+	}
+	if input.Name == "" {
+		return input, errNameRequired
+	}
+	currency := field("currency")
+	if currency == "" {
+		currency = domain.DefaultCurrency
+	}
+	if field("price") == "" {
+		return input, errPriceRequired
+	}
+	price, err := domain.ParseMoney(field("price"), currency)
+	if err != nil {
+		return input, err
+	}
+	if price.Amount <= 0 {
+		return input, errPriceRequired
+	}
+	input.Price = price
+
+	for _, column := range []struct {
+		name string
+		dest **time.Time
+	}{
+		{"expires_at", &input.ExpiresAt},
+		{"publish_at", &input.PublishAt},
+		{"auto_archive_at", &input.AutoArchiveAt},
+	} {
+		value := field(column.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return input, fmt.Errorf("%s must be an RFC 3339 timestamp", column.name)
+		}
+		*column.dest = &parsed
+	}
+
+	if value := field("cost_price"); value != "" {
+		if !isAdmin {
+			return input, errors.New(errCostPriceForbidden)
+		}
+		cost, err := domain.ParseDecimal(value)
+		if err != nil || cost.Sign() < 0 {
+			return input, errors.New("cost_price must be a number of at least 0")
+		}
+		input.CostPrice = &cost
+	}
+	return input, nil
+}
+
+// parseNDJSONImport reads one JSON product per line, with the fields of
+// POST /api/v1/products. Blank lines are ignored but still counted, so row
+// numbers match line numbers.
+func parseNDJSONImport(r io.Reader, isAdmin bool) ([]ports.ImportRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+
+	var rows []ports.ImportRow
+	for row := 1; scanner.Scan(); row++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if len(rows) == domain.MaxImportRows {
+			return nil, errImportTooLarge
+		}
+
+		var req CreateProductRequest
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&req); err != nil {
+			rows = append(rows, ports.ImportRow{Row: row, Err: fmt.Errorf("invalid JSON: %w", err)})
+			continue
+		}
+		// Versions only guard updates
+		req.Version = nil
+		rows = append(rows, ports.ImportRow{Row: row, Input: req.toInput(), Err: ndjsonImportError(req, isAdmin)})
+	}
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, errors.New("NDJSON lines cannot exceed 1 MB")
+		}
+		return nil, fmt.Errorf("failed to read import file: %w", err)
+	}
+	return rows, nil
+}
+
+// ndjsonImportError applies the checks the create endpoint's binding makes
+func ndjsonImportError(req CreateProductRequest, isAdmin bool) error {
+	switch {
+	case req.Name == "":
+		return errNameRequired
+	case req.Price.Amount <= 0:
+		return errPriceRequired
+	case req.Price.Validate() != nil:
+		return domain.ErrUnsupportedCurrency
+	case req.ID != "":
+		// Batch writes cannot be conditional, so a chosen ID could replace
+		// an existing product
+		return errors.New("id is not accepted in imports, imported products get generated IDs")
+	case req.CostPrice != nil && !isAdmin:
+		return errors.New(errCostPriceForbidden)
+	case req.CostPrice != nil && req.CostPrice.Sign() < 0:
+		return errors.New("cost_price must be a number of at least 0")
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// stubImportService skips the rows that failed to parse and records the rest
+type stubImportService struct {
+	rows   []ports.ImportRow
+	dryRun bool
+	// err fails the import after writing the first valid row
+	err error
+}
+
+func (s *stubImportService) Import(ctx context.Context, rows []ports.ImportRow, dryRun bool) (domain.ImportSummary, error) {
+	s.rows, s.dryRun = rows, dryRun
+	summary := domain.ImportSummary{DryRun: dryRun, Errors: []domain.ImportRowError{}}
+	for _, row := range rows {
+		if row.Err != nil {
+			summary.Skip(row.Row, row.Err)
+			continue
+		}
+		if s.err != nil && summary.Imported > 0 {
+			summary.Skip(row.Row, domain.ErrNotImported)
+			continue
+		}
+		summary.Imported++
+		if s.err != nil {
+			summary.Written = append(summary.Written, "prod-1")
+		}
+	}
+	return summary, s.err
+}
+
+func newImportRequest(t *testing.T, target, filename, content string) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filename)
+	require.NoError(t, err)
+	_, err = part.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req, _ := http.NewRequest("POST", target, &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func setupImportRouter(service ports.ImportService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/v1/products/import", NewImportHandler(service, slog.Default()).Import)
+	return router
+}
+
+func TestImportHandler_CSV(t *testing.T) {
+	service := &stubImportService{}
+	router := setupImportRouter(service)
+	content := "name,price,category_id,publish_at,tags\n" +
+		"Laptop,999.5,electronics,,\n" +
+		"Mouse,,electronics,,\n" +
+		"Desk,120,,tomorrow,\n" +
+		"\"Cable, USB-C\",9,,2030-01-01T00:00:00Z,\"usb,cables\"\n"
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newImportRequest(t, "/api/v1/products/import?dry_run=true", "products.csv", content))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, service.dryRun)
+	require.Len(t, service.rows, 4)
+	assert.Equal(t, ports.ProductInput{Name: "Laptop", Price: domain.Money{Amount: 99950, Currency: "USD"}, CategoryID: "electronics"}, service.rows[0].Input)
+	assert.Equal(t, "Cable, USB-C", service.rows[3].Input.Name)
+	require.NotNil(t, service.rows[3].Input.PublishAt)
+	assert.Equal(t, []string{"usb", "cables"}, service.rows[3].Input.Tags)
+
+	var summary domain.ImportSummary
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &summary))
+	assert.Equal(t, 2, summary.Imported)
+	assert.Equal(t, 2, summary.Skipped)
+	assert.Equal(t, []domain.ImportRowError{
+		{Row: 2, Error: "price is required and must be greater than 0"},
+		{Row: 3, Error: "publish_at must be an RFC 3339 timestamp"},
+	}, summary.Errors)
+}
+
+func TestImportHandler_NDJSON(t *testing.T) {
+	service := &stubImportService{}
+	router := setupImportRouter(service)
+	content := `{"name":"Laptop","price":999}` + "\n\n" +
+		`{"name":"Mouse","price":25,"colour":"red"}` + "\n" +
+		`{"name":"Desk","price":120,"cost_price":80}` + "\n"
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newImportRequest(t, "/api/v1/products/import", "products.ndjson", content))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, service.dryRun)
+	require.Len(t, service.rows, 3)
+	assert.NoError(t, service.rows[0].Err)
+	// Blank lines keep their number, so rows match lines
+	assert.Equal(t, 3, service.rows[1].Row)
+	assert.ErrorContains(t, service.rows[1].Err, "unknown field")
+	assert.EqualError(t, service.rows[2].Err, errCostPriceForbidden)
+}
+
+func TestImportHandler_PartialFailure(t *testing.T) {
+	service := &stubImportService{err: domain.NewError(domain.KindUnavailable, "storage is unavailable")}
+	router := setupImportRouter(service)
+	content := "name,price\nLaptop,999\nMouse,25\n"
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newImportRequest(t, "/api/v1/products/import", "products.csv", content))
+
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	var body struct {
+		Error string `json:"error"`
+		domain.ImportSummary
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "storage is unavailable", body.Error)
+	assert.Equal(t, 1, body.Imported)
+	assert.Equal(t, []string{"prod-1"}, body.Written)
+	assert.Equal(t, []domain.ImportRowError{{Row: 2, Error: domain.ErrNotImported.Error()}}, body.Errors)
+}
+
+func TestImportHandler_RejectsBadFiles(t *testing.T) {
+	router := setupImportRouter(&stubImportService{})
+
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+	}{
+		{"unknown format", "products.txt", "name,price\nLaptop,10\n"},
+		{"unknown column", "products.csv", "name,price,colour\nLaptop,10,red\n"},
+		{"missing price column", "products.csv", "name\nLaptop\n"},
+		{"malformed csv", "products.csv", "name,price\n\"Laptop,10\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, newImportRequest(t, "/api/v1/products/import", tt.filename, tt.content))
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newImportRequest(t, "/api/v1/products/import", "products.csv",
+		"name,price\n"+strings.Repeat("Laptop,10\n", domain.MaxImportRows+1)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
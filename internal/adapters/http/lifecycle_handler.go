@@ -0,0 +1,60 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/dto"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/i18n"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+type LifecycleHandler struct {
+	service ports.LifecycleService
+	logger  *slog.Logger
+}
+
+func NewLifecycleHandler(service ports.LifecycleService, logger *slog.Logger) *LifecycleHandler {
+	return &LifecycleHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Publish makes a draft or archived product live
+func (h *LifecycleHandler) Publish(c *gin.Context) {
+	h.transition(c, domain.StatusPublished)
+}
+
+// Archive takes a published product out of listings
+func (h *LifecycleHandler) Archive(c *gin.Context) {
+	h.transition(c, domain.StatusArchived)
+}
+
+// Discontinue retires a product for good
+func (h *LifecycleHandler) Discontinue(c *gin.Context) {
+	h.transition(c, domain.StatusDiscontinued)
+}
+
+func (h *LifecycleHandler) transition(c *gin.Context, status string) {
+	id := c.Param("id")
+	product, err := h.service.Transition(c.Request.Context(), id, status)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, err.Error())})
+		case errors.Is(err, domain.ErrInvalidTransition), errors.Is(err, domain.ErrConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": i18n.T(c, err.Error())})
+		default:
+			h.logger.ErrorContext(c.Request.Context(), "failed to change product status", "id", id, "status", status, "error", err)
+			respondError(c, err)
+		}
+		return
+	}
+
+	c.Header("ETag", productETag(c, product, ""))
+	c.JSON(http.StatusOK, dto.NewProductResponse(product))
+}
@@ -0,0 +1,54 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"golang.org/x/text/language"
+)
+
+// localize returns the product with the name and description of the
+// translation that best matches the request's Accept-Language, along with
+// the locale chosen. Products keep their own text when it matches better
+// or no translation matches at all.
+func (h *ProductHandler) localize(c *gin.Context, product domain.Product) (domain.Product, string) {
+	c.Writer.Header().Add("Vary", "Accept-Language")
+	locale := h.preferredLocale(c, product)
+	if locale == "" {
+		return product, h.defaultLocale.String()
+	}
+	return product.Translated(locale), locale
+}
+
+// localizeAll localizes every product of a listing in place
+func (h *ProductHandler) localizeAll(c *gin.Context, products []domain.Product) {
+	for i, product := range products {
+		products[i], _ = h.localize(c, product)
+	}
+}
+
+// preferredLocale picks one of the product's translations for the
+// request, or "" for the product's own text
+func (h *ProductHandler) preferredLocale(c *gin.Context, product domain.Product) string {
+	header := c.GetHeader("Accept-Language")
+	if header == "" || len(product.Translations) == 0 {
+		return ""
+	}
+	accepted, _, err := language.ParseAcceptLanguage(header)
+	if err != nil || len(accepted) == 0 {
+		return ""
+	}
+
+	// The product's own text is in the default locale and comes first, so
+	// it also wins when nothing matches
+	locales := product.Locales()
+	supported := make([]language.Tag, 0, len(locales)+1)
+	supported = append(supported, h.defaultLocale)
+	for _, locale := range locales {
+		supported = append(supported, language.Make(locale))
+	}
+	_, index, confidence := language.NewMatcher(supported).Match(accepted...)
+	if index == 0 || confidence == language.No {
+		return ""
+	}
+	return locales[index-1]
+}
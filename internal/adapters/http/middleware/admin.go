@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/i18n"
+)
+
+// AdminKeyHeader carries the shared secret for admin-only routes
+const AdminKeyHeader = "X-Admin-Key"
+
+// RequireAdminKey rejects requests that do not present the configured admin key
+func RequireAdminKey(key string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provided := c.GetHeader(AdminKeyHeader)
+		if key == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(key)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": i18n.T(c, "unauthorized")})
+			return
+		}
+		c.Next()
+	}
+}
+
+// adminContextKey marks requests that presented a valid admin key
+const adminContextKey = "is_admin"
+
+// IdentifyAdmin flags requests that present the configured admin key without
+// rejecting the others, for public routes that show admins more detail. As
+// the response then depends on the key, caches are told to vary on it.
+func IdentifyAdmin(key string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", AdminKeyHeader)
+		provided := c.GetHeader(AdminKeyHeader)
+		if key != "" && subtle.ConstantTimeCompare([]byte(provided), []byte(key)) == 1 {
+			c.Set(adminContextKey, true)
+		}
+		c.Next()
+	}
+}
+
+// IsAdmin reports whether IdentifyAdmin recognized the request as an admin's
+func IsAdmin(c *gin.Context) bool {
+	return c.GetBool(adminContextKey)
+}
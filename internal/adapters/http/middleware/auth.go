@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/i18n"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/auth"
+)
+
+// claimsContextKey holds the *auth.Claims of an authenticated request
+const claimsContextKey = "auth_claims"
+
+// RequireJWT rejects requests without a valid bearer token and stores the
+// token's claims in the context for the handlers behind it, with the
+// token's subject as the actor of their writes. Requests AuthenticateJWT
+// already verified are not verified again.
+func RequireJWT(verifier *auth.Verifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := Claims(c); ok {
+			c.Next()
+			return
+		}
+		if !authenticate(c, verifier, true) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// AuthenticateJWT is RequireJWT for routes open to anonymous callers: a
+// request without an Authorization header goes through unauthenticated,
+// while one with an invalid token is rejected
+func AuthenticateJWT(verifier *auth.Verifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !authenticate(c, verifier, false) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// authenticate verifies the request's bearer token and stores its claims,
+// reporting whether the request may go on
+func authenticate(c *gin.Context, verifier *auth.Verifier, required bool) bool {
+	header := c.GetHeader("Authorization")
+	if header == "" && !required {
+		return true
+	}
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		c.Header("WWW-Authenticate", `Bearer`)
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": i18n.T(c, "missing bearer token")})
+		return false
+	}
+
+	claims, err := verifier.Verify(token)
+	if err != nil {
+		c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": i18n.T(c, auth.ErrInvalidToken.Error())})
+		return false
+	}
+
+	c.Set(claimsContextKey, claims)
+	ctx := ports.WithActor(c.Request.Context(), claims.Subject)
+	c.Request = c.Request.WithContext(withLogAttrs(ctx, "user_id", claims.Subject))
+	return true
+}
+
+// Claims returns the claims RequireJWT stored for the request, if any
+func Claims(c *gin.Context) (*auth.Claims, bool) {
+	value, ok := c.Get(claimsContextKey)
+	if !ok {
+		return nil, false
+	}
+	claims, ok := value.(*auth.Claims)
+	return claims, ok
+}
+
+// Authorize lets the request through only if the authenticated caller's
+// roles allow action. It must run after RequireJWT.
+func Authorize(authorizer ports.Authorizer, action string, logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := Claims(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": i18n.T(c, "missing bearer token")})
+			return
+		}
+
+		principal := domain.Principal{Subject: claims.Subject, Roles: claims.Roles}
+		if err := authorizer.Authorize(c.Request.Context(), principal, action); err != nil {
+			if errors.Is(err, domain.ErrForbidden) {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": i18n.T(c, err.Error())})
+				return
+			}
+			logger.ErrorContext(c.Request.Context(), "authorization failed", "subject", principal.Subject, "action", action, "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": i18n.T(c, "internal server error")})
+			return
+		}
+		c.Next()
+	}
+}
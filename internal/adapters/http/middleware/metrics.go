@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/metrics"
+)
+
+// unmatchedRoute labels requests that hit no route, so scanners probing
+// random paths cannot blow up the label cardinality
+const unmatchedRoute = "unmatched"
+
+// otherMethod labels requests with a method outside standardMethods, which
+// only unmatched requests can carry, for the same reason
+const otherMethod = "other"
+
+var standardMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true, http.MethodPut: true, http.MethodPatch: true,
+	http.MethodDelete: true, http.MethodConnect: true, http.MethodOptions: true, http.MethodTrace: true,
+}
+
+// Metrics records request count, latency and in-flight requests per route
+// template, e.g. /api/v1/products/:id rather than each product's path
+func Metrics(m *metrics.Metrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		method := c.Request.Method
+		if !standardMethods[method] {
+			method = otherMethod
+		}
+
+		done := m.StartRequest(method, route)
+		c.Next()
+		done(c.Writer.Status())
+	}
+}
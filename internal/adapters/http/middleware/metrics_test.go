@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/metrics"
+)
+
+func TestMetrics_BoundsLabels(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := metrics.New()
+	router := gin.New()
+	router.Use(Metrics(m))
+	router.GET("/products/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest("GET", "/products/1", nil),
+		httptest.NewRequest("GET", "/wp-admin", nil),
+		httptest.NewRequest("PROPFIND", "/products/1", nil),
+		httptest.NewRequest("X-SCAN-12345", "/anything", nil),
+	} {
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body := w.Body.String()
+	assert.Contains(t, body, `product_api_http_requests_total{method="GET",route="/products/:id",status="200"} 1`)
+	assert.Contains(t, body, `product_api_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, body, `product_api_http_requests_total{method="other",route="unmatched",status="404"} 2`)
+	assert.NotContains(t, body, "PROPFIND")
+	assert.NotContains(t, body, "X-SCAN")
+}
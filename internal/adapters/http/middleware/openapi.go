@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/i18n"
+)
+
+// Request validation modes
+const (
+	ValidationOff     = "off"
+	ValidationReport  = "report"
+	ValidationEnforce = "enforce"
+)
+
+// ValidationMode holds the mode of a running validation middleware, so it
+// can be switched without rebuilding the router
+type ValidationMode struct {
+	mode atomic.Value
+}
+
+// NewValidationMode starts in one of the request validation modes
+func NewValidationMode(mode string) *ValidationMode {
+	m := &ValidationMode{}
+	m.Set(mode)
+	return m
+}
+
+// Set switches the mode for the requests that follow
+func (m *ValidationMode) Set(mode string) {
+	m.mode.Store(mode)
+}
+
+// Get returns the current mode
+func (m *ValidationMode) Get() string {
+	return m.mode.Load().(string)
+}
+
+// ValidateRequests checks incoming requests against the OpenAPI spec. In
+// off mode they all pass through, in report mode mismatches are only logged
+// and in enforce mode they are answered with 400 before reaching the
+// handler. Routes missing from the spec pass
+// through untouched, and authentication is left to the auth middleware.
+func ValidateRequests(spec *openapi3.T, mode *ValidationMode, logger *slog.Logger) (gin.HandlerFunc, error) {
+	router, err := gorillamux.NewRouter(spec)
+	if err != nil {
+		return nil, err
+	}
+	options := &openapi3filter.Options{
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+	}
+
+	return func(c *gin.Context) {
+		current := mode.Get()
+		if current == ValidationOff {
+			c.Next()
+			return
+		}
+		route, pathParams, err := router.FindRoute(c.Request)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		err = openapi3filter.ValidateRequest(c.Request.Context(), &openapi3filter.RequestValidationInput{
+			Request:    c.Request,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		})
+		if err == nil {
+			c.Next()
+			return
+		}
+
+		field, rule, message := describeValidationError(err)
+		logger.WarnContext(c.Request.Context(), "request does not match the API spec",
+			"method", c.Request.Method, "path", c.Request.URL.Path, "field", field, "rule", rule, "error", message)
+		if current != ValidationEnforce {
+			c.Next()
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error":  i18n.T(c, "request does not match the API specification"),
+			"fields": []gin.H{{"field": field, "rule": rule, "message": message}},
+		})
+	}, nil
+}
+
+// describeValidationError names the offending parameter or body field in
+// the same terms as the handlers' own validation errors
+func describeValidationError(err error) (field, rule, message string) {
+	var requestErr *openapi3filter.RequestError
+	if !errors.As(err, &requestErr) {
+		return "", "openapi", err.Error()
+	}
+
+	var schemaErr *openapi3.SchemaError
+	if errors.As(requestErr.Err, &schemaErr) {
+		field = strings.Join(schemaErr.JSONPointer(), ".")
+		if requestErr.Parameter != nil {
+			field = requestErr.Parameter.Name
+		}
+		return field, schemaErr.SchemaField, schemaErr.Reason
+	}
+	if requestErr.Parameter != nil {
+		return requestErr.Parameter.Name, "openapi", requestErr.Err.Error()
+	}
+	if requestErr.Reason != "" {
+		return "", "openapi", requestErr.Reason
+	}
+	return "", "openapi", requestErr.Error()
+}
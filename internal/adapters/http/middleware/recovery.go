@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"syscall"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/i18n"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/logger"
+)
+
+// ProblemContentType is the media type of RFC 9457 problem details
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 9457 problem details body
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// RequestID is the X-Request-ID to quote when reporting the problem
+	RequestID string `json:"request_id,omitempty"`
+	// Error repeats Title for clients reading the {"error": ...} body
+	// every other error of the API has
+	Error string `json:"error"`
+}
+
+// Recovery turns a panic in a later handler into a 500 problem carrying the
+// request ID, logs it with its stack and hands it to reporter. It must run
+// after RequestLogger, which assigns the request ID and logs the 500.
+func Recovery(appLogger *slog.Logger, reporter ports.ErrorReporter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			value := recover()
+			if value == nil {
+				return
+			}
+			ctx := c.Request.Context()
+			requestLogger := ports.Logger(ctx, appLogger)
+			if clientGone(value) {
+				// Nothing to answer, and nothing wrong with the service
+				requestLogger.WarnContext(ctx, "client went away mid-response", "error", fmt.Sprint(value))
+				c.Abort()
+				return
+			}
+
+			stack := debug.Stack()
+			requestLogger.ErrorContext(ctx, "panic recovered",
+				"panic", fmt.Sprint(value),
+				"method", c.Request.Method,
+				"path", c.Request.URL.Path,
+				"stack", string(stack),
+			)
+			reporter.ReportPanic(ctx, ports.PanicReport{
+				Value:     value,
+				Stack:     stack,
+				RequestID: logger.RequestID(ctx),
+				Method:    c.Request.Method,
+				Route:     c.FullPath(),
+				Path:      c.Request.URL.Path,
+				Tenant:    ports.TenantID(ctx),
+			})
+
+			if c.Writer.Written() {
+				// The status is already sent; all that is left is to cut
+				// the response short
+				c.Abort()
+				return
+			}
+			title := i18n.T(c, "internal server error")
+			body, _ := json.Marshal(Problem{
+				Type:      "about:blank",
+				Title:     title,
+				Status:    http.StatusInternalServerError,
+				Instance:  c.Request.URL.Path,
+				RequestID: logger.RequestID(ctx),
+				Error:     title,
+			})
+			c.Data(http.StatusInternalServerError, ProblemContentType, body)
+			c.Abort()
+		}()
+		c.Next()
+	}
+}
+
+// clientGone reports whether a panic is the connection dropping rather
+// than a bug: net/http's abort, or a write to a closed connection
+func clientGone(value any) bool {
+	err, ok := value.(error)
+	if !ok {
+		return false
+	}
+	if errors.Is(err, http.ErrAbortHandler) {
+		return true
+	}
+	var opErr *net.OpError
+	var syscallErr *os.SyscallError
+	return errors.As(err, &opErr) && errors.As(opErr.Err, &syscallErr) &&
+		(errors.Is(syscallErr.Err, syscall.EPIPE) || errors.Is(syscallErr.Err, syscall.ECONNRESET))
+}
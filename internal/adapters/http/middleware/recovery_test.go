@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+type recordingReporter struct {
+	reports []ports.PanicReport
+}
+
+func (r *recordingReporter) ReportPanic(ctx context.Context, report ports.PanicReport) {
+	r.reports = append(r.reports, report)
+}
+
+func TestRecovery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var logs bytes.Buffer
+	appLogger := slog.New(slog.NewJSONHandler(&logs, nil))
+	reporter := &recordingReporter{}
+
+	router := gin.New()
+	router.Use(RequestLogger(appLogger), Recovery(appLogger, reporter))
+	router.GET("/products/:id", func(c *gin.Context) {
+		var product map[string]string
+		c.String(http.StatusOK, "partial")
+		product["name"] = "boom"
+	})
+	router.GET("/panic", func(c *gin.Context) {
+		panic("unexpected state")
+	})
+	router.GET("/broken", func(c *gin.Context) {
+		panic(http.ErrAbortHandler)
+	})
+
+	t.Run("after writing", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/products/p1", nil)
+		request.Header.Set(RequestIDHeader, "req-1")
+		router.ServeHTTP(w, request)
+
+		require.Len(t, reporter.reports, 1)
+		report := reporter.reports[0]
+		assert.Equal(t, "req-1", report.RequestID)
+		assert.Equal(t, "/products/:id", report.Route)
+		assert.Equal(t, "/products/p1", report.Path)
+		assert.Contains(t, string(report.Stack), "recovery_test.go")
+		assert.Contains(t, logs.String(), `"msg":"panic recovered"`)
+		assert.Contains(t, logs.String(), "assignment to entry in nil map")
+
+		// The handler wrote before panicking, so the 200 stands
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "partial", w.Body.String())
+	})
+
+	t.Run("problem", func(t *testing.T) {
+		reporter.reports = nil
+		w := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/panic", nil)
+		request.Header.Set(RequestIDHeader, "req-2")
+		router.ServeHTTP(w, request)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+		var problem Problem
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+		assert.Equal(t, Problem{
+			Type:      "about:blank",
+			Title:     "internal server error",
+			Status:    http.StatusInternalServerError,
+			Instance:  "/panic",
+			RequestID: "req-2",
+			Error:     "internal server error",
+		}, problem)
+		assert.NotContains(t, w.Body.String(), "unexpected state", "panic values stay out of responses")
+		require.Len(t, reporter.reports, 1)
+		assert.Equal(t, "unexpected state", reporter.reports[0].Value)
+	})
+
+	t.Run("client gone", func(t *testing.T) {
+		reporter.reports = nil
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/broken", nil))
+		assert.Empty(t, reporter.reports)
+		assert.Empty(t, w.Body.String())
+	})
+}
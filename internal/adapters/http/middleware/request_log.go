@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/logger"
+)
+
+// RequestIDHeader carries the ID that correlates a request with its logs
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID accepts IDs generated upstream (load balancers, other
+// services) while keeping arbitrary client input out of the logs
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestLogger propagates the caller's X-Request-ID, or generates one,
+// echoes it in the response and stores it in the request context so logs
+// written while handling the request carry it. Once the request finishes it
+// logs its method, path, status, latency and response size.
+func RequestLogger(appLogger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = uuid.NewString()
+		}
+		c.Header(RequestIDHeader, id)
+		ctx := logger.WithRequestID(c.Request.Context(), id)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+		appLogger.Log(ctx, level, "request completed",
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"route", c.FullPath(),
+			"status", status,
+			"latency_ms", time.Since(start).Milliseconds(),
+			"size", max(c.Writer.Size(), 0),
+			"client_ip", c.ClientIP(),
+		)
+	}
+}
+
+// ScopeLogger stores a request-scoped logger in the request context for the
+// services and repositories handling the request, carrying its route and,
+// when the request is traced, its trace ID. The request ID is added from
+// the context like for every logger, and the tenant and user are added by
+// the middlewares that identify them. It must run after the tracing
+// middleware.
+func ScopeLogger(appLogger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestLogger := appLogger.With("route", c.FullPath())
+		if span := trace.SpanContextFromContext(c.Request.Context()); span.HasTraceID() {
+			requestLogger = requestLogger.With("trace_id", span.TraceID().String())
+		}
+		c.Request = c.Request.WithContext(ports.WithLogger(c.Request.Context(), requestLogger))
+		c.Next()
+	}
+}
+
+// withLogAttrs adds attributes to the request-scoped logger of ctx, if any
+func withLogAttrs(ctx context.Context, args ...any) context.Context {
+	if requestLogger := ports.Logger(ctx, nil); requestLogger != nil {
+		return ports.WithLogger(ctx, requestLogger.With(args...))
+	}
+	return ctx
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/i18n"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// DefaultTenant names the default tenant in headers and token claims,
+// where an empty value would mean no tenant was given
+const DefaultTenant = "default"
+
+// IdentifyTenant scopes the request to the tenant named in header. Requests
+// without it are served from the default tenant.
+func IdentifyTenant(header string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenant := c.GetHeader(header)
+		if tenant == "" || tenant == DefaultTenant {
+			c.Next()
+			return
+		}
+		if err := domain.ValidateTenantID(tenant); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, err.Error())})
+			return
+		}
+		ctx := ports.WithTenant(c.Request.Context(), tenant)
+		c.Request = c.Request.WithContext(withLogAttrs(ctx, "tenant_id", tenant))
+		c.Next()
+	}
+}
+
+// TenantFromClaims binds every request to the tenant of its token when
+// authentication is enabled. It must run after IdentifyTenant and
+// AuthenticateJWT.
+//
+// Authenticated requests act for the tenant in their token's tenant_id
+// claim, DefaultTenant for the default one; tokens without the claim and
+// headers naming another tenant are rejected. Anonymous requests are only
+// served from the default tenant, unless they carry the admin key.
+func TenantFromClaims() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		requested := ports.TenantID(ctx)
+		claims, ok := Claims(c)
+		if !ok {
+			if requested != "" && !IsAdmin(c) {
+				c.Header("WWW-Authenticate", `Bearer`)
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": i18n.T(c, "a bearer token is required to use a tenant")})
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if claims.TenantID == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": i18n.T(c, "token is not bound to a tenant")})
+			return
+		}
+		tenant := claims.TenantID
+		if tenant == DefaultTenant {
+			tenant = ""
+		} else if err := domain.ValidateTenantID(tenant); err != nil {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": i18n.T(c, err.Error())})
+			return
+		}
+		if requested != "" && requested != tenant {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": i18n.T(c, "token is not valid for this tenant")})
+			return
+		}
+		if requested == "" && tenant != "" {
+			// A tenant named in the header is already logged
+			ctx = withLogAttrs(ports.WithTenant(ctx, tenant), "tenant_id", tenant)
+			c.Request = c.Request.WithContext(ctx)
+		}
+		c.Next()
+	}
+}
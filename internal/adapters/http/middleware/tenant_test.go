@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/auth"
+)
+
+func TestTenantFromClaims(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	verifier := auth.NewVerifier(func(*jwt.Token) (interface{}, error) { return &key.PublicKey, nil }, "issuer", "")
+	token := func(tenant string) string {
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodES256, auth.Claims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   "user-1",
+				Issuer:    "issuer",
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			},
+			TenantID: tenant,
+		}).SignedString(key)
+		require.NoError(t, err)
+		return "Bearer " + signed
+	}
+
+	router := gin.New()
+	router.Use(IdentifyAdmin("admin-key"), IdentifyTenant("X-Tenant-ID"), AuthenticateJWT(verifier), TenantFromClaims())
+	router.GET("/products", func(c *gin.Context) {
+		c.String(http.StatusOK, "tenant=%s", ports.TenantID(c.Request.Context()))
+	})
+
+	tests := []struct {
+		name          string
+		authorization string
+		tenant        string
+		adminKey      string
+		status        int
+		body          string
+	}{
+		{"anonymous default tenant", "", "", "", http.StatusOK, "tenant="},
+		{"anonymous naming a tenant", "", "acme", "", http.StatusUnauthorized, ""},
+		{"admin naming a tenant", "", "acme", "admin-key", http.StatusOK, "tenant=acme"},
+		{"token tenant", token("acme"), "", "", http.StatusOK, "tenant=acme"},
+		{"token and matching header", token("acme"), "acme", "", http.StatusOK, "tenant=acme"},
+		{"token and other tenant", token("acme"), "globex", "", http.StatusForbidden, ""},
+		{"default tenant token", token(DefaultTenant), "", "", http.StatusOK, "tenant="},
+		{"default tenant token naming a tenant", token(DefaultTenant), "acme", "", http.StatusForbidden, ""},
+		{"token without the claim", token(""), "acme", "", http.StatusForbidden, ""},
+		{"invalid token", "Bearer nope", "", "", http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/products", nil)
+			if tt.authorization != "" {
+				request.Header.Set("Authorization", tt.authorization)
+			}
+			if tt.tenant != "" {
+				request.Header.Set("X-Tenant-ID", tt.tenant)
+			}
+			if tt.adminKey != "" {
+				request.Header.Set(AdminKeyHeader, tt.adminKey)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, request)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.body != "" {
+				assert.Equal(t, tt.body, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/i18n"
+)
+
+// API versions served. Each has its own URL prefix, /api/v1 and /api/v2.
+const (
+	APIVersion1      = 1
+	APIVersion2      = 2
+	LatestAPIVersion = APIVersion2
+)
+
+// VersionHeader names the API version a response was rendered for
+const VersionHeader = "API-Version"
+
+// BuildVersionHeader names the version of the binary that answered, which
+// unlike VersionHeader changes with every release
+const BuildVersionHeader = "X-API-Version"
+
+const apiVersionKey = "api_version"
+
+// versionMediaType matches the vendor media types clients may send in
+// Accept to pick a version, such as application/vnd.products.v2+json
+var versionMediaType = regexp.MustCompile(`application/vnd\.products\.v(\d+)\+json`)
+
+// Version sets the API version of requests under a versioned URL prefix.
+// An Accept header naming a vendor media type overrides the prefix, so
+// clients can move to a newer response shape without changing URLs;
+// versions that are not served are answered with 406.
+func Version(prefixVersion int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		version := prefixVersion
+		if match := versionMediaType.FindStringSubmatch(c.GetHeader("Accept")); match != nil {
+			requested, err := strconv.Atoi(match[1])
+			if err != nil || requested < APIVersion1 || requested > LatestAPIVersion {
+				c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{
+					"error": i18n.T(c, fmt.Sprintf("API version %s is not supported; use 1 to %d", match[1], LatestAPIVersion)),
+				})
+				return
+			}
+			version = requested
+		}
+
+		c.Set(apiVersionKey, version)
+		c.Header(VersionHeader, strconv.Itoa(version))
+		c.Writer.Header().Add("Vary", "Accept")
+		c.Next()
+	}
+}
+
+// APIVersion is the version a request negotiated, or version 1 outside a
+// versioned group
+func APIVersion(c *gin.Context) int {
+	if version, ok := c.Get(apiVersionKey); ok {
+		return version.(int)
+	}
+	return APIVersion1
+}
+
+// BuildVersion stamps every response with the running build version
+func BuildVersion(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header(BuildVersionHeader, version)
+		c.Next()
+	}
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/dto"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/i18n"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+type ModerationHandler struct {
+	service ports.ModerationService
+	logger  *slog.Logger
+}
+
+func NewModerationHandler(service ports.ModerationService, logger *slog.Logger) *ModerationHandler {
+	return &ModerationHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+type RejectReviewRequest struct {
+	Reason string `json:"reason"`
+}
+
+// Queue lists the products held for manual review
+func (h *ModerationHandler) Queue(c *gin.Context) {
+	products, err := h.service.PendingReview(c.Request.Context())
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to list review queue", "error", err)
+		respondError(c, err)
+		return
+	}
+
+	response := make([]dto.AdminProductResponse, len(products))
+	for i, product := range products {
+		response[i] = dto.NewAdminProductResponse(product)
+	}
+	c.JSON(http.StatusOK, gin.H{"products": response})
+}
+
+// Approve releases a held product into listings
+func (h *ModerationHandler) Approve(c *gin.Context) {
+	id := c.Param("id")
+	product, err := h.service.Approve(c.Request.Context(), id)
+	h.respond(c, id, product, err)
+}
+
+// Reject keeps a held product out of listings until its owner edits it
+func (h *ModerationHandler) Reject(c *gin.Context) {
+	var req RejectReviewRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, err.Error())})
+			return
+		}
+	}
+
+	id := c.Param("id")
+	product, err := h.service.Reject(c.Request.Context(), id, req.Reason)
+	h.respond(c, id, product, err)
+}
+
+func (h *ModerationHandler) respond(c *gin.Context, id string, product domain.Product, err error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, err.Error())})
+		case errors.Is(err, domain.ErrNotPendingReview), errors.Is(err, domain.ErrConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": i18n.T(c, err.Error())})
+		default:
+			h.logger.ErrorContext(c.Request.Context(), "failed to resolve review", "id", id, "error", err)
+			respondError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewAdminProductResponse(product))
+}
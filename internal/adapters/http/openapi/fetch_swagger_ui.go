@@ -0,0 +1,117 @@
+//go:build ignore
+
+// fetch_swagger_ui downloads a Swagger UI release from the npm registry,
+// checks it against the registry's integrity hash and extracts the assets
+// the docs page uses into a directory, where openapi.go embeds them. Run it
+// with go generate, which pins the release.
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const registry = "https://registry.npmjs.org/swagger-ui-dist/"
+
+// assets are the files of the package the docs page loads
+var assets = []string{"swagger-ui.css", "swagger-ui-bundle.js"}
+
+func main() {
+	if len(os.Args) != 3 {
+		fmt.Fprintln(os.Stderr, "usage: go run fetch_swagger_ui.go <version> <dir>")
+		os.Exit(2)
+	}
+	if err := fetch(os.Args[1], os.Args[2]); err != nil {
+		fmt.Fprintln(os.Stderr, "fetch_swagger_ui:", err)
+		os.Exit(1)
+	}
+}
+
+func fetch(version, dir string) error {
+	client := &http.Client{Timeout: time.Minute}
+
+	var metadata struct {
+		Dist struct {
+			Tarball   string `json:"tarball"`
+			Integrity string `json:"integrity"`
+		} `json:"dist"`
+	}
+	body, err := get(client, registry+version)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, &metadata); err != nil {
+		return fmt.Errorf("invalid registry metadata: %w", err)
+	}
+	expected, ok := strings.CutPrefix(metadata.Dist.Integrity, "sha512-")
+	if !ok {
+		return fmt.Errorf("unsupported integrity %q", metadata.Dist.Integrity)
+	}
+
+	tarball, err := get(client, metadata.Dist.Tarball)
+	if err != nil {
+		return err
+	}
+	sum := sha512.Sum512(tarball)
+	if base64.StdEncoding.EncodeToString(sum[:]) != expected {
+		return errors.New("tarball does not match the registry's integrity hash")
+	}
+
+	archive, err := gzip.NewReader(bytes.NewReader(tarball))
+	if err != nil {
+		return err
+	}
+	reader := tar.NewReader(archive)
+	found := 0
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		name := strings.TrimPrefix(header.Name, "package/")
+		for _, asset := range assets {
+			if name != asset {
+				continue
+			}
+			content, err := io.ReadAll(reader)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(filepath.Join(dir, asset), content, 0o644); err != nil {
+				return err
+			}
+			found++
+		}
+	}
+	if found != len(assets) {
+		return fmt.Errorf("swagger-ui-dist %s lacks some of %v", version, assets)
+	}
+	return nil
+}
+
+func get(client *http.Client, url string) ([]byte, error) {
+	response, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, response.Status)
+	}
+	return io.ReadAll(response.Body)
+}
@@ -0,0 +1,116 @@
+// Package openapi embeds the hand-written OpenAPI description of the API and
+// serves it together with Swagger UI.
+package openapi
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/i18n"
+)
+
+//go:embed openapi.yaml
+var spec []byte
+
+// go generate fetches the assets of the pinned Swagger UI release into
+// swaggerui/, checked against the npm registry's integrity hash
+//go:generate go run fetch_swagger_ui.go 5.17.14 swaggerui
+
+// swaggerUIAssets holds the Swagger UI files served from the binary, so the
+// docs page loads no script from a third party
+//
+//go:embed swaggerui
+var swaggerUIAssets embed.FS
+
+// swaggerUIFiles maps the assets the docs page loads to their content type
+var swaggerUIFiles = map[string]string{
+	"swagger-ui.css":       "text/css; charset=utf-8",
+	"swagger-ui-bundle.js": "text/javascript; charset=utf-8",
+}
+
+// Load parses and validates the embedded specification
+func Load() (*openapi3.T, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI spec: %w", err)
+	}
+	return doc, nil
+}
+
+// Handler serves Swagger UI and the raw specification under a catch-all
+// route such as /swagger/*any. A build without the embedded assets serves a
+// page pointing at the raw specification instead of Swagger UI.
+func Handler() gin.HandlerFunc {
+	page := swaggerUI
+	if !swaggerUIBundled() {
+		page = swaggerUIMissing
+	}
+	return func(c *gin.Context) {
+		path := c.Param("any")
+		switch path {
+		case "/", "/index.html":
+			c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page))
+			return
+		case "/openapi.yaml":
+			c.Data(http.StatusOK, "application/yaml", spec)
+			return
+		}
+		name := path[1:]
+		if contentType, ok := swaggerUIFiles[name]; ok {
+			if asset, err := fs.ReadFile(swaggerUIAssets, "swaggerui/"+name); err == nil {
+				c.Data(http.StatusOK, contentType, asset)
+				return
+			}
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, "not found")})
+	}
+}
+
+// swaggerUIBundled reports whether go generate fetched the assets before
+// the build
+func swaggerUIBundled() bool {
+	for name := range swaggerUIFiles {
+		if _, err := fs.Stat(swaggerUIAssets, "swaggerui/"+name); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+var swaggerUI = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Product CRUD API</title>
+  <link rel="stylesheet" href="swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({url: "openapi.yaml", dom_id: "#swagger-ui"});
+  </script>
+</body>
+</html>
+`
+
+var swaggerUIMissing = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Product CRUD API</title>
+</head>
+<body>
+  <p>Swagger UI is not bundled in this build; run <code>go generate ./internal/adapters/http/openapi</code> before building.</p>
+  <p>The API is described by <a href="openapi.yaml">openapi.yaml</a>.</p>
+</body>
+</html>
+`
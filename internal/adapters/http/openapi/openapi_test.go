@@ -0,0 +1,116 @@
+package openapi
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/middleware"
+)
+
+func TestLoad(t *testing.T) {
+	doc, err := Load()
+	require.NoError(t, err)
+	assert.NotNil(t, doc.Paths.Find("/api/v1/products/{id}"))
+}
+
+func TestHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/swagger/*any", Handler())
+
+	for path, contentType := range map[string]string{
+		"/swagger/":             "text/html; charset=utf-8",
+		"/swagger/openapi.yaml": "application/yaml",
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusOK, w.Code, path)
+		assert.Equal(t, contentType, w.Header().Get("Content-Type"), path)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/swagger/missing", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// The page loads nothing from outside the binary
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/swagger/", nil))
+	assert.NotContains(t, w.Body.String(), "https://")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/swagger/swagger-ui-bundle.js", nil))
+	if swaggerUIBundled() {
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/javascript; charset=utf-8", w.Header().Get("Content-Type"))
+	} else {
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	}
+}
+
+func TestValidateRequests(t *testing.T) {
+	doc, err := Load()
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	validate, err := middleware.ValidateRequests(doc, middleware.NewValidationMode(middleware.ValidationEnforce), logger)
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(validate)
+	ok := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+	router.GET("/api/v1/products", ok)
+	// The body must still be readable after validation
+	router.POST("/api/v1/products", func(c *gin.Context) {
+		var body map[string]interface{}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+	router.PUT("/api/v1/products/:id", ok)
+	router.GET("/health", ok)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		field  string
+	}{
+		{"valid create", http.MethodPost, "/api/v1/products", `{"name":"Laptop","price":10}`, http.StatusNoContent, ""},
+		{"missing name", http.MethodPost, "/api/v1/products", `{"price":10}`, http.StatusBadRequest, "name"},
+		{"zero price", http.MethodPost, "/api/v1/products", `{"name":"Laptop","price":0}`, http.StatusBadRequest, "price"},
+		{"price with currency", http.MethodPost, "/api/v1/products", `{"name":"Laptop","price":{"amount":1000,"currency":"EUR"}}`, http.StatusNoContent, ""},
+		{"price without amount", http.MethodPost, "/api/v1/products", `{"name":"Laptop","price":{"currency":"EUR"}}`, http.StatusBadRequest, "price"},
+		{"valid update", http.MethodPut, "/api/v1/products/prod-1", `{"name":"Laptop","price":10,"version":3}`, http.StatusNoContent, ""},
+		{"valid listing", http.MethodGet, "/api/v1/products?limit=50&sort_by=price&sort_order=asc", "", http.StatusNoContent, ""},
+		{"limit too large", http.MethodGet, "/api/v1/products?limit=500", "", http.StatusBadRequest, "limit"},
+		{"unknown sort field", http.MethodGet, "/api/v1/products?sort_by=color", "", http.StatusBadRequest, "sort_by"},
+		{"route outside the spec", http.MethodGet, "/health", "", http.StatusNoContent, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code, w.Body.String())
+			if tt.field != "" {
+				assert.Contains(t, w.Body.String(), `"field":"`+tt.field+`"`)
+			}
+		})
+	}
+}
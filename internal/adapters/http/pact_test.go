@@ -0,0 +1,188 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/analytics"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/middleware"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/ids"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/moderation"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports/repotest"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/services"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/cursor"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/pact"
+)
+
+// memoryTombstones keeps tombstones in memory
+type memoryTombstones struct {
+	mu         sync.Mutex
+	tombstones map[string]domain.Tombstone
+}
+
+func (m *memoryTombstones) Save(ctx context.Context, tombstone domain.Tombstone) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tombstones[tombstone.ID] = tombstone
+	return nil
+}
+
+func (m *memoryTombstones) Get(ctx context.Context, id string) (domain.Tombstone, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tombstone, ok := m.tombstones[id]
+	if !ok {
+		return domain.Tombstone{}, domain.ErrNotFound
+	}
+	return tombstone, nil
+}
+
+func (m *memoryTombstones) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.tombstones, id)
+	return nil
+}
+
+type discardAuditLog struct{}
+
+func (discardAuditLog) Record(ctx context.Context, entry domain.AuditEntry) error {
+	return nil
+}
+
+func (discardAuditLog) History(ctx context.Context, productID string, limit int) ([]domain.AuditEntry, error) {
+	return nil, nil
+}
+
+type discardSearchTerms struct{}
+
+func (discardSearchTerms) RecordSearch(ctx context.Context, term string, results int64) {}
+
+func (discardSearchTerms) Report(ctx context.Context, query ports.SearchTermQuery) ([]domain.SearchTermStats, error) {
+	return nil, nil
+}
+
+// pactProvider serves the product API from the real handler and service
+// over an in-memory repository, which each interaction starts afresh
+type pactProvider struct {
+	repo   *repotest.MemoryRepository
+	router *gin.Engine
+}
+
+func newPactProvider(t *testing.T) *pactProvider {
+	gin.SetMode(gin.TestMode)
+	p := &pactProvider{}
+	p.reset(t)
+	return p
+}
+
+func (p *pactProvider) reset(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	generator, err := ids.New(ids.UUIDv4, "")
+	require.NoError(t, err)
+	cursors, err := cursor.NewCodec("test-secret", time.Minute)
+	require.NoError(t, err)
+
+	p.repo = repotest.NewMemoryRepository()
+	service := services.NewProductService(p.repo, &memoryTombstones{tombstones: map[string]domain.Tombstone{}},
+		moderation.NewWordlistModerator(nil, nil), analytics.NewNoopPublisher(), nil, discardSearchTerms{},
+		nil, nil, nil, discardAuditLog{}, generator, domain.ProductIDFormat{}, logger)
+	handler := NewProductHandler(service, stubCurrencyService{"EUR": 0.5}, cursors, false, "en", logger)
+
+	p.router = gin.New()
+	products := p.router.Group("/api/v1", middleware.IdentifyAdmin(testAdminKey)).Group("/products")
+	{
+		products.GET("", handler.List)
+		products.POST("", handler.Create)
+		products.GET("/:id", handler.Get)
+		products.PUT("/:id", handler.Update)
+		products.DELETE("/:id", handler.Delete)
+	}
+}
+
+// productExists saves the product the consumer names, defaulting whatever
+// it leaves out
+func (p *pactProvider) productExists(params map[string]any) error {
+	product := domain.Product{
+		ID:        "3f2c8a4e-6b1d-4c57-9a0e-2d7b5f8c1e90",
+		Name:      "Contract product",
+		Price:     domain.Money{Amount: 1999, Currency: "USD"},
+		Status:    domain.StatusPublished,
+		CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Version:   1,
+	}
+	product.UpdatedAt = product.CreatedAt
+	for key, value := range params {
+		switch key {
+		case "id":
+			product.ID = fmt.Sprint(value)
+		case "name":
+			product.Name = fmt.Sprint(value)
+		case "description":
+			product.Description = fmt.Sprint(value)
+		case "price":
+			amount, ok := value.(float64)
+			if !ok {
+				return fmt.Errorf("price must be a number of minor units, got %v", value)
+			}
+			product.Price.Amount = int64(amount)
+		case "currency":
+			product.Price.Currency = fmt.Sprint(value)
+		default:
+			return fmt.Errorf("unknown parameter %q", key)
+		}
+	}
+	return p.repo.Save(context.Background(), product)
+}
+
+// pactSources lists where the pacts to verify are, from PACT_URLS when a
+// CI job passes the ones a broker published
+func pactSources() []string {
+	if urls := os.Getenv("PACT_URLS"); urls != "" {
+		return strings.Split(urls, ",")
+	}
+	return []string{"testdata/pacts"}
+}
+
+func TestPactProvider(t *testing.T) {
+	provider := newPactProvider(t)
+	verifier := pact.Verifier{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			provider.router.ServeHTTP(w, r)
+		}),
+		States: map[string]pact.StateHandler{
+			"product exists": provider.productExists,
+			// The fresh repository has no products
+			"product missing": func(map[string]any) error { return nil },
+		},
+		Reset: func() { provider.reset(t) },
+	}
+
+	for _, source := range pactSources() {
+		files, err := pact.Load(strings.TrimSpace(source))
+		require.NoError(t, err)
+		for _, file := range files {
+			t.Run(file.Consumer.Name, func(t *testing.T) {
+				for _, interaction := range file.Interactions {
+					t.Run(interaction.Description, func(t *testing.T) {
+						if err := verifier.Verify(interaction); err != nil {
+							t.Error(err)
+						}
+					})
+				}
+			})
+		}
+	}
+}
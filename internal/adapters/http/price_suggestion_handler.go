@@ -0,0 +1,32 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+type PriceSuggestionHandler struct {
+	service ports.PriceSuggestionService
+	logger  *slog.Logger
+}
+
+func NewPriceSuggestionHandler(service ports.PriceSuggestionService, logger *slog.Logger) *PriceSuggestionHandler {
+	return &PriceSuggestionHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Suggest returns the price the pricing service recommends for a product.
+// An unreachable service answers 503, like an unavailable table.
+func (h *PriceSuggestionHandler) Suggest(c *gin.Context) {
+	suggestion, err := h.service.Suggest(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, suggestion)
+}
@@ -1,81 +1,371 @@
 package http
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"math"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/dto"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/i18n"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/middleware"
 	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
 	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/cursor"
+	"golang.org/x/text/language"
 	"log/slog"
 )
 
+const (
+	errCostPriceForbidden  = "cost_price can only be set by admins"
+	errPreconditionMissing = "updates require an If-Match header with the product's ETag"
+	errPreconditionFailed  = "product does not match If-Match"
+	errInvalidIfMatch      = "If-Match must be the product's ETag or *"
+	errInvalidIfNoneMatch  = "If-None-Match on PUT must be * and cannot be combined with If-Match"
+	errProductExists       = "product already exists"
+	errIDMismatch          = "id in the body does not match the URL"
+	// totalCountHeader carries how many products match a listing's filters
+	totalCountHeader = "X-Total-Count"
+)
+
 type ProductHandler struct {
-	service ports.ProductService
-	logger  *slog.Logger
+	service    ports.ProductService
+	currencies ports.CurrencyService
+	cursors    *cursor.Codec
+	// upsert makes a PUT without preconditions create a missing product
+	upsert bool
+	// defaultLocale is the language products' own names and descriptions
+	// are written in
+	defaultLocale language.Tag
+	logger        *slog.Logger
 }
 
-func NewProductHandler(service ports.ProductService, logger *slog.Logger) *ProductHandler {
+func NewProductHandler(service ports.ProductService, currencies ports.CurrencyService, cursors *cursor.Codec, upsert bool, defaultLocale string, logger *slog.Logger) *ProductHandler {
 	return &ProductHandler{
-		service: service,
-		logger:  logger,
+		service:       service,
+		currencies:    currencies,
+		cursors:       cursors,
+		upsert:        upsert,
+		defaultLocale: language.Make(defaultLocale),
+		logger:        logger,
 	}
 }
 
 type CreateProductRequest struct {
-	Name        string  `json:"name" binding:"required"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price" binding:"required,gt=0"`
+	// ID names the new product instead of a generated UUID; creating a
+	// product with an existing ID fails with 409. Ignored on update.
+	ID          string `json:"id"`
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	// Price is {"amount": <minor units>, "currency": "<ISO 4217>"}; a bare
+	// number is read as major units of domain.DefaultCurrency
+	Price     domain.Money `json:"price" binding:"price"`
+	ExpiresAt *time.Time   `json:"expires_at"`
+	PublishAt *time.Time   `json:"publish_at"`
+	// AutoArchiveAt archives seasonal products automatically
+	AutoArchiveAt *time.Time `json:"auto_archive_at"`
+	// CostPrice is in major units of the price's currency and only
+	// accepted from admins
+	CostPrice *domain.Decimal `json:"cost_price" binding:"omitempty,nonnegative"`
+	// Version guards updates against overwriting a newer write
+	Version *int64 `json:"version" binding:"omitempty,min=0"`
+	// CategoryID must name an existing category; omitting it on update
+	// removes the product from its category
+	CategoryID string `json:"category_id"`
+	// Tags replace the product's tags; omitting them on update removes them
+	Tags []string `json:"tags"`
+	// SKU and Barcode must not be held by another product; omitting them on
+	// update removes them
+	SKU     string `json:"sku"`
+	Barcode string `json:"barcode"`
+}
+
+func (r CreateProductRequest) toInput() ports.ProductInput {
+	return ports.ProductInput{
+		ID:            r.ID,
+		Name:          r.Name,
+		Description:   r.Description,
+		Price:         r.Price,
+		ExpiresAt:     r.ExpiresAt,
+		PublishAt:     r.PublishAt,
+		AutoArchiveAt: r.AutoArchiveAt,
+		CostPrice:     r.CostPrice,
+		Version:       r.Version,
+		CategoryID:    r.CategoryID,
+		Tags:          r.Tags,
+		SKU:           r.SKU,
+		Barcode:       r.Barcode,
+	}
 }
 
 func (h *ProductHandler) Create(c *gin.Context) {
 	var req CreateProductRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Warn("invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.WarnContext(c.Request.Context(), "invalid request body", "error", err)
+		respondBindingError(c, "invalid request body", err)
+		return
+	}
+
+	if req.CostPrice != nil && !middleware.IsAdmin(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": i18n.T(c, errCostPriceForbidden)})
+		return
+	}
+
+	product, err := h.service.Create(c.Request.Context(), req.toInput())
+	if err != nil {
+		h.respondCreateError(c, err)
+		return
+	}
+
+	c.Header("ETag", productETag(c, product, ""))
+	c.JSON(http.StatusCreated, h.productBody(c, product))
+}
+
+// createAt answers a PUT that creates the product at the URL's ID. The
+// write is conditional, so a product created in the meantime is never
+// replaced: createOnly requests get 412 and unconditional ones 428, as any
+// update of an existing product would.
+func (h *ProductHandler) createAt(c *gin.Context, id string, req CreateProductRequest, createOnly bool) {
+	if req.ID != "" && req.ID != id {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, errIDMismatch)})
 		return
 	}
+	input := req.toInput()
+	input.ID = id
+	input.Version = nil
 
-	product, err := h.service.Create(c.Request.Context(), req.Name, req.Description, req.Price)
+	product, err := h.service.Create(c.Request.Context(), input)
 	if err != nil {
-		if err == domain.ErrInvalidProduct {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		var duplicate *domain.DuplicateError
+		if errors.As(err, &duplicate) && duplicate.Field == domain.FieldID {
+			if createOnly {
+				c.JSON(http.StatusPreconditionFailed, gin.H{"error": i18n.T(c, errProductExists)})
+			} else {
+				c.JSON(http.StatusPreconditionRequired, gin.H{"error": i18n.T(c, errPreconditionMissing)})
+			}
 			return
 		}
-		h.logger.Error("failed to create product", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		h.respondCreateError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, product)
+	c.Header("ETag", productETag(c, product, ""))
+	c.JSON(http.StatusCreated, h.productBody(c, product))
+}
+
+// CloneProductRequest changes the copy of a product; fields left out keep
+// the original's value. The SKU, barcode and schedule are not copied.
+type CloneProductRequest struct {
+	// ID names the copy instead of a generated UUID
+	ID string `json:"id"`
+	// NameSuffix is appended to the name, such as " (copy)"
+	NameSuffix    string          `json:"name_suffix"`
+	Name          *string         `json:"name" binding:"omitempty,min=1"`
+	Description   *string         `json:"description"`
+	Price         *domain.Money   `json:"price" binding:"omitempty,price"`
+	CategoryID    *string         `json:"category_id"`
+	Tags          []string        `json:"tags"`
+	CostPrice     *domain.Decimal `json:"cost_price" binding:"omitempty,nonnegative"`
+	ExpiresAt     *time.Time      `json:"expires_at"`
+	PublishAt     *time.Time      `json:"publish_at"`
+	AutoArchiveAt *time.Time      `json:"auto_archive_at"`
+	SKU           string          `json:"sku"`
+	Barcode       string          `json:"barcode"`
+}
+
+// Clone creates a product from an existing one. The body is optional; an
+// empty one copies the product as it is.
+func (h *ProductHandler) Clone(c *gin.Context) {
+	var req CloneProductRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.logger.WarnContext(c.Request.Context(), "invalid request body", "error", err)
+			respondBindingError(c, "invalid request body", err)
+			return
+		}
+	}
+	if req.CostPrice != nil && !middleware.IsAdmin(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": i18n.T(c, errCostPriceForbidden)})
+		return
+	}
+
+	product, err := h.service.Clone(c.Request.Context(), c.Param("id"), ports.CloneInput{
+		ID:            req.ID,
+		NameSuffix:    req.NameSuffix,
+		Name:          req.Name,
+		Description:   req.Description,
+		Price:         req.Price,
+		CategoryID:    req.CategoryID,
+		Tags:          req.Tags,
+		CostPrice:     req.CostPrice,
+		ExpiresAt:     req.ExpiresAt,
+		PublishAt:     req.PublishAt,
+		AutoArchiveAt: req.AutoArchiveAt,
+		SKU:           req.SKU,
+		Barcode:       req.Barcode,
+	})
+	if err != nil {
+		h.respondCreateError(c, err)
+		return
+	}
+
+	c.Header("ETag", productETag(c, product, ""))
+	c.JSON(http.StatusCreated, h.productBody(c, product))
+}
+
+func (h *ProductHandler) respondCreateError(c *gin.Context, err error) {
+	if errors.Is(err, domain.ErrInvalidProduct) || errors.Is(err, domain.ErrUnknownCategory) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, err.Error())})
+		return
+	}
+	if respondRejected(c, err) || respondDuplicate(c, err) {
+		return
+	}
+	h.logger.ErrorContext(c.Request.Context(), "failed to create product", "error", err)
+	respondError(c, err)
 }
 
 func (h *ProductHandler) Get(c *gin.Context) {
 	id := c.Param("id")
-	product, err := h.service.Get(c.Request.Context(), id)
+	product, err := h.service.Get(h.readContext(c), id)
+	if err != nil {
+		var tombstone *domain.TombstoneError
+		if errors.As(err, &tombstone) {
+			h.respondTombstone(c, tombstone)
+			return
+		}
+		if errors.Is(err, domain.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, err.Error())})
+			return
+		}
+		h.logger.ErrorContext(c.Request.Context(), "failed to get product", "id", id, "error", err)
+		respondError(c, err)
+		return
+	}
+	if !visible(c, product) {
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, domain.ErrNotFound.Error())})
+		return
+	}
+
+	product, locale := h.localize(c, product)
+	etag := productETag(c, product, locale)
+	c.Header("ETag", etag)
+	if match := c.GetHeader("If-None-Match"); match != "" && etagListMatches(match, etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Header("Content-Language", locale)
+	if respondEncoded(c, http.StatusOK, []domain.Product{product}, false) {
+		h.service.RecordView(c.Request.Context(), id)
+		return
+	}
+
+	displayPrice, ok := h.displayPrice(c, product.Price)
+	if !ok {
+		return
+	}
+	h.service.RecordView(c.Request.Context(), id)
+	if displayPrice == nil {
+		c.JSON(http.StatusOK, h.productBody(c, product))
+		return
+	}
+	if middleware.APIVersion(c) >= middleware.APIVersion2 {
+		response := dto.NewProductV2(product, middleware.IsAdmin(c))
+		response.DisplayPrice = displayPrice
+		c.JSON(http.StatusOK, dto.ProductEnvelopeV2{Data: response})
+		return
+	}
+	if middleware.IsAdmin(c) {
+		response := dto.NewAdminProductResponse(product)
+		response.DisplayPrice = displayPrice
+		c.JSON(http.StatusOK, response)
+		return
+	}
+	response := dto.NewProductResponse(product)
+	response.DisplayPrice = displayPrice
+	c.JSON(http.StatusOK, response)
+}
+
+// GetBySKU serves the product holding an SKU
+func (h *ProductHandler) GetBySKU(c *gin.Context) {
+	sku := c.Param("sku")
+	product, err := h.service.GetBySKU(h.readContext(c), sku)
 	if err != nil {
-		if err == domain.ErrNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		if errors.Is(err, domain.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, err.Error())})
 			return
 		}
-		h.logger.Error("failed to get product", "id", id, "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		h.logger.ErrorContext(c.Request.Context(), "failed to get product by sku", "sku", sku, "error", err)
+		respondError(c, err)
+		return
+	}
+	if !visible(c, product) {
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, domain.ErrNotFound.Error())})
 		return
 	}
 
-	c.JSON(http.StatusOK, product)
+	product, locale := h.localize(c, product)
+	c.Header("ETag", productETag(c, product, locale))
+	c.Header("Content-Language", locale)
+	if respondEncoded(c, http.StatusOK, []domain.Product{product}, false) {
+		return
+	}
+	c.JSON(http.StatusOK, h.productBody(c, product))
 }
 
-func (h *ProductHandler) List(c *gin.Context) {
+// BatchGet reads up to 100 products by ID in one request. IDs that do not
+// exist are listed under missing rather than failing the request.
+func (h *ProductHandler) BatchGet(c *gin.Context) {
+	var req dto.BatchGetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WarnContext(c.Request.Context(), "invalid request body", "error", err)
+		respondBindingError(c, "invalid request body", err)
+		return
+	}
+
+	products, missing, err := h.service.GetMany(h.readContext(c), req.IDs)
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to get products", "error", err)
+		respondError(c, err)
+		return
+	}
+	shown := products[:0]
+	for _, product := range products {
+		if visible(c, product) {
+			shown = append(shown, product)
+		} else {
+			missing = append(missing, product.ID)
+		}
+	}
+	products = shown
+
+	h.localizeAll(c, products)
+	response := dto.BatchGetResponse{Products: make([]dto.ProductResponse, len(products)), Missing: missing}
+	for i, product := range products {
+		response.Products[i] = dto.NewProductResponse(product)
+		displayPrice, ok := h.displayPrice(c, product.Price)
+		if !ok {
+			return
+		}
+		response.Products[i].DisplayPrice = displayPrice
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// bindListRequest binds the listing query parameters with their defaults,
+// answering 400 itself when they are invalid
+func (h *ProductHandler) bindListRequest(c *gin.Context) (dto.ListProductsRequest, bool) {
 	var req dto.ListProductsRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		h.logger.Warn("invalid query parameters", "error", err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "invalid query parameters",
-			"details": err.Error(),
-		})
-		return
+		h.logger.WarnContext(c.Request.Context(), "invalid query parameters", "error", err)
+		respondBindingError(c, "invalid query parameters", err)
+		return req, false
 	}
 
 	// Set defaults
@@ -83,36 +373,175 @@ func (h *ProductHandler) List(c *gin.Context) {
 
 	// Additional validations
 	if req.Page > 1000 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "page cannot exceed 1000"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "page cannot exceed 1000")})
+		return req, false
+	}
+
+	if !req.MinPrice.IsZero() && !req.MaxPrice.IsZero() && req.MinPrice.Cmp(req.MaxPrice) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "min_price cannot be greater than max_price")})
+		return req, false
+	}
+
+	// Only listings of published products are public
+	if req.Status != "" && req.Status != domain.StatusPublished && !middleware.IsAdmin(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": i18n.T(c, fmt.Sprintf("only admins may list %s products", req.Status))})
+		return req, false
+	}
+
+	if len(req.TagList()) > domain.MaxProductTags {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, fmt.Sprintf("tags cannot list more than %d tags", domain.MaxProductTags))})
+		return req, false
+	}
+
+	times, err := req.TimeRange()
+	var paramErr *dto.TimeParamError
+	if errors.As(err, &paramErr) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  i18n.T(c, "invalid query parameters"),
+			"fields": []FieldError{{Field: paramErr.Param, Rule: "format", Message: i18n.T(c, err.Error())}},
+		})
+		return req, false
+	}
+	if invertedRange(times.CreatedAfter, times.CreatedBefore) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "created_after cannot be later than created_before")})
+		return req, false
+	}
+	if invertedRange(times.UpdatedAfter, times.UpdatedBefore) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "updated_after cannot be later than updated_before")})
+		return req, false
+	}
+	return req, true
+}
+
+// invertedRange is true when both bounds are set and after is later than
+// before
+func invertedRange(after, before time.Time) bool {
+	return !after.IsZero() && !before.IsZero() && after.After(before)
+}
+
+// listFilters are the filters of a listing request, without its
+// pagination and sorting. The request has been through bindListRequest,
+// so its time range parses.
+func listFilters(req dto.ListProductsRequest) ports.ProductFilters {
+	times, _ := req.TimeRange()
+	return ports.ProductFilters{
+		Name:          req.Name,
+		MinPrice:      req.MinPrice,
+		MaxPrice:      req.MaxPrice,
+		Currency:      strings.ToUpper(req.PriceCurrency),
+		CategoryID:    req.CategoryID,
+		Tags:          req.TagList(),
+		TagMatch:      req.TagsMatch,
+		Status:        req.Status,
+		CreatedAfter:  times.CreatedAfter,
+		CreatedBefore: times.CreatedBefore,
+		UpdatedAfter:  times.UpdatedAfter,
+		UpdatedBefore: times.UpdatedBefore,
+	}
+}
+
+func (h *ProductHandler) List(c *gin.Context) {
+	req, ok := h.bindListRequest(c)
+	if !ok {
 		return
 	}
 
-	if req.MinPrice > 0 && req.MaxPrice > 0 && req.MinPrice > req.MaxPrice {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "min_price cannot be greater than max_price"})
+	fields, err := req.FieldList()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  i18n.T(c, "invalid query parameters"),
+			"fields": []FieldError{{Field: "fields", Rule: "oneof", Message: i18n.T(c, err.Error())}},
+		})
+		return
+	}
+	thenBy, err := req.ThenByList()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  i18n.T(c, "invalid query parameters"),
+			"fields": []FieldError{{Field: "then_by", Rule: "oneof", Message: i18n.T(c, err.Error())}},
+		})
 		return
 	}
 
 	// Build filters for service
-	filters := ports.ProductFilters{
-		Name:      req.Name,
-		MinPrice:  req.MinPrice,
-		MaxPrice:  req.MaxPrice,
-		SortBy:    req.SortBy,
-		SortOrder: req.SortOrder,
-		Offset:    req.GetOffset(),
-		Limit:     req.Limit,
+	filters := listFilters(req)
+	filters.SortBy = req.SortBy
+	filters.ThenBy = thenBy
+	filters.SortOrder = req.SortOrder
+	filters.Page = req.Page
+	filters.Offset = req.GetOffset()
+	filters.Limit = req.Limit
+	filters.Explain = req.Explain
+	filters.Fields = fields
+	// A display price is converted from the stored price, and a localized
+	// name or description is picked from the translations
+	if len(fields) > 0 && c.Query("currency") != "" {
+		filters.Fields = append(slices.Clone(filters.Fields), "price")
+	}
+	if len(fields) > 0 && c.GetHeader("Accept-Language") != "" &&
+		(slices.Contains(fields, "name") || slices.Contains(fields, "description")) {
+		filters.Fields = append(slices.Clone(filters.Fields), "translations")
 	}
 
-	result, err := h.service.ListWithFilters(c.Request.Context(), filters)
+	// A cursor replaces page-based offsets
+	filterHash := req.FilterHash()
+	if req.Cursor != "" {
+		startKey, err := h.cursors.Decode(req.Cursor, filterHash)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, err.Error())})
+			return
+		}
+		filters.StartKey = startKey
+		filters.Offset = 0
+	}
+
+	// A keyset position continues after the last product already seen
+	if req.AfterID != "" || req.AfterValue != "" {
+		if req.AfterID == "" || req.AfterValue == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "after_id and after_value must be given together")})
+			return
+		}
+		if req.Cursor != "" || req.Page > 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "after_id and after_value cannot be combined with cursor or page")})
+			return
+		}
+		// A position holds the sort_by value alone
+		if len(thenBy) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "then_by cannot be combined with after_id and after_value")})
+			return
+		}
+		after, err := ports.ParseSortKey(req.SortBy, req.AfterValue, req.AfterID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "invalid after_value: "+err.Error())})
+			return
+		}
+		filters.After = &after
+		filters.Offset = 0
+	}
+
+	result, err := h.service.ListWithFilters(h.readContext(c), filters)
 	if err != nil {
-		h.logger.Error("failed to list products with filters", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		if errors.Is(err, domain.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, err.Error())})
+			return
+		}
+		h.logger.ErrorContext(c.Request.Context(), "failed to list products with filters", "error", err)
+		respondError(c, err)
+		return
+	}
+
+	c.Header(totalCountHeader, strconv.Itoa(result.TotalItems))
+	h.localizeAll(c, result.Products)
+	// CSV and XML carry no pagination; clients page with page and limit
+	// against X-Total-Count
+	if respondEncoded(c, http.StatusOK, result.Products, true) {
 		return
 	}
 
 	// Build response
 	response := dto.ListProductsResponse{
 		Products: make([]dto.ProductResponse, len(result.Products)),
+		Fields:   fields,
 		Pagination: dto.PaginationInfo{
 			CurrentPage: req.Page,
 			PerPage:     req.Limit,
@@ -123,64 +552,446 @@ func (h *ProductHandler) List(c *gin.Context) {
 		},
 	}
 
+	if result.NextKey != nil {
+		nextCursor, err := h.cursors.Encode(result.NextKey, filterHash)
+		if err != nil {
+			h.logger.ErrorContext(c.Request.Context(), "failed to encode cursor", "error", err)
+			respondError(c, err)
+			return
+		}
+		response.Pagination.NextCursor = nextCursor
+		response.Pagination.HasNext = true
+	}
+
+	if filters.After != nil {
+		response.Pagination.HasPrev = true
+		response.Pagination.HasNext = len(result.Products) == req.Limit
+	}
+	if len(thenBy) == 0 && len(result.Products) > 0 && len(result.Products) == req.Limit {
+		next := ports.ProductSortKey(result.Products[len(result.Products)-1], req.SortBy)
+		response.Pagination.NextAfterID = next.ID
+		response.Pagination.NextAfterValue = next.Value
+	}
+
 	// Convert domain products to DTOs
 	for i, product := range result.Products {
-		response.Products[i] = dto.NewProductResponse(
-			product.ID,
-			product.Name,
-			product.Description,
-			product.Price,
-			product.CreatedAt,
-			product.UpdatedAt,
-		)
+		response.Products[i] = dto.NewProductResponse(product)
+		displayPrice, ok := h.displayPrice(c, product.Price)
+		if !ok {
+			return
+		}
+		response.Products[i].DisplayPrice = displayPrice
 	}
 
 	// Add filter info if filters were applied
 	if req.HasFilters() {
 		response.FiltersApplied = dto.FilterInfo{
-			Name:     req.Name,
-			MinPrice: req.MinPrice,
-			MaxPrice: req.MaxPrice,
+			Name:          req.Name,
+			MinPrice:      nonZero(req.MinPrice),
+			MaxPrice:      nonZero(req.MaxPrice),
+			PriceCurrency: filters.PriceCurrency(),
+			CategoryID:    req.CategoryID,
+			Tags:          req.TagList(),
+			TagsMatch:     req.TagsMatch,
+			Status:        req.Status,
+		}
+	}
+
+	if result.Plan != nil {
+		response.Explain = &dto.ExplainInfo{
+			Operation:    result.Plan.Operation,
+			Index:        result.Plan.Index,
+			Partitions:   result.Plan.Partitions,
+			SortInMemory: result.Plan.SortInMemory,
+			Reason:       result.Plan.Reason,
 		}
 	}
 
+	if middleware.APIVersion(c) >= middleware.APIVersion2 {
+		c.JSON(http.StatusOK, dto.NewListProductsResponseV2(result.Products, response, req.HasFilters()))
+		return
+	}
 	c.JSON(http.StatusOK, response)
 }
 
+// Count answers how many products match the listing filters without
+// reading them. On HEAD requests the count is only sent in the
+// X-Total-Count header.
+func (h *ProductHandler) Count(c *gin.Context) {
+	req, ok := h.bindListRequest(c)
+	if !ok {
+		return
+	}
+
+	count, err := h.service.Count(h.readContext(c), listFilters(req))
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to count products", "error", err)
+		respondError(c, err)
+		return
+	}
+
+	c.Header(totalCountHeader, strconv.Itoa(count))
+	if c.Request.Method == http.MethodHead {
+		c.Status(http.StatusOK)
+		return
+	}
+	c.JSON(http.StatusOK, dto.CountResponse{Count: count})
+}
+
 func (h *ProductHandler) Update(c *gin.Context) {
 	id := c.Param("id")
 	var req CreateProductRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Warn("invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.WarnContext(c.Request.Context(), "invalid request body", "error", err)
+		respondBindingError(c, "invalid request body", err)
+		return
+	}
+
+	if req.CostPrice != nil && !middleware.IsAdmin(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": i18n.T(c, errCostPriceForbidden)})
+		return
+	}
+
+	// If-None-Match: * creates the product and fails if it exists. With
+	// upserts enabled a PUT without preconditions creates a missing product
+	ifMatch := c.GetHeader("If-Match")
+	if ifNoneMatch := c.GetHeader("If-None-Match"); ifNoneMatch != "" {
+		if strings.TrimSpace(ifNoneMatch) != "*" || ifMatch != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, errInvalidIfNoneMatch)})
+			return
+		}
+		h.createAt(c, id, req, true)
+		return
+	}
+	if h.upsert && ifMatch == "" && req.Version == nil {
+		h.createAt(c, id, req, false)
 		return
 	}
 
-	product, err := h.service.Update(c.Request.Context(), id, req.Name, req.Description, req.Price)
+	// Updates must name the state they were based on: either an If-Match
+	// ETag or, for older clients, a version in the body
+	input := req.toInput()
+	switch {
+	case ifMatch == "" && req.Version == nil:
+		c.JSON(http.StatusPreconditionRequired, gin.H{"error": i18n.T(c, errPreconditionMissing)})
+		return
+	case ifMatch != "":
+		version, ok := h.ifMatchVersion(c, id, ifMatch)
+		if !ok {
+			return
+		}
+		if version != nil && req.Version != nil && *req.Version != *version {
+			c.JSON(http.StatusPreconditionFailed, gin.H{"error": i18n.T(c, errPreconditionFailed)})
+			return
+		}
+		if version != nil {
+			input.Version = version
+		}
+	}
+
+	product, err := h.service.Update(c.Request.Context(), id, input)
 	if err != nil {
-		if err == domain.ErrNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		if errors.Is(err, domain.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, err.Error())})
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidProduct) || errors.Is(err, domain.ErrUnknownCategory) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, err.Error())})
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidTransition) {
+			c.JSON(http.StatusConflict, gin.H{"error": i18n.T(c, err.Error())})
+			return
+		}
+		if errors.Is(err, domain.ErrConflict) && ifMatch != "" {
+			c.JSON(http.StatusPreconditionFailed, gin.H{"error": i18n.T(c, errPreconditionFailed)})
+			return
+		}
+		if errors.Is(err, domain.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": i18n.T(c, domain.ErrConflict.Error())})
+			return
+		}
+		if respondRejected(c, err) || respondDuplicate(c, err) {
 			return
 		}
-		h.logger.Error("failed to update product", "id", id, "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		h.logger.ErrorContext(c.Request.Context(), "failed to update product", "id", id, "error", err)
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, product)
+	c.Header("ETag", productETag(c, product, ""))
+	c.JSON(http.StatusOK, h.productBody(c, product))
+}
+
+// TranslationRequest is a product's name and description in one locale
+type TranslationRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
 }
 
+// PutTranslation adds or replaces the product's name and description in
+// the locale of the URL. An If-Match ETag makes the write conditional.
+func (h *ProductHandler) PutTranslation(c *gin.Context) {
+	id, locale := c.Param("id"), c.Param("locale")
+	var req TranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WarnContext(c.Request.Context(), "invalid request body", "error", err)
+		respondBindingError(c, "invalid request body", err)
+		return
+	}
+
+	var version *int64
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch != "" {
+		var ok bool
+		if version, ok = h.ifMatchVersion(c, id, ifMatch); !ok {
+			return
+		}
+	}
+
+	translation := domain.Translation{Name: req.Name, Description: req.Description}
+	product, err := h.service.SetTranslation(c.Request.Context(), id, locale, translation, version)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, err.Error())})
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidLocale) || errors.Is(err, domain.ErrInvalidProduct) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, err.Error())})
+			return
+		}
+		if errors.Is(err, domain.ErrConflict) && ifMatch != "" {
+			c.JSON(http.StatusPreconditionFailed, gin.H{"error": i18n.T(c, errPreconditionFailed)})
+			return
+		}
+		if errors.Is(err, domain.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": i18n.T(c, domain.ErrConflict.Error())})
+			return
+		}
+		if respondRejected(c, err) {
+			return
+		}
+		h.logger.ErrorContext(c.Request.Context(), "failed to save product translation", "id", id, "locale", locale, "error", err)
+		respondError(c, err)
+		return
+	}
+
+	c.Header("ETag", productETag(c, product, ""))
+	c.JSON(http.StatusOK, h.productBody(c, product))
+}
+
+// Delete removes a product. With If-Match it only does so while the
+// product is unchanged since the client read it, answering 412 otherwise.
 func (h *ProductHandler) Delete(c *gin.Context) {
 	id := c.Param("id")
-	if err := h.service.Delete(c.Request.Context(), id); err != nil {
-		if err == domain.ErrNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	var version *int64
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch != "" {
+		var ok bool
+		if version, ok = h.ifMatchVersion(c, id, ifMatch); !ok {
+			return
+		}
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id, c.Query("replaced_by"), version); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, err.Error())})
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidReplacement) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, err.Error())})
+			return
+		}
+		if errors.Is(err, domain.ErrConflict) && ifMatch != "" {
+			c.JSON(http.StatusPreconditionFailed, gin.H{"error": i18n.T(c, errPreconditionFailed)})
 			return
 		}
-		h.logger.Error("failed to delete product", "id", id, "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		h.logger.ErrorContext(c.Request.Context(), "failed to delete product", "id", id, "error", err)
+		respondError(c, err)
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
+
+// productBody is the JSON body for a single product in the negotiated API
+// version. The domain product never serializes its cost, so admins get a
+// response that adds it.
+func (h *ProductHandler) productBody(c *gin.Context, product domain.Product) interface{} {
+	if middleware.APIVersion(c) >= middleware.APIVersion2 {
+		return dto.ProductEnvelopeV2{Data: dto.NewProductV2(product, middleware.IsAdmin(c))}
+	}
+	if middleware.IsAdmin(c) {
+		return dto.NewAdminProductResponse(product)
+	}
+	return product
+}
+
+// ifMatchVersion resolves an If-Match header to the version a write on the
+// product must be conditional on, or nil for "*". A list naming several
+// versions is checked against the stored product, and the write is made
+// conditional on the version found. It answers the request itself and
+// reports false when the header is malformed or names no version the
+// product is at.
+func (h *ProductHandler) ifMatchVersion(c *gin.Context, id, header string) (*int64, bool) {
+	versions, wildcard, ok := ifMatchVersions(header)
+	switch {
+	case !ok:
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, errInvalidIfMatch)})
+		return nil, false
+	case wildcard:
+		return nil, true
+	case len(versions) == 1:
+		return &versions[0], true
+	case len(versions) == 0:
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": i18n.T(c, errPreconditionFailed)})
+		return nil, false
+	}
+
+	product, err := h.service.Get(ports.WithConsistentRead(c.Request.Context()), id)
+	if errors.Is(err, domain.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, err.Error())})
+		return nil, false
+	}
+	if err != nil {
+		respondError(c, err)
+		return nil, false
+	}
+	if !slices.Contains(versions, product.Version) {
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": i18n.T(c, errPreconditionFailed)})
+		return nil, false
+	}
+	return &product.Version, true
+}
+
+// displayPrice converts price into the currency requested with ?currency,
+// or returns nil when none was. It answers the request itself and reports
+// false when the price cannot be converted.
+func (h *ProductHandler) displayPrice(c *gin.Context, price domain.Money) (*domain.Money, bool) {
+	currency := c.Query("currency")
+	if currency == "" {
+		return nil, true
+	}
+
+	converted, err := h.currencies.Convert(c.Request.Context(), price, currency)
+	if err != nil {
+		if errors.Is(err, domain.ErrUnsupportedCurrency) || errors.Is(err, domain.ErrNoExchangeRate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, err.Error())})
+			return nil, false
+		}
+		h.logger.ErrorContext(c.Request.Context(), "failed to convert price", "currency", currency, "error", err)
+		respondError(c, err)
+		return nil, false
+	}
+	return &converted, true
+}
+
+// visible reports whether the client may read product: content moderation
+// has not approved it, only admins may, and to everyone else it does not
+// exist
+func visible(c *gin.Context, product domain.Product) bool {
+	return product.IsModerationApproved() || middleware.IsAdmin(c)
+}
+
+// respondRejected answers 422 with the moderation reasons when err is a
+// content rejection, reporting whether it did
+func respondRejected(c *gin.Context, err error) bool {
+	var rejected *domain.ContentRejectedError
+	if !errors.As(err, &rejected) {
+		return false
+	}
+	c.JSON(http.StatusUnprocessableEntity, gin.H{
+		"error":   i18n.T(c, domain.ErrContentRejected.Error()),
+		"reasons": rejected.Reasons,
+	})
+	return true
+}
+
+// respondDuplicate answers 409 naming the field whose value another product
+// already holds, reporting whether it did
+func respondDuplicate(c *gin.Context, err error) bool {
+	var duplicate *domain.DuplicateError
+	if !errors.As(err, &duplicate) {
+		return false
+	}
+	c.JSON(http.StatusConflict, gin.H{
+		"error": i18n.T(c, duplicate.Error()),
+		"field": duplicate.Field,
+	})
+	return true
+}
+
+// respondError answers an error the handler has no specific response for,
+// by its domain kind. Calls that ran out of time answer 504 and other
+// retryable failures 503, so a slow or throttled dependency can be told
+// apart from a bug; unclassified errors answer 500.
+func respondError(c *gin.Context, err error) {
+	status, message := errorStatus(err)
+	if status == http.StatusServiceUnavailable {
+		c.Header("Retry-After", "1")
+	}
+	c.JSON(status, gin.H{"error": i18n.T(c, message)})
+}
+
+// errorStatus is the status and untranslated message respondError answers
+// err with
+func errorStatus(err error) (int, string) {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout, "request timed out"
+	}
+	var domainErr *domain.Error
+	if !errors.As(err, &domainErr) {
+		return http.StatusInternalServerError, "internal server error"
+	}
+	switch domainErr.Kind {
+	case domain.KindNotFound:
+		return http.StatusNotFound, domainErr.Message
+	case domain.KindConflict:
+		return http.StatusConflict, domainErr.Message
+	case domain.KindValidation:
+		return http.StatusBadRequest, domainErr.Message
+	case domain.KindUnavailable:
+		return http.StatusServiceUnavailable, domainErr.Message
+	default:
+		return http.StatusInternalServerError, "internal server error"
+	}
+}
+
+// nonZero points at a price bound, or is nil when the bound is open
+func nonZero(bound domain.Decimal) *domain.Decimal {
+	if bound.IsZero() {
+		return nil
+	}
+	return &bound
+}
+
+// respondTombstone answers a request for a deleted product with a permanent
+// redirect to its successor, or 410 Gone when it has none
+func (h *ProductHandler) respondTombstone(c *gin.Context, tombstone *domain.TombstoneError) {
+	if tombstone.ReplacedBy == "" {
+		c.JSON(http.StatusGone, gin.H{"error": i18n.T(c, tombstone.Error())})
+		return
+	}
+
+	location := strings.TrimSuffix(c.Request.URL.Path, tombstone.ID) + tombstone.ReplacedBy
+	c.Header("Location", location)
+	c.JSON(http.StatusMovedPermanently, gin.H{
+		"error":       i18n.T(c, tombstone.Error()),
+		"replaced_by": tombstone.ReplacedBy,
+	})
+}
+
+// readContext returns the request context, flagged for strongly consistent
+// reads when the client asks for them via ?consistent=true or the
+// X-Consistent-Read header
+func (h *ProductHandler) readContext(c *gin.Context) context.Context {
+	ctx := c.Request.Context()
+	value := c.Query("consistent")
+	if value == "" {
+		value = c.GetHeader("X-Consistent-Read")
+	}
+	if consistent, err := strconv.ParseBool(value); err == nil && consistent {
+		return ports.WithConsistentRead(ctx)
+	}
+	return ctx
+}
@@ -4,27 +4,42 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/dto"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/middleware"
 	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
 	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/cursor"
 	"log/slog"
 )
 
 // MockProductService for testing
 type MockProductService struct {
 	mock.Mock
+	// views are the IDs RecordView was called with
+	views []string
 }
 
-func (m *MockProductService) Create(ctx context.Context, name, description string, price float64) (domain.Product, error) {
-	args := m.Called(ctx, name, description, price)
+func (m *MockProductService) Create(ctx context.Context, input ports.ProductInput) (domain.Product, error) {
+	args := m.Called(ctx, input)
+	return args.Get(0).(domain.Product), args.Error(1)
+}
+
+func (m *MockProductService) Clone(ctx context.Context, id string, input ports.CloneInput) (domain.Product, error) {
+	args := m.Called(ctx, id, input)
 	return args.Get(0).(domain.Product), args.Error(1)
 }
 
@@ -33,13 +48,32 @@ func (m *MockProductService) Get(ctx context.Context, id string) (domain.Product
 	return args.Get(0).(domain.Product), args.Error(1)
 }
 
-func (m *MockProductService) Update(ctx context.Context, id, name, description string, price float64) (domain.Product, error) {
-	args := m.Called(ctx, id, name, description, price)
+func (m *MockProductService) RecordView(ctx context.Context, id string) {
+	m.views = append(m.views, id)
+}
+
+func (m *MockProductService) GetBySKU(ctx context.Context, sku string) (domain.Product, error) {
+	args := m.Called(ctx, sku)
 	return args.Get(0).(domain.Product), args.Error(1)
 }
 
-func (m *MockProductService) Delete(ctx context.Context, id string) error {
-	args := m.Called(ctx, id)
+func (m *MockProductService) GetMany(ctx context.Context, ids []string) ([]domain.Product, []string, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]domain.Product), args.Get(1).([]string), args.Error(2)
+}
+
+func (m *MockProductService) Update(ctx context.Context, id string, input ports.ProductInput) (domain.Product, error) {
+	args := m.Called(ctx, id, input)
+	return args.Get(0).(domain.Product), args.Error(1)
+}
+
+func (m *MockProductService) SetTranslation(ctx context.Context, id, locale string, translation domain.Translation, version *int64) (domain.Product, error) {
+	args := m.Called(ctx, id, locale, translation, version)
+	return args.Get(0).(domain.Product), args.Error(1)
+}
+
+func (m *MockProductService) Delete(ctx context.Context, id, replacedBy string, version *int64) error {
+	args := m.Called(ctx, id, replacedBy, version)
 	return args.Error(0)
 }
 
@@ -50,24 +84,50 @@ func (m *MockProductService) List(ctx context.Context) ([]domain.Product, error)
 
 func (m *MockProductService) ListWithFilters(ctx context.Context, filters ports.ProductFilters) (*ports.ProductListResult, error) {
 	args := m.Called(ctx, filters)
-	return args.Get(0).(*ports.ProductListResult), args.Error(1)
+	result, _ := args.Get(0).(*ports.ProductListResult)
+	return result, args.Error(1)
+}
+
+func (m *MockProductService) Count(ctx context.Context, filters ports.ProductFilters) (int, error) {
+	args := m.Called(ctx, filters)
+	return args.Int(0), args.Error(1)
+}
+
+// stubCurrencyService converts at fixed rates from any currency
+type stubCurrencyService map[string]float64
+
+func (s stubCurrencyService) Convert(ctx context.Context, price domain.Money, to string) (domain.Money, error) {
+	rate, ok := s[to]
+	if !ok {
+		return domain.Money{}, domain.ErrNoExchangeRate
+	}
+	return price.Convert(to, rate)
 }
 
+const testAdminKey = "test-admin-key"
+
 func setupTestRouter() (*gin.Engine, *MockProductService) {
 	gin.SetMode(gin.TestMode)
 
 	mockService := &MockProductService{}
 	logger := slog.Default()
-	handler := NewProductHandler(mockService, logger)
+	cursors, _ := cursor.NewCodec("test-secret", time.Minute)
+	handler := NewProductHandler(mockService, stubCurrencyService{"EUR": 0.5}, cursors, false, "en", logger)
 
 	router := gin.New()
-	v1 := router.Group("/api/v1")
+	v1 := router.Group("/api/v1", middleware.IdentifyAdmin(testAdminKey))
 	products := v1.Group("/products")
 	{
 		products.GET("", handler.List)
+		products.HEAD("", handler.Count)
+		products.GET("/count", handler.Count)
 		products.POST("", handler.Create)
+		products.POST("/:id/clone", handler.Clone)
+		products.POST("/batch-get", handler.BatchGet)
+		products.GET("/by-sku/:sku", handler.GetBySKU)
 		products.GET("/:id", handler.Get)
 		products.PUT("/:id", handler.Update)
+		products.PUT("/:id/translations/:locale", handler.PutTranslation)
 		products.DELETE("/:id", handler.Delete)
 	}
 
@@ -80,8 +140,8 @@ func TestProductHandler_List_WithDefaults(t *testing.T) {
 	// Mock data
 	now := time.Now().UTC()
 	products := []domain.Product{
-		{ID: "1", Name: "Test Product 1", Description: "Description 1", Price: 10.99, CreatedAt: now, UpdatedAt: now},
-		{ID: "2", Name: "Test Product 2", Description: "Description 2", Price: 20.99, CreatedAt: now, UpdatedAt: now},
+		{ID: "1", Name: "Test Product 1", Description: "Description 1", Price: domain.Money{Amount: 1099, Currency: "USD"}, CreatedAt: now, UpdatedAt: now},
+		{ID: "2", Name: "Test Product 2", Description: "Description 2", Price: domain.Money{Amount: 2099, Currency: "USD"}, CreatedAt: now, UpdatedAt: now},
 	}
 
 	expectedResult := &ports.ProductListResult{
@@ -119,7 +179,7 @@ func TestProductHandler_List_WithPagination(t *testing.T) {
 	router, mockService := setupTestRouter()
 
 	products := []domain.Product{
-		{ID: "1", Name: "Test Product 1", Description: "Description 1", Price: 10.99, CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		{ID: "1", Name: "Test Product 1", Description: "Description 1", Price: domain.Money{Amount: 1099, Currency: "USD"}, CreatedAt: time.Now(), UpdatedAt: time.Now()},
 	}
 
 	expectedResult := &ports.ProductListResult{
@@ -154,7 +214,7 @@ func TestProductHandler_List_WithFilters(t *testing.T) {
 	router, mockService := setupTestRouter()
 
 	products := []domain.Product{
-		{ID: "1", Name: "Laptop", Description: "Gaming laptop", Price: 999.99, CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		{ID: "1", Name: "Laptop", Description: "Gaming laptop", Price: domain.Money{Amount: 99999, Currency: "USD"}, CreatedAt: time.Now(), UpdatedAt: time.Now()},
 	}
 
 	expectedResult := &ports.ProductListResult{
@@ -163,7 +223,7 @@ func TestProductHandler_List_WithFilters(t *testing.T) {
 	}
 
 	mockService.On("ListWithFilters", mock.Anything, mock.MatchedBy(func(filters ports.ProductFilters) bool {
-		return filters.Name == "Laptop" && filters.MinPrice == 500 && filters.MaxPrice == 1500
+		return filters.Name == "Laptop" && filters.MinPrice == domain.NewDecimal(500, 0) && filters.MaxPrice == domain.NewDecimal(1500, 0)
 	})).Return(expectedResult, nil)
 
 	req, _ := http.NewRequest("GET", "/api/v1/products?name=Laptop&min_price=500&max_price=1500", nil)
@@ -178,17 +238,75 @@ func TestProductHandler_List_WithFilters(t *testing.T) {
 	assert.Len(t, response.Products, 1)
 	assert.NotNil(t, response.FiltersApplied)
 	assert.Equal(t, "Laptop", response.FiltersApplied.Name)
-	assert.Equal(t, 500.0, response.FiltersApplied.MinPrice)
-	assert.Equal(t, 1500.0, response.FiltersApplied.MaxPrice)
+	assert.Equal(t, "500", response.FiltersApplied.MinPrice.String())
+	assert.Equal(t, "1500", response.FiltersApplied.MaxPrice.String())
+	assert.Contains(t, w.Body.String(), `"min_price":500,"max_price":1500`)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_List_ByCategory(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	products := []domain.Product{
+		{ID: "1", Name: "Pan", Price: domain.Money{Amount: 2500, Currency: "USD"}, CategoryID: "kitchen", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+	}
+
+	mockService.On("ListWithFilters", mock.Anything, mock.MatchedBy(func(filters ports.ProductFilters) bool {
+		return filters.CategoryID == "kitchen"
+	})).Return(&ports.ProductListResult{Products: products, TotalItems: 1}, nil)
 
+	req, _ := http.NewRequest("GET", "/api/v1/products?category_id=kitchen", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response dto.ListProductsResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response.Products, 1)
+	assert.Equal(t, "kitchen", response.Products[0].CategoryID)
+	assert.Equal(t, "kitchen", response.FiltersApplied.CategoryID)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_List_ByTags(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	products := []domain.Product{
+		{ID: "1", Name: "Headset", Price: domain.Money{Amount: 8000, Currency: "USD"}, Tags: []string{"gaming", "audio"}, CreatedAt: time.Now(), UpdatedAt: time.Now()},
+	}
+
+	mockService.On("ListWithFilters", mock.Anything, mock.MatchedBy(func(filters ports.ProductFilters) bool {
+		return assert.ObjectsAreEqual([]string{"gaming", "audio"}, filters.Tags) && filters.TagMatch == domain.TagMatchAll
+	})).Return(&ports.ProductListResult{Products: products, TotalItems: 1}, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/products?tags=Gaming,%20audio,&tags_match=all", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response dto.ListProductsResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"gaming", "audio"}, response.Products[0].Tags)
+	assert.Equal(t, []string{"gaming", "audio"}, response.FiltersApplied.Tags)
 	mockService.AssertExpectations(t)
+
+	req, _ = http.NewRequest("GET", "/api/v1/products?tags=gaming&tags_match=some", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
 func TestProductHandler_List_WithSorting(t *testing.T) {
 	router, mockService := setupTestRouter()
 
 	products := []domain.Product{
-		{ID: "1", Name: "A Product", Description: "Description", Price: 10.99, CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		{ID: "1", Name: "A Product", Description: "Description", Price: domain.Money{Amount: 1099, Currency: "USD"}, CreatedAt: time.Now(), UpdatedAt: time.Now()},
 	}
 
 	expectedResult := &ports.ProductListResult{
@@ -208,6 +326,58 @@ func TestProductHandler_List_WithSorting(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestProductHandler_List_ThenBy(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	mockService.On("ListWithFilters", mock.Anything, mock.MatchedBy(func(filters ports.ProductFilters) bool {
+		return filters.SortBy == "price" && slices.Equal(filters.ThenBy, []string{"name", "created_at"})
+	})).Return(&ports.ProductListResult{Products: []domain.Product{}}, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/products?sort_by=price&then_by=Name,%20created_at", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+
+	for _, query := range []string{
+		"sort_by=price&then_by=color",
+		"sort_by=price&then_by=name,price",
+		"sort_by=price&then_by=name&after_id=1&after_value=10",
+	} {
+		req, _ := http.NewRequest("GET", "/api/v1/products?"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestProductHandler_List_TimeRange(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	since := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	mockService.On("ListWithFilters", mock.Anything, mock.MatchedBy(func(filters ports.ProductFilters) bool {
+		return filters.UpdatedAfter.Equal(since) && filters.UpdatedBefore.IsZero() && filters.CreatedAfter.IsZero()
+	})).Return(&ports.ProductListResult{Products: []domain.Product{}}, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/products?sort_by=updated_at&updated_after=2024-03-01T13:00:00%2B01:00", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+
+	for _, query := range []string{
+		"created_after=yesterday",
+		"updated_before=2024-03-01",
+		"created_after=2024-03-02T00:00:00Z&created_before=2024-03-01T00:00:00Z",
+		"updated_after=2024-03-02T00:00:00Z&updated_before=2024-03-01T00:00:00Z",
+	} {
+		req, _ := http.NewRequest("GET", "/api/v1/products?"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
 func TestProductHandler_List_InvalidPage(t *testing.T) {
 	router, _ := setupTestRouter()
 
@@ -223,8 +393,30 @@ func TestProductHandler_List_InvalidPage(t *testing.T) {
 	assert.Equal(t, "page cannot exceed 1000", response["error"])
 }
 
+func TestProductHandler_List_PriceCurrency(t *testing.T) {
+	router, mockService := setupTestRouter()
+	mockService.On("ListWithFilters", mock.Anything, mock.MatchedBy(func(filters ports.ProductFilters) bool {
+		return filters.Currency == "EUR"
+	})).Return(&ports.ProductListResult{}, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/products?min_price=10&price_currency=eur", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var response dto.ListProductsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "EUR", response.FiltersApplied.PriceCurrency)
+
+	req, _ = http.NewRequest("GET", "/api/v1/products?price_currency=XYZ", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "price_currency must be a supported ISO 4217 code")
+}
+
 func TestProductHandler_List_InvalidPriceRange(t *testing.T) {
-	router, _ := setupTestRouter()
+	router, mockService := setupTestRouter()
+	mockService.On("ListWithFilters", mock.Anything, mock.Anything).Return(&ports.ProductListResult{}, nil)
 
 	req, _ := http.NewRequest("GET", "/api/v1/products?min_price=100&max_price=50", nil)
 	w := httptest.NewRecorder()
@@ -236,6 +428,19 @@ func TestProductHandler_List_InvalidPriceRange(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 	assert.Equal(t, "min_price cannot be greater than max_price", response["error"])
+
+	// Bounds compare exactly, whatever their trailing zeros
+	req, _ = http.NewRequest("GET", "/api/v1/products?min_price=0.30&max_price=0.3", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	for _, query := range []string{"min_price=-1", "max_price=1e3", "min_price=abc"} {
+		req, _ = http.NewRequest("GET", "/api/v1/products?"+query, nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
 }
 
 func TestProductHandler_List_InvalidSortField(t *testing.T) {
@@ -246,6 +451,95 @@ func TestProductHandler_List_InvalidSortField(t *testing.T) {
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response struct {
+		Error  string       `json:"error"`
+		Fields []FieldError `json:"fields"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "invalid query parameters", response.Error)
+	assert.Equal(t, []FieldError{{
+		Field:   "sort_by",
+		Rule:    "oneof",
+		Message: "sort_by must be one of: name, price, created_at, updated_at",
+	}}, response.Fields)
+}
+
+func TestProductHandler_Create_FieldErrors(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	tests := []struct {
+		name   string
+		body   string
+		fields []FieldError
+	}{
+		{
+			"failed rules",
+			`{"description":"no name","price":0}`,
+			[]FieldError{
+				{Field: "name", Rule: "required", Message: "name is required"},
+				{Field: "price", Rule: "price", Message: "price must have an amount greater than 0 and a supported ISO 4217 currency"},
+			},
+		},
+		{
+			"wrong type",
+			`{"name":"Hat","price":"cheap"}`,
+			[]FieldError{{Field: "price", Rule: "type", Message: "price must be a number or an object with amount and currency"}},
+		},
+		{
+			"malformed JSON",
+			`{"name":`,
+			[]FieldError{{Rule: "json", Message: "request body is not valid JSON"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("POST", "/api/v1/products", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var response struct {
+				Error  string       `json:"error"`
+				Fields []FieldError `json:"fields"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, "invalid request body", response.Error)
+			assert.Equal(t, tt.fields, response.Fields)
+		})
+	}
+}
+
+func TestProductHandler_ErrorsInAcceptedLanguage(t *testing.T) {
+	router, mockService := setupTestRouter()
+	mockService.On("Get", mock.Anything, "missing").Return(domain.Product{}, domain.ErrNotFound)
+
+	req, _ := http.NewRequest("POST", "/api/v1/products", bytes.NewBufferString(`{"price":0}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", "es-AR,es;q=0.9")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response struct {
+		Error  string       `json:"error"`
+		Fields []FieldError `json:"fields"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "cuerpo de la solicitud inválido", response.Error)
+	assert.Equal(t, FieldError{Field: "name", Rule: "required", Message: "el nombre es obligatorio"}, response.Fields[0])
+
+	for header, want := range map[string]string{"es": "producto no encontrado", "fr": "product not found", "": "product not found"} {
+		req, _ = http.NewRequest("GET", "/api/v1/products/missing", nil)
+		req.Header.Set("Accept-Language", header)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.JSONEq(t, `{"error":"`+want+`"}`, w.Body.String(), header)
+	}
 }
 
 func TestProductHandler_List_ServiceError(t *testing.T) {
@@ -347,9 +641,9 @@ func TestListProductsRequest_HasFilters(t *testing.T) {
 	}{
 		{"no filters", dto.ListProductsRequest{}, false},
 		{"name filter", dto.ListProductsRequest{Name: "test"}, true},
-		{"min_price filter", dto.ListProductsRequest{MinPrice: 10}, true},
-		{"max_price filter", dto.ListProductsRequest{MaxPrice: 100}, true},
-		{"multiple filters", dto.ListProductsRequest{Name: "test", MinPrice: 10}, true},
+		{"min_price filter", dto.ListProductsRequest{MinPrice: domain.NewDecimal(10, 0)}, true},
+		{"max_price filter", dto.ListProductsRequest{MaxPrice: domain.NewDecimal(100, 0)}, true},
+		{"multiple filters", dto.ListProductsRequest{Name: "test", MinPrice: domain.NewDecimal(10, 0)}, true},
 	}
 
 	for _, tt := range tests {
@@ -358,3 +652,1113 @@ func TestListProductsRequest_HasFilters(t *testing.T) {
 		})
 	}
 }
+
+func TestProductHandler_Get_ConsistentRead(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		header   string
+		expected bool
+	}{
+		{"default eventual", "", "", false},
+		{"query param", "?consistent=true", "", true},
+		{"header", "", "true", true},
+		{"explicit false", "?consistent=false", "true", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, mockService := setupTestRouter()
+
+			mockService.On("Get", mock.MatchedBy(func(ctx context.Context) bool {
+				return ports.ConsistentRead(ctx) == tt.expected
+			}), "1").Return(domain.Product{ID: "1"}, nil)
+
+			req, _ := http.NewRequest("GET", "/api/v1/products/1"+tt.query, nil)
+			if tt.header != "" {
+				req.Header.Set("X-Consistent-Read", tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductHandler_Get_DisplayCurrency(t *testing.T) {
+	router, mockService := setupTestRouter()
+	mockService.On("Get", mock.Anything, "1").Return(domain.Product{ID: "1", Name: "Hat", Price: domain.Money{Amount: 2050, Currency: "USD"}}, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/products/1?currency=EUR", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response dto.ProductResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, domain.Money{Amount: 2050, Currency: "USD"}, response.Price)
+	assert.Equal(t, &domain.Money{Amount: 1025, Currency: "EUR"}, response.DisplayPrice)
+
+	req, _ = http.NewRequest("GET", "/api/v1/products/1?currency=GBP", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestProductHandler_Clone(t *testing.T) {
+	router, mockService := setupTestRouter()
+	clone := domain.Product{ID: "2", Name: "Hat (blue)", Price: domain.Money{Amount: 1999, Currency: "USD"}, Version: 1}
+	mockService.On("Clone", mock.Anything, "1", mock.MatchedBy(func(input ports.CloneInput) bool {
+		return input.NameSuffix == " (blue)" && input.Price == nil && input.Tags != nil && input.SKU == "HAT-BLUE"
+	})).Return(clone, nil)
+	mockService.On("Clone", mock.Anything, "missing", ports.CloneInput{}).Return(domain.Product{}, domain.ErrNotFound)
+
+	req, _ := http.NewRequest("POST", "/api/v1/products/1/clone", bytes.NewBufferString(`{"name_suffix":" (blue)","tags":["blue"],"sku":"HAT-BLUE"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+	version, ok := etagVersion(w.Header().Get("ETag"))
+	assert.True(t, ok)
+	assert.Equal(t, clone.Version, version)
+	assert.Contains(t, w.Body.String(), `"name":"Hat (blue)"`)
+
+	// Without a body the product is copied as it is
+	req, _ = http.NewRequest("POST", "/api/v1/products/missing/clone", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	req, _ = http.NewRequest("POST", "/api/v1/products/1/clone", bytes.NewBufferString(`{"cost_price":5}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestProductHandler_Create_Money(t *testing.T) {
+	tests := []struct {
+		name  string
+		price string
+		want  domain.Money
+	}{
+		{"amount and currency", `{"amount":1999,"currency":"eur"}`, domain.Money{Amount: 1999, Currency: "EUR"}},
+		{"legacy number", `19.99`, domain.Money{Amount: 1999, Currency: domain.DefaultCurrency}},
+		{"zero-decimal currency", `{"amount":1500,"currency":"JPY"}`, domain.Money{Amount: 1500, Currency: "JPY"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, mockService := setupTestRouter()
+			mockService.On("Create", mock.Anything, mock.MatchedBy(func(input ports.ProductInput) bool {
+				return input.Price == tt.want
+			})).Return(domain.Product{ID: "1", Name: "Hat", Price: tt.want}, nil)
+
+			req, _ := http.NewRequest("POST", "/api/v1/products", bytes.NewBufferString(`{"name":"Hat","price":`+tt.price+`}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusCreated, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+
+	router, _ := setupTestRouter()
+	for _, price := range []string{`{"amount":1999,"currency":"XYZ"}`, `{"amount":-5,"currency":"USD"}`, `19.999`} {
+		req, _ := http.NewRequest("POST", "/api/v1/products", bytes.NewBufferString(`{"name":"Hat","price":`+price+`}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, price)
+	}
+}
+
+func TestProductHandler_Create_WithExpiration(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	mockService.On("Create", mock.Anything, mock.MatchedBy(func(input ports.ProductInput) bool {
+		return input.Name == "Promo" && input.ExpiresAt != nil && input.ExpiresAt.Equal(expiresAt)
+	})).Return(domain.Product{ID: "1", Name: "Promo", Price: domain.Money{Amount: 500, Currency: "USD"}, ExpiresAt: &expiresAt}, nil)
+
+	body := `{"name":"Promo","price":5,"expires_at":"2030-01-01T00:00:00Z"}`
+	req, _ := http.NewRequest("POST", "/api/v1/products", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response domain.Product
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.NotNil(t, response.ExpiresAt)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_Create_ExpiredInPast(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	mockService.On("Create", mock.Anything, mock.Anything).Return(domain.Product{}, domain.ErrInvalidProduct)
+
+	body := `{"name":"Promo","price":5,"expires_at":"2001-01-01T00:00:00Z"}`
+	req, _ := http.NewRequest("POST", "/api/v1/products", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_List_Explain(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	expectedResult := &ports.ProductListResult{
+		Products:   []domain.Product{},
+		TotalItems: 0,
+		Plan:       &ports.QueryPlan{Operation: "Query", Index: "price-index", Partitions: 1, Reason: "index sorted by price"},
+	}
+
+	mockService.On("ListWithFilters", mock.Anything, mock.MatchedBy(func(filters ports.ProductFilters) bool {
+		return filters.Explain
+	})).Return(expectedResult, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/products?sort_by=price&explain=true", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response dto.ListProductsResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	if assert.NotNil(t, response.Explain) {
+		assert.Equal(t, "Query", response.Explain.Operation)
+		assert.Equal(t, "price-index", response.Explain.Index)
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_List_Cursor(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	mockService.On("ListWithFilters", mock.Anything, mock.MatchedBy(func(filters ports.ProductFilters) bool {
+		return filters.StartKey == nil
+	})).Return(&ports.ProductListResult{
+		Products:   []domain.Product{{ID: "1"}},
+		TotalItems: 2,
+		NextKey:    []byte("after-1"),
+	}, nil).Once()
+
+	req, _ := http.NewRequest("GET", "/api/v1/products?limit=1&sort_by=price", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var first dto.ListProductsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &first))
+	assert.NotEmpty(t, first.Pagination.NextCursor)
+	assert.True(t, first.Pagination.HasNext)
+
+	mockService.On("ListWithFilters", mock.Anything, mock.MatchedBy(func(filters ports.ProductFilters) bool {
+		return string(filters.StartKey) == "after-1" && filters.Offset == 0
+	})).Return(&ports.ProductListResult{
+		Products:   []domain.Product{{ID: "2"}},
+		TotalItems: 2,
+	}, nil).Once()
+
+	req, _ = http.NewRequest("GET", "/api/v1/products?limit=1&sort_by=price&cursor="+first.Pagination.NextCursor, nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var second dto.ListProductsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &second))
+	assert.Empty(t, second.Pagination.NextCursor)
+
+	// Replaying the cursor against other filters is rejected
+	req, _ = http.NewRequest("GET", "/api/v1/products?limit=1&sort_by=name&cursor="+first.Pagination.NextCursor, nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), cursor.ErrFilterMismatch.Error())
+
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_List_InvalidCursor(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	req, _ := http.NewRequest("GET", "/api/v1/products?cursor=bogus", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), cursor.ErrInvalid.Error())
+}
+
+func TestProductHandler_Get_Tombstone(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	mockService.On("Get", mock.Anything, "old").
+		Return(domain.Product{}, &domain.TombstoneError{ID: "old", ReplacedBy: "new"})
+	mockService.On("Get", mock.Anything, "gone").
+		Return(domain.Product{}, &domain.TombstoneError{ID: "gone"})
+
+	req, _ := http.NewRequest("GET", "/api/v1/products/old", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/api/v1/products/new", w.Header().Get("Location"))
+	assert.Contains(t, w.Body.String(), `"replaced_by":"new"`)
+
+	req, _ = http.NewRequest("GET", "/api/v1/products/gone", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusGone, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_Delete_WithReplacement(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	mockService.On("Delete", mock.Anything, "old", "new", (*int64)(nil)).Return(nil)
+	mockService.On("Delete", mock.Anything, "dup", "missing", (*int64)(nil)).Return(domain.ErrInvalidReplacement)
+
+	req, _ := http.NewRequest("DELETE", "/api/v1/products/old?replaced_by=new", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	req, _ = http.NewRequest("DELETE", "/api/v1/products/dup?replaced_by=missing", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_Delete_IfMatch(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	current, stale := int64(3), int64(2)
+	mockService.On("Delete", mock.Anything, "prod-1", "", &current).Return(nil)
+	mockService.On("Delete", mock.Anything, "prod-1", "", &stale).Return(domain.ErrConflict)
+	mockService.On("Delete", mock.Anything, "prod-2", "", (*int64)(nil)).Return(domain.ErrConflict)
+
+	tests := []struct {
+		name    string
+		id      string
+		ifMatch string
+		status  int
+	}{
+		{"current version", "prod-1", `"3"`, http.StatusNoContent},
+		{"stale version", "prod-1", `W/"2"`, http.StatusPreconditionFailed},
+		{"malformed If-Match", "prod-1", "3", http.StatusBadRequest},
+		{"modified while deleting", "prod-2", "", http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("DELETE", "/api/v1/products/"+tt.id, nil)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_Create_RejectedByModeration(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	mockService.On("Create", mock.Anything, mock.Anything).
+		Return(domain.Product{}, &domain.ContentRejectedError{Reasons: []string{`blocked term "scam"`}})
+
+	body := bytes.NewBufferString(`{"name":"Scam kit","price":10}`)
+	req, _ := http.NewRequest("POST", "/api/v1/products", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), `blocked term`)
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_CostPrice_ForbiddenForNonAdmins(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	body := bytes.NewBufferString(`{"name":"Hat","price":20,"cost_price":12}`)
+	req, _ := http.NewRequest("POST", "/api/v1/products", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockService.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestProductHandler_CostPrice_Decimal(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	body := bytes.NewBufferString(`{"name":"Hat","price":20,"cost_price":-1}`)
+	req, _ := http.NewRequest("POST", "/api/v1/products", body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(middleware.AdminKeyHeader, testAdminKey)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+	mockService.On("Create", mock.Anything, mock.MatchedBy(func(input ports.ProductInput) bool {
+		return input.CostPrice != nil && input.CostPrice.String() == "12.5"
+	})).Return(domain.Product{ID: "1", Name: "Hat", Price: domain.Money{Amount: 2000, Currency: "USD"}}, nil)
+
+	body = bytes.NewBufferString(`{"name":"Hat","price":20,"cost_price":"12.50"}`)
+	req, _ = http.NewRequest("POST", "/api/v1/products", body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(middleware.AdminKeyHeader, testAdminKey)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_CostPrice_VisibleOnlyToAdmins(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	cost := domain.Money{Amount: 1500, Currency: "USD"}
+	mockService.On("Get", mock.Anything, "1").Return(domain.Product{ID: "1", Name: "Hat", Price: domain.Money{Amount: 2000, Currency: "USD"}, CostPrice: &cost}, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/products/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "cost_price")
+	assert.NotContains(t, w.Body.String(), "margin")
+
+	req, _ = http.NewRequest("GET", "/api/v1/products/1", nil)
+	req.Header.Set(middleware.AdminKeyHeader, testAdminKey)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response dto.AdminProductResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, cost, *response.CostPrice)
+	assert.InDelta(t, 0.25, *response.Margin, 1e-9)
+}
+
+func TestProductHandler_Update_Conflict(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	mockService.On("Update", mock.Anything, "1", mock.MatchedBy(func(input ports.ProductInput) bool {
+		return input.Version != nil && *input.Version == 3
+	})).Return(domain.Product{}, domain.ErrConflict)
+
+	body := bytes.NewBufferString(`{"name":"Hat","price":20,"version":3}`)
+	req, _ := http.NewRequest("PUT", "/api/v1/products/1", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_Create_DuplicateSKU(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	mockService.On("Create", mock.Anything, mock.MatchedBy(func(input ports.ProductInput) bool {
+		return input.SKU == "lap-1" && input.Barcode == "4006381333931"
+	})).Return(domain.Product{}, &domain.DuplicateError{Field: domain.FieldSKU, Value: "LAP-1"})
+
+	body := bytes.NewBufferString(`{"name":"Laptop","price":10,"sku":"lap-1","barcode":"4006381333931"}`)
+	req, _ := http.NewRequest("POST", "/api/v1/products", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.JSONEq(t, `{"error":"sku \"LAP-1\" is already used by another product","field":"sku"}`, w.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_Create_DuplicateID(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	mockService.On("Create", mock.Anything, mock.MatchedBy(func(input ports.ProductInput) bool {
+		return input.ID == "erp-1042"
+	})).Return(domain.Product{}, &domain.DuplicateError{Field: domain.FieldID, Value: "erp-1042"})
+
+	body := bytes.NewBufferString(`{"id":"erp-1042","name":"Laptop","price":10}`)
+	req, _ := http.NewRequest("POST", "/api/v1/products", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.JSONEq(t, `{"error":"id \"erp-1042\" is already used by another product","field":"id"}`, w.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_Update_CreateIfMissing(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	mockService.On("Create", mock.Anything, mock.MatchedBy(func(input ports.ProductInput) bool {
+		return input.ID == "new" && input.Version == nil
+	})).Return(domain.Product{ID: "new", Name: "Hat", Price: domain.Money{Amount: 2000, Currency: "USD"}, Version: 1}, nil)
+	mockService.On("Create", mock.Anything, mock.MatchedBy(func(input ports.ProductInput) bool {
+		return input.ID == "1"
+	})).Return(domain.Product{}, &domain.DuplicateError{Field: domain.FieldID, Value: "1"})
+
+	tests := []struct {
+		name        string
+		id          string
+		ifNoneMatch string
+		ifMatch     string
+		body        string
+		status      int
+	}{
+		{"creates missing product", "new", "*", "", `{"name":"Hat","price":20}`, http.StatusCreated},
+		{"existing product", "1", "*", "", `{"name":"Hat","price":20}`, http.StatusPreconditionFailed},
+		{"ETag list", "new", `"1"`, "", `{"name":"Hat","price":20}`, http.StatusBadRequest},
+		{"combined with If-Match", "new", "*", `"1"`, `{"name":"Hat","price":20}`, http.StatusBadRequest},
+		{"body id differs", "new", "*", "", `{"id":"other","name":"Hat","price":20}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("PUT", "/api/v1/products/"+tt.id, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusCreated {
+				assert.True(t, strings.HasPrefix(w.Header().Get("ETag"), `"1-`))
+			}
+		})
+	}
+}
+
+func TestProductHandler_Update_Upsert(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := &MockProductService{}
+	cursors, _ := cursor.NewCodec("test-secret", time.Minute)
+	handler := NewProductHandler(mockService, stubCurrencyService{"EUR": 0.5}, cursors, true, "en", slog.Default())
+	router := gin.New()
+	router.PUT("/products/:id", handler.Update)
+
+	mockService.On("Create", mock.Anything, mock.MatchedBy(func(input ports.ProductInput) bool {
+		return input.ID == "new"
+	})).Return(domain.Product{ID: "new", Name: "Hat", Version: 1}, nil)
+	mockService.On("Create", mock.Anything, mock.MatchedBy(func(input ports.ProductInput) bool {
+		return input.ID == "1"
+	})).Return(domain.Product{}, &domain.DuplicateError{Field: domain.FieldID, Value: "1"})
+	mockService.On("Update", mock.Anything, "1", mock.Anything).Return(domain.Product{ID: "1", Name: "Hat", Version: 5}, nil)
+
+	put := func(id, ifMatch string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("PUT", "/products/"+id, bytes.NewBufferString(`{"name":"Hat","price":20}`))
+		req.Header.Set("Content-Type", "application/json")
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusCreated, put("new", "").Code)
+	// Existing products still need a precondition
+	assert.Equal(t, http.StatusPreconditionRequired, put("1", "").Code)
+	assert.Equal(t, http.StatusOK, put("1", `"4"`).Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_BatchGet(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	mockService.On("GetMany", mock.Anything, []string{"1", "gone"}).Return(
+		[]domain.Product{{ID: "1", Name: "Laptop", Price: domain.Money{Amount: 99900, Currency: "USD"}}}, []string{"gone"}, nil)
+
+	req, _ := http.NewRequest("POST", "/api/v1/products/batch-get", bytes.NewBufferString(`{"ids":["1","gone"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response dto.BatchGetResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Products, 1)
+	assert.Equal(t, "Laptop", response.Products[0].Name)
+	assert.Equal(t, []string{"gone"}, response.Missing)
+
+	ids := make([]string, 101)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("p%d", i)
+	}
+	body, _ := json.Marshal(dto.BatchGetRequest{IDs: ids})
+	for _, payload := range []string{`{"ids":[]}`, `{"ids":[""]}`, string(body)} {
+		req, _ = http.NewRequest("POST", "/api/v1/products/batch-get", bytes.NewBufferString(payload))
+		req.Header.Set("Content-Type", "application/json")
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, payload[:min(len(payload), 20)])
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_Timeout(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	mockService.On("Get", mock.Anything, "slow").Return(domain.Product{}, fmt.Errorf("operation error DynamoDB: GetItem: %w", context.DeadlineExceeded))
+	mockService.On("Get", mock.Anything, "broken").Return(domain.Product{}, errors.New("boom"))
+
+	req, _ := http.NewRequest("GET", "/api/v1/products/slow", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.JSONEq(t, `{"error":"request timed out"}`, w.Body.String())
+
+	req, _ = http.NewRequest("GET", "/api/v1/products/broken", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestProductHandler_ErrorKinds(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	mockService.On("Get", mock.Anything, "throttled").Return(domain.Product{}, domain.ErrUnavailable.Wrap(errors.New("ProvisionedThroughputExceededException")))
+	mockService.On("Get", mock.Anything, "contended").Return(domain.Product{}, fmt.Errorf("get: %w", domain.ErrConflict.Wrap(errors.New("TransactionConflict"))))
+	mockService.On("Get", mock.Anything, "gone").Return(domain.Product{}, domain.ErrNotFound.With("id", "gone"))
+
+	req, _ := http.NewRequest("GET", "/api/v1/products/throttled", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"error":"service temporarily unavailable"}`, w.Body.String(), "the cause is not leaked")
+
+	req, _ = http.NewRequest("GET", "/api/v1/products/contended", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	req, _ = http.NewRequest("GET", "/api/v1/products/gone", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestProductHandler_Get_Localized(t *testing.T) {
+	router, mockService := setupTestRouter()
+	product := domain.Product{ID: "1", Name: "Laptop", Description: "Fast", Version: 2, Translations: map[string]domain.Translation{
+		"es":    {Name: "Portátil", Description: "Rápido"},
+		"pt-BR": {Name: "Notebook"},
+	}}
+	mockService.On("Get", mock.Anything, "1").Return(product, nil)
+
+	tests := []struct {
+		acceptLanguage string
+		locale         string
+		name           string
+		description    string
+	}{
+		{"", "en", "Laptop", "Fast"},
+		{"es-AR,es;q=0.9", "es", "Portátil", "Rápido"},
+		{"pt-BR", "pt-BR", "Notebook", "Fast"},
+		{"fr, es;q=0.5", "es", "Portátil", "Rápido"},
+		{"en-US, es;q=0.5", "en", "Laptop", "Fast"},
+		{"de", "en", "Laptop", "Fast"},
+		{"not a language", "en", "Laptop", "Fast"},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest("GET", "/api/v1/products/1", nil)
+		req.Header.Set("Accept-Language", tt.acceptLanguage)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, tt.locale, w.Header().Get("Content-Language"), tt.acceptLanguage)
+		assert.Contains(t, w.Header().Values("Vary"), "Accept-Language")
+		var response domain.Product
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, tt.name, response.Name, tt.acceptLanguage)
+		assert.Equal(t, tt.description, response.Description, tt.acceptLanguage)
+		assert.Len(t, response.Translations, 2)
+	}
+}
+
+func TestProductHandler_PutTranslation(t *testing.T) {
+	router, mockService := setupTestRouter()
+	translated := domain.Product{ID: "1", Name: "Laptop", Version: 3, Translations: map[string]domain.Translation{"es": {Name: "Portátil"}}}
+	version := int64(2)
+	mockService.On("SetTranslation", mock.Anything, "1", "es", domain.Translation{Name: "Portátil"}, &version).Return(translated, nil)
+	stale := int64(1)
+	mockService.On("SetTranslation", mock.Anything, "1", "es", domain.Translation{Name: "Portátil"}, &stale).Return(domain.Product{}, domain.ErrConflict)
+	mockService.On("SetTranslation", mock.Anything, "1", "es", domain.Translation{Name: "Portátil"}, (*int64)(nil)).Return(domain.Product{}, domain.ErrConflict)
+	mockService.On("SetTranslation", mock.Anything, "1", "123", mock.Anything, mock.Anything).Return(domain.Product{}, domain.ErrInvalidLocale)
+	mockService.On("SetTranslation", mock.Anything, "missing", "es", mock.Anything, mock.Anything).Return(domain.Product{}, domain.ErrNotFound)
+
+	put := func(path, ifMatch, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("PUT", path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := put("/api/v1/products/1/translations/es", `"2"`, `{"name":"Portátil"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, strings.HasPrefix(w.Header().Get("ETag"), `"3-`))
+	assert.Contains(t, w.Body.String(), `"translations":{"es":{"name":"Portátil"}}`)
+
+	assert.Equal(t, http.StatusPreconditionFailed, put("/api/v1/products/1/translations/es", `"1"`, `{"name":"Portátil"}`).Code)
+	assert.Equal(t, http.StatusConflict, put("/api/v1/products/1/translations/es", "", `{"name":"Portátil"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, put("/api/v1/products/1/translations/123", "", `{"name":"x"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, put("/api/v1/products/1/translations/es", "", `{"description":"no name"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, put("/api/v1/products/1/translations/es", "v1", `{"name":"x"}`).Code)
+	assert.Equal(t, http.StatusNotFound, put("/api/v1/products/missing/translations/es", "", `{"name":"x"}`).Code)
+}
+
+func TestProductHandler_ContentNegotiation(t *testing.T) {
+	router, mockService := setupTestRouter()
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	products := []domain.Product{
+		{ID: "1", Name: "=Laptop", Price: domain.Money{Amount: 99950, Currency: "USD"}, Stock: 3, Version: 2, CreatedAt: created, UpdatedAt: created},
+		{ID: "2", Name: "Mouse & pad", Price: domain.Money{Amount: 1999, Currency: "USD"}, CreatedAt: created, UpdatedAt: created},
+	}
+	mockService.On("Get", mock.Anything, "1").Return(products[0], nil)
+	mockService.On("ListWithFilters", mock.Anything, mock.Anything).Return(&ports.ProductListResult{Products: products, TotalItems: 2}, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/products", nil)
+	req.Header.Set("Accept", "text/csv")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "2", w.Header().Get("X-Total-Count"))
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, strings.Join(exportColumns, ","), lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], "1,'=Laptop,,999.50,USD,"), lines[1])
+
+	req, _ = http.NewRequest("GET", "/api/v1/products/1", nil)
+	req.Header.Set("Accept", "application/xml")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/xml; charset=utf-8", w.Header().Get("Content-Type"))
+	var product xmlProduct
+	require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &product))
+	assert.Equal(t, "=Laptop", product.Name)
+	assert.Equal(t, "999.50", product.Price)
+	assert.Equal(t, int64(3), product.Stock)
+
+	req, _ = http.NewRequest("GET", "/api/v1/products", nil)
+	req.Header.Set("Accept", "text/xml")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var list xmlProducts
+	require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Products, 2)
+	assert.Equal(t, "Mouse & pad", list.Products[1].Name)
+
+	for _, accept := range []string{"", "*/*", "application/json", "application/vnd.products.v2+json", "image/png"} {
+		req, _ = http.NewRequest("GET", "/api/v1/products/1", nil)
+		req.Header.Set("Accept", accept)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"), accept)
+	}
+}
+
+func TestProductHandler_GetBySKU(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	mockService.On("GetBySKU", mock.Anything, "lap-1").Return(domain.Product{ID: "1", Name: "Laptop", SKU: "LAP-1", Version: 2}, nil)
+	mockService.On("GetBySKU", mock.Anything, "missing").Return(domain.Product{}, domain.ErrNotFound)
+
+	req, _ := http.NewRequest("GET", "/api/v1/products/by-sku/lap-1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"sku":"LAP-1"`)
+	assert.NotEmpty(t, w.Header().Get("ETag"))
+
+	req, _ = http.NewRequest("GET", "/api/v1/products/by-sku/missing", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_Get_NotModified(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	mockService.On("Get", mock.Anything, "1").Return(domain.Product{ID: "1", Name: "Hat", Price: domain.Money{Amount: 2000, Currency: "USD"}, Version: 4}, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/products/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.True(t, strings.HasPrefix(etag, `"4-`), etag)
+
+	req, _ = http.NewRequest("GET", "/api/v1/products/1", nil)
+	req.Header.Set("If-None-Match", `"3", `+etag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Equal(t, []string{"1"}, mockService.views, "a 304 is not counted as a view")
+
+	// Another representation of the same version has another tag
+	for _, header := range [][2]string{{"Accept", "text/csv"}, {middleware.AdminKeyHeader, testAdminKey}} {
+		req, _ = http.NewRequest("GET", "/api/v1/products/1", nil)
+		req.Header.Set("If-None-Match", etag)
+		req.Header.Set(header[0], header[1])
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code, header[0])
+		assert.NotEqual(t, etag, w.Header().Get("ETag"), header[0])
+	}
+	req, _ = http.NewRequest("GET", "/api/v1/products/1?currency=EUR", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestProductHandler_Update_Preconditions(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	mockService.On("Update", mock.Anything, "1", mock.MatchedBy(func(input ports.ProductInput) bool {
+		return input.Version != nil && *input.Version == 3
+	})).Return(domain.Product{}, domain.ErrConflict)
+	mockService.On("Update", mock.Anything, "1", mock.MatchedBy(func(input ports.ProductInput) bool {
+		return input.Version != nil && *input.Version == 4
+	})).Return(domain.Product{ID: "1", Name: "Hat", Price: domain.Money{Amount: 2000, Currency: "USD"}, Version: 5}, nil)
+	mockService.On("Get", mock.Anything, "1").Return(domain.Product{ID: "1", Name: "Hat", Price: domain.Money{Amount: 2000, Currency: "USD"}, Version: 4}, nil)
+
+	tests := []struct {
+		name    string
+		ifMatch string
+		body    string
+		status  int
+		etag    string
+	}{
+		{"missing precondition", "", `{"name":"Hat","price":20}`, http.StatusPreconditionRequired, ""},
+		{"malformed If-Match", "4", `{"name":"Hat","price":20}`, http.StatusBadRequest, ""},
+		{"stale If-Match", `"3"`, `{"name":"Hat","price":20}`, http.StatusPreconditionFailed, ""},
+		{"If-Match disagrees with body version", `"4"`, `{"name":"Hat","price":20,"version":3}`, http.StatusPreconditionFailed, ""},
+		{"current If-Match", `"4"`, `{"name":"Hat","price":20}`, http.StatusOK, `"5-`},
+		{"tags of one version", `"4-0a1b2c3d", W/"4-ccf8b8e8"`, `{"name":"Hat","price":20}`, http.StatusOK, `"5-`},
+		{"list naming the current version", `"2", "4-0a1b2c3d"`, `{"name":"Hat","price":20}`, http.StatusOK, `"5-`},
+		{"list of stale versions", `"2", "3"`, `{"name":"Hat","price":20}`, http.StatusPreconditionFailed, ""},
+		{"foreign tags", `"abc"`, `{"name":"Hat","price":20}`, http.StatusPreconditionFailed, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("PUT", "/api/v1/products/1", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.etag == "" {
+				assert.Empty(t, w.Header().Get("ETag"))
+			} else {
+				assert.True(t, strings.HasPrefix(w.Header().Get("ETag"), tt.etag), w.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func setupVersionedRouter() (*gin.Engine, *MockProductService) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := &MockProductService{}
+	cursors, _ := cursor.NewCodec("test-secret", time.Minute)
+	handler := NewProductHandler(mockService, stubCurrencyService{"EUR": 0.5}, cursors, false, "en", slog.Default())
+
+	router := gin.New()
+	for _, version := range []int{middleware.APIVersion1, middleware.APIVersion2} {
+		products := router.Group(fmt.Sprintf("/api/v%d/products", version), middleware.Version(version))
+		products.GET("", handler.List)
+		products.GET("/:id", handler.Get)
+	}
+	return router, mockService
+}
+
+func TestProductHandler_V2ResponseShape(t *testing.T) {
+	router, mockService := setupVersionedRouter()
+
+	mockService.On("Get", mock.Anything, "1").Return(domain.Product{ID: "1", Name: "Hat", Price: domain.Money{Amount: 2000, Currency: "USD"}, Stock: 4}, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v2/products/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get(middleware.VersionHeader))
+	var body dto.ProductEnvelopeV2
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "Hat", body.Data.Name)
+	assert.Equal(t, int64(4), body.Data.Inventory.Stock)
+	assert.Equal(t, []string{}, body.Data.Tags)
+
+	// v1 keeps its flat shape
+	req, _ = http.NewRequest("GET", "/api/v1/products/1", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, "1", w.Header().Get(middleware.VersionHeader))
+	assert.NotContains(t, w.Body.String(), `"data"`)
+	assert.Contains(t, w.Body.String(), `"stock":4`)
+}
+
+func TestProductHandler_VersionFromAccept(t *testing.T) {
+	router, mockService := setupVersionedRouter()
+
+	mockService.On("ListWithFilters", mock.Anything, mock.Anything).Return(&ports.ProductListResult{
+		Products:   []domain.Product{{ID: "1", Name: "Hat", Price: domain.Money{Amount: 2000, Currency: "USD"}}},
+		TotalItems: 1,
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/products?name=hat", nil)
+	req.Header.Set("Accept", "application/vnd.products.v2+json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get(middleware.VersionHeader))
+	assert.Equal(t, "Accept", w.Header().Get("Vary"))
+	var body dto.ListProductsResponseV2
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body.Data, 1)
+	assert.Equal(t, 1, body.Meta.Pagination.TotalItems)
+	require.NotNil(t, body.Meta.Filters)
+	assert.Equal(t, "hat", body.Meta.Filters.Name)
+
+	req, _ = http.NewRequest("GET", "/api/v1/products", nil)
+	req.Header.Set("Accept", "application/vnd.products.v9+json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotAcceptable, w.Code)
+}
+
+func TestProductHandler_List_SparseFields(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	mockService.On("ListWithFilters", mock.Anything, mock.MatchedBy(func(filters ports.ProductFilters) bool {
+		return assert.ObjectsAreEqual([]string{"name", "price"}, filters.Fields)
+	})).Return(&ports.ProductListResult{
+		Products:   []domain.Product{{ID: "1", Name: "Hat", Price: domain.Money{Amount: 2000, Currency: "USD"}}},
+		TotalItems: 1,
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/products?fields=Name,price,name", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Products []map[string]json.RawMessage `json:"products"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body.Products, 1)
+	assert.Len(t, body.Products[0], 3)
+	assert.Contains(t, body.Products[0], "id")
+	assert.Contains(t, body.Products[0], "price")
+	assert.NotContains(t, body.Products[0], "stock")
+	assert.Contains(t, w.Body.String(), `"pagination"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_List_UnknownField(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	req, _ := http.NewRequest("GET", "/api/v1/products?fields=name,cost_price", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `unknown field \"cost_price\"`)
+	mockService.AssertNotCalled(t, "ListWithFilters", mock.Anything, mock.Anything)
+}
+
+func TestProductHandler_List_SparseFieldsV2(t *testing.T) {
+	router, mockService := setupVersionedRouter()
+
+	mockService.On("ListWithFilters", mock.Anything, mock.Anything).Return(&ports.ProductListResult{
+		Products:   []domain.Product{{ID: "1", Name: "Hat", Stock: 4}},
+		TotalItems: 1,
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v2/products?fields=stock", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"data":[{"id":"1","inventory":{"stock":4}}]`)
+}
+
+func TestProductHandler_List_Keyset(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	createdAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	mockService.On("ListWithFilters", mock.Anything, mock.MatchedBy(func(filters ports.ProductFilters) bool {
+		return filters.After != nil && *filters.After == ports.SortKey{Value: "2024-03-02T10:00:00Z", ID: "9"} && filters.Offset == 0
+	})).Return(&ports.ProductListResult{
+		Products:   []domain.Product{{ID: "8", Name: "Hat", CreatedAt: createdAt}},
+		TotalItems: 5,
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/products?limit=1&after_id=9&after_value=2024-03-02T12:00:00%2B02:00", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"has_prev":true`)
+	assert.Contains(t, w.Body.String(), `"next_after_id":"8","next_after_value":"2024-03-01T12:00:00Z"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_List_KeysetInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		error string
+	}{
+		{"missing id", "after_value=10&sort_by=price", "must be given together"},
+		{"with page", "after_id=1&after_value=10&sort_by=price&page=2", "cannot be combined"},
+		{"price not a number", "after_id=1&after_value=cheap&sort_by=price", "non-negative number"},
+		{"time not RFC 3339", "after_id=1&after_value=yesterday", "RFC 3339"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, mockService := setupTestRouter()
+
+			req, _ := http.NewRequest("GET", "/api/v1/products?"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), tt.error)
+			mockService.AssertNotCalled(t, "ListWithFilters", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestProductHandler_Count(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	mockService.On("Count", mock.Anything, ports.ProductFilters{CategoryID: "electronics", MinPrice: domain.NewDecimal(10, 0), Tags: []string{"sale"}}).Return(42, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/products/count?category_id=electronics&min_price=10&tags=sale&page=3", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"count":42}`, w.Body.String())
+	assert.Equal(t, "42", w.Header().Get("X-Total-Count"))
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_HeadList(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	mockService.On("Count", mock.Anything, ports.ProductFilters{Name: "lamp"}).Return(7, nil)
+
+	req, _ := http.NewRequest("HEAD", "/api/v1/products?name=lamp", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Equal(t, "7", w.Header().Get("X-Total-Count"))
+	mockService.AssertNotCalled(t, "ListWithFilters", mock.Anything, mock.Anything)
+}
+
+func TestProductHandler_Count_InvalidFilters(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	req, _ := http.NewRequest("GET", "/api/v1/products/count?min_price=20&max_price=10", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "Count", mock.Anything, mock.Anything)
+}
+
+func TestProductHandler_Get_HidesUnapprovedFromNonAdmins(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	pending := domain.Product{ID: "1", Name: "Hat", Price: domain.Money{Amount: 2000, Currency: "USD"}, ModerationStatus: domain.ModerationPendingReview}
+	mockService.On("Get", mock.Anything, "1").Return(pending, nil)
+	mockService.On("GetBySKU", mock.Anything, "HAT-1").Return(pending, nil)
+	mockService.On("GetMany", mock.Anything, []string{"1"}).Return([]domain.Product{pending}, []string{}, nil)
+
+	for _, path := range []string{"/api/v1/products/1", "/api/v1/products/by-sku/HAT-1"} {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code, path)
+
+		req, _ = http.NewRequest("GET", path, nil)
+		req.Header.Set(middleware.AdminKeyHeader, testAdminKey)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code, path)
+	}
+	assert.Equal(t, []string{"1"}, mockService.views, "only the admin read counts as a view")
+
+	req, _ := http.NewRequest("POST", "/api/v1/products/batch-get", bytes.NewBufferString(`{"ids":["1"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response dto.BatchGetResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Empty(t, response.Products)
+	assert.Equal(t, []string{"1"}, response.Missing)
+}
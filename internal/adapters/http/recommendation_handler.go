@@ -0,0 +1,71 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/dto"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/i18n"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+const (
+	defaultRecommendationLimit = 10
+	maxRecommendationLimit     = 50
+)
+
+type RecommendationHandler struct {
+	service ports.RecommendationService
+	logger  *slog.Logger
+}
+
+func NewRecommendationHandler(service ports.RecommendationService, logger *slog.Logger) *RecommendationHandler {
+	return &RecommendationHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RecommendedProductResponse is a product with its recommendation score
+type RecommendedProductResponse struct {
+	dto.ProductResponse
+	Score float64 `json:"score"`
+}
+
+// Recommendations returns products related to the given one
+func (h *RecommendationHandler) Recommendations(c *gin.Context) {
+	id := c.Param("id")
+	limit := defaultRecommendationLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxRecommendationLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "limit must be between 1 and 50")})
+			return
+		}
+		limit = parsed
+	}
+
+	recommended, err := h.service.Recommendations(c.Request.Context(), id, limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, err.Error())})
+			return
+		}
+		h.logger.ErrorContext(c.Request.Context(), "failed to get recommendations", "id", id, "error", err)
+		respondError(c, err)
+		return
+	}
+
+	response := make([]RecommendedProductResponse, len(recommended))
+	for i, item := range recommended {
+		response[i] = RecommendedProductResponse{
+			ProductResponse: dto.NewProductResponse(item.Product),
+			Score:           item.Score,
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"products": response})
+}
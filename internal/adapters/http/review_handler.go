@@ -0,0 +1,124 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/i18n"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+const (
+	defaultReviewLimit = 20
+	maxReviewLimit     = 100
+)
+
+type ReviewHandler struct {
+	service ports.ReviewService
+	logger  *slog.Logger
+}
+
+func NewReviewHandler(service ports.ReviewService, logger *slog.Logger) *ReviewHandler {
+	return &ReviewHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// ReviewRequest is the body of review creations and updates
+type ReviewRequest struct {
+	Rating  int    `json:"rating" binding:"required"`
+	Comment string `json:"comment"`
+}
+
+// List returns a page of the product's reviews, newest first, with its
+// rating summary
+func (h *ReviewHandler) List(c *gin.Context) {
+	id := c.Param("id")
+	limit := defaultReviewLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxReviewLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "limit must be between 1 and 100")})
+			return
+		}
+		limit = parsed
+	}
+
+	page, err := h.service.List(c.Request.Context(), id, c.Query("after"), limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+func (h *ReviewHandler) Get(c *gin.Context) {
+	review, err := h.service.Get(c.Request.Context(), c.Param("id"), c.Param("reviewId"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, review)
+}
+
+// Create records a review by the caller
+func (h *ReviewHandler) Create(c *gin.Context) {
+	var req ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, err.Error())})
+		return
+	}
+
+	review, err := h.service.Create(c.Request.Context(), c.Param("id"), ports.ReviewInput{Rating: req.Rating, Comment: req.Comment})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, review)
+}
+
+// Update replaces the rating and comment of one of the caller's reviews
+func (h *ReviewHandler) Update(c *gin.Context) {
+	var req ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, err.Error())})
+		return
+	}
+
+	review, err := h.service.Update(c.Request.Context(), c.Param("id"), c.Param("reviewId"), ports.ReviewInput{Rating: req.Rating, Comment: req.Comment})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, review)
+}
+
+// Delete removes one of the caller's reviews
+func (h *ReviewHandler) Delete(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), c.Param("id"), c.Param("reviewId")); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *ReviewHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound), errors.Is(err, domain.ErrReviewNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, err.Error())})
+	case errors.Is(err, domain.ErrNotReviewAuthor):
+		c.JSON(http.StatusForbidden, gin.H{"error": i18n.T(c, err.Error())})
+	case errors.Is(err, domain.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": i18n.T(c, "review was modified concurrently")})
+	case errors.Is(err, domain.ErrInvalidRating), errors.Is(err, domain.ErrCommentTooLong):
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, err.Error())})
+	default:
+		h.logger.ErrorContext(c.Request.Context(), "review request failed", "id", c.Param("id"), "error", err)
+		respondError(c, err)
+	}
+}
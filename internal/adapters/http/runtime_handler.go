@@ -0,0 +1,131 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/i18n"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/buildinfo"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/config"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/logger"
+)
+
+// RuntimeHandler serves the operator's view of the running process
+type RuntimeHandler struct {
+	config    func() *config.Config
+	startedAt time.Time
+	logger    *slog.Logger
+}
+
+// NewRuntimeHandler reports the configuration config returns, which follows
+// reloads
+func NewRuntimeHandler(config func() *config.Config, logger *slog.Logger) *RuntimeHandler {
+	return &RuntimeHandler{
+		config:    config,
+		startedAt: time.Now().UTC(),
+		logger:    logger,
+	}
+}
+
+type BuildResponse struct {
+	buildinfo.Info
+	StartedAt time.Time `json:"started_at"`
+	Uptime    string    `json:"uptime"`
+}
+
+type RuntimeStatsResponse struct {
+	Goroutines int    `json:"goroutines"`
+	CPUs       int    `json:"cpus"`
+	GOMAXPROCS int    `json:"gomaxprocs"`
+	Memory     Memory `json:"memory"`
+}
+
+// Memory figures are in bytes
+type Memory struct {
+	HeapAlloc    uint64 `json:"heap_alloc"`
+	HeapInuse    uint64 `json:"heap_inuse"`
+	HeapObjects  uint64 `json:"heap_objects"`
+	TotalAlloc   uint64 `json:"total_alloc"`
+	Sys          uint64 `json:"sys"`
+	NumGC        uint32 `json:"num_gc"`
+	GCPauseTotal string `json:"gc_pause_total"`
+}
+
+type LogLevelRequest struct {
+	Level string `json:"level" binding:"required,oneof=debug info warn error"`
+}
+
+type LogLevelResponse struct {
+	Level string `json:"level"`
+}
+
+// Config returns the running configuration with secrets masked
+func (h *RuntimeHandler) Config(c *gin.Context) {
+	c.JSON(http.StatusOK, h.config().Redacted())
+}
+
+// Build reports the version and commit the binary was built from, and how
+// long the process has been running
+func (h *RuntimeHandler) Build(c *gin.Context) {
+	c.JSON(http.StatusOK, BuildResponse{
+		Info:      buildinfo.Get(),
+		StartedAt: h.startedAt,
+		Uptime:    time.Since(h.startedAt).Truncate(time.Second).String(),
+	})
+}
+
+// Stats reports goroutine, memory and garbage collector figures. Reading
+// them briefly stops the world, so it is not meant for frequent polling;
+// /metrics serves that.
+func (h *RuntimeHandler) Stats(c *gin.Context) {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	c.JSON(http.StatusOK, RuntimeStatsResponse{
+		Goroutines: runtime.NumGoroutine(),
+		CPUs:       runtime.NumCPU(),
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+		Memory: Memory{
+			HeapAlloc:    stats.HeapAlloc,
+			HeapInuse:    stats.HeapInuse,
+			HeapObjects:  stats.HeapObjects,
+			TotalAlloc:   stats.TotalAlloc,
+			Sys:          stats.Sys,
+			NumGC:        stats.NumGC,
+			GCPauseTotal: time.Duration(stats.PauseTotalNs).String(),
+		},
+	})
+}
+
+// LogLevel returns the minimum level logged
+func (h *RuntimeHandler) LogLevel(c *gin.Context) {
+	c.JSON(http.StatusOK, LogLevelResponse{Level: logger.Level()})
+}
+
+// SetLogLevel changes the minimum level logged until the next restart or
+// configuration reload that changes LOG_LEVEL
+func (h *RuntimeHandler) SetLogLevel(c *gin.Context) {
+	var req LogLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, err.Error())})
+		return
+	}
+
+	previous := logger.Level()
+	logger.SetLevel(req.Level)
+	SetGinMode(req.Level)
+	h.logger.InfoContext(c.Request.Context(), "log level changed", "from", previous, "to", req.Level)
+	c.JSON(http.StatusOK, LogLevelResponse{Level: logger.Level()})
+}
+
+// SetGinMode runs gin in debug mode, printing its routes and warnings, only
+// while the service logs at debug level
+func SetGinMode(level string) {
+	if level == "debug" {
+		gin.SetMode(gin.DebugMode)
+	} else {
+		gin.SetMode(gin.ReleaseMode)
+	}
+}
@@ -0,0 +1,81 @@
+package http
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/config"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/logger"
+)
+
+func setupRuntimeRouter(cfg *config.Config) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewRuntimeHandler(func() *config.Config { return cfg }, slog.Default())
+	router := gin.New()
+	router.GET("/admin/config", handler.Config)
+	router.GET("/admin/build", handler.Build)
+	router.GET("/admin/runtime", handler.Stats)
+	router.GET("/admin/log-level", handler.LogLevel)
+	router.PUT("/admin/log-level", handler.SetLogLevel)
+	return router
+}
+
+func TestRuntimeHandler_ConfigIsRedacted(t *testing.T) {
+	router := setupRuntimeRouter(&config.Config{Port: "8080", AdminAPIKey: "admin-secret"})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/config", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "admin-secret")
+	var settings map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &settings))
+	assert.Equal(t, "8080", settings["Port"])
+	assert.Equal(t, "[REDACTED]", settings["AdminAPIKey"])
+}
+
+func TestRuntimeHandler_BuildAndStats(t *testing.T) {
+	router := setupRuntimeRouter(&config.Config{})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/build", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var build BuildResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &build))
+	assert.Equal(t, "dev", build.Version)
+	assert.True(t, strings.HasPrefix(build.GoVersion, "go"))
+	assert.False(t, build.StartedAt.IsZero())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/runtime", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var stats RuntimeStatsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+	assert.Positive(t, stats.Goroutines)
+	assert.Positive(t, stats.Memory.Sys)
+}
+
+func TestRuntimeHandler_LogLevel(t *testing.T) {
+	router := setupRuntimeRouter(&config.Config{})
+	t.Cleanup(func() { logger.SetLevel("info") })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/admin/log-level", strings.NewReader(`{"level":"debug"}`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"level":"debug"}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/log-level", nil))
+	assert.JSONEq(t, `{"level":"debug"}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/admin/log-level", strings.NewReader(`{"level":"verbose"}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
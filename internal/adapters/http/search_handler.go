@@ -0,0 +1,78 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/dto"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/i18n"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+type SearchHandler struct {
+	service ports.SearchService
+	logger  *slog.Logger
+}
+
+func NewSearchHandler(service ports.SearchService, logger *slog.Logger) *SearchHandler {
+	return &SearchHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// SearchResultResponse is a product with its relevance to the query and,
+// when the search backend provides them, the matching fragments by field
+type SearchResultResponse struct {
+	dto.ProductResponse
+	Score      float64             `json:"score"`
+	Highlights map[string][]string `json:"highlights,omitempty"`
+}
+
+// Search returns the products matching the q parameter, most relevant first
+func (h *SearchHandler) Search(c *gin.Context) {
+	query := c.Query("q")
+	limit := defaultSearchLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxSearchLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "limit must be between 1 and 100")})
+			return
+		}
+		limit = parsed
+	}
+
+	hits, err := h.service.Search(c.Request.Context(), ports.SearchQuery{Text: query, Limit: limit})
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidSearch) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, err.Error())})
+			return
+		}
+		h.logger.ErrorContext(c.Request.Context(), "failed to search products", "query", query, "error", err)
+		respondError(c, err)
+		return
+	}
+
+	response := make([]SearchResultResponse, len(hits))
+	for i, hit := range hits {
+		response[i] = SearchResultResponse{
+			ProductResponse: dto.NewProductResponse(hit.Product),
+			Score:           hit.Score,
+			Highlights:      hit.Highlights,
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"query":    query,
+		"products": response,
+		"count":    len(response),
+	})
+}
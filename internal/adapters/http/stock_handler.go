@@ -0,0 +1,74 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/i18n"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+type StockHandler struct {
+	service ports.StockService
+	logger  *slog.Logger
+}
+
+func NewStockHandler(service ports.StockService, logger *slog.Logger) *StockHandler {
+	return &StockHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+type AdjustStockRequest struct {
+	// Delta is added to the stock; negative values take stock out
+	Delta int64 `json:"delta" binding:"required"`
+}
+
+// Adjust atomically increments or decrements a product's stock
+func (h *StockHandler) Adjust(c *gin.Context) {
+	id := c.Param("id")
+	var req AdjustStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WarnContext(c.Request.Context(), "invalid request body", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, err.Error())})
+		return
+	}
+
+	level, err := h.service.Adjust(c.Request.Context(), id, req.Delta)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, err.Error())})
+		case errors.Is(err, domain.ErrInvalidStockAdjustment):
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, err.Error())})
+		case errors.Is(err, domain.ErrInsufficientStock):
+			c.JSON(http.StatusConflict, gin.H{"error": i18n.T(c, err.Error())})
+		default:
+			respondError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, level)
+}
+
+// Get returns a product's stock
+func (h *StockHandler) Get(c *gin.Context) {
+	id := c.Param("id")
+	level, err := h.service.Get(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, err.Error())})
+			return
+		}
+		h.logger.ErrorContext(c.Request.Context(), "failed to get stock", "id", id, "error", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, level)
+}
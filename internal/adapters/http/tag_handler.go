@@ -0,0 +1,32 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+type TagHandler struct {
+	service ports.TagService
+	logger  *slog.Logger
+}
+
+func NewTagHandler(service ports.TagService, logger *slog.Logger) *TagHandler {
+	return &TagHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *TagHandler) List(c *gin.Context) {
+	tags, err := h.service.ListTags(c.Request.Context())
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to list tags", "error", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tags": tags})
+}
@@ -0,0 +1,149 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/i18n"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// FieldError describes why one request field was rejected
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func init() {
+	// Report fields by the names clients send rather than Go field names
+	if engine, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		engine.RegisterTagNameFunc(requestFieldName)
+		engine.RegisterValidation("price", validPrice)
+		engine.RegisterValidation("nonnegative", nonNegative)
+		engine.RegisterValidation("currency", validCurrency)
+	}
+}
+
+// validPrice accepts a positive amount in a supported currency
+func validPrice(field validator.FieldLevel) bool {
+	price, ok := field.Field().Interface().(domain.Money)
+	return ok && price.Amount > 0 && price.Validate() == nil
+}
+
+// nonNegative accepts a decimal of at least 0
+func nonNegative(field validator.FieldLevel) bool {
+	value, ok := field.Field().Interface().(domain.Decimal)
+	return ok && value.Sign() >= 0
+}
+
+// validCurrency accepts a supported ISO 4217 code
+func validCurrency(field validator.FieldLevel) bool {
+	_, ok := domain.CurrencyExponent(field.Field().String())
+	return ok
+}
+
+// requestFieldName is the JSON or query parameter name of a struct field
+func requestFieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name := strings.Split(field.Tag.Get(tag), ",")[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// respondBindingError answers 400 with the field-level reasons a request
+// failed to bind, under the given error message, both in the language the
+// client accepts
+func respondBindingError(c *gin.Context, message string, err error) {
+	fields := fieldErrors(err)
+	for i := range fields {
+		fields[i].Message = i18n.T(c, fields[i].Message)
+	}
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error":  i18n.T(c, message),
+		"fields": fields,
+	})
+}
+
+// fieldErrors translates a binding error into field errors. Errors that do
+// not concern a single field, such as malformed JSON, yield one entry with
+// an empty field.
+func fieldErrors(err error) []FieldError {
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		fields := make([]FieldError, len(validationErrors))
+		for i, fieldErr := range validationErrors {
+			fields[i] = FieldError{
+				Field:   fieldErr.Field(),
+				Rule:    fieldErr.Tag(),
+				Message: ruleMessage(fieldErr),
+			}
+		}
+		return fields
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		field, message := typeErr.Field, fmt.Sprintf("%s must be a %s", typeErr.Field, typeErr.Type)
+		if typeErr.Type == reflect.TypeOf(domain.Money{}) {
+			// Money decodes itself, so the decoder does not report which
+			// field held it; price is the only one
+			if field == "" {
+				field = "price"
+			}
+			message = field + " must be a number or an object with amount and currency"
+		}
+		return []FieldError{{Field: field, Rule: "type", Message: message}}
+	}
+
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.Is(err, io.EOF):
+		return []FieldError{{Rule: "json", Message: "request body is required"}}
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return []FieldError{{Rule: "json", Message: "request body is not valid JSON"}}
+	}
+	return []FieldError{{Rule: "format", Message: err.Error()}}
+}
+
+func ruleMessage(fieldErr validator.FieldError) string {
+	field, param := fieldErr.Field(), fieldErr.Param()
+	switch fieldErr.Tag() {
+	case "required":
+		return field + " is required"
+	case "min":
+		return fmt.Sprintf("%s must be at least %s", field, param)
+	case "max":
+		return fmt.Sprintf("%s must be at most %s", field, param)
+	case "gt":
+		return fmt.Sprintf("%s must be greater than %s", field, param)
+	case "gte":
+		return fmt.Sprintf("%s must be at least %s", field, param)
+	case "lt":
+		return fmt.Sprintf("%s must be less than %s", field, param)
+	case "nonnegative":
+		return field + " must be at least 0"
+	case "currency":
+		return field + " must be a supported ISO 4217 code"
+	case "price":
+		return field + " must have an amount greater than 0 and a supported ISO 4217 currency"
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", field, strings.ReplaceAll(param, " ", ", "))
+	default:
+		return fmt.Sprintf("%s is invalid (%s)", field, fieldErr.Tag())
+	}
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/dto"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/i18n"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+const (
+	defaultTrendingLimit = 10
+	maxTrendingLimit     = 100
+	// sessionHeader identifies the browsing session a view belongs to
+	sessionHeader = "X-Session-ID"
+)
+
+type ViewHandler struct {
+	service ports.ViewService
+	logger  *slog.Logger
+}
+
+func NewViewHandler(service ports.ViewService, logger *slog.Logger) *ViewHandler {
+	return &ViewHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// TrendingProductResponse is a product with its views in the trending window
+type TrendingProductResponse struct {
+	dto.ProductResponse
+	Views int64 `json:"views"`
+}
+
+// RecordView counts one view of a product
+func (h *ViewHandler) RecordView(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.service.RecordView(c.Request.Context(), id, c.GetHeader(sessionHeader)); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, err.Error())})
+			return
+		}
+		h.logger.ErrorContext(c.Request.Context(), "failed to record view", "id", id, "error", err)
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// Trending returns the most viewed products of the latest rollup
+func (h *ViewHandler) Trending(c *gin.Context) {
+	limit := defaultTrendingLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxTrendingLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "limit must be between 1 and 100")})
+			return
+		}
+		limit = parsed
+	}
+
+	trending, err := h.service.Trending(c.Request.Context(), limit)
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to get trending products", "error", err)
+		respondError(c, err)
+		return
+	}
+
+	response := make([]TrendingProductResponse, len(trending))
+	for i, item := range trending {
+		response[i] = TrendingProductResponse{
+			ProductResponse: dto.NewProductResponse(item.Product),
+			Views:           item.Views,
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"products": response})
+}
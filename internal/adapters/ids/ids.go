@@ -0,0 +1,86 @@
+package ids
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// Strategies for generating product IDs. UUIDv7 and ULID start with the
+// creation time in milliseconds, so they sort by it and spread writes
+// less randomly than UUIDv4, which is fully random.
+const (
+	UUIDv4 = "uuidv4"
+	UUIDv7 = "uuidv7"
+	ULID   = "ulid"
+)
+
+type generator func() (string, error)
+
+func (g generator) NewID() (string, error) {
+	return g()
+}
+
+// New returns the generator of strategy, putting prefix, such as "prod_",
+// before every ID
+func New(strategy, prefix string) (ports.IDGenerator, error) {
+	var next generator
+	switch strategy {
+	case UUIDv4, "":
+		next = func() (string, error) { return uuid.NewString(), nil }
+	case UUIDv7:
+		next = func() (string, error) {
+			id, err := uuid.NewV7()
+			if err != nil {
+				return "", err
+			}
+			return id.String(), nil
+		}
+	case ULID:
+		next = func() (string, error) { return newULID(time.Now()) }
+	default:
+		return nil, fmt.Errorf("unknown ID strategy %q", strategy)
+	}
+	if prefix == "" {
+		return next, nil
+	}
+	return generator(func() (string, error) {
+		id, err := next()
+		if err != nil {
+			return "", err
+		}
+		return prefix + id, nil
+	}), nil
+}
+
+// crockford is the base32 alphabet of ULIDs, without I, L, O and U
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// newULID encodes the 48-bit Unix time in milliseconds of now followed by
+// 80 random bits as 26 Crockford base32 characters
+func newULID(now time.Time) (string, error) {
+	var raw [16]byte
+	ms := uint64(now.UnixMilli())
+	for i := range 6 {
+		raw[i] = byte(ms >> (40 - 8*i))
+	}
+	if _, err := rand.Read(raw[6:]); err != nil {
+		return "", fmt.Errorf("failed to read random bits: %w", err)
+	}
+
+	// 128 bits are 26 characters of 5 bits, the first holding only 3
+	var id [26]byte
+	hi := uint64(raw[0])<<56 | uint64(raw[1])<<48 | uint64(raw[2])<<40 | uint64(raw[3])<<32 |
+		uint64(raw[4])<<24 | uint64(raw[5])<<16 | uint64(raw[6])<<8 | uint64(raw[7])
+	lo := uint64(raw[8])<<56 | uint64(raw[9])<<48 | uint64(raw[10])<<40 | uint64(raw[11])<<32 |
+		uint64(raw[12])<<24 | uint64(raw[13])<<16 | uint64(raw[14])<<8 | uint64(raw[15])
+	for i := 25; i >= 0; i-- {
+		id[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(id[:]), nil
+}
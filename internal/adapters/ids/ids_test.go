@@ -0,0 +1,45 @@
+package ids
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_Strategies(t *testing.T) {
+	tests := []struct {
+		strategy string
+		prefix   string
+		pattern  string
+	}{
+		{UUIDv4, "", `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+		{UUIDv7, "", `^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+		{ULID, "prod_", `^prod_[0-9A-HJKMNP-TV-Z]{26}$`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			generator, err := New(tt.strategy, tt.prefix)
+			require.NoError(t, err)
+			id, err := generator.NewID()
+			require.NoError(t, err)
+			assert.Regexp(t, regexp.MustCompile(tt.pattern), id)
+		})
+	}
+
+	_, err := New("snowflake", "")
+	assert.Error(t, err)
+}
+
+func TestNewULID_SortsByTime(t *testing.T) {
+	// The timestamp example of the ULID specification
+	id, err := newULID(time.UnixMilli(1469918176385))
+	require.NoError(t, err)
+	assert.Equal(t, "01ARYZ6S41", id[:10])
+
+	later, err := newULID(time.UnixMilli(1469918176386))
+	require.NoError(t, err)
+	assert.Less(t, id, later)
+}
@@ -0,0 +1,72 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/comprehend"
+	"github.com/aws/aws-sdk-go-v2/service/comprehend/types"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// ComprehendModerator screens text with Amazon Comprehend toxicity
+// detection. Segments scoring at least rejectThreshold are rejected, and at
+// least flagThreshold are held for review.
+type ComprehendModerator struct {
+	client          *comprehend.Client
+	rejectThreshold float32
+	flagThreshold   float32
+}
+
+func NewComprehendModerator(client *comprehend.Client, rejectThreshold, flagThreshold float64) *ComprehendModerator {
+	return &ComprehendModerator{
+		client:          client,
+		rejectThreshold: float32(rejectThreshold),
+		flagThreshold:   float32(flagThreshold),
+	}
+}
+
+func (m *ComprehendModerator) Screen(ctx context.Context, name, description string) (domain.ModerationVerdict, error) {
+	segments := []types.TextSegment{{Text: aws.String(name)}}
+	if description != "" {
+		segments = append(segments, types.TextSegment{Text: aws.String(description)})
+	}
+
+	output, err := m.client.DetectToxicContent(ctx, &comprehend.DetectToxicContentInput{
+		LanguageCode: types.LanguageCodeEn,
+		TextSegments: segments,
+	})
+	if err != nil {
+		return domain.ModerationVerdict{}, fmt.Errorf("failed to detect toxic content: %w", err)
+	}
+
+	var toxicity float32
+	var reasons []string
+	for _, result := range output.ResultList {
+		toxicity = max(toxicity, aws.ToFloat32(result.Toxicity))
+		for _, label := range result.Labels {
+			if score := aws.ToFloat32(label.Score); score >= m.flagThreshold {
+				reasons = append(reasons, fmt.Sprintf("%s (%.2f)", label.Name, score))
+			}
+		}
+	}
+
+	switch {
+	case toxicity >= m.rejectThreshold:
+		return domain.ModerationVerdict{Decision: domain.DecisionReject, Reasons: withToxicity(reasons, toxicity)}, nil
+	case toxicity >= m.flagThreshold:
+		return domain.ModerationVerdict{Decision: domain.DecisionFlag, Reasons: withToxicity(reasons, toxicity)}, nil
+	default:
+		return domain.ModerationVerdict{Decision: domain.DecisionAllow}, nil
+	}
+}
+
+// withToxicity makes sure a verdict explains itself even when no single
+// label crossed the threshold
+func withToxicity(reasons []string, toxicity float32) []string {
+	if len(reasons) > 0 {
+		return reasons
+	}
+	return []string{fmt.Sprintf("TOXICITY (%.2f)", toxicity)}
+}
@@ -0,0 +1,66 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// WordlistModerator rejects text containing a blocked term and flags text
+// containing a flagged one. Terms may span several words and are matched on
+// whole words, ignoring case and punctuation.
+type WordlistModerator struct {
+	blocked []string
+	flagged []string
+}
+
+func NewWordlistModerator(blocked, flagged []string) *WordlistModerator {
+	return &WordlistModerator{
+		blocked: normalizeTerms(blocked),
+		flagged: normalizeTerms(flagged),
+	}
+}
+
+func (m *WordlistModerator) Screen(ctx context.Context, name, description string) (domain.ModerationVerdict, error) {
+	text := " " + strings.Join(words(name+" "+description), " ") + " "
+
+	if reasons := matchTerms(text, m.blocked, "blocked"); len(reasons) > 0 {
+		return domain.ModerationVerdict{Decision: domain.DecisionReject, Reasons: reasons}, nil
+	}
+	if reasons := matchTerms(text, m.flagged, "flagged"); len(reasons) > 0 {
+		return domain.ModerationVerdict{Decision: domain.DecisionFlag, Reasons: reasons}, nil
+	}
+	return domain.ModerationVerdict{Decision: domain.DecisionAllow}, nil
+}
+
+// matchTerms looks for each term in text, which is padded with spaces so
+// that only whole words match
+func matchTerms(text string, terms []string, kind string) []string {
+	var reasons []string
+	for _, term := range terms {
+		if strings.Contains(text, " "+term+" ") {
+			reasons = append(reasons, fmt.Sprintf("%s term %q", kind, term))
+		}
+	}
+	return reasons
+}
+
+func normalizeTerms(terms []string) []string {
+	normalized := make([]string, 0, len(terms))
+	for _, term := range terms {
+		if w := words(term); len(w) > 0 {
+			normalized = append(normalized, strings.Join(w, " "))
+		}
+	}
+	return normalized
+}
+
+// words lower-cases text and splits it on anything but letters and digits
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,37 @@
+package moderation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+func TestWordlistModerator(t *testing.T) {
+	moderator := NewWordlistModerator([]string{"Scam", "fake id"}, []string{"replica"})
+
+	tests := []struct {
+		name        string
+		productName string
+		description string
+		decision    string
+	}{
+		{"clean", "Laptop Pro", "Fast and light", domain.DecisionAllow},
+		{"blocked word any case", "Laptop", "Totally not a SCAM!", domain.DecisionReject},
+		{"blocked phrase", "Fake-ID kit", "", domain.DecisionReject},
+		{"flagged word", "Replica watch", "", domain.DecisionFlag},
+		{"substring does not match", "Scampi", "Replicable results", domain.DecisionAllow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict, err := moderator.Screen(context.Background(), tt.productName, tt.description)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.decision, verdict.Decision)
+			if tt.decision != domain.DecisionAllow {
+				assert.NotEmpty(t, verdict.Reasons)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package notifier
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// LogNotifier writes notifications to the log instead of sending them, for
+// local runs without an SES sender
+type LogNotifier struct {
+	logger *slog.Logger
+}
+
+func NewLogNotifier(logger *slog.Logger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+func (n *LogNotifier) Send(ctx context.Context, notification domain.Notification) error {
+	n.logger.InfoContext(ctx, "notification",
+		"rule", notification.Rule,
+		"to", notification.To,
+		"subject", notification.Subject,
+		"body", notification.Body,
+	)
+	return nil
+}
@@ -0,0 +1,44 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2/types"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// SESNotifier sends notifications as plain-text email through Amazon SES.
+// The from address must be a verified SES identity.
+type SESNotifier struct {
+	client *sesv2.Client
+	from   string
+}
+
+func NewSESNotifier(client *sesv2.Client, from string) *SESNotifier {
+	return &SESNotifier{
+		client: client,
+		from:   from,
+	}
+}
+
+func (n *SESNotifier) Send(ctx context.Context, notification domain.Notification) error {
+	_, err := n.client.SendEmail(ctx, &sesv2.SendEmailInput{
+		FromEmailAddress: aws.String(n.from),
+		Destination:      &types.Destination{ToAddresses: notification.To},
+		Content: &types.EmailContent{
+			Simple: &types.Message{
+				Subject: &types.Content{Data: aws.String(notification.Subject), Charset: aws.String("UTF-8")},
+				Body: &types.Body{
+					Text: &types.Content{Data: aws.String(notification.Body), Charset: aws.String("UTF-8")},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send email for rule %q: %w", notification.Rule, err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package pricing
+
+import (
+	"sync"
+	"time"
+)
+
+// circuitBreaker stops calling a service that keeps failing. After
+// maxFailures failures in a row it opens and refuses calls for cooldown;
+// then it lets a single trial call through, which closes it on success and
+// opens it again on failure.
+type circuitBreaker struct {
+	mu          sync.Mutex
+	maxFailures int
+	cooldown    time.Duration
+	failures    int
+	openedAt    time.Time
+	// trial is set while the call let through after the cooldown runs
+	trial bool
+	now   func() time.Time
+}
+
+func newCircuitBreaker(maxFailures int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		maxFailures: maxFailures,
+		cooldown:    cooldown,
+		now:         time.Now,
+	}
+}
+
+// allow reports whether a call may be made now. Every allowed call must be
+// followed by done.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.maxFailures {
+		return true
+	}
+	if b.trial || b.now().Sub(b.openedAt) < b.cooldown {
+		return false
+	}
+	b.trial = true
+	return true
+}
+
+// done records the outcome of an allowed call
+func (b *circuitBreaker) done(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+	if !failed {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.maxFailures {
+		b.openedAt = b.now()
+	}
+}
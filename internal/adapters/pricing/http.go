@@ -0,0 +1,140 @@
+// Package pricing asks an external service for product price suggestions.
+package pricing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// maxResponseSize bounds the suggestion responses read
+const maxResponseSize = 1 << 20
+
+// suggestionRequest is what the pricing service is told about a product.
+// The cost price stays confidential.
+type suggestionRequest struct {
+	ID          string       `json:"id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Price       domain.Money `json:"price"`
+	CategoryID  string       `json:"category_id,omitempty"`
+	Tags        []string     `json:"tags,omitempty"`
+	Stock       int64        `json:"stock"`
+}
+
+type suggestionResponse struct {
+	Price      *domain.Money `json:"price"`
+	Confidence *float64      `json:"confidence"`
+	Reason     string        `json:"reason"`
+}
+
+// HTTPPriceSuggester POSTs each product as JSON to a pricing service and
+// reads back {"price": ..., "confidence": ..., "reason": ...}, the price
+// in the shape products take. A 404 means the service has no suggestion.
+// Failures to reach the service open a circuit breaker, so a service that
+// is down is not waited on by every request.
+type HTTPPriceSuggester struct {
+	client  *http.Client
+	url     string
+	token   string
+	breaker *circuitBreaker
+	now     func() time.Time
+}
+
+// NewHTTPPriceSuggester calls the service at url, sending token as a bearer
+// token unless it is empty. Each call is given up after timeout, and
+// maxFailures failures in a row stop the calls for cooldown.
+func NewHTTPPriceSuggester(url, token string, timeout time.Duration, maxFailures int, cooldown time.Duration) *HTTPPriceSuggester {
+	return &HTTPPriceSuggester{
+		client:  &http.Client{Timeout: timeout},
+		url:     url,
+		token:   token,
+		breaker: newCircuitBreaker(maxFailures, cooldown),
+		now:     time.Now,
+	}
+}
+
+func (s *HTTPPriceSuggester) SuggestPrice(ctx context.Context, product domain.Product) (domain.PriceSuggestion, error) {
+	if !s.breaker.allow() {
+		return domain.PriceSuggestion{}, domain.ErrPricingUnavailable.Wrap(errors.New("circuit breaker is open"))
+	}
+	response, err := s.call(ctx, product)
+	// Only the service's own failures count against it, not a caller
+	// that gave up
+	s.breaker.done(errors.Is(err, domain.ErrPricingUnavailable) && ctx.Err() == nil)
+	if err != nil {
+		return domain.PriceSuggestion{}, err
+	}
+
+	return domain.PriceSuggestion{
+		ProductID:      product.ID,
+		CurrentPrice:   product.Price,
+		SuggestedPrice: *response.Price,
+		Confidence:     response.Confidence,
+		Reason:         response.Reason,
+		SuggestedAt:    s.now().UTC(),
+	}, nil
+}
+
+// call sends one request, classifying every failure of the service as
+// domain.ErrPricingUnavailable
+func (s *HTTPPriceSuggester) call(ctx context.Context, product domain.Product) (suggestionResponse, error) {
+	body, err := json.Marshal(suggestionRequest{
+		ID:          product.ID,
+		Name:        product.Name,
+		Description: product.Description,
+		Price:       product.Price,
+		CategoryID:  product.CategoryID,
+		Tags:        product.Tags,
+		Stock:       product.Stock,
+	})
+	if err != nil {
+		return suggestionResponse{}, fmt.Errorf("failed to encode price suggestion request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return suggestionResponse{}, fmt.Errorf("failed to build price suggestion request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return suggestionResponse{}, domain.ErrPricingUnavailable.Wrap(err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return suggestionResponse{}, domain.ErrPricingUnavailable.Wrap(err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return suggestionResponse{}, domain.ErrNoPriceSuggestion
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return suggestionResponse{}, domain.ErrPricingUnavailable.Wrap(fmt.Errorf("pricing service returned %d: %s", resp.StatusCode, bytes.TrimSpace(data)))
+	}
+
+	var response suggestionResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return suggestionResponse{}, domain.ErrPricingUnavailable.Wrap(fmt.Errorf("failed to decode price suggestion: %w", err))
+	}
+	if response.Price == nil {
+		return suggestionResponse{}, domain.ErrPricingUnavailable.Wrap(errors.New("price suggestion has no price"))
+	}
+	if err := response.Price.Validate(); err != nil {
+		return suggestionResponse{}, domain.ErrPricingUnavailable.Wrap(fmt.Errorf("invalid suggested price: %w", err))
+	}
+	return response, nil
+}
@@ -0,0 +1,89 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+var laptop = domain.Product{
+	ID:        "prod-1",
+	Name:      "Laptop",
+	Price:     domain.Money{Amount: 99900, Currency: "USD"},
+	CostPrice: &domain.Money{Amount: 70000, Currency: "USD"},
+}
+
+func TestHTTPPriceSuggester_Suggest(t *testing.T) {
+	var authorization string
+	var request map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(t, json.Unmarshal(body, &request))
+		if request["id"] == "unknown" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"price":{"amount":94900,"currency":"usd"},"confidence":0.8,"reason":"competitors dropped their prices"}`))
+	}))
+	defer server.Close()
+
+	suggester := NewHTTPPriceSuggester(server.URL, "secret", time.Second, 3, time.Minute)
+	suggestion, err := suggester.SuggestPrice(context.Background(), laptop)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Bearer secret", authorization)
+	assert.Equal(t, "Laptop", request["name"])
+	assert.NotContains(t, request, "cost_price")
+	assert.Equal(t, "prod-1", suggestion.ProductID)
+	assert.Equal(t, laptop.Price, suggestion.CurrentPrice)
+	assert.Equal(t, domain.Money{Amount: 94900, Currency: "USD"}, suggestion.SuggestedPrice)
+	require.NotNil(t, suggestion.Confidence)
+	assert.Equal(t, 0.8, *suggestion.Confidence)
+	assert.Equal(t, "competitors dropped their prices", suggestion.Reason)
+
+	_, err = suggester.SuggestPrice(context.Background(), domain.Product{ID: "unknown"})
+	assert.ErrorIs(t, err, domain.ErrNoPriceSuggestion)
+}
+
+func TestHTTPPriceSuggester_CircuitBreaker(t *testing.T) {
+	var calls, healthy atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if healthy.Load() == 0 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"price":949.00}`))
+	}))
+	defer server.Close()
+
+	suggester := NewHTTPPriceSuggester(server.URL, "", time.Second, 2, time.Minute)
+	now := time.Date(2024, 8, 1, 12, 0, 0, 0, time.UTC)
+	suggester.breaker.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		_, err := suggester.SuggestPrice(context.Background(), laptop)
+		assert.ErrorIs(t, err, domain.ErrPricingUnavailable)
+	}
+	assert.EqualValues(t, 2, calls.Load(), "the breaker opens after two failures")
+
+	// After the cooldown a trial call goes through and closes it again
+	healthy.Store(1)
+	now = now.Add(time.Minute)
+	suggestion, err := suggester.SuggestPrice(context.Background(), laptop)
+	require.NoError(t, err)
+	assert.Equal(t, domain.Money{Amount: 94900, Currency: "USD"}, suggestion.SuggestedPrice)
+	_, err = suggester.SuggestPrice(context.Background(), laptop)
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, calls.Load())
+}
@@ -0,0 +1,243 @@
+// Package queue consumes SQS messages and hands them to the core services.
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+const (
+	// maxBatch is the most messages SQS returns per receive
+	maxBatch = 10
+	// longPollSeconds keeps receives open until messages arrive
+	longPollSeconds = 20
+	// retryDelay is how long a message that failed transiently stays hidden
+	// before it is retried
+	retryDelay = 30 * time.Second
+	// errorAttribute records why a message was moved to the DLQ
+	errorAttribute = "error"
+)
+
+// SQSAPI is the subset of the SQS client used by the consumer
+type SQSAPI interface {
+	ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error)
+	DeleteMessage(ctx context.Context, params *sqs.DeleteMessageInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error)
+	ChangeMessageVisibility(ctx context.Context, params *sqs.ChangeMessageVisibilityInput, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityOutput, error)
+	SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error)
+}
+
+// Handler processes one message body. Errors wrapped with Permanent are
+// never retried; any other error is retried until the queue's redrive
+// policy moves the message to its dead-letter queue.
+type Handler interface {
+	Handle(ctx context.Context, body string) error
+}
+
+// PermanentError marks a message that can never succeed, such as one that
+// fails validation
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string { return e.Err.Error() }
+
+func (e *PermanentError) Unwrap() error { return e.Err }
+
+// Permanent wraps err so the consumer gives up on the message at once
+func Permanent(err error) error {
+	return &PermanentError{Err: err}
+}
+
+// Consumer long-polls a queue and runs up to concurrency handlers at a time.
+// While a handler runs, the message's visibility timeout is extended so no
+// other worker picks it up.
+type Consumer struct {
+	client            SQSAPI
+	queueURL          string
+	dlqURL            string
+	handler           Handler
+	concurrency       int
+	visibilityTimeout time.Duration
+	logger            *slog.Logger
+}
+
+// NewConsumer reads from queueURL. Permanently failing messages are sent to
+// dlqURL when set; otherwise they are left to the queue's redrive policy.
+func NewConsumer(client SQSAPI, queueURL, dlqURL string, handler Handler, concurrency int, visibilityTimeout time.Duration, logger *slog.Logger) *Consumer {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	// The heartbeat renews at half the timeout, in whole seconds
+	if visibilityTimeout < 2*time.Second {
+		visibilityTimeout = 2 * time.Second
+	}
+	return &Consumer{
+		client:            client,
+		queueURL:          queueURL,
+		dlqURL:            dlqURL,
+		handler:           handler,
+		concurrency:       concurrency,
+		visibilityTimeout: visibilityTimeout,
+		logger:            logger,
+	}
+}
+
+// Run consumes messages until ctx is done, then waits for the messages in
+// flight to finish
+func (c *Consumer) Run(ctx context.Context) {
+	slots := make(chan struct{}, c.concurrency)
+	var inFlight sync.WaitGroup
+	defer inFlight.Wait()
+
+	for ctx.Err() == nil {
+		// Only ask for as many messages as there are free handlers, so none
+		// sit received but unprocessed while their visibility runs out
+		slots <- struct{}{}
+		free := 1
+	fill:
+		for free < c.concurrency && free < maxBatch {
+			select {
+			case slots <- struct{}{}:
+				free++
+			default:
+				break fill
+			}
+		}
+
+		messages, err := c.receive(ctx, free)
+		for i := len(messages); i < free; i++ {
+			<-slots
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				c.logger.ErrorContext(ctx, "failed to receive messages", "queue", c.queueURL, "error", err)
+				sleep(ctx, time.Second)
+			}
+			continue
+		}
+
+		for _, message := range messages {
+			inFlight.Add(1)
+			go func(message types.Message) {
+				defer inFlight.Done()
+				defer func() { <-slots }()
+				// Messages already received are finished even during shutdown
+				c.process(context.WithoutCancel(ctx), message)
+			}(message)
+		}
+	}
+}
+
+func (c *Consumer) receive(ctx context.Context, max int) ([]types.Message, error) {
+	result, err := c.client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+		QueueUrl:                    aws.String(c.queueURL),
+		MaxNumberOfMessages:         int32(max),
+		WaitTimeSeconds:             longPollSeconds,
+		VisibilityTimeout:           int32(c.visibilityTimeout.Seconds()),
+		MessageSystemAttributeNames: []types.MessageSystemAttributeName{types.MessageSystemAttributeNameApproximateReceiveCount},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.Messages, nil
+}
+
+// process runs the handler and settles the message: deleted on success,
+// moved to the DLQ on a permanent failure, retried later otherwise
+func (c *Consumer) process(ctx context.Context, message types.Message) {
+	id := aws.ToString(message.MessageId)
+	logger := c.logger.With("message_id", id, "receive_count", message.Attributes[string(types.MessageSystemAttributeNameApproximateReceiveCount)])
+
+	heartbeatCtx, stopHeartbeat := context.WithCancel(ctx)
+	go c.heartbeat(heartbeatCtx, message, logger)
+	err := c.handler.Handle(ctx, aws.ToString(message.Body))
+	stopHeartbeat()
+
+	var permanent *PermanentError
+	switch {
+	case err == nil:
+		c.delete(ctx, message, logger)
+	case errors.As(err, &permanent) && c.dlqURL != "":
+		logger.WarnContext(ctx, "message cannot be processed, moving to dead-letter queue", "error", err)
+		if dlqErr := c.deadLetter(ctx, message, err); dlqErr != nil {
+			logger.ErrorContext(ctx, "failed to move message to dead-letter queue", "error", dlqErr)
+			return
+		}
+		c.delete(ctx, message, logger)
+	case errors.As(err, &permanent):
+		// Make it visible again at once so the redrive policy moves it to
+		// the DLQ after the fewest receives
+		logger.WarnContext(ctx, "message cannot be processed", "error", err)
+		c.changeVisibility(ctx, message, 0, logger)
+	default:
+		logger.ErrorContext(ctx, "failed to process message, will retry", "error", err)
+		c.changeVisibility(ctx, message, retryDelay, logger)
+	}
+}
+
+// heartbeat extends the message's visibility at half the timeout until ctx
+// is done, so slow handlers keep their message
+func (c *Consumer) heartbeat(ctx context.Context, message types.Message, logger *slog.Logger) {
+	ticker := time.NewTicker(c.visibilityTimeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.changeVisibility(ctx, message, c.visibilityTimeout, logger)
+		}
+	}
+}
+
+func (c *Consumer) delete(ctx context.Context, message types.Message, logger *slog.Logger) {
+	_, err := c.client.DeleteMessage(ctx, &sqs.DeleteMessageInput{
+		QueueUrl:      aws.String(c.queueURL),
+		ReceiptHandle: message.ReceiptHandle,
+	})
+	if err != nil {
+		// The message comes back after its visibility timeout and is
+		// processed again
+		logger.ErrorContext(ctx, "failed to delete processed message", "error", err)
+	}
+}
+
+func (c *Consumer) changeVisibility(ctx context.Context, message types.Message, timeout time.Duration, logger *slog.Logger) {
+	_, err := c.client.ChangeMessageVisibility(ctx, &sqs.ChangeMessageVisibilityInput{
+		QueueUrl:          aws.String(c.queueURL),
+		ReceiptHandle:     message.ReceiptHandle,
+		VisibilityTimeout: int32(timeout.Seconds()),
+	})
+	if err != nil && ctx.Err() == nil {
+		logger.WarnContext(ctx, "failed to change message visibility", "error", err)
+	}
+}
+
+func (c *Consumer) deadLetter(ctx context.Context, message types.Message, cause error) error {
+	_, err := c.client.SendMessage(ctx, &sqs.SendMessageInput{
+		QueueUrl:    aws.String(c.dlqURL),
+		MessageBody: message.Body,
+		MessageAttributes: map[string]types.MessageAttributeValue{
+			errorAttribute: {DataType: aws.String("String"), StringValue: aws.String(cause.Error())},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send message to %s: %w", c.dlqURL, err)
+	}
+	return nil
+}
+
+func sleep(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
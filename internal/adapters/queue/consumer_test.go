@@ -0,0 +1,160 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSQS struct {
+	mu         sync.Mutex
+	queue      []types.Message
+	deleted    []string
+	visibility map[string]int32
+	deadLetter []string
+}
+
+func (f *fakeSQS) ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	f.mu.Lock()
+	if len(f.queue) == 0 {
+		f.mu.Unlock()
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	n := min(int(params.MaxNumberOfMessages), len(f.queue))
+	messages := f.queue[:n]
+	f.queue = f.queue[n:]
+	f.mu.Unlock()
+	return &sqs.ReceiveMessageOutput{Messages: messages}, nil
+}
+
+func (f *fakeSQS) DeleteMessage(ctx context.Context, params *sqs.DeleteMessageInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deleted = append(f.deleted, aws.ToString(params.ReceiptHandle))
+	return &sqs.DeleteMessageOutput{}, nil
+}
+
+func (f *fakeSQS) ChangeMessageVisibility(ctx context.Context, params *sqs.ChangeMessageVisibilityInput, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.visibility[aws.ToString(params.ReceiptHandle)] = params.VisibilityTimeout
+	return &sqs.ChangeMessageVisibilityOutput{}, nil
+}
+
+func (f *fakeSQS) SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deadLetter = append(f.deadLetter, aws.ToString(params.MessageBody))
+	return &sqs.SendMessageOutput{}, nil
+}
+
+// handlerFunc answers each body with the error mapped to it
+type handlerFunc func(ctx context.Context, body string) error
+
+func (f handlerFunc) Handle(ctx context.Context, body string) error { return f(ctx, body) }
+
+func message(body string) types.Message {
+	return types.Message{MessageId: aws.String(body), ReceiptHandle: aws.String(body), Body: aws.String(body)}
+}
+
+func TestConsumer_SettlesMessages(t *testing.T) {
+	outcomes := map[string]error{
+		"ok":        nil,
+		"invalid":   Permanent(errors.New("invalid product")),
+		"throttled": errors.New("throttled"),
+	}
+	handler := handlerFunc(func(ctx context.Context, body string) error { return outcomes[body] })
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	tests := []struct {
+		name       string
+		dlqURL     string
+		deleted    []string
+		visibility map[string]int32
+		deadLetter []string
+	}{
+		{
+			name:       "with dead-letter queue",
+			dlqURL:     "https://sqs/imports-dlq",
+			deleted:    []string{"invalid", "ok"},
+			visibility: map[string]int32{"throttled": 30},
+			deadLetter: []string{"invalid"},
+		},
+		{
+			name:       "redrive policy only",
+			deleted:    []string{"ok"},
+			visibility: map[string]int32{"invalid": 0, "throttled": 30},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeSQS{
+				queue:      []types.Message{message("ok"), message("invalid"), message("throttled")},
+				visibility: map[string]int32{},
+			}
+			consumer := NewConsumer(client, "https://sqs/imports", tt.dlqURL, handler, 2, 30*time.Second, logger)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan struct{})
+			go func() {
+				consumer.Run(ctx)
+				close(done)
+			}()
+			require.Eventually(t, func() bool {
+				client.mu.Lock()
+				defer client.mu.Unlock()
+				return len(client.queue) == 0 && len(client.deleted)+len(client.visibility) == 3
+			}, time.Second, 5*time.Millisecond)
+			cancel()
+			<-done
+
+			assert.ElementsMatch(t, tt.deleted, client.deleted)
+			assert.Equal(t, tt.visibility, client.visibility)
+			assert.Equal(t, tt.deadLetter, client.deadLetter)
+		})
+	}
+}
+
+func TestConsumer_ExtendsVisibilityWhileProcessing(t *testing.T) {
+	client := &fakeSQS{visibility: map[string]int32{}}
+	release := make(chan struct{})
+	handler := handlerFunc(func(ctx context.Context, body string) error {
+		<-release
+		return nil
+	})
+	consumer := NewConsumer(client, "https://sqs/imports", "", handler, 1, 2*time.Second, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	done := make(chan struct{})
+	go func() {
+		consumer.process(context.Background(), message("slow"))
+		close(done)
+	}()
+	require.Eventually(t, func() bool {
+		client.mu.Lock()
+		defer client.mu.Unlock()
+		return client.visibility["slow"] == 2
+	}, 3*time.Second, 10*time.Millisecond)
+	close(release)
+	<-done
+
+	assert.Equal(t, []string{"slow"}, client.deleted)
+}
+
+func TestProductImportHandler_InvalidMessageIsPermanent(t *testing.T) {
+	handler := NewProductImportHandler(nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	var permanent *PermanentError
+	assert.ErrorAs(t, handler.Handle(context.Background(), "{not json"), &permanent)
+	assert.ErrorAs(t, handler.Handle(context.Background(), `{"name":"Lamp","tenant_id":"a#b"}`), &permanent)
+}
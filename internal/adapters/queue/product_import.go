@@ -0,0 +1,89 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// ProductImportMessage is the body of a product-creation message, with the
+// same fields as POST /api/v1/products
+type ProductImportMessage struct {
+	// ID makes redelivered messages create the product only once
+	ID            string          `json:"id"`
+	Name          string          `json:"name"`
+	Description   string          `json:"description"`
+	Price         domain.Money    `json:"price"`
+	ExpiresAt     *time.Time      `json:"expires_at"`
+	PublishAt     *time.Time      `json:"publish_at"`
+	AutoArchiveAt *time.Time      `json:"auto_archive_at"`
+	CostPrice     *domain.Decimal `json:"cost_price"`
+	CategoryID    string          `json:"category_id"`
+	// TenantID creates the product for that tenant; empty uses the default
+	TenantID string `json:"tenant_id"`
+}
+
+// importActor is recorded in the audit log as the creator of imported
+// products
+const importActor = "product-import"
+
+// ProductImportHandler creates one product per message
+type ProductImportHandler struct {
+	service ports.ProductService
+	logger  *slog.Logger
+}
+
+func NewProductImportHandler(service ports.ProductService, logger *slog.Logger) *ProductImportHandler {
+	return &ProductImportHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *ProductImportHandler) Handle(ctx context.Context, body string) error {
+	var message ProductImportMessage
+	if err := json.Unmarshal([]byte(body), &message); err != nil {
+		return Permanent(fmt.Errorf("invalid product message: %w", err))
+	}
+	if message.TenantID != "" {
+		if err := domain.ValidateTenantID(message.TenantID); err != nil {
+			return Permanent(err)
+		}
+		ctx = ports.WithTenant(ctx, message.TenantID)
+	}
+	ctx = ports.WithActor(ctx, importActor)
+
+	product, err := h.service.Create(ctx, ports.ProductInput{
+		ID:            message.ID,
+		Name:          message.Name,
+		Description:   message.Description,
+		Price:         message.Price,
+		ExpiresAt:     message.ExpiresAt,
+		PublishAt:     message.PublishAt,
+		AutoArchiveAt: message.AutoArchiveAt,
+		CostPrice:     message.CostPrice,
+		CategoryID:    message.CategoryID,
+	})
+	var duplicate *domain.DuplicateError
+	if errors.As(err, &duplicate) && duplicate.Field == domain.FieldID {
+		// An earlier delivery of the message created the product
+		h.logger.InfoContext(ctx, "product already imported", "id", message.ID)
+		return nil
+	}
+	if err != nil {
+		// Retrying cannot fix the content of the message
+		if errors.Is(err, domain.ErrInvalidProduct) || errors.Is(err, domain.ErrUnknownCategory) || errors.Is(err, domain.ErrContentRejected) {
+			return Permanent(err)
+		}
+		return err
+	}
+
+	h.logger.InfoContext(ctx, "product imported", "id", product.ID, "name", product.Name, "tenant_id", product.TenantID)
+	return nil
+}
@@ -0,0 +1,80 @@
+package recommender
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// minCandidates is the fewest products read from each listing, so short
+// limits still have a choice of candidates to rank
+const minCandidates = 20
+
+// AttributeRecommender relates products by catalog attributes alone: the
+// products of the same category and those priced within a band around the
+// product, ranked by domain.SimilarityScore. It needs no interaction
+// history, so it works for new products and new catalogs.
+type AttributeRecommender struct {
+	products  ports.ProductRepository
+	priceBand float64
+}
+
+// NewAttributeRecommender relates products priced within priceBand, a
+// fraction of the product's price, of each other
+func NewAttributeRecommender(products ports.ProductRepository, priceBand float64) *AttributeRecommender {
+	return &AttributeRecommender{
+		products:  products,
+		priceBand: priceBand,
+	}
+}
+
+// RecordInteraction does nothing; attributes do not change with traffic
+func (r *AttributeRecommender) RecordInteraction(ctx context.Context, sessionID, productID string) error {
+	return nil
+}
+
+// Recommend ranks the product's category and its price band, which are two
+// listings of the tenant's visible products
+func (r *AttributeRecommender) Recommend(ctx context.Context, productID string, limit int) ([]domain.Recommendation, error) {
+	product, err := r.products.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := max(limit*4, minCandidates)
+	var listings []ports.ProductFilters
+	if product.CategoryID != "" {
+		listings = append(listings, ports.ProductFilters{CategoryID: product.CategoryID, Limit: candidates})
+	}
+	if r.priceBand > 0 && product.Price.Amount > 0 {
+		low, high := product.Price, product.Price
+		band := int64(math.Round(float64(product.Price.Amount) * r.priceBand))
+		low.Amount, high.Amount = max(low.Amount-band, 0), high.Amount+band
+		listings = append(listings, ports.ProductFilters{
+			MinPrice: low.Major(),
+			MaxPrice: high.Major(),
+			SortBy:   "price",
+			Limit:    candidates,
+		})
+	}
+
+	scores := map[string]float64{}
+	for _, filters := range listings {
+		result, err := r.products.ListWithFilters(ctx, filters)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list related candidates: %w", err)
+		}
+		for _, candidate := range result.Products {
+			if candidate.ID == product.ID {
+				continue
+			}
+			if score := domain.SimilarityScore(product, candidate, r.priceBand); score > 0 {
+				scores[candidate.ID] = score
+			}
+		}
+	}
+	return domain.RankRecommendations(scores, limit), nil
+}
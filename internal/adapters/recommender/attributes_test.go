@@ -0,0 +1,48 @@
+package recommender
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports/repotest"
+)
+
+func TestAttributeRecommender(t *testing.T) {
+	products := repotest.NewMemoryRepository()
+	ctx := context.Background()
+	now := time.Now().UTC()
+	for _, product := range []domain.Product{
+		{ID: "headphones", CategoryID: "audio", Price: domain.Money{Amount: 10000, Currency: "USD"}},
+		{ID: "earbuds", CategoryID: "audio", Price: domain.Money{Amount: 9000, Currency: "USD"}},
+		{ID: "speaker", CategoryID: "audio", Price: domain.Money{Amount: 40000, Currency: "USD"}},
+		{ID: "webcam", CategoryID: "video", Price: domain.Money{Amount: 10500, Currency: "USD"}},
+		{ID: "tripod", CategoryID: "video", Price: domain.Money{Amount: 2000, Currency: "USD"}},
+		{ID: "draft", CategoryID: "audio", Price: domain.Money{Amount: 10000, Currency: "USD"}, Status: domain.StatusDraft},
+	} {
+		product.Name = product.ID
+		product.CreatedAt, product.UpdatedAt = now, now
+		if product.Status == "" {
+			product.Status = domain.StatusPublished
+		}
+		require.NoError(t, products.Save(ctx, product))
+	}
+
+	recommendations, err := NewAttributeRecommender(products, 0.2).Recommend(ctx, "headphones", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Recommendation{
+		{ProductID: "earbuds", Score: 1.5},
+		{ProductID: "speaker", Score: 1},
+		{ProductID: "webcam", Score: 0.75},
+	}, recommendations)
+
+	recommendations, err = NewAttributeRecommender(products, 0.2).Recommend(ctx, "headphones", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Recommendation{{ProductID: "earbuds", Score: 1.5}}, recommendations)
+
+	_, err = NewAttributeRecommender(products, 0.2).Recommend(ctx, "missing", 10)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
@@ -0,0 +1,203 @@
+package recommender
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// sessionPrefix marks the items holding a session's recent products
+	sessionPrefix  = "SESSION#"
+	sessionSortKey = "#RECENT"
+	// sessionHistory is how many recent products of a session are paired
+	// with each new interaction
+	sessionHistory = 10
+	// sessionTTL is how long a quiet session keeps pairing products
+	sessionTTL = 24 * time.Hour
+	// maxConcurrentUpdates bounds the counter updates in flight per interaction
+	maxConcurrentUpdates = 8
+)
+
+// CooccurrenceRecommender counts how often two products are interacted with
+// in the same session and recommends the products seen most often together.
+// Counters are items keyed by product_id and related_id; session histories
+// live in the same table and are removed by TTL on expires_at.
+type CooccurrenceRecommender struct {
+	client    *dynamodb.Client
+	tableName string
+	now       func() time.Time
+}
+
+func NewCooccurrenceRecommender(client *dynamodb.Client, tableName string) *CooccurrenceRecommender {
+	return &CooccurrenceRecommender{
+		client:    client,
+		tableName: tableName,
+		now:       time.Now,
+	}
+}
+
+type sessionItem struct {
+	ProductID string   `dynamodbav:"product_id"`
+	RelatedID string   `dynamodbav:"related_id"`
+	Products  []string `dynamodbav:"products"`
+	ExpiresAt int64    `dynamodbav:"expires_at"`
+}
+
+type cooccurrenceItem struct {
+	ProductID string `dynamodbav:"product_id"`
+	RelatedID string `dynamodbav:"related_id"`
+	Count     int64  `dynamodbav:"count"`
+}
+
+// RecordInteraction pairs productID with the session's recent products and
+// adds the product to that history
+func (r *CooccurrenceRecommender) RecordInteraction(ctx context.Context, sessionID, productID string) error {
+	history, err := r.sessionProducts(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	related, history := pairWithHistory(history, productID)
+	if err := r.saveSession(ctx, sessionID, history); err != nil {
+		return err
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxConcurrentUpdates)
+	for _, other := range related {
+		g.Go(func() error { return r.increment(gctx, productID, other) })
+		g.Go(func() error { return r.increment(gctx, other, productID) })
+	}
+	return g.Wait()
+}
+
+// Recommend returns the products most often seen together with productID
+func (r *CooccurrenceRecommender) Recommend(ctx context.Context, productID string, limit int) ([]domain.Recommendation, error) {
+	paginator := dynamodb.NewQueryPaginator(r.client, &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		KeyConditionExpression: aws.String("#product_id = :product_id"),
+		ExpressionAttributeNames: map[string]string{
+			"#product_id": "product_id",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":product_id": &types.AttributeValueMemberS{Value: productID},
+		},
+	})
+
+	scores := make(map[string]float64)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query co-occurrences: %w", err)
+		}
+
+		var items []cooccurrenceItem
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &items); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal co-occurrences: %w", err)
+		}
+		for _, item := range items {
+			scores[item.RelatedID] = float64(item.Count)
+		}
+	}
+	return domain.RankRecommendations(scores, limit), nil
+}
+
+func (r *CooccurrenceRecommender) sessionProducts(ctx context.Context, sessionID string) ([]string, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"product_id": &types.AttributeValueMemberS{Value: sessionPrefix + sessionID},
+			"related_id": &types.AttributeValueMemberS{Value: sessionSortKey},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session history: %w", err)
+	}
+	if result.Item == nil {
+		return nil, nil
+	}
+
+	var item sessionItem
+	if err := attributevalue.UnmarshalMap(result.Item, &item); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session history: %w", err)
+	}
+	// Expired sessions may linger until TTL deletes them
+	if item.ExpiresAt <= r.now().Unix() {
+		return nil, nil
+	}
+	return item.Products, nil
+}
+
+func (r *CooccurrenceRecommender) saveSession(ctx context.Context, sessionID string, products []string) error {
+	item, err := attributevalue.MarshalMap(sessionItem{
+		ProductID: sessionPrefix + sessionID,
+		RelatedID: sessionSortKey,
+		Products:  products,
+		ExpiresAt: r.now().Add(sessionTTL).Unix(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal session history: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save session history: %w", err)
+	}
+	return nil
+}
+
+func (r *CooccurrenceRecommender) increment(ctx context.Context, productID, relatedID string) error {
+	_, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"product_id": &types.AttributeValueMemberS{Value: productID},
+			"related_id": &types.AttributeValueMemberS{Value: relatedID},
+		},
+		UpdateExpression: aws.String("ADD #count :one"),
+		ExpressionAttributeNames: map[string]string{
+			"#count": "count",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":one": &types.AttributeValueMemberN{Value: "1"},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to increment co-occurrence: %w", err)
+	}
+	return nil
+}
+
+// pairWithHistory returns the products productID should be counted with and
+// the session history after the interaction, most recent first. Repeated
+// interactions with a product already in the history only move it to the
+// front, so reloading a page does not inflate its counters.
+func pairWithHistory(history []string, productID string) (related, updated []string) {
+	updated = make([]string, 0, sessionHistory)
+	updated = append(updated, productID)
+
+	seen := false
+	for _, id := range history {
+		if id == productID {
+			seen = true
+			continue
+		}
+		if len(updated) < sessionHistory {
+			updated = append(updated, id)
+		}
+	}
+	if seen {
+		return nil, updated
+	}
+	return updated[1:], updated
+}
@@ -0,0 +1,31 @@
+package recommender
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPairWithHistory(t *testing.T) {
+	related, updated := pairWithHistory(nil, "a")
+	assert.Empty(t, related)
+	assert.Equal(t, []string{"a"}, updated)
+
+	related, updated = pairWithHistory([]string{"b", "c"}, "a")
+	assert.Equal(t, []string{"b", "c"}, related)
+	assert.Equal(t, []string{"a", "b", "c"}, updated)
+
+	// Seen products move to the front without being counted again
+	related, updated = pairWithHistory([]string{"b", "a", "c"}, "a")
+	assert.Empty(t, related)
+	assert.Equal(t, []string{"a", "b", "c"}, updated)
+}
+
+func TestPairWithHistoryKeepsMostRecent(t *testing.T) {
+	history := []string{"p1", "p2", "p3", "p4", "p5", "p6", "p7", "p8", "p9", "p10"}
+
+	related, updated := pairWithHistory(history, "new")
+	assert.Len(t, updated, sessionHistory)
+	assert.Equal(t, "new", updated[0])
+	assert.Equal(t, history[:sessionHistory-1], related)
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// ListScheduledForArchival scans for unexpired published products whose
+// auto_archive_at falls before until
+func (r *DynamoDBRepository) ListScheduledForArchival(ctx context.Context, until time.Time) ([]domain.Product, error) {
+	names := map[string]string{
+		"#status":          "status",
+		"#auto_archive_at": "auto_archive_at",
+	}
+	values := map[string]types.AttributeValue{
+		":published": &types.AttributeValueMemberS{Value: domain.StatusPublished},
+		":until":     &types.AttributeValueMemberN{Value: strconv.FormatInt(until.Unix(), 10)},
+	}
+	filter := "(attribute_not_exists(#status) OR #status = :published) AND #auto_archive_at <= :until AND " +
+		notExpiredCondition(time.Now().UTC(), names, values)
+	paginator := dynamodb.NewScanPaginator(r.client, productScan(&dynamodb.ScanInput{
+		TableName:                 aws.String(r.tableName),
+		FilterExpression:          aws.String(filter),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	}))
+
+	var products []domain.Product
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan products scheduled for archival: %w", err)
+		}
+
+		batch, err := decodeProducts(page.Items)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal products scheduled for archival: %w", err)
+		}
+		products = append(products, batch...)
+	}
+	return products, nil
+}
+
+// MarkArchiveWarned records the pre-archive warning, unless the product was
+// already warned or changed since it was read
+func (r *DynamoDBRepository) MarkArchiveWarned(ctx context.Context, product domain.Product, now time.Time) error {
+	return r.updateProduct(ctx, product, &types.Update{
+		UpdateExpression:         aws.String("SET #archive_warned_at = :now"),
+		ConditionExpression:      aws.String("attribute_not_exists(#archive_warned_at)"),
+		ExpressionAttributeNames: map[string]string{"#archive_warned_at": "archive_warned_at"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":now": &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Unix(), 10)},
+		},
+	}, "failed to mark archive warning")
+}
+
+// MarkArchived archives a published product whose auto_archive_at has
+// passed, in a single conditional update
+func (r *DynamoDBRepository) MarkArchived(ctx context.Context, product domain.Product, now time.Time) error {
+	updatedAt, err := attributevalue.Marshal(now)
+	if err != nil {
+		return fmt.Errorf("failed to marshal updated_at: %w", err)
+	}
+
+	return r.updateProduct(ctx, product, &types.Update{
+		UpdateExpression:    aws.String("SET #status = :archived, #updated_at = :updated_at REMOVE #auto_archive_at, #archive_warned_at"),
+		ConditionExpression: aws.String("(attribute_not_exists(#status) OR #status = :published) AND #auto_archive_at <= :now"),
+		ExpressionAttributeNames: map[string]string{
+			"#status":            "status",
+			"#updated_at":        "updated_at",
+			"#auto_archive_at":   "auto_archive_at",
+			"#archive_warned_at": "archive_warned_at",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":archived":   &types.AttributeValueMemberS{Value: domain.StatusArchived},
+			":published":  &types.AttributeValueMemberS{Value: domain.StatusPublished},
+			":now":        &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Unix(), 10)},
+			":updated_at": updatedAt,
+		},
+	}, "failed to archive product")
+}
+
+// conditionalUpdateError maps a failed condition to domain.ErrConflict and
+// wraps anything else with msg
+func conditionalUpdateError(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return domain.ErrConflict
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
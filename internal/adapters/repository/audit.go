@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// auditItem is one audit entry, keyed by product with a sort key that
+// orders the entries by time; the changes are kept as a JSON document
+type auditItem struct {
+	ProductID  string `dynamodbav:"product_id"`
+	SortKey    string `dynamodbav:"sk"`
+	ID         string `dynamodbav:"id"`
+	TenantID   string `dynamodbav:"tenant_id,omitempty"`
+	Action     string `dynamodbav:"action"`
+	Actor      string `dynamodbav:"actor"`
+	OccurredAt string `dynamodbav:"occurred_at"`
+	Changes    string `dynamodbav:"changes"`
+}
+
+// DynamoDBAuditLog stores the audit entries of every product in their own
+// table, which outlives the products it describes
+type DynamoDBAuditLog struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+func NewDynamoDBAuditLog(client *dynamodb.Client, tableName string) *DynamoDBAuditLog {
+	return &DynamoDBAuditLog{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+func (r *DynamoDBAuditLog) Record(ctx context.Context, entry domain.AuditEntry) error {
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit changes: %w", err)
+	}
+	// The time layout shared with the outbox sorts as a string; the ID
+	// keeps entries written in the same instant apart
+	occurredAt := entry.OccurredAt.UTC().Format(outboxTimeLayout)
+	item, err := attributevalue.MarshalMap(auditItem{
+		ProductID:  entry.ProductID,
+		SortKey:    occurredAt + "#" + entry.ID,
+		ID:         entry.ID,
+		TenantID:   entry.TenantID,
+		Action:     entry.Action,
+		Actor:      entry.Actor,
+		OccurredAt: occurredAt,
+		Changes:    string(changes),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save audit entry: %w", err)
+	}
+	return nil
+}
+
+func (r *DynamoDBAuditLog) History(ctx context.Context, productID string, limit int) ([]domain.AuditEntry, error) {
+	names := map[string]string{"#product_id": "product_id"}
+	values := map[string]types.AttributeValue{
+		":product_id": &types.AttributeValueMemberS{Value: productID},
+	}
+	filter := tenantCondition(ports.TenantID(ctx), names, values)
+
+	entries := []domain.AuditEntry{}
+	var startKey map[string]types.AttributeValue
+	for {
+		result, err := r.client.Query(ctx, &dynamodb.QueryInput{
+			TableName:                 aws.String(r.tableName),
+			KeyConditionExpression:    aws.String("#product_id = :product_id"),
+			FilterExpression:          aws.String(filter),
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+			ScanIndexForward:          aws.Bool(false),
+			Limit:                     aws.Int32(int32(limit)),
+			ExclusiveStartKey:         startKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query audit history: %w", err)
+		}
+
+		for _, raw := range result.Items {
+			entry, err := decodeAuditItem(raw)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry)
+			if len(entries) == limit {
+				return entries, nil
+			}
+		}
+		// Entries of other tenants are filtered out after the limit is
+		// applied, so a short page does not mean the history is exhausted
+		if result.LastEvaluatedKey == nil {
+			return entries, nil
+		}
+		startKey = result.LastEvaluatedKey
+	}
+}
+
+func decodeAuditItem(raw map[string]types.AttributeValue) (domain.AuditEntry, error) {
+	var item auditItem
+	if err := attributevalue.UnmarshalMap(raw, &item); err != nil {
+		return domain.AuditEntry{}, fmt.Errorf("failed to unmarshal audit entry: %w", err)
+	}
+	occurredAt, err := time.Parse(outboxTimeLayout, item.OccurredAt)
+	if err != nil {
+		return domain.AuditEntry{}, fmt.Errorf("invalid audit entry time %q: %w", item.OccurredAt, err)
+	}
+	var changes []domain.FieldChange
+	if err := json.Unmarshal([]byte(item.Changes), &changes); err != nil {
+		return domain.AuditEntry{}, fmt.Errorf("failed to decode audit changes: %w", err)
+	}
+	return domain.AuditEntry{
+		ID:         item.ID,
+		ProductID:  item.ProductID,
+		TenantID:   item.TenantID,
+		Action:     item.Action,
+		Actor:      item.Actor,
+		OccurredAt: occurredAt,
+		Changes:    changes,
+	}, nil
+}
@@ -0,0 +1,355 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+const (
+	// batchWriteSize is the most requests BatchWriteItem accepts at once
+	batchWriteSize = 25
+	// batchGetSize is the most keys BatchGetItem accepts at once
+	batchGetSize = 100
+	// batchAttempts bounds the retries of items DynamoDB left
+	// unprocessed, with exponential backoff from batchBackoff
+	batchAttempts = 5
+	batchBackoff  = 50 * time.Millisecond
+)
+
+// GetByIDs reads the products with BatchGetItem, 100 keys at a time.
+// Products of other tenants and expired ones are left out like missing ones.
+func (r *DynamoDBRepository) GetByIDs(ctx context.Context, ids []string) ([]domain.Product, error) {
+	now := time.Now().UTC()
+	products := make([]domain.Product, 0, len(ids))
+	for start := 0; start < len(ids); start += batchGetSize {
+		end := min(start+batchGetSize, len(ids))
+		items, err := r.batchGet(ctx, ids[start:end])
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			product, err := decodeProduct(item)
+			if err != nil {
+				return nil, err
+			}
+			if product.TenantID != ports.TenantID(ctx) || product.IsExpired(now) {
+				continue
+			}
+			products = append(products, product)
+		}
+	}
+	return products, nil
+}
+
+func (r *DynamoDBRepository) batchGet(ctx context.Context, ids []string) ([]map[string]types.AttributeValue, error) {
+	keys := make([]map[string]types.AttributeValue, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, productKey(id))
+	}
+	pending := map[string]types.KeysAndAttributes{
+		r.tableName: {Keys: keys, ConsistentRead: aws.Bool(ports.ConsistentRead(ctx))},
+	}
+
+	var items []map[string]types.AttributeValue
+	backoff := batchBackoff
+	for attempt := 1; ; attempt++ {
+		result, err := r.client.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{RequestItems: pending})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read product batch: %w", err)
+		}
+		items = append(items, result.Responses[r.tableName]...)
+		if len(result.UnprocessedKeys) == 0 {
+			return items, nil
+		}
+		if attempt == batchAttempts {
+			return nil, fmt.Errorf("failed to read product batch: %d keys still unprocessed after %d attempts",
+				len(result.UnprocessedKeys[r.tableName].Keys), attempt)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		pending = result.UnprocessedKeys
+	}
+}
+
+// SaveBatch puts products with BatchWriteItem, each followed by its outbox
+// events, 25 requests at a time. Products holding an SKU or barcode are
+// instead saved one by one in a transaction with their reservations.
+// Batches are not atomic, so they never join a unit of work, and a failure
+// part way through reports the products already saved in a
+// *domain.BatchWriteError.
+func (r *DynamoDBRepository) SaveBatch(ctx context.Context, products []domain.Product) (map[string]*domain.DuplicateError, error) {
+	ctx = withoutTransaction(ctx)
+	events := map[string][]domain.ProductEvent{}
+	if r.outboxTable != "" {
+		for _, event := range ports.OutboxEvents(ctx) {
+			events[event.ProductID] = append(events[event.ProductID], event)
+		}
+	}
+
+	rejected := map[string]*domain.DuplicateError{}
+	var written []string
+	var requests []batchRequest
+	for _, product := range products {
+		item, err := r.toItem(product)
+		if err != nil {
+			return nil, partialWrite(written, err)
+		}
+		unique, err := r.uniqueWrites(nil, &product)
+		if err != nil {
+			return nil, partialWrite(written, err)
+		}
+		if len(unique) > 0 {
+			put := types.TransactWriteItem{Put: &types.Put{TableName: aws.String(r.tableName), Item: item}}
+			err := r.writeWithEvents(ctx, put, events[product.ID], unique, func(err error) error { return err })
+			var duplicate *domain.DuplicateError
+			if errors.As(err, &duplicate) {
+				rejected[product.ID] = duplicate
+				continue
+			}
+			if err != nil {
+				return nil, partialWrite(written, fmt.Errorf("failed to save product %s: %w", product.ID, err))
+			}
+			r.onWrite.call(ctx, product.TenantID, product.ID)
+			written = append(written, product.ID)
+			continue
+		}
+
+		requests = append(requests, batchRequest{table: r.tableName, item: item, product: &product})
+		for _, event := range events[product.ID] {
+			outboxItem, err := newOutboxItem(event)
+			if err != nil {
+				return nil, partialWrite(written, err)
+			}
+			requests = append(requests, batchRequest{table: r.outboxTable, item: outboxItem})
+		}
+	}
+
+	batched, err := r.batchWriteAll(ctx, requests)
+	if err != nil {
+		return nil, partialWrite(append(written, batched...), err)
+	}
+	return rejected, nil
+}
+
+// partialWrite reports err along with the products already written, if any
+func partialWrite(written []string, err error) error {
+	if len(written) == 0 {
+		return err
+	}
+	return &domain.BatchWriteError{Written: written, Err: err}
+}
+
+// DeleteBatch deletes products conditionally on their version, each
+// delete in a transaction with its outbox events and as many others as fit
+// in one. The products that changed or disappeared cancel their
+// transaction; they are kept and the rest of it is sent again. Products
+// holding an SKU or barcode are deleted one by one, releasing their
+// reservations. Like SaveBatch, it never joins a unit of work.
+func (r *DynamoDBRepository) DeleteBatch(ctx context.Context, products []domain.Product) ([]string, error) {
+	ctx = withoutTransaction(ctx)
+	tenant := ports.TenantID(ctx)
+	events := map[string][]domain.ProductEvent{}
+	if r.outboxTable != "" {
+		for _, event := range ports.OutboxEvents(ctx) {
+			events[event.ProductID] = append(events[event.ProductID], event)
+		}
+	}
+
+	var skipped []string
+	var deletes []productDelete
+	for _, product := range products {
+		if r.uniqueTable != "" && len(product.UniqueValues()) > 0 {
+			err := r.deleteWithEvents(ctx, product.ID, product.Version, events[product.ID])
+			if errors.Is(err, domain.ErrConflict) || errors.Is(err, domain.ErrNotFound) {
+				skipped = append(skipped, product.ID)
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to delete product %s: %w", product.ID, err)
+			}
+			continue
+		}
+
+		condition, names, values := deleteCondition(tenant, product.Version)
+		if len(values) == 0 {
+			values = nil
+		}
+		items := []types.TransactWriteItem{{Delete: &types.Delete{
+			TableName:                 aws.String(r.tableName),
+			Key:                       productKey(product.ID),
+			ConditionExpression:       aws.String(condition),
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+		}}}
+		for _, event := range events[product.ID] {
+			outboxItem, err := newOutboxItem(event)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, types.TransactWriteItem{Put: &types.Put{TableName: aws.String(r.outboxTable), Item: outboxItem}})
+		}
+		deletes = append(deletes, productDelete{id: product.ID, items: items})
+	}
+
+	conflicted, err := r.transactDeletes(ctx, tenant, deletes)
+	return append(skipped, conflicted...), err
+}
+
+// productDelete is the conditional delete of one product followed by its
+// outbox events
+type productDelete struct {
+	id    string
+	items []types.TransactWriteItem
+}
+
+// transactDeletes runs deletes in transactions of up to maxTransactItems
+// writes. A transaction canceled by the condition of some deletes is sent
+// again without them, and their IDs are returned.
+func (r *DynamoDBRepository) transactDeletes(ctx context.Context, tenant string, deletes []productDelete) ([]string, error) {
+	var conflicted []string
+	for len(deletes) > 0 {
+		var items []types.TransactWriteItem
+		n := 0
+		for n < len(deletes) && len(items)+len(deletes[n].items) <= maxTransactItems {
+			items = append(items, deletes[n].items...)
+			n++
+		}
+		if n == 0 {
+			return conflicted, fmt.Errorf("product %s has more than %d writes to delete", deletes[0].id, maxTransactItems)
+		}
+		chunk, rest := deletes[:n], deletes[n:]
+
+		_, err := r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items})
+		var canceled *types.TransactionCanceledException
+		if errors.As(err, &canceled) {
+			// Only the deletes that failed their condition are dropped;
+			// any other reason fails the batch
+			var retry []productDelete
+			start := 0
+			for _, del := range chunk {
+				end := min(start+len(del.items), len(canceled.CancellationReasons))
+				if start < end && aws.ToString(canceled.CancellationReasons[start].Code) == "ConditionalCheckFailed" {
+					conflicted = append(conflicted, del.id)
+				} else {
+					retry = append(retry, del)
+				}
+				start += len(del.items)
+			}
+			if len(retry) < len(chunk) {
+				deletes = append(retry, rest...)
+				continue
+			}
+		}
+		if err != nil {
+			return conflicted, fmt.Errorf("failed to delete product batch: %w", err)
+		}
+		for _, del := range chunk {
+			r.onWrite.call(ctx, tenant, del.id)
+		}
+		deletes = rest
+	}
+	return conflicted, nil
+}
+
+// batchRequest is an item to put into a table
+type batchRequest struct {
+	table string
+	item  map[string]types.AttributeValue
+	// product is the product written, nil for outbox events
+	product *domain.Product
+}
+
+// batchWriteAll writes requests batchWriteSize at a time, telling the write
+// hook about the products of every batch sent, even one that failed part
+// way. It returns the IDs of the products written, up to a failure.
+func (r *DynamoDBRepository) batchWriteAll(ctx context.Context, requests []batchRequest) ([]string, error) {
+	var written []string
+	for start := 0; start < len(requests); start += batchWriteSize {
+		end := min(start+batchWriteSize, len(requests))
+		unwritten, err := r.batchWrite(ctx, requests[start:end])
+		for _, request := range requests[start:end] {
+			if request.product == nil {
+				continue
+			}
+			r.onWrite.call(ctx, request.product.TenantID, request.product.ID)
+			if !unwritten[itemKey(request.item)] {
+				written = append(written, request.product.ID)
+			}
+		}
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// batchWrite sends requests with BatchWriteItem, retrying the items left
+// unprocessed. When it fails it returns the keys of the items that may not
+// have been written.
+func (r *DynamoDBRepository) batchWrite(ctx context.Context, requests []batchRequest) (map[string]bool, error) {
+	pending := map[string][]types.WriteRequest{}
+	for _, request := range requests {
+		pending[request.table] = append(pending[request.table], types.WriteRequest{PutRequest: &types.PutRequest{Item: request.item}})
+	}
+
+	backoff := batchBackoff
+	for attempt := 1; ; attempt++ {
+		result, err := r.client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{RequestItems: pending})
+		if err != nil {
+			return pendingKeys(pending), fmt.Errorf("failed to write product batch: %w", err)
+		}
+		if len(result.UnprocessedItems) == 0 {
+			return nil, nil
+		}
+		if attempt == batchAttempts {
+			unprocessed := 0
+			for _, writes := range result.UnprocessedItems {
+				unprocessed += len(writes)
+			}
+			return pendingKeys(result.UnprocessedItems), fmt.Errorf("failed to write product batch: %d items still unprocessed after %d attempts", unprocessed, attempt)
+		}
+
+		// Unprocessed items mean the table is throttling; back off before
+		// sending them again
+		select {
+		case <-ctx.Done():
+			return pendingKeys(result.UnprocessedItems), ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		pending = result.UnprocessedItems
+	}
+}
+
+// pendingKeys lists the partition keys of the items pending writes put
+func pendingKeys(pending map[string][]types.WriteRequest) map[string]bool {
+	keys := map[string]bool{}
+	for _, writes := range pending {
+		for _, write := range writes {
+			keys[itemKey(write.PutRequest.Item)] = true
+		}
+	}
+	return keys
+}
+
+// itemKey is the partition key of item
+func itemKey(item map[string]types.AttributeValue) string {
+	key, _ := item[partitionKeyAttribute].(*types.AttributeValueMemberS)
+	if key == nil {
+		return ""
+	}
+	return key.Value
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// sequenceTransport answers each request with the next of its bodies,
+// and of its statuses when set, recording the requests it was sent
+type sequenceTransport struct {
+	bodies   []string
+	statuses []int
+	requests *[]string
+}
+
+func (s sequenceTransport) Do(req *http.Request) (*http.Response, error) {
+	body, _ := io.ReadAll(req.Body)
+	*s.requests = append(*s.requests, string(body))
+	status := http.StatusOK
+	if len(s.statuses) > 0 {
+		status = s.statuses[len(*s.requests)-1]
+	}
+	return stubTransport{status: status, body: s.bodies[len(*s.requests)-1]}.Do(req)
+}
+
+func TestGetByIDs_RetriesUnprocessedKeys(t *testing.T) {
+	var requests []string
+	client := dynamodb.New(dynamodb.Options{
+		Region:      "us-east-1",
+		Credentials: aws.AnonymousCredentials{},
+		HTTPClient: sequenceTransport{requests: &requests, bodies: []string{
+			`{"Responses":{"products":[{"id":{"S":"p1"},"name":{"S":"Laptop"}},{"id":{"S":"p3"},"name":{"S":"Anvil"},"tenant_id":{"S":"acme"}}]},
+			  "UnprocessedKeys":{"products":{"Keys":[{"id":{"S":"p2"}}]}}}`,
+			`{"Responses":{"products":[{"id":{"S":"p2"},"name":{"S":"Mouse"}}]}}`,
+		}},
+	})
+	repo := NewDynamoDBRepository(client, "products")
+
+	products, err := repo.GetByIDs(ports.WithConsistentRead(context.Background()), []string{"p1", "p2", "p3", "p4"})
+	require.NoError(t, err)
+	require.Len(t, requests, 2)
+	assert.Contains(t, requests[0], `"ConsistentRead":true`)
+	assert.True(t, strings.Contains(requests[1], `"p2"`) && !strings.Contains(requests[1], `"p1"`), "only unprocessed keys are sent again")
+
+	var names []string
+	for _, product := range products {
+		names = append(names, product.Name)
+	}
+	assert.Equal(t, []string{"Laptop", "Mouse"}, names, "other tenants' products are left out")
+}
+
+func TestSaveBatch_ReportsProductsWrittenBeforeFailure(t *testing.T) {
+	products := make([]domain.Product, 27)
+	for i := range products {
+		products[i] = domain.Product{ID: fmt.Sprintf("p%02d", i+1), Name: "Hat", Price: domain.Money{Amount: 1000, Currency: "USD"}}
+	}
+	unprocessed := `{"UnprocessedItems":{"products":[{"PutRequest":{"Item":{"pk":{"S":"PRODUCT#p27"},"sk":{"S":"PRODUCT"}}}}]}}`
+	var requests []string
+	client := dynamodb.New(dynamodb.Options{
+		Region:      "us-east-1",
+		Credentials: aws.AnonymousCredentials{},
+		HTTPClient: sequenceTransport{requests: &requests, bodies: []string{
+			`{}`, unprocessed, unprocessed, unprocessed, unprocessed, unprocessed,
+		}},
+	})
+	repo := NewDynamoDBRepository(client, "products")
+
+	_, err := repo.SaveBatch(context.Background(), products)
+	var partial *domain.BatchWriteError
+	require.ErrorAs(t, err, &partial)
+	require.Len(t, requests, 1+batchAttempts)
+	require.Len(t, partial.Written, 26)
+	assert.Equal(t, "p01", partial.Written[0])
+	assert.Equal(t, "p26", partial.Written[25], "the product left unprocessed is not reported written")
+}
+
+func TestDeleteBatch_KeepsChangedProducts(t *testing.T) {
+	var requests []string
+	client := dynamodb.New(dynamodb.Options{
+		Region:      "us-east-1",
+		Credentials: aws.AnonymousCredentials{},
+		HTTPClient: sequenceTransport{requests: &requests, statuses: []int{http.StatusBadRequest, http.StatusOK}, bodies: []string{
+			`{"__type":"com.amazonaws.dynamodb.v20120810#TransactionCanceledException","message":"Transaction cancelled",
+			  "CancellationReasons":[{"Code":"None"},{"Code":"ConditionalCheckFailed"},{"Code":"None"}]}`,
+			`{}`,
+		}},
+	})
+	var written []string
+	repo := NewDynamoDBRepository(client, "products", WithWriteHook(func(ctx context.Context, tenant, id string) {
+		written = append(written, id)
+	}))
+
+	skipped, err := repo.DeleteBatch(context.Background(), []domain.Product{
+		{ID: "p1", Version: 1}, {ID: "p2", Version: 2}, {ID: "p3", Version: 3},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"p2"}, skipped, "the product changed since it was read is kept")
+	require.Len(t, requests, 2)
+	assert.Contains(t, requests[0], `":expected_version":{"N":"2"}`)
+	assert.NotContains(t, requests[1], `"p2"`, "the others are sent again without it")
+	assert.Equal(t, []string{"p1", "p3"}, written)
+}
@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// productCountAttribute holds a category's ports.CategoryCounter count
+const productCountAttribute = "product_count"
+
+// DynamoDBCategoryRepository keeps categories in the products table, all
+// under one partition, so listing them is a single query
+type DynamoDBCategoryRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+func NewDynamoDBCategoryRepository(client *dynamodb.Client, tableName string) *DynamoDBCategoryRepository {
+	return &DynamoDBCategoryRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// Save writes the category's own attributes, leaving its product count to
+// AdjustProductCount
+func (r *DynamoDBCategoryRepository) Save(ctx context.Context, category domain.Category) error {
+	item, err := attributevalue.MarshalMap(category)
+	if err != nil {
+		return fmt.Errorf("failed to marshal category: %w", err)
+	}
+	delete(item, productCountAttribute)
+	item[entityTypeAttribute] = &types.AttributeValueMemberS{Value: entityCategory}
+
+	names := make([]string, 0, len(item))
+	for name := range item {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	update := "SET "
+	attributeNames := map[string]string{}
+	values := map[string]types.AttributeValue{}
+	for i, name := range names {
+		if i > 0 {
+			update += ", "
+		}
+		update += "#" + name + " = :" + name
+		attributeNames["#"+name] = name
+		values[":"+name] = item[name]
+	}
+
+	_, err = r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(r.tableName),
+		Key:                       categoryKey(category.ID),
+		UpdateExpression:          aws.String(update),
+		ExpressionAttributeNames:  attributeNames,
+		ExpressionAttributeValues: values,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save category: %w", err)
+	}
+	return nil
+}
+
+// AdjustProductCount adds delta to the category's product_count. Inside a
+// unit of work the update is enlisted with the product writes.
+func (r *DynamoDBCategoryRepository) AdjustProductCount(ctx context.Context, categoryID string, delta int) error {
+	update := types.Update{
+		TableName:           aws.String(r.tableName),
+		Key:                 categoryKey(categoryID),
+		UpdateExpression:    aws.String("ADD #product_count :delta"),
+		ConditionExpression: aws.String("attribute_exists(#pk)"),
+		ExpressionAttributeNames: map[string]string{
+			"#product_count": productCountAttribute,
+			"#pk":            partitionKeyAttribute,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":delta": &types.AttributeValueMemberN{Value: strconv.Itoa(delta)},
+		},
+	}
+	if tx := activeTransaction(ctx); tx != nil {
+		tx.enlist([]types.TransactWriteItem{{Update: &update}}, func(*types.TransactionCanceledException) error {
+			return domain.ErrCategoryNotFound
+		})
+		return nil
+	}
+
+	_, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 update.TableName,
+		Key:                       update.Key,
+		UpdateExpression:          update.UpdateExpression,
+		ConditionExpression:       update.ConditionExpression,
+		ExpressionAttributeNames:  update.ExpressionAttributeNames,
+		ExpressionAttributeValues: update.ExpressionAttributeValues,
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return domain.ErrCategoryNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to adjust category product count: %w", err)
+	}
+	return nil
+}
+
+func (r *DynamoDBCategoryRepository) GetByID(ctx context.Context, id string) (domain.Category, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       categoryKey(id),
+	})
+	if err != nil {
+		return domain.Category{}, fmt.Errorf("failed to get category: %w", err)
+	}
+	if result.Item == nil {
+		return domain.Category{}, domain.ErrCategoryNotFound
+	}
+
+	var category domain.Category
+	if err := attributevalue.UnmarshalMap(result.Item, &category); err != nil {
+		return domain.Category{}, fmt.Errorf("failed to unmarshal category: %w", err)
+	}
+	return category, nil
+}
+
+func (r *DynamoDBCategoryRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       categoryKey(id),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+	return nil
+}
+
+func (r *DynamoDBCategoryRepository) List(ctx context.Context) ([]domain.Category, error) {
+	paginator := dynamodb.NewQueryPaginator(r.client, &dynamodb.QueryInput{
+		TableName:                aws.String(r.tableName),
+		KeyConditionExpression:   aws.String("#pk = :category"),
+		ExpressionAttributeNames: map[string]string{"#pk": partitionKeyAttribute},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":category": &types.AttributeValueMemberS{Value: entityCategory},
+		},
+	})
+
+	var categories []domain.Category
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query categories: %w", err)
+		}
+
+		var batch []domain.Category
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &batch); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal categories: %w", err)
+		}
+		categories = append(categories, batch...)
+	}
+	return categories, nil
+}
+
+// HasProductsInCategory scans for any product, in any status, that still
+// belongs to the category, stopping at the first match
+func (r *DynamoDBRepository) HasProductsInCategory(ctx context.Context, categoryID string) (bool, error) {
+	paginator := dynamodb.NewScanPaginator(r.client, productScan(&dynamodb.ScanInput{
+		TableName:            aws.String(r.tableName),
+		FilterExpression:     aws.String("#category_id = :category_id"),
+		ProjectionExpression: aws.String("#id"),
+		ExpressionAttributeNames: map[string]string{
+			"#id":          "id",
+			"#category_id": "category_id",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":category_id": &types.AttributeValueMemberS{Value: categoryID},
+		},
+	}))
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return false, fmt.Errorf("failed to scan products by category: %w", err)
+		}
+		if len(page.Items) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// RecountCategories sets every category's product count to the number of
+// products, in any status, that belong to it, for counts that predate
+// their adjustment on product writes. Products written during the recount
+// may be missed, so it is best run while writes are stopped.
+func (r *DynamoDBRepository) RecountCategories(ctx context.Context, logger *slog.Logger) (int, error) {
+	counts := map[string]int{}
+	paginator := dynamodb.NewScanPaginator(r.client, productScan(&dynamodb.ScanInput{
+		TableName:                aws.String(r.tableName),
+		ProjectionExpression:     aws.String("#category_id"),
+		ExpressionAttributeNames: map[string]string{"#category_id": "category_id"},
+	}))
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to scan product categories: %w", err)
+		}
+		for _, item := range page.Items {
+			if category, ok := item["category_id"].(*types.AttributeValueMemberS); ok && category.Value != "" {
+				counts[category.Value]++
+			}
+		}
+	}
+
+	categories, err := NewDynamoDBCategoryRepository(r.client, r.tableName).List(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, category := range categories {
+		_, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName:                aws.String(r.tableName),
+			Key:                      categoryKey(category.ID),
+			UpdateExpression:         aws.String("SET #product_count = :count"),
+			ExpressionAttributeNames: map[string]string{"#product_count": productCountAttribute},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":count": &types.AttributeValueMemberN{Value: strconv.Itoa(counts[category.ID])},
+			},
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to recount category %s: %w", category.ID, err)
+		}
+	}
+	logger.InfoContext(ctx, "recounted category products", "categories", len(categories))
+	return len(categories), nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// ListArchivedBefore scans for unexpired archived products whose
+// updated_at falls before before, stopping once limit products were found.
+// updated_at is stored as an RFC 3339 string, so it compares as one.
+func (r *DynamoDBRepository) ListArchivedBefore(ctx context.Context, before time.Time, limit int) ([]domain.Product, error) {
+	cutoff, err := attributevalue.Marshal(before.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal cutoff: %w", err)
+	}
+
+	names := map[string]string{
+		"#status":     "status",
+		"#updated_at": "updated_at",
+	}
+	values := map[string]types.AttributeValue{
+		":archived": &types.AttributeValueMemberS{Value: domain.StatusArchived},
+		":before":   cutoff,
+	}
+	filter := "#status = :archived AND #updated_at < :before AND " + notExpiredCondition(time.Now().UTC(), names, values)
+	paginator := dynamodb.NewScanPaginator(r.client, productScan(&dynamodb.ScanInput{
+		TableName:                 aws.String(r.tableName),
+		FilterExpression:          aws.String(filter),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	}))
+
+	var products []domain.Product
+	for paginator.HasMorePages() && len(products) < limit {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan archived products: %w", err)
+		}
+
+		batch, err := decodeProducts(page.Items)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal archived products: %w", err)
+		}
+		products = append(products, batch...)
+	}
+	if len(products) > limit {
+		products = products[:limit]
+	}
+	return products, nil
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// counterAttempts bounds the reads of a product whose counters change with
+// a review or a favorite, when other writes keep changing it in between
+const counterAttempts = 3
+
+// errCounterItemFailed reports that the condition on the item written
+// alongside a counter update failed
+var errCounterItemFailed = errors.New("counter item condition failed")
+
+// productCounters changes the counters kept on product items, such as
+// rating totals and favorite counts, in the same transaction as the item
+// they count. With an outbox the product.updated event of the product it
+// leaves behind is written with them, like any other product write.
+type productCounters struct {
+	client      *dynamodb.Client
+	tableName   string
+	outboxTable string
+}
+
+// write runs item together with update on one of tenant's products. With
+// an outbox the product is read first and update made conditional on its
+// version, so the event built by applying change to it is exact; a write
+// in between makes it read the product again, up to counterAttempts times
+// before giving up with domain.ErrConflict. A failed condition on item
+// returns errCounterItemFailed and a missing product domain.ErrNotFound.
+func (c productCounters) write(ctx context.Context, tenant, productID string, item types.TransactWriteItem, update *types.Update, change func(*domain.Product)) error {
+	for attempt := 1; ; attempt++ {
+		items := []types.TransactWriteItem{item, {Update: update}}
+		if c.outboxTable != "" {
+			product, err := c.read(ctx, tenant, productID)
+			if err != nil {
+				return err
+			}
+			versioned, event, err := c.versioned(update, product, change)
+			if err != nil {
+				return err
+			}
+			items = []types.TransactWriteItem{item, {Update: versioned}, event}
+		}
+
+		_, err := c.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items})
+		var canceled *types.TransactionCanceledException
+		if !errors.As(err, &canceled) {
+			return err
+		}
+		reasons := canceled.CancellationReasons
+		switch {
+		case len(reasons) > 0 && aws.ToString(reasons[0].Code) == "ConditionalCheckFailed":
+			return errCounterItemFailed
+		case len(reasons) > 1 && aws.ToString(reasons[1].Code) == "ConditionalCheckFailed":
+			// The old product tells a missing one from one changed since
+			// it was read
+			old := reasons[1].Item
+			if old == nil || itemTenant(old) != tenant {
+				return domain.ErrNotFound
+			}
+			if attempt == counterAttempts {
+				return domain.ErrConflict
+			}
+		default:
+			return err
+		}
+	}
+}
+
+// read reads the product whose counters change, consistently
+func (c productCounters) read(ctx context.Context, tenant, productID string) (domain.Product, error) {
+	result, err := c.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(c.tableName),
+		Key:            productKey(productID),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return domain.Product{}, err
+	}
+	if result.Item == nil || itemTenant(result.Item) != tenant {
+		return domain.Product{}, domain.ErrNotFound
+	}
+	return decodeProduct(result.Item)
+}
+
+// versioned returns a copy of update conditional on the version of product
+// and the outbox put of the product.updated event of the result
+func (c productCounters) versioned(update *types.Update, product domain.Product, change func(*domain.Product)) (*types.Update, types.TransactWriteItem, error) {
+	versioned := *update
+	versioned.ExpressionAttributeValues = maps.Clone(update.ExpressionAttributeValues)
+	condition, values := versionCondition(product.Version)
+	maps.Copy(versioned.ExpressionAttributeValues, values)
+	versioned.ConditionExpression = aws.String(aws.ToString(update.ConditionExpression) + " AND " + condition)
+	versioned.ReturnValuesOnConditionCheckFailure = types.ReturnValuesOnConditionCheckFailureAllOld
+
+	updated := product
+	change(&updated)
+	updated.Version++
+	outboxItem, err := newOutboxItem(domain.NewProductEvent(domain.EventProductUpdated, product.ID, &updated, time.Now().UTC()))
+	if err != nil {
+		return nil, types.TransactWriteItem{}, err
+	}
+	return &versioned, types.TransactWriteItem{Put: &types.Put{
+		TableName: aws.String(c.outboxTable),
+		Item:      outboxItem,
+	}}, nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"encoding/json"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// pageCursor is the resume position of an index query: the key of the last
+// returned item in every partition, the partitions already exhausted and
+// the total counted on the first page
+type pageCursor struct {
+	Keys  map[string]map[string]keyAttribute `json:"k,omitempty"`
+	Done  []string                           `json:"d,omitempty"`
+	Total *int                               `json:"t,omitempty"`
+}
+
+// keyAttribute holds a string or number key attribute
+type keyAttribute struct {
+	S string `json:"s,omitempty"`
+	N string `json:"n,omitempty"`
+}
+
+func decodePageCursor(data []byte) (*pageCursor, error) {
+	cursor := &pageCursor{}
+	if len(data) == 0 {
+		return cursor, nil
+	}
+	if err := json.Unmarshal(data, cursor); err != nil {
+		return nil, domain.ErrInvalidCursor
+	}
+	return cursor, nil
+}
+
+func (c *pageCursor) encode() ([]byte, error) {
+	return json.Marshal(c)
+}
+
+func (c *pageCursor) isDone(partition string) bool {
+	for _, done := range c.Done {
+		if done == partition {
+			return true
+		}
+	}
+	return false
+}
+
+// startKey returns the ExclusiveStartKey for a partition, or nil to start
+// from the beginning
+func (c *pageCursor) startKey(partition string) map[string]types.AttributeValue {
+	attributes, ok := c.Keys[partition]
+	if !ok {
+		return nil
+	}
+	key := make(map[string]types.AttributeValue, len(attributes))
+	for name, attribute := range attributes {
+		if attribute.N != "" {
+			key[name] = &types.AttributeValueMemberN{Value: attribute.N}
+		} else {
+			key[name] = &types.AttributeValueMemberS{Value: attribute.S}
+		}
+	}
+	return key
+}
+
+func (c *pageCursor) setStartKey(partition string, key map[string]types.AttributeValue) {
+	attributes := make(map[string]keyAttribute, len(key))
+	for name, value := range key {
+		switch v := value.(type) {
+		case *types.AttributeValueMemberS:
+			attributes[name] = keyAttribute{S: v.Value}
+		case *types.AttributeValueMemberN:
+			attributes[name] = keyAttribute{N: v.Value}
+		}
+	}
+	if c.Keys == nil {
+		c.Keys = make(map[string]map[string]keyAttribute)
+	}
+	c.Keys[partition] = attributes
+}
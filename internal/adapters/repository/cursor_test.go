@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+func TestPageCursor(t *testing.T) {
+	cursor := &pageCursor{Done: []string{"PRODUCT#1"}}
+	cursor.setStartKey("PRODUCT#0", map[string]types.AttributeValue{
+		"id":     &types.AttributeValueMemberS{Value: "abc"},
+		"gsi_pk": &types.AttributeValueMemberS{Value: "PRODUCT#0"},
+		"price":  &types.AttributeValueMemberN{Value: "10.5"},
+	})
+
+	data, err := cursor.encode()
+	require.NoError(t, err)
+
+	decoded, err := decodePageCursor(data)
+	require.NoError(t, err)
+	assert.True(t, decoded.isDone("PRODUCT#1"))
+	assert.False(t, decoded.isDone("PRODUCT#0"))
+	assert.Nil(t, decoded.startKey("PRODUCT#1"))
+	assert.Equal(t, map[string]types.AttributeValue{
+		"id":     &types.AttributeValueMemberS{Value: "abc"},
+		"gsi_pk": &types.AttributeValueMemberS{Value: "PRODUCT#0"},
+		"price":  &types.AttributeValueMemberN{Value: "10.5"},
+	}, decoded.startKey("PRODUCT#0"))
+
+	_, err = decodePageCursor([]byte("{"))
+	assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+}
+
+func TestListWithFilters_CursorCarriesTotal(t *testing.T) {
+	repo, operations, _ := recordingRepository(http.StatusOK, `{"Count":1,"Items":[{"id":{"S":"a"},"price":{"N":"10"}}]}`)
+	result, err := repo.ListWithFilters(context.Background(), ports.ProductFilters{
+		SortBy:   "price",
+		Limit:    1,
+		StartKey: []byte(`{"t":7}`),
+	})
+	require.NoError(t, err)
+
+	// Later pages reuse the first page's total instead of counting again
+	assert.Equal(t, []string{"Query"}, *operations)
+	assert.Equal(t, 7, result.TotalItems)
+}
@@ -1,9 +1,15 @@
 package repository
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
@@ -11,144 +17,282 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
 	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"golang.org/x/sync/errgroup"
 )
 
 type DynamoDBRepository struct {
-	client    *dynamodb.Client
-	tableName string
+	client      *dynamodb.Client
+	tableName   string
+	shards      int
+	outboxTable string
+	uniqueTable string
+	// scanSegments and scanWorkers configure parallel scans, see
+	// WithParallelScan
+	scanSegments int
+	scanWorkers  int
+	onWrite      ProductWriteHook
 }
 
-func NewDynamoDBRepository(client *dynamodb.Client, tableName string) *DynamoDBRepository {
-	return &DynamoDBRepository{
+// Option customizes a DynamoDBRepository
+type Option func(*DynamoDBRepository)
+
+// WithIndexShards spreads the index partition key over n shards so writes
+// during traffic spikes don't throttle on a single hot partition. Reads fan
+// out to every shard and merge the results.
+func WithIndexShards(n int) Option {
+	return func(r *DynamoDBRepository) {
+		if n > 1 {
+			r.shards = n
+		}
+	}
+}
+
+// WithWriteHook calls hook after every write of a product item the
+// repository makes, whichever method made it, so a cache in front of the
+// repository can drop its copy even of writes made around it
+func WithWriteHook(hook ProductWriteHook) Option {
+	return func(r *DynamoDBRepository) {
+		r.onWrite = hook
+	}
+}
+
+func NewDynamoDBRepository(client *dynamodb.Client, tableName string, opts ...Option) *DynamoDBRepository {
+	r := &DynamoDBRepository{
 		client:    client,
 		tableName: tableName,
+		shards:    1,
 	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 func (r *DynamoDBRepository) Save(ctx context.Context, product domain.Product) error {
-	item, err := attributevalue.MarshalMap(product)
+	item, err := r.toItem(product)
+	if err != nil {
+		return err
+	}
+	unique, err := r.uniqueWrites(nil, &product)
 	if err != nil {
-		return fmt.Errorf("failed to marshal product: %w", err)
+		return err
 	}
 
-	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
-		TableName: aws.String(r.tableName),
-		Item:      item,
-	})
+	// Creates never replace a product: an existing ID, chosen by the
+	// client or repeated by a retry, is a duplicate
+	err = r.write(ctx, types.TransactWriteItem{Put: &types.Put{
+		TableName:                aws.String(r.tableName),
+		Item:                     item,
+		ConditionExpression:      aws.String("attribute_not_exists(#pk)"),
+		ExpressionAttributeNames: map[string]string{"#pk": partitionKeyAttribute},
+	}}, func(err error) error {
+		var conditionFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionFailed) {
+			return &domain.DuplicateError{Field: domain.FieldID, Value: product.ID}
+		}
+		return err
+	}, unique...)
+	if err == nil {
+		r.onWrite.call(ctx, product.TenantID, product.ID)
+	}
 	return err
 }
 
 func (r *DynamoDBRepository) GetByID(ctx context.Context, id string) (domain.Product, error) {
 	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
-		TableName: aws.String(r.tableName),
-		Key: map[string]types.AttributeValue{
-			"id": &types.AttributeValueMemberS{Value: id},
-		},
+		TableName:      aws.String(r.tableName),
+		Key:            productKey(id),
+		ConsistentRead: aws.Bool(ports.ConsistentRead(ctx)),
 	})
 	if err != nil {
 		return domain.Product{}, err
 	}
 	if result.Item == nil {
-		return domain.Product{}, domain.ErrNotFound
+		return domain.Product{}, domain.ErrNotFound.With("id", id)
 	}
 
-	var product domain.Product
-	err = attributevalue.UnmarshalMap(result.Item, &product)
-	return product, err
+	product, err := decodeProduct(result.Item)
+	if err != nil {
+		return domain.Product{}, err
+	}
+	// Product IDs are unique across tenants, so the key alone finds the
+	// item; other tenants' products are reported as missing
+	if product.TenantID != ports.TenantID(ctx) {
+		return domain.Product{}, domain.ErrNotFound.With("id", id)
+	}
+	// TTL deletion lags behind expiration, so hide expired items ourselves
+	if product.IsExpired(time.Now().UTC()) {
+		return domain.Product{}, domain.ErrNotFound.With("id", id)
+	}
+	return product, nil
 }
 
+// Update overwrites the product only if nobody else wrote it since it was
+// read, bumping its version. The version check also guarantees that the
+// identifiers read to release dropped reservations are still current.
 func (r *DynamoDBRepository) Update(ctx context.Context, product domain.Product) error {
-	return r.Save(ctx, product) // PutItem overwrites
-}
+	expected := product.Version
+	product.Version++
+	item, err := r.toItem(product)
+	if err != nil {
+		return err
+	}
+	var unique []uniqueWrite
+	if r.uniqueTable != "" {
+		stored, err := r.storedUniqueValues(ctx, product.ID)
+		if err != nil {
+			return err
+		}
+		if unique, err = r.uniqueWrites(stored, &product); err != nil {
+			return err
+		}
+	}
 
-func (r *DynamoDBRepository) Delete(ctx context.Context, id string) error {
-	_, err := r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
-		TableName: aws.String(r.tableName),
-		Key: map[string]types.AttributeValue{
-			"id": &types.AttributeValueMemberS{Value: id},
-		},
-	})
+	condition, values := versionCondition(expected)
+	if values == nil {
+		values = map[string]types.AttributeValue{}
+	}
+	names := map[string]string{"#pk": partitionKeyAttribute, "#version": "version"}
+	// The product keeps the tenant it was read with
+	condition += " AND " + tenantCondition(product.TenantID, names, values)
+	if len(values) == 0 {
+		// DynamoDB rejects an empty value map
+		values = nil
+	}
+	err = r.write(ctx, types.TransactWriteItem{Put: &types.Put{
+		TableName:                 aws.String(r.tableName),
+		Item:                      item,
+		ConditionExpression:       aws.String("attribute_exists(#pk) AND " + condition),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	}}, func(err error) error {
+		return conditionalUpdateError(err, "failed to update product")
+	}, unique...)
+	if err == nil {
+		r.onWrite.call(ctx, product.TenantID, product.ID)
+	}
 	return err
 }
 
-func (r *DynamoDBRepository) List(ctx context.Context) ([]domain.Product, error) {
-	result, err := r.client.Scan(ctx, &dynamodb.ScanInput{
-		TableName: aws.String(r.tableName),
-	})
-	if err != nil {
-		return nil, err
+// versionCondition matches items still at version expected. Version 0 stands
+// for items written before versioning, which have no version attribute.
+func versionCondition(expected int64) (string, map[string]types.AttributeValue) {
+	if expected == 0 {
+		return "attribute_not_exists(#version)", nil
+	}
+	return "#version = :expected_version", map[string]types.AttributeValue{
+		":expected_version": &types.AttributeValueMemberN{Value: strconv.FormatInt(expected, 10)},
 	}
+}
 
-	var products []domain.Product
-	err = attributevalue.UnmarshalListOfMaps(result.Items, &products)
-	return products, err
+// Delete removes the product only if it belongs to the context's tenant
+// and is still at version, releasing its SKU and barcode. A product of the
+// tenant that changed since is left in place with domain.ErrConflict.
+func (r *DynamoDBRepository) Delete(ctx context.Context, id string, version int64) error {
+	return r.deleteWithEvents(ctx, id, version, ports.OutboxEvents(ctx))
 }
 
-func (r *DynamoDBRepository) ListWithFilters(ctx context.Context, filters ports.ProductFilters) (*ports.ProductListResult, error) {
-	// Build scan input with filters
-	scanInput := &dynamodb.ScanInput{
-		TableName:         aws.String(r.tableName),
-		Limit:             aws.Int32(int32(filters.Limit)),
-		ExclusiveStartKey: nil, // Will be set for pagination
-	}
-
-	// Build filter expression if filters are applied
-	var filterExpression strings.Builder
-	var expressionAttributeNames map[string]string
-	var expressionAttributeValues map[string]types.AttributeValue
-
-	if filters.Name != "" || filters.MinPrice > 0 || filters.MaxPrice > 0 {
-		expressionAttributeNames = make(map[string]string)
-		expressionAttributeValues = make(map[string]types.AttributeValue)
-		var conditions []string
-
-		// Name filter (contains)
-		if filters.Name != "" {
-			conditions = append(conditions, "contains(#name, :name)")
-			expressionAttributeNames["#name"] = "name"
-			expressionAttributeValues[":name"] = &types.AttributeValueMemberS{Value: filters.Name}
-		}
+// deleteCondition matches a product of tenant still at version
+func deleteCondition(tenant string, version int64) (string, map[string]string, map[string]types.AttributeValue) {
+	condition, values := versionCondition(version)
+	if values == nil {
+		values = map[string]types.AttributeValue{}
+	}
+	names := map[string]string{"#pk": partitionKeyAttribute, "#version": "version"}
+	return "attribute_exists(#pk) AND " + tenantCondition(tenant, names, values) + " AND " + condition, names, values
+}
 
-		// Price filters
-		if filters.MinPrice > 0 {
-			conditions = append(conditions, "price >= :min_price")
-			expressionAttributeValues[":min_price"] = &types.AttributeValueMemberN{Value: fmt.Sprintf("%.2f", filters.MinPrice)}
+// deleteWithEvents is Delete with the outbox events given explicitly
+func (r *DynamoDBRepository) deleteWithEvents(ctx context.Context, id string, version int64, events []domain.ProductEvent) error {
+	tenant := ports.TenantID(ctx)
+	condition, names, values := deleteCondition(tenant, version)
+	var unique []uniqueWrite
+	if r.uniqueTable != "" {
+		stored, err := r.storedUniqueValues(ctx, id)
+		if err != nil {
+			return err
 		}
-
-		if filters.MaxPrice > 0 {
-			conditions = append(conditions, "price <= :max_price")
-			expressionAttributeValues[":max_price"] = &types.AttributeValueMemberN{Value: fmt.Sprintf("%.2f", filters.MaxPrice)}
+		if stored == nil {
+			return domain.ErrNotFound.With("id", id)
+		}
+		if unique, err = r.uniqueWrites(stored, nil); err != nil {
+			return err
 		}
+		// The reservations released are the ones read above
+		condition += " AND " + unchangedIdentifiers(stored, names, values)
+	}
+	if len(values) == 0 {
+		values = nil
+	}
+	err := r.writeWithEvents(ctx, types.TransactWriteItem{Delete: &types.Delete{
+		TableName:                           aws.String(r.tableName),
+		Key:                                 productKey(id),
+		ConditionExpression:                 aws.String(condition),
+		ExpressionAttributeNames:            names,
+		ExpressionAttributeValues:           values,
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	}}, events, unique, func(err error) error {
+		var conditionFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionFailed) {
+			// The item that failed the condition tells a missing product
+			// from one that was modified
+			if conditionFailed.Item != nil && itemTenant(conditionFailed.Item) == tenant {
+				return domain.ErrConflict.With("id", id)
+			}
+			return domain.ErrNotFound.With("id", id)
+		}
+		return err
+	})
+	if err == nil {
+		r.onWrite.call(ctx, tenant, id)
+	}
+	return err
+}
 
-		// Combine conditions
-		filterExpression.WriteString(strings.Join(conditions, " AND "))
-		scanInput.FilterExpression = aws.String(filterExpression.String())
-		scanInput.ExpressionAttributeNames = expressionAttributeNames
-		scanInput.ExpressionAttributeValues = expressionAttributeValues
+func (r *DynamoDBRepository) List(ctx context.Context) ([]domain.Product, error) {
+	scanInput := &dynamodb.ScanInput{
+		TableName:      aws.String(r.tableName),
+		ConsistentRead: aws.Bool(ports.ConsistentRead(ctx)),
 	}
+	scanInput.FilterExpression, scanInput.ExpressionAttributeNames, scanInput.ExpressionAttributeValues =
+		buildFilterExpression(ports.ProductFilters{}, ports.TenantID(ctx), time.Now().UTC())
 
-	// Execute scan
-	result, err := r.client.Scan(ctx, scanInput)
+	result, err := r.client.Scan(ctx, productScan(scanInput))
 	if err != nil {
-		return nil, fmt.Errorf("failed to scan products: %w", err)
+		return nil, err
 	}
 
-	// Unmarshal products
+	return decodeProducts(result.Items)
+}
+
+// ListWithFilters counts the total in the same read that fills the page,
+// so every matching item is read once rather than once more by a separate
+// count scan
+func (r *DynamoDBRepository) ListWithFilters(ctx context.Context, filters ports.ProductFilters) (*ports.ProductListResult, error) {
+	now := time.Now().UTC()
+
+	plan := r.planQuery(filters, ports.ConsistentRead(ctx))
+
 	var products []domain.Product
-	err = attributevalue.UnmarshalListOfMaps(result.Items, &products)
-	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal products: %w", err)
+	var nextKey []byte
+	var totalItems int
+	var err error
+	switch {
+	case plan.Operation == operationQuery && !plan.SortInMemory:
+		index, _ := indexForField(filters.SortBy)
+		products, nextKey, totalItems, err = r.queryIndex(ctx, index, filters, now)
+	case len(filters.StartKey) > 0:
+		// Cursors only resume ordered index reads
+		return nil, domain.ErrInvalidCursor
+	case plan.Operation == operationQuery:
+		products, totalItems, err = r.queryPriceRange(ctx, filters, now)
+	default:
+		products, totalItems, err = r.scanFiltered(ctx, filters, now)
 	}
-
-	// Get total count for pagination
-	totalItems, err := r.getTotalCount(ctx, filters)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get total count: %w", err)
+		return nil, err
 	}
 
-	// Sort products in memory (DynamoDB Scan doesn't guarantee order)
-	products = r.sortProducts(products, filters.SortBy, filters.SortOrder)
-
 	// Apply offset for pagination
 	if filters.Offset < len(products) {
 		products = products[filters.Offset:]
@@ -161,116 +305,719 @@ func (r *DynamoDBRepository) ListWithFilters(ctx context.Context, filters ports.
 		products = products[:filters.Limit]
 	}
 
-	return &ports.ProductListResult{
+	// Repositories hold no logger of their own; the request's, when there
+	// is one, ties the access path to the request that needed it
+	ports.Logger(ctx, slog.Default()).DebugContext(ctx, "products listed",
+		"operation", plan.Operation,
+		"index", plan.Index,
+		"partitions", plan.Partitions,
+		"returned", len(products),
+		"total", totalItems,
+	)
+
+	result := &ports.ProductListResult{
 		Products:   products,
 		TotalItems: totalItems,
-	}, nil
+		NextKey:    nextKey,
+	}
+	if filters.Explain {
+		result.Plan = &plan
+	}
+	return result, nil
 }
 
-func (r *DynamoDBRepository) getTotalCount(ctx context.Context, filters ports.ProductFilters) (int, error) {
-	scanInput := &dynamodb.ScanInput{
-		TableName: aws.String(r.tableName),
-		Select:    types.SelectCount,
+// indexEntry is a product read from an index along with its index key
+type indexEntry struct {
+	product   domain.Product
+	partition string
+	key       map[string]types.AttributeValue
+}
+
+// partitionResult holds the entries read from one index partition
+type partitionResult struct {
+	partition string
+	entries   []indexEntry
+	exhausted bool
+	// matched counts every item of the partition matching the filters,
+	// read or not; it is only counted for a listing's first page
+	matched int
+}
+
+// queryIndex reads the sort index in order until offset+limit matching
+// items have been collected, resuming from filters.StartKey when set. With
+// sharding enabled every shard is queried concurrently and the ordered
+// results are merged. It also returns the resume position after the last
+// returned item, or nil when every partition has been read to the end,
+// and the total. The first page counts the items it did not read with
+// Select COUNT queries; its cursor carries the total to the next pages.
+func (r *DynamoDBRepository) queryIndex(ctx context.Context, index IndexSchema, filters ports.ProductFilters, now time.Time) ([]domain.Product, []byte, int, error) {
+	cursor, err := decodePageCursor(filters.StartKey)
+	if err != nil {
+		return nil, nil, 0, err
 	}
 
-	// Apply same filters for count
-	if filters.Name != "" || filters.MinPrice > 0 || filters.MaxPrice > 0 {
-		var filterExpression strings.Builder
-		var expressionAttributeNames map[string]string
-		var expressionAttributeValues map[string]types.AttributeValue
-		var conditions []string
-
-		if filters.Name != "" {
-			conditions = append(conditions, "contains(#name, :name)")
-			if expressionAttributeNames == nil {
-				expressionAttributeNames = make(map[string]string)
-			}
-			if expressionAttributeValues == nil {
-				expressionAttributeValues = make(map[string]types.AttributeValue)
-			}
-			expressionAttributeNames["#name"] = "name"
-			expressionAttributeValues[":name"] = &types.AttributeValueMemberS{Value: filters.Name}
+	tenantPartitions := r.indexPartitions(ports.TenantID(ctx))
+	var partitions []string
+	for _, partition := range tenantPartitions {
+		if !cursor.isDone(partition) {
+			partitions = append(partitions, partition)
 		}
+	}
 
-		if filters.MinPrice > 0 {
-			conditions = append(conditions, "price >= :min_price")
-			if expressionAttributeValues == nil {
-				expressionAttributeValues = make(map[string]types.AttributeValue)
-			}
-			expressionAttributeValues[":min_price"] = &types.AttributeValueMemberN{Value: fmt.Sprintf("%.2f", filters.MinPrice)}
+	wanted := filters.Offset + filters.Limit
+	results := make([]partitionResult, len(partitions))
+	g, gctx := errgroup.WithContext(ctx)
+	for i, partition := range partitions {
+		g.Go(func() error {
+			result, err := r.queryPartition(gctx, index, partition, cursor.startKey(partition), filters, now, wanted)
+			results[i] = result
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, nil, 0, err
+	}
+
+	total := 0
+	switch {
+	case len(filters.StartKey) == 0:
+		for _, result := range results {
+			total += result.matched
 		}
+	case cursor.Total != nil:
+		total = *cursor.Total
+	default:
+		// Cursors issued before they carried the total
+		if total, err = r.getTotalCount(ctx, filters); err != nil {
+			return nil, nil, 0, fmt.Errorf("failed to get total count: %w", err)
+		}
+	}
 
-		if filters.MaxPrice > 0 {
-			conditions = append(conditions, "price <= :max_price")
-			if expressionAttributeValues == nil {
-				expressionAttributeValues = make(map[string]types.AttributeValue)
-			}
-			expressionAttributeValues[":max_price"] = &types.AttributeValueMemberN{Value: fmt.Sprintf("%.2f", filters.MaxPrice)}
+	entries := make(map[string]indexEntry)
+	var products []domain.Product
+	for _, result := range results {
+		for _, entry := range result.entries {
+			entries[entry.product.ID] = entry
+			products = append(products, entry.product)
 		}
+	}
+	// Keyset pages are ordered by ID within a sort value, and secondary sort
+	// fields order the ties, neither of which the index guarantees
+	if len(results) > 1 || filters.After != nil || len(filters.ThenBy) > 0 {
+		products = r.sortProducts(products, filters)
+	}
+	if len(products) > wanted {
+		products = products[:wanted]
+	}
 
-		if len(conditions) > 0 {
-			filterExpression.WriteString(strings.Join(conditions, " AND "))
-			scanInput.FilterExpression = aws.String(filterExpression.String())
-			scanInput.ExpressionAttributeNames = expressionAttributeNames
-			scanInput.ExpressionAttributeValues = expressionAttributeValues
+	// Every partition resumes after its last returned item; one that was read
+	// to the end with all of its items returned needs no further reads
+	consumed := make(map[string]int)
+	next := &pageCursor{Done: cursor.Done, Total: &total}
+	for _, product := range products {
+		entry := entries[product.ID]
+		consumed[entry.partition]++
+		next.setStartKey(entry.partition, entry.key)
+	}
+	for _, result := range results {
+		if result.exhausted && consumed[result.partition] == len(result.entries) {
+			next.Done = append(next.Done, result.partition)
+			delete(next.Keys, result.partition)
+		} else if consumed[result.partition] == 0 {
+			if key := cursor.startKey(result.partition); key != nil {
+				next.setStartKey(result.partition, key)
+			}
 		}
 	}
+	// A page reordered by secondary fields is not a prefix of the index
+	// order, so there is no position to resume from
+	if len(next.Done) == len(tenantPartitions) || len(filters.ThenBy) > 0 {
+		return products, nil, total, nil
+	}
 
-	result, err := r.client.Scan(ctx, scanInput)
+	nextKey, err := next.encode()
 	if err != nil {
-		return 0, err
+		return nil, nil, 0, fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return products, nextKey, total, nil
+}
+
+func (r *DynamoDBRepository) queryPartition(ctx context.Context, index IndexSchema, partition string, startKey map[string]types.AttributeValue, filters ports.ProductFilters, now time.Time, wanted int) (partitionResult, error) {
+	remaining, priceRange := priceRangeKeyFilters(index, filters)
+	remaining, timeRange := timeRangeKeyFilters(index, remaining)
+	filterExpression, names, values := buildFilterExpression(remaining, ports.TenantID(ctx), now)
+	condition := keyCondition(partition, filters, priceRange, names, values)
+	if timeRange {
+		condition += " AND " + timeRangeKeyCondition(index, filters, names, values)
+	}
+	// A range on the sort key leaves the keyset position to afterSortKey
+	keyset := filters.After != nil && !priceRange && !timeRange
+	if keyset {
+		condition += " AND " + keysetKeyCondition(filters, names, values)
 	}
 
-	return int(result.Count), nil
+	paginator := dynamodb.NewQueryPaginator(r.client, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.tableName),
+		IndexName:                 aws.String(index.Name),
+		KeyConditionExpression:    aws.String(condition),
+		FilterExpression:          filterExpression,
+		ProjectionExpression:      projectionExpression(filters, names),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+		ScanIndexForward:          aws.Bool(filters.SortOrder != "desc"),
+		ExclusiveStartKey:         startKey,
+	})
+
+	result := partitionResult{partition: partition}
+	var lastKey map[string]types.AttributeValue
+	// Keyset pages and secondary sort fields reorder the ties at the end of
+	// the page, so the whole tie group is read
+	reorders := filters.After != nil || len(filters.ThenBy) > 0
+	for paginator.HasMorePages() && (len(result.entries) < wanted || reorders && tiedAtBoundary(result.entries, wanted, index.Keys.RangeKey)) {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return result, fmt.Errorf("failed to query products: %w", err)
+		}
+		lastKey = page.LastEvaluatedKey
+
+		for _, item := range page.Items {
+			product, err := decodeProduct(item)
+			if err != nil {
+				return result, err
+			}
+			if !afterSortKey(product, filters) {
+				result.matched++
+				continue
+			}
+			key := productKey(product.ID)
+			key[indexPartitionAttribute] = &types.AttributeValueMemberS{Value: partition}
+			key[index.Keys.RangeKey] = item[index.Keys.RangeKey]
+			result.entries = append(result.entries, indexEntry{product: product, partition: partition, key: key})
+		}
+	}
+	result.exhausted = !paginator.HasMorePages()
+	if len(filters.StartKey) > 0 {
+		return result, nil
+	}
+
+	// The items past the page, and before the keyset position when the
+	// key condition left them out, are counted without being read
+	result.matched += len(result.entries)
+	if !result.exhausted {
+		var keysetCondition func(ports.ProductFilters, map[string]string, map[string]types.AttributeValue) string
+		if keyset {
+			keysetCondition = keysetKeyCondition
+		}
+		rest, err := r.countPartition(ctx, index, partition, lastKey, filters, keysetCondition, now)
+		if err != nil {
+			return result, err
+		}
+		result.matched += rest
+	}
+	if keyset {
+		before, err := r.countPartition(ctx, index, partition, nil, filters, keysetBeforeKeyCondition, now)
+		if err != nil {
+			return result, err
+		}
+		result.matched += before
+	}
+	return result, nil
 }
 
-func (r *DynamoDBRepository) sortProducts(products []domain.Product, sortBy, sortOrder string) []domain.Product {
-	if len(products) <= 1 {
-		return products
+// countPartition counts the items of one index partition matching filters
+// from startKey on, narrowed by the keyset key condition when given
+func (r *DynamoDBRepository) countPartition(ctx context.Context, index IndexSchema, partition string, startKey map[string]types.AttributeValue, filters ports.ProductFilters, keyset func(ports.ProductFilters, map[string]string, map[string]types.AttributeValue) string, now time.Time) (int, error) {
+	remaining, priceRange := priceRangeKeyFilters(index, filters)
+	remaining, timeRange := timeRangeKeyFilters(index, remaining)
+	filterExpression, names, values := buildFilterExpression(remaining, ports.TenantID(ctx), now)
+	condition := keyCondition(partition, filters, priceRange, names, values)
+	if timeRange {
+		condition += " AND " + timeRangeKeyCondition(index, filters, names, values)
+	}
+	if keyset != nil {
+		condition += " AND " + keyset(filters, names, values)
 	}
 
-	// Simple bubble sort for demonstration - in production, consider more efficient sorting
-	sorted := make([]domain.Product, len(products))
-	copy(sorted, products)
-
-	// Define comparison function based on sort field
-	var compare func(i, j int) bool
-	switch sortBy {
-	case "name":
-		compare = func(i, j int) bool {
-			if sortOrder == "desc" {
-				return sorted[i].Name > sorted[j].Name
+	paginator := dynamodb.NewQueryPaginator(r.client, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.tableName),
+		IndexName:                 aws.String(index.Name),
+		KeyConditionExpression:    aws.String(condition),
+		FilterExpression:          filterExpression,
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+		ScanIndexForward:          aws.Bool(filters.SortOrder != "desc"),
+		ExclusiveStartKey:         startKey,
+		Select:                    types.SelectCount,
+	})
+	total := 0
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to count products: %w", err)
+		}
+		total += int(page.Count)
+	}
+	return total, nil
+}
+
+// queryPriceRange reads every product in the requested price range from the
+// price index, querying the shards concurrently, and sorts them in memory,
+// for sort fields that have no declared index. It also returns how many
+// products are in the range, before and after the keyset position.
+func (r *DynamoDBRepository) queryPriceRange(ctx context.Context, filters ports.ProductFilters, now time.Time) ([]domain.Product, int, error) {
+	index, _ := indexForField(priceAttribute)
+	remaining, _ := priceRangeKeyFilters(index, filters)
+
+	tenant := ports.TenantID(ctx)
+	partitions := r.indexPartitions(tenant)
+	results := make([][]domain.Product, len(partitions))
+	matched := make([]int, len(partitions))
+	g, gctx := errgroup.WithContext(ctx)
+	for i, partition := range partitions {
+		g.Go(func() error {
+			filterExpression, names, values := buildFilterExpression(remaining, tenant, now)
+			condition := keyCondition(partition, filters, true, names, values)
+			paginator := dynamodb.NewQueryPaginator(r.client, &dynamodb.QueryInput{
+				TableName:                 aws.String(r.tableName),
+				IndexName:                 aws.String(index.Name),
+				KeyConditionExpression:    aws.String(condition),
+				FilterExpression:          filterExpression,
+				ProjectionExpression:      projectionExpression(filters, names),
+				ExpressionAttributeNames:  names,
+				ExpressionAttributeValues: values,
+			})
+			for paginator.HasMorePages() {
+				page, err := paginator.NextPage(gctx)
+				if err != nil {
+					return fmt.Errorf("failed to query price range: %w", err)
+				}
+				batch, err := decodeProducts(page.Items)
+				if err != nil {
+					return err
+				}
+				matched[i] += len(batch)
+				for _, product := range batch {
+					if afterSortKey(product, filters) {
+						results[i] = append(results[i], product)
+					}
+				}
 			}
-			return sorted[i].Name < sorted[j].Name
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, 0, err
+	}
+
+	var products []domain.Product
+	total := 0
+	for i, batch := range results {
+		products = append(products, batch...)
+		total += matched[i]
+	}
+	return r.sortProducts(products, filters), total, nil
+}
+
+// scanFiltered scans the table and sorts in memory, for sort fields that
+// have no declared index. It also returns how many products matched.
+func (r *DynamoDBRepository) scanFiltered(ctx context.Context, filters ports.ProductFilters, now time.Time) ([]domain.Product, int, error) {
+	// Every matching item is read: a scan returns them unordered, so the
+	// page can only be cut after sorting. The keyset position is applied in
+	// memory, so the same scan counts the products before it.
+	scanInput := &dynamodb.ScanInput{
+		TableName:      aws.String(r.tableName),
+		ConsistentRead: aws.Bool(ports.ConsistentRead(ctx)),
+	}
+	scanInput.FilterExpression, scanInput.ExpressionAttributeNames, scanInput.ExpressionAttributeValues =
+		buildFilterExpression(filters, ports.TenantID(ctx), now)
+	scanInput.ProjectionExpression = projectionExpression(filters, scanInput.ExpressionAttributeNames)
+
+	var products []domain.Product
+	total := 0
+	err := r.parallelScan(ctx, productScan(scanInput), func(page *dynamodb.ScanOutput) error {
+		decoded, err := decodeProducts(page.Items)
+		if err != nil {
+			return err
 		}
-	case "price":
-		compare = func(i, j int) bool {
-			if sortOrder == "desc" {
-				return sorted[i].Price > sorted[j].Price
+		total += len(decoded)
+		for _, product := range decoded {
+			if afterSortKey(product, filters) {
+				products = append(products, product)
 			}
-			return sorted[i].Price < sorted[j].Price
 		}
-	case "created_at":
-		fallthrough
-	default:
-		compare = func(i, j int) bool {
-			if sortOrder == "desc" {
-				return sorted[i].CreatedAt.After(sorted[j].CreatedAt)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Sort products in memory (DynamoDB Scan doesn't guarantee order)
+	return r.sortProducts(products, filters), total, nil
+}
+
+// Count counts the products matching filters with a Select COUNT read, on
+// the price index for a price range alone and over a scan otherwise
+func (r *DynamoDBRepository) Count(ctx context.Context, filters ports.ProductFilters) (int, error) {
+	return r.getTotalCount(ctx, filters)
+}
+
+func (r *DynamoDBRepository) getTotalCount(ctx context.Context, filters ports.ProductFilters) (int, error) {
+	if index, ok := indexForField(priceAttribute); ok && onlyPriceRange(filters) && !ports.ConsistentRead(ctx) {
+		return r.countPriceRange(ctx, index, filters)
+	}
+
+	scanInput := &dynamodb.ScanInput{
+		TableName:      aws.String(r.tableName),
+		Select:         types.SelectCount,
+		ConsistentRead: aws.Bool(ports.ConsistentRead(ctx)),
+	}
+
+	// Apply same filters for count
+	scanInput.FilterExpression, scanInput.ExpressionAttributeNames, scanInput.ExpressionAttributeValues =
+		buildFilterExpression(filters, ports.TenantID(ctx), time.Now().UTC())
+
+	total := 0
+	err := r.parallelScan(ctx, productScan(scanInput), func(page *dynamodb.ScanOutput) error {
+		total += int(page.Count)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// countPriceRange counts the products in a price range on the price index,
+// reading only the items inside the range instead of scanning the table
+func (r *DynamoDBRepository) countPriceRange(ctx context.Context, index IndexSchema, filters ports.ProductFilters) (int, error) {
+	remaining, _ := priceRangeKeyFilters(index, filters)
+	now := time.Now().UTC()
+	tenant := ports.TenantID(ctx)
+
+	total := 0
+	for _, partition := range r.indexPartitions(tenant) {
+		filterExpression, names, values := buildFilterExpression(remaining, tenant, now)
+		condition := keyCondition(partition, filters, true, names, values)
+		paginator := dynamodb.NewQueryPaginator(r.client, &dynamodb.QueryInput{
+			TableName:                 aws.String(r.tableName),
+			IndexName:                 aws.String(index.Name),
+			KeyConditionExpression:    aws.String(condition),
+			FilterExpression:          filterExpression,
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+			Select:                    types.SelectCount,
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return 0, err
 			}
-			return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+			total += int(page.Count)
 		}
 	}
+	return total, nil
+}
+
+// notExpiredCondition matches items whose expires_at has not passed at now,
+// binding :now. Every scan of products applies it, since DynamoDB TTL can
+// take days to purge expired items.
+func notExpiredCondition(now time.Time, names map[string]string, values map[string]types.AttributeValue) string {
+	names["#expires_at"] = "expires_at"
+	values[":now"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Unix(), 10)}
+	return "(attribute_not_exists(#expires_at) OR #expires_at > :now)"
+}
+
+// buildFilterExpression builds the filter for the given filters. Items
+// whose expiration has passed are always excluded because DynamoDB TTL can
+// take up to a few days to actually delete them, and so are drafts,
+// archived products, products held by content moderation and products of
+// tenants other than tenant. A status filter lists that status instead of
+// the published products. Published products follow their schedule to the
+// second: drafts past publish_at are listed and products past
+// auto_archive_at are not, before the jobs get to them. IncludeHidden
+// keeps only the tenant condition of these.
+func buildFilterExpression(filters ports.ProductFilters, tenant string, now time.Time) (*string, map[string]string, map[string]types.AttributeValue) {
+	expressionAttributeNames := map[string]string{}
+	expressionAttributeValues := map[string]types.AttributeValue{}
+	var conditions []string
+	if !filters.IncludeHidden {
+		conditions = visibilityConditions(filters.Status, now, expressionAttributeNames, expressionAttributeValues)
+	}
+	conditions = append(conditions, tenantCondition(tenant, expressionAttributeNames, expressionAttributeValues))
 
-	// Simple insertion sort
-	for i := 1; i < len(sorted); i++ {
-		key := sorted[i]
-		j := i - 1
-		for j >= 0 && compare(j, j+1) {
-			sorted[j+1] = sorted[j]
-			j--
+	// Name filter (contains)
+	if filters.Name != "" {
+		conditions = append(conditions, "contains(#name, :name)")
+		expressionAttributeNames["#name"] = "name"
+		expressionAttributeValues[":name"] = &types.AttributeValueMemberS{Value: filters.Name}
+	}
+
+	if filters.CategoryID != "" {
+		conditions = append(conditions, "#category_id = :category_id")
+		expressionAttributeNames["#category_id"] = "category_id"
+		expressionAttributeValues[":category_id"] = &types.AttributeValueMemberS{Value: filters.CategoryID}
+	}
+
+	// Tag filters: lists match an element with contains
+	if len(filters.Tags) > 0 {
+		expressionAttributeNames["#tags"] = "tags"
+		tagConditions := make([]string, len(filters.Tags))
+		for i, tag := range filters.Tags {
+			placeholder := fmt.Sprintf(":tag%d", i)
+			tagConditions[i] = fmt.Sprintf("contains(#tags, %s)", placeholder)
+			expressionAttributeValues[placeholder] = &types.AttributeValueMemberS{Value: tag}
+		}
+		join := " OR "
+		if filters.TagMatch == domain.TagMatchAll {
+			join = " AND "
+		}
+		conditions = append(conditions, "("+strings.Join(tagConditions, join)+")")
+	}
+
+	// Prices only compare within one currency; items written before prices
+	// had one are in the default currency
+	if currency := filters.PriceCurrency(); currency != "" {
+		expressionAttributeNames["#currency"] = currencyAttribute
+		expressionAttributeValues[":currency"] = &types.AttributeValueMemberS{Value: currency}
+		if currency == domain.DefaultCurrency {
+			conditions = append(conditions, "(attribute_not_exists(#currency) OR #currency = :currency)")
+		} else {
+			conditions = append(conditions, "#currency = :currency")
 		}
-		sorted[j+1] = key
 	}
 
+	// Price filters
+	if !filters.MinPrice.IsZero() {
+		conditions = append(conditions, "price >= :min_price")
+		expressionAttributeValues[":min_price"] = &types.AttributeValueMemberN{Value: filters.MinPrice.String()}
+	}
+
+	if !filters.MaxPrice.IsZero() {
+		conditions = append(conditions, "price <= :max_price")
+		expressionAttributeValues[":max_price"] = &types.AttributeValueMemberN{Value: filters.MaxPrice.String()}
+	}
+
+	// Time bounds compare the stored RFC 3339 strings
+	for _, bound := range timeBounds(filters) {
+		expressionAttributeNames["#"+bound.field] = bound.field
+		expressionAttributeValues[bound.placeholder] = timeValue(bound.value)
+		conditions = append(conditions, fmt.Sprintf("#%s %s %s", bound.field, bound.operator, bound.placeholder))
+	}
+
+	return aws.String(strings.Join(conditions, " AND ")), expressionAttributeNames, expressionAttributeValues
+}
+
+// visibilityConditions match the products listed in status, or the
+// published ones: unexpired and approved by content moderation
+func visibilityConditions(status string, now time.Time, names map[string]string, values map[string]types.AttributeValue) []string {
+	names["#status"] = "status"
+	names["#moderation_status"] = "moderation_status"
+	values[":approved"] = &types.AttributeValueMemberS{Value: domain.ModerationApproved}
+	notExpired := notExpiredCondition(now, names, values)
+	statusCondition := "#status = :status"
+	if status == "" || status == domain.StatusPublished {
+		statusCondition = "(attribute_not_exists(#status) OR #status = :status OR (#status = :draft AND #publish_at <= :now))" +
+			" AND (attribute_not_exists(#auto_archive_at) OR #auto_archive_at > :now)"
+		names["#publish_at"] = "publish_at"
+		names["#auto_archive_at"] = "auto_archive_at"
+		values[":draft"] = &types.AttributeValueMemberS{Value: domain.StatusDraft}
+	}
+	values[":status"] = &types.AttributeValueMemberS{Value: cmp.Or(status, domain.StatusPublished)}
+	return []string{
+		notExpired,
+		statusCondition,
+		"(attribute_not_exists(#moderation_status) OR #moderation_status = :approved)",
+	}
+}
+
+// timeBound is one side of a creation or update time range
+type timeBound struct {
+	field       string
+	operator    string
+	placeholder string
+	value       time.Time
+}
+
+// timeBounds lists the time bounds set in filters. Like price bounds they
+// are inclusive, so a range on an index key is exactly a BETWEEN.
+func timeBounds(filters ports.ProductFilters) []timeBound {
+	var bounds []timeBound
+	for _, bound := range []timeBound{
+		{"created_at", ">=", ":created_after", filters.CreatedAfter},
+		{"created_at", "<=", ":created_before", filters.CreatedBefore},
+		{"updated_at", ">=", ":updated_after", filters.UpdatedAfter},
+		{"updated_at", "<=", ":updated_before", filters.UpdatedBefore},
+	} {
+		if !bound.value.IsZero() {
+			bounds = append(bounds, bound)
+		}
+	}
+	return bounds
+}
+
+// timeValue is t as the attributevalue package stores times: an RFC 3339
+// string in UTC
+func timeValue(t time.Time) types.AttributeValue {
+	return &types.AttributeValueMemberS{Value: t.UTC().Format(time.RFC3339Nano)}
+}
+
+// projectableFields are the product attributes a projection may select
+var projectableFields = map[string]bool{
+	"id":                 true,
+	"name":               true,
+	"description":        true,
+	"price":              true,
+	"created_at":         true,
+	"updated_at":         true,
+	"expires_at":         true,
+	"status":             true,
+	"publish_at":         true,
+	"auto_archive_at":    true,
+	"category_id":        true,
+	"tags":               true,
+	"stock":              true,
+	"images":             true,
+	"version":            true,
+	"sku":                true,
+	"barcode":            true,
+	"moderation_status":  true,
+	"moderation_reasons": true,
+	"translations":       true,
+}
+
+// projectionExpression limits reads to the requested fields plus the ID and
+// sort field, registering attribute name placeholders in names. It returns
+// nil, reading whole items, when no fields were requested.
+func projectionExpression(filters ports.ProductFilters, names map[string]string) *string {
+	if len(filters.Fields) == 0 {
+		return nil
+	}
+
+	selected := []string{"id"}
+	seen := map[string]bool{"id": true}
+	add := func(field string) {
+		if projectableFields[field] && !seen[field] {
+			seen[field] = true
+			selected = append(selected, field)
+		}
+	}
+	for _, field := range filters.Fields {
+		add(field)
+	}
+	add(filters.SortBy)
+	for _, field := range filters.ThenBy {
+		add(field)
+	}
+	// A price is only whole with its currency
+	if seen[priceAttribute] {
+		selected = append(selected, currencyAttribute)
+	}
+
+	placeholders := make([]string, len(selected))
+	for i, field := range selected {
+		placeholder := "#f_" + field
+		names[placeholder] = field
+		placeholders[i] = placeholder
+	}
+	return aws.String(strings.Join(placeholders, ", "))
+}
+
+// toItem marshals a product together with its key and the attributes the
+// table's indexes are keyed on. The price is stored as a number in major units,
+// which the price index sorts on and filters compare, next to its currency.
+func (r *DynamoDBRepository) toItem(product domain.Product) (map[string]types.AttributeValue, error) {
+	item, err := attributevalue.MarshalMap(product)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal product: %w", err)
+	}
+	item[priceAttribute] = &types.AttributeValueMemberN{Value: product.Price.DecimalString()}
+	item[currencyAttribute] = &types.AttributeValueMemberS{Value: product.Price.Currency}
+	if product.CostPrice != nil {
+		item[costPriceAttribute] = &types.AttributeValueMemberN{Value: product.CostPrice.DecimalString()}
+	}
+	item[indexPartitionAttribute] = &types.AttributeValueMemberS{Value: r.indexPartition(product.TenantID, product.ID)}
+	return withKey(item, productKey(product.ID), entityProduct), nil
+}
+
+// decodeProduct unmarshals a product item, the inverse of toItem. Items
+// written before prices had a currency are in domain.DefaultCurrency.
+func decodeProduct(item map[string]types.AttributeValue) (domain.Product, error) {
+	// Items the migration has not renamed yet still read whole
+	item = currentProductAttributes(item)
+	var product domain.Product
+	if err := attributevalue.UnmarshalMap(item, &product); err != nil {
+		return domain.Product{}, fmt.Errorf("failed to unmarshal product: %w", err)
+	}
+
+	currency := domain.DefaultCurrency
+	if value, ok := item[currencyAttribute].(*types.AttributeValueMemberS); ok && value.Value != "" {
+		currency = value.Value
+	}
+	// Projections that leave out the price leave it zero
+	if price, ok := item[priceAttribute].(*types.AttributeValueMemberN); ok {
+		amount, err := decodeAmount(price.Value, currency)
+		if err != nil {
+			return domain.Product{}, fmt.Errorf("invalid price %w", err)
+		}
+		product.Price = amount
+	}
+	// The cost is in the price's currency; costs written as floats round to
+	// its minor unit
+	if cost, ok := item[costPriceAttribute].(*types.AttributeValueMemberN); ok {
+		amount, err := decodeAmount(cost.Value, currency)
+		if err != nil {
+			return domain.Product{}, fmt.Errorf("invalid cost price %w", err)
+		}
+		product.CostPrice = &amount
+	}
+	return product, nil
+}
+
+// decodeAmount reads a number attribute holding major units of currency
+func decodeAmount(value, currency string) (domain.Money, error) {
+	amount, err := domain.ParseDecimal(value)
+	if err != nil {
+		return domain.Money{}, fmt.Errorf("%q: %w", value, err)
+	}
+	money, err := domain.MoneyFromDecimal(amount, currency)
+	if err != nil {
+		return domain.Money{}, fmt.Errorf("%s %s: %w", value, currency, err)
+	}
+	return money, nil
+}
+
+// decodeProducts unmarshals a page of product items
+func decodeProducts(items []map[string]types.AttributeValue) ([]domain.Product, error) {
+	products := make([]domain.Product, 0, len(items))
+	for _, item := range items {
+		product, err := decodeProduct(item)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+	return products, nil
+}
+
+// sortProducts orders products by the sort field and then the secondary
+// ones, breaking ties by ID so the order is the same on every read. The
+// order being total, an unstable sort gives the same result; it sorts
+// indexes, since swapping whole products dominates on large scans.
+func (r *DynamoDBRepository) sortProducts(products []domain.Product, filters ports.ProductFilters) []domain.Product {
+	sortBy := append([]string{filters.SortBy}, filters.ThenBy...)
+	order := make([]int, len(products))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortFunc(order, func(i, j int) int {
+		if filters.SortOrder == "desc" {
+			i, j = j, i
+		}
+		return ports.CompareProductsBy(&products[i], &products[j], sortBy)
+	})
+	sorted := make([]domain.Product, len(products))
+	for i, index := range order {
+		sorted[i] = products[index]
+	}
 	return sorted
 }
@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+func TestProjectionExpression(t *testing.T) {
+	names := map[string]string{}
+	assert.Nil(t, projectionExpression(ports.ProductFilters{SortBy: "price"}, names))
+	assert.Empty(t, names)
+
+	expression := projectionExpression(ports.ProductFilters{Fields: []string{"name", "id", "unknown"}, SortBy: "price"}, names)
+	if assert.NotNil(t, expression) {
+		assert.Equal(t, "#f_id, #f_name, #f_price, #f_currency", *expression)
+	}
+	assert.Equal(t, map[string]string{"#f_id": "id", "#f_name": "name", "#f_price": "price", "#f_currency": "currency"}, names)
+}
+
+// Projections and the name filter name attributes as a marshalled product
+// stores them; a name it lacks would select or match nothing
+func TestProjectionAndNameFilter_MatchStoredAttributes(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	product := domain.Product{
+		ID:                "p1",
+		Name:              "Gaming Laptop",
+		Description:       "Fast",
+		Price:             domain.Money{Amount: 99900, Currency: "USD"},
+		CreatedAt:         now,
+		UpdatedAt:         now,
+		ExpiresAt:         &now,
+		Status:            domain.StatusPublished,
+		PublishAt:         &now,
+		AutoArchiveAt:     &now,
+		CategoryID:        "c1",
+		Tags:              []string{"sale"},
+		Stock:             3,
+		Images:            []domain.ProductImage{{Key: "k", URL: "u"}},
+		Version:           1,
+		SKU:               "SKU-1",
+		Barcode:           "4006381333931",
+		ModerationStatus:  "approved",
+		ModerationReasons: []string{"ok"},
+		Translations:      map[string]domain.Translation{"es": {Name: "Portátil"}},
+	}
+	item, err := NewDynamoDBRepository(nil, "products").toItem(product)
+	require.NoError(t, err)
+
+	for field := range projectableFields {
+		assert.Contains(t, item, field)
+	}
+	names := map[string]string{}
+	projectionExpression(ports.ProductFilters{Fields: []string{"name", "created_at"}, SortBy: "updated_at"}, names)
+	for _, attribute := range names {
+		assert.Contains(t, item, attribute)
+	}
+
+	_, names, values := buildFilterExpression(ports.ProductFilters{Name: "Laptop"}, "", now)
+	if assert.Contains(t, item, names["#name"]) {
+		assert.Contains(t, item[names["#name"]].(*types.AttributeValueMemberS).Value, values[":name"].(*types.AttributeValueMemberS).Value)
+	}
+}
+
+func TestVersionCondition(t *testing.T) {
+	condition, values := versionCondition(0)
+	assert.Equal(t, "attribute_not_exists(#version)", condition)
+	assert.Nil(t, values)
+
+	condition, values = versionCondition(4)
+	assert.Equal(t, "#version = :expected_version", condition)
+	assert.Equal(t, &types.AttributeValueMemberN{Value: "4"}, values[":expected_version"])
+}
+
+func TestBuildFilterExpression_Tags(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	expression, names, values := buildFilterExpression(ports.ProductFilters{Tags: []string{"sale", "gaming"}}, "", now)
+	assert.True(t, strings.HasSuffix(*expression, " AND (contains(#tags, :tag0) OR contains(#tags, :tag1))"), *expression)
+	assert.Equal(t, "tags", names["#tags"])
+	assert.Equal(t, &types.AttributeValueMemberS{Value: "gaming"}, values[":tag1"])
+
+	expression, _, _ = buildFilterExpression(ports.ProductFilters{Tags: []string{"sale", "gaming"}, TagMatch: domain.TagMatchAll}, "", now)
+	assert.True(t, strings.HasSuffix(*expression, " AND (contains(#tags, :tag0) AND contains(#tags, :tag1))"), *expression)
+
+	expression, names, _ = buildFilterExpression(ports.ProductFilters{}, "", now)
+	assert.NotContains(t, *expression, "#tags")
+	assert.NotContains(t, names, "#tags")
+}
+
+func TestBuildFilterExpression_Currency(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	expression, _, values := buildFilterExpression(ports.ProductFilters{MinPrice: domain.NewDecimal(10, 0)}, "", now)
+	assert.Contains(t, *expression, "(attribute_not_exists(#currency) OR #currency = :currency)")
+	assert.Equal(t, &types.AttributeValueMemberS{Value: domain.DefaultCurrency}, values[":currency"])
+
+	expression, _, values = buildFilterExpression(ports.ProductFilters{SortBy: "price", Currency: "EUR"}, "", now)
+	assert.Contains(t, *expression, " AND #currency = :currency")
+	assert.Equal(t, &types.AttributeValueMemberS{Value: "EUR"}, values[":currency"])
+
+	expression, names, _ := buildFilterExpression(ports.ProductFilters{SortBy: "name"}, "", now)
+	assert.NotContains(t, *expression, "#currency")
+	assert.NotContains(t, names, "#currency")
+}
+
+func TestBuildFilterExpression_Status(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	expression, names, values := buildFilterExpression(ports.ProductFilters{}, "", now)
+	assert.Contains(t, *expression, "(#status = :draft AND #publish_at <= :now)")
+	assert.Contains(t, *expression, "#auto_archive_at > :now")
+	assert.Equal(t, "publish_at", names["#publish_at"])
+	assert.Equal(t, &types.AttributeValueMemberS{Value: domain.StatusPublished}, values[":status"])
+
+	expression, names, values = buildFilterExpression(ports.ProductFilters{Status: domain.StatusArchived}, "", now)
+	assert.Contains(t, *expression, " #status = :status AND ")
+	assert.NotContains(t, names, "#publish_at")
+	assert.Equal(t, &types.AttributeValueMemberS{Value: domain.StatusArchived}, values[":status"])
+}
+
+func TestDecodeProduct_Price(t *testing.T) {
+	product, err := decodeProduct(map[string]types.AttributeValue{
+		"id":    &types.AttributeValueMemberS{Value: "1"},
+		"price": &types.AttributeValueMemberN{Value: "19.99"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Money{Amount: 1999, Currency: domain.DefaultCurrency}, product.Price)
+
+	product, err = decodeProduct(map[string]types.AttributeValue{
+		"id":       &types.AttributeValueMemberS{Value: "2"},
+		"price":    &types.AttributeValueMemberN{Value: "1500"},
+		"currency": &types.AttributeValueMemberS{Value: "JPY"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Money{Amount: 1500, Currency: "JPY"}, product.Price)
+
+	// Prices once written from float arithmetic round to the minor unit
+	product, err = decodeProduct(map[string]types.AttributeValue{
+		"id":    &types.AttributeValueMemberS{Value: "3"},
+		"price": &types.AttributeValueMemberN{Value: "0.30000000000000004"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Money{Amount: 30, Currency: domain.DefaultCurrency}, product.Price)
+
+	product, err = decodeProduct(map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "4"}})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Money{}, product.Price)
+}
+
+func TestCostPrice_RoundTrip(t *testing.T) {
+	repo := NewDynamoDBRepository(nil, "products")
+	cost := domain.Money{Amount: 650, Currency: "EUR"}
+	item, err := repo.toItem(domain.Product{ID: "1", Price: domain.Money{Amount: 1000, Currency: "EUR"}, CostPrice: &cost})
+	require.NoError(t, err)
+	assert.Equal(t, &types.AttributeValueMemberN{Value: "6.50"}, item["cost_price"])
+
+	product, err := decodeProduct(item)
+	require.NoError(t, err)
+	assert.Equal(t, &cost, product.CostPrice)
+
+	// Costs once written as floats round to the price's minor unit
+	product, err = decodeProduct(map[string]types.AttributeValue{
+		"id":         &types.AttributeValueMemberS{Value: "2"},
+		"price":      &types.AttributeValueMemberN{Value: "1500"},
+		"currency":   &types.AttributeValueMemberS{Value: "JPY"},
+		"cost_price": &types.AttributeValueMemberN{Value: "899.9999999999999"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &domain.Money{Amount: 900, Currency: "JPY"}, product.CostPrice)
+
+	item, err = repo.toItem(domain.Product{ID: "3", Price: domain.Money{Amount: 1000, Currency: "USD"}})
+	require.NoError(t, err)
+	assert.NotContains(t, item, "cost_price")
+}
+
+func TestBuildFilterExpression_PriceIsExact(t *testing.T) {
+	filters := ports.ProductFilters{MinPrice: domain.NewDecimal(1, 1), MaxPrice: domain.NewDecimal(123456789012345678, 3)}
+	_, _, values := buildFilterExpression(filters, "", time.Now())
+	assert.Equal(t, &types.AttributeValueMemberN{Value: "0.1"}, values[":min_price"])
+	assert.Equal(t, &types.AttributeValueMemberN{Value: "123456789012345.678"}, values[":max_price"])
+}
+
+// The jobs and admin views scan products outside the listing filter, so
+// each must leave out expired items TTL has not purged yet
+func TestScans_ExcludeExpired(t *testing.T) {
+	now := time.Now().UTC()
+	scans := map[string]func(*DynamoDBRepository) error{
+		"publishing": func(r *DynamoDBRepository) error {
+			_, err := r.ListDueForPublishing(context.Background(), now)
+			return err
+		},
+		"archival": func(r *DynamoDBRepository) error {
+			_, err := r.ListScheduledForArchival(context.Background(), now)
+			return err
+		},
+		"cold storage": func(r *DynamoDBRepository) error {
+			_, err := r.ListArchivedBefore(context.Background(), now, 10)
+			return err
+		},
+		"moderation queue": func(r *DynamoDBRepository) error {
+			_, err := r.ListPendingReview(context.Background())
+			return err
+		},
+		"margin report": func(r *DynamoDBRepository) error {
+			_, err := r.ListCosted(context.Background())
+			return err
+		},
+	}
+	for name, scan := range scans {
+		t.Run(name, func(t *testing.T) {
+			repo, operations, bodies := recordingRepository(http.StatusOK, `{"Items":[]}`)
+			require.NoError(t, scan(repo))
+			require.Equal(t, []string{"Scan"}, *operations)
+			assert.Contains(t, (*bodies)[0], "(attribute_not_exists(#expires_at) OR #expires_at \u003e :now)")
+			assert.Contains(t, (*bodies)[0], `"#expires_at":"expires_at"`)
+		})
+	}
+}
+
+// BenchmarkSortProducts sorts the result sets of in-memory sorts, which
+// hold every matching product of a scan
+func BenchmarkSortProducts(b *testing.B) {
+	repo := &DynamoDBRepository{}
+	for _, size := range []int{10_000, 50_000} {
+		products := make([]domain.Product, size)
+		for i := range products {
+			// Few distinct prices, so secondary fields have ties to order
+			products[i] = domain.Product{
+				ID:        fmt.Sprintf("prod-%06d", i),
+				Name:      fmt.Sprintf("Product %d", i*7919%size),
+				Price:     domain.Money{Amount: int64(i * 31 % 500 * 100), Currency: "USD"},
+				CreatedAt: time.Unix(int64(i*104729%size), 0),
+			}
+		}
+		for _, filters := range []ports.ProductFilters{
+			{SortBy: "name"},
+			{SortBy: "price"},
+			{SortBy: "price", ThenBy: []string{"name"}},
+			{SortBy: "created_at", ThenBy: []string{"price", "name"}, SortOrder: "desc"},
+		} {
+			name := strings.Join(append([]string{filters.SortBy}, filters.ThenBy...), ",")
+			b.Run(fmt.Sprintf("%s/%d", name, size), func(b *testing.B) {
+				for b.Loop() {
+					repo.sortProducts(products, filters)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// ErrorsAPIOption classifies the errors of every call of a DynamoDB client
+// into domain kinds, so services and handlers can react to a throttled or
+// timed out table without knowing the SDK. The SDK error stays in the
+// chain for errors.As. Install it last, through dynamodb.NewFromConfig(cfg,
+// func(o *dynamodb.Options) { o.APIOptions = append(o.APIOptions,
+// ErrorsAPIOption) }), so it also sees the errors of the other middleware.
+func ErrorsAPIOption(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("ClassifyErrors",
+		func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+			out, metadata, err := next.HandleInitialize(ctx, in)
+			return out, metadata, classifyError(err)
+		}), middleware.Before)
+}
+
+// retryable matches the errors the SDK retries by default: throttling,
+// server errors and broken connections. Once its retries are used up they
+// are still worth retrying later.
+var retryable = retry.IsErrorRetryables(retry.DefaultRetryables)
+
+// classifyError wraps conflicts between concurrent transactions in
+// domain.ErrConflict and transient failures in domain.ErrUnavailable.
+// Other errors, conditional check failures included, are left to the
+// repository, which knows what each condition meant.
+func classifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var conflict *types.TransactionConflictException
+	if errors.As(err, &conflict) || hasCancellationReason(err, "TransactionConflict") {
+		return domain.ErrConflict.Wrap(err)
+	}
+	if errors.Is(err, context.DeadlineExceeded) || isThrottle(err) || hasCancellationReason(err, "ThrottlingError") ||
+		retryable.IsErrorRetryable(err) == aws.TrueTernary {
+		return domain.ErrUnavailable.Wrap(err)
+	}
+	return err
+}
+
+// hasCancellationReason reports whether err canceled a transaction with
+// the given reason code on any of its items
+func hasCancellationReason(err error, code string) bool {
+	var canceled *types.TransactionCanceledException
+	if !errors.As(err, &canceled) {
+		return false
+	}
+	for _, reason := range canceled.CancellationReasons {
+		if aws.ToString(reason.Code) == code {
+			return true
+		}
+	}
+	return false
+}
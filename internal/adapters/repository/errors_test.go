@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+func TestClassifyError(t *testing.T) {
+	canceled := func(code string) error {
+		return &types.TransactionCanceledException{CancellationReasons: []types.CancellationReason{{Code: aws.String("None")}, {Code: aws.String(code)}}}
+	}
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"throttled", &types.ProvisionedThroughputExceededException{}, domain.ErrUnavailable},
+		{"timed out", fmt.Errorf("operation error: %w", context.DeadlineExceeded), domain.ErrUnavailable},
+		{"throttled transaction", canceled("ThrottlingError"), domain.ErrUnavailable},
+		{"concurrent transaction", &types.TransactionConflictException{}, domain.ErrConflict},
+		{"conflicting transaction item", canceled("TransactionConflict"), domain.ErrConflict},
+		{"condition failed", &types.ConditionalCheckFailedException{}, nil},
+		{"missing table", &types.ResourceNotFoundException{}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classifyError(tt.err)
+			assert.ErrorIs(t, err, tt.err, "the SDK error stays in the chain")
+			if tt.want == nil {
+				assert.Equal(t, tt.err, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+	assert.NoError(t, classifyError(nil))
+	assert.NotErrorIs(t, classifyError(context.Canceled), domain.ErrUnavailable, "callers that gave up are not an outage")
+}
+
+func TestErrorsAPIOption(t *testing.T) {
+	client := dynamodb.New(dynamodb.Options{
+		Region:           "us-east-1",
+		Credentials:      aws.AnonymousCredentials{},
+		RetryMaxAttempts: 1,
+		HTTPClient: stubTransport{status: http.StatusBadRequest,
+			body: `{"__type":"com.amazonaws.dynamodb.v20120810#ProvisionedThroughputExceededException","message":"slow down"}`},
+		APIOptions: []func(*middleware.Stack) error{ErrorsAPIOption},
+	})
+	repo := NewDynamoDBRepository(client, "products")
+
+	_, err := repo.GetByID(context.Background(), "p1")
+	assert.ErrorIs(t, err, domain.ErrUnavailable)
+	var throttled *types.ProvisionedThroughputExceededException
+	assert.True(t, errors.As(err, &throttled))
+}
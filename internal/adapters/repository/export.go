@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// streamPageSize is how many items each scan page evaluates, bounding the
+// memory a stream holds at once
+const streamPageSize = 500
+
+// StreamProducts scans the table page by page with the same visibility,
+// filter conditions and field selection as listings. With parallel scans
+// configured the segments are read concurrently, fn still receiving one
+// page at a time.
+func (r *DynamoDBRepository) StreamProducts(ctx context.Context, filters ports.ProductFilters, fn func(page []domain.Product) error) error {
+	filter, names, values := buildFilterExpression(filters, ports.TenantID(ctx), time.Now().UTC())
+	input := &dynamodb.ScanInput{
+		TableName:                 aws.String(r.tableName),
+		FilterExpression:          filter,
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+		ProjectionExpression:      projectionExpression(filters, names),
+		Limit:                     aws.Int32(streamPageSize),
+	}
+
+	return r.parallelScan(ctx, productScan(input), func(result *dynamodb.ScanOutput) error {
+		if len(result.Items) == 0 {
+			return nil
+		}
+		page, err := decodeProducts(result.Items)
+		if err != nil {
+			return err
+		}
+		return fn(page)
+	})
+}
@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// favoriteItem is one favorite in the item collection of its user, sorted
+// by product ID
+type favoriteItem struct {
+	UserID    string    `dynamodbav:"user_id"`
+	ProductID string    `dynamodbav:"product_id"`
+	TenantID  string    `dynamodbav:"tenant_id,omitempty"`
+	CreatedAt time.Time `dynamodbav:"created_at"`
+}
+
+// DynamoDBFavoriteRepository stores favorites in their own table. With a
+// products table it also keeps each product's favorite_count, changed in
+// the same transaction as the favorite.
+type DynamoDBFavoriteRepository struct {
+	client        *dynamodb.Client
+	tableName     string
+	productsTable string
+	counters      productCounters
+	onWrite       ProductWriteHook
+}
+
+// NewDynamoDBFavoriteRepository keeps favorite counts on the items of
+// productsTable, or none when it is empty. Whenever a count changes it
+// writes a product.updated event to outboxTable, unless it is empty, and
+// calls onWrite, which may be nil.
+func NewDynamoDBFavoriteRepository(client *dynamodb.Client, tableName, productsTable, outboxTable string, onWrite ProductWriteHook) *DynamoDBFavoriteRepository {
+	return &DynamoDBFavoriteRepository{
+		client:        client,
+		tableName:     tableName,
+		productsTable: productsTable,
+		counters:      productCounters{client: client, tableName: productsTable, outboxTable: outboxTable},
+		onWrite:       onWrite,
+	}
+}
+
+func (r *DynamoDBFavoriteRepository) Add(ctx context.Context, favorite domain.Favorite) (bool, error) {
+	item, err := attributevalue.MarshalMap(favoriteItem(favorite))
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal favorite: %w", err)
+	}
+	put := &types.Put{
+		TableName:                aws.String(r.tableName),
+		Item:                     item,
+		ConditionExpression:      aws.String("attribute_not_exists(#product_id)"),
+		ExpressionAttributeNames: map[string]string{"#product_id": "product_id"},
+	}
+
+	if r.productsTable == "" {
+		_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName:                put.TableName,
+			Item:                     put.Item,
+			ConditionExpression:      put.ConditionExpression,
+			ExpressionAttributeNames: put.ExpressionAttributeNames,
+		})
+		if failedCondition(err) == 0 {
+			err = errCounterItemFailed
+		}
+	} else {
+		err = r.counters.write(ctx, favorite.TenantID, favorite.ProductID, types.TransactWriteItem{Put: put},
+			favoriteCountUpdate(r.productsTable, favorite.TenantID, favorite.ProductID, 1), addFavorites(1))
+	}
+	switch {
+	case errors.Is(err, errCounterItemFailed):
+		return false, nil
+	case errors.Is(err, domain.ErrNotFound), errors.Is(err, domain.ErrConflict):
+		return false, err
+	case err != nil:
+		return false, fmt.Errorf("failed to add favorite: %w", err)
+	}
+	if r.productsTable != "" {
+		r.onWrite.call(ctx, favorite.TenantID, favorite.ProductID)
+	}
+	return true, nil
+}
+
+// Remove also removes favorites of products that no longer exist, whose
+// counts went with them
+func (r *DynamoDBFavoriteRepository) Remove(ctx context.Context, userID, productID string) (bool, error) {
+	input := &dynamodb.DeleteItemInput{
+		TableName:                aws.String(r.tableName),
+		Key:                      favoriteKey(userID, productID),
+		ConditionExpression:      aws.String("attribute_exists(#product_id)"),
+		ExpressionAttributeNames: map[string]string{"#product_id": "product_id"},
+	}
+
+	var err error
+	counted := false
+	if r.productsTable != "" {
+		err = r.counters.write(ctx, ports.TenantID(ctx), productID, types.TransactWriteItem{Delete: &types.Delete{
+			TableName:                input.TableName,
+			Key:                      input.Key,
+			ConditionExpression:      input.ConditionExpression,
+			ExpressionAttributeNames: input.ExpressionAttributeNames,
+		}}, favoriteCountUpdate(r.productsTable, ports.TenantID(ctx), productID, -1), addFavorites(-1))
+	}
+	if r.productsTable == "" || errors.Is(err, domain.ErrNotFound) {
+		_, err = r.client.DeleteItem(ctx, input)
+		if failedCondition(err) == 0 {
+			err = errCounterItemFailed
+		}
+	} else {
+		counted = err == nil
+	}
+	if errors.Is(err, errCounterItemFailed) {
+		return false, nil
+	}
+	if errors.Is(err, domain.ErrConflict) {
+		return false, err
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to remove favorite: %w", err)
+	}
+	if counted {
+		r.onWrite.call(ctx, ports.TenantID(ctx), productID)
+	}
+	return true, nil
+}
+
+func (r *DynamoDBFavoriteRepository) List(ctx context.Context, userID, after string, limit int) ([]domain.Favorite, error) {
+	names := map[string]string{"#user_id": "user_id"}
+	values := map[string]types.AttributeValue{
+		":user_id": &types.AttributeValueMemberS{Value: userID},
+	}
+	condition := "#user_id = :user_id"
+	if after != "" {
+		names["#product_id"] = "product_id"
+		values[":after"] = &types.AttributeValueMemberS{Value: after}
+		condition += " AND #product_id > :after"
+	}
+	filter := tenantCondition(ports.TenantID(ctx), names, values)
+
+	favorites := []domain.Favorite{}
+	var startKey map[string]types.AttributeValue
+	for {
+		result, err := r.client.Query(ctx, &dynamodb.QueryInput{
+			TableName:                 aws.String(r.tableName),
+			KeyConditionExpression:    aws.String(condition),
+			FilterExpression:          aws.String(filter),
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+			Limit:                     aws.Int32(int32(limit)),
+			ExclusiveStartKey:         startKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query favorites: %w", err)
+		}
+
+		for _, raw := range result.Items {
+			var item favoriteItem
+			if err := attributevalue.UnmarshalMap(raw, &item); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal favorite: %w", err)
+			}
+			favorites = append(favorites, domain.Favorite(item))
+			if len(favorites) == limit {
+				return favorites, nil
+			}
+		}
+		// Favorites in other tenants are filtered out after the limit is
+		// applied, so a short page does not mean the list is exhausted
+		if result.LastEvaluatedKey == nil {
+			return favorites, nil
+		}
+		startKey = result.LastEvaluatedKey
+	}
+}
+
+// favoriteCountUpdate adds delta to the favorite count of one of tenant's
+// products. Like a stock adjustment it bumps the version, so a product
+// update read before it cannot overwrite the count.
+func favoriteCountUpdate(tableName, tenant, productID string, delta int64) *types.Update {
+	names := map[string]string{
+		"#pk":             partitionKeyAttribute,
+		"#favorite_count": "favorite_count",
+		"#version":        "version",
+	}
+	values := map[string]types.AttributeValue{
+		":delta": &types.AttributeValueMemberN{Value: strconv.FormatInt(delta, 10)},
+		":one":   &types.AttributeValueMemberN{Value: "1"},
+	}
+	condition := "attribute_exists(#pk) AND " + tenantCondition(tenant, names, values)
+
+	return &types.Update{
+		TableName:                 aws.String(tableName),
+		Key:                       productKey(productID),
+		UpdateExpression:          aws.String("ADD #favorite_count :delta, #version :one"),
+		ConditionExpression:       aws.String(condition),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	}
+}
+
+// addFavorites applies a change of the favorite count to a product
+func addFavorites(delta int64) func(*domain.Product) {
+	return func(product *domain.Product) {
+		product.FavoriteCount += delta
+	}
+}
+
+// failedCondition returns the position of the write whose condition failed
+// in a single write, always 0, or in a transaction, and -1 when none did
+func failedCondition(err error) int {
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return 0
+	}
+	var canceled *types.TransactionCanceledException
+	if errors.As(err, &canceled) {
+		for i, reason := range canceled.CancellationReasons {
+			if aws.ToString(reason.Code) == "ConditionalCheckFailed" {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func favoriteKey(userID, productID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"user_id":    &types.AttributeValueMemberS{Value: userID},
+		"product_id": &types.AttributeValueMemberS{Value: productID},
+	}
+}
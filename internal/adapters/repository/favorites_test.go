@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+func TestFavoriteCountUpdate(t *testing.T) {
+	update := favoriteCountUpdate("products", "", "1", 1)
+	assert.Equal(t, "ADD #favorite_count :delta, #version :one", *update.UpdateExpression)
+	assert.Equal(t, "attribute_exists(#pk) AND attribute_not_exists(#tenant_id)", *update.ConditionExpression)
+	assert.Equal(t, &types.AttributeValueMemberN{Value: "1"}, update.ExpressionAttributeValues[":delta"])
+
+	update = favoriteCountUpdate("products", "acme", "1", -1)
+	assert.Equal(t, "attribute_exists(#pk) AND #tenant_id = :tenant_id", *update.ConditionExpression)
+	assert.Equal(t, &types.AttributeValueMemberN{Value: "-1"}, update.ExpressionAttributeValues[":delta"])
+}
+
+func TestFavoriteRepository_Add(t *testing.T) {
+	const canceled = `{"__type":"com.amazonaws.dynamodb.v20120810#TransactionCanceledException","Message":"Transaction cancelled","CancellationReasons":[{"Code":"%s"},{"Code":"%s"}]}`
+
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		wantAdded bool
+		wantErr   error
+	}{
+		{"added", http.StatusOK, `{}`, true, nil},
+		{"already a favorite", http.StatusBadRequest, fmt.Sprintf(canceled, "ConditionalCheckFailed", "None"), false, nil},
+		{"product gone", http.StatusBadRequest, fmt.Sprintf(canceled, "None", "ConditionalCheckFailed"), false, domain.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := dynamodb.New(dynamodb.Options{
+				Region:      "us-east-1",
+				Credentials: aws.AnonymousCredentials{},
+				HTTPClient:  stubTransport{status: tt.status, body: tt.body},
+			})
+			repo := NewDynamoDBFavoriteRepository(client, "favorites", "products", "", nil)
+
+			added, err := repo.Add(context.Background(), domain.Favorite{UserID: "alice", ProductID: "1"})
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantAdded, added)
+		})
+	}
+}
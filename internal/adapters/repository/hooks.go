@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// ProductWriteHook is told the tenant and ID of a product whose item was
+// written
+type ProductWriteHook func(ctx context.Context, tenant, id string)
+
+// call runs the hook, if there is one, once the write is committed: right
+// away, or when the unit of work it was enlisted in commits
+func (h ProductWriteHook) call(ctx context.Context, tenant, id string) {
+	if h != nil {
+		ports.AfterCommit(ctx, func() { h(ctx, tenant, id) })
+	}
+}
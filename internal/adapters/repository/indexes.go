@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	// indexPartitionAttribute is written on every product so the sort
+	// indexes below can hold the whole catalog under one partition value
+	// (or one per shard, see WithIndexShards)
+	indexPartitionAttribute = "gsi_pk"
+	indexPartitionValue     = "PRODUCT"
+)
+
+// productIndexes is the single source of truth for the table's GSIs. The
+// migrate command provisions them and the list query planner only routes
+// to indexes declared here, so code and table cannot drift apart.
+var productIndexes = []IndexSchema{
+	{Name: "price-index", Keys: KeySchema{HashKey: indexPartitionAttribute, RangeKey: "price"}, RangeKeyType: types.ScalarAttributeTypeN},
+	{Name: "created_at-index", Keys: KeySchema{HashKey: indexPartitionAttribute, RangeKey: "created_at"}, RangeKeyType: types.ScalarAttributeTypeS},
+	{Name: "updated_at-index", Keys: KeySchema{HashKey: indexPartitionAttribute, RangeKey: "updated_at"}, RangeKeyType: types.ScalarAttributeTypeS},
+}
+
+// ProductIndexes returns the GSIs declared for sortable and range-filterable fields
+func ProductIndexes() []IndexSchema {
+	indexes := make([]IndexSchema, len(productIndexes))
+	copy(indexes, productIndexes)
+	return indexes
+}
+
+// indexPartition returns the index partition value for a product. Without
+// sharding it is the bare prefix; with n shards the ID's hash picks a stable
+// PRODUCT#<shard> suffix. Products of a tenant other than the default one
+// are partitioned under <tenant>#PRODUCT, so index reads never cross
+// tenants.
+func (r *DynamoDBRepository) indexPartition(tenant, id string) string {
+	prefix := tenantPartitionPrefix(tenant)
+	if r.shards <= 1 {
+		return prefix
+	}
+	h := fnv.New32a()
+	h.Write([]byte(id))
+	return fmt.Sprintf("%s#%d", prefix, h.Sum32()%uint32(r.shards))
+}
+
+// indexPartitions lists every partition value a read of tenant's products
+// has to fan out to
+func (r *DynamoDBRepository) indexPartitions(tenant string) []string {
+	prefix := tenantPartitionPrefix(tenant)
+	if r.shards <= 1 {
+		return []string{prefix}
+	}
+	partitions := make([]string, r.shards)
+	for i := range partitions {
+		partitions[i] = fmt.Sprintf("%s#%d", prefix, i)
+	}
+	return partitions
+}
+
+func tenantPartitionPrefix(tenant string) string {
+	if tenant == "" {
+		return indexPartitionValue
+	}
+	return tenant + "#" + indexPartitionValue
+}
+
+// indexForField returns the declared index sorted by the given attribute
+func indexForField(field string) (IndexSchema, bool) {
+	for _, index := range productIndexes {
+		if index.Keys.RangeKey == field {
+			return index, true
+		}
+	}
+	return IndexSchema{}, false
+}
+
+// EnsureIndexes creates every declared index missing from the table, one at
+// a time, waiting for each to become active. Existing indexes whose key
+// schema differs are reported instead of being dropped and rebuilt.
+func EnsureIndexes(ctx context.Context, client *dynamodb.Client, tableName string, indexes []IndexSchema, logger *slog.Logger) error {
+	table, err := describeTable(ctx, client, tableName)
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[string]types.GlobalSecondaryIndexDescription, len(table.GlobalSecondaryIndexes))
+	for _, index := range table.GlobalSecondaryIndexes {
+		existing[aws.ToString(index.IndexName)] = index
+	}
+
+	for _, index := range indexes {
+		if current, ok := existing[index.Name]; ok {
+			if mismatches := compareKeys("index "+index.Name, index.Keys, current.KeySchema); len(mismatches) > 0 {
+				return &SchemaMismatchError{Table: tableName, Mismatches: mismatches}
+			}
+			logger.InfoContext(ctx, "index already exists", "table", tableName, "index", index.Name)
+			continue
+		}
+
+		logger.InfoContext(ctx, "creating index", "table", tableName, "index", index.Name)
+		if _, err := client.UpdateTable(ctx, createIndexInput(tableName, table, index)); err != nil {
+			return fmt.Errorf("failed to create index %q: %w", index.Name, err)
+		}
+		if err := waitForIndex(ctx, client, tableName, index.Name); err != nil {
+			return err
+		}
+		logger.InfoContext(ctx, "index active", "table", tableName, "index", index.Name)
+	}
+	return nil
+}
+
+func createIndexInput(tableName string, table *types.TableDescription, index IndexSchema) *dynamodb.UpdateTableInput {
+	create := &types.CreateGlobalSecondaryIndexAction{
+		IndexName: aws.String(index.Name),
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String(index.Keys.HashKey), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String(index.Keys.RangeKey), KeyType: types.KeyTypeRange},
+		},
+		Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+	}
+	// Provisioned tables need explicit index throughput; mirror the table's
+	if table.BillingModeSummary == nil || table.BillingModeSummary.BillingMode != types.BillingModePayPerRequest {
+		if table.ProvisionedThroughput != nil {
+			create.ProvisionedThroughput = &types.ProvisionedThroughput{
+				ReadCapacityUnits:  table.ProvisionedThroughput.ReadCapacityUnits,
+				WriteCapacityUnits: table.ProvisionedThroughput.WriteCapacityUnits,
+			}
+		}
+	}
+
+	return &dynamodb.UpdateTableInput{
+		TableName: aws.String(tableName),
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String(index.Keys.HashKey), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String(index.Keys.RangeKey), AttributeType: index.RangeKeyType},
+		},
+		GlobalSecondaryIndexUpdates: []types.GlobalSecondaryIndexUpdate{{Create: create}},
+	}
+}
+
+func waitForIndex(ctx context.Context, client *dynamodb.Client, tableName, indexName string) error {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		table, err := describeTable(ctx, client, tableName)
+		if err != nil {
+			return err
+		}
+		for _, index := range table.GlobalSecondaryIndexes {
+			if aws.ToString(index.IndexName) == indexName && index.IndexStatus == types.IndexStatusActive {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for index %q: %w", indexName, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// BackfillIndexAttributes writes the index partition attribute on items
+// created before the indexes existed, or written under a different shard
+// count, so they become visible to queries
+func (r *DynamoDBRepository) BackfillIndexAttributes(ctx context.Context, logger *slog.Logger) (int, error) {
+	paginator := dynamodb.NewScanPaginator(r.client, productScan(&dynamodb.ScanInput{
+		TableName:                aws.String(r.tableName),
+		ProjectionExpression:     aws.String("id, #gsi_pk, #tenant_id"),
+		ExpressionAttributeNames: map[string]string{"#gsi_pk": indexPartitionAttribute, "#tenant_id": tenantAttribute},
+	}))
+
+	updated := 0
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return updated, fmt.Errorf("failed to scan for backfill: %w", err)
+		}
+		for _, item := range page.Items {
+			id, ok := item["id"].(*types.AttributeValueMemberS)
+			if !ok {
+				continue
+			}
+			partition := r.indexPartition(itemTenant(item), id.Value)
+			if current, ok := item[indexPartitionAttribute].(*types.AttributeValueMemberS); ok && current.Value == partition {
+				continue
+			}
+
+			_, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+				TableName:                 aws.String(r.tableName),
+				Key:                       productKey(id.Value),
+				UpdateExpression:          aws.String("SET #gsi_pk = :gsi_pk"),
+				ConditionExpression:       aws.String("attribute_exists(#pk)"),
+				ExpressionAttributeNames:  map[string]string{"#gsi_pk": indexPartitionAttribute, "#pk": partitionKeyAttribute},
+				ExpressionAttributeValues: map[string]types.AttributeValue{":gsi_pk": &types.AttributeValueMemberS{Value: partition}},
+			})
+			var conditionErr *types.ConditionalCheckFailedException
+			if err != nil && !errors.As(err, &conditionErr) {
+				return updated, fmt.Errorf("failed to backfill item: %w", err)
+			}
+			updated++
+		}
+	}
+	logger.InfoContext(ctx, "backfilled index attributes", "table", r.tableName, "items", updated, "shards", r.shards)
+	return updated, nil
+}
+
+func describeTable(ctx context.Context, client *dynamodb.Client, tableName string) (*types.TableDescription, error) {
+	output, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(tableName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe table %q: %w", tableName, err)
+	}
+	return output.Table, nil
+}
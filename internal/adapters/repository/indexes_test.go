@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+func TestIndexPartition(t *testing.T) {
+	unsharded := NewDynamoDBRepository(nil, "products")
+	assert.Equal(t, "PRODUCT", unsharded.indexPartition("", "abc"))
+	assert.Equal(t, []string{"PRODUCT"}, unsharded.indexPartitions(""))
+	assert.Equal(t, "acme#PRODUCT", unsharded.indexPartition("acme", "abc"))
+
+	sharded := NewDynamoDBRepository(nil, "products", WithIndexShards(4))
+	assert.Equal(t, []string{"PRODUCT#0", "PRODUCT#1", "PRODUCT#2", "PRODUCT#3"}, sharded.indexPartitions(""))
+	assert.Equal(t, []string{"acme#PRODUCT#0", "acme#PRODUCT#1", "acme#PRODUCT#2", "acme#PRODUCT#3"}, sharded.indexPartitions("acme"))
+
+	seen := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		id := fmt.Sprintf("product-%d", i)
+		partition := sharded.indexPartition("", id)
+		assert.Equal(t, partition, sharded.indexPartition("", id), "partition must be stable per id")
+		assert.Contains(t, sharded.indexPartitions(""), partition)
+		assert.Contains(t, sharded.indexPartitions("acme"), sharded.indexPartition("acme", id))
+		seen[partition]++
+	}
+	assert.Len(t, seen, 4, "every shard should receive writes")
+}
+
+// A GSI only holds items that carry both of its key attributes with the
+// declared type, so every product has to be written with them
+func TestProductIndexes_KeysWritten(t *testing.T) {
+	now := time.Now().UTC()
+	repo := NewDynamoDBRepository(nil, "products")
+	item, err := repo.toItem(domain.Product{ID: "p1", Name: "Laptop", Price: domain.Money{Amount: 99900, Currency: "USD"}, CreatedAt: now, UpdatedAt: now})
+	require.NoError(t, err)
+
+	for _, index := range productIndexes {
+		assert.IsType(t, &types.AttributeValueMemberS{}, item[index.Keys.HashKey], index.Name)
+		switch index.RangeKeyType {
+		case types.ScalarAttributeTypeN:
+			assert.IsType(t, &types.AttributeValueMemberN{}, item[index.Keys.RangeKey], index.Name)
+		default:
+			assert.IsType(t, &types.AttributeValueMemberS{}, item[index.Keys.RangeKey], index.Name)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+//go:build integration
+
+// The integration suite runs the repository contract of repotest against a
+// real DynamoDB engine, either DynamoDB Local or LocalStack:
+//
+//	docker compose --profile test up -d dynamodb-local
+//	DYNAMODB_ENDPOINT=http://localhost:8000 go test -tags integration ./internal/adapters/repository/
+//
+// Every test creates its own tables and drops them when it finishes.
+package repository_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/repository"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports/repotest"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/migrations"
+)
+
+// localClient connects to the engine at DYNAMODB_ENDPOINT, skipping the
+// test when none is configured
+func localClient(t *testing.T) *dynamodb.Client {
+	endpoint := os.Getenv("DYNAMODB_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("DYNAMODB_ENDPOINT is not set")
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(context.Background(),
+		awsconfig.WithRegion("us-east-1"),
+		awsconfig.WithCredentialsProvider(credentials.NewStaticCredentialsProvider("local", "local", "")),
+	)
+	require.NoError(t, err)
+	return dynamodb.NewFromConfig(awsCfg, func(o *dynamodb.Options) {
+		o.BaseEndpoint = aws.String(endpoint)
+	})
+}
+
+// newLocalRepository migrates a fresh products table and a unique keys
+// table, and returns a repository over them
+func newLocalRepository(t *testing.T, opts ...repository.Option) *repository.DynamoDBRepository {
+	client := localClient(t)
+	ctx := context.Background()
+	suffix := uuid.NewString()[:8]
+	table := "products_it_" + suffix
+	uniqueTable := "unique_keys_it_" + suffix
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	_, err := migrations.EnsureTable(ctx, client, table, repository.ExpectedSchema(), logger)
+	require.NoError(t, err)
+	_, err = client.CreateTable(ctx, &dynamodb.CreateTableInput{
+		TableName:            aws.String(uniqueTable),
+		AttributeDefinitions: []types.AttributeDefinition{{AttributeName: aws.String("id"), AttributeType: types.ScalarAttributeTypeS}},
+		KeySchema:            []types.KeySchemaElement{{AttributeName: aws.String("id"), KeyType: types.KeyTypeHash}},
+		BillingMode:          types.BillingModePayPerRequest,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		for _, name := range []string{table, uniqueTable} {
+			_, _ = client.DeleteTable(context.Background(), &dynamodb.DeleteTableInput{TableName: aws.String(name)})
+		}
+	})
+
+	return repository.NewDynamoDBRepository(client, table, append([]repository.Option{repository.WithUniqueKeys(uniqueTable)}, opts...)...)
+}
+
+func TestIntegration_Contract(t *testing.T) {
+	repotest.TestProductRepository(t, func(t *testing.T) ports.ProductRepository {
+		return newLocalRepository(t)
+	})
+}
+
+func TestIntegration_ContractSharded(t *testing.T) {
+	repotest.TestProductRepository(t, func(t *testing.T) ports.ProductRepository {
+		return newLocalRepository(t, repository.WithIndexShards(3), repository.WithParallelScan(2, 0))
+	})
+}
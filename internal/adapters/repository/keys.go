@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// The products table is a single table holding every catalog entity under
+// a generic pk/sk key, told apart by the entity_type attribute:
+//
+//	entity    pk                 sk
+//	product   PRODUCT#<id>       PRODUCT
+//	review    PRODUCT#<product>  REVIEW#<id>
+//	variant   PRODUCT#<product>  VARIANT#<id>
+//	category  CATEGORY           CATEGORY#<id>
+//
+// A product's reviews, and its variants once there are any, share its
+// partition, so one query reads the product with them, and all categories
+// share one partition, so listing them is a query rather than a scan.
+const (
+	partitionKeyAttribute = "pk"
+	sortKeyAttribute      = "sk"
+	entityTypeAttribute   = "entity_type"
+
+	entityProduct  = "PRODUCT"
+	entityReview   = "REVIEW"
+	entityVariant  = "VARIANT"
+	entityCategory = "CATEGORY"
+)
+
+// entityKey joins an entity type prefix and an ID into a key value
+func entityKey(entity, id string) string {
+	return entity + "#" + id
+}
+
+func productKey(id string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		partitionKeyAttribute: &types.AttributeValueMemberS{Value: entityKey(entityProduct, id)},
+		sortKeyAttribute:      &types.AttributeValueMemberS{Value: entityProduct},
+	}
+}
+
+func reviewKey(productID, id string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		partitionKeyAttribute: &types.AttributeValueMemberS{Value: entityKey(entityProduct, productID)},
+		sortKeyAttribute:      &types.AttributeValueMemberS{Value: entityKey(entityReview, id)},
+	}
+}
+
+func categoryKey(id string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		partitionKeyAttribute: &types.AttributeValueMemberS{Value: entityCategory},
+		sortKeyAttribute:      &types.AttributeValueMemberS{Value: entityKey(entityCategory, id)},
+	}
+}
+
+// withKey adds key and the entity type to a marshalled item
+func withKey(item, key map[string]types.AttributeValue, entity string) map[string]types.AttributeValue {
+	for name, value := range key {
+		item[name] = value
+	}
+	item[entityTypeAttribute] = &types.AttributeValueMemberS{Value: entity}
+	return item
+}
+
+// productIDFromKey returns the ID of the product a key belongs to, and
+// whether the key is a product item's own rather than another entity's
+func productIDFromKey(pk, sk string) (string, bool) {
+	id, ok := strings.CutPrefix(pk, entityProduct+"#")
+	return id, ok && sk == entityProduct
+}
+
+// productScan narrows a scan of the table to product items, adding the
+// entity type to its filter
+func productScan(input *dynamodb.ScanInput) *dynamodb.ScanInput {
+	if input.ExpressionAttributeNames == nil {
+		input.ExpressionAttributeNames = map[string]string{}
+	}
+	if input.ExpressionAttributeValues == nil {
+		input.ExpressionAttributeValues = map[string]types.AttributeValue{}
+	}
+	input.ExpressionAttributeNames["#entity_type"] = entityTypeAttribute
+	input.ExpressionAttributeValues[":product_entity"] = &types.AttributeValueMemberS{Value: entityProduct}
+	condition := "#entity_type = :product_entity"
+	if filter := aws.ToString(input.FilterExpression); filter != "" {
+		condition += " AND (" + filter + ")"
+	}
+	input.FilterExpression = aws.String(condition)
+	return input
+}
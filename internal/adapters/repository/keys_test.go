@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+func TestToItem_SingleTableKey(t *testing.T) {
+	repo := NewDynamoDBRepository(nil, "products")
+	item, err := repo.toItem(domain.Product{ID: "p1", Name: "Laptop", Price: domain.Money{Amount: 99900, Currency: "USD"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, &types.AttributeValueMemberS{Value: "PRODUCT#p1"}, item["pk"])
+	assert.Equal(t, &types.AttributeValueMemberS{Value: "PRODUCT"}, item["sk"])
+	assert.Equal(t, &types.AttributeValueMemberS{Value: "PRODUCT"}, item["entity_type"])
+	assert.Equal(t, &types.AttributeValueMemberS{Value: "p1"}, item["id"])
+
+	id, ok := productIDFromKey("PRODUCT#p1", "PRODUCT")
+	assert.True(t, ok)
+	assert.Equal(t, "p1", id)
+	_, ok = productIDFromKey("PRODUCT#p1", "REVIEW#r1")
+	assert.False(t, ok, "a review in the product's partition is not the product")
+	_, ok = productIDFromKey("CATEGORY", "CATEGORY#c1")
+	assert.False(t, ok)
+}
+
+// Conditions, projections, filters and index keys name product attributes
+// in snake case; the item has to store them under those names
+func TestToItem_AttributeNames(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	product := domain.Product{
+		ID:          "p1",
+		Name:        "Laptop",
+		Description: "Thin and light",
+		Price:       domain.Money{Amount: 99900, Currency: "USD"},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	repo := NewDynamoDBRepository(nil, "products")
+	item, err := repo.toItem(product)
+	require.NoError(t, err)
+
+	for _, name := range []string{"id", "name", "description", "created_at", "updated_at", "price", "currency"} {
+		assert.Contains(t, item, name)
+	}
+	for legacy := range legacyProductAttributes {
+		assert.NotContains(t, item, legacy)
+	}
+
+	decoded, err := decodeProduct(item)
+	require.NoError(t, err)
+	assert.Equal(t, product, decoded)
+}
+
+func TestProductScan(t *testing.T) {
+	input := productScan(&dynamodb.ScanInput{TableName: aws.String("products")})
+	assert.Equal(t, "#entity_type = :product_entity", aws.ToString(input.FilterExpression))
+
+	input = productScan(&dynamodb.ScanInput{
+		TableName:                 aws.String("products"),
+		FilterExpression:          aws.String("#a = :a OR #b = :b"),
+		ExpressionAttributeNames:  map[string]string{"#a": "a", "#b": "b"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{":a": &types.AttributeValueMemberS{Value: "1"}, ":b": &types.AttributeValueMemberS{Value: "2"}},
+	})
+	assert.Equal(t, "#entity_type = :product_entity AND (#a = :a OR #b = :b)", aws.ToString(input.FilterExpression))
+	assert.Equal(t, "entity_type", input.ExpressionAttributeNames["#entity_type"])
+	assert.Len(t, input.ExpressionAttributeValues, 3)
+}
+
+func TestReviewRepository_ListQueriesProductPartition(t *testing.T) {
+	var operations, bodies []string
+	client := dynamodb.New(dynamodb.Options{
+		Region:      "us-east-1",
+		Credentials: aws.AnonymousCredentials{},
+		HTTPClient:  recordingTransport{stubTransport{status: http.StatusOK, body: `{"Items":[]}`}, &operations, &bodies, &sync.Mutex{}},
+	})
+	repo := NewDynamoDBReviewRepository(client, "products", "", nil)
+
+	_, err := repo.List(context.Background(), "p1", "", 10)
+	require.NoError(t, err)
+	_, err = repo.List(context.Background(), "p1", "r9", 10)
+	require.NoError(t, err)
+
+	require.Len(t, bodies, 2)
+	assert.Contains(t, bodies[0], `"KeyConditionExpression":"#pk = :pk AND begins_with(#sk, :from)"`)
+	assert.Contains(t, bodies[0], `":from":{"S":"REVIEW#"}`)
+	assert.Contains(t, bodies[0], `":pk":{"S":"PRODUCT#p1"}`)
+	assert.Contains(t, bodies[1], `"KeyConditionExpression":"#pk = :pk AND #sk < :from"`)
+	assert.Contains(t, bodies[1], `":from":{"S":"REVIEW#r9"}`)
+	assert.Contains(t, bodies[1], `"FilterExpression":"#entity_type = :review"`)
+}
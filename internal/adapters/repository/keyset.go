@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// afterSortKey reports whether product comes strictly after the keyset
+// position of filters in the listing order. Every product does when no
+// position was given.
+func afterSortKey(product domain.Product, filters ports.ProductFilters) bool {
+	if filters.After == nil {
+		return true
+	}
+	c := filters.After.Compare(product, filters.SortBy)
+	if filters.SortOrder == "desc" {
+		return c < 0
+	}
+	return c > 0
+}
+
+// sortKeyValue is the attribute value a position's value is stored as
+func sortKeyValue(sortBy, value string) types.AttributeValue {
+	if sortBy == priceAttribute {
+		return &types.AttributeValueMemberN{Value: value}
+	}
+	return &types.AttributeValueMemberS{Value: value}
+}
+
+// keysetKeyCondition narrows a query on the index sorted by the keyset
+// field to the items from the position's value on. Items tied with the
+// position on that value are read too; afterSortKey drops the ones at or
+// before it by ID.
+func keysetKeyCondition(filters ports.ProductFilters, names map[string]string, values map[string]types.AttributeValue) string {
+	names["#after_sort"] = filters.SortBy
+	values[":after_value"] = sortKeyValue(filters.SortBy, filters.After.Value)
+	if filters.SortOrder == "desc" {
+		return "#after_sort <= :after_value"
+	}
+	return "#after_sort >= :after_value"
+}
+
+// keysetBeforeKeyCondition narrows a query on the index sorted by the
+// keyset field to the items before the position's value, which a keyset
+// page counts without reading
+func keysetBeforeKeyCondition(filters ports.ProductFilters, names map[string]string, values map[string]types.AttributeValue) string {
+	names["#after_sort"] = filters.SortBy
+	values[":after_value"] = sortKeyValue(filters.SortBy, filters.After.Value)
+	if filters.SortOrder == "desc" {
+		return "#after_sort > :after_value"
+	}
+	return "#after_sort < :after_value"
+}
+
+// tiedAtBoundary reports whether the last entry read shares its sort value
+// with the entry at position wanted. A keyset page cut between them could
+// skip ties with a lower ID on the next page, so the whole tie group has to
+// be read before sorting by ID.
+func tiedAtBoundary(entries []indexEntry, wanted int, rangeKey string) bool {
+	if wanted <= 0 || len(entries) < wanted {
+		return false
+	}
+	return sameScalar(entries[wanted-1].key[rangeKey], entries[len(entries)-1].key[rangeKey])
+}
+
+func sameScalar(a, b types.AttributeValue) bool {
+	switch a := a.(type) {
+	case *types.AttributeValueMemberS:
+		b, ok := b.(*types.AttributeValueMemberS)
+		return ok && a.Value == b.Value
+	case *types.AttributeValueMemberN:
+		b, ok := b.(*types.AttributeValueMemberN)
+		return ok && a.Value == b.Value
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+func TestListWithFilters_Keyset(t *testing.T) {
+	// The index returns the tie on price 10 out of ID order
+	const page = `{"Count":4,"Items":[
+		{"id":{"S":"c"},"price":{"N":"10"}},
+		{"id":{"S":"a"},"price":{"N":"10"}},
+		{"id":{"S":"b"},"price":{"N":"10"}},
+		{"id":{"S":"d"},"price":{"N":"12"}}]}`
+
+	repo, operations, bodies := recordingRepository(http.StatusOK, page)
+	result, err := repo.ListWithFilters(context.Background(), ports.ProductFilters{
+		SortBy:    "price",
+		SortOrder: "asc",
+		Limit:     2,
+		After:     &ports.SortKey{Value: "10", ID: "a"},
+	})
+	require.NoError(t, err)
+
+	// The products before the position are counted, not read
+	assert.Equal(t, []string{"Query", "Query"}, *operations)
+	assert.Contains(t, (*bodies)[0], `"KeyConditionExpression":"#pk = :pk AND #after_sort >= :after_value"`)
+	assert.Contains(t, (*bodies)[0], `":after_value":{"N":"10"}`)
+	assert.Contains(t, (*bodies)[1], `"KeyConditionExpression":"#pk = :pk AND #after_sort < :after_value"`)
+	assert.Contains(t, (*bodies)[1], `"Select":"COUNT"`)
+
+	ids := make([]string, len(result.Products))
+	for i, product := range result.Products {
+		ids[i] = product.ID
+	}
+	assert.Equal(t, []string{"b", "c"}, ids)
+	// The four items read plus the four the stub counts before the position
+	assert.Equal(t, 8, result.TotalItems)
+}
+
+func TestScanFiltered_Keyset(t *testing.T) {
+	const page = `{"Count":3,"Items":[
+		{"id":{"S":"a"},"name":{"S":"Lamp"}},
+		{"id":{"S":"b"},"name":{"S":"Lamp"}},
+		{"id":{"S":"c"},"name":{"S":"Desk"}}]}`
+
+	repo, operations, bodies := recordingRepository(http.StatusOK, page)
+	result, err := repo.ListWithFilters(context.Background(), ports.ProductFilters{
+		SortBy:    "name",
+		SortOrder: "desc",
+		Limit:     10,
+		After:     &ports.SortKey{Value: "Lamp", ID: "b"},
+	})
+	require.NoError(t, err)
+
+	// One scan both fills the page and counts the total
+	assert.Equal(t, []string{"Scan"}, *operations)
+	assert.NotContains(t, (*bodies)[0], "after_value")
+	require.Len(t, result.Products, 2)
+	assert.Equal(t, "a", result.Products[0].ID)
+	assert.Equal(t, "c", result.Products[1].ID)
+	assert.Equal(t, 3, result.TotalItems)
+}
+
+func TestAfterSortKey(t *testing.T) {
+	after := &ports.SortKey{Value: "Lamp", ID: "b"}
+	asc := ports.ProductFilters{SortBy: "name", SortOrder: "asc", After: after}
+	desc := ports.ProductFilters{SortBy: "name", SortOrder: "desc", After: after}
+
+	assert.True(t, afterSortKey(domain.Product{ID: "c", Name: "Lamp"}, asc))
+	assert.False(t, afterSortKey(domain.Product{ID: "b", Name: "Lamp"}, asc))
+	assert.False(t, afterSortKey(domain.Product{ID: "z", Name: "Desk"}, asc))
+	assert.True(t, afterSortKey(domain.Product{ID: "a", Name: "Lamp"}, desc))
+	assert.True(t, afterSortKey(domain.Product{ID: "z", Name: "Desk"}, desc))
+	assert.True(t, afterSortKey(domain.Product{}, ports.ProductFilters{}))
+}
+
+func TestTiedAtBoundary(t *testing.T) {
+	entry := func(price string) indexEntry {
+		return indexEntry{key: map[string]types.AttributeValue{"price": &types.AttributeValueMemberN{Value: price}}}
+	}
+	entries := []indexEntry{entry("10"), entry("12"), entry("12")}
+
+	assert.True(t, tiedAtBoundary(entries, 2, "price"))
+	assert.False(t, tiedAtBoundary(entries, 1, "price"))
+	assert.False(t, tiedAtBoundary(entries, 4, "price"))
+}
@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Kinds of the tables products, reviews and categories had before they
+// shared the products table. Products and categories were keyed by id,
+// reviews by product_id and id.
+const (
+	LegacyProducts   = "products"
+	LegacyReviews    = "reviews"
+	LegacyCategories = "categories"
+)
+
+// CopyLegacyTable copies every item of source, a table of the given legacy
+// kind, into the products table with the keys and entity type of the
+// single-table layout. Items are put whole, so copying again overwrites
+// the earlier copies with the source's current items.
+func (r *DynamoDBRepository) CopyLegacyTable(ctx context.Context, source, kind string, logger *slog.Logger) (int, error) {
+	var convert func(item map[string]types.AttributeValue, id string) (map[string]types.AttributeValue, error)
+	switch kind {
+	case LegacyProducts:
+		convert = func(item map[string]types.AttributeValue, id string) (map[string]types.AttributeValue, error) {
+			item = currentProductAttributes(item)
+			item[indexPartitionAttribute] = &types.AttributeValueMemberS{Value: r.indexPartition(itemTenant(item), id)}
+			return withKey(item, productKey(id), entityProduct), nil
+		}
+	case LegacyReviews:
+		convert = func(item map[string]types.AttributeValue, id string) (map[string]types.AttributeValue, error) {
+			productID, ok := item["product_id"].(*types.AttributeValueMemberS)
+			if !ok {
+				return nil, fmt.Errorf("review %s has no product_id", id)
+			}
+			return withKey(item, reviewKey(productID.Value, id), entityReview), nil
+		}
+	case LegacyCategories:
+		convert = func(item map[string]types.AttributeValue, id string) (map[string]types.AttributeValue, error) {
+			return withKey(item, categoryKey(id), entityCategory), nil
+		}
+	default:
+		return 0, fmt.Errorf("unknown legacy table kind %q", kind)
+	}
+
+	paginator := dynamodb.NewScanPaginator(r.client, &dynamodb.ScanInput{TableName: aws.String(source)})
+	copied := 0
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return copied, fmt.Errorf("failed to scan %s: %w", source, err)
+		}
+
+		requests := make([]batchRequest, 0, len(page.Items))
+		for _, item := range page.Items {
+			id, ok := item["id"].(*types.AttributeValueMemberS)
+			if !ok {
+				// Products marshal their ID under the field name
+				if id, ok = item["ID"].(*types.AttributeValueMemberS); !ok {
+					continue
+				}
+			}
+			converted, err := convert(item, id.Value)
+			if err != nil {
+				return copied, err
+			}
+			requests = append(requests, batchRequest{table: r.tableName, item: converted})
+		}
+		for start := 0; start < len(requests); start += batchWriteSize {
+			end := min(start+batchWriteSize, len(requests))
+			if _, err := r.batchWrite(ctx, requests[start:end]); err != nil {
+				return copied, err
+			}
+			copied += end - start
+		}
+	}
+	logger.InfoContext(ctx, "copied legacy table", "source", source, "kind", kind, "table", r.tableName, "items", copied)
+	return copied, nil
+}
+
+// legacyProductAttributes maps the names products stored some fields under,
+// before those fields were tagged, to the names expressions and indexes use
+var legacyProductAttributes = map[string]string{
+	"ID":          "id",
+	"Name":        "name",
+	"Description": "description",
+	"CreatedAt":   "created_at",
+	"UpdatedAt":   "updated_at",
+}
+
+// currentProductAttributes returns item with its legacy attribute names
+// replaced by the current ones. Items without any are returned as they are;
+// others are copied, leaving the caller's map untouched.
+func currentProductAttributes(item map[string]types.AttributeValue) map[string]types.AttributeValue {
+	renamed := item
+	copied := false
+	for legacy, current := range legacyProductAttributes {
+		value, ok := item[legacy]
+		if !ok {
+			continue
+		}
+		if !copied {
+			renamed, copied = maps.Clone(item), true
+		}
+		// A value written under the current name is the newer one
+		if _, ok := renamed[current]; !ok {
+			renamed[current] = value
+		}
+		delete(renamed, legacy)
+	}
+	return renamed
+}
+
+// RenameLegacyAttributes moves the fields of products written before they
+// were tagged to the attribute names the conditions, projections and
+// indexes use. A field written under its current name since is kept.
+func (r *DynamoDBRepository) RenameLegacyAttributes(ctx context.Context, logger *slog.Logger) (int, error) {
+	legacyNames := slices.Sorted(maps.Keys(legacyProductAttributes))
+	names := map[string]string{}
+	exists := make([]string, 0, len(legacyNames))
+	for _, legacy := range legacyNames {
+		names["#"+legacy] = legacy
+		exists = append(exists, "attribute_exists(#"+legacy+")")
+	}
+	paginator := dynamodb.NewScanPaginator(r.client, productScan(&dynamodb.ScanInput{
+		TableName:                aws.String(r.tableName),
+		FilterExpression:         aws.String(strings.Join(exists, " OR ")),
+		ExpressionAttributeNames: names,
+	}))
+
+	renamed := 0
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return renamed, fmt.Errorf("failed to scan for legacy attributes: %w", err)
+		}
+		for _, item := range page.Items {
+			update, ok := legacyAttributesUpdate(r.tableName, item, legacyNames)
+			if !ok {
+				continue
+			}
+			_, err := r.client.UpdateItem(ctx, update)
+			var conditionErr *types.ConditionalCheckFailedException
+			if err != nil && !errors.As(err, &conditionErr) {
+				return renamed, fmt.Errorf("failed to rename legacy attributes: %w", err)
+			}
+			renamed++
+		}
+	}
+	logger.InfoContext(ctx, "renamed legacy product attributes", "table", r.tableName, "items", renamed)
+	return renamed, nil
+}
+
+// legacyAttributesUpdate builds the update that moves item's legacy
+// attributes to their current names, unless item has none
+func legacyAttributesUpdate(tableName string, item map[string]types.AttributeValue, legacyNames []string) (*dynamodb.UpdateItemInput, bool) {
+	names := map[string]string{"#pk": partitionKeyAttribute}
+	values := map[string]types.AttributeValue{}
+	var set, remove []string
+	for _, legacy := range legacyNames {
+		value, ok := item[legacy]
+		if !ok {
+			continue
+		}
+		current := legacyProductAttributes[legacy]
+		names["#"+legacy] = legacy
+		names["#"+current] = current
+		values[":"+current] = value
+		set = append(set, fmt.Sprintf("#%s = if_not_exists(#%s, :%s)", current, current, current))
+		remove = append(remove, "#"+legacy)
+	}
+	if len(set) == 0 {
+		return nil, false
+	}
+	return &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]types.AttributeValue{
+			partitionKeyAttribute: item[partitionKeyAttribute],
+			sortKeyAttribute:      item[sortKeyAttribute],
+		},
+		UpdateExpression:          aws.String("SET " + strings.Join(set, ", ") + " REMOVE " + strings.Join(remove, ", ")),
+		ConditionExpression:       aws.String("attribute_exists(#pk)"),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	}, true
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeProduct_LegacyAttributes(t *testing.T) {
+	item := map[string]types.AttributeValue{
+		"ID":        &types.AttributeValueMemberS{Value: "p1"},
+		"Name":      &types.AttributeValueMemberS{Value: "Laptop"},
+		"CreatedAt": &types.AttributeValueMemberS{Value: "2024-05-01T12:00:00Z"},
+		"price":     &types.AttributeValueMemberN{Value: "999"},
+	}
+	product, err := decodeProduct(item)
+	require.NoError(t, err)
+	assert.Equal(t, "p1", product.ID)
+	assert.Equal(t, "Laptop", product.Name)
+	assert.Equal(t, 2024, product.CreatedAt.Year())
+	assert.Contains(t, item, "ID", "the caller's item is left as it was")
+}
+
+func TestLegacyAttributesUpdate(t *testing.T) {
+	legacyNames := slices.Sorted(maps.Keys(legacyProductAttributes))
+	item := map[string]types.AttributeValue{
+		"pk":   &types.AttributeValueMemberS{Value: "PRODUCT#p1"},
+		"sk":   &types.AttributeValueMemberS{Value: "PRODUCT"},
+		"ID":   &types.AttributeValueMemberS{Value: "p1"},
+		"Name": &types.AttributeValueMemberS{Value: "Laptop"},
+	}
+	update, ok := legacyAttributesUpdate("products", item, legacyNames)
+	require.True(t, ok)
+	assert.Equal(t, productKey("p1"), update.Key)
+	assert.Equal(t, "SET #id = if_not_exists(#id, :id), #name = if_not_exists(#name, :name) REMOVE #ID, #Name", aws.ToString(update.UpdateExpression))
+	assert.Equal(t, "attribute_exists(#pk)", aws.ToString(update.ConditionExpression))
+	assert.Equal(t, item["Name"], update.ExpressionAttributeValues[":name"])
+
+	_, ok = legacyAttributesUpdate("products", map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "p2"}}, legacyNames)
+	assert.False(t, ok, "items already renamed need no update")
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// DynamoDBLock implements leases as items keyed by name in a locks table,
+// holding the owner and an expires_at that DynamoDB TTL eventually cleans up
+type DynamoDBLock struct {
+	client    *dynamodb.Client
+	tableName string
+	owner     string
+	now       func() time.Time
+}
+
+// NewDynamoDBLock creates a lock client; owner must be unique per instance
+func NewDynamoDBLock(client *dynamodb.Client, tableName, owner string) *DynamoDBLock {
+	return &DynamoDBLock{
+		client:    client,
+		tableName: tableName,
+		owner:     owner,
+		now:       time.Now,
+	}
+}
+
+// Acquire takes the lease when it is free, expired or already ours
+func (l *DynamoDBLock) Acquire(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	now := l.now()
+	_, err := l.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(l.tableName),
+		Item: map[string]types.AttributeValue{
+			"name":       &types.AttributeValueMemberS{Value: name},
+			"owner":      &types.AttributeValueMemberS{Value: l.owner},
+			"expires_at": &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Add(ttl).Unix(), 10)},
+		},
+		ConditionExpression: aws.String("attribute_not_exists(#name) OR #expires_at <= :now OR #owner = :owner"),
+		ExpressionAttributeNames: map[string]string{
+			"#name":       "name",
+			"#owner":      "owner",
+			"#expires_at": "expires_at",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":now":   &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Unix(), 10)},
+			":owner": &types.AttributeValueMemberS{Value: l.owner},
+		},
+	})
+	if err != nil {
+		var conditionFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionFailed) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to acquire lock %q: %w", name, err)
+	}
+	return true, nil
+}
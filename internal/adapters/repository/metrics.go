@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// CallObserver receives DynamoDB call measurements
+type CallObserver interface {
+	ObserveDynamoDBCall(operation string, duration time.Duration)
+	ObserveDynamoDBThrottle(operation string)
+}
+
+// MetricsAPIOption instruments a DynamoDB client, e.g. through
+// dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) { o.APIOptions =
+// append(o.APIOptions, MetricsAPIOption(observer)) })
+func MetricsAPIOption(observer CallObserver) func(*middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		// The whole call, retries and client-side throttling included. Added
+		// last so the operation name is already set.
+		err := stack.Initialize.Add(middleware.InitializeMiddlewareFunc("CallMetrics",
+			func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+				start := time.Now()
+				out, metadata, err := next.HandleInitialize(ctx, in)
+				observer.ObserveDynamoDBCall(awsmiddleware.GetOperationName(ctx), time.Since(start))
+				return out, metadata, err
+			}), middleware.After)
+		if err != nil {
+			return err
+		}
+
+		// Each attempt, so throttles absorbed by retries are still counted
+		return stack.Finalize.Insert(middleware.FinalizeMiddlewareFunc("ThrottleMetrics",
+			func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+				out, metadata, err := next.HandleFinalize(ctx, in)
+				if isThrottle(err) {
+					observer.ObserveDynamoDBThrottle(awsmiddleware.GetOperationName(ctx))
+				}
+				return out, metadata, err
+			}), "Retry", middleware.After)
+	}
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingObserver struct {
+	mu        sync.Mutex
+	calls     []string
+	throttles []string
+}
+
+func (o *recordingObserver) ObserveDynamoDBCall(operation string, duration time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.calls = append(o.calls, operation)
+}
+
+func (o *recordingObserver) ObserveDynamoDBThrottle(operation string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.throttles = append(o.throttles, operation)
+}
+
+type throttlingTransport struct{}
+
+func (throttlingTransport) Do(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Header:     http.Header{"Content-Type": []string{"application/x-amz-json-1.0"}},
+		Body:       io.NopCloser(strings.NewReader(`{"__type":"com.amazonaws.dynamodb.v20120810#ProvisionedThroughputExceededException","message":"slow down"}`)),
+		Request:    req,
+	}, nil
+}
+
+type noBackoff struct{}
+
+func (noBackoff) BackoffDelay(attempt int, err error) (time.Duration, error) {
+	return 0, nil
+}
+
+func TestMetricsAPIOption(t *testing.T) {
+	observer := &recordingObserver{}
+	client := dynamodb.New(dynamodb.Options{
+		Region:      "us-east-1",
+		Credentials: aws.AnonymousCredentials{},
+		HTTPClient:  throttlingTransport{},
+		Retryer:     retry.AddWithMaxAttempts(retry.NewStandard(func(o *retry.StandardOptions) { o.Backoff = noBackoff{} }), 2),
+		APIOptions:  []func(*middleware.Stack) error{MetricsAPIOption(observer)},
+	})
+
+	_, err := client.GetItem(context.Background(), &dynamodb.GetItemInput{
+		TableName: aws.String("products"),
+		Key:       map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "1"}},
+	})
+	require.True(t, isThrottle(err), "unexpected error: %v", err)
+
+	assert.Equal(t, []string{"GetItem"}, observer.calls)
+	assert.Equal(t, []string{"GetItem", "GetItem"}, observer.throttles)
+}
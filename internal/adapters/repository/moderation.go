@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// ListPendingReview scans for the tenant's unexpired products flagged by
+// content moderation
+func (r *DynamoDBRepository) ListPendingReview(ctx context.Context) ([]domain.Product, error) {
+	names := map[string]string{
+		"#moderation_status": "moderation_status",
+	}
+	values := map[string]types.AttributeValue{
+		":pending": &types.AttributeValueMemberS{Value: domain.ModerationPendingReview},
+	}
+	filter := "#moderation_status = :pending AND " + tenantCondition(ports.TenantID(ctx), names, values) +
+		" AND " + notExpiredCondition(time.Now().UTC(), names, values)
+	paginator := dynamodb.NewScanPaginator(r.client, productScan(&dynamodb.ScanInput{
+		TableName:                 aws.String(r.tableName),
+		FilterExpression:          aws.String(filter),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	}))
+
+	var products []domain.Product
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan review queue: %w", err)
+		}
+
+		batch, err := decodeProducts(page.Items)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal review queue: %w", err)
+		}
+		products = append(products, batch...)
+	}
+	return products, nil
+}
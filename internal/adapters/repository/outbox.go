@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+const (
+	// outboxPendingIndex is a sparse GSI over the events not yet sent; the
+	// pending attribute is removed once the relay has published an event
+	outboxPendingIndex = "pending-index"
+	outboxPending      = "1"
+	// outboxTimeLayout is fixed width so occurred_at sorts as a string
+	outboxTimeLayout = "2006-01-02T15:04:05.000000000Z"
+	// outboxRetention is how long sent events are kept before TTL removes them
+	outboxRetention = 7 * 24 * time.Hour
+)
+
+// outboxItem is an event waiting in, or already relayed from, the outbox
+type outboxItem struct {
+	ID         string `dynamodbav:"id"`
+	Type       string `dynamodbav:"type"`
+	ProductID  string `dynamodbav:"product_id"`
+	Payload    string `dynamodbav:"payload"`
+	Pending    string `dynamodbav:"pending,omitempty"`
+	OccurredAt string `dynamodbav:"occurred_at"`
+}
+
+func newOutboxItem(event domain.ProductEvent) (map[string]types.AttributeValue, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s event: %w", event.Type, err)
+	}
+	return attributevalue.MarshalMap(outboxItem{
+		ID:         event.ID,
+		Type:       event.Type,
+		ProductID:  event.ProductID,
+		Payload:    string(payload),
+		Pending:    outboxPending,
+		OccurredAt: event.OccurredAt.UTC().Format(outboxTimeLayout),
+	})
+}
+
+// WithOutbox stores the events attached with ports.WithOutboxEvent in
+// tableName, in the same transaction as the product write
+func WithOutbox(tableName string) Option {
+	return func(r *DynamoDBRepository) {
+		r.outboxTable = tableName
+	}
+}
+
+// write runs a single product write, wrapped in a transaction with the
+// SKU and barcode reservations in unique and the outbox puts when ctx
+// carries events. Inside a unit of work the writes are enlisted instead.
+// failed maps the write's error, nil included, to the one to return.
+func (r *DynamoDBRepository) write(ctx context.Context, item types.TransactWriteItem, failed func(error) error, unique ...uniqueWrite) error {
+	return r.writeWithEvents(ctx, item, ports.OutboxEvents(ctx), unique, failed)
+}
+
+// writeWithEvents is write with the outbox events given explicitly
+func (r *DynamoDBRepository) writeWithEvents(ctx context.Context, item types.TransactWriteItem, events []domain.ProductEvent, unique []uniqueWrite, failed func(error) error) error {
+	if r.outboxTable == "" {
+		events = nil
+	}
+	tx := activeTransaction(ctx)
+	if tx == nil && len(events) == 0 && len(unique) == 0 {
+		return failed(r.writeItem(ctx, item))
+	}
+
+	items := []types.TransactWriteItem{item}
+	for _, write := range unique {
+		items = append(items, write.item)
+	}
+	for _, event := range events {
+		outboxItem, err := newOutboxItem(event)
+		if err != nil {
+			return err
+		}
+		items = append(items, types.TransactWriteItem{Put: &types.Put{
+			TableName: aws.String(r.outboxTable),
+			Item:      outboxItem,
+		}})
+	}
+	if tx != nil {
+		tx.enlist(items, func(canceled *types.TransactionCanceledException) error {
+			return failed(transactionError(canceled, unique))
+		})
+		return nil
+	}
+	_, err := r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items})
+	return failed(transactionError(err, unique))
+}
+
+// writeItem runs item on its own, without a transaction
+func (r *DynamoDBRepository) writeItem(ctx context.Context, item types.TransactWriteItem) error {
+	switch {
+	case item.Put != nil:
+		_, err := r.client.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName:                 item.Put.TableName,
+			Item:                      item.Put.Item,
+			ConditionExpression:       item.Put.ConditionExpression,
+			ExpressionAttributeNames:  item.Put.ExpressionAttributeNames,
+			ExpressionAttributeValues: item.Put.ExpressionAttributeValues,
+		})
+		return err
+	case item.Delete != nil:
+		_, err := r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+			TableName:                           item.Delete.TableName,
+			Key:                                 item.Delete.Key,
+			ConditionExpression:                 item.Delete.ConditionExpression,
+			ExpressionAttributeNames:            item.Delete.ExpressionAttributeNames,
+			ExpressionAttributeValues:           item.Delete.ExpressionAttributeValues,
+			ReturnValuesOnConditionCheckFailure: item.Delete.ReturnValuesOnConditionCheckFailure,
+		})
+		return err
+	case item.Update != nil:
+		_, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName:                           item.Update.TableName,
+			Key:                                 item.Update.Key,
+			UpdateExpression:                    item.Update.UpdateExpression,
+			ConditionExpression:                 item.Update.ConditionExpression,
+			ExpressionAttributeNames:            item.Update.ExpressionAttributeNames,
+			ExpressionAttributeValues:           item.Update.ExpressionAttributeValues,
+			ReturnValuesOnConditionCheckFailure: item.Update.ReturnValuesOnConditionCheckFailure,
+		})
+		return err
+	default:
+		return errors.New("unsupported product write")
+	}
+}
+
+// transactionError surfaces a failed condition on the product write, always
+// the first item of the transaction, as the ConditionalCheckFailedException
+// the non-transactional write would have returned, and a failed
+// reservation, which follow it, as a *domain.DuplicateError
+func transactionError(err error, unique []uniqueWrite) error {
+	var canceled *types.TransactionCanceledException
+	if !errors.As(err, &canceled) {
+		return err
+	}
+	reasons := canceled.CancellationReasons
+	if len(reasons) > 0 && aws.ToString(reasons[0].Code) == "ConditionalCheckFailed" {
+		return &types.ConditionalCheckFailedException{Message: canceled.Message, Item: reasons[0].Item}
+	}
+	for i, write := range unique {
+		if i+1 < len(reasons) && aws.ToString(reasons[i+1].Code) == "ConditionalCheckFailed" {
+			return &domain.DuplicateError{Field: write.field, Value: write.value}
+		}
+	}
+	return err
+}
+
+// DynamoDBOutboxRepository reads the outbox filled by DynamoDBRepository
+type DynamoDBOutboxRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+func NewDynamoDBOutboxRepository(client *dynamodb.Client, tableName string) *DynamoDBOutboxRepository {
+	return &DynamoDBOutboxRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+func (r *DynamoDBOutboxRepository) ListPending(ctx context.Context, limit int) ([]domain.ProductEvent, error) {
+	result, err := r.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String(outboxPendingIndex),
+		KeyConditionExpression: aws.String("#pending = :pending"),
+		ExpressionAttributeNames: map[string]string{
+			"#pending": "pending",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pending": &types.AttributeValueMemberS{Value: outboxPending},
+		},
+		ScanIndexForward: aws.Bool(true),
+		Limit:            aws.Int32(int32(limit)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending events: %w", err)
+	}
+
+	var items []outboxItem
+	if err := attributevalue.UnmarshalListOfMaps(result.Items, &items); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal outbox items: %w", err)
+	}
+	events := make([]domain.ProductEvent, len(items))
+	for i, item := range items {
+		if err := json.Unmarshal([]byte(item.Payload), &events[i]); err != nil {
+			return nil, fmt.Errorf("failed to decode event %s: %w", item.ID, err)
+		}
+	}
+	return events, nil
+}
+
+// Append stores an event that comes with no product write
+func (r *DynamoDBOutboxRepository) Append(ctx context.Context, event domain.ProductEvent) error {
+	item, err := newOutboxItem(event)
+	if err != nil {
+		return err
+	}
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to append event %s: %w", event.ID, err)
+	}
+	return nil
+}
+
+// MarkSent takes the event out of the pending index and lets TTL remove it
+// after the retention period
+func (r *DynamoDBOutboxRepository) MarkSent(ctx context.Context, id string, sentAt time.Time) error {
+	_, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression: aws.String("SET #sent_at = :sent_at, #expires_at = :expires_at REMOVE #pending"),
+		ExpressionAttributeNames: map[string]string{
+			"#sent_at":    "sent_at",
+			"#expires_at": "expires_at",
+			"#pending":    "pending",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":sent_at":    &types.AttributeValueMemberS{Value: sentAt.UTC().Format(outboxTimeLayout)},
+			":expires_at": &types.AttributeValueMemberN{Value: strconv.FormatInt(sentAt.Add(outboxRetention).Unix(), 10)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to mark event %s sent: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// recordingTransport answers every call like stubTransport and remembers
+// the operation and body of each request
+type recordingTransport struct {
+	stubTransport
+	operations *[]string
+	bodies     *[]string
+	mu         *sync.Mutex
+}
+
+func (r recordingTransport) Do(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	target := req.Header.Get("X-Amz-Target")
+	*r.operations = append(*r.operations, target[strings.LastIndex(target, ".")+1:])
+	body, _ := io.ReadAll(req.Body)
+	*r.bodies = append(*r.bodies, string(body))
+	return r.stubTransport.Do(req)
+}
+
+func recordingRepository(status int, body string) (*DynamoDBRepository, *[]string, *[]string) {
+	var operations, bodies []string
+	client := dynamodb.New(dynamodb.Options{
+		Region:      "us-east-1",
+		Credentials: aws.AnonymousCredentials{},
+		HTTPClient:  recordingTransport{stubTransport{status: status, body: body}, &operations, &bodies, &sync.Mutex{}},
+	})
+	repo := NewDynamoDBRepository(client, "products", WithOutbox("product_outbox"))
+	return repo, &operations, &bodies
+}
+
+func TestWrite_Outbox(t *testing.T) {
+	product := domain.Product{ID: "prod-1", Name: "Laptop", Price: domain.Money{Amount: 99900, Currency: "USD"}, Version: 2}
+	event := domain.NewProductEvent(domain.EventProductUpdated, product.ID, &product, time.Date(2024, 8, 1, 12, 0, 0, 0, time.UTC))
+
+	repo, operations, bodies := recordingRepository(http.StatusOK, `{}`)
+	require.NoError(t, repo.Save(context.Background(), product))
+	require.NoError(t, repo.Save(ports.WithOutboxEvent(context.Background(), event), product))
+	require.NoError(t, repo.Delete(ports.WithOutboxEvent(context.Background(), event), product.ID, product.Version))
+
+	assert.Equal(t, []string{"PutItem", "TransactWriteItems", "TransactWriteItems"}, *operations)
+	assert.Contains(t, (*bodies)[0], `"ConditionExpression":"attribute_not_exists(#pk)"`)
+	assert.Contains(t, (*bodies)[1], `"TableName":"product_outbox"`)
+	assert.Contains(t, (*bodies)[1], `"occurred_at":{"S":"2024-08-01T12:00:00.000000000Z"}`)
+	assert.Contains(t, (*bodies)[2], `"Delete":{`)
+}
+
+func TestWrite_OutboxConditionFailed(t *testing.T) {
+	const canceled = `{"__type":"com.amazonaws.dynamodb.v20120810#TransactionCanceledException","message":"Transaction cancelled","CancellationReasons":[{"Code":"ConditionalCheckFailed"},{"Code":"None"}]}`
+	product := domain.Product{ID: "prod-1", Name: "Laptop", Price: domain.Money{Amount: 99900, Currency: "USD"}, Version: 2}
+	event := domain.NewProductEvent(domain.EventProductUpdated, product.ID, &product, time.Now())
+
+	repo, _, _ := recordingRepository(http.StatusBadRequest, canceled)
+	err := repo.Update(ports.WithOutboxEvent(context.Background(), event), product)
+	assert.ErrorIs(t, err, domain.ErrConflict)
+
+	// A create whose ID is taken is a duplicate, not a conflict
+	err = repo.Save(ports.WithOutboxEvent(context.Background(), event), product)
+	var duplicate *domain.DuplicateError
+	require.ErrorAs(t, err, &duplicate)
+	assert.Equal(t, domain.FieldID, duplicate.Field)
+	assert.Equal(t, "prod-1", duplicate.Value)
+}
+
+// Saving an ID twice fails the second time: the guard checks the key
+// attribute every stored product has
+func TestSave_DuplicateID(t *testing.T) {
+	var requests []string
+	client := dynamodb.New(dynamodb.Options{
+		Region:      "us-east-1",
+		Credentials: aws.AnonymousCredentials{},
+		HTTPClient: sequenceTransport{
+			bodies:   []string{`{}`, `{"__type":"com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException","message":"The conditional request failed"}`},
+			statuses: []int{http.StatusOK, http.StatusBadRequest},
+			requests: &requests,
+		},
+	})
+	repo := NewDynamoDBRepository(client, "products")
+	product := domain.Product{ID: "prod-1", Name: "Laptop", Price: domain.Money{Amount: 99900, Currency: "USD"}}
+
+	require.NoError(t, repo.Save(context.Background(), product))
+	err := repo.Save(context.Background(), product)
+	assert.ErrorIs(t, err, domain.ErrDuplicate)
+	assert.Equal(t, domain.KindConflict, domain.KindOf(err))
+
+	require.Len(t, requests, 2)
+	for _, body := range requests {
+		assert.Contains(t, body, `"ConditionExpression":"attribute_not_exists(#pk)"`)
+		assert.Contains(t, body, `"#pk":"pk"`)
+		assert.Contains(t, body, `"pk":{"S":"PRODUCT#prod-1"}`)
+	}
+}
+
+func TestDelete_ConditionFailed(t *testing.T) {
+	const conditionFailed = `{"__type":"com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException","message":"The conditional request failed"%s}`
+	const canceled = `{"__type":"com.amazonaws.dynamodb.v20120810#TransactionCanceledException","message":"Transaction cancelled","CancellationReasons":[{"Code":"ConditionalCheckFailed","Item":{"id":{"S":"prod-1"},"version":{"N":"3"}}},{"Code":"None"}]}`
+
+	tests := []struct {
+		name    string
+		body    string
+		event   bool
+		wantErr error
+	}{
+		{"missing product", strings.Replace(conditionFailed, "%s", "", 1), false, domain.ErrNotFound},
+		{"modified product", strings.Replace(conditionFailed, "%s", `,"Item":{"id":{"S":"prod-1"},"version":{"N":"3"}}`, 1), false, domain.ErrConflict},
+		{"other tenant's product", strings.Replace(conditionFailed, "%s", `,"Item":{"id":{"S":"prod-1"},"version":{"N":"2"},"tenant_id":{"S":"acme"}}`, 1), false, domain.ErrNotFound},
+		{"modified product in a transaction", canceled, true, domain.ErrConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.event {
+				ctx = ports.WithOutboxEvent(ctx, domain.NewProductEvent(domain.EventProductDeleted, "prod-1", nil, time.Now()))
+			}
+			repo, _, bodies := recordingRepository(http.StatusBadRequest, tt.body)
+			assert.ErrorIs(t, repo.Delete(ctx, "prod-1", 2), tt.wantErr)
+			assert.Contains(t, (*bodies)[0], `"ReturnValuesOnConditionCheckFailure":"ALL_OLD"`)
+			assert.Contains(t, (*bodies)[0], `":expected_version":{"N":"2"}`)
+		})
+	}
+}
+
+func TestDeleteBatch_Outbox(t *testing.T) {
+	products := []domain.Product{{ID: "prod-1", Version: 1}, {ID: "prod-2", Version: 4}}
+	ctx := ports.WithOutboxEvents(context.Background(), []domain.ProductEvent{
+		domain.NewProductEvent(domain.EventProductDeleted, "prod-1", nil, time.Now()),
+		domain.NewProductEvent(domain.EventProductDeleted, "prod-2", nil, time.Now()),
+	})
+
+	repo, operations, bodies := recordingRepository(http.StatusOK, `{}`)
+	skipped, err := repo.DeleteBatch(ctx, products)
+	require.NoError(t, err)
+
+	assert.Empty(t, skipped)
+	assert.Equal(t, []string{"TransactWriteItems"}, *operations)
+	assert.Equal(t, 2, strings.Count((*bodies)[0], `"Delete":{`))
+	assert.Equal(t, 2, strings.Count((*bodies)[0], `"TableName":"product_outbox"`))
+	assert.Contains(t, (*bodies)[0], `":expected_version":{"N":"4"}`)
+}
+
+func TestUpdates_Outbox(t *testing.T) {
+	now := time.Date(2024, 8, 1, 12, 0, 0, 0, time.UTC)
+	product := domain.Product{ID: "prod-1", Status: domain.StatusDraft, Stock: 5, Version: 2}
+	ctx := ports.WithOutboxEvent(context.Background(), domain.NewProductEvent(domain.EventProductUpdated, product.ID, &product, now))
+
+	repo, operations, bodies := recordingRepository(http.StatusOK, `{}`)
+	require.NoError(t, repo.MarkPublished(ctx, product, now))
+	require.NoError(t, repo.MarkArchiveWarned(ctx, product, now))
+	require.NoError(t, repo.MarkArchived(ctx, product, now))
+	stock, err := repo.AdjustStock(ctx, product, -2, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), stock)
+
+	assert.Equal(t, []string{"TransactWriteItems", "TransactWriteItems", "TransactWriteItems", "TransactWriteItems"}, *operations)
+	for _, body := range *bodies {
+		assert.Contains(t, body, `"TableName":"product_outbox"`)
+		assert.Contains(t, body, `":expected_version":{"N":"2"}`, "the event carries the product the write leaves behind")
+	}
+
+	// Without events the updates are written on their own
+	repo, operations, _ = recordingRepository(http.StatusOK, `{}`)
+	require.NoError(t, repo.MarkPublished(context.Background(), product, now))
+	assert.Equal(t, []string{"UpdateItem"}, *operations)
+}
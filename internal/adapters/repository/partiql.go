@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// ExecuteStatement runs a PartiQL statement scoped to the products table
+func (r *DynamoDBRepository) ExecuteStatement(ctx context.Context, query ports.StatementQuery) (*ports.StatementResult, error) {
+	// Statements must read from this table (or one of its indexes) and no other
+	if table, ok := statementTarget(query.Statement); !ok || table != r.tableName {
+		return nil, domain.ErrForbiddenQuery
+	}
+
+	parameters := make([]types.AttributeValue, len(query.Parameters))
+	for i, param := range query.Parameters {
+		av, err := attributevalue.Marshal(param)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal parameter %d: %w", i, err)
+		}
+		parameters[i] = av
+	}
+
+	input := &dynamodb.ExecuteStatementInput{
+		Statement:      aws.String(query.Statement),
+		ConsistentRead: aws.Bool(ports.ConsistentRead(ctx)),
+	}
+	if len(parameters) > 0 {
+		input.Parameters = parameters
+	}
+	if query.Limit > 0 {
+		input.Limit = aws.Int32(int32(query.Limit))
+	}
+	if query.NextToken != "" {
+		input.NextToken = aws.String(query.NextToken)
+	}
+
+	output, err := r.client.ExecuteStatement(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute statement: %w", err)
+	}
+
+	items := make([]map[string]interface{}, 0, len(output.Items))
+	if err := attributevalue.UnmarshalListOfMaps(output.Items, &items); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal statement results: %w", err)
+	}
+
+	return &ports.StatementResult{
+		Items:     items,
+		NextToken: aws.ToString(output.NextToken),
+	}, nil
+}
+
+// statementTarget returns the table a SELECT statement reads from. ok is
+// false unless the statement has exactly one FROM clause naming a table,
+// or a table and one of its indexes, followed by nothing but its WHERE and
+// ORDER BY clauses. Keywords inside string literals and quoted names do
+// not count.
+func statementTarget(statement string) (table string, ok bool) {
+	tokens, ok := partiqlTokens(statement)
+	if !ok {
+		return "", false
+	}
+	from := -1
+	for i, token := range tokens {
+		if !token.quoted && strings.EqualFold(token.text, "FROM") {
+			if from >= 0 {
+				return "", false
+			}
+			from = i
+		}
+	}
+	if from < 0 || from+1 >= len(tokens) || !tokens[from+1].name() {
+		return "", false
+	}
+	rest := tokens[from+2:]
+	if len(rest) >= 2 && rest[0].text == "." && !rest[0].quoted && rest[1].name() {
+		rest = rest[2:]
+	}
+	if len(rest) > 0 && (rest[0].quoted || !(strings.EqualFold(rest[0].text, "WHERE") || strings.EqualFold(rest[0].text, "ORDER"))) {
+		return "", false
+	}
+	return tokens[from+1].text, true
+}
+
+// partiqlToken is a word, a quoted name or a punctuation character of a
+// statement. String literals are dropped.
+type partiqlToken struct {
+	text   string
+	quoted bool
+}
+
+func (t partiqlToken) name() bool {
+	if t.quoted {
+		return true
+	}
+	for i, r := range t.text {
+		if !(r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r))) {
+			return false
+		}
+	}
+	return t.text != ""
+}
+
+// partiqlTokens splits a statement into tokens; ok is false when a string
+// literal or quoted name is left open
+func partiqlTokens(statement string) (tokens []partiqlToken, ok bool) {
+	runes := []rune(statement)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'' || r == '"':
+			// Quotes are escaped by doubling them
+			var text []rune
+			closed := false
+			for i++; i < len(runes); i++ {
+				if runes[i] == r {
+					if i+1 < len(runes) && runes[i+1] == r {
+						text = append(text, r)
+						i++
+						continue
+					}
+					closed = true
+					i++
+					break
+				}
+				text = append(text, runes[i])
+			}
+			if !closed {
+				return nil, false
+			}
+			if r == '"' {
+				tokens = append(tokens, partiqlToken{text: string(text), quoted: true})
+			}
+		case r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (runes[i] == '_' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			tokens = append(tokens, partiqlToken{text: string(runes[start:i])})
+		default:
+			tokens = append(tokens, partiqlToken{text: string(r)})
+			i++
+		}
+	}
+	return tokens, true
+}
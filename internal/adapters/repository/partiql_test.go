@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatementTarget(t *testing.T) {
+	tests := []struct {
+		name      string
+		statement string
+		table     string
+		ok        bool
+	}{
+		{"quoted table", `SELECT * FROM "products"`, "products", true},
+		{"bare table", `select id from products where price > ?`, "products", true},
+		{"index", `SELECT * FROM "products"."GSI1" WHERE gsi1pk = ?`, "products", true},
+		{"order by", `SELECT * FROM "products" WHERE pk = ? ORDER BY sk DESC`, "products", true},
+		{"other table", `SELECT * FROM "users"`, "users", true},
+		{"table named in a literal", `SELECT * FROM "users" WHERE note = '"products"'`, "users", true},
+		{"table named in a column", `SELECT "products" FROM "users"`, "users", true},
+		{"from in a literal", `SELECT * FROM "users" WHERE a = 'FROM "products"'`, "users", true},
+		{"escaped quote", `SELECT * FROM "products" WHERE name = 'it''s'`, "products", true},
+		{"two from clauses", `SELECT * FROM "products" WHERE id IN (SELECT id FROM "users")`, "", false},
+		{"trailing target", `SELECT * FROM "products", "users"`, "", false},
+		{"no from", `SELECT 1`, "", false},
+		{"open literal", `SELECT * FROM "products" WHERE name = 'x`, "", false},
+		{"open name", `SELECT * FROM "products`, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, ok := statementTarget(tt.statement)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.table, table)
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+const (
+	operationQuery = "Query"
+	operationScan  = "Scan"
+
+	// priceAttribute is the range key of the index that serves price ranges
+	priceAttribute = "price"
+	// currencyAttribute holds the currency of the price
+	currencyAttribute = "currency"
+	// costPriceAttribute holds the cost in major units of the price's
+	// currency
+	costPriceAttribute = "cost_price"
+)
+
+// planQuery picks the cheapest access path for the filters. A Query on a
+// declared sort index reads items in order and stops once the page is full.
+// Without one, a price range on its own is still read from the price index
+// and sorted in memory; anything else needs a full Scan.
+func (r *DynamoDBRepository) planQuery(filters ports.ProductFilters, consistent bool) ports.QueryPlan {
+	index, ok := indexForField(filters.SortBy)
+	priceIndex, hasPriceIndex := indexForField(priceAttribute)
+	switch {
+	case consistent:
+		return ports.QueryPlan{
+			Operation:    operationScan,
+			Reason:       "strongly consistent reads are not supported on global secondary indexes",
+			SortInMemory: true,
+		}
+	case ok:
+		return ports.QueryPlan{
+			Operation:  operationQuery,
+			Index:      index.Name,
+			Partitions: r.shards,
+			Reason:     fmt.Sprintf("index %q is sorted by %q", index.Name, index.Keys.RangeKey),
+		}
+	case hasPriceIndex && onlyPriceRange(filters):
+		return ports.QueryPlan{
+			Operation:    operationQuery,
+			Index:        priceIndex.Name,
+			Partitions:   r.shards,
+			SortInMemory: true,
+			Reason:       fmt.Sprintf("price range read from index %q, no index declared for sort field %q", priceIndex.Name, filters.SortBy),
+		}
+	default:
+		return ports.QueryPlan{
+			Operation:    operationScan,
+			Reason:       fmt.Sprintf("no index declared for sort field %q", filters.SortBy),
+			SortInMemory: true,
+		}
+	}
+}
+
+// onlyPriceRange reports whether a price range is the only filter requested
+func onlyPriceRange(filters ports.ProductFilters) bool {
+	return (!filters.MinPrice.IsZero() || !filters.MaxPrice.IsZero()) && filters.Name == "" && filters.CategoryID == "" && len(filters.Tags) == 0
+}
+
+// priceRangeKeyFilters splits the price bounds off filters when a query on
+// index can apply them as its key condition. It returns the filters left
+// for the filter expression and whether the bounds were split off.
+func priceRangeKeyFilters(index IndexSchema, filters ports.ProductFilters) (ports.ProductFilters, bool) {
+	if index.Keys.RangeKey != priceAttribute || (filters.MinPrice.IsZero() && filters.MaxPrice.IsZero()) {
+		return filters, false
+	}
+	remaining := filters
+	remaining.MinPrice, remaining.MaxPrice = domain.Decimal{}, domain.Decimal{}
+	return remaining, true
+}
+
+// timeRangeKeyFilters splits the bounds on index's range key off filters
+// when it is created_at or updated_at, like priceRangeKeyFilters, so a
+// listing of the changes since a time reads only those. A key attribute
+// cannot appear in the filter expression of a query on its index.
+func timeRangeKeyFilters(index IndexSchema, filters ports.ProductFilters) (ports.ProductFilters, bool) {
+	remaining := filters
+	switch {
+	case index.Keys.RangeKey == "created_at" && (!filters.CreatedAfter.IsZero() || !filters.CreatedBefore.IsZero()):
+		remaining.CreatedAfter, remaining.CreatedBefore = time.Time{}, time.Time{}
+	case index.Keys.RangeKey == "updated_at" && (!filters.UpdatedAfter.IsZero() || !filters.UpdatedBefore.IsZero()):
+		remaining.UpdatedAfter, remaining.UpdatedBefore = time.Time{}, time.Time{}
+	default:
+		return filters, false
+	}
+	return remaining, true
+}
+
+// timeRangeKeyCondition is the key condition on index's range key for the
+// time bounds timeRangeKeyFilters split off. Placeholders are registered in
+// names and values.
+func timeRangeKeyCondition(index IndexSchema, filters ports.ProductFilters, names map[string]string, values map[string]types.AttributeValue) string {
+	var bounds []timeBound
+	for _, bound := range timeBounds(filters) {
+		if bound.field == index.Keys.RangeKey {
+			bounds = append(bounds, bound)
+			values[bound.placeholder] = timeValue(bound.value)
+		}
+	}
+	field := "#" + index.Keys.RangeKey
+	names[field] = index.Keys.RangeKey
+	if len(bounds) == 2 {
+		return fmt.Sprintf("%s BETWEEN %s AND %s", field, bounds[0].placeholder, bounds[1].placeholder)
+	}
+	return fmt.Sprintf("%s %s %s", field, bounds[0].operator, bounds[0].placeholder)
+}
+
+// keyCondition builds the key condition for one index partition, adding
+// the price bounds of filters when priceRange is set. Placeholders are
+// registered in names and values.
+func keyCondition(partition string, filters ports.ProductFilters, priceRange bool, names map[string]string, values map[string]types.AttributeValue) string {
+	names["#pk"] = indexPartitionAttribute
+	values[":pk"] = &types.AttributeValueMemberS{Value: partition}
+	if !priceRange {
+		return "#pk = :pk"
+	}
+
+	names["#price"] = priceAttribute
+	minPrice := &types.AttributeValueMemberN{Value: filters.MinPrice.String()}
+	maxPrice := &types.AttributeValueMemberN{Value: filters.MaxPrice.String()}
+	switch {
+	case !filters.MinPrice.IsZero() && !filters.MaxPrice.IsZero():
+		values[":min_price"], values[":max_price"] = minPrice, maxPrice
+		return "#pk = :pk AND #price BETWEEN :min_price AND :max_price"
+	case !filters.MinPrice.IsZero():
+		values[":min_price"] = minPrice
+		return "#pk = :pk AND #price >= :min_price"
+	default:
+		values[":max_price"] = maxPrice
+		return "#pk = :pk AND #price <= :max_price"
+	}
+}
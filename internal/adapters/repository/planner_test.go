@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+func TestPlanQuery(t *testing.T) {
+	repo := NewDynamoDBRepository(nil, "products", WithIndexShards(2))
+
+	tests := []struct {
+		name         string
+		filters      ports.ProductFilters
+		consistent   bool
+		operation    string
+		index        string
+		sortInMemory bool
+	}{
+		{"sorted by price", ports.ProductFilters{SortBy: "price"}, false, operationQuery, "price-index", false},
+		{"sorted by created_at with filters", ports.ProductFilters{SortBy: "created_at", Name: "lap"}, false, operationQuery, "created_at-index", false},
+		{"sorted by name", ports.ProductFilters{SortBy: "name"}, false, operationScan, "", true},
+		{"price range sorted by name", ports.ProductFilters{SortBy: "name", MinPrice: domain.NewDecimal(10, 0)}, false, operationQuery, "price-index", true},
+		{"price range and name sorted by name", ports.ProductFilters{SortBy: "name", MinPrice: domain.NewDecimal(10, 0), Name: "lap"}, false, operationScan, "", true},
+		{"consistent read", ports.ProductFilters{SortBy: "price"}, true, operationScan, "", true},
+		{"consistent price range", ports.ProductFilters{SortBy: "name", MaxPrice: domain.NewDecimal(10, 0)}, true, operationScan, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := repo.planQuery(tt.filters, tt.consistent)
+			assert.Equal(t, tt.operation, plan.Operation)
+			assert.Equal(t, tt.index, plan.Index)
+			assert.Equal(t, tt.sortInMemory, plan.SortInMemory)
+			assert.NotEmpty(t, plan.Reason)
+		})
+	}
+}
+
+func TestKeyCondition(t *testing.T) {
+	priceIndex, _ := indexForField("price")
+	createdIndex, _ := indexForField("created_at")
+
+	tests := []struct {
+		name      string
+		index     IndexSchema
+		filters   ports.ProductFilters
+		condition string
+	}{
+		{"no range", priceIndex, ports.ProductFilters{}, "#pk = :pk"},
+		{"between", priceIndex, ports.ProductFilters{MinPrice: domain.NewDecimal(10, 0), MaxPrice: domain.NewDecimal(20, 0)}, "#pk = :pk AND #price BETWEEN :min_price AND :max_price"},
+		{"minimum", priceIndex, ports.ProductFilters{MinPrice: domain.NewDecimal(10, 0)}, "#pk = :pk AND #price >= :min_price"},
+		{"maximum", priceIndex, ports.ProductFilters{MaxPrice: domain.NewDecimal(20, 0)}, "#pk = :pk AND #price <= :max_price"},
+		{"other index", createdIndex, ports.ProductFilters{MinPrice: domain.NewDecimal(10, 0)}, "#pk = :pk"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remaining, priceRange := priceRangeKeyFilters(tt.index, tt.filters)
+			names := map[string]string{}
+			values := map[string]types.AttributeValue{}
+
+			assert.Equal(t, tt.condition, keyCondition("PRODUCT", tt.filters, priceRange, names, values))
+			assert.Equal(t, &types.AttributeValueMemberS{Value: "PRODUCT"}, values[":pk"])
+			if priceRange {
+				// Bounds in the key condition are not filtered a second time
+				assert.Zero(t, remaining.MinPrice)
+				assert.Zero(t, remaining.MaxPrice)
+			} else {
+				assert.Equal(t, tt.filters, remaining)
+			}
+		})
+	}
+}
+
+func TestTimeRangeKeyCondition(t *testing.T) {
+	createdIndex, _ := indexForField("created_at")
+	updatedIndex, _ := indexForField("updated_at")
+	since := time.Date(2024, 3, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+	until := since.Add(24 * time.Hour)
+
+	tests := []struct {
+		name      string
+		index     IndexSchema
+		filters   ports.ProductFilters
+		condition string
+		remaining ports.ProductFilters
+	}{
+		{"between", createdIndex, ports.ProductFilters{CreatedAfter: since, CreatedBefore: until}, "#created_at BETWEEN :created_after AND :created_before", ports.ProductFilters{}},
+		// Bounds on the other time are left to the filter expression
+		{"since", updatedIndex, ports.ProductFilters{UpdatedAfter: since, CreatedBefore: until}, "#updated_at >= :updated_after", ports.ProductFilters{CreatedBefore: until}},
+		{"until", updatedIndex, ports.ProductFilters{UpdatedBefore: until}, "#updated_at <= :updated_before", ports.ProductFilters{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remaining, timeRange := timeRangeKeyFilters(tt.index, tt.filters)
+			require.True(t, timeRange)
+			names := map[string]string{}
+			values := map[string]types.AttributeValue{}
+
+			assert.Equal(t, tt.condition, timeRangeKeyCondition(tt.index, tt.filters, names, values))
+			assert.Equal(t, tt.remaining, remaining)
+		})
+	}
+
+	_, timeRange := timeRangeKeyFilters(createdIndex, ports.ProductFilters{UpdatedAfter: since})
+	assert.False(t, timeRange)
+
+	values := map[string]types.AttributeValue{}
+	timeRangeKeyCondition(updatedIndex, ports.ProductFilters{UpdatedAfter: since}, map[string]string{}, values)
+	// Stored times are UTC strings, so the bound is converted to compare
+	assert.Equal(t, &types.AttributeValueMemberS{Value: "2024-03-01T11:00:00Z"}, values[":updated_after"])
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// ListDueForPublishing scans for drafts whose publish_at has passed, leaving
+// out the ones that expired first. Drafts are rare compared to the catalog,
+// so a filtered scan is cheap enough for a job that runs every minute or so.
+func (r *DynamoDBRepository) ListDueForPublishing(ctx context.Context, now time.Time) ([]domain.Product, error) {
+	names := map[string]string{
+		"#status":     "status",
+		"#publish_at": "publish_at",
+	}
+	values := map[string]types.AttributeValue{
+		":draft": &types.AttributeValueMemberS{Value: domain.StatusDraft},
+	}
+	filter := "#status = :draft AND #publish_at <= :now AND " + notExpiredCondition(now, names, values)
+	paginator := dynamodb.NewScanPaginator(r.client, productScan(&dynamodb.ScanInput{
+		TableName:                 aws.String(r.tableName),
+		FilterExpression:          aws.String(filter),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	}))
+
+	var products []domain.Product
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan scheduled drafts: %w", err)
+		}
+
+		batch, err := decodeProducts(page.Items)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal scheduled drafts: %w", err)
+		}
+		products = append(products, batch...)
+	}
+	return products, nil
+}
+
+// MarkPublished flips a due draft to published in a single conditional
+// update, so concurrent edits or a second publisher cannot publish it
+// twice. The outbox events of ctx are written with it.
+func (r *DynamoDBRepository) MarkPublished(ctx context.Context, product domain.Product, now time.Time) error {
+	updatedAt, err := attributevalue.Marshal(now)
+	if err != nil {
+		return fmt.Errorf("failed to marshal updated_at: %w", err)
+	}
+
+	return r.updateProduct(ctx, product, &types.Update{
+		UpdateExpression:    aws.String("SET #status = :published, #updated_at = :updated_at REMOVE #publish_at"),
+		ConditionExpression: aws.String("#status = :draft AND #publish_at <= :now"),
+		ExpressionAttributeNames: map[string]string{
+			"#status":     "status",
+			"#publish_at": "publish_at",
+			"#updated_at": "updated_at",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":draft":      &types.AttributeValueMemberS{Value: domain.StatusDraft},
+			":published":  &types.AttributeValueMemberS{Value: domain.StatusPublished},
+			":now":        &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Unix(), 10)},
+			":updated_at": updatedAt,
+		},
+	}, "failed to publish product")
+}
+
+// updateProduct runs update on product, conditional on its version as well
+// and bumping it, together with the outbox events of ctx. A product that
+// changed since it was read fails with domain.ErrConflict.
+func (r *DynamoDBRepository) updateProduct(ctx context.Context, product domain.Product, update *types.Update, msg string) error {
+	condition, values := versionCondition(product.Version)
+	maps.Copy(update.ExpressionAttributeValues, values)
+	update.ExpressionAttributeNames["#version"] = "version"
+	update.ExpressionAttributeValues[":one"] = &types.AttributeValueMemberN{Value: "1"}
+	update.TableName = aws.String(r.tableName)
+	update.Key = productKey(product.ID)
+	update.UpdateExpression = aws.String(aws.ToString(update.UpdateExpression) + " ADD #version :one")
+	update.ConditionExpression = aws.String(aws.ToString(update.ConditionExpression) + " AND " + condition)
+
+	err := r.write(ctx, types.TransactWriteItem{Update: update}, func(err error) error {
+		return conditionalUpdateError(err, msg)
+	})
+	if err == nil {
+		r.onWrite.call(ctx, ports.TenantID(ctx), product.ID)
+	}
+	return err
+}
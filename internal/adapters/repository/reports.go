@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// marginReportName keys the latest margin report in the reports table
+const marginReportName = "margins"
+
+// ListCosted scans for every unexpired product that has a cost price
+func (r *DynamoDBRepository) ListCosted(ctx context.Context) ([]domain.Product, error) {
+	names := map[string]string{
+		"#cost_price": costPriceAttribute,
+	}
+	values := map[string]types.AttributeValue{}
+	filter := "attribute_exists(#cost_price) AND " + notExpiredCondition(time.Now().UTC(), names, values)
+	paginator := dynamodb.NewScanPaginator(r.client, productScan(&dynamodb.ScanInput{
+		TableName:                 aws.String(r.tableName),
+		FilterExpression:          aws.String(filter),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	}))
+
+	var products []domain.Product
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan costed products: %w", err)
+		}
+
+		batch, err := decodeProducts(page.Items)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal costed products: %w", err)
+		}
+		products = append(products, batch...)
+	}
+	return products, nil
+}
+
+// DynamoDBReportRepository keeps the latest copy of each report as one item
+// keyed by report name
+type DynamoDBReportRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+func NewDynamoDBReportRepository(client *dynamodb.Client, tableName string) *DynamoDBReportRepository {
+	return &DynamoDBReportRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+type marginReportItem struct {
+	Name   string              `dynamodbav:"name"`
+	Report domain.MarginReport `dynamodbav:"report"`
+}
+
+func (r *DynamoDBReportRepository) SaveMarginReport(ctx context.Context, report domain.MarginReport) error {
+	item, err := attributevalue.MarshalMap(marginReportItem{Name: marginReportName, Report: report})
+	if err != nil {
+		return fmt.Errorf("failed to marshal margin report: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save margin report: %w", err)
+	}
+	return nil
+}
+
+func (r *DynamoDBReportRepository) LatestMarginReport(ctx context.Context) (domain.MarginReport, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"name": &types.AttributeValueMemberS{Value: marginReportName},
+		},
+	})
+	if err != nil {
+		return domain.MarginReport{}, fmt.Errorf("failed to get margin report: %w", err)
+	}
+	if result.Item == nil {
+		return domain.MarginReport{}, domain.ErrReportNotReady
+	}
+
+	var item marginReportItem
+	if err := attributevalue.UnmarshalMap(result.Item, &item); err != nil {
+		return domain.MarginReport{}, fmt.Errorf("failed to unmarshal margin report: %w", err)
+	}
+	return item.Report, nil
+}
@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// reviewItem is one review, stored in its product's partition with the
+// time-ordered review ID in its sort key
+type reviewItem struct {
+	ID        string    `dynamodbav:"id"`
+	ProductID string    `dynamodbav:"product_id"`
+	TenantID  string    `dynamodbav:"tenant_id,omitempty"`
+	Rating    int       `dynamodbav:"rating"`
+	Comment   string    `dynamodbav:"comment,omitempty"`
+	Author    string    `dynamodbav:"author"`
+	CreatedAt time.Time `dynamodbav:"created_at"`
+	UpdatedAt time.Time `dynamodbav:"updated_at"`
+}
+
+// DynamoDBReviewRepository stores reviews in the products table, under
+// their product's partition, and their rating totals on the product item
+type DynamoDBReviewRepository struct {
+	client    *dynamodb.Client
+	tableName string
+	counters  productCounters
+	onWrite   ProductWriteHook
+}
+
+// NewDynamoDBReviewRepository writes a product.updated event to
+// outboxTable, unless it is empty, and calls onWrite, which may be nil,
+// whenever the rating totals of a product change
+func NewDynamoDBReviewRepository(client *dynamodb.Client, tableName, outboxTable string, onWrite ProductWriteHook) *DynamoDBReviewRepository {
+	return &DynamoDBReviewRepository{
+		client:    client,
+		tableName: tableName,
+		counters:  productCounters{client: client, tableName: tableName, outboxTable: outboxTable},
+		onWrite:   onWrite,
+	}
+}
+
+func (r *DynamoDBReviewRepository) Create(ctx context.Context, review domain.Review) error {
+	item, err := toReviewItem(review)
+	if err != nil {
+		return err
+	}
+	return r.transact(ctx, review, "failed to create review",
+		types.TransactWriteItem{Put: &types.Put{
+			TableName:                aws.String(r.tableName),
+			Item:                     item,
+			ConditionExpression:      aws.String("attribute_not_exists(#id)"),
+			ExpressionAttributeNames: map[string]string{"#id": "id"},
+		}},
+		1, int64(review.Rating),
+	)
+}
+
+func (r *DynamoDBReviewRepository) Get(ctx context.Context, productID, id string) (domain.Review, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(r.tableName),
+		Key:            reviewKey(productID, id),
+		ConsistentRead: aws.Bool(ports.ConsistentRead(ctx)),
+	})
+	if err != nil {
+		return domain.Review{}, fmt.Errorf("failed to get review: %w", err)
+	}
+	if result.Item == nil || itemTenant(result.Item) != ports.TenantID(ctx) {
+		return domain.Review{}, domain.ErrReviewNotFound
+	}
+	return decodeReview(result.Item)
+}
+
+// List reads one page of the product's reviews. It does not check tenants:
+// product IDs are unique across tenants, and callers check the product's.
+func (r *DynamoDBReviewRepository) List(ctx context.Context, productID, after string, limit int) ([]domain.Review, error) {
+	condition := "#pk = :pk AND begins_with(#sk, :from)"
+	from := entityKey(entityReview, "")
+	if after != "" {
+		// Below the reviews the partition holds the product item, reached
+		// only once every older review has been read and left out by the
+		// filter
+		condition = "#pk = :pk AND #sk < :from"
+		from = entityKey(entityReview, after)
+	}
+
+	result, err := r.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		KeyConditionExpression: aws.String(condition),
+		FilterExpression:       aws.String("#entity_type = :review"),
+		ExpressionAttributeNames: map[string]string{
+			"#pk":          partitionKeyAttribute,
+			"#sk":          sortKeyAttribute,
+			"#entity_type": entityTypeAttribute,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":     &types.AttributeValueMemberS{Value: entityKey(entityProduct, productID)},
+			":from":   &types.AttributeValueMemberS{Value: from},
+			":review": &types.AttributeValueMemberS{Value: entityReview},
+		},
+		ScanIndexForward: aws.Bool(false),
+		Limit:            aws.Int32(int32(limit)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reviews: %w", err)
+	}
+
+	reviews := make([]domain.Review, 0, len(result.Items))
+	for _, raw := range result.Items {
+		review, err := decodeReview(raw)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, nil
+}
+
+// Update overwrites the review only if its rating is still previousRating,
+// so the change added to the product's rating sum is the one it made
+func (r *DynamoDBReviewRepository) Update(ctx context.Context, review domain.Review, previousRating int) error {
+	item, err := toReviewItem(review)
+	if err != nil {
+		return err
+	}
+	put := types.TransactWriteItem{Put: &types.Put{
+		TableName:                aws.String(r.tableName),
+		Item:                     item,
+		ConditionExpression:      aws.String("attribute_exists(#id) AND #rating = :previous"),
+		ExpressionAttributeNames: map[string]string{"#id": "id", "#rating": "rating"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":previous": &types.AttributeValueMemberN{Value: strconv.Itoa(previousRating)},
+		},
+	}}
+
+	return r.transact(ctx, review, "failed to update review", put, 0, int64(review.Rating-previousRating))
+}
+
+// Delete removes the review only if it still has the rating it was read
+// with, taking that rating out of the product's totals
+func (r *DynamoDBReviewRepository) Delete(ctx context.Context, review domain.Review) error {
+	return r.transact(ctx, review, "failed to delete review",
+		types.TransactWriteItem{Delete: &types.Delete{
+			TableName:                aws.String(r.tableName),
+			Key:                      reviewKey(review.ProductID, review.ID),
+			ConditionExpression:      aws.String("attribute_exists(#id) AND #rating = :rating"),
+			ExpressionAttributeNames: map[string]string{"#id": "id", "#rating": "rating"},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":rating": &types.AttributeValueMemberN{Value: strconv.Itoa(review.Rating)},
+			},
+		}},
+		-1, -int64(review.Rating),
+	)
+}
+
+// transact runs the write of review, together with the change of count and
+// sum to the product's rating totals when there is one. A failed condition
+// on the review means it changed since it was read, and one on the product
+// that the product is gone.
+func (r *DynamoDBReviewRepository) transact(ctx context.Context, review domain.Review, msg string, item types.TransactWriteItem, count, sum int64) error {
+	var err error
+	if count == 0 && sum == 0 {
+		_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{item}})
+		if failedCondition(err) == 0 {
+			err = errCounterItemFailed
+		}
+	} else {
+		update := ratingUpdate(r.tableName, review.TenantID, review.ProductID, count, sum)
+		err = r.counters.write(ctx, review.TenantID, review.ProductID, item, update, func(product *domain.Product) {
+			product.RatingCount += count
+			product.RatingSum += sum
+		})
+	}
+	switch {
+	case errors.Is(err, errCounterItemFailed):
+		return domain.ErrConflict
+	case errors.Is(err, domain.ErrNotFound), errors.Is(err, domain.ErrConflict):
+		return err
+	case err != nil:
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if count != 0 || sum != 0 {
+		r.onWrite.call(ctx, review.TenantID, review.ProductID)
+	}
+	return nil
+}
+
+// ratingUpdate adds count and sum to the rating totals of one of tenant's
+// products. Like a stock adjustment it bumps the version, so a product
+// update read before it cannot overwrite the totals.
+func ratingUpdate(tableName, tenant, productID string, count, sum int64) *types.Update {
+	names := map[string]string{
+		"#pk":           partitionKeyAttribute,
+		"#rating_count": "rating_count",
+		"#rating_sum":   "rating_sum",
+		"#version":      "version",
+	}
+	values := map[string]types.AttributeValue{
+		":count": &types.AttributeValueMemberN{Value: strconv.FormatInt(count, 10)},
+		":sum":   &types.AttributeValueMemberN{Value: strconv.FormatInt(sum, 10)},
+		":one":   &types.AttributeValueMemberN{Value: "1"},
+	}
+	condition := "attribute_exists(#pk) AND " + tenantCondition(tenant, names, values)
+
+	return &types.Update{
+		TableName:                 aws.String(tableName),
+		Key:                       productKey(productID),
+		UpdateExpression:          aws.String("ADD #rating_count :count, #rating_sum :sum, #version :one"),
+		ConditionExpression:       aws.String(condition),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	}
+}
+
+// toReviewItem marshals a review together with its key
+func toReviewItem(review domain.Review) (map[string]types.AttributeValue, error) {
+	item, err := attributevalue.MarshalMap(reviewItem(review))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal review: %w", err)
+	}
+	return withKey(item, reviewKey(review.ProductID, review.ID), entityReview), nil
+}
+
+func decodeReview(raw map[string]types.AttributeValue) (domain.Review, error) {
+	var item reviewItem
+	if err := attributevalue.UnmarshalMap(raw, &item); err != nil {
+		return domain.Review{}, fmt.Errorf("failed to unmarshal review: %w", err)
+	}
+	return domain.Review(item), nil
+}
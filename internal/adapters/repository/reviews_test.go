@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+func TestRatingUpdate(t *testing.T) {
+	update := ratingUpdate("products", "", "1", 1, 4)
+	assert.Equal(t, "ADD #rating_count :count, #rating_sum :sum, #version :one", *update.UpdateExpression)
+	assert.Equal(t, "attribute_exists(#pk) AND attribute_not_exists(#tenant_id)", *update.ConditionExpression)
+	assert.Equal(t, &types.AttributeValueMemberN{Value: "1"}, update.ExpressionAttributeValues[":count"])
+	assert.Equal(t, &types.AttributeValueMemberN{Value: "4"}, update.ExpressionAttributeValues[":sum"])
+
+	update = ratingUpdate("products", "acme", "1", -1, -3)
+	assert.Equal(t, "attribute_exists(#pk) AND #tenant_id = :tenant_id", *update.ConditionExpression)
+	assert.Equal(t, &types.AttributeValueMemberN{Value: "-1"}, update.ExpressionAttributeValues[":count"])
+	assert.Equal(t, &types.AttributeValueMemberN{Value: "-3"}, update.ExpressionAttributeValues[":sum"])
+}
+
+func TestReviewRepository_TransactionErrors(t *testing.T) {
+	const canceled = `{"__type":"com.amazonaws.dynamodb.v20120810#TransactionCanceledException","Message":"Transaction cancelled","CancellationReasons":[{"Code":"%s"},{"Code":"%s"}]}`
+
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr error
+	}{
+		{"applied", http.StatusOK, `{}`, nil},
+		{"review changed", http.StatusBadRequest, fmt.Sprintf(canceled, "ConditionalCheckFailed", "None"), domain.ErrConflict},
+		{"product gone", http.StatusBadRequest, fmt.Sprintf(canceled, "None", "ConditionalCheckFailed"), domain.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := dynamodb.New(dynamodb.Options{
+				Region:      "us-east-1",
+				Credentials: aws.AnonymousCredentials{},
+				HTTPClient:  stubTransport{status: tt.status, body: tt.body},
+			})
+			repo := NewDynamoDBReviewRepository(client, "products", "", nil)
+			review := domain.Review{ID: "r1", ProductID: "1", Rating: 4, Author: "alice"}
+
+			assert.ErrorIs(t, repo.Delete(context.Background(), review), tt.wantErr)
+			if tt.wantErr == nil {
+				assert.NoError(t, repo.Update(context.Background(), review, 2))
+			}
+		})
+	}
+}
+
+func TestReviewRepository_OutboxRereadsChangedProduct(t *testing.T) {
+	var requests []string
+	client := dynamodb.New(dynamodb.Options{
+		Region:      "us-east-1",
+		Credentials: aws.AnonymousCredentials{},
+		HTTPClient: sequenceTransport{requests: &requests, statuses: []int{http.StatusOK, http.StatusBadRequest, http.StatusOK, http.StatusOK}, bodies: []string{
+			`{"Item":{"id":{"S":"1"},"rating_count":{"N":"1"},"rating_sum":{"N":"5"},"version":{"N":"2"}}}`,
+			`{"__type":"com.amazonaws.dynamodb.v20120810#TransactionCanceledException","Message":"Transaction cancelled",
+			  "CancellationReasons":[{"Code":"None"},{"Code":"ConditionalCheckFailed","Item":{"id":{"S":"1"},"version":{"N":"3"}}},{"Code":"None"}]}`,
+			`{"Item":{"id":{"S":"1"},"rating_count":{"N":"2"},"rating_sum":{"N":"9"},"version":{"N":"3"}}}`,
+			`{}`,
+		}},
+	})
+	repo := NewDynamoDBReviewRepository(client, "products", "product_outbox", nil)
+
+	require.NoError(t, repo.Create(context.Background(), domain.Review{ID: "r1", ProductID: "1", Rating: 4, Author: "alice"}))
+	require.Len(t, requests, 4)
+	assert.Contains(t, requests[1], `":expected_version":{"N":"2"}`)
+	assert.Contains(t, requests[3], `":expected_version":{"N":"3"}`, "the product changed in between is read again")
+	assert.Contains(t, requests[3], `"TableName":"product_outbox"`)
+	assert.Contains(t, requests[3], `"rating_sum\":13`, "the event carries the totals the write leaves behind")
+}
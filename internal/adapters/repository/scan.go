@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"golang.org/x/sync/errgroup"
+)
+
+// WithParallelScan splits the full scans behind exports, counts and
+// listings without a sort index into segments read concurrently by up to
+// workers goroutines, cutting their latency on large tables at the cost of
+// a higher read rate. Workers defaults to one per segment.
+func WithParallelScan(segments, workers int) Option {
+	return func(r *DynamoDBRepository) {
+		if segments > 1 {
+			r.scanSegments = segments
+			r.scanWorkers = segments
+			if workers > 0 && workers < segments {
+				r.scanWorkers = workers
+			}
+		}
+	}
+}
+
+// parallelScan reads every page of input, split over the configured scan
+// segments. fn is called with one page at a time, in no particular order
+// across segments.
+func (r *DynamoDBRepository) parallelScan(ctx context.Context, input *dynamodb.ScanInput, fn func(page *dynamodb.ScanOutput) error) error {
+	if r.scanSegments <= 1 {
+		return scanSegment(ctx, r.client, *input, fn)
+	}
+
+	var mu sync.Mutex
+	serialized := func(page *dynamodb.ScanOutput) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return fn(page)
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(r.scanWorkers)
+	for segment := range r.scanSegments {
+		segmentInput := *input
+		segmentInput.Segment = aws.Int32(int32(segment))
+		segmentInput.TotalSegments = aws.Int32(int32(r.scanSegments))
+		g.Go(func() error {
+			return scanSegment(gctx, r.client, segmentInput, serialized)
+		})
+	}
+	return g.Wait()
+}
+
+func scanSegment(ctx context.Context, client *dynamodb.Client, input dynamodb.ScanInput, fn func(page *dynamodb.ScanOutput) error) error {
+	paginator := dynamodb.NewScanPaginator(client, &input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to scan products: %w", err)
+		}
+		if err := fn(page); err != nil {
+			return err
+		}
+	}
+	return nil
+}
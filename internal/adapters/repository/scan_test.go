@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+var segmentPattern = regexp.MustCompile(`"Segment":(\d+)`)
+
+func TestGetTotalCount_ParallelScan(t *testing.T) {
+	repo, operations, bodies := recordingRepository(http.StatusOK, `{"Count":3}`)
+	WithParallelScan(4, 2)(repo)
+
+	total, err := repo.getTotalCount(context.Background(), ports.ProductFilters{Name: "lamp"})
+	require.NoError(t, err)
+	assert.Equal(t, 12, total)
+	assert.Equal(t, []string{"Scan", "Scan", "Scan", "Scan"}, *operations)
+
+	var segments []string
+	for _, body := range *bodies {
+		assert.Contains(t, body, `"TotalSegments":4`)
+		segments = append(segments, segmentPattern.FindStringSubmatch(body)[1])
+	}
+	slices.Sort(segments)
+	assert.Equal(t, []string{"0", "1", "2", "3"}, segments)
+}
+
+func TestStreamProducts_SerialScan(t *testing.T) {
+	repo, operations, bodies := recordingRepository(http.StatusOK, `{"Count":1,"Items":[{"id":{"S":"prod-1"}}]}`)
+
+	var streamed []domain.Product
+	err := repo.StreamProducts(context.Background(), ports.ProductFilters{}, func(page []domain.Product) error {
+		streamed = append(streamed, page...)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Scan"}, *operations)
+	assert.NotContains(t, (*bodies)[0], "Segment")
+	require.Len(t, streamed, 1)
+	assert.Equal(t, "prod-1", streamed[0].ID)
+}
+
+func TestWithParallelScan(t *testing.T) {
+	repo := &DynamoDBRepository{}
+	WithParallelScan(1, 4)(repo)
+	assert.Zero(t, repo.scanSegments)
+
+	WithParallelScan(8, 0)(repo)
+	assert.Equal(t, 8, repo.scanSegments)
+	assert.Equal(t, 8, repo.scanWorkers)
+
+	WithParallelScan(8, 3)(repo)
+	assert.Equal(t, 3, repo.scanWorkers)
+}
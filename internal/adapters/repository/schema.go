@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// KeySchema describes the hash and optional range key of a table or index
+type KeySchema struct {
+	HashKey  string
+	RangeKey string
+}
+
+// IndexSchema describes a global secondary index the repository queries
+type IndexSchema struct {
+	Name string
+	Keys KeySchema
+	// RangeKeyType is the attribute type of the range key, needed to create the index
+	RangeKeyType types.ScalarAttributeType
+}
+
+// TableSchema is the table layout the repository code relies on
+type TableSchema struct {
+	Keys         KeySchema
+	Indexes      []IndexSchema
+	StreamView   types.StreamViewType // empty when no stream is required
+	TTLAttribute string               // empty when TTL is not required
+}
+
+// ExpectedSchema returns the layout DynamoDBRepository needs from its
+// table, which holds products, their reviews and the categories under the
+// generic keys described in keys.go
+func ExpectedSchema() TableSchema {
+	return TableSchema{
+		Keys:         KeySchema{HashKey: partitionKeyAttribute, RangeKey: sortKeyAttribute},
+		Indexes:      ProductIndexes(),
+		TTLAttribute: "expires_at",
+	}
+}
+
+// SchemaMismatchError lists every difference between the live table and
+// the expected schema
+type SchemaMismatchError struct {
+	Table      string
+	Mismatches []string
+}
+
+func (e *SchemaMismatchError) Error() string {
+	return fmt.Sprintf("table %q does not match expected schema: %s", e.Table, strings.Join(e.Mismatches, "; "))
+}
+
+// Ping describes the table, a cheap call that fails when DynamoDB is
+// unreachable, credentials are rejected or the table is not usable yet
+func Ping(ctx context.Context, client *dynamodb.Client, tableName string) error {
+	table, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(tableName),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe table %q: %w", tableName, err)
+	}
+	// Updating tables keep serving reads and writes
+	if status := table.Table.TableStatus; status != types.TableStatusActive && status != types.TableStatusUpdating {
+		return fmt.Errorf("table %q is %s", tableName, status)
+	}
+	return nil
+}
+
+// VerifySchema describes the table and reports a *SchemaMismatchError when
+// its key schema, indexes, stream or TTL settings differ from expected
+func VerifySchema(ctx context.Context, client *dynamodb.Client, tableName string, expected TableSchema) error {
+	table, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(tableName),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe table %q: %w", tableName, err)
+	}
+
+	var ttl *types.TimeToLiveDescription
+	if expected.TTLAttribute != "" {
+		ttlOutput, err := client.DescribeTimeToLive(ctx, &dynamodb.DescribeTimeToLiveInput{
+			TableName: aws.String(tableName),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to describe time to live for %q: %w", tableName, err)
+		}
+		ttl = ttlOutput.TimeToLiveDescription
+	}
+
+	if mismatches := compareSchema(expected, table.Table, ttl); len(mismatches) > 0 {
+		return &SchemaMismatchError{Table: tableName, Mismatches: mismatches}
+	}
+	return nil
+}
+
+func compareSchema(expected TableSchema, table *types.TableDescription, ttl *types.TimeToLiveDescription) []string {
+	var mismatches []string
+
+	mismatches = append(mismatches, compareKeys("table", expected.Keys, table.KeySchema)...)
+
+	indexes := make(map[string]types.GlobalSecondaryIndexDescription, len(table.GlobalSecondaryIndexes))
+	for _, index := range table.GlobalSecondaryIndexes {
+		indexes[aws.ToString(index.IndexName)] = index
+	}
+	for _, want := range expected.Indexes {
+		index, ok := indexes[want.Name]
+		if !ok {
+			mismatches = append(mismatches, fmt.Sprintf("missing global secondary index %q", want.Name))
+			continue
+		}
+		mismatches = append(mismatches, compareKeys("index "+want.Name, want.Keys, index.KeySchema)...)
+	}
+
+	if expected.StreamView != "" {
+		spec := table.StreamSpecification
+		switch {
+		case spec == nil || !aws.ToBool(spec.StreamEnabled):
+			mismatches = append(mismatches, fmt.Sprintf("stream is disabled, expected %s", expected.StreamView))
+		case spec.StreamViewType != expected.StreamView:
+			mismatches = append(mismatches, fmt.Sprintf("stream view type is %s, expected %s", spec.StreamViewType, expected.StreamView))
+		}
+	}
+
+	if expected.TTLAttribute != "" {
+		switch {
+		case ttl == nil || ttl.TimeToLiveStatus != types.TimeToLiveStatusEnabled:
+			mismatches = append(mismatches, fmt.Sprintf("time to live is not enabled, expected on %q", expected.TTLAttribute))
+		case aws.ToString(ttl.AttributeName) != expected.TTLAttribute:
+			mismatches = append(mismatches, fmt.Sprintf("time to live attribute is %q, expected %q", aws.ToString(ttl.AttributeName), expected.TTLAttribute))
+		}
+	}
+
+	return mismatches
+}
+
+// KeyMismatches lists the differences between a table's key schema and
+// expected, which unlike indexes cannot be changed in place
+func KeyMismatches(expected KeySchema, actual []types.KeySchemaElement) []string {
+	return compareKeys("table", expected, actual)
+}
+
+func compareKeys(scope string, expected KeySchema, actual []types.KeySchemaElement) []string {
+	var hashKey, rangeKey string
+	for _, element := range actual {
+		switch element.KeyType {
+		case types.KeyTypeHash:
+			hashKey = aws.ToString(element.AttributeName)
+		case types.KeyTypeRange:
+			rangeKey = aws.ToString(element.AttributeName)
+		}
+	}
+
+	var mismatches []string
+	if hashKey != expected.HashKey {
+		mismatches = append(mismatches, fmt.Sprintf("%s hash key is %q, expected %q", scope, hashKey, expected.HashKey))
+	}
+	if rangeKey != expected.RangeKey {
+		mismatches = append(mismatches, fmt.Sprintf("%s range key is %q, expected %q", scope, rangeKey, expected.RangeKey))
+	}
+	return mismatches
+}
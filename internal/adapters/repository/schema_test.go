@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func hashKey(name string) []types.KeySchemaElement {
+	return []types.KeySchemaElement{{AttributeName: aws.String(name), KeyType: types.KeyTypeHash}}
+}
+
+func TestCompareSchema(t *testing.T) {
+	expected := TableSchema{
+		Keys:         KeySchema{HashKey: "id"},
+		Indexes:      []IndexSchema{{Name: "price-index", Keys: KeySchema{HashKey: "gsi_pk", RangeKey: "price"}}},
+		StreamView:   types.StreamViewTypeNewAndOldImages,
+		TTLAttribute: "expires_at",
+	}
+	enabledTTL := &types.TimeToLiveDescription{
+		AttributeName:    aws.String("expires_at"),
+		TimeToLiveStatus: types.TimeToLiveStatusEnabled,
+	}
+	matchingIndex := types.GlobalSecondaryIndexDescription{
+		IndexName: aws.String("price-index"),
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("gsi_pk"), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String("price"), KeyType: types.KeyTypeRange},
+		},
+	}
+	enabledStream := &types.StreamSpecification{
+		StreamEnabled:  aws.Bool(true),
+		StreamViewType: types.StreamViewTypeNewAndOldImages,
+	}
+
+	tests := []struct {
+		name     string
+		table    *types.TableDescription
+		ttl      *types.TimeToLiveDescription
+		expected []string
+	}{
+		{
+			name: "matching",
+			table: &types.TableDescription{
+				KeySchema:              hashKey("id"),
+				GlobalSecondaryIndexes: []types.GlobalSecondaryIndexDescription{matchingIndex},
+				StreamSpecification:    enabledStream,
+			},
+			ttl: enabledTTL,
+		},
+		{
+			name: "everything wrong",
+			table: &types.TableDescription{
+				KeySchema: hashKey("pk"),
+			},
+			expected: []string{
+				`table hash key is "pk", expected "id"`,
+				`missing global secondary index "price-index"`,
+				"stream is disabled, expected NEW_AND_OLD_IMAGES",
+				`time to live is not enabled, expected on "expires_at"`,
+			},
+		},
+		{
+			name: "wrong ttl attribute",
+			table: &types.TableDescription{
+				KeySchema:              hashKey("id"),
+				GlobalSecondaryIndexes: []types.GlobalSecondaryIndexDescription{matchingIndex},
+				StreamSpecification:    enabledStream,
+			},
+			ttl: &types.TimeToLiveDescription{
+				AttributeName:    aws.String("ttl"),
+				TimeToLiveStatus: types.TimeToLiveStatusEnabled,
+			},
+			expected: []string{`time to live attribute is "ttl", expected "expires_at"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, compareSchema(expected, tt.table, tt.ttl))
+		})
+	}
+}
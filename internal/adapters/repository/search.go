@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// Search scans for visible products whose name or description contains the
+// query and ranks them in memory, name matches first. contains() is case
+// sensitive, so the query is also tried lowercased and capitalized; use the
+// OpenSearch adapter for real relevance ranking.
+func (r *DynamoDBRepository) Search(ctx context.Context, query ports.SearchQuery) ([]domain.SearchHit, error) {
+	input := &dynamodb.ScanInput{TableName: aws.String(r.tableName)}
+	input.FilterExpression, input.ExpressionAttributeNames, input.ExpressionAttributeValues =
+		buildFilterExpression(ports.ProductFilters{}, ports.TenantID(ctx), time.Now().UTC())
+
+	input.ExpressionAttributeNames["#name"] = "name"
+	input.ExpressionAttributeNames["#description"] = "description"
+	var matches []string
+	for i, variant := range searchVariants(query.Text) {
+		placeholder := ":q" + strconv.Itoa(i)
+		input.ExpressionAttributeValues[placeholder] = &types.AttributeValueMemberS{Value: variant}
+		matches = append(matches, fmt.Sprintf("contains(#name, %s) OR contains(#description, %s)", placeholder, placeholder))
+	}
+	input.FilterExpression = aws.String(fmt.Sprintf("%s AND (%s)", aws.ToString(input.FilterExpression), strings.Join(matches, " OR ")))
+
+	var hits []domain.SearchHit
+	paginator := dynamodb.NewScanPaginator(r.client, productScan(input))
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan products for search: %w", err)
+		}
+
+		batch, err := decodeProducts(page.Items)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal products: %w", err)
+		}
+		for _, product := range batch {
+			hits = append(hits, domain.SearchHit{Product: product, Score: domain.MatchScore(product, query.Text)})
+		}
+	}
+	return domain.RankSearchHits(hits, query.Limit), nil
+}
+
+// searchVariants lists the distinct spellings of text tried by Search
+func searchVariants(text string) []string {
+	lower := []rune(strings.ToLower(text))
+	capitalized := append([]rune{unicode.ToUpper(lower[0])}, lower[1:]...)
+
+	var variants []string
+	seen := make(map[string]bool)
+	for _, variant := range []string{text, string(lower), string(capitalized)} {
+		if !seen[variant] {
+			seen[variant] = true
+			variants = append(variants, variant)
+		}
+	}
+	return variants
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+const searchTermsDayIndex = "day-index"
+
+// DynamoDBSearchTermRepository keeps one counter item per search term and
+// day, keyed by term and day, with a day-index GSI for reports
+type DynamoDBSearchTermRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+func NewDynamoDBSearchTermRepository(client *dynamodb.Client, tableName string) *DynamoDBSearchTermRepository {
+	return &DynamoDBSearchTermRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+type searchTermCounter struct {
+	Term        string `dynamodbav:"term"`
+	Day         string `dynamodbav:"day"`
+	Searches    int64  `dynamodbav:"searches"`
+	ZeroResults int64  `dynamodbav:"zero_results"`
+}
+
+// IncrementSearch atomically adds one search, and one zero-result search when
+// nothing matched, to the term's counters for day
+func (r *DynamoDBSearchTermRepository) IncrementSearch(ctx context.Context, term string, day time.Time, zeroResults bool) error {
+	zero := "0"
+	if zeroResults {
+		zero = "1"
+	}
+
+	_, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"term": &types.AttributeValueMemberS{Value: term},
+			"day":  &types.AttributeValueMemberS{Value: day.Format(dayLayout)},
+		},
+		UpdateExpression: aws.String("ADD #searches :one, #zero_results :zero"),
+		ExpressionAttributeNames: map[string]string{
+			"#searches":     "searches",
+			"#zero_results": "zero_results",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":one":  &types.AttributeValueMemberN{Value: "1"},
+			":zero": &types.AttributeValueMemberN{Value: zero},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to increment search term: %w", err)
+	}
+	return nil
+}
+
+// DailySearchTerms returns every term's counters for day
+func (r *DynamoDBSearchTermRepository) DailySearchTerms(ctx context.Context, day time.Time) ([]domain.SearchTermStats, error) {
+	paginator := dynamodb.NewQueryPaginator(r.client, &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String(searchTermsDayIndex),
+		KeyConditionExpression: aws.String("#day = :day"),
+		ExpressionAttributeNames: map[string]string{
+			"#day": "day",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":day": &types.AttributeValueMemberS{Value: day.Format(dayLayout)},
+		},
+	})
+
+	var terms []domain.SearchTermStats
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query daily search terms: %w", err)
+		}
+
+		var counters []searchTermCounter
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &counters); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal daily search terms: %w", err)
+		}
+		for _, counter := range counters {
+			terms = append(terms, domain.SearchTermStats{
+				Term:        counter.Term,
+				Searches:    counter.Searches,
+				ZeroResults: counter.ZeroResults,
+			})
+		}
+	}
+	return terms, nil
+}
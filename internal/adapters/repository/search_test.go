@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchVariants(t *testing.T) {
+	assert.Equal(t, []string{"LAPTOP", "laptop", "Laptop"}, searchVariants("LAPTOP"))
+	assert.Equal(t, []string{"laptop", "Laptop"}, searchVariants("laptop"))
+	assert.Equal(t, []string{"Ñandú", "ñandú"}, searchVariants("Ñandú"))
+}
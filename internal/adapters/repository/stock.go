@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// AdjustStock adds delta to the product's stock in a single conditional
+// update, written with the outbox events of ctx. A decrement is
+// conditioned on enough stock being on hand, so concurrent adjustments can
+// never take it below zero, and the update on the product still being at
+// the version read, so the events carry the product it leaves behind.
+func (r *DynamoDBRepository) AdjustStock(ctx context.Context, product domain.Product, delta int64, now time.Time) (int64, error) {
+	updatedAt, err := attributevalue.Marshal(now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal updated_at: %w", err)
+	}
+
+	tenant := ports.TenantID(ctx)
+	update := stockAdjustment(r.tableName, tenant, product.ID, product.Version, delta, updatedAt)
+	err = r.write(ctx, types.TransactWriteItem{Update: update}, func(err error) error {
+		if err == nil {
+			return nil
+		}
+		var conditionFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionFailed) {
+			return stockConditionError(conditionFailed.Item, tenant, product.Version)
+		}
+		return fmt.Errorf("failed to adjust stock: %w", err)
+	})
+	if err != nil {
+		return 0, err
+	}
+	r.onWrite.call(ctx, tenant, product.ID)
+	return product.Stock + delta, nil
+}
+
+// stockConditionError tells from the old item why an adjustment failed: a
+// missing product, one changed since it was read, or one short of stock
+func stockConditionError(item map[string]types.AttributeValue, tenant string, version int64) error {
+	if item == nil || itemTenant(item) != tenant {
+		return domain.ErrNotFound
+	}
+	var current struct {
+		Version int64 `dynamodbav:"version"`
+	}
+	if err := attributevalue.UnmarshalMap(item, &current); err != nil || current.Version != version {
+		return domain.ErrConflict
+	}
+	return domain.ErrInsufficientStock
+}
+
+// stockAdjustment builds the conditional ADD for a stock adjustment of one
+// of tenant's products at version. Items without a stock attribute count as
+// having none.
+func stockAdjustment(tableName, tenant, id string, version, delta int64, updatedAt types.AttributeValue) *types.Update {
+	names := map[string]string{
+		"#pk":         partitionKeyAttribute,
+		"#stock":      "stock",
+		"#version":    "version",
+		"#updated_at": "updated_at",
+	}
+	values := map[string]types.AttributeValue{
+		":delta":      &types.AttributeValueMemberN{Value: strconv.FormatInt(delta, 10)},
+		":one":        &types.AttributeValueMemberN{Value: "1"},
+		":updated_at": updatedAt,
+	}
+	condition := "attribute_exists(#pk) AND " + tenantCondition(tenant, names, values)
+	versionCheck, versionValues := versionCondition(version)
+	condition += " AND " + versionCheck
+	maps.Copy(values, versionValues)
+	if delta < 0 {
+		condition += " AND #stock >= :required"
+		values[":required"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(-delta, 10)}
+	}
+
+	return &types.Update{
+		TableName:                           aws.String(tableName),
+		Key:                                 productKey(id),
+		UpdateExpression:                    aws.String("SET #updated_at = :updated_at ADD #stock :delta, #version :one"),
+		ConditionExpression:                 aws.String(condition),
+		ExpressionAttributeNames:            names,
+		ExpressionAttributeValues:           values,
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	}
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+type stubTransport struct {
+	status int
+	body   string
+}
+
+func (s stubTransport) Do(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: s.status,
+		Header:     http.Header{"Content-Type": []string{"application/x-amz-json-1.0"}},
+		Body:       io.NopCloser(strings.NewReader(s.body)),
+		Request:    req,
+	}, nil
+}
+
+func stubRepository(status int, body string) *DynamoDBRepository {
+	client := dynamodb.New(dynamodb.Options{
+		Region:      "us-east-1",
+		Credentials: aws.AnonymousCredentials{},
+		HTTPClient:  stubTransport{status: status, body: body},
+	})
+	return &DynamoDBRepository{client: client, tableName: "products"}
+}
+
+func TestStockAdjustment(t *testing.T) {
+	updatedAt := &types.AttributeValueMemberS{Value: "now"}
+
+	input := stockAdjustment("products", "", "1", 0, 5, updatedAt)
+	assert.Equal(t, "attribute_exists(#pk) AND attribute_not_exists(#tenant_id) AND attribute_not_exists(#version)", *input.ConditionExpression)
+	assert.Equal(t, &types.AttributeValueMemberN{Value: "5"}, input.ExpressionAttributeValues[":delta"])
+
+	input = stockAdjustment("products", "acme", "1", 2, -3, updatedAt)
+	assert.Equal(t, "attribute_exists(#pk) AND #tenant_id = :tenant_id AND #version = :expected_version AND #stock >= :required", *input.ConditionExpression)
+	assert.Equal(t, &types.AttributeValueMemberN{Value: "2"}, input.ExpressionAttributeValues[":expected_version"])
+	assert.Equal(t, &types.AttributeValueMemberS{Value: "acme"}, input.ExpressionAttributeValues[":tenant_id"])
+	assert.Equal(t, &types.AttributeValueMemberN{Value: "-3"}, input.ExpressionAttributeValues[":delta"])
+	assert.Equal(t, &types.AttributeValueMemberN{Value: "3"}, input.ExpressionAttributeValues[":required"])
+}
+
+func TestAdjustStock(t *testing.T) {
+	const conditionFailed = `{"__type":"com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException","message":"The conditional request failed"%s}`
+
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		stock   int64
+		wantErr error
+	}{
+		{"applied", http.StatusOK, `{}`, 7, nil},
+		{"missing product", http.StatusBadRequest, strings.Replace(conditionFailed, "%s", "", 1), 0, domain.ErrNotFound},
+		{"not enough stock", http.StatusBadRequest, strings.Replace(conditionFailed, "%s", `,"Item":{"id":{"S":"1"},"stock":{"N":"1"},"version":{"N":"2"}}`, 1), 0, domain.ErrInsufficientStock},
+		{"changed since read", http.StatusBadRequest, strings.Replace(conditionFailed, "%s", `,"Item":{"id":{"S":"1"},"stock":{"N":"9"},"version":{"N":"3"}}`, 1), 0, domain.ErrConflict},
+		{"other tenant's product", http.StatusBadRequest, strings.Replace(conditionFailed, "%s", `,"Item":{"id":{"S":"1"},"stock":{"N":"9"},"tenant_id":{"S":"acme"}}`, 1), 0, domain.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := stubRepository(tt.status, tt.body)
+			var written []string
+			repo.onWrite = func(ctx context.Context, tenant, id string) { written = append(written, id) }
+			stock, err := repo.AdjustStock(context.Background(), domain.Product{ID: "1", Stock: 9, Version: 2}, -2, time.Now())
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.stock, stock)
+			if tt.wantErr == nil {
+				assert.Equal(t, []string{"1"}, written, "caches are told about the adjustment")
+			} else {
+				assert.Empty(t, written)
+			}
+		})
+	}
+}
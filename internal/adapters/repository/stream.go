@@ -0,0 +1,385 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams"
+	streamtypes "github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// StreamsAPI is the subset of the DynamoDB Streams client the consumer uses
+type StreamsAPI interface {
+	DescribeStream(ctx context.Context, params *dynamodbstreams.DescribeStreamInput, optFns ...func(*dynamodbstreams.Options)) (*dynamodbstreams.DescribeStreamOutput, error)
+	GetShardIterator(ctx context.Context, params *dynamodbstreams.GetShardIteratorInput, optFns ...func(*dynamodbstreams.Options)) (*dynamodbstreams.GetShardIteratorOutput, error)
+	GetRecords(ctx context.Context, params *dynamodbstreams.GetRecordsInput, optFns ...func(*dynamodbstreams.Options)) (*dynamodbstreams.GetRecordsOutput, error)
+}
+
+// StreamConsumer forwards the changes recorded in the products table's
+// stream to an EventPublisher, so consumers learn of every write, including
+// TTL deletes and writes made outside the services. Delivery is at least
+// once: the event ID is the stream record's, the same on every delivery.
+type StreamConsumer struct {
+	client        StreamsAPI
+	streamARN     string
+	publisher     ports.EventPublisher
+	checkpoints   StreamCheckpointStore
+	interval      time.Duration
+	startPosition streamtypes.ShardIteratorType
+	logger        *slog.Logger
+
+	shards  map[string]*shardState
+	started bool
+}
+
+// shardState is where reading of one shard resumes
+type shardState struct {
+	parentID string
+	iterator *string
+	// resumeType and sequence position a new iterator when the last one
+	// expired or a publish failed; empty before the first record
+	resumeType streamtypes.ShardIteratorType
+	sequence   string
+	closed     bool
+}
+
+// NewStreamConsumer resumes shards from their checkpoint, and reads other
+// shards open at startup from startPosition, LATEST or TRIM_HORIZON, and
+// shards created later from their first record. Without checkpoints, nil,
+// every start begins at startPosition.
+func NewStreamConsumer(client StreamsAPI, streamARN string, publisher ports.EventPublisher, checkpoints StreamCheckpointStore, interval time.Duration, startPosition string, logger *slog.Logger) *StreamConsumer {
+	return &StreamConsumer{
+		client:        client,
+		streamARN:     streamARN,
+		publisher:     publisher,
+		checkpoints:   checkpoints,
+		interval:      interval,
+		startPosition: streamtypes.ShardIteratorType(startPosition),
+		logger:        logger,
+		shards:        map[string]*shardState{},
+	}
+}
+
+// Run polls the stream until ctx is done
+func (c *StreamConsumer) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		if err := c.Poll(ctx); err != nil && ctx.Err() == nil {
+			c.logger.ErrorContext(ctx, "failed to poll stream", "stream", c.streamARN, "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll publishes the records added to the stream since the last poll.
+// A shard is only read once its parent has been read to the end, so the
+// changes to a product are published in the order they were made.
+func (c *StreamConsumer) Poll(ctx context.Context) error {
+	if err := c.refreshShards(ctx); err != nil {
+		return err
+	}
+	for id, state := range c.shards {
+		if state.closed || !c.parentClosed(state) {
+			continue
+		}
+		if err := c.readShard(ctx, id, state); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refreshShards tracks the shards of the stream. Shards trimmed from the
+// stream are forgotten.
+func (c *StreamConsumer) refreshShards(ctx context.Context) error {
+	listed := map[string]bool{}
+	var startID *string
+	for {
+		result, err := c.client.DescribeStream(ctx, &dynamodbstreams.DescribeStreamInput{
+			StreamArn:             aws.String(c.streamARN),
+			ExclusiveStartShardId: startID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to describe stream: %w", err)
+		}
+		for _, shard := range result.StreamDescription.Shards {
+			id := aws.ToString(shard.ShardId)
+			listed[id] = true
+			if _, ok := c.shards[id]; ok {
+				continue
+			}
+			state := &shardState{parentID: aws.ToString(shard.ParentShardId)}
+			// Shards created after startup hold only changes not yet seen
+			if c.started {
+				state.resumeType = streamtypes.ShardIteratorTypeTrimHorizon
+			} else {
+				state.resumeType = c.startPosition
+			}
+			if err := c.resume(ctx, id, state); err != nil {
+				return err
+			}
+			c.shards[id] = state
+		}
+		startID = result.StreamDescription.LastEvaluatedShardId
+		if startID == nil {
+			break
+		}
+	}
+	for id := range c.shards {
+		if !listed[id] {
+			delete(c.shards, id)
+		}
+	}
+	c.started = true
+	return nil
+}
+
+// resume positions state after the shard's checkpoint, when it has one
+func (c *StreamConsumer) resume(ctx context.Context, shardID string, state *shardState) error {
+	if c.checkpoints == nil {
+		return nil
+	}
+	checkpoint, ok, err := c.checkpoints.LoadCheckpoint(ctx, c.streamARN, shardID)
+	if err != nil || !ok {
+		return err
+	}
+	state.closed = checkpoint.Closed
+	if checkpoint.Sequence != "" {
+		state.resumeType, state.sequence = streamtypes.ShardIteratorTypeAfterSequenceNumber, checkpoint.Sequence
+	}
+	return nil
+}
+
+// checkpoint saves how far the shard was published. A checkpoint that
+// fails to save is only logged: the next one covers it, and until then a
+// restart publishes some records again.
+func (c *StreamConsumer) checkpoint(ctx context.Context, shardID string, state *shardState) {
+	if c.checkpoints == nil {
+		return
+	}
+	checkpoint := StreamCheckpoint{Sequence: state.sequence, Closed: state.closed}
+	if err := c.checkpoints.SaveCheckpoint(ctx, c.streamARN, shardID, checkpoint); err != nil {
+		c.logger.WarnContext(ctx, "failed to save stream checkpoint", "shard", shardID, "error", err)
+	}
+}
+
+func (c *StreamConsumer) parentClosed(state *shardState) bool {
+	parent, ok := c.shards[state.parentID]
+	return !ok || parent.closed
+}
+
+// readShard publishes the shard's records until it is caught up. A record
+// that fails to publish is read again on the next poll.
+func (c *StreamConsumer) readShard(ctx context.Context, shardID string, state *shardState) error {
+	for {
+		if state.iterator == nil {
+			input := &dynamodbstreams.GetShardIteratorInput{
+				StreamArn:         aws.String(c.streamARN),
+				ShardId:           aws.String(shardID),
+				ShardIteratorType: state.resumeType,
+			}
+			if state.sequence != "" {
+				input.SequenceNumber = aws.String(state.sequence)
+			}
+			result, err := c.client.GetShardIterator(ctx, input)
+			var trimmed *streamtypes.TrimmedDataAccessException
+			if errors.As(err, &trimmed) {
+				// The resume point aged out of the stream; what is left
+				// starts at the trim horizon
+				c.logger.WarnContext(ctx, "stream records expired before they were published", "shard", shardID)
+				state.resumeType, state.sequence = streamtypes.ShardIteratorTypeTrimHorizon, ""
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to get iterator for shard %s: %w", shardID, err)
+			}
+			state.iterator = result.ShardIterator
+		}
+
+		result, err := c.client.GetRecords(ctx, &dynamodbstreams.GetRecordsInput{ShardIterator: state.iterator})
+		var expired *streamtypes.ExpiredIteratorException
+		if errors.As(err, &expired) {
+			state.iterator = nil
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read shard %s: %w", shardID, err)
+		}
+
+		for _, record := range result.Records {
+			sequence := aws.ToString(record.Dynamodb.SequenceNumber)
+			if err := c.publish(ctx, record); err != nil {
+				state.iterator = nil
+				state.resumeType, state.sequence = streamtypes.ShardIteratorTypeAtSequenceNumber, sequence
+				return err
+			}
+			state.resumeType, state.sequence = streamtypes.ShardIteratorTypeAfterSequenceNumber, sequence
+		}
+
+		state.iterator = result.NextShardIterator
+		if state.iterator == nil {
+			state.closed = true
+			c.checkpoint(ctx, shardID, state)
+			return nil
+		}
+		if len(result.Records) == 0 {
+			return nil
+		}
+		c.checkpoint(ctx, shardID, state)
+	}
+}
+
+func (c *StreamConsumer) publish(ctx context.Context, record streamtypes.Record) error {
+	event, err := streamEvent(record)
+	if errors.Is(err, errNotProduct) {
+		return nil
+	}
+	if err != nil {
+		// A record that cannot be decoded never will be; skip it rather
+		// than stall the shard
+		c.logger.ErrorContext(ctx, "skipping undecodable stream record", "event_id", aws.ToString(record.EventID), "error", err)
+		return nil
+	}
+	if err := c.publisher.Publish(ctx, event); err != nil {
+		return fmt.Errorf("failed to publish stream record %s: %w", event.ID, err)
+	}
+	c.logger.DebugContext(ctx, "stream record published", "event_id", event.ID, "type", event.Type, "product_id", event.ProductID)
+	return nil
+}
+
+// errNotProduct marks the stream records of the reviews and categories
+// sharing the products table, which publish no product event
+var errNotProduct = errors.New("stream record is not of a product")
+
+// streamEvent turns a stream record into the event the services would
+// publish for the same change
+func streamEvent(record streamtypes.Record) (domain.ProductEvent, error) {
+	if record.Dynamodb == nil {
+		return domain.ProductEvent{}, errors.New("stream record has no change")
+	}
+	change := record.Dynamodb
+	pk, hasPK := change.Keys[partitionKeyAttribute].(*streamtypes.AttributeValueMemberS)
+	sk, hasSK := change.Keys[sortKeyAttribute].(*streamtypes.AttributeValueMemberS)
+	if !hasPK || !hasSK {
+		return domain.ProductEvent{}, errors.New("stream record has no item key")
+	}
+	productID, ok := productIDFromKey(pk.Value, sk.Value)
+	if !ok {
+		return domain.ProductEvent{}, errNotProduct
+	}
+	occurredAt := time.Now().UTC()
+	if change.ApproximateCreationDateTime != nil {
+		occurredAt = change.ApproximateCreationDateTime.UTC()
+	}
+
+	event := domain.NewProductEvent(domain.EventProductDeleted, productID, nil, occurredAt)
+	if id := aws.ToString(record.EventID); id != "" {
+		event.ID = id
+	}
+	if record.EventName == streamtypes.OperationTypeRemove {
+		if !removedByTTL(record) {
+			return event, nil
+		}
+		// The product reached its expires_at and DynamoDB's TTL deleted it
+		event.Type = domain.EventProductExpired
+		if change.OldImage != nil {
+			product, err := decodeProduct(streamItem(change.OldImage))
+			if err != nil {
+				return domain.ProductEvent{}, err
+			}
+			event.Product = &product
+		}
+		return event, nil
+	}
+	if change.NewImage == nil {
+		return domain.ProductEvent{}, errors.New("stream record has no new image; the stream must include new images")
+	}
+	product, err := decodeProduct(streamItem(change.NewImage))
+	if err != nil {
+		return domain.ProductEvent{}, err
+	}
+	event.Type = domain.EventProductUpdated
+	if record.EventName == streamtypes.OperationTypeInsert {
+		event.Type = domain.EventProductCreated
+	}
+	event.Product = &product
+	return event, nil
+}
+
+// removedByTTL reports whether a removal was made by the table's TTL rather
+// than by a client, which DynamoDB marks with its own service identity
+func removedByTTL(record streamtypes.Record) bool {
+	identity := record.UserIdentity
+	return identity != nil && aws.ToString(identity.Type) == "Service" &&
+		aws.ToString(identity.PrincipalId) == "dynamodb.amazonaws.com"
+}
+
+// streamItem converts a stream image to the attribute values of the
+// DynamoDB client, which share their wire format
+func streamItem(image map[string]streamtypes.AttributeValue) map[string]types.AttributeValue {
+	item := make(map[string]types.AttributeValue, len(image))
+	for name, value := range image {
+		item[name] = streamValue(value)
+	}
+	return item
+}
+
+func streamValue(value streamtypes.AttributeValue) types.AttributeValue {
+	switch v := value.(type) {
+	case *streamtypes.AttributeValueMemberS:
+		return &types.AttributeValueMemberS{Value: v.Value}
+	case *streamtypes.AttributeValueMemberN:
+		return &types.AttributeValueMemberN{Value: v.Value}
+	case *streamtypes.AttributeValueMemberB:
+		return &types.AttributeValueMemberB{Value: v.Value}
+	case *streamtypes.AttributeValueMemberBOOL:
+		return &types.AttributeValueMemberBOOL{Value: v.Value}
+	case *streamtypes.AttributeValueMemberNULL:
+		return &types.AttributeValueMemberNULL{Value: v.Value}
+	case *streamtypes.AttributeValueMemberSS:
+		return &types.AttributeValueMemberSS{Value: v.Value}
+	case *streamtypes.AttributeValueMemberNS:
+		return &types.AttributeValueMemberNS{Value: v.Value}
+	case *streamtypes.AttributeValueMemberBS:
+		return &types.AttributeValueMemberBS{Value: v.Value}
+	case *streamtypes.AttributeValueMemberL:
+		list := make([]types.AttributeValue, len(v.Value))
+		for i, element := range v.Value {
+			list[i] = streamValue(element)
+		}
+		return &types.AttributeValueMemberL{Value: list}
+	case *streamtypes.AttributeValueMemberM:
+		return &types.AttributeValueMemberM{Value: streamItem(v.Value)}
+	}
+	return &types.AttributeValueMemberNULL{Value: true}
+}
+
+// LatestStreamARN returns the stream of tableName, which must have one
+// with new images
+func LatestStreamARN(ctx context.Context, client *dynamodb.Client, tableName string) (string, error) {
+	table, err := describeTable(ctx, client, tableName)
+	if err != nil {
+		return "", err
+	}
+	spec := table.StreamSpecification
+	if table.LatestStreamArn == nil || spec == nil || !aws.ToBool(spec.StreamEnabled) {
+		return "", fmt.Errorf("table %q has no stream enabled", tableName)
+	}
+	if spec.StreamViewType != types.StreamViewTypeNewImage && spec.StreamViewType != types.StreamViewTypeNewAndOldImages {
+		return "", fmt.Errorf("stream of table %q does not include new images", tableName)
+	}
+	return aws.ToString(table.LatestStreamArn), nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// checkpointRetention keeps a checkpoint past the 24 hours its stream keeps
+// records, after which DynamoDB TTL removes the checkpoints of trimmed shards
+const checkpointRetention = 48 * time.Hour
+
+// StreamCheckpoint is how far a shard has been published
+type StreamCheckpoint struct {
+	// Sequence is the last record read; empty before the first one
+	Sequence string
+	// Closed is set once the shard was read to its end
+	Closed bool
+}
+
+// StreamCheckpointStore keeps the checkpoints of a stream's shards, so a
+// restarted consumer resumes where the last one stopped
+type StreamCheckpointStore interface {
+	LoadCheckpoint(ctx context.Context, streamARN, shardID string) (StreamCheckpoint, bool, error)
+	SaveCheckpoint(ctx context.Context, streamARN, shardID string, checkpoint StreamCheckpoint) error
+}
+
+// DynamoDBStreamCheckpoints keeps checkpoints as items of the locks table,
+// named after the stream and shard, next to the job leases
+type DynamoDBStreamCheckpoints struct {
+	client    *dynamodb.Client
+	tableName string
+	now       func() time.Time
+}
+
+func NewDynamoDBStreamCheckpoints(client *dynamodb.Client, tableName string) *DynamoDBStreamCheckpoints {
+	return &DynamoDBStreamCheckpoints{client: client, tableName: tableName, now: time.Now}
+}
+
+func (s *DynamoDBStreamCheckpoints) LoadCheckpoint(ctx context.Context, streamARN, shardID string) (StreamCheckpoint, bool, error) {
+	result, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(s.tableName),
+		Key:            map[string]types.AttributeValue{"name": &types.AttributeValueMemberS{Value: checkpointName(streamARN, shardID)}},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return StreamCheckpoint{}, false, fmt.Errorf("failed to load checkpoint of shard %s: %w", shardID, err)
+	}
+	if result.Item == nil {
+		return StreamCheckpoint{}, false, nil
+	}
+	var checkpoint StreamCheckpoint
+	if sequence, ok := result.Item["sequence"].(*types.AttributeValueMemberS); ok {
+		checkpoint.Sequence = sequence.Value
+	}
+	if closed, ok := result.Item["closed"].(*types.AttributeValueMemberBOOL); ok {
+		checkpoint.Closed = closed.Value
+	}
+	return checkpoint, true, nil
+}
+
+func (s *DynamoDBStreamCheckpoints) SaveCheckpoint(ctx context.Context, streamARN, shardID string, checkpoint StreamCheckpoint) error {
+	item := map[string]types.AttributeValue{
+		"name":       &types.AttributeValueMemberS{Value: checkpointName(streamARN, shardID)},
+		"closed":     &types.AttributeValueMemberBOOL{Value: checkpoint.Closed},
+		"expires_at": &types.AttributeValueMemberN{Value: strconv.FormatInt(s.now().Add(checkpointRetention).Unix(), 10)},
+	}
+	if checkpoint.Sequence != "" {
+		item["sequence"] = &types.AttributeValueMemberS{Value: checkpoint.Sequence}
+	}
+	_, err := s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save checkpoint of shard %s: %w", shardID, err)
+	}
+	return nil
+}
+
+func checkpointName(streamARN, shardID string) string {
+	return "stream-checkpoint#" + streamARN + "#" + shardID
+}
@@ -0,0 +1,294 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams"
+	streamtypes "github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// fakeStream serves the records of its shards, one record per GetRecords
+// call. Iterators are "shard/position"; closed shards end after their last
+// record.
+type fakeStream struct {
+	shards  []streamtypes.Shard
+	records map[string][]streamtypes.Record
+	closed  map[string]bool
+}
+
+func (f *fakeStream) DescribeStream(ctx context.Context, params *dynamodbstreams.DescribeStreamInput, optFns ...func(*dynamodbstreams.Options)) (*dynamodbstreams.DescribeStreamOutput, error) {
+	return &dynamodbstreams.DescribeStreamOutput{StreamDescription: &streamtypes.StreamDescription{Shards: f.shards}}, nil
+}
+
+func (f *fakeStream) GetShardIterator(ctx context.Context, params *dynamodbstreams.GetShardIteratorInput, optFns ...func(*dynamodbstreams.Options)) (*dynamodbstreams.GetShardIteratorOutput, error) {
+	shard := aws.ToString(params.ShardId)
+	position := 0
+	switch params.ShardIteratorType {
+	case streamtypes.ShardIteratorTypeLatest:
+		position = len(f.records[shard])
+	case streamtypes.ShardIteratorTypeAtSequenceNumber, streamtypes.ShardIteratorTypeAfterSequenceNumber:
+		for i, record := range f.records[shard] {
+			if aws.ToString(record.Dynamodb.SequenceNumber) == aws.ToString(params.SequenceNumber) {
+				position = i
+			}
+		}
+		if params.ShardIteratorType == streamtypes.ShardIteratorTypeAfterSequenceNumber {
+			position++
+		}
+	}
+	return &dynamodbstreams.GetShardIteratorOutput{ShardIterator: aws.String(iterator(shard, position))}, nil
+}
+
+func (f *fakeStream) GetRecords(ctx context.Context, params *dynamodbstreams.GetRecordsInput, optFns ...func(*dynamodbstreams.Options)) (*dynamodbstreams.GetRecordsOutput, error) {
+	var shard string
+	var position int
+	for id := range f.records {
+		for i := 0; i <= len(f.records[id]); i++ {
+			if iterator(id, i) == aws.ToString(params.ShardIterator) {
+				shard, position = id, i
+			}
+		}
+	}
+	records := f.records[shard]
+	if position == len(records) {
+		if f.closed[shard] {
+			return &dynamodbstreams.GetRecordsOutput{}, nil
+		}
+		return &dynamodbstreams.GetRecordsOutput{NextShardIterator: params.ShardIterator}, nil
+	}
+	return &dynamodbstreams.GetRecordsOutput{
+		Records:           records[position : position+1],
+		NextShardIterator: aws.String(iterator(shard, position+1)),
+	}, nil
+}
+
+func iterator(shard string, position int) string {
+	return shard + "/" + string(rune('0'+position))
+}
+
+// failingPublisher records events and fails the ones whose product is in fail
+type failingPublisher struct {
+	events []domain.ProductEvent
+	fail   map[string]bool
+}
+
+func (p *failingPublisher) Publish(ctx context.Context, event domain.ProductEvent) error {
+	if p.fail[event.ProductID] {
+		return errors.New("topic unavailable")
+	}
+	p.events = append(p.events, event)
+	return nil
+}
+
+func streamProductKey(id string) map[string]streamtypes.AttributeValue {
+	return map[string]streamtypes.AttributeValue{
+		"pk": &streamtypes.AttributeValueMemberS{Value: "PRODUCT#" + id},
+		"sk": &streamtypes.AttributeValueMemberS{Value: "PRODUCT"},
+	}
+}
+
+func streamRecord(sequence, operation, id string) streamtypes.Record {
+	change := &streamtypes.StreamRecord{
+		SequenceNumber: aws.String(sequence),
+		Keys:           streamProductKey(id),
+	}
+	if operation != string(streamtypes.OperationTypeRemove) {
+		change.NewImage = map[string]streamtypes.AttributeValue{
+			"id":   &streamtypes.AttributeValueMemberS{Value: id},
+			"name": &streamtypes.AttributeValueMemberS{Value: "Laptop"},
+		}
+	}
+	return streamtypes.Record{
+		EventID:   aws.String("event-" + sequence),
+		EventName: streamtypes.OperationType(operation),
+		Dynamodb:  change,
+	}
+}
+
+func TestStreamEvent(t *testing.T) {
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	record := streamtypes.Record{
+		EventID:   aws.String("event-1"),
+		EventName: streamtypes.OperationTypeModify,
+		Dynamodb: &streamtypes.StreamRecord{
+			ApproximateCreationDateTime: aws.Time(created),
+			Keys:                        streamProductKey("p1"),
+			NewImage: map[string]streamtypes.AttributeValue{
+				"id":       &streamtypes.AttributeValueMemberS{Value: "p1"},
+				"name":     &streamtypes.AttributeValueMemberS{Value: "Laptop"},
+				"price":    &streamtypes.AttributeValueMemberN{Value: "999.5"},
+				"currency": &streamtypes.AttributeValueMemberS{Value: "EUR"},
+				"stock":    &streamtypes.AttributeValueMemberN{Value: "3"},
+				"tags": &streamtypes.AttributeValueMemberL{Value: []streamtypes.AttributeValue{
+					&streamtypes.AttributeValueMemberS{Value: "electronics"},
+				}},
+				"images": &streamtypes.AttributeValueMemberL{Value: []streamtypes.AttributeValue{
+					&streamtypes.AttributeValueMemberM{Value: map[string]streamtypes.AttributeValue{
+						"url": &streamtypes.AttributeValueMemberS{Value: "https://cdn.example.com/p1.jpg"},
+					}},
+				}},
+			},
+		},
+	}
+
+	event, err := streamEvent(record)
+	require.NoError(t, err)
+	assert.Equal(t, "event-1", event.ID)
+	assert.Equal(t, domain.EventProductUpdated, event.Type)
+	assert.Equal(t, "p1", event.ProductID)
+	assert.Equal(t, created, event.OccurredAt)
+	require.NotNil(t, event.Product)
+	assert.Equal(t, "Laptop", event.Product.Name)
+	assert.Equal(t, "EUR", event.Product.Price.Currency)
+	assert.Equal(t, 999.5, event.Product.Price.Decimal())
+	assert.Equal(t, int64(3), event.Product.Stock)
+	assert.Equal(t, []string{"electronics"}, event.Product.Tags)
+	require.Len(t, event.Product.Images, 1)
+	assert.Equal(t, "https://cdn.example.com/p1.jpg", event.Product.Images[0].URL)
+
+	event, err = streamEvent(streamRecord("2", "INSERT", "p1"))
+	require.NoError(t, err)
+	assert.Equal(t, domain.EventProductCreated, event.Type)
+
+	event, err = streamEvent(streamRecord("2", "REMOVE", "p1"))
+	require.NoError(t, err)
+	assert.Equal(t, domain.EventProductDeleted, event.Type)
+	assert.Equal(t, "p1", event.ProductID)
+	assert.Nil(t, event.Product)
+
+	expired := streamRecord("2", "REMOVE", "p1")
+	expired.UserIdentity = &streamtypes.Identity{Type: aws.String("Service"), PrincipalId: aws.String("dynamodb.amazonaws.com")}
+	expired.Dynamodb.OldImage = map[string]streamtypes.AttributeValue{
+		"id":         &streamtypes.AttributeValueMemberS{Value: "p1"},
+		"name":       &streamtypes.AttributeValueMemberS{Value: "Flash Sale Hat"},
+		"expires_at": &streamtypes.AttributeValueMemberN{Value: "1772366400"},
+	}
+	event, err = streamEvent(expired)
+	require.NoError(t, err)
+	assert.Equal(t, domain.EventProductExpired, event.Type)
+	require.NotNil(t, event.Product, "an expiry carries the product as it was")
+	assert.Equal(t, "Flash Sale Hat", event.Product.Name)
+
+	keysOnly := streamRecord("3", "MODIFY", "p1")
+	keysOnly.Dynamodb.NewImage = nil
+	_, err = streamEvent(keysOnly)
+	assert.Error(t, err)
+
+	// Reviews share the table, and its stream, with their product
+	review := streamRecord("4", "INSERT", "p1")
+	review.Dynamodb.Keys["sk"] = &streamtypes.AttributeValueMemberS{Value: "REVIEW#r1"}
+	_, err = streamEvent(review)
+	assert.ErrorIs(t, err, errNotProduct)
+}
+
+func TestStreamConsumer_Poll(t *testing.T) {
+	stream := &fakeStream{
+		shards: []streamtypes.Shard{
+			{ShardId: aws.String("parent")},
+			{ShardId: aws.String("child"), ParentShardId: aws.String("parent")},
+		},
+		records: map[string][]streamtypes.Record{
+			"parent": {streamRecord("1", "INSERT", "p1"), streamRecord("2", "MODIFY", "p2")},
+			"child":  {streamRecord("3", "REMOVE", "p1")},
+		},
+		closed: map[string]bool{"parent": true},
+	}
+	publisher := &failingPublisher{fail: map[string]bool{"p2": true}}
+	consumer := NewStreamConsumer(stream, "arn:stream", publisher, nil, time.Second, "TRIM_HORIZON", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	// The child waits for its parent, which stops at the failed record
+	assert.Error(t, consumer.Poll(ctx))
+	require.Len(t, publisher.events, 1)
+	assert.Equal(t, "event-1", publisher.events[0].ID)
+
+	// The failed record is retried, then the child is read
+	publisher.fail = nil
+	require.NoError(t, consumer.Poll(ctx))
+	require.NoError(t, consumer.Poll(ctx))
+	ids := []string{}
+	for _, event := range publisher.events {
+		ids = append(ids, event.ID)
+	}
+	assert.Equal(t, []string{"event-1", "event-2", "event-3"}, ids)
+	assert.Equal(t, domain.EventProductDeleted, publisher.events[2].Type)
+
+	// New records of an open shard are picked up by the next poll
+	stream.records["child"] = append(stream.records["child"], streamRecord("4", "INSERT", "p3"))
+	require.NoError(t, consumer.Poll(ctx))
+	require.Len(t, publisher.events, 4)
+	assert.Equal(t, "p3", publisher.events[3].ProductID)
+}
+
+func TestStreamConsumer_Latest(t *testing.T) {
+	stream := &fakeStream{
+		shards:  []streamtypes.Shard{{ShardId: aws.String("shard")}},
+		records: map[string][]streamtypes.Record{"shard": {streamRecord("1", "INSERT", "p1")}},
+	}
+	publisher := &failingPublisher{}
+	consumer := NewStreamConsumer(stream, "arn:stream", publisher, nil, time.Second, "LATEST", slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	require.NoError(t, consumer.Poll(context.Background()))
+	assert.Empty(t, publisher.events)
+
+	// Shards created after startup are read from the start
+	stream.shards = append(stream.shards, streamtypes.Shard{ShardId: aws.String("new")})
+	stream.records["new"] = []streamtypes.Record{streamRecord("2", "INSERT", "p2")}
+	require.NoError(t, consumer.Poll(context.Background()))
+	require.Len(t, publisher.events, 1)
+	assert.Equal(t, "p2", publisher.events[0].ProductID)
+}
+
+// memoryCheckpoints keeps checkpoints by shard
+type memoryCheckpoints map[string]StreamCheckpoint
+
+func (m memoryCheckpoints) LoadCheckpoint(ctx context.Context, streamARN, shardID string) (StreamCheckpoint, bool, error) {
+	checkpoint, ok := m[shardID]
+	return checkpoint, ok, nil
+}
+
+func (m memoryCheckpoints) SaveCheckpoint(ctx context.Context, streamARN, shardID string, checkpoint StreamCheckpoint) error {
+	m[shardID] = checkpoint
+	return nil
+}
+
+func TestStreamConsumer_Checkpoints(t *testing.T) {
+	stream := &fakeStream{
+		shards: []streamtypes.Shard{
+			{ShardId: aws.String("parent")},
+			{ShardId: aws.String("child"), ParentShardId: aws.String("parent")},
+		},
+		records: map[string][]streamtypes.Record{
+			"parent": {streamRecord("1", "INSERT", "p1")},
+			"child":  {streamRecord("2", "MODIFY", "p1")},
+		},
+		closed: map[string]bool{"parent": true},
+	}
+	checkpoints := memoryCheckpoints{}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	publisher := &failingPublisher{}
+	consumer := NewStreamConsumer(stream, "arn:stream", publisher, checkpoints, time.Second, "TRIM_HORIZON", logger)
+	// The child may only be reached on the poll after its parent closed
+	require.NoError(t, consumer.Poll(context.Background()))
+	require.NoError(t, consumer.Poll(context.Background()))
+	require.Len(t, publisher.events, 2)
+	assert.Equal(t, StreamCheckpoint{Sequence: "1", Closed: true}, checkpoints["parent"])
+	assert.Equal(t, StreamCheckpoint{Sequence: "2"}, checkpoints["child"])
+
+	// A restart publishes only what was added since
+	stream.records["child"] = append(stream.records["child"], streamRecord("3", "MODIFY", "p2"))
+	publisher = &failingPublisher{}
+	consumer = NewStreamConsumer(stream, "arn:stream", publisher, checkpoints, time.Second, "TRIM_HORIZON", logger)
+	require.NoError(t, consumer.Poll(context.Background()))
+	require.Len(t, publisher.events, 1)
+	assert.Equal(t, "p2", publisher.events[0].ProductID)
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// tenantAttribute holds the owning tenant of a product. Products of the
+// default tenant, including every one written before multi-tenancy, have
+// none.
+const tenantAttribute = "tenant_id"
+
+// tenantCondition matches the items of tenant, registering its placeholders
+// in names and values
+func tenantCondition(tenant string, names map[string]string, values map[string]types.AttributeValue) string {
+	names["#tenant_id"] = tenantAttribute
+	if tenant == "" {
+		return "attribute_not_exists(#tenant_id)"
+	}
+	values[":tenant_id"] = &types.AttributeValueMemberS{Value: tenant}
+	return "#tenant_id = :tenant_id"
+}
+
+// itemTenant returns the tenant stored on a raw item
+func itemTenant(item map[string]types.AttributeValue) string {
+	if tenant, ok := item[tenantAttribute].(*types.AttributeValueMemberS); ok {
+		return tenant.Value
+	}
+	return ""
+}
@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+const (
+	// backgroundReserve is the share of the burst kept free for interactive
+	// requests; background work waits until the bucket is above it
+	backgroundReserve = 0.2
+	// throttleBackoff is the multiplicative rate decrease on throttling
+	throttleBackoff = 0.5
+	// recoverySteps is how many successful calls it takes to climb back
+	// from the minimum rate to the maximum
+	recoverySteps = 100
+)
+
+// AdaptiveThrottle is a client-side token bucket measured in capacity units.
+// Calls are admitted while the bucket is not in debt, then charged the
+// capacity DynamoDB reports they consumed. The refill rate backs off when
+// DynamoDB throttles and creeps back up while calls succeed (AIMD), so bulk
+// work slows down before it starves interactive traffic.
+type AdaptiveThrottle struct {
+	mu      sync.Mutex
+	rate    float64
+	minRate float64
+	maxRate float64
+	tokens  float64
+	last    time.Time
+	now     func() time.Time
+}
+
+// NewAdaptiveThrottle starts at initialRate capacity units per second and
+// never exceeds maxRate
+func NewAdaptiveThrottle(initialRate, maxRate float64) *AdaptiveThrottle {
+	if maxRate < initialRate {
+		maxRate = initialRate
+	}
+	return &AdaptiveThrottle{
+		rate:    initialRate,
+		minRate: math.Max(initialRate/10, 1),
+		maxRate: maxRate,
+		tokens:  initialRate,
+		last:    time.Now(),
+		now:     time.Now,
+	}
+}
+
+// APIOption installs the throttle on a DynamoDB client, e.g. through
+// dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) { o.APIOptions =
+// append(o.APIOptions, throttle.APIOption) })
+func (t *AdaptiveThrottle) APIOption(stack *middleware.Stack) error {
+	if err := stack.Initialize.Add(middleware.InitializeMiddlewareFunc("ReturnConsumedCapacity", requestConsumedCapacity), middleware.After); err != nil {
+		return err
+	}
+	// Runs after the retry middleware so every attempt is paced and observed
+	return stack.Finalize.Insert(middleware.FinalizeMiddlewareFunc("AdaptiveThrottle", t.handleFinalize), "Retry", middleware.After)
+}
+
+func (t *AdaptiveThrottle) handleFinalize(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+	if err := t.wait(ctx, ports.BackgroundPriority(ctx)); err != nil {
+		return middleware.FinalizeOutput{}, middleware.Metadata{}, err
+	}
+
+	out, metadata, err := next.HandleFinalize(ctx, in)
+	t.observe(consumedCapacity(out.Result), isThrottle(err))
+	return out, metadata, err
+}
+
+// wait blocks until the bucket can admit a call of the given priority
+func (t *AdaptiveThrottle) wait(ctx context.Context, background bool) error {
+	for {
+		t.mu.Lock()
+		t.refill()
+		threshold := 0.0
+		if background {
+			threshold = t.rate * backgroundReserve
+		}
+		if t.tokens >= threshold {
+			t.mu.Unlock()
+			return nil
+		}
+		delay := time.Duration((threshold - t.tokens) / t.rate * float64(time.Second))
+		t.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// observe charges a finished call and adapts the refill rate
+func (t *AdaptiveThrottle) observe(consumed float64, throttled bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.refill()
+	t.tokens -= consumed
+	if throttled {
+		t.rate = math.Max(t.minRate, t.rate*throttleBackoff)
+		t.tokens = math.Min(t.tokens, 0)
+		return
+	}
+	t.rate = math.Min(t.maxRate, t.rate+(t.maxRate-t.minRate)/recoverySteps)
+}
+
+// refill adds tokens for the time elapsed, capped at one second of burst
+func (t *AdaptiveThrottle) refill() {
+	now := t.now()
+	t.tokens = math.Min(t.rate, t.tokens+now.Sub(t.last).Seconds()*t.rate)
+	t.last = now
+}
+
+// SetRates replaces the rates the throttle was created with; the current
+// rate is kept within the new bounds
+func (t *AdaptiveThrottle) SetRates(initialRate, maxRate float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if maxRate < initialRate {
+		maxRate = initialRate
+	}
+	t.minRate = math.Max(initialRate/10, 1)
+	t.maxRate = maxRate
+	t.rate = math.Min(t.maxRate, math.Max(t.minRate, t.rate))
+}
+
+// Rate returns the current refill rate in capacity units per second
+func (t *AdaptiveThrottle) Rate() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.rate
+}
+
+func requestConsumedCapacity(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+	switch params := in.Parameters.(type) {
+	case *dynamodb.GetItemInput:
+		params.ReturnConsumedCapacity = types.ReturnConsumedCapacityTotal
+	case *dynamodb.PutItemInput:
+		params.ReturnConsumedCapacity = types.ReturnConsumedCapacityTotal
+	case *dynamodb.UpdateItemInput:
+		params.ReturnConsumedCapacity = types.ReturnConsumedCapacityTotal
+	case *dynamodb.DeleteItemInput:
+		params.ReturnConsumedCapacity = types.ReturnConsumedCapacityTotal
+	case *dynamodb.QueryInput:
+		params.ReturnConsumedCapacity = types.ReturnConsumedCapacityTotal
+	case *dynamodb.ScanInput:
+		params.ReturnConsumedCapacity = types.ReturnConsumedCapacityTotal
+	case *dynamodb.BatchGetItemInput:
+		params.ReturnConsumedCapacity = types.ReturnConsumedCapacityTotal
+	case *dynamodb.BatchWriteItemInput:
+		params.ReturnConsumedCapacity = types.ReturnConsumedCapacityTotal
+	case *dynamodb.TransactWriteItemsInput:
+		params.ReturnConsumedCapacity = types.ReturnConsumedCapacityTotal
+	case *dynamodb.ExecuteStatementInput:
+		params.ReturnConsumedCapacity = types.ReturnConsumedCapacityTotal
+	}
+	return next.HandleInitialize(ctx, in)
+}
+
+func consumedCapacity(result interface{}) float64 {
+	var capacities []types.ConsumedCapacity
+	switch output := result.(type) {
+	case *dynamodb.GetItemOutput:
+		capacities = appendCapacity(capacities, output.ConsumedCapacity)
+	case *dynamodb.PutItemOutput:
+		capacities = appendCapacity(capacities, output.ConsumedCapacity)
+	case *dynamodb.UpdateItemOutput:
+		capacities = appendCapacity(capacities, output.ConsumedCapacity)
+	case *dynamodb.DeleteItemOutput:
+		capacities = appendCapacity(capacities, output.ConsumedCapacity)
+	case *dynamodb.QueryOutput:
+		capacities = appendCapacity(capacities, output.ConsumedCapacity)
+	case *dynamodb.ScanOutput:
+		capacities = appendCapacity(capacities, output.ConsumedCapacity)
+	case *dynamodb.ExecuteStatementOutput:
+		capacities = appendCapacity(capacities, output.ConsumedCapacity)
+	case *dynamodb.BatchGetItemOutput:
+		capacities = output.ConsumedCapacity
+	case *dynamodb.BatchWriteItemOutput:
+		capacities = output.ConsumedCapacity
+	case *dynamodb.TransactWriteItemsOutput:
+		capacities = output.ConsumedCapacity
+	}
+
+	total := 0.0
+	for _, capacity := range capacities {
+		if capacity.CapacityUnits != nil {
+			total += *capacity.CapacityUnits
+		}
+	}
+	return total
+}
+
+func appendCapacity(capacities []types.ConsumedCapacity, capacity *types.ConsumedCapacity) []types.ConsumedCapacity {
+	if capacity == nil {
+		return capacities
+	}
+	return append(capacities, *capacity)
+}
+
+func isThrottle(err error) bool {
+	if err == nil {
+		return false
+	}
+	var provisioned *types.ProvisionedThroughputExceededException
+	var requestLimit *types.RequestLimitExceeded
+	return errors.As(err, &provisioned) || errors.As(err, &requestLimit) || retry.IsErrorThrottles(retry.DefaultThrottles).IsErrorThrottle(err).Bool()
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+func TestAdaptiveThrottle_AIMD(t *testing.T) {
+	throttle := NewAdaptiveThrottle(100, 200)
+	clock := time.Now()
+	throttle.now = func() time.Time { return clock }
+	throttle.last = clock
+
+	throttle.observe(5, true)
+	assert.Equal(t, 50.0, throttle.Rate(), "throttling halves the rate")
+
+	throttle.observe(5, true)
+	throttle.observe(5, true)
+	throttle.observe(5, true)
+	assert.Equal(t, 10.0, throttle.Rate(), "rate never drops below the floor")
+
+	for i := 0; i < 1000; i++ {
+		throttle.observe(0, false)
+	}
+	assert.Equal(t, 200.0, throttle.Rate(), "successes recover up to the max rate")
+}
+
+func TestAdaptiveThrottle_Wait(t *testing.T) {
+	throttle := NewAdaptiveThrottle(10, 10)
+	clock := time.Now()
+	throttle.now = func() time.Time { return clock }
+	throttle.last = clock
+
+	// A full bucket admits interactive and background work
+	assert.NoError(t, throttle.wait(context.Background(), false))
+	assert.NoError(t, throttle.wait(context.Background(), true))
+
+	// Leave the bucket just above zero: interactive calls pass, background
+	// calls must wait for the reserve to refill
+	throttle.observe(9, false)
+	assert.NoError(t, throttle.wait(context.Background(), false))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, throttle.wait(ctx, true), context.Canceled)
+}
+
+func TestAdaptiveThrottle_SetRates(t *testing.T) {
+	throttle := NewAdaptiveThrottle(100, 200)
+
+	throttle.SetRates(20, 50)
+	assert.Equal(t, 50.0, throttle.Rate(), "the rate is capped at the new max rate")
+
+	throttle.SetRates(80, 0)
+	assert.Equal(t, 50.0, throttle.Rate(), "a rate within the new bounds is kept")
+	for i := 0; i < 1000; i++ {
+		throttle.observe(0, false)
+	}
+	assert.Equal(t, 80.0, throttle.Rate(), "the max rate is never below the initial rate")
+}
+
+func TestConsumedCapacity(t *testing.T) {
+	assert.Equal(t, 2.5, consumedCapacity(&dynamodb.QueryOutput{
+		ConsumedCapacity: &types.ConsumedCapacity{CapacityUnits: aws.Float64(2.5)},
+	}))
+	assert.Equal(t, 3.0, consumedCapacity(&dynamodb.BatchWriteItemOutput{
+		ConsumedCapacity: []types.ConsumedCapacity{
+			{CapacityUnits: aws.Float64(1)},
+			{CapacityUnits: aws.Float64(2)},
+		},
+	}))
+	assert.Equal(t, 0.0, consumedCapacity(nil))
+}
+
+func TestAdaptiveThrottle_BackgroundReserve(t *testing.T) {
+	throttle := NewAdaptiveThrottle(10, 10)
+	client := dynamodb.New(dynamodb.Options{
+		Region:      "us-east-1",
+		Credentials: aws.AnonymousCredentials{},
+		HTTPClient:  stubTransport{status: http.StatusOK, body: `{}`},
+		APIOptions:  []func(*middleware.Stack) error{throttle.APIOption},
+	})
+	repo := &DynamoDBRepository{client: client, tableName: "products"}
+
+	// Drain the bucket: it refills at 10 units a second and background work
+	// waits for the 2 units of the reserve, 200ms
+	throttle.observe(10, false)
+
+	ctx, cancel := context.WithTimeout(ports.WithBackgroundPriority(context.Background()), 50*time.Millisecond)
+	defer cancel()
+	_, err := repo.GetByID(ctx, "prod-1")
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "background work leaves the reserve alone")
+
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = repo.GetByID(ctx, "prod-1")
+	assert.ErrorIs(t, err, domain.ErrNotFound, "interactive requests use the reserve")
+
+	start := time.Now()
+	_, err = repo.GetByID(ports.WithBackgroundPriority(context.Background()), "prod-1")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond, "background work waits for the reserve to refill")
+}
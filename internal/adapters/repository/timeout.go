@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/smithy-go/middleware"
+)
+
+// TimeoutAPIOption bounds every call of a DynamoDB client, retries and
+// client-side throttling included, to timeout. A caller's earlier deadline
+// still applies, and a call whose context is already done fails without
+// being sent. Install it through dynamodb.NewFromConfig(cfg, func(o
+// *dynamodb.Options) { o.APIOptions = append(o.APIOptions,
+// TimeoutAPIOption(timeout)) }).
+func TimeoutAPIOption(timeout time.Duration) func(*middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		// Added first so the whole call runs under the deadline
+		return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("OperationTimeout",
+			func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+				if err := ctx.Err(); err != nil {
+					return middleware.InitializeOutput{}, middleware.Metadata{}, err
+				}
+				ctx, cancel := context.WithTimeout(ctx, timeout)
+				defer cancel()
+				return next.HandleInitialize(ctx, in)
+			}), middleware.Before)
+	}
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// hangingTransport never answers: it waits for the request to be canceled,
+// counting the requests it was sent
+type hangingTransport struct {
+	requests *atomic.Int32
+}
+
+func (h hangingTransport) Do(req *http.Request) (*http.Response, error) {
+	h.requests.Add(1)
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+}
+
+func timeoutRepository(timeout time.Duration) (*DynamoDBRepository, *atomic.Int32) {
+	requests := &atomic.Int32{}
+	client := dynamodb.New(dynamodb.Options{
+		Region:      "us-east-1",
+		Credentials: aws.AnonymousCredentials{},
+		HTTPClient:  hangingTransport{requests},
+		APIOptions:  []func(*middleware.Stack) error{TimeoutAPIOption(timeout)},
+	})
+	return NewDynamoDBRepository(client, "products", WithOutbox("product_outbox")), requests
+}
+
+func TestTimeoutAPIOption(t *testing.T) {
+	product := domain.Product{ID: "p1", Name: "Laptop", Price: domain.Money{Amount: 99900, Currency: "USD"}, Version: 1}
+	operations := map[string]func(context.Context, *DynamoDBRepository) error{
+		"GetByID": func(ctx context.Context, r *DynamoDBRepository) error {
+			_, err := r.GetByID(ctx, product.ID)
+			return err
+		},
+		"GetByIDs": func(ctx context.Context, r *DynamoDBRepository) error {
+			_, err := r.GetByIDs(ctx, []string{product.ID})
+			return err
+		},
+		"Save":   func(ctx context.Context, r *DynamoDBRepository) error { return r.Save(ctx, product) },
+		"Update": func(ctx context.Context, r *DynamoDBRepository) error { return r.Update(ctx, product) },
+		"Delete": func(ctx context.Context, r *DynamoDBRepository) error {
+			return r.Delete(ctx, product.ID, product.Version)
+		},
+		"ListWithFilters": func(ctx context.Context, r *DynamoDBRepository) error {
+			_, err := r.ListWithFilters(ctx, ports.ProductFilters{SortBy: "created_at", SortOrder: "desc", Limit: 10})
+			return err
+		},
+	}
+
+	for name, operation := range operations {
+		t.Run(name, func(t *testing.T) {
+			// A call that outlives the timeout is abandoned
+			repo, requests := timeoutRepository(20 * time.Millisecond)
+			start := time.Now()
+			assert.ErrorIs(t, operation(context.Background(), repo), context.DeadlineExceeded)
+			assert.Less(t, time.Since(start), time.Second)
+			assert.NotZero(t, requests.Load())
+
+			// A call whose context is already canceled is never sent
+			repo, requests = timeoutRepository(time.Minute)
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			assert.ErrorIs(t, operation(ctx, repo), context.Canceled)
+			assert.Zero(t, requests.Load())
+		})
+	}
+}
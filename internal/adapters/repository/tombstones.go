@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// tombstoneDeletedIndex orders each tenant's tombstones by deletion time
+// for the change feed. Tombstones are partitioned like the product indexes,
+// and deleted_at is written fixed width so it sorts as a string.
+// Tombstones saved before the index carry no partition and are left out.
+const tombstoneDeletedIndex = "deleted_at-index"
+
+// DynamoDBTombstoneRepository stores one item per deleted product ID, kept
+// out of the products table so scans and counts never see them
+type DynamoDBTombstoneRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+func NewDynamoDBTombstoneRepository(client *dynamodb.Client, tableName string) *DynamoDBTombstoneRepository {
+	return &DynamoDBTombstoneRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+func (r *DynamoDBTombstoneRepository) Save(ctx context.Context, tombstone domain.Tombstone) error {
+	item, err := attributevalue.MarshalMap(tombstone)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tombstone: %w", err)
+	}
+	item[indexPartitionAttribute] = &types.AttributeValueMemberS{Value: tenantPartitionPrefix(tombstone.TenantID)}
+	item["deleted_at"] = &types.AttributeValueMemberS{Value: tombstone.DeletedAt.UTC().Format(outboxTimeLayout)}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save tombstone: %w", err)
+	}
+	return nil
+}
+
+func (r *DynamoDBTombstoneRepository) Get(ctx context.Context, id string) (domain.Tombstone, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return domain.Tombstone{}, fmt.Errorf("failed to get tombstone: %w", err)
+	}
+	if result.Item == nil {
+		return domain.Tombstone{}, domain.ErrNotFound
+	}
+
+	var tombstone domain.Tombstone
+	if err := attributevalue.UnmarshalMap(result.Item, &tombstone); err != nil {
+		return domain.Tombstone{}, fmt.Errorf("failed to unmarshal tombstone: %w", err)
+	}
+	return tombstone, nil
+}
+
+func (r *DynamoDBTombstoneRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete tombstone: %w", err)
+	}
+	return nil
+}
+
+// ListDeleted queries the deletion index from the time of the position,
+// skipping the tombstones up to the position itself. The index leaves ties
+// on deleted_at in no particular order, so the reads go on past limit
+// tombstones until the time of the last one kept has been read in full.
+func (r *DynamoDBTombstoneRepository) ListDeleted(ctx context.Context, after ports.SortKey, until time.Time, limit int) ([]domain.Tombstone, error) {
+	from, err := time.Parse(time.RFC3339Nano, after.Value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deletion position %q: %w", after.Value, err)
+	}
+	if from.After(until) {
+		return []domain.Tombstone{}, nil
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String(tombstoneDeletedIndex),
+		KeyConditionExpression: aws.String("#pk = :pk AND #deleted_at BETWEEN :from AND :until"),
+		ExpressionAttributeNames: map[string]string{
+			"#pk":         indexPartitionAttribute,
+			"#deleted_at": "deleted_at",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":    &types.AttributeValueMemberS{Value: tenantPartitionPrefix(ports.TenantID(ctx))},
+			":from":  &types.AttributeValueMemberS{Value: from.UTC().Format(outboxTimeLayout)},
+			":until": &types.AttributeValueMemberS{Value: until.UTC().Format(outboxTimeLayout)},
+		},
+		Limit: aws.Int32(int32(limit)),
+	}
+
+	tombstones := []domain.Tombstone{}
+	var lastRead time.Time
+	for {
+		result, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query tombstones: %w", err)
+		}
+		for _, item := range result.Items {
+			var tombstone domain.Tombstone
+			if err := attributevalue.UnmarshalMap(item, &tombstone); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal tombstone: %w", err)
+			}
+			lastRead = tombstone.DeletedAt
+			if compareTombstones(tombstone, domain.Tombstone{ID: after.ID, DeletedAt: from}) > 0 {
+				tombstones = append(tombstones, tombstone)
+			}
+		}
+		slices.SortFunc(tombstones, compareTombstones)
+		if len(result.LastEvaluatedKey) == 0 {
+			break
+		}
+		if len(tombstones) >= limit && lastRead.After(tombstones[limit-1].DeletedAt) {
+			break
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+	if len(tombstones) > limit {
+		tombstones = tombstones[:limit]
+	}
+	return tombstones, nil
+}
+
+// compareTombstones orders tombstones by deletion time and then ID
+func compareTombstones(a, b domain.Tombstone) int {
+	if c := a.DeletedAt.Compare(b.DeletedAt); c != 0 {
+		return c
+	}
+	return strings.Compare(a.ID, b.ID)
+}
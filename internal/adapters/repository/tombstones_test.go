@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+func recordingTombstones(body string) (*DynamoDBTombstoneRepository, *[]string) {
+	var operations, bodies []string
+	client := dynamodb.New(dynamodb.Options{
+		Region:      "us-east-1",
+		Credentials: aws.AnonymousCredentials{},
+		HTTPClient:  recordingTransport{stubTransport{status: http.StatusOK, body: body}, &operations, &bodies, &sync.Mutex{}},
+	})
+	return NewDynamoDBTombstoneRepository(client, "tombstones"), &bodies
+}
+
+func TestTombstoneRepository_SaveIndexesDeletion(t *testing.T) {
+	repo, bodies := recordingTombstones(`{}`)
+	deletedAt := time.Date(2024, 3, 1, 13, 0, 0, 0, time.FixedZone("CET", 3600))
+	require.NoError(t, repo.Save(context.Background(), domain.Tombstone{ID: "1", DeletedAt: deletedAt, TenantID: "acme"}))
+
+	assert.Contains(t, (*bodies)[0], `"gsi_pk":{"S":"acme#PRODUCT"}`)
+	assert.Contains(t, (*bodies)[0], `"deleted_at":{"S":"2024-03-01T12:00:00.000000000Z"}`)
+}
+
+func TestTombstoneRepository_ListDeleted(t *testing.T) {
+	// The index returns the tie at 12:01 out of ID order
+	const page = `{"Count":4,"Items":[
+		{"id":{"S":"a"},"deleted_at":{"S":"2024-03-01T12:00:00.000000000Z"}},
+		{"id":{"S":"d"},"deleted_at":{"S":"2024-03-01T12:01:00.000000000Z"}},
+		{"id":{"S":"c"},"deleted_at":{"S":"2024-03-01T12:01:00.000000000Z"}},
+		{"id":{"S":"b"},"deleted_at":{"S":"2024-03-01T12:00:00.000000000Z"}}]}`
+	repo, bodies := recordingTombstones(page)
+	after := ports.SortKey{Value: "2024-03-01T12:00:00Z", ID: "a"}
+	until := time.Date(2024, 3, 1, 13, 0, 0, 0, time.UTC)
+
+	tombstones, err := repo.ListDeleted(ports.WithTenant(context.Background(), "acme"), after, until, 2)
+	require.NoError(t, err)
+
+	assert.Contains(t, (*bodies)[0], `"IndexName":"deleted_at-index"`)
+	assert.Contains(t, (*bodies)[0], `"KeyConditionExpression":"#pk = :pk AND #deleted_at BETWEEN :from AND :until"`)
+	assert.Contains(t, (*bodies)[0], `":pk":{"S":"acme#PRODUCT"}`)
+	assert.Contains(t, (*bodies)[0], `":from":{"S":"2024-03-01T12:00:00.000000000Z"}`)
+	ids := make([]string, len(tombstones))
+	for i, tombstone := range tombstones {
+		ids[i] = tombstone.ID
+	}
+	// a is the position itself, so it was already sent
+	assert.Equal(t, []string{"b", "c"}, ids)
+
+	tombstones, err = repo.ListDeleted(context.Background(), ports.SortKey{Value: "2024-03-01T14:00:00Z"}, until, 2)
+	require.NoError(t, err)
+	assert.Empty(t, tombstones)
+	assert.Len(t, *bodies, 1, "a position after until reads nothing")
+}
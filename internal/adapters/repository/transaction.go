@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// maxTransactItems is the most writes DynamoDB accepts in one transaction
+const maxTransactItems = 100
+
+// DynamoDBUnitOfWork commits the writes made inside Do as a single
+// TransactWriteItems call. Product saves, updates and deletes, with their
+// reservations and outbox events, and category count adjustments take
+// part; every other write runs as soon as it is made.
+type DynamoDBUnitOfWork struct {
+	client *dynamodb.Client
+}
+
+func NewDynamoDBUnitOfWork(client *dynamodb.Client) *DynamoDBUnitOfWork {
+	return &DynamoDBUnitOfWork{client: client}
+}
+
+// transaction collects the writes of a unit of work in the order they
+// were made
+type transaction struct {
+	mu     sync.Mutex
+	items  []types.TransactWriteItem
+	groups []transactGroup
+}
+
+// transactGroup is the writes of one repository call, which the call
+// would have run as its own transaction. failed turns the cancellation of
+// the group, given only its own reasons, into the error the call would
+// have returned.
+type transactGroup struct {
+	size   int
+	failed func(canceled *types.TransactionCanceledException) error
+}
+
+type transactionKey struct{}
+
+// activeTransaction returns the transaction of the unit of work ctx was
+// given by, nil outside of one
+func activeTransaction(ctx context.Context) *transaction {
+	tx, _ := ctx.Value(transactionKey{}).(*transaction)
+	return tx
+}
+
+// withoutTransaction returns a context whose writes run on their own even
+// inside a unit of work, and so are followed by their hooks right away
+func withoutTransaction(ctx context.Context) context.Context {
+	return ports.WithCommitHooks(context.WithValue(ctx, transactionKey{}, (*transaction)(nil)), nil)
+}
+
+// enlist adds the writes of one repository call
+func (t *transaction) enlist(items []types.TransactWriteItem, failed func(*types.TransactionCanceledException) error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.items = append(t.items, items...)
+	t.groups = append(t.groups, transactGroup{size: len(items), failed: failed})
+}
+
+// Do runs fn and commits the writes it enlisted, then runs what fn
+// deferred with ports.AfterCommit. Nested calls join the outer unit of
+// work.
+func (u *DynamoDBUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if activeTransaction(ctx) != nil {
+		return fn(ctx)
+	}
+	tx := &transaction{}
+	hooks := &ports.CommitHooks{}
+	if err := fn(ports.WithCommitHooks(context.WithValue(ctx, transactionKey{}, tx), hooks)); err != nil {
+		return err
+	}
+	if err := u.commit(ctx, tx); err != nil {
+		return err
+	}
+	hooks.Run()
+	return nil
+}
+
+func (u *DynamoDBUnitOfWork) commit(ctx context.Context, tx *transaction) error {
+	switch {
+	case len(tx.items) == 0:
+		return nil
+	case len(tx.items) > maxTransactItems:
+		return fmt.Errorf("unit of work has %d writes, more than the %d of a transaction", len(tx.items), maxTransactItems)
+	}
+	_, err := u.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: tx.items})
+	if err == nil {
+		return nil
+	}
+
+	var canceled *types.TransactionCanceledException
+	if errors.As(err, &canceled) {
+		// Report the first group whose own writes caused the cancellation
+		start := 0
+		for _, group := range tx.groups {
+			end := min(start+group.size, len(canceled.CancellationReasons))
+			if start < end && causedCancellation(canceled.CancellationReasons[start:end]) {
+				return group.failed(&types.TransactionCanceledException{
+					Message:             canceled.Message,
+					CancellationReasons: canceled.CancellationReasons[start:end],
+				})
+			}
+			start += group.size
+		}
+	}
+	return fmt.Errorf("failed to commit unit of work: %w", err)
+}
+
+// causedCancellation reports whether any of the reasons is a failure
+// rather than the "None" of writes canceled because of others
+func causedCancellation(reasons []types.CancellationReason) bool {
+	for _, reason := range reasons {
+		if code := aws.ToString(reason.Code); code != "" && code != "None" {
+			return true
+		}
+	}
+	return false
+}
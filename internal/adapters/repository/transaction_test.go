@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+func TestUnitOfWork_CommitsTogether(t *testing.T) {
+	product := domain.Product{ID: "prod-1", Name: "Laptop", Price: domain.Money{Amount: 99900, Currency: "USD"}, CategoryID: "cat-1"}
+	event := domain.NewProductEvent(domain.EventProductCreated, product.ID, &product, time.Now())
+
+	repo, operations, bodies := recordingRepository(http.StatusOK, `{}`)
+	categories := NewDynamoDBCategoryRepository(repo.client, "products")
+	err := NewDynamoDBUnitOfWork(repo.client).Do(context.Background(), func(ctx context.Context) error {
+		if err := repo.Save(ports.WithOutboxEvent(ctx, event), product); err != nil {
+			return err
+		}
+		return categories.AdjustProductCount(ctx, "cat-1", 1)
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"TransactWriteItems"}, *operations)
+	assert.Equal(t, 2, strings.Count((*bodies)[0], `"Put":{`), "the product and its outbox event")
+	assert.Contains(t, (*bodies)[0], `"TableName":"product_outbox"`)
+	assert.Contains(t, (*bodies)[0], `"UpdateExpression":"ADD #product_count :delta"`)
+}
+
+func TestUnitOfWork_RollsBackOnError(t *testing.T) {
+	repo, operations, _ := recordingRepository(http.StatusOK, `{}`)
+	failure := errors.New("screening failed")
+	err := NewDynamoDBUnitOfWork(repo.client).Do(context.Background(), func(ctx context.Context) error {
+		if err := repo.Save(ctx, domain.Product{ID: "prod-1"}); err != nil {
+			return err
+		}
+		return failure
+	})
+
+	assert.ErrorIs(t, err, failure)
+	assert.Empty(t, *operations, "nothing is written when fn fails")
+}
+
+func TestUnitOfWork_WriteHookAfterCommit(t *testing.T) {
+	var written []string
+	hook := func(ctx context.Context, tenant, id string) { written = append(written, id) }
+
+	repo, operations, _ := recordingRepository(http.StatusOK, `{}`)
+	repo.onWrite = hook
+	err := NewDynamoDBUnitOfWork(repo.client).Do(context.Background(), func(ctx context.Context) error {
+		require.NoError(t, repo.Save(ctx, domain.Product{ID: "prod-1"}))
+		assert.Empty(t, written, "the write is only enlisted")
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"TransactWriteItems"}, *operations)
+	assert.Equal(t, []string{"prod-1"}, written)
+
+	written = nil
+	const canceled = `{"__type":"com.amazonaws.dynamodb.v20120810#TransactionCanceledException","message":"Transaction cancelled","CancellationReasons":[{"Code":"ConditionalCheckFailed"}]}`
+	repo, _, _ = recordingRepository(http.StatusBadRequest, canceled)
+	repo.onWrite = hook
+	err = NewDynamoDBUnitOfWork(repo.client).Do(context.Background(), func(ctx context.Context) error {
+		return repo.Save(ctx, domain.Product{ID: "prod-1"})
+	})
+	assert.ErrorIs(t, err, domain.ErrDuplicate)
+	assert.Empty(t, written, "nothing was written")
+}
+
+func TestUnitOfWork_CancellationErrors(t *testing.T) {
+	const canceled = `{"__type":"com.amazonaws.dynamodb.v20120810#TransactionCanceledException","message":"Transaction cancelled","CancellationReasons":[{"Code":"%s"},{"Code":"%s"}]}`
+	tests := []struct {
+		name    string
+		body    string
+		wantErr error
+	}{
+		{"product exists", fmt.Sprintf(canceled, "ConditionalCheckFailed", "None"), domain.ErrDuplicate},
+		{"category missing", fmt.Sprintf(canceled, "None", "ConditionalCheckFailed"), domain.ErrCategoryNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, _, _ := recordingRepository(http.StatusBadRequest, tt.body)
+			categories := NewDynamoDBCategoryRepository(repo.client, "products")
+			err := NewDynamoDBUnitOfWork(repo.client).Do(context.Background(), func(ctx context.Context) error {
+				if err := repo.Save(ctx, domain.Product{ID: "prod-1", CategoryID: "cat-1"}); err != nil {
+					return err
+				}
+				return categories.AdjustProductCount(ctx, "cat-1", 1)
+			})
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestCategorySave_KeepsProductCount(t *testing.T) {
+	repo, operations, bodies := recordingRepository(http.StatusOK, `{}`)
+	categories := NewDynamoDBCategoryRepository(repo.client, "products")
+	require.NoError(t, categories.Save(context.Background(), domain.Category{ID: "cat-1", Name: "Laptops", ProductCount: 7}))
+
+	assert.Equal(t, []string{"UpdateItem"}, *operations)
+	assert.Contains(t, (*bodies)[0], `#name = :name`)
+	assert.NotContains(t, (*bodies)[0], "product_count")
+}
@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// uniqueKeyItem reserves one SKU or barcode of a tenant for a product. It
+// is written and removed in the same transaction as the product, so two
+// products can never hold the same value.
+type uniqueKeyItem struct {
+	Key       string `dynamodbav:"id"`
+	ProductID string `dynamodbav:"product_id"`
+	// ExpiresAt follows the product's, so TTL frees the value when it
+	// purges the product
+	ExpiresAt *time.Time `dynamodbav:"expires_at,omitempty,unixtime"`
+}
+
+// uniqueWrite claims or releases a reservation alongside a product write
+type uniqueWrite struct {
+	field string
+	value string
+	item  types.TransactWriteItem
+}
+
+// WithUniqueKeys enforces unique SKUs and barcodes per tenant through
+// reservation items in tableName
+func WithUniqueKeys(tableName string) Option {
+	return func(r *DynamoDBRepository) {
+		r.uniqueTable = tableName
+	}
+}
+
+// uniqueKey is the reservation key of a value of field in tenant
+func uniqueKey(tenant, field, value string) string {
+	return tenant + "#" + field + "#" + value
+}
+
+// uniqueWrites claims every unique value after holds and releases those
+// before held that after no longer does. Claims are written again on every
+// write so their expiry follows the product's. Each write only succeeds
+// while the reservation is free or already the product's own.
+func (r *DynamoDBRepository) uniqueWrites(before, after *domain.Product) ([]uniqueWrite, error) {
+	if r.uniqueTable == "" {
+		return nil, nil
+	}
+
+	var writes []uniqueWrite
+	claims := map[string]string{}
+	if after != nil {
+		claims = after.UniqueValues()
+	}
+	for _, field := range sortedFields(claims) {
+		item, err := attributevalue.MarshalMap(uniqueKeyItem{
+			Key:       uniqueKey(after.TenantID, field, claims[field]),
+			ProductID: after.ID,
+			ExpiresAt: after.ExpiresAt,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s reservation: %w", field, err)
+		}
+		writes = append(writes, uniqueWrite{field: field, value: claims[field], item: types.TransactWriteItem{Put: &types.Put{
+			TableName:                 aws.String(r.uniqueTable),
+			Item:                      item,
+			ConditionExpression:       aws.String(ownReservation),
+			ExpressionAttributeNames:  ownReservationNames(),
+			ExpressionAttributeValues: ownReservationValues(after.ID),
+		}}})
+	}
+
+	if before == nil {
+		return writes, nil
+	}
+	held := before.UniqueValues()
+	for _, field := range sortedFields(held) {
+		if claims[field] == held[field] {
+			continue
+		}
+		writes = append(writes, uniqueWrite{field: field, value: held[field], item: types.TransactWriteItem{Delete: &types.Delete{
+			TableName: aws.String(r.uniqueTable),
+			Key: map[string]types.AttributeValue{
+				"id": &types.AttributeValueMemberS{Value: uniqueKey(before.TenantID, field, held[field])},
+			},
+			ConditionExpression:       aws.String(ownReservation),
+			ExpressionAttributeNames:  ownReservationNames(),
+			ExpressionAttributeValues: ownReservationValues(before.ID),
+		}}})
+	}
+	return writes, nil
+}
+
+// ownReservation matches a reservation that is free or held by :product_id
+const ownReservation = "attribute_not_exists(#id) OR #product_id = :product_id"
+
+func ownReservationNames() map[string]string {
+	return map[string]string{"#id": "id", "#product_id": "product_id"}
+}
+
+func ownReservationValues(productID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{":product_id": &types.AttributeValueMemberS{Value: productID}}
+}
+
+// sortedFields orders the fields of values so transactions are built
+// deterministically
+func sortedFields(values map[string]string) []string {
+	fields := make([]string, 0, len(values))
+	for field := range values {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// storedUniqueValues reads the tenant and unique values currently stored for
+// a product, so a write can release the ones it drops. It returns nil when
+// the product does not exist.
+func (r *DynamoDBRepository) storedUniqueValues(ctx context.Context, id string) (*domain.Product, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:            aws.String(r.tableName),
+		Key:                  productKey(id),
+		ProjectionExpression: aws.String("#id, #tenant_id, #sku, #barcode"),
+		ExpressionAttributeNames: map[string]string{
+			"#id":        "id",
+			"#tenant_id": tenantAttribute,
+			"#sku":       domain.FieldSKU,
+			"#barcode":   domain.FieldBarcode,
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read product identifiers: %w", err)
+	}
+	if result.Item == nil {
+		return nil, nil
+	}
+	var stored domain.Product
+	if err := attributevalue.UnmarshalMap(result.Item, &stored); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal product identifiers: %w", err)
+	}
+	return &stored, nil
+}
+
+// unchangedIdentifiers is a condition that the product still holds the SKU
+// and barcode of stored, registering its placeholders in names and values
+func unchangedIdentifiers(stored *domain.Product, names map[string]string, values map[string]types.AttributeValue) string {
+	condition := ""
+	for _, field := range []string{domain.FieldSKU, domain.FieldBarcode} {
+		if condition != "" {
+			condition += " AND "
+		}
+		names["#"+field] = field
+		value, ok := stored.UniqueValues()[field]
+		if !ok {
+			condition += "attribute_not_exists(#" + field + ")"
+			continue
+		}
+		values[":"+field] = &types.AttributeValueMemberS{Value: value}
+		condition += "#" + field + " = :" + field
+	}
+	return condition
+}
+
+// GetBySKU follows the SKU's reservation to its product
+func (r *DynamoDBRepository) GetBySKU(ctx context.Context, sku string) (domain.Product, error) {
+	if r.uniqueTable == "" {
+		return domain.Product{}, domain.ErrNotFound
+	}
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.uniqueTable),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: uniqueKey(ports.TenantID(ctx), domain.FieldSKU, sku)},
+		},
+		ConsistentRead: aws.Bool(ports.ConsistentRead(ctx)),
+	})
+	if err != nil {
+		return domain.Product{}, fmt.Errorf("failed to get sku reservation: %w", err)
+	}
+	if result.Item == nil {
+		return domain.Product{}, domain.ErrNotFound
+	}
+	var reservation uniqueKeyItem
+	if err := attributevalue.UnmarshalMap(result.Item, &reservation); err != nil {
+		return domain.Product{}, fmt.Errorf("failed to unmarshal sku reservation: %w", err)
+	}
+
+	product, err := r.GetByID(ctx, reservation.ProductID)
+	if err != nil {
+		return domain.Product{}, err
+	}
+	// TTL removes an expired product and its reservations independently,
+	// so a reservation may briefly outlive its product
+	if product.SKU != sku {
+		return domain.Product{}, domain.ErrNotFound
+	}
+	return product, nil
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+func TestSave_ReservesUniqueValues(t *testing.T) {
+	product := domain.Product{ID: "prod-1", TenantID: "acme", Name: "Laptop", Price: domain.Money{Amount: 99900, Currency: "USD"}, SKU: "LAP-1", Barcode: "4006381333931"}
+
+	repo, operations, bodies := recordingRepository(http.StatusOK, `{}`)
+	WithUniqueKeys("product_unique_keys")(repo)
+	require.NoError(t, repo.Save(context.Background(), product))
+
+	assert.Equal(t, []string{"TransactWriteItems"}, *operations)
+	assert.Contains(t, (*bodies)[0], `"TableName":"product_unique_keys"`)
+	assert.Contains(t, (*bodies)[0], `"id":{"S":"acme#sku#LAP-1"}`)
+	assert.Contains(t, (*bodies)[0], `"id":{"S":"acme#barcode#4006381333931"}`)
+}
+
+func TestSave_DuplicateUniqueValue(t *testing.T) {
+	// Reservations follow the product in field order: barcode, then sku
+	const canceled = `{"__type":"com.amazonaws.dynamodb.v20120810#TransactionCanceledException","message":"Transaction cancelled","CancellationReasons":[{"Code":"None"},{"Code":"None"},{"Code":"ConditionalCheckFailed"}]}`
+	product := domain.Product{ID: "prod-1", Name: "Laptop", Price: domain.Money{Amount: 99900, Currency: "USD"}, SKU: "LAP-1", Barcode: "4006381333931"}
+
+	repo, _, _ := recordingRepository(http.StatusBadRequest, canceled)
+	WithUniqueKeys("product_unique_keys")(repo)
+	err := repo.Save(context.Background(), product)
+
+	var duplicate *domain.DuplicateError
+	require.ErrorAs(t, err, &duplicate)
+	assert.Equal(t, domain.FieldSKU, duplicate.Field)
+	assert.Equal(t, "LAP-1", duplicate.Value)
+}
+
+func TestUniqueWrites_ReleasesChangedValues(t *testing.T) {
+	repo := &DynamoDBRepository{uniqueTable: "product_unique_keys"}
+	before := &domain.Product{ID: "prod-1", TenantID: "acme", SKU: "OLD-1", Barcode: "4006381333931"}
+	after := &domain.Product{ID: "prod-1", TenantID: "acme", SKU: "NEW-1", Barcode: "4006381333931"}
+
+	writes, err := repo.uniqueWrites(before, after)
+	require.NoError(t, err)
+	require.Len(t, writes, 3)
+	assert.NotNil(t, writes[0].item.Put)
+	assert.NotNil(t, writes[1].item.Put)
+	require.NotNil(t, writes[2].item.Delete)
+	assert.Equal(t, &types.AttributeValueMemberS{Value: "acme#sku#OLD-1"}, writes[2].item.Delete.Key["id"])
+	assert.Equal(t, ownReservation, aws.ToString(writes[2].item.Delete.ConditionExpression))
+
+	writes, err = (&DynamoDBRepository{}).uniqueWrites(before, after)
+	require.NoError(t, err)
+	assert.Empty(t, writes)
+}
+
+func TestUnchangedIdentifiers(t *testing.T) {
+	names := map[string]string{}
+	values := map[string]types.AttributeValue{}
+	condition := unchangedIdentifiers(&domain.Product{SKU: "LAP-1"}, names, values)
+
+	assert.Equal(t, "#sku = :sku AND attribute_not_exists(#barcode)", condition)
+	assert.Equal(t, map[string]string{"#sku": "sku", "#barcode": "barcode"}, names)
+	assert.Equal(t, &types.AttributeValueMemberS{Value: "LAP-1"}, values[":sku"])
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+const (
+	viewsDayIndex = "day-index"
+	dayLayout     = "2006-01-02"
+	// trendingKey is the product_id under which the latest rollup is stored
+	trendingKey = "#TRENDING"
+	trendingDay = "latest"
+)
+
+// DynamoDBViewRepository keeps one counter item per product and day, keyed
+// by product_id and day, with a day-index GSI for rollups
+type DynamoDBViewRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+func NewDynamoDBViewRepository(client *dynamodb.Client, tableName string) *DynamoDBViewRepository {
+	return &DynamoDBViewRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+type viewCounter struct {
+	ProductID string `dynamodbav:"product_id"`
+	Day       string `dynamodbav:"day"`
+	Views     int64  `dynamodbav:"views"`
+}
+
+type trendingItem struct {
+	ProductID   string                `dynamodbav:"product_id"`
+	Day         string                `dynamodbav:"day"`
+	GeneratedAt time.Time             `dynamodbav:"generated_at"`
+	WindowDays  int                   `dynamodbav:"window_days"`
+	Items       []domain.ProductViews `dynamodbav:"items"`
+}
+
+// IncrementViews atomically adds one view to the product's counter for day
+func (r *DynamoDBViewRepository) IncrementViews(ctx context.Context, productID string, day time.Time) error {
+	_, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"product_id": &types.AttributeValueMemberS{Value: productID},
+			"day":        &types.AttributeValueMemberS{Value: day.Format(dayLayout)},
+		},
+		UpdateExpression: aws.String("ADD #views :one"),
+		ExpressionAttributeNames: map[string]string{
+			"#views": "views",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":one": &types.AttributeValueMemberN{Value: "1"},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to increment views: %w", err)
+	}
+	return nil
+}
+
+// DailyViews returns every product's counter for day
+func (r *DynamoDBViewRepository) DailyViews(ctx context.Context, day time.Time) ([]domain.ProductViews, error) {
+	paginator := dynamodb.NewQueryPaginator(r.client, &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String(viewsDayIndex),
+		KeyConditionExpression: aws.String("#day = :day"),
+		ExpressionAttributeNames: map[string]string{
+			"#day": "day",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":day": &types.AttributeValueMemberS{Value: day.Format(dayLayout)},
+		},
+	})
+
+	var views []domain.ProductViews
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query daily views: %w", err)
+		}
+
+		var counters []viewCounter
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &counters); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal daily views: %w", err)
+		}
+		for _, counter := range counters {
+			views = append(views, domain.ProductViews{ProductID: counter.ProductID, Views: counter.Views})
+		}
+	}
+	return views, nil
+}
+
+func (r *DynamoDBViewRepository) SaveTrending(ctx context.Context, snapshot domain.TrendingSnapshot) error {
+	item, err := attributevalue.MarshalMap(trendingItem{
+		ProductID:   trendingKey,
+		Day:         trendingDay,
+		GeneratedAt: snapshot.GeneratedAt,
+		WindowDays:  snapshot.WindowDays,
+		Items:       snapshot.Items,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal trending snapshot: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save trending snapshot: %w", err)
+	}
+	return nil
+}
+
+func (r *DynamoDBViewRepository) LatestTrending(ctx context.Context) (domain.TrendingSnapshot, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"product_id": &types.AttributeValueMemberS{Value: trendingKey},
+			"day":        &types.AttributeValueMemberS{Value: trendingDay},
+		},
+	})
+	if err != nil {
+		return domain.TrendingSnapshot{}, fmt.Errorf("failed to get trending snapshot: %w", err)
+	}
+	if result.Item == nil {
+		return domain.TrendingSnapshot{}, domain.ErrNotFound
+	}
+
+	var item trendingItem
+	if err := attributevalue.UnmarshalMap(result.Item, &item); err != nil {
+		return domain.TrendingSnapshot{}, fmt.Errorf("failed to unmarshal trending snapshot: %w", err)
+	}
+	return domain.TrendingSnapshot{
+		GeneratedAt: item.GeneratedAt,
+		WindowDays:  item.WindowDays,
+		Items:       item.Items,
+	}, nil
+}
@@ -0,0 +1,230 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// signingService is the SigV4 service name of Amazon OpenSearch Service
+const signingService = "es"
+
+// OpenSearchRepository runs relevance-ranked searches against an OpenSearch
+// index of products and keeps the index up to date. Requests are signed with SigV4 when AWS credentials
+// are configured, or use the basic auth credentials embedded in the URL.
+type OpenSearchRepository struct {
+	client   *http.Client
+	endpoint *url.URL
+	index    string
+	awsCfg   *aws.Config
+	signer   *v4.Signer
+	now      func() time.Time
+}
+
+// NewOpenSearchRepository searches index on the cluster at endpoint. A nil
+// awsCfg sends requests unsigned.
+func NewOpenSearchRepository(endpoint, index string, awsCfg *aws.Config, timeout time.Duration) (*OpenSearchRepository, error) {
+	parsed, err := url.Parse(endpoint)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid OpenSearch URL %q", endpoint)
+	}
+	if parsed.User != nil {
+		// Basic auth replaces signing
+		awsCfg = nil
+	}
+	return &OpenSearchRepository{
+		client:   &http.Client{Timeout: timeout},
+		endpoint: parsed,
+		index:    index,
+		awsCfg:   awsCfg,
+		signer:   v4.NewSigner(),
+		now:      time.Now,
+	}, nil
+}
+
+type searchResponse struct {
+	Hits struct {
+		Hits []struct {
+			Score     float64             `json:"_score"`
+			Source    domain.Product      `json:"_source"`
+			Highlight map[string][]string `json:"highlight"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// statusError is a response outside 2xx
+type statusError struct {
+	status int
+	body   []byte
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("OpenSearch returned %d: %s", e.status, e.body)
+}
+
+func hasStatus(err error, status int) bool {
+	var statusErr *statusError
+	return errors.As(err, &statusErr) && statusErr.status == status
+}
+
+// Search matches the query against name and description, weighting the
+// name higher and tolerating typos, and filters out products that listings
+// would hide
+func (r *OpenSearchRepository) Search(ctx context.Context, query ports.SearchQuery) ([]domain.SearchHit, error) {
+	body, err := json.Marshal(searchRequest(query, ports.TenantID(ctx), r.now().UTC()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode search request: %w", err)
+	}
+
+	data, err := r.do(ctx, http.MethodPost, "/"+url.PathEscape(r.index)+"/_search", nil, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var response searchResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode search response: %w", err)
+	}
+	hits := make([]domain.SearchHit, len(response.Hits.Hits))
+	for i, hit := range response.Hits.Hits {
+		hits[i] = domain.SearchHit{Product: hit.Source, Score: hit.Score, Highlights: hit.Highlight}
+	}
+	return hits, nil
+}
+
+// Index stores the product as a document keyed by its ID, in the JSON shape
+// Search decodes. The product's version is the document's external
+// version: an older version than the indexed one is ignored, so changes
+// delivered out of order or twice leave the newest in place.
+func (r *OpenSearchRepository) Index(ctx context.Context, product domain.Product) error {
+	body, err := json.Marshal(product)
+	if err != nil {
+		return fmt.Errorf("failed to encode product document: %w", err)
+	}
+	path := fmt.Sprintf("/%s/_doc/%s", url.PathEscape(r.index), url.PathEscape(product.ID))
+	query := url.Values{
+		"version":      {strconv.FormatInt(product.Version, 10)},
+		"version_type": {"external_gte"},
+	}
+	if _, err := r.do(ctx, http.MethodPut, path, query, body); err != nil && !hasStatus(err, http.StatusConflict) {
+		return fmt.Errorf("failed to index product %s: %w", product.ID, err)
+	}
+	return nil
+}
+
+// Remove deletes the product's document, if there is one
+func (r *OpenSearchRepository) Remove(ctx context.Context, productID string) error {
+	path := fmt.Sprintf("/%s/_doc/%s", url.PathEscape(r.index), url.PathEscape(productID))
+	if _, err := r.do(ctx, http.MethodDelete, path, nil, nil); err != nil && !hasStatus(err, http.StatusNotFound) {
+		return fmt.Errorf("failed to remove product %s from index: %w", productID, err)
+	}
+	return nil
+}
+
+// Ping checks that the cluster is reachable and the index exists
+func (r *OpenSearchRepository) Ping(ctx context.Context) error {
+	_, err := r.do(ctx, http.MethodHead, "/"+url.PathEscape(r.index), nil, nil)
+	return err
+}
+
+// searchRequest builds the query DSL for a search. Visibility mirrors the
+// listing filter: drafts, archived, expired and moderated products are
+// excluded, while documents missing those fields are kept. Only tenant's
+// documents match; the default tenant's have no tenant_id.
+func searchRequest(query ports.SearchQuery, tenant string, now time.Time) map[string]interface{} {
+	mustNot := []interface{}{
+		map[string]interface{}{"terms": map[string]interface{}{"status": []string{domain.StatusDraft, domain.StatusArchived, domain.StatusDiscontinued}}},
+		map[string]interface{}{"terms": map[string]interface{}{"moderation_status": []string{domain.ModerationPendingReview, domain.ModerationRejected}}},
+		map[string]interface{}{"range": map[string]interface{}{"expires_at": map[string]interface{}{"lte": now.Format(time.RFC3339)}}},
+	}
+	var filter []interface{}
+	if tenant == "" {
+		mustNot = append(mustNot, map[string]interface{}{"exists": map[string]interface{}{"field": "tenant_id"}})
+	} else {
+		filter = append(filter, map[string]interface{}{"term": map[string]interface{}{"tenant_id": tenant}})
+	}
+
+	boolQuery := map[string]interface{}{
+		"must": map[string]interface{}{
+			"multi_match": map[string]interface{}{
+				"query":     query.Text,
+				"fields":    []string{"name^2", "description"},
+				"fuzziness": "AUTO",
+			},
+		},
+		"must_not": mustNot,
+	}
+	if filter != nil {
+		boolQuery["filter"] = filter
+	}
+	return map[string]interface{}{
+		"size":  query.Limit,
+		"query": map[string]interface{}{"bool": boolQuery},
+		"highlight": map[string]interface{}{
+			"pre_tags":  []string{"<em>"},
+			"post_tags": []string{"</em>"},
+			"fields": map[string]interface{}{
+				"name":        map[string]interface{}{"number_of_fragments": 0},
+				"description": map[string]interface{}{"fragment_size": 150, "number_of_fragments": 3},
+			},
+		},
+	}
+}
+
+// do sends a request to the cluster and returns the body of a 2xx response
+func (r *OpenSearchRepository) do(ctx context.Context, method, path string, query url.Values, body []byte) ([]byte, error) {
+	target := *r.endpoint
+	target.User = nil
+	target.Path = strings.TrimSuffix(target.Path, "/") + path
+	target.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build OpenSearch request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if r.endpoint.User != nil {
+		password, _ := r.endpoint.User.Password()
+		req.SetBasicAuth(r.endpoint.User.Username(), password)
+	} else if r.awsCfg != nil {
+		credentials, err := r.awsCfg.Credentials.Retrieve(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+		}
+		payloadHash := sha256.Sum256(body)
+		if err := r.signer.SignHTTP(ctx, credentials, req, hex.EncodeToString(payloadHash[:]), signingService, r.awsCfg.Region, r.now()); err != nil {
+			return nil, fmt.Errorf("failed to sign OpenSearch request: %w", err)
+		}
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("OpenSearch request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OpenSearch response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &statusError{status: resp.StatusCode, body: bytes.TrimSpace(data)}
+	}
+	return data, nil
+}
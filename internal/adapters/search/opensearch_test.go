@@ -0,0 +1,135 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+func TestOpenSearchRepository_Search(t *testing.T) {
+	var path, authorization string
+	var request map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		authorization = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(t, json.Unmarshal(body, &request))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"hits":{"hits":[
+			{"_score":4.2,"_source":{"id":"1","name":"Laptop Pro","price":1299.99},"highlight":{"name":["<em>Laptop</em> Pro"]}},
+			{"_score":1.1,"_source":{"id":"2","name":"Laptop Sleeve","price":39.99}}
+		]}}`))
+	}))
+	defer server.Close()
+
+	awsCfg := aws.Config{
+		Region:      "us-east-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+	}
+	repo, err := NewOpenSearchRepository(server.URL, "products", &awsCfg, time.Second)
+	require.NoError(t, err)
+
+	hits, err := repo.Search(context.Background(), ports.SearchQuery{Text: "laptop", Limit: 5})
+	require.NoError(t, err)
+
+	assert.Equal(t, "/products/_search", path)
+	assert.True(t, strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 "), authorization)
+	assert.EqualValues(t, 5, request["size"])
+	assert.NotContains(t, request["query"].(map[string]interface{})["bool"], "filter")
+	assert.Contains(t, request, "highlight")
+	if assert.Len(t, hits, 2) {
+		assert.Equal(t, "1", hits[0].Product.ID)
+		assert.Equal(t, 4.2, hits[0].Score)
+		assert.Equal(t, map[string][]string{"name": {"<em>Laptop</em> Pro"}}, hits[0].Highlights)
+		assert.Nil(t, hits[1].Highlights)
+	}
+}
+
+func TestOpenSearchRepository_Index(t *testing.T) {
+	var requests []string
+	var document domain.Product
+	status := http.StatusCreated
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		if r.Method == http.MethodPut {
+			body, _ := io.ReadAll(r.Body)
+			require.NoError(t, json.Unmarshal(body, &document))
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	repo, err := NewOpenSearchRepository(server.URL, "products", nil, time.Second)
+	require.NoError(t, err)
+	ctx := context.Background()
+	cost := domain.Money{Amount: 70000, Currency: "USD"}
+	product := domain.Product{ID: "p1", Name: "Laptop Pro", Price: domain.Money{Amount: 129999, Currency: "USD"}, CostPrice: &cost, TenantID: "acme", Version: 4}
+
+	require.NoError(t, repo.Index(ctx, product))
+	assert.Equal(t, "Laptop Pro", document.Name)
+	assert.Equal(t, "acme", document.TenantID)
+	assert.Equal(t, product.Price, document.Price)
+	assert.Nil(t, document.CostPrice)
+
+	// A newer version is already indexed, or the document is already gone
+	status = http.StatusConflict
+	require.NoError(t, repo.Index(ctx, product))
+	status = http.StatusNotFound
+	require.NoError(t, repo.Remove(ctx, "p1"))
+	status = http.StatusServiceUnavailable
+	assert.Error(t, repo.Index(ctx, product))
+	assert.Error(t, repo.Remove(ctx, "p1"))
+
+	assert.Equal(t, []string{
+		"PUT /products/_doc/p1?version=4&version_type=external_gte",
+		"PUT /products/_doc/p1?version=4&version_type=external_gte",
+		"DELETE /products/_doc/p1",
+		"PUT /products/_doc/p1?version=4&version_type=external_gte",
+		"DELETE /products/_doc/p1",
+	}, requests)
+}
+
+func TestOpenSearchRepository_BasicAuthAndErrors(t *testing.T) {
+	var username string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, _, _ = r.BasicAuth()
+		http.Error(w, `{"error":"index_not_found_exception"}`, http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	endpoint := strings.Replace(server.URL, "http://", "http://admin:secret@", 1)
+	repo, err := NewOpenSearchRepository(endpoint, "products", &aws.Config{}, time.Second)
+	require.NoError(t, err)
+
+	_, err = repo.Search(context.Background(), ports.SearchQuery{Text: "laptop", Limit: 5})
+	assert.ErrorContains(t, err, "index_not_found_exception")
+	assert.Equal(t, "admin", username)
+
+	_, err = NewOpenSearchRepository("not a url", "products", nil, time.Second)
+	assert.Error(t, err)
+}
+
+func TestSearchRequest_TenantFilter(t *testing.T) {
+	now := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	query := ports.SearchQuery{Text: "laptop", Limit: 5}
+
+	scoped := searchRequest(query, "acme", now)["query"].(map[string]interface{})["bool"].(map[string]interface{})
+	assert.Equal(t, []interface{}{map[string]interface{}{"term": map[string]interface{}{"tenant_id": "acme"}}}, scoped["filter"])
+
+	unscoped := searchRequest(query, "", now)["query"].(map[string]interface{})["bool"].(map[string]interface{})
+	assert.NotContains(t, unscoped, "filter")
+	assert.Contains(t, unscoped["must_not"], map[string]interface{}{"exists": map[string]interface{}{"field": "tenant_id"}})
+}
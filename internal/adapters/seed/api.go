@@ -0,0 +1,261 @@
+package seed
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// maxErrorSize bounds the error responses read
+const maxErrorSize = 64 << 10
+
+// productRequest is the body of POST /api/v1/products and of each line of
+// an NDJSON import
+type productRequest struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Price       domain.Money `json:"price"`
+	PublishAt   *time.Time   `json:"publish_at,omitempty"`
+	CategoryID  string       `json:"category_id,omitempty"`
+	Tags        []string     `json:"tags,omitempty"`
+	SKU         string       `json:"sku,omitempty"`
+}
+
+type categoryRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type bulkDeleteRequest struct {
+	IDs               []string `json:"ids"`
+	DryRun            bool     `json:"dry_run"`
+	ConfirmationToken string   `json:"confirmation_token,omitempty"`
+}
+
+// APITarget writes through a running API, so runs measure it end to end.
+// Single products are created with POST /api/v1/products and batches are
+// uploaded to the NDJSON import.
+type APITarget struct {
+	client  *http.Client
+	baseURL string
+	token   string
+}
+
+// NewAPITarget calls the API served at baseURL, such as
+// http://localhost:8080, sending token as a bearer token unless it is
+// empty. Each call is given up after timeout.
+func NewAPITarget(baseURL, token string, timeout time.Duration) *APITarget {
+	return &APITarget{
+		client:  &http.Client{Timeout: timeout},
+		baseURL: strings.TrimSuffix(baseURL, "/") + "/api/v1",
+		token:   token,
+	}
+}
+
+func (t *APITarget) CreateCategory(ctx context.Context, input ports.CategoryInput) (string, error) {
+	var category domain.Category
+	err := t.call(ctx, http.MethodPost, "/categories", categoryRequest{Name: input.Name, Description: input.Description}, http.StatusCreated, &category)
+	return category.ID, err
+}
+
+func (t *APITarget) CreateProducts(ctx context.Context, inputs []ports.ProductInput) (int, error) {
+	if len(inputs) == 1 {
+		if err := t.call(ctx, http.MethodPost, "/products", newProductRequest(inputs[0]), http.StatusCreated, nil); err != nil {
+			return 0, err
+		}
+		return 1, nil
+	}
+
+	var file bytes.Buffer
+	encoder := json.NewEncoder(&file)
+	for _, input := range inputs {
+		if err := encoder.Encode(newProductRequest(input)); err != nil {
+			return 0, err
+		}
+	}
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "seed.ndjson")
+	if err != nil {
+		return 0, err
+	}
+	if _, err := part.Write(file.Bytes()); err != nil {
+		return 0, err
+	}
+	if err := form.Close(); err != nil {
+		return 0, err
+	}
+
+	request, err := t.newRequest(ctx, http.MethodPost, "/products/import", &body)
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", form.FormDataContentType())
+	var summary domain.ImportSummary
+	if err := t.do(request, http.StatusOK, &summary); err != nil {
+		return 0, err
+	}
+	return summary.Imported, skippedError(summary)
+}
+
+// ProductIDs reads the first column of the CSV export
+func (t *APITarget) ProductIDs(ctx context.Context) ([]string, error) {
+	request, err := t.newRequest(ctx, http.MethodGet, "/products/export", nil)
+	if err != nil {
+		return nil, err
+	}
+	// An export of a large table outlasts the timeout of single calls
+	response, err := (&http.Client{Transport: t.client.Transport}).Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, responseError(request, response)
+	}
+
+	reader := csv.NewReader(response.Body)
+	if _, err := reader.Read(); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid export: %w", err)
+	}
+	var ids []string
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return ids, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid export: %w", err)
+		}
+		ids = append(ids, record[0])
+	}
+}
+
+func (t *APITarget) DeleteProducts(ctx context.Context, ids []string) (int, error) {
+	var preview domain.BulkDeleteSummary
+	if err := t.call(ctx, http.MethodPost, "/products/bulk-delete", bulkDeleteRequest{IDs: ids, DryRun: true}, http.StatusOK, &preview); err != nil {
+		return 0, err
+	}
+	var summary domain.BulkDeleteSummary
+	err := t.call(ctx, http.MethodPost, "/products/bulk-delete", bulkDeleteRequest{IDs: ids, ConfirmationToken: preview.ConfirmationToken}, http.StatusOK, &summary)
+	return summary.Deleted, err
+}
+
+func (t *APITarget) CategoryIDs(ctx context.Context) ([]string, error) {
+	var listing struct {
+		Categories []domain.Category `json:"categories"`
+	}
+	if err := t.call(ctx, http.MethodGet, "/categories", nil, http.StatusOK, &listing); err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(listing.Categories))
+	for i, category := range listing.Categories {
+		ids[i] = category.ID
+	}
+	return ids, nil
+}
+
+func (t *APITarget) DeleteCategory(ctx context.Context, id string) error {
+	err := t.call(ctx, http.MethodDelete, "/categories/"+url.PathEscape(id), nil, http.StatusNoContent, nil)
+	var status *statusError
+	if errors.As(err, &status) && status.code == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+// call sends body as JSON and decodes the response into out, unless out is
+// nil, failing unless the response has status want
+func (t *APITarget) call(ctx context.Context, method, path string, body any, want int, out any) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	request, err := t.newRequest(ctx, method, path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	return t.do(request, want, out)
+}
+
+func (t *APITarget) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	request, err := http.NewRequestWithContext(ctx, method, t.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if t.token != "" {
+		request.Header.Set("Authorization", "Bearer "+t.token)
+	}
+	return request, nil
+}
+
+func (t *APITarget) do(request *http.Request, want int, out any) error {
+	response, err := t.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != want {
+		return responseError(request, response)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(response.Body).Decode(out); err != nil {
+		return fmt.Errorf("invalid response to %s %s: %w", request.Method, request.URL.Path, err)
+	}
+	return nil
+}
+
+// statusError is an unexpected response of the API
+type statusError struct {
+	code    int
+	message string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("status %d: %s", e.code, e.message)
+}
+
+func responseError(request *http.Request, response *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(response.Body, maxErrorSize))
+	var decoded struct {
+		Error string `json:"error"`
+	}
+	message := strings.TrimSpace(string(body))
+	if json.Unmarshal(body, &decoded) == nil && decoded.Error != "" {
+		message = decoded.Error
+	}
+	return fmt.Errorf("%s %s: %w", request.Method, request.URL.Path, &statusError{code: response.StatusCode, message: message})
+}
+
+func newProductRequest(input ports.ProductInput) productRequest {
+	return productRequest{
+		Name:        input.Name,
+		Description: input.Description,
+		Price:       input.Price,
+		PublishAt:   input.PublishAt,
+		CategoryID:  input.CategoryID,
+		Tags:        input.Tags,
+		SKU:         input.SKU,
+	}
+}
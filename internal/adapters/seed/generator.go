@@ -0,0 +1,196 @@
+// Package seed fills a product catalog with realistic fake data, either
+// through the services themselves or through the HTTP API, and measures how
+// fast the writes go so the same run doubles as a load test.
+package seed
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// Price distributions. Uniform spreads prices evenly over the range;
+// lognormal clusters them around its geometric middle with a long tail of
+// expensive products, as real catalogs do.
+const (
+	PricesUniform   = "uniform"
+	PricesLognormal = "lognormal"
+)
+
+// Options shape the generated catalog
+type Options struct {
+	// Categories is how many categories the products are spread over;
+	// zero leaves every product uncategorized
+	Categories int
+	// CategorySkew, above 1, is the exponent of a Zipf distribution that
+	// gives the first categories most products; zero spreads them evenly
+	CategorySkew float64
+	// MinPrice and MaxPrice bound prices, in minor units of Currency
+	MinPrice          int64
+	MaxPrice          int64
+	Currency          string
+	PriceDistribution string
+	// DraftRatio is the share of products scheduled to publish later
+	DraftRatio float64
+	// SKURatio is the share of products with an SKU. Those are written one
+	// by one to reserve it, so it also slows batched writes down.
+	SKURatio float64
+	// Seed makes runs reproducible; zero seeds from the clock
+	Seed uint64
+}
+
+// DefaultOptions seed a mid-sized store
+func DefaultOptions() Options {
+	return Options{
+		Categories:        8,
+		CategorySkew:      1.2,
+		MinPrice:          199,
+		MaxPrice:          99999,
+		Currency:          "USD",
+		PriceDistribution: PricesLognormal,
+		DraftRatio:        0.05,
+		SKURatio:          0.2,
+	}
+}
+
+type catalog struct {
+	category string
+	nouns    []string
+}
+
+// catalogs name the categories and the products that belong in each
+var catalogs = []catalog{
+	{"Electronics", []string{"Headphones", "Speaker", "Charger", "Monitor", "Keyboard", "Webcam", "Router"}},
+	{"Kitchen", []string{"Skillet", "Kettle", "Knife Set", "Blender", "Cutting Board", "Mug", "Grinder"}},
+	{"Outdoor", []string{"Tent", "Backpack", "Lantern", "Sleeping Bag", "Water Bottle", "Hammock"}},
+	{"Apparel", []string{"Jacket", "Hoodie", "T-Shirt", "Beanie", "Scarf", "Sneakers", "Socks"}},
+	{"Home", []string{"Lamp", "Throw Blanket", "Vase", "Rug", "Pillow", "Wall Clock", "Candle"}},
+	{"Toys", []string{"Puzzle", "Building Set", "Plush Bear", "Kite", "Board Game", "Yo-Yo"}},
+	{"Sports", []string{"Yoga Mat", "Dumbbell", "Jump Rope", "Football", "Tennis Racket", "Helmet"}},
+	{"Office", []string{"Notebook", "Desk Organizer", "Pen Set", "Stapler", "Desk Chair", "Planner"}},
+	{"Garden", []string{"Planter", "Watering Can", "Pruner", "Hose", "Bird Feeder", "Seed Kit"}},
+	{"Pets", []string{"Dog Bed", "Cat Tree", "Leash", "Food Bowl", "Chew Toy", "Grooming Brush"}},
+}
+
+var (
+	adjectives = []string{"Classic", "Compact", "Deluxe", "Essential", "Lightweight", "Premium", "Rugged", "Smart", "Vintage", "Eco"}
+	materials  = []string{"bamboo", "steel", "cotton", "oak", "recycled plastic", "wool", "ceramic", "aluminium", "leather", "glass"}
+	tags       = []string{"new", "sale", "eco", "bestseller", "limited", "gift", "clearance", "handmade"}
+)
+
+// Generator makes fake categories and products. It is not safe for
+// concurrent use.
+type Generator struct {
+	options  Options
+	random   *rand.Rand
+	category func() int
+	seq      int
+}
+
+// NewGenerator checks options and returns a generator following them
+func NewGenerator(options Options) (*Generator, error) {
+	if options.Categories < 0 {
+		return nil, errors.New("categories cannot be negative")
+	}
+	if options.CategorySkew != 0 && options.CategorySkew <= 1 {
+		return nil, errors.New("category skew must be above 1, or 0 for an even spread")
+	}
+	if options.MinPrice <= 0 || options.MaxPrice < options.MinPrice {
+		return nil, fmt.Errorf("invalid price range %d to %d", options.MinPrice, options.MaxPrice)
+	}
+	if options.PriceDistribution != PricesUniform && options.PriceDistribution != PricesLognormal {
+		return nil, fmt.Errorf("unknown price distribution %q", options.PriceDistribution)
+	}
+	if options.DraftRatio < 0 || options.DraftRatio > 1 || options.SKURatio < 0 || options.SKURatio > 1 {
+		return nil, errors.New("draft and SKU ratios must be between 0 and 1")
+	}
+	if options.Seed == 0 {
+		options.Seed = uint64(time.Now().UnixNano())
+	}
+
+	g := &Generator{options: options, random: rand.New(rand.NewPCG(options.Seed, options.Seed>>32))}
+	switch {
+	case options.Categories == 0:
+		g.category = func() int { return -1 }
+	case options.CategorySkew == 0 || options.Categories == 1:
+		g.category = func() int { return g.random.IntN(options.Categories) }
+	default:
+		zipf := rand.NewZipf(g.random, options.CategorySkew, 1, uint64(options.Categories-1))
+		g.category = func() int { return int(zipf.Uint64()) }
+	}
+	return g, nil
+}
+
+// Categories returns the categories to create, in the order Product expects
+// their IDs. Past the built-in names they repeat with a number.
+func (g *Generator) Categories() []ports.CategoryInput {
+	inputs := make([]ports.CategoryInput, g.options.Categories)
+	for i := range inputs {
+		name := catalogs[i%len(catalogs)].category
+		if round := i / len(catalogs); round > 0 {
+			name = fmt.Sprintf("%s %d", name, round+1)
+		}
+		inputs[i] = ports.CategoryInput{Name: name, Description: "Seeded " + strings.ToLower(name) + " products"}
+	}
+	return inputs
+}
+
+// Product returns a new product in one of categoryIDs, which are the IDs
+// the categories of Categories were created with
+func (g *Generator) Product(categoryIDs []string) ports.ProductInput {
+	g.seq++
+	index := g.category()
+	pool := catalogs[g.random.IntN(len(catalogs))]
+	var categoryID string
+	if index >= 0 && index < len(categoryIDs) {
+		pool = catalogs[index%len(catalogs)]
+		categoryID = categoryIDs[index]
+	}
+
+	adjective := adjectives[g.random.IntN(len(adjectives))]
+	noun := pool.nouns[g.random.IntN(len(pool.nouns))]
+	material := materials[g.random.IntN(len(materials))]
+	input := ports.ProductInput{
+		// The sequence keeps names apart, so name searches find few products
+		Name:        fmt.Sprintf("%s %s %d", adjective, noun, g.seq),
+		Description: fmt.Sprintf("%s %s made of %s.", adjective, strings.ToLower(noun), material),
+		CategoryID:  categoryID,
+	}
+	input.Price.Amount = g.price()
+	input.Price.Currency = g.options.Currency
+	for _, i := range g.random.Perm(len(tags))[:g.random.IntN(4)] {
+		input.Tags = append(input.Tags, tags[i])
+	}
+	if g.random.Float64() < g.options.SKURatio {
+		input.SKU = fmt.Sprintf("SEED-%08X", g.random.Uint32())
+	}
+	if g.random.Float64() < g.options.DraftRatio {
+		publishAt := time.Now().UTC().Add(time.Duration(1+g.random.IntN(30*24)) * time.Hour).Truncate(time.Second)
+		input.PublishAt = &publishAt
+	}
+	return input
+}
+
+// price draws a price in minor units. Prices of a unit or more end in .99.
+func (g *Generator) price() int64 {
+	low, high := g.options.MinPrice, g.options.MaxPrice
+	var amount int64
+	switch g.options.PriceDistribution {
+	case PricesLognormal:
+		// Most prices fall within two standard deviations of the middle
+		mu := (math.Log(float64(low)) + math.Log(float64(high))) / 2
+		sigma := (math.Log(float64(high)) - math.Log(float64(low))) / 4
+		amount = int64(math.Exp(mu + sigma*g.random.NormFloat64()))
+	default:
+		amount = low + g.random.Int64N(high-low+1)
+	}
+	if amount >= 100 {
+		amount = amount/100*100 + 99
+	}
+	return min(max(amount, low), high)
+}
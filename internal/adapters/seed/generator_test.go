@@ -0,0 +1,75 @@
+package seed
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+func TestGenerator_Product(t *testing.T) {
+	options := DefaultOptions()
+	options.Seed = 42
+	generator, err := NewGenerator(options)
+	require.NoError(t, err)
+
+	categories := generator.Categories()
+	require.Len(t, categories, options.Categories)
+	assert.Equal(t, "Electronics", categories[0].Name)
+	ids := []string{"c0", "c1", "c2", "c3", "c4", "c5", "c6", "c7"}
+
+	perCategory := map[string]int{}
+	for range 2000 {
+		product := generator.Product(ids)
+		assert.NotEmpty(t, product.Name)
+		assert.GreaterOrEqual(t, product.Price.Amount, options.MinPrice)
+		assert.LessOrEqual(t, product.Price.Amount, options.MaxPrice)
+		assert.NoError(t, product.Price.Validate())
+		_, err := domain.NormalizeTags(product.Tags)
+		assert.NoError(t, err)
+		_, err = domain.NormalizeSKU(product.SKU)
+		assert.NoError(t, err)
+		perCategory[product.CategoryID]++
+	}
+	assert.Len(t, perCategory, len(ids))
+	assert.Greater(t, perCategory["c0"], perCategory["c7"], "skew favors the first categories")
+
+	first, err := NewGenerator(options)
+	require.NoError(t, err)
+	again, err := NewGenerator(options)
+	require.NoError(t, err)
+	a, b := first.Product(ids), again.Product(ids)
+	assert.Equal(t, a.Name, b.Name, "a seed repeats the data")
+	assert.Equal(t, a.Price, b.Price)
+}
+
+func TestGenerator_Uncategorized(t *testing.T) {
+	options := DefaultOptions()
+	options.Categories = 0
+	options.PriceDistribution = PricesUniform
+	generator, err := NewGenerator(options)
+	require.NoError(t, err)
+
+	assert.Empty(t, generator.Categories())
+	assert.Empty(t, generator.Product(nil).CategoryID)
+}
+
+func TestNewGenerator_InvalidOptions(t *testing.T) {
+	tests := map[string]func(*Options){
+		"skew of 1":           func(o *Options) { o.CategorySkew = 1 },
+		"inverted prices":     func(o *Options) { o.MinPrice, o.MaxPrice = 500, 100 },
+		"free products":       func(o *Options) { o.MinPrice = 0 },
+		"unknown prices":      func(o *Options) { o.PriceDistribution = "normal" },
+		"ratio above one":     func(o *Options) { o.DraftRatio = 1.5 },
+		"negative categories": func(o *Options) { o.Categories = -1 },
+	}
+	for name, change := range tests {
+		t.Run(name, func(t *testing.T) {
+			options := DefaultOptions()
+			change(&options)
+			_, err := NewGenerator(options)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// maxLoggedFailures bounds the failed calls logged one by one; a target
+// that is down would otherwise log every batch
+const maxLoggedFailures = 10
+
+// Report measures a seed run. Latencies are those of the calls to the
+// target, each creating one batch.
+type Report struct {
+	Categories int
+	Created    int
+	Failed     int
+	Elapsed    time.Duration
+	P50        time.Duration
+	P95        time.Duration
+	P99        time.Duration
+	Max        time.Duration
+}
+
+// Rate is how many products were created per second
+func (r Report) Rate() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Created) / r.Elapsed.Seconds()
+}
+
+// Run creates the generator's categories and then count products, in
+// batches of batchSize with up to concurrency batches in flight. Failed
+// batches are counted in the report rather than ending the run; only a
+// category that cannot be created or a cancelled ctx stop it early.
+func Run(ctx context.Context, target Target, generator *Generator, count, batchSize, concurrency int, logger *slog.Logger) (Report, error) {
+	var report Report
+	categories := generator.Categories()
+	categoryIDs := make([]string, 0, len(categories))
+	for _, input := range categories {
+		id, err := target.CreateCategory(ctx, input)
+		if err != nil {
+			return report, fmt.Errorf("failed to create category %q: %w", input.Name, err)
+		}
+		categoryIDs = append(categoryIDs, id)
+	}
+	report.Categories = len(categoryIDs)
+
+	batches := make(chan []ports.ProductInput)
+	var (
+		mu        sync.Mutex
+		latencies []time.Duration
+		// failedCalls counts the calls that returned an error
+		failedCalls int
+		wg          sync.WaitGroup
+	)
+	started := time.Now()
+	for range max(concurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batches {
+				callStarted := time.Now()
+				created, err := target.CreateProducts(ctx, batch)
+				latency := time.Since(callStarted)
+
+				mu.Lock()
+				latencies = append(latencies, latency)
+				before := report.Created
+				report.Created += created
+				report.Failed += len(batch) - created
+				if err != nil {
+					failedCalls++
+				}
+				logFailure := err != nil && failedCalls <= maxLoggedFailures
+				mu.Unlock()
+
+				if logFailure && ctx.Err() == nil {
+					logger.WarnContext(ctx, "failed to create seed products", "batch", len(batch), "created", created, "error", err)
+				}
+				// Progress every tenth of the run
+				if step := max(count/10, 1); (before+created)/step > before/step {
+					logger.InfoContext(ctx, "seeding products", "created", before+created, "of", count)
+				}
+			}
+		}()
+	}
+
+	size := max(batchSize, 1)
+produce:
+	for sent := 0; sent < count; sent += size {
+		batch := make([]ports.ProductInput, min(size, count-sent))
+		for i := range batch {
+			batch[i] = generator.Product(categoryIDs)
+		}
+		select {
+		case batches <- batch:
+		case <-ctx.Done():
+			break produce
+		}
+	}
+	close(batches)
+	wg.Wait()
+
+	report.Elapsed = time.Since(started)
+	if failedCalls > maxLoggedFailures {
+		logger.WarnContext(ctx, "more seed batches failed than were logged", "failed_calls", failedCalls)
+	}
+	slices.Sort(latencies)
+	report.P50 = percentile(latencies, 0.50)
+	report.P95 = percentile(latencies, 0.95)
+	report.P99 = percentile(latencies, 0.99)
+	report.Max = percentile(latencies, 1)
+	return report, ctx.Err()
+}
+
+// Truncate deletes every product and then every category of the target,
+// not only the seeded ones. It returns how many of each it deleted.
+func Truncate(ctx context.Context, target Target, logger *slog.Logger) (int, int, error) {
+	ids, err := target.ProductIDs(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list products: %w", err)
+	}
+	products := 0
+	for chunk := range slices.Chunk(ids, domain.MaxBulkDelete) {
+		deleted, err := target.DeleteProducts(ctx, chunk)
+		products += deleted
+		if err != nil {
+			return products, 0, fmt.Errorf("failed to delete products: %w", err)
+		}
+		logger.InfoContext(ctx, "truncating products", "deleted", products, "of", len(ids))
+	}
+
+	categoryIDs, err := target.CategoryIDs(ctx)
+	if err != nil {
+		return products, 0, fmt.Errorf("failed to list categories: %w", err)
+	}
+	for i, id := range categoryIDs {
+		if err := target.DeleteCategory(ctx, id); err != nil {
+			return products, i, fmt.Errorf("failed to delete category %s: %w", id, err)
+		}
+	}
+	return products, len(categoryIDs), nil
+}
+
+// percentile returns the value below which the share p of sorted falls
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	index := int(float64(len(sorted))*p+0.5) - 1
+	return sorted[min(max(index, 0), len(sorted)-1)]
+}
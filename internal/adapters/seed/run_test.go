@@ -0,0 +1,188 @@
+package seed
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// fakeAPI serves the endpoints a seed run calls, keeping what it is sent
+type fakeAPI struct {
+	mu         sync.Mutex
+	products   map[string]productRequest
+	categories map[string]bool
+	imports    int
+	// failSKU is rejected as taken by imports
+	failSKU string
+}
+
+func newFakeAPI(t *testing.T) (*fakeAPI, *httptest.Server) {
+	api := &fakeAPI{products: map[string]productRequest{}, categories: map[string]bool{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/categories", func(w http.ResponseWriter, r *http.Request) {
+		var req categoryRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		api.mu.Lock()
+		id := fmt.Sprintf("cat-%d", len(api.categories))
+		api.categories[id] = true
+		api.mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(domain.Category{ID: id, Name: req.Name})
+	})
+	mux.HandleFunc("GET /api/v1/categories", func(w http.ResponseWriter, r *http.Request) {
+		api.mu.Lock()
+		defer api.mu.Unlock()
+		var categories []domain.Category
+		for id := range api.categories {
+			categories = append(categories, domain.Category{ID: id})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"categories": categories})
+	})
+	mux.HandleFunc("DELETE /api/v1/categories/{id}", func(w http.ResponseWriter, r *http.Request) {
+		api.mu.Lock()
+		delete(api.categories, r.PathValue("id"))
+		api.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("POST /api/v1/products", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		var req productRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		api.save(req)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{}`))
+	})
+	mux.HandleFunc("POST /api/v1/products/import", func(w http.ResponseWriter, r *http.Request) {
+		file, _, err := r.FormFile("file")
+		require.NoError(t, err)
+		summary := domain.ImportSummary{Errors: []domain.ImportRowError{}}
+		scanner := bufio.NewScanner(file)
+		for row := 1; scanner.Scan(); row++ {
+			var req productRequest
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &req))
+			if req.SKU != "" && req.SKU == api.failSKU {
+				summary.Skip(row, &domain.DuplicateError{Field: domain.FieldSKU, Value: req.SKU})
+				continue
+			}
+			api.save(req)
+			summary.Imported++
+		}
+		api.mu.Lock()
+		api.imports++
+		api.mu.Unlock()
+		json.NewEncoder(w).Encode(summary)
+	})
+	mux.HandleFunc("GET /api/v1/products/export", func(w http.ResponseWriter, r *http.Request) {
+		api.mu.Lock()
+		defer api.mu.Unlock()
+		io.WriteString(w, "id,name\n")
+		for id, product := range api.products {
+			fmt.Fprintf(w, "%s,%q\n", id, product.Name)
+		}
+	})
+	mux.HandleFunc("POST /api/v1/products/bulk-delete", func(w http.ResponseWriter, r *http.Request) {
+		var req bulkDeleteRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.DryRun {
+			json.NewEncoder(w).Encode(domain.BulkDeleteSummary{DryRun: true, Matched: len(req.IDs), ConfirmationToken: "token"})
+			return
+		}
+		assert.Equal(t, "token", req.ConfirmationToken)
+		api.mu.Lock()
+		for _, id := range req.IDs {
+			delete(api.products, id)
+		}
+		api.mu.Unlock()
+		json.NewEncoder(w).Encode(domain.BulkDeleteSummary{Deleted: len(req.IDs), IDs: req.IDs})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return api, server
+}
+
+func (a *fakeAPI) save(req productRequest) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.products[fmt.Sprintf("prod-%d", len(a.products))] = req
+}
+
+func TestRun_API(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	options := DefaultOptions()
+	options.Categories = 3
+	options.SKURatio = 0
+	options.Seed = 7
+
+	t.Run("single creates", func(t *testing.T) {
+		api, server := newFakeAPI(t)
+		generator, err := NewGenerator(options)
+		require.NoError(t, err)
+
+		report, err := Run(context.Background(), NewAPITarget(server.URL+"/", "secret", time.Second), generator, 20, 1, 4, logger)
+		require.NoError(t, err)
+		assert.Equal(t, 3, report.Categories)
+		assert.Equal(t, 20, report.Created)
+		assert.Zero(t, report.Failed)
+		assert.Positive(t, report.Max)
+		assert.LessOrEqual(t, report.P50, report.P99)
+		assert.Len(t, api.products, 20)
+		for _, product := range api.products {
+			assert.True(t, api.categories[product.CategoryID], "products reference the created categories")
+		}
+	})
+
+	t.Run("batches", func(t *testing.T) {
+		api, server := newFakeAPI(t)
+		generator, err := NewGenerator(options)
+		require.NoError(t, err)
+		report, err := Run(context.Background(), NewAPITarget(server.URL, "secret", time.Second), generator, 23, 10, 2, logger)
+		require.NoError(t, err)
+		assert.Equal(t, 23, report.Created)
+		assert.Equal(t, 3, api.imports, "23 products go in batches of 10, 10 and 3")
+	})
+
+	t.Run("truncate", func(t *testing.T) {
+		api, server := newFakeAPI(t)
+		target := NewAPITarget(server.URL, "secret", time.Second)
+		generator, err := NewGenerator(options)
+		require.NoError(t, err)
+		_, err = Run(context.Background(), target, generator, 5, 1, 1, logger)
+		require.NoError(t, err)
+
+		products, categories, err := Truncate(context.Background(), target, logger)
+		require.NoError(t, err)
+		assert.Equal(t, 5, products)
+		assert.Equal(t, 3, categories)
+		assert.Empty(t, api.products)
+		assert.Empty(t, api.categories)
+	})
+}
+
+func TestAPITarget_SkippedRows(t *testing.T) {
+	api, server := newFakeAPI(t)
+	api.failSKU = "SEED-TAKEN"
+	target := NewAPITarget(server.URL, "", time.Second)
+	generator, err := NewGenerator(DefaultOptions())
+	require.NoError(t, err)
+
+	taken := generator.Product(nil)
+	taken.SKU = "SEED-TAKEN"
+	inputs := []ports.ProductInput{generator.Product(nil), generator.Product(nil), taken}
+	created, err := target.CreateProducts(context.Background(), inputs)
+	assert.Equal(t, 2, created)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "1 products skipped, first at row 3")
+}
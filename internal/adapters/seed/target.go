@@ -0,0 +1,108 @@
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// Target is where a seed run writes: the services in-process, or a running
+// API over HTTP
+type Target interface {
+	CreateCategory(ctx context.Context, input ports.CategoryInput) (string, error)
+	// CreateProducts reports how many of inputs were created. An error
+	// along with a count explains why the rest were not.
+	CreateProducts(ctx context.Context, inputs []ports.ProductInput) (int, error)
+	ProductIDs(ctx context.Context) ([]string, error)
+	// DeleteProducts deletes up to domain.MaxBulkDelete products
+	DeleteProducts(ctx context.Context, ids []string) (int, error)
+	CategoryIDs(ctx context.Context) ([]string, error)
+	DeleteCategory(ctx context.Context, id string) error
+}
+
+// ServiceTarget writes through the same services as the API, without HTTP
+// in between, so runs measure the services and DynamoDB alone
+type ServiceTarget struct {
+	imports     ports.ImportService
+	exports     ports.ExportService
+	bulkDeletes ports.BulkDeleteService
+	categories  ports.CategoryService
+}
+
+func NewServiceTarget(imports ports.ImportService, exports ports.ExportService, bulkDeletes ports.BulkDeleteService, categories ports.CategoryService) *ServiceTarget {
+	return &ServiceTarget{
+		imports:     imports,
+		exports:     exports,
+		bulkDeletes: bulkDeletes,
+		categories:  categories,
+	}
+}
+
+func (t *ServiceTarget) CreateCategory(ctx context.Context, input ports.CategoryInput) (string, error) {
+	category, err := t.categories.Create(ctx, input)
+	return category.ID, err
+}
+
+// CreateProducts imports inputs as one batch
+func (t *ServiceTarget) CreateProducts(ctx context.Context, inputs []ports.ProductInput) (int, error) {
+	rows := make([]ports.ImportRow, len(inputs))
+	for i, input := range inputs {
+		rows[i] = ports.ImportRow{Row: i + 1, Input: input}
+	}
+	summary, err := t.imports.Import(ctx, rows, false)
+	if err != nil {
+		return 0, err
+	}
+	return summary.Imported, skippedError(summary)
+}
+
+func (t *ServiceTarget) ProductIDs(ctx context.Context) ([]string, error) {
+	var ids []string
+	err := t.exports.Export(ctx, ports.ProductFilters{}, func(product domain.Product) error {
+		ids = append(ids, product.ID)
+		return nil
+	})
+	return ids, err
+}
+
+func (t *ServiceTarget) DeleteProducts(ctx context.Context, ids []string) (int, error) {
+	selection := ports.BulkDeleteSelection{IDs: ids}
+	preview, err := t.bulkDeletes.BulkDelete(ctx, selection, true, "")
+	if err != nil {
+		return 0, err
+	}
+	summary, err := t.bulkDeletes.BulkDelete(ctx, selection, false, preview.ConfirmationToken)
+	return summary.Deleted, err
+}
+
+func (t *ServiceTarget) CategoryIDs(ctx context.Context) ([]string, error) {
+	categories, err := t.categories.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(categories))
+	for i, category := range categories {
+		ids[i] = category.ID
+	}
+	return ids, nil
+}
+
+func (t *ServiceTarget) DeleteCategory(ctx context.Context, id string) error {
+	err := t.categories.Delete(ctx, id)
+	if errors.Is(err, domain.ErrCategoryNotFound) {
+		return nil
+	}
+	return err
+}
+
+// skippedError describes the first row an import skipped, if any
+func skippedError(summary domain.ImportSummary) error {
+	if summary.Skipped == 0 || len(summary.Errors) == 0 {
+		return nil
+	}
+	first := summary.Errors[0]
+	return fmt.Errorf("%d products skipped, first at row %d: %s", summary.Skipped, first.Row, first.Error)
+}
@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/google/uuid"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// archivedProduct is one line of a cold storage object. The cost price is
+// left out of product JSON, so it is carried next to it, in major units of
+// the price's currency as objects written before it was Money hold it.
+type archivedProduct struct {
+	domain.Product
+	CostPrice *domain.Decimal `json:"cost_price,omitempty"`
+}
+
+// S3ColdStorage writes each batch of products moved out of the table as a
+// JSON Lines object, one product per line
+type S3ColdStorage struct {
+	client *s3.Client
+	bucket string
+}
+
+func NewS3ColdStorage(client *s3.Client, bucket string) *S3ColdStorage {
+	return &S3ColdStorage{
+		client: client,
+		bucket: bucket,
+	}
+}
+
+func (s *S3ColdStorage) Store(ctx context.Context, products []domain.Product) (string, error) {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, product := range products {
+		line := archivedProduct{Product: product}
+		if product.CostPrice != nil {
+			cost := product.CostPrice.Major()
+			line.CostPrice = &cost
+		}
+		if err := encoder.Encode(line); err != nil {
+			return "", fmt.Errorf("failed to encode product %s: %w", product.ID, err)
+		}
+	}
+
+	key := fmt.Sprintf("products/%s/%s.jsonl", time.Now().UTC().Format("2006/01/02"), uuid.NewString())
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(body.Bytes()),
+		ContentType: aws.String("application/x-ndjson"),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to store archived products: %w", err)
+	}
+	return key, nil
+}
+
+func (s *S3ColdStorage) Load(ctx context.Context, key, id string) (domain.Product, error) {
+	object, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	var noSuchKey *types.NoSuchKey
+	if errors.As(err, &noSuchKey) {
+		return domain.Product{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.Product{}, fmt.Errorf("failed to read archived products: %w", err)
+	}
+	defer object.Body.Close()
+
+	decoder := json.NewDecoder(object.Body)
+	for {
+		var line archivedProduct
+		err := decoder.Decode(&line)
+		if errors.Is(err, io.EOF) {
+			return domain.Product{}, domain.ErrNotFound
+		}
+		if err != nil {
+			return domain.Product{}, fmt.Errorf("failed to decode archived products: %w", err)
+		}
+		if line.ID == id {
+			if line.CostPrice != nil {
+				cost, err := domain.MoneyFromDecimal(*line.CostPrice, line.Price.Currency)
+				if err != nil {
+					return domain.Product{}, fmt.Errorf("invalid cost price of archived product %s: %w", id, err)
+				}
+				line.Product.CostPrice = &cost
+			}
+			return line.Product, nil
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Package storage keeps uploaded files in blob storage.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// S3Storage hands out presigned PUT URLs so clients upload straight to the
+// bucket without the files passing through the API
+type S3Storage struct {
+	presigner *s3.PresignClient
+	bucket    string
+	// baseURL serves the uploaded objects, such as a CloudFront
+	// distribution in front of the bucket
+	baseURL string
+	expiry  time.Duration
+}
+
+// NewS3Storage presigns uploads to bucket valid for expiry. An empty
+// baseURL serves objects from the bucket's virtual-hosted endpoint.
+func NewS3Storage(client *s3.Client, bucket, baseURL string, expiry time.Duration) *S3Storage {
+	if baseURL == "" {
+		baseURL = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", bucket, client.Options().Region)
+	}
+	return &S3Storage{
+		presigner: s3.NewPresignClient(client),
+		bucket:    bucket,
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+		expiry:    expiry,
+	}
+}
+
+func (s *S3Storage) PresignUpload(ctx context.Context, key, contentType string) (ports.PresignedUpload, error) {
+	request, err := s.presigner.PresignPutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		ContentType: aws.String(contentType),
+	}, s3.WithPresignExpires(s.expiry))
+	if err != nil {
+		return ports.PresignedUpload{}, fmt.Errorf("failed to presign upload of %s: %w", key, err)
+	}
+
+	// The signature covers these headers, so the client must send them as is
+	headers := make(map[string]string, len(request.SignedHeader))
+	for name, values := range request.SignedHeader {
+		if strings.EqualFold(name, "Host") || len(values) == 0 {
+			continue
+		}
+		headers[name] = values[0]
+	}
+	return ports.PresignedUpload{
+		URL:       request.URL,
+		Method:    request.Method,
+		Headers:   headers,
+		ExpiresAt: time.Now().UTC().Add(s.expiry),
+	}, nil
+}
+
+func (s *S3Storage) URL(key string) string {
+	return s.baseURL + "/" + (&url.URL{Path: key}).EscapedPath()
+}
@@ -0,0 +1,696 @@
+// Package app wires configuration, adapters and services into the HTTP
+// router, so every entry point (the HTTP server, the Lambda handler) serves
+// the same application.
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/comprehend"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/firehose"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/analytics"
+	authz "github.com/tu-usuario/product-crud-hexagonal/internal/adapters/authorizer"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/cache"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/errreport"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/events"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/exchange"
+	productHttp "github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/adminui"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/middleware"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/openapi"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/ids"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/moderation"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/notifier"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/pricing"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/recommender"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/repository"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/search"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/storage"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/services"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/auth"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/buildinfo"
+	appConfig "github.com/tu-usuario/product-crud-hexagonal/internal/platform/config"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/cursor"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/health"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/logger"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/metrics"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/migrations"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/scheduler"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/telemetry"
+)
+
+// App is the wired application: its router, the background jobs it owns and
+// the resources that must be flushed on shutdown
+type App struct {
+	Router *gin.Engine
+	// Products, AWS and the bulk services let other entry points, such as
+	// the import worker and the seed tool, drive the same services
+	Products    ports.ProductService
+	Imports     ports.ImportService
+	Exports     ports.ExportService
+	BulkDeletes ports.BulkDeleteService
+	Categories  ports.CategoryService
+	AWS         aws.Config
+	logger      *slog.Logger
+	jobs        []job
+	// running counts the jobs started by RunJobs, which Close waits for
+	running sync.WaitGroup
+	closers []closer
+	// throttle and validation are adjusted by Reload; throttle is nil when
+	// it was disabled at startup
+	throttle   *repository.AdaptiveThrottle
+	validation *middleware.ValidationMode
+	// config is the running configuration, replaced on every reload
+	config atomic.Pointer[appConfig.Config]
+}
+
+type job struct {
+	name     string
+	interval time.Duration
+	run      func(context.Context) error
+}
+
+type closer struct {
+	// failure is logged when close does not finish in time
+	failure string
+	close   func(context.Context) error
+}
+
+// New builds every adapter and service from cfg and mounts the routes
+func New(ctx context.Context, cfg *appConfig.Config, appLogger *slog.Logger) (*App, error) {
+	a := &App{logger: appLogger}
+	a.config.Store(cfg)
+
+	// AWS SDK Configuration
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(cfg.AWSRegion))
+	if err != nil {
+		return nil, fmt.Errorf("unable to load SDK config: %w", err)
+	}
+
+	if cfg.TracingEnabled {
+		shutdownTracing, err := telemetry.Setup(ctx, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("unable to set up tracing: %w", err)
+		}
+		// Closed last, so spans recorded while flushing the others are exported
+		a.closers = append(a.closers, closer{"spans not exported before shutdown", shutdownTracing})
+		// Every AWS client built from awsCfg below is traced
+		telemetry.InstrumentAWS(&awsCfg)
+		appLogger.Info("tracing enabled", "service", cfg.TracingServiceName, "sample_ratio", cfg.TracingSampleRatio)
+	}
+
+	a.AWS = awsCfg
+
+	var appMetrics *metrics.Metrics
+	if cfg.MetricsEnabled {
+		appMetrics = metrics.New()
+	}
+
+	dbClient, throttle := newDynamoDBClient(cfg, awsCfg, appMetrics, appLogger)
+	a.throttle = throttle
+
+	if cfg.MigrateOnStart {
+		created, err := migrations.EnsureTable(ctx, dbClient, cfg.DynamoDBTable, repository.ExpectedSchema(), appLogger)
+		if err != nil {
+			return nil, fmt.Errorf("table migration of %s failed: %w", cfg.DynamoDBTable, err)
+		}
+		appLogger.Info("table migration finished", "table", cfg.DynamoDBTable, "created", created)
+	}
+
+	// Fail fast when the table layout drifted from what the repository expects
+	if cfg.VerifySchema {
+		if err := repository.VerifySchema(ctx, dbClient, cfg.DynamoDBTable, repository.ExpectedSchema()); err != nil {
+			return nil, fmt.Errorf("table schema verification of %s failed: %w", cfg.DynamoDBTable, err)
+		}
+		appLogger.Info("table schema verified", "table", cfg.DynamoDBTable)
+	}
+
+	// Readiness probe checks, added as dependencies are configured
+	checker := health.NewChecker(cfg.HealthCheckTimeout, cfg.HealthHistorySize, cfg.HealthDegradedSuccessRate)
+	checker.Add("dynamodb", func(ctx context.Context) error {
+		return repository.Ping(ctx, dbClient, cfg.DynamoDBTable)
+	})
+
+	// Dependency Injection
+	// Every product write, made through the cache or around it, drops the
+	// product's cached copy
+	var redisClient *redis.Client
+	var productWritten repository.ProductWriteHook
+	if cfg.RedisURL != "" {
+		redisOptions, err := redis.ParseURL(cfg.RedisURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid REDIS_URL: %w", err)
+		}
+		redisClient = redis.NewClient(redisOptions)
+		a.closers = append(a.closers, closer{"redis connections not closed", func(context.Context) error { return redisClient.Close() }})
+		productWritten = cache.NewInvalidator(redisClient, appLogger).Invalidate
+	}
+	productRepo := repository.NewDynamoDBRepository(dbClient, cfg.DynamoDBTable, repository.WithIndexShards(cfg.IndexShardsFor(cfg.DynamoDBTable)), repository.WithOutbox(cfg.OutboxTable), repository.WithUniqueKeys(cfg.UniqueKeysTable), repository.WithParallelScan(cfg.ScanSegments, cfg.ScanWorkers), repository.WithWriteHook(productWritten))
+	var analyticsPublisher ports.AnalyticsPublisher = analytics.NewNoopPublisher()
+	if cfg.AnalyticsStream != "" {
+		firehosePublisher := analytics.NewFirehosePublisher(firehose.NewFromConfig(awsCfg), cfg.AnalyticsStream, cfg.AnalyticsBufferSize, cfg.AnalyticsFlushInterval, appLogger)
+		analyticsPublisher = firehosePublisher
+		a.closers = append(a.closers, closer{"analytics events not flushed before shutdown", firehosePublisher.Close})
+		appLogger.Info("analytics delivery enabled", "stream", cfg.AnalyticsStream)
+	}
+	var searchRepo ports.SearchRepository = productRepo
+	var eventPublisher ports.EventPublisher = events.NewNoopPublisher()
+	if cfg.EventsTopicARN != "" {
+		eventPublisher = events.NewSNSPublisher(sns.NewFromConfig(awsCfg), cfg.EventsTopicARN)
+		appLogger.Info("product events enabled", "topic", cfg.EventsTopicARN)
+	}
+	if cfg.SearchProvider == "opensearch" {
+		openSearch, err := search.NewOpenSearchRepository(cfg.OpenSearchURL, cfg.OpenSearchIndex, &awsCfg, 5*time.Second)
+		if err != nil {
+			return nil, fmt.Errorf("unable to set up OpenSearch: %w", err)
+		}
+		searchRepo = openSearch
+		checker.Add("opensearch", openSearch.Ping)
+		if cfg.SearchIndexing == "outbox" {
+			eventPublisher = events.NewFanoutPublisher(eventPublisher, services.NewSearchIndexPublisher(openSearch, appLogger))
+		}
+		appLogger.Info("OpenSearch product search enabled", "index", cfg.OpenSearchIndex, "indexing", cfg.SearchIndexing)
+	}
+	if cfg.NotificationRulesFile != "" {
+		data, err := os.ReadFile(cfg.NotificationRulesFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read notification rules %s: %w", cfg.NotificationRulesFile, err)
+		}
+		rules, err := domain.ParseNotificationRules(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid notification rules %s: %w", cfg.NotificationRulesFile, err)
+		}
+		var productNotifier ports.Notifier = notifier.NewLogNotifier(appLogger)
+		if cfg.NotificationFrom != "" {
+			productNotifier = notifier.NewSESNotifier(sesv2.NewFromConfig(awsCfg), cfg.NotificationFrom)
+		}
+		notificationService := services.NewNotificationService(productNotifier, rules, appLogger)
+		// Fed by the outbox relay, so only committed changes are notified
+		eventPublisher = events.NewFanoutPublisher(eventPublisher, notificationService)
+		a.closers = append(a.closers, closer{"notifications not sent before shutdown", notificationService.Close})
+		appLogger.Info("notifications enabled", "rules", len(rules), "ses", cfg.NotificationFrom != "")
+	}
+
+	outboxRepo := repository.NewDynamoDBOutboxRepository(dbClient, cfg.OutboxTable)
+	outboxService := services.NewOutboxService(outboxRepo, eventPublisher, cfg.OutboxBatchSize, appLogger)
+
+	searchTermRepo := repository.NewDynamoDBSearchTermRepository(dbClient, cfg.SearchTermsTable)
+	searchTermService := services.NewSearchTermService(searchTermRepo, appLogger)
+	var moderator ports.ContentModerator = moderation.NewWordlistModerator(cfg.ModerationBlockedTerms, cfg.ModerationFlaggedTerms)
+	if cfg.ModerationProvider == "comprehend" {
+		moderator = moderation.NewComprehendModerator(comprehend.NewFromConfig(awsCfg), cfg.ModerationRejectThreshold, cfg.ModerationFlagThreshold)
+	}
+	appLogger.Info("content moderation configured", "provider", cfg.ModerationProvider)
+
+	tombstoneRepo := repository.NewDynamoDBTombstoneRepository(dbClient, cfg.TombstonesTable)
+	categoryRepo := repository.NewDynamoDBCategoryRepository(dbClient, cfg.DynamoDBTable)
+	var productReads ports.ProductRepository = productRepo
+	var productDeletes ports.ProductBatchDeleter = productRepo
+	var cachedProducts *cache.RedisProductRepository
+	if redisClient != nil {
+		cachedProducts = cache.NewRedisProductRepository(productRepo, redisClient, cfg.CacheTTL, appLogger, cache.WithMetrics(appMetrics))
+		productReads = cachedProducts
+		productDeletes = cachedProducts
+		checker.AddOptional("redis", cachedProducts.Ping)
+		appLogger.Info("product cache enabled", "addr", redisClient.Options().Addr, "ttl", cfg.CacheTTL)
+	}
+	auditLog := repository.NewDynamoDBAuditLog(dbClient, cfg.AuditTable)
+	productIDFormat, err := domain.NewProductIDFormat(cfg.ProductIDPattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PRODUCT_ID_PATTERN: %w", err)
+	}
+	productIDs, err := ids.New(cfg.ProductIDStrategy, cfg.ProductIDPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PRODUCT_ID_STRATEGY: %w", err)
+	}
+	// Product writes commit with the category counts they change
+	unitOfWork := repository.NewDynamoDBUnitOfWork(dbClient)
+	productService := services.NewProductService(productReads, tombstoneRepo, moderator, analyticsPublisher, outboxRepo, searchTermService, categoryRepo, categoryRepo, unitOfWork, auditLog, productIDs, productIDFormat, appLogger)
+	auditService := services.NewAuditService(auditLog, productReads, appLogger)
+	auditHandler := productHttp.NewAuditHandler(auditService, appLogger)
+	var imageHandler *productHttp.ImageHandler
+	if cfg.ImagesBucket != "" {
+		imageStorage := storage.NewS3Storage(s3.NewFromConfig(awsCfg), cfg.ImagesBucket, cfg.ImagesBaseURL, cfg.ImageUploadExpiry)
+		imageService := services.NewImageService(productRepo, imageStorage, auditLog, appLogger)
+		imageHandler = productHttp.NewImageHandler(imageService, appLogger)
+		appLogger.Info("product image uploads enabled", "bucket", cfg.ImagesBucket)
+	}
+	if cfg.CursorSecret == "" {
+		appLogger.Warn("CURSOR_SECRET is not set, pagination cursors will not survive restarts")
+	}
+	cursors, err := cursor.NewCodec(cfg.CursorSecret, cfg.CursorTTL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create cursor codec: %w", err)
+	}
+	exchangeRates, err := exchange.NewStaticRates(domain.DefaultCurrency, cfg.ExchangeRates)
+	if err != nil {
+		return nil, fmt.Errorf("invalid EXCHANGE_RATES: %w", err)
+	}
+	currencyService := services.NewCurrencyService(exchangeRates, appLogger)
+	var priceSuggestionHandler *productHttp.PriceSuggestionHandler
+	if cfg.PricingURL != "" {
+		suggester := pricing.NewHTTPPriceSuggester(cfg.PricingURL, cfg.PricingToken, cfg.PricingTimeout, cfg.PricingBreakerFailures, cfg.PricingBreakerCooldown)
+		priceSuggestionHandler = productHttp.NewPriceSuggestionHandler(services.NewPriceSuggestionService(productReads, suggester, appLogger), appLogger)
+		appLogger.Info("price suggestions enabled", "timeout", cfg.PricingTimeout)
+	}
+	productHandler := productHttp.NewProductHandler(productService, currencyService, cursors, cfg.UpsertOnPut, cfg.DefaultLocale, appLogger)
+	a.Products = productService
+	searchService := services.NewSearchService(searchRepo, searchTermService, appLogger)
+	searchHandler := productHttp.NewSearchHandler(searchService, appLogger)
+	exportService := services.NewExportService(productRepo, appLogger)
+	a.Exports = exportService
+	exportHandler := productHttp.NewExportHandler(exportService, appLogger)
+	importService := services.NewImportService(productRepo, moderator, analyticsPublisher, outboxRepo, categoryRepo, categoryRepo, auditLog, productIDs, appLogger)
+	a.Imports = importService
+	importHandler := productHttp.NewImportHandler(importService, appLogger)
+	bulkDeleteService := services.NewBulkDeleteService(productRepo, productRepo, productDeletes, categoryRepo, tombstoneRepo, auditLog, appLogger)
+	a.BulkDeletes = bulkDeleteService
+	bulkDeleteHandler := productHttp.NewBulkDeleteHandler(bulkDeleteService, appLogger)
+	changesHandler := productHttp.NewChangesHandler(services.NewChangeService(productRepo, tombstoneRepo, appLogger), appLogger)
+	tagService := services.NewTagService(productRepo, cfg.TagsCacheTTL, appLogger)
+	tagHandler := productHttp.NewTagHandler(tagService, appLogger)
+	stockService := services.NewStockService(productRepo, productRepo, appLogger)
+	stockHandler := productHttp.NewStockHandler(stockService, appLogger)
+	lifecycleService := services.NewLifecycleService(productReads, analyticsPublisher, auditLog, appLogger)
+	lifecycleHandler := productHttp.NewLifecycleHandler(lifecycleService, appLogger)
+	reviewRepo := repository.NewDynamoDBReviewRepository(dbClient, cfg.DynamoDBTable, cfg.OutboxTable, productWritten)
+	reviewService := services.NewReviewService(reviewRepo, productRepo, appLogger)
+	reviewHandler := productHttp.NewReviewHandler(reviewService, appLogger)
+	countsTable := ""
+	if cfg.FavoriteCounts {
+		countsTable = cfg.DynamoDBTable
+	}
+	favoriteRepo := repository.NewDynamoDBFavoriteRepository(dbClient, cfg.FavoritesTable, countsTable, cfg.OutboxTable, productWritten)
+	favoriteService := services.NewFavoriteService(favoriteRepo, productRepo, appLogger)
+	favoriteHandler := productHttp.NewFavoriteHandler(favoriteService, appLogger)
+	categoryService := services.NewCategoryService(categoryRepo, productRepo, appLogger)
+	a.Categories = categoryService
+	categoryHandler := productHttp.NewCategoryHandler(categoryService, appLogger)
+	viewRepo := repository.NewDynamoDBViewRepository(dbClient, cfg.ViewsTable)
+	productRecommender := recommender.NewCooccurrenceRecommender(dbClient, cfg.RecommendationsTable)
+	viewService := services.NewViewService(viewRepo, productRepo, productRecommender, cfg.TrendingWindowDays, appLogger)
+	viewHandler := productHttp.NewViewHandler(viewService, appLogger)
+	recommendationService := services.NewRecommendationService(productRecommender, productRepo, appLogger)
+	recommendationHandler := productHttp.NewRecommendationHandler(recommendationService, appLogger)
+	// Related products come from catalog attributes; a personalization
+	// service can take their place behind the same ports.Recommender
+	relatedService := services.NewRecommendationService(recommender.NewAttributeRecommender(productRepo, cfg.RelatedPriceBand), productRepo, appLogger)
+	relatedHandler := productHttp.NewRecommendationHandler(relatedService, appLogger)
+	publishingService := services.NewPublishingService(productRepo, analyticsPublisher, appLogger)
+	archivingService := services.NewArchivingService(productRepo, analyticsPublisher, cfg.ArchiveWarningWindow, appLogger)
+	var coldStorageService ports.ColdStorageService
+	var coldStorageHandler *productHttp.ColdStorageHandler
+	if cfg.ColdStorageBucket != "" {
+		coldStorage := storage.NewS3ColdStorage(s3.NewFromConfig(awsCfg), cfg.ColdStorageBucket)
+		coldStorageService = services.NewColdStorageService(productReads, productRepo, coldStorage, tombstoneRepo, categoryRepo, unitOfWork, cfg.ColdStorageAfterDays, appLogger)
+		coldStorageHandler = productHttp.NewColdStorageHandler(coldStorageService, appLogger)
+		appLogger.Info("cold storage of archived products enabled", "bucket", cfg.ColdStorageBucket, "after_days", cfg.ColdStorageAfterDays)
+	}
+	adminQueryService := services.NewAdminQueryService(productRepo, appLogger)
+	reportRepo := repository.NewDynamoDBReportRepository(dbClient, cfg.ReportsTable)
+	reportService := services.NewReportService(productRepo, categoryRepo, reportRepo, cfg.LowMarginThreshold, appLogger)
+	adminHandler := productHttp.NewAdminHandler(adminQueryService, searchTermService, reportService, appLogger)
+	moderationService := services.NewModerationService(productRepo, productRepo, appLogger)
+	moderationHandler := productHttp.NewModerationHandler(moderationService, appLogger)
+
+	var tokenVerifier *auth.Verifier
+	if cfg.AuthJWKSURL != "" {
+		tokenVerifier, err = auth.NewJWKSVerifier(ctx, cfg.AuthJWKSURL, cfg.AuthIssuer, cfg.AuthAudience)
+		if err != nil {
+			return nil, fmt.Errorf("unable to set up JWT authentication: %w", err)
+		}
+		appLogger.Info("JWT authentication enabled for product writes", "issuer", cfg.AuthIssuer)
+	} else {
+		appLogger.Warn("AUTH_JWKS_URL is not set, product writes are not authenticated")
+	}
+
+	// Roles can only be checked on authenticated requests
+	allow := func(action string) gin.HandlerFunc {
+		return func(c *gin.Context) { c.Next() }
+	}
+	if tokenVerifier != nil {
+		var authorizer ports.Authorizer = authz.NewStaticAuthorizer(domain.DefaultPolicy())
+		if cfg.AuthzProvider == "dynamodb" {
+			authorizer = authz.NewDynamoDBAuthorizer(dbClient, cfg.AuthzTable, cfg.AuthzCacheTTL)
+		}
+		allow = func(action string) gin.HandlerFunc {
+			return middleware.Authorize(authorizer, action, appLogger)
+		}
+		appLogger.Info("role-based access control enabled", "provider", cfg.AuthzProvider)
+	}
+
+	// Router Setup; gin's own output goes through the service logger
+	productHttp.SetGinMode(cfg.LogLevel)
+	ginLogger := appLogger.With("component", "gin")
+	gin.DefaultWriter = logger.Writer(ginLogger, slog.LevelDebug)
+	gin.DefaultErrorWriter = logger.Writer(ginLogger, slog.LevelError)
+
+	// Panics recovered from handlers are always logged, and also reported
+	// to Sentry when it is configured
+	var errorReporter ports.ErrorReporter = errreport.NewNoopReporter()
+	if cfg.SentryDSN != "" {
+		sentryReporter, err := errreport.NewSentryReporter(cfg.SentryDSN, cfg.SentryEnvironment, buildinfo.Get().Version)
+		if err != nil {
+			return nil, fmt.Errorf("unable to set up panic reporting: %w", err)
+		}
+		a.closers = append(a.closers, closer{"panic reports not sent before shutdown", sentryReporter.Close})
+		errorReporter = sentryReporter
+		appLogger.Info("panic reporting enabled", "environment", cfg.SentryEnvironment)
+	}
+
+	router := gin.New()
+	a.Router = router
+
+	// Middleware; the request logger comes first so recovered panics are
+	// logged as 500s with their request ID
+	router.Use(middleware.RequestLogger(appLogger), middleware.Recovery(appLogger, errorReporter), middleware.BuildVersion(buildinfo.Get().Version))
+	if cfg.TracingEnabled {
+		router.Use(telemetry.Middleware(cfg.TracingServiceName))
+	}
+	// After tracing, so the request-scoped logger carries the trace ID
+	router.Use(middleware.ScopeLogger(appLogger))
+	if appMetrics != nil {
+		router.Use(middleware.Metrics(appMetrics))
+		router.GET("/metrics", gin.WrapH(appMetrics.Handler()))
+		appLogger.Info("prometheus metrics enabled", "path", "/metrics")
+	}
+
+	apiSpec, err := openapi.Load()
+	if err != nil {
+		return nil, fmt.Errorf("unable to load OpenAPI spec: %w", err)
+	}
+	router.GET("/swagger/*any", openapi.Handler())
+	if cfg.AdminUIEnabled {
+		router.GET("/admin/ui/*any", adminui.Handler())
+		appLogger.Info("admin UI enabled", "path", "/admin/ui/")
+	}
+	// Installed even when off, so Reload can switch validation on
+	a.validation = middleware.NewValidationMode(cfg.RequestValidation)
+	validate, err := middleware.ValidateRequests(apiSpec, a.validation, appLogger)
+	if err != nil {
+		return nil, fmt.Errorf("unable to set up request validation: %w", err)
+	}
+	router.Use(validate)
+	if cfg.RequestValidation != middleware.ValidationOff {
+		appLogger.Info("OpenAPI request validation enabled", "mode", cfg.RequestValidation)
+	}
+
+	// Health check endpoints; /health is kept as an alias of the liveness
+	// probe for existing checks
+	healthHandler := productHttp.NewHealthHandler(checker, appLogger)
+	router.GET("/health", healthHandler.Live)
+	router.GET("/health/live", healthHandler.Live)
+	router.GET("/health/ready", healthHandler.Ready)
+	router.GET("/health/details", healthHandler.Details)
+
+	// Runtime introspection for operators, authenticated with its own key
+	// and kept outside the versioned API
+	if cfg.RuntimeAdminKey != "" {
+		runtimeHandler := productHttp.NewRuntimeHandler(a.config.Load, appLogger)
+		runtimeAdmin := router.Group("/admin", middleware.RequireAdminKey(cfg.RuntimeAdminKey))
+		{
+			runtimeAdmin.GET("/config", runtimeHandler.Config)
+			runtimeAdmin.GET("/build", runtimeHandler.Build)
+			runtimeAdmin.GET("/runtime", runtimeHandler.Stats)
+			runtimeAdmin.GET("/log-level", runtimeHandler.LogLevel)
+			runtimeAdmin.PUT("/log-level", runtimeHandler.SetLogLevel)
+		}
+	}
+
+	// API routes. Clients may also pick a version with an Accept header such
+	// as application/vnd.products.v2+json; routes whose responses did not
+	// change in v2 answer the same in both.
+	v1 := router.Group("/api/v1", middleware.IdentifyAdmin(cfg.AdminAPIKey), middleware.IdentifyTenant(cfg.TenantHeader), middleware.Version(middleware.APIVersion1))
+	if tokenVerifier != nil {
+		// Every route, reads included, acts for the tenant of the token
+		v1.Use(middleware.AuthenticateJWT(tokenVerifier), middleware.TenantFromClaims())
+	}
+	{
+		products := v1.Group("/products")
+		{
+			products.GET("", productHandler.List)
+			products.HEAD("", productHandler.Count)
+			products.GET("/count", productHandler.Count)
+			products.GET("/changes", changesHandler.Changes)
+			products.GET("/trending", viewHandler.Trending)
+			products.GET("/search", searchHandler.Search)
+			products.GET("/by-sku/:sku", productHandler.GetBySKU)
+			products.POST("/batch-get", productHandler.BatchGet)
+			products.GET("/:id", productHandler.Get)
+			products.POST("/:id/view", viewHandler.RecordView)
+			products.GET("/:id/recommendations", recommendationHandler.Recommendations)
+			products.GET("/:id/related", relatedHandler.Recommendations)
+			products.GET("/:id/stock", stockHandler.Get)
+			products.GET("/:id/reviews", reviewHandler.List)
+			products.GET("/:id/reviews/:reviewId", reviewHandler.Get)
+
+			writes := products.Group("")
+			if tokenVerifier != nil {
+				writes.Use(middleware.RequireJWT(tokenVerifier))
+			}
+			writes.POST("", allow(domain.ActionCreateProduct), productHandler.Create)
+			writes.POST("/:id/clone", allow(domain.ActionCreateProduct), productHandler.Clone)
+			writes.PUT("/:id", allow(domain.ActionUpdateProduct), productHandler.Update)
+			writes.PUT("/:id/translations/:locale", allow(domain.ActionUpdateProduct), productHandler.PutTranslation)
+			writes.DELETE("/:id", allow(domain.ActionDeleteProduct), productHandler.Delete)
+			writes.POST("/:id/stock/adjust", allow(domain.ActionUpdateProduct), stockHandler.Adjust)
+			writes.POST("/:id/publish", allow(domain.ActionUpdateProduct), lifecycleHandler.Publish)
+			writes.POST("/:id/archive", allow(domain.ActionUpdateProduct), lifecycleHandler.Archive)
+			writes.POST("/:id/discontinue", allow(domain.ActionUpdateProduct), lifecycleHandler.Discontinue)
+			writes.POST("/:id/reviews", allow(domain.ActionWriteReview), reviewHandler.Create)
+			writes.PUT("/:id/reviews/:reviewId", allow(domain.ActionWriteReview), reviewHandler.Update)
+			writes.DELETE("/:id/reviews/:reviewId", allow(domain.ActionWriteReview), reviewHandler.Delete)
+			// Favorites belong to the JWT subject, so without authentication
+			// they answer 401
+			writes.PUT("/:id/favorite", favoriteHandler.Add)
+			writes.DELETE("/:id/favorite", favoriteHandler.Remove)
+			// The history names who made each change, so it is only shown to
+			// the callers allowed to read it
+			writes.GET("/:id/audit", allow(domain.ActionReadAudit), auditHandler.History)
+			// A full export reads the whole table, so it is not left open
+			// to anonymous callers when authentication is enabled
+			writes.GET("/export", allow(domain.ActionReadProduct), exportHandler.Export)
+			writes.POST("/import", allow(domain.ActionCreateProduct), importHandler.Import)
+			writes.POST("/bulk-delete", allow(domain.ActionDeleteProduct), bulkDeleteHandler.BulkDelete)
+			// Suggestions are for whoever sets the price
+			if priceSuggestionHandler != nil {
+				writes.GET("/:id/price-suggestion", allow(domain.ActionUpdateProduct), priceSuggestionHandler.Suggest)
+			}
+			if imageHandler != nil {
+				writes.POST("/:id/images", allow(domain.ActionUpdateProduct), imageHandler.AddImage)
+			}
+		}
+
+		v1.GET("/tags", tagHandler.List)
+
+		me := v1.Group("/me")
+		{
+			if tokenVerifier != nil {
+				me.Use(middleware.RequireJWT(tokenVerifier))
+			}
+			me.GET("/favorites", favoriteHandler.List)
+		}
+
+		categories := v1.Group("/categories")
+		{
+			categories.GET("", categoryHandler.List)
+			categories.GET("/:id", categoryHandler.Get)
+
+			writes := categories.Group("")
+			if tokenVerifier != nil {
+				writes.Use(middleware.RequireJWT(tokenVerifier))
+			}
+			writes.Use(allow(domain.ActionManageCategories))
+			writes.POST("", categoryHandler.Create)
+			writes.PUT("/:id", categoryHandler.Update)
+			writes.DELETE("/:id", categoryHandler.Delete)
+		}
+
+		// Admin routes are only exposed when an admin key is configured
+		if cfg.AdminAPIKey != "" {
+			admin := v1.Group("/admin", middleware.RequireAdminKey(cfg.AdminAPIKey))
+			{
+				admin.POST("/query", adminHandler.Query)
+				admin.GET("/search-terms", adminHandler.SearchTerms)
+				admin.GET("/reports/margins", adminHandler.MarginReport)
+				admin.GET("/moderation", moderationHandler.Queue)
+				admin.POST("/moderation/:id/approve", moderationHandler.Approve)
+				admin.POST("/moderation/:id/reject", moderationHandler.Reject)
+				if coldStorageHandler != nil {
+					admin.POST("/cold-storage/:id/restore", coldStorageHandler.Restore)
+				}
+			}
+		}
+	}
+
+	// v2 changes the shape of product responses
+	v2 := router.Group("/api/v2", middleware.IdentifyAdmin(cfg.AdminAPIKey), middleware.IdentifyTenant(cfg.TenantHeader), middleware.Version(middleware.APIVersion2))
+	if tokenVerifier != nil {
+		// Every route, reads included, acts for the tenant of the token
+		v2.Use(middleware.AuthenticateJWT(tokenVerifier), middleware.TenantFromClaims())
+	}
+	{
+		products := v2.Group("/products")
+		{
+			products.GET("", productHandler.List)
+			products.GET("/by-sku/:sku", productHandler.GetBySKU)
+			products.GET("/:id", productHandler.Get)
+
+			writes := products.Group("")
+			if tokenVerifier != nil {
+				writes.Use(middleware.RequireJWT(tokenVerifier))
+			}
+			writes.POST("", allow(domain.ActionCreateProduct), productHandler.Create)
+			writes.POST("/:id/clone", allow(domain.ActionCreateProduct), productHandler.Clone)
+			writes.PUT("/:id", allow(domain.ActionUpdateProduct), productHandler.Update)
+			writes.PUT("/:id/translations/:locale", allow(domain.ActionUpdateProduct), productHandler.PutTranslation)
+			writes.DELETE("/:id", allow(domain.ActionDeleteProduct), productHandler.Delete)
+		}
+	}
+
+	// Background jobs, each run by a single elected instance
+	hostname, _ := os.Hostname()
+	jobLock := repository.NewDynamoDBLock(dbClient, cfg.LocksTable, hostname+"-"+uuid.NewString())
+	jobs := []job{
+		{"trending-rollup", cfg.TrendingRollupInterval, viewService.RollupTrending},
+		{"scheduled-publishing", cfg.PublishInterval, publishingService.PublishDue},
+		{"auto-archive", cfg.ArchiveInterval, archivingService.ArchiveDue},
+		{"margin-report", cfg.MarginReportInterval, reportService.GenerateMarginReport},
+		{"outbox-relay", cfg.OutboxRelayInterval, outboxService.RelayPending},
+	}
+	if coldStorageService != nil {
+		jobs = append(jobs, job{"cold-storage", cfg.ColdStorageInterval, coldStorageService.MoveArchived})
+	}
+	if cachedProducts != nil && cfg.CacheWarmProducts > 0 {
+		// The cache is shared, so one instance warms it for all of them;
+		// jobs run once at startup before their first interval
+		warmTenants := append([]string{""}, cfg.CacheWarmTenants...)
+		jobs = append(jobs, job{"cache-warm", cfg.CacheWarmInterval, func(ctx context.Context) error {
+			return cachedProducts.Warm(ctx, warmTenants, cfg.CacheWarmProducts)
+		}})
+	}
+	for _, j := range jobs {
+		// Jobs, cold storage and cache warming among them, yield DynamoDB
+		// capacity to requests; taking the lease does not, so the leader
+		// keeps it under load
+		run := j.run
+		j.run = scheduler.Leader(jobLock, j.name, j.interval, appLogger, func(ctx context.Context) error {
+			return run(ports.WithBackgroundPriority(ctx))
+		})
+		a.jobs = append(a.jobs, j)
+	}
+
+	return a, nil
+}
+
+// newDynamoDBClient applies the call timeout, the optional throttle and
+// metrics middleware and the error classification, returning the throttle
+// when it is enabled
+func newDynamoDBClient(cfg *appConfig.Config, awsCfg aws.Config, appMetrics *metrics.Metrics, appLogger *slog.Logger) (*dynamodb.Client, *repository.AdaptiveThrottle) {
+	var dbOptions []func(*dynamodb.Options)
+	if cfg.DynamoDBTimeout > 0 {
+		dbOptions = append(dbOptions, func(o *dynamodb.Options) {
+			o.APIOptions = append(o.APIOptions, repository.TimeoutAPIOption(cfg.DynamoDBTimeout))
+		})
+	}
+	var throttle *repository.AdaptiveThrottle
+	if cfg.ThrottleRate > 0 {
+		throttle = repository.NewAdaptiveThrottle(cfg.ThrottleRate, cfg.ThrottleMaxRate)
+		dbOptions = append(dbOptions, func(o *dynamodb.Options) {
+			o.APIOptions = append(o.APIOptions, throttle.APIOption)
+		})
+		appLogger.Info("adaptive DynamoDB throttle enabled", "rate", cfg.ThrottleRate, "max_rate", cfg.ThrottleMaxRate)
+	}
+	if appMetrics != nil {
+		dbOptions = append(dbOptions, func(o *dynamodb.Options) {
+			o.APIOptions = append(o.APIOptions, repository.MetricsAPIOption(appMetrics))
+		})
+	}
+	dbOptions = append(dbOptions, func(o *dynamodb.Options) {
+		o.APIOptions = append(o.APIOptions, repository.ErrorsAPIOption)
+	})
+	return dynamodb.NewFromConfig(awsCfg, dbOptions...), throttle
+}
+
+// RunJobs starts the background jobs; they stop when ctx is done, and
+// Close waits for the runs still in progress
+func (a *App) RunJobs(ctx context.Context) {
+	for _, j := range a.jobs {
+		a.running.Add(1)
+		go func() {
+			defer a.running.Done()
+			scheduler.Run(ctx, j.name, j.interval, a.logger, j.run)
+		}()
+	}
+}
+
+// Reload applies the settings of next that are safe to change while
+// serving: the log level, the DynamoDB throttle rates and the OpenAPI
+// validation mode. Other changed settings are logged as needing a restart.
+func (a *App) Reload(previous, next *appConfig.Config) {
+	var restart []string
+	for _, field := range appConfig.Changed(previous, next) {
+		switch field {
+		case "LogLevel":
+			logger.SetLevel(next.LogLevel)
+			productHttp.SetGinMode(next.LogLevel)
+		case "RequestValidation":
+			a.validation.Set(next.RequestValidation)
+		case "ThrottleRate", "ThrottleMaxRate":
+			// Turning the throttle on or off rebuilds the DynamoDB client
+			if a.throttle == nil || next.ThrottleRate <= 0 {
+				restart = append(restart, field)
+				continue
+			}
+			a.throttle.SetRates(next.ThrottleRate, next.ThrottleMaxRate)
+		default:
+			restart = append(restart, field)
+		}
+	}
+	a.config.Store(next)
+	a.logger.Info("configuration reloaded", "log_level", next.LogLevel,
+		"openapi_validation", next.RequestValidation, "throttle_rate", next.ThrottleRate, "throttle_max_rate", next.ThrottleMaxRate)
+	if len(restart) > 0 {
+		a.logger.Warn("changed settings take effect after a restart", "fields", restart)
+	}
+}
+
+// Close waits for the background jobs, whose context must be done by now,
+// then flushes buffered analytics events and notifications and releases
+// connections, in reverse order of creation
+func (a *App) Close(ctx context.Context) {
+	stopped := make(chan struct{})
+	go func() {
+		a.running.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		// Whatever they still track is dropped by the closed publishers
+		a.logger.Warn("background jobs still running at shutdown", "error", ctx.Err())
+	}
+	for i := len(a.closers) - 1; i >= 0; i-- {
+		if err := a.closers[i].close(ctx); err != nil {
+			a.logger.Warn(a.closers[i].failure, "error", err)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/dto"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// run executes productctl against server with a fresh profiles file,
+// returning what it printed
+func run(t *testing.T, server *httptest.Server, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	root := NewRootCommand(&out)
+	root.SetErr(io.Discard)
+	root.SetArgs(append([]string{"--config", filepath.Join(t.TempDir(), "config.yaml"), "--server", server.URL}, args...))
+	err := root.ExecuteContext(context.Background())
+	return out.String(), err
+}
+
+func serve(t *testing.T, mux *http.ServeMux) *httptest.Server {
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func storedProduct() dto.ProductResponse {
+	return dto.ProductResponse{
+		ID:          "p1",
+		Name:        "Coffee mug",
+		Description: "Holds coffee",
+		Price:       domain.Money{Amount: 1250, Currency: "EUR"},
+		Status:      "published",
+		CategoryID:  "kitchen",
+		Tags:        []string{"gift"},
+		SKU:         "MUG-1",
+		Version:     3,
+		CreatedAt:   time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		UpdatedAt:   time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestCreate(t *testing.T) {
+	var sent map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/products", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&sent))
+		w.Header().Set("ETag", `"1"`)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(storedProduct())
+	})
+	server := serve(t, mux)
+
+	out, err := run(t, server, "--token", "secret", "create", "--name", "Coffee mug", "--price", "12.50", "--currency", "EUR", "--tags", "gift,kitchen")
+	require.NoError(t, err)
+	assert.Equal(t, "Coffee mug", sent["name"])
+	assert.Equal(t, map[string]any{"amount": float64(1250), "currency": "EUR"}, sent["price"])
+	assert.Equal(t, []any{"gift", "kitchen"}, sent["tags"])
+	assert.NotContains(t, sent, "sku", "flags left out are not sent")
+	assert.Contains(t, out, "ID:")
+	assert.Contains(t, out, "Coffee mug")
+
+	t.Run("file and flags", func(t *testing.T) {
+		_, err := run(t, server, "create", "-f", "product.json", "--name", "Mug")
+		assert.ErrorContains(t, err, "--file takes the whole product")
+	})
+}
+
+func TestGet_JSON(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/products/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") != "p1" {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"error":"Product not found"}`)
+			return
+		}
+		json.NewEncoder(w).Encode(storedProduct())
+	})
+	server := serve(t, mux)
+
+	out, err := run(t, server, "get", "p1", "-o", "json")
+	require.NoError(t, err)
+	var product dto.ProductResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &product))
+	assert.Equal(t, storedProduct().ID, product.ID)
+	assert.Equal(t, storedProduct().Price, product.Price)
+
+	_, err = run(t, server, "get", "missing")
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.Status)
+	assert.Equal(t, "Product not found (status 404)", err.Error())
+}
+
+func TestUpdate(t *testing.T) {
+	var (
+		sent    ProductRequest
+		ifMatch string
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/products/p1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"3"`)
+		json.NewEncoder(w).Encode(storedProduct())
+	})
+	mux.HandleFunc("PUT /api/v1/products/p1", func(w http.ResponseWriter, r *http.Request) {
+		ifMatch, sent = r.Header.Get("If-Match"), ProductRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&sent))
+		json.NewEncoder(w).Encode(storedProduct())
+	})
+	server := serve(t, mux)
+
+	t.Run("changes only the flags given", func(t *testing.T) {
+		_, err := run(t, server, "update", "p1", "--price", "9.99", "--sku", "")
+		require.NoError(t, err)
+		assert.Equal(t, `"3"`, ifMatch)
+		assert.Equal(t, domain.Money{Amount: 999, Currency: "EUR"}, sent.Price, "the product's currency is kept")
+		assert.Equal(t, "Coffee mug", sent.Name)
+		assert.Equal(t, "kitchen", sent.CategoryID)
+		assert.Equal(t, []string{"gift"}, sent.Tags)
+		assert.Empty(t, sent.SKU, "an empty flag clears the field")
+	})
+
+	t.Run("stale version", func(t *testing.T) {
+		_, err := run(t, server, "update", "p1", "--name", "Mug", "--if-version", "2")
+		assert.ErrorContains(t, err, "is at version 3, not 2")
+	})
+
+	t.Run("file needs a version", func(t *testing.T) {
+		_, err := run(t, server, "update", "p1", "-f", "product.json")
+		assert.ErrorContains(t, err, "--file needs --if-version")
+	})
+
+	t.Run("file", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "product.json")
+		require.NoError(t, os.WriteFile(file, []byte(`{"name":"Tea cup","price":{"amount":500,"currency":"EUR"}}`), 0o600))
+		_, err := run(t, server, "update", "p1", "-f", file, "--if-version", "3")
+		require.NoError(t, err)
+		assert.Equal(t, `"3"`, ifMatch)
+		assert.Equal(t, "Tea cup", sent.Name)
+		assert.Empty(t, sent.Tags, "the file replaces the whole product")
+	})
+}
+
+func TestList(t *testing.T) {
+	var queries []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/products", func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		response := dto.ListProductsResponse{
+			Products:   []dto.ProductResponse{storedProduct()},
+			Pagination: dto.PaginationInfo{CurrentPage: 1, PerPage: 1, TotalPages: 2, TotalItems: 2, HasNext: true, NextCursor: "next"},
+		}
+		if r.URL.Query().Get("cursor") == "next" {
+			response.Products[0].ID = "p2"
+			response.Pagination = dto.PaginationInfo{CurrentPage: 2, PerPage: 1, TotalPages: 2, TotalItems: 2, HasPrev: true}
+		}
+		json.NewEncoder(w).Encode(response)
+	})
+	server := serve(t, mux)
+
+	t.Run("table", func(t *testing.T) {
+		queries = nil
+		out, err := run(t, server, "list", "--category", "kitchen", "--tags", "gift,sale", "--sort-by", "price", "--limit", "1")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"category_id=kitchen&limit=1&sort_by=price&tags=gift%2Csale"}, queries)
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		require.Len(t, lines, 2)
+		assert.True(t, strings.HasPrefix(lines[0], "ID"))
+		assert.Contains(t, lines[1], "12.50 EUR")
+	})
+
+	t.Run("all pages as JSON", func(t *testing.T) {
+		queries = nil
+		out, err := run(t, server, "list", "--all", "-o", "json")
+		require.NoError(t, err)
+		assert.Len(t, queries, 2)
+		var list dto.ListProductsResponse
+		require.NoError(t, json.Unmarshal([]byte(out), &list))
+		require.Len(t, list.Products, 2)
+		assert.Equal(t, "p2", list.Products[1].ID)
+		assert.False(t, list.Pagination.HasNext)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	var requests []*http.Request
+	mux := http.NewServeMux()
+	mux.HandleFunc("DELETE /api/v1/products/{id}", func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		if r.Header.Get("If-Match") == `"2"` {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			io.WriteString(w, `{"error":"Product was modified"}`)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	server := serve(t, mux)
+
+	out, err := run(t, server, "delete", "p1", "p2", "--replaced-by", "p3")
+	require.NoError(t, err)
+	assert.Equal(t, "Deleted p1\nDeleted p2\n", out)
+	require.Len(t, requests, 2)
+	assert.Equal(t, "p3", requests[0].URL.Query().Get("replaced_by"))
+	assert.Empty(t, requests[0].Header.Get("If-Match"))
+
+	_, err = run(t, server, "delete", "p1", "--if-version", "2")
+	assert.ErrorContains(t, err, "product p1: Product was modified (status 412)")
+}
+
+func TestImport(t *testing.T) {
+	var gotFile, gotFormat, gotDryRun string
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/products/import", func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("file")
+		require.NoError(t, err)
+		data, _ := io.ReadAll(file)
+		gotFile, gotFormat, gotDryRun = header.Filename+":"+string(data), r.FormValue("format"), r.FormValue("dry_run")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{"error": "Invalid format", "fields": []FieldError{{Field: "format", Message: "must be csv or ndjson"}}})
+	})
+	server := serve(t, mux)
+
+	file := filepath.Join(t.TempDir(), "products.csv")
+	require.NoError(t, os.WriteFile(file, []byte("name,price\nMug,12.50\n"), 0o600))
+	_, err := run(t, server, "import", file, "--dry-run", "--format", "xml")
+	assert.Equal(t, "products.csv:name,price\nMug,12.50\n", gotFile)
+	assert.Equal(t, "xml", gotFormat)
+	assert.Equal(t, "true", gotDryRun)
+	assert.EqualError(t, err, "Invalid format (status 400)\n  format: must be csv or ndjson")
+
+	mux = http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/products/import", func(w http.ResponseWriter, r *http.Request) {
+		summary := domain.ImportSummary{Imported: 1}
+		summary.Skip(2, errors.New("price must be positive"))
+		json.NewEncoder(w).Encode(summary)
+	})
+	out, err := run(t, serve(t, mux), "import", file)
+	require.NoError(t, err)
+	assert.Contains(t, out, "Imported: 1, skipped: 1\n")
+	assert.Contains(t, out, "price must be positive")
+}
+
+func TestExport(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/products/export", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "10", r.URL.Query().Get("min_price"))
+		io.WriteString(w, "id,name\np1,Coffee mug\n")
+	})
+	server := serve(t, mux)
+
+	out, err := run(t, server, "export", "--min-price", "10")
+	require.NoError(t, err)
+	assert.Equal(t, "id,name\np1,Coffee mug\n", out)
+
+	file := filepath.Join(t.TempDir(), "export.csv")
+	out, err = run(t, server, "export", "--min-price", "10", "-O", file)
+	require.NoError(t, err)
+	assert.Empty(t, out)
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, "id,name\np1,Coffee mug\n", string(data))
+}
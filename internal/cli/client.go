@@ -0,0 +1,296 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/dto"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// maxErrorSize bounds the error responses read
+const maxErrorSize = 64 << 10
+
+// APIError is a response of the API other than the one a command expects
+type APIError struct {
+	Status  int
+	Message string
+	// Fields are the per-field validation errors of a 400
+	Fields []FieldError
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (status %d)", e.Message, e.Status)
+	for _, field := range e.Fields {
+		if field.Field == "" {
+			fmt.Fprintf(&b, "\n  %s", field.Message)
+			continue
+		}
+		fmt.Fprintf(&b, "\n  %s: %s", field.Field, field.Message)
+	}
+	return b.String()
+}
+
+// Client calls the product API of one server
+type Client struct {
+	http    *http.Client
+	baseURL string
+	profile Profile
+}
+
+// NewClient calls the server of profile, giving each call up after timeout
+// unless it is zero
+func NewClient(profile Profile, timeout time.Duration) *Client {
+	return &Client{
+		http:    &http.Client{Timeout: timeout},
+		baseURL: strings.TrimSuffix(profile.Server, "/") + "/api/v1",
+		profile: profile,
+	}
+}
+
+// Product is a product as the API returns it, along with its ETag
+type Product struct {
+	dto.ProductResponse
+	// CostPrice is only returned to admins
+	CostPrice *domain.Money `json:"cost_price,omitempty"`
+	ETag      string        `json:"-"`
+}
+
+// ProductRequest is the body of creates and updates
+type ProductRequest struct {
+	Name          string          `json:"name"`
+	Description   string          `json:"description"`
+	Price         domain.Money    `json:"price"`
+	ExpiresAt     *time.Time      `json:"expires_at,omitempty"`
+	PublishAt     *time.Time      `json:"publish_at,omitempty"`
+	AutoArchiveAt *time.Time      `json:"auto_archive_at,omitempty"`
+	CostPrice     *domain.Decimal `json:"cost_price,omitempty"`
+	CategoryID    string          `json:"category_id,omitempty"`
+	Tags          []string        `json:"tags,omitempty"`
+	SKU           string          `json:"sku,omitempty"`
+	Barcode       string          `json:"barcode,omitempty"`
+}
+
+// requestOf is the update request that keeps everything of product
+func requestOf(product Product) ProductRequest {
+	return ProductRequest{
+		Name:          product.Name,
+		Description:   product.Description,
+		Price:         product.Price,
+		ExpiresAt:     product.ExpiresAt,
+		PublishAt:     product.PublishAt,
+		AutoArchiveAt: product.AutoArchiveAt,
+		CategoryID:    product.CategoryID,
+		Tags:          product.Tags,
+		SKU:           product.SKU,
+		Barcode:       product.Barcode,
+	}
+}
+
+func (c *Client) CreateProduct(ctx context.Context, body io.Reader) (Product, error) {
+	var product Product
+	response, err := c.do(ctx, http.MethodPost, "/products", nil, body, "application/json", http.StatusCreated)
+	if err != nil {
+		return product, err
+	}
+	return product, decodeProduct(response, &product)
+}
+
+func (c *Client) GetProduct(ctx context.Context, id string) (Product, error) {
+	var product Product
+	response, err := c.do(ctx, http.MethodGet, "/products/"+url.PathEscape(id), nil, nil, "", http.StatusOK)
+	if err != nil {
+		return product, err
+	}
+	return product, decodeProduct(response, &product)
+}
+
+// UpdateProduct replaces the product, as long as it still has etag
+func (c *Client) UpdateProduct(ctx context.Context, id, etag string, body io.Reader) (Product, error) {
+	var product Product
+	request, err := c.newRequest(ctx, http.MethodPut, "/products/"+url.PathEscape(id), nil, body)
+	if err != nil {
+		return product, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("If-Match", etag)
+	response, err := c.send(request, http.StatusOK)
+	if err != nil {
+		return product, err
+	}
+	return product, decodeProduct(response, &product)
+}
+
+// DeleteProduct deletes the product; a non-empty etag only deletes it
+// unchanged, and replacedBy sends those asking for it to another product
+func (c *Client) DeleteProduct(ctx context.Context, id, etag, replacedBy string) error {
+	query := url.Values{}
+	if replacedBy != "" {
+		query.Set("replaced_by", replacedBy)
+	}
+	request, err := c.newRequest(ctx, http.MethodDelete, "/products/"+url.PathEscape(id), query, nil)
+	if err != nil {
+		return err
+	}
+	if etag != "" {
+		request.Header.Set("If-Match", etag)
+	}
+	response, err := c.send(request, http.StatusNoContent)
+	if err != nil {
+		return err
+	}
+	return response.Body.Close()
+}
+
+func (c *Client) ListProducts(ctx context.Context, query url.Values) (dto.ListProductsResponse, error) {
+	var list dto.ListProductsResponse
+	response, err := c.do(ctx, http.MethodGet, "/products", query, nil, "", http.StatusOK)
+	if err != nil {
+		return list, err
+	}
+	defer response.Body.Close()
+	if err := json.NewDecoder(response.Body).Decode(&list); err != nil {
+		return list, fmt.Errorf("invalid response: %w", err)
+	}
+	return list, nil
+}
+
+// Import uploads a CSV or NDJSON file named filename
+func (c *Client) Import(ctx context.Context, filename, format string, file io.Reader, dryRun bool) (domain.ImportSummary, error) {
+	var summary domain.ImportSummary
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	if format != "" {
+		if err := form.WriteField("format", format); err != nil {
+			return summary, err
+		}
+	}
+	if err := form.WriteField("dry_run", strconv.FormatBool(dryRun)); err != nil {
+		return summary, err
+	}
+	part, err := form.CreateFormFile("file", filename)
+	if err != nil {
+		return summary, err
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return summary, err
+	}
+	if err := form.Close(); err != nil {
+		return summary, err
+	}
+
+	response, err := c.do(ctx, http.MethodPost, "/products/import", nil, &body, form.FormDataContentType(), http.StatusOK)
+	if err != nil {
+		return summary, err
+	}
+	defer response.Body.Close()
+	if err := json.NewDecoder(response.Body).Decode(&summary); err != nil {
+		return summary, fmt.Errorf("invalid response: %w", err)
+	}
+	return summary, nil
+}
+
+// Export copies the CSV export of the products matching query to w. It is
+// not bound by the client's timeout, since large catalogs take a while.
+func (c *Client) Export(ctx context.Context, query url.Values, w io.Writer) error {
+	request, err := c.newRequest(ctx, http.MethodGet, "/products/export", query, nil)
+	if err != nil {
+		return err
+	}
+	response, err := (&http.Client{Transport: c.http.Transport}).Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return responseError(response)
+	}
+	_, err = io.Copy(w, response.Body)
+	return err
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body io.Reader, contentType string, want int) (*http.Response, error) {
+	request, err := c.newRequest(ctx, method, path, query, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+	return c.send(request, want)
+}
+
+func (c *Client) newRequest(ctx context.Context, method, path string, query url.Values, body io.Reader) (*http.Request, error) {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	request, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	if c.profile.Token != "" {
+		request.Header.Set("Authorization", "Bearer "+c.profile.Token)
+	}
+	if c.profile.AdminKey != "" {
+		request.Header.Set("X-Admin-Key", c.profile.AdminKey)
+	}
+	if c.profile.Tenant != "" {
+		request.Header.Set(c.profile.tenantHeader(), c.profile.Tenant)
+	}
+	return request, nil
+}
+
+// send returns the response when it has status want, and its error
+// otherwise
+func (c *Client) send(request *http.Request, want int) (*http.Response, error) {
+	response, err := c.http.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != want {
+		defer response.Body.Close()
+		return nil, responseError(response)
+	}
+	return response, nil
+}
+
+func responseError(response *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(response.Body, maxErrorSize))
+	var decoded struct {
+		Error  string       `json:"error"`
+		Fields []FieldError `json:"fields"`
+	}
+	apiErr := &APIError{Status: response.StatusCode, Message: strings.TrimSpace(string(body))}
+	if json.Unmarshal(body, &decoded) == nil && decoded.Error != "" {
+		apiErr.Message, apiErr.Fields = decoded.Error, decoded.Fields
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(response.StatusCode)
+	}
+	return apiErr
+}
+
+func decodeProduct(response *http.Response, product *Product) error {
+	defer response.Body.Close()
+	if err := json.NewDecoder(response.Body).Decode(product); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	product.ETag = response.Header.Get("ETag")
+	return nil
+}
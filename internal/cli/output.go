@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/http/dto"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// Output formats
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+)
+
+// printer writes command results in the chosen format
+type printer struct {
+	out    io.Writer
+	format string
+}
+
+func (p printer) json(v any) error {
+	encoder := json.NewEncoder(p.out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// product prints one product, as a field per line in a table
+func (p printer) product(product Product) error {
+	if p.format == OutputJSON {
+		return p.json(product)
+	}
+	w := tabwriter.NewWriter(p.out, 0, 4, 2, ' ', 0)
+	row := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(w, "%s:\t%s\n", name, value)
+		}
+	}
+	row("ID", product.ID)
+	row("Name", product.Name)
+	row("Description", product.Description)
+	row("Price", product.Price.String())
+	if product.CostPrice != nil {
+		row("Cost price", product.CostPrice.String())
+	}
+	row("Status", product.Status)
+	row("Category", product.CategoryID)
+	row("Tags", strings.Join(product.Tags, ", "))
+	row("SKU", product.SKU)
+	row("Barcode", product.Barcode)
+	row("Stock", strconv.FormatInt(product.Stock, 10))
+	row("Version", strconv.FormatInt(product.Version, 10))
+	row("Publish at", formatTime(product.PublishAt))
+	row("Expires at", formatTime(product.ExpiresAt))
+	row("Created", product.CreatedAt.Format(time.RFC3339))
+	row("Updated", product.UpdatedAt.Format(time.RFC3339))
+	return w.Flush()
+}
+
+// products prints a product per row in a table
+func (p printer) products(products []dto.ProductResponse) error {
+	w := tabwriter.NewWriter(p.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tPRICE\tSTATUS\tSTOCK\tCATEGORY\tVERSION")
+	for _, product := range products {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%d\n",
+			product.ID, product.Name, product.Price.String(), product.Status, product.Stock, product.CategoryID, product.Version)
+	}
+	return w.Flush()
+}
+
+// importSummary prints how an import went, row by row for the rows skipped
+func (p printer) importSummary(summary domain.ImportSummary) error {
+	if p.format == OutputJSON {
+		return p.json(summary)
+	}
+	verb := "Imported"
+	if summary.DryRun {
+		verb = "Valid"
+	}
+	fmt.Fprintf(p.out, "%s: %d, skipped: %d\n", verb, summary.Imported, summary.Skipped)
+	if len(summary.Errors) == 0 {
+		return nil
+	}
+	w := tabwriter.NewWriter(p.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ROW\tERROR")
+	for _, rowErr := range summary.Errors {
+		fmt.Fprintf(w, "%d\t%s\n", rowErr.Row, rowErr.Error)
+	}
+	return w.Flush()
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
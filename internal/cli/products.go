@@ -0,0 +1,455 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// productFlags set the fields of a product on create and update, or name a
+// file with the whole JSON body instead
+type productFlags struct {
+	file        string
+	name        string
+	description string
+	price       string
+	currency    string
+	costPrice   string
+	category    string
+	tags        []string
+	sku         string
+	barcode     string
+	publishAt   string
+	expiresAt   string
+}
+
+// productFlagNames are the flags setting single fields
+var productFlagNames = []string{
+	"name", "description", "price", "currency", "cost-price", "category", "tags", "sku", "barcode", "publish-at", "expires-at",
+}
+
+func (f *productFlags) register(flags *pflag.FlagSet) {
+	flags.StringVarP(&f.file, "file", "f", "", `JSON body of the request, "-" for stdin, instead of the flags`)
+	flags.StringVar(&f.name, "name", "", "product name")
+	flags.StringVar(&f.description, "description", "", "product description")
+	flags.StringVar(&f.price, "price", "", "price as a decimal, such as 19.99")
+	flags.StringVar(&f.currency, "currency", "", "ISO 4217 currency of --price (default "+domain.DefaultCurrency+", or the product's on update)")
+	flags.StringVar(&f.costPrice, "cost-price", "", "cost price as a decimal in the currency of the price, admins only")
+	flags.StringVar(&f.category, "category", "", "category id")
+	flags.StringSliceVar(&f.tags, "tags", nil, "comma-separated tags")
+	flags.StringVar(&f.sku, "sku", "", "stock keeping unit")
+	flags.StringVar(&f.barcode, "barcode", "", "EAN-13 or UPC-A barcode")
+	flags.StringVar(&f.publishAt, "publish-at", "", "RFC 3339 time to publish the product at")
+	flags.StringVar(&f.expiresAt, "expires-at", "", "RFC 3339 time the product expires at")
+}
+
+// body is the request body: the file, or request with the flags given on
+// the command line applied
+func (f *productFlags) body(cmd *cobra.Command, request ProductRequest) (io.Reader, error) {
+	flags := cmd.Flags()
+	if f.file != "" {
+		for _, name := range productFlagNames {
+			if flags.Changed(name) {
+				return nil, errors.New("--file takes the whole product, leave the other product flags out")
+			}
+		}
+		if f.file == "-" {
+			return cmd.InOrStdin(), nil
+		}
+		return os.Open(f.file)
+	}
+
+	if flags.Changed("name") {
+		request.Name = f.name
+	}
+	if flags.Changed("description") {
+		request.Description = f.description
+	}
+	if flags.Changed("price") || flags.Changed("currency") {
+		currency := f.currency
+		if currency == "" {
+			currency = request.Price.Currency
+		}
+		if currency == "" {
+			currency = domain.DefaultCurrency
+		}
+		amount := f.price
+		if !flags.Changed("price") {
+			amount = request.Price.DecimalString()
+		}
+		price, err := domain.ParseMoney(amount, currency)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --price: %w", err)
+		}
+		request.Price = price
+	}
+	if flags.Changed("cost-price") {
+		cost, err := domain.ParseDecimal(f.costPrice)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --cost-price: %w", err)
+		}
+		request.CostPrice = &cost
+	}
+	if flags.Changed("category") {
+		request.CategoryID = f.category
+	}
+	if flags.Changed("tags") {
+		request.Tags = f.tags
+	}
+	if flags.Changed("sku") {
+		request.SKU = f.sku
+	}
+	if flags.Changed("barcode") {
+		request.Barcode = f.barcode
+	}
+	for name, target := range map[string]**time.Time{"publish-at": &request.PublishAt, "expires-at": &request.ExpiresAt} {
+		if !flags.Changed(name) {
+			continue
+		}
+		value, _ := flags.GetString(name)
+		if value == "" {
+			*target = nil
+			continue
+		}
+		at, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --%s, want an RFC 3339 time: %w", name, err)
+		}
+		*target = &at
+	}
+
+	data, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
+
+func newCreateCommand(s *session) *cobra.Command {
+	var flags productFlags
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a product",
+		Example: `  productctl create --name "Coffee mug" --price 12.50 --tags kitchen,gift
+  productctl create -f product.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			body, err := flags.body(cmd, ProductRequest{})
+			if err != nil {
+				return err
+			}
+			if closer, ok := body.(io.Closer); ok && body != cmd.InOrStdin() {
+				defer closer.Close()
+			}
+			product, err := s.client.CreateProduct(cmd.Context(), body)
+			if err != nil {
+				return err
+			}
+			return s.out.product(product)
+		},
+	}
+	flags.register(cmd.Flags())
+	return cmd
+}
+
+func newGetCommand(s *session) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <id>",
+		Short: "Show a product",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			product, err := s.client.GetProduct(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			return s.out.product(product)
+		},
+	}
+}
+
+// listFlags filter the products listed or exported
+type listFlags struct {
+	name      string
+	minPrice  string
+	maxPrice  string
+	category  string
+	tags      string
+	tagsMatch string
+	status    string
+}
+
+func (f *listFlags) register(flags *pflag.FlagSet) {
+	flags.StringVar(&f.name, "name", "", "only products whose name contains this")
+	flags.StringVar(&f.minPrice, "min-price", "", "lowest price")
+	flags.StringVar(&f.maxPrice, "max-price", "", "highest price")
+	flags.StringVar(&f.category, "category", "", "only products of this category id")
+}
+
+func (f *listFlags) query() url.Values {
+	query := url.Values{}
+	for key, value := range map[string]string{
+		"name":        f.name,
+		"min_price":   f.minPrice,
+		"max_price":   f.maxPrice,
+		"category_id": f.category,
+		"tags":        f.tags,
+		"tags_match":  f.tagsMatch,
+		"status":      f.status,
+	} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	return query
+}
+
+func newListCommand(s *session) *cobra.Command {
+	var (
+		filters   listFlags
+		sortBy    string
+		sortOrder string
+		page      int
+		limit     int
+		cursor    string
+		all       bool
+	)
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List products",
+		Example: `  productctl list --category books --sort-by price --limit 50
+  productctl list --tags sale,new --tags-match all --all -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := filters.query()
+			for key, value := range map[string]string{"sort_by": sortBy, "sort_order": sortOrder, "cursor": cursor} {
+				if value != "" {
+					query.Set(key, value)
+				}
+			}
+			if page > 0 {
+				query.Set("page", strconv.Itoa(page))
+			}
+			if limit > 0 {
+				query.Set("limit", strconv.Itoa(limit))
+			}
+
+			list, err := s.client.ListProducts(cmd.Context(), query)
+			if err != nil {
+				return err
+			}
+			// --all follows the pages, by cursor when the server hands one
+			for all && list.Pagination.HasNext {
+				if list.Pagination.NextCursor != "" {
+					query.Set("cursor", list.Pagination.NextCursor)
+					query.Del("page")
+				} else {
+					query.Set("page", strconv.Itoa(list.Pagination.CurrentPage+1))
+				}
+				next, err := s.client.ListProducts(cmd.Context(), query)
+				if err != nil {
+					return err
+				}
+				list.Products = append(list.Products, next.Products...)
+				list.Pagination = next.Pagination
+			}
+
+			if s.out.format == OutputJSON {
+				return s.out.json(list)
+			}
+			if err := s.out.products(list.Products); err != nil {
+				return err
+			}
+			// Pagination goes to stderr, so the table can be piped
+			pagination := list.Pagination
+			fmt.Fprintf(cmd.ErrOrStderr(), "page %d of %d, %d products\n", pagination.CurrentPage, pagination.TotalPages, pagination.TotalItems)
+			if !all && pagination.NextCursor != "" {
+				fmt.Fprintf(cmd.ErrOrStderr(), "next page: --cursor %s\n", pagination.NextCursor)
+			}
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	filters.register(flags)
+	flags.StringVar(&filters.tags, "tags", "", "comma-separated tags")
+	flags.StringVar(&filters.tagsMatch, "tags-match", "", "any or all of --tags (default any)")
+	flags.StringVar(&filters.status, "status", "", "draft, published (or active), archived or discontinued")
+	flags.StringVar(&sortBy, "sort-by", "", "name, price, created_at or updated_at")
+	flags.StringVar(&sortOrder, "sort-order", "", "asc or desc")
+	flags.IntVar(&page, "page", 0, "page to show")
+	flags.IntVar(&limit, "limit", 0, "products per page, up to 100")
+	flags.StringVar(&cursor, "cursor", "", "cursor of the page to show, from a previous listing")
+	flags.BoolVar(&all, "all", false, "list every page")
+	return cmd
+}
+
+func newUpdateCommand(s *session) *cobra.Command {
+	var flags productFlags
+	var version int64
+	cmd := &cobra.Command{
+		Use:   "update <id>",
+		Short: "Update a product",
+		Long: `Update a product. Only the fields given by flags change; the rest are kept as
+the server returns them, and the update fails if someone else changes the
+product in between. With --file the body replaces the whole product.`,
+		Example: `  productctl update 2f1c --price 9.99 --tags sale
+  productctl update 2f1c -f product.json --if-version 4`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id := args[0]
+			etag := versionETag(version)
+			var request ProductRequest
+			if flags.file == "" {
+				product, err := s.client.GetProduct(cmd.Context(), id)
+				if err != nil {
+					return err
+				}
+				if version != 0 && version != product.Version {
+					return fmt.Errorf("product %s is at version %d, not %d", id, product.Version, version)
+				}
+				etag, request = product.ETag, requestOf(product)
+			} else if etag == "" {
+				return errors.New("--file needs --if-version, the version the body was based on")
+			}
+
+			body, err := flags.body(cmd, request)
+			if err != nil {
+				return err
+			}
+			if closer, ok := body.(io.Closer); ok && body != cmd.InOrStdin() {
+				defer closer.Close()
+			}
+			product, err := s.client.UpdateProduct(cmd.Context(), id, etag, body)
+			if err != nil {
+				return err
+			}
+			return s.out.product(product)
+		},
+	}
+	flags.register(cmd.Flags())
+	cmd.Flags().Int64Var(&version, "if-version", 0, "only update the product at this version")
+	return cmd
+}
+
+func newDeleteCommand(s *session) *cobra.Command {
+	var (
+		version    int64
+		replacedBy string
+	)
+	cmd := &cobra.Command{
+		Use:   "delete <id>...",
+		Short: "Delete products",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if version != 0 && len(args) > 1 {
+				return errors.New("--if-version takes a single product")
+			}
+			for _, id := range args {
+				if err := s.client.DeleteProduct(cmd.Context(), id, versionETag(version), replacedBy); err != nil {
+					return fmt.Errorf("product %s: %w", id, err)
+				}
+				if s.out.format == OutputTable {
+					fmt.Fprintf(s.out.out, "Deleted %s\n", id)
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().Int64Var(&version, "if-version", 0, "only delete the product at this version")
+	cmd.Flags().StringVar(&replacedBy, "replaced-by", "", "id of the product that replaces it")
+	return cmd
+}
+
+func newImportCommand(s *session) *cobra.Command {
+	var (
+		format string
+		dryRun bool
+	)
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import products from a CSV or NDJSON file",
+		Long: `Import products from a CSV or NDJSON file, "-" for stdin. The format is
+taken from the file extension unless --format is given. Rows that fail are
+reported and skipped, the rest are imported.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			var file io.Reader = cmd.InOrStdin()
+			if name == "-" {
+				if format == "" {
+					return errors.New("importing from stdin needs --format")
+				}
+				name = "stdin." + format
+			} else {
+				opened, err := os.Open(name)
+				if err != nil {
+					return err
+				}
+				defer opened.Close()
+				file, name = opened, filepath.Base(name)
+			}
+			summary, err := s.client.Import(cmd.Context(), name, format, file, dryRun)
+			if err != nil {
+				return err
+			}
+			return s.out.importSummary(summary)
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "", "csv or ndjson")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "validate the file without importing it")
+	return cmd
+}
+
+func newExportCommand(s *session) *cobra.Command {
+	var (
+		filters listFlags
+		file    string
+	)
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export products as CSV",
+		Long: `Export the products matching the filters as CSV, the format import takes
+back. The export is written to stdout unless --output-file is given, and is
+not bound by --timeout.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			w := cmd.OutOrStdout()
+			if file != "" {
+				created, err := os.Create(file)
+				if err != nil {
+					return err
+				}
+				defer created.Close()
+				w = created
+			}
+			if err := s.client.Export(cmd.Context(), filters.query(), w); err != nil {
+				return err
+			}
+			if closer, ok := w.(io.Closer); ok {
+				return closer.Close()
+			}
+			return nil
+		},
+	}
+	filters.register(cmd.Flags())
+	cmd.Flags().StringVarP(&file, "output-file", "O", "", "file to write the export to")
+	return cmd
+}
+
+// versionETag is the If-Match of a product at version, none for zero
+func versionETag(version int64) string {
+	if version == 0 {
+		return ""
+	}
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
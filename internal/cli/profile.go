@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultProfile is used while no other profile was picked
+const DefaultProfile = "default"
+
+// Profile is how to reach one server
+type Profile struct {
+	Server   string `yaml:"server"`
+	Token    string `yaml:"token,omitempty"`
+	AdminKey string `yaml:"admin_key,omitempty"`
+	Tenant   string `yaml:"tenant,omitempty"`
+	// TenantHeader is the server's TENANT_HEADER, X-Tenant-ID by default
+	TenantHeader string `yaml:"tenant_header,omitempty"`
+	// Output is the format commands print in unless told otherwise
+	Output string `yaml:"output,omitempty"`
+}
+
+func (p Profile) tenantHeader() string {
+	if p.TenantHeader == "" {
+		return "X-Tenant-ID"
+	}
+	return p.TenantHeader
+}
+
+// Config is the profiles file, YAML at ConfigPath
+type Config struct {
+	Current  string             `yaml:"current,omitempty"`
+	Profiles map[string]Profile `yaml:"profiles"`
+}
+
+// ConfigPath is where profiles are kept: PRODUCTCTL_CONFIG, or
+// productctl/config.yaml in the user's configuration directory
+func ConfigPath() (string, error) {
+	if path := os.Getenv("PRODUCTCTL_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "productctl", "config.yaml"), nil
+}
+
+// LoadConfig reads the profiles at path; a missing file has none
+func LoadConfig(path string) (Config, error) {
+	config := Config{Profiles: map[string]Profile{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return config, fmt.Errorf("failed to read profiles: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("failed to parse profiles %s: %w", path, err)
+	}
+	if config.Profiles == nil {
+		config.Profiles = map[string]Profile{}
+	}
+	return config, nil
+}
+
+// Save writes the profiles to path, readable by the user alone since they
+// hold tokens
+func (c Config) Save(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to save profiles: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to save profiles: %w", err)
+	}
+	return nil
+}
+
+// Profile returns the profile named name, or the current one when name is
+// empty. Only the default profile may be missing, reaching a local server.
+func (c Config) Profile(name string) (Profile, error) {
+	if name == "" {
+		name = c.Current
+	}
+	if name == "" {
+		name = DefaultProfile
+	}
+	profile, ok := c.Profiles[name]
+	if !ok {
+		if name != DefaultProfile {
+			return Profile{}, fmt.Errorf("no profile named %q", name)
+		}
+		profile = Profile{Server: "http://localhost:8080"}
+	}
+	return profile, nil
+}
+
+// Names lists the profiles in order
+func (c Config) Names() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
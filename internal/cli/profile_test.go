@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Profile(t *testing.T) {
+	config := Config{Profiles: map[string]Profile{"staging": {Server: "https://staging.example.com"}}}
+
+	profile, err := config.Profile("")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080", profile.Server, "a missing default reaches a local server")
+
+	config.Current = "staging"
+	profile, err = config.Profile("")
+	require.NoError(t, err)
+	assert.Equal(t, "https://staging.example.com", profile.Server)
+
+	_, err = config.Profile("prod")
+	assert.EqualError(t, err, `no profile named "prod"`)
+}
+
+func TestLoadConfig_Missing(t *testing.T) {
+	config, err := LoadConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.NoError(t, err)
+	assert.Empty(t, config.Profiles)
+}
+
+func TestProfileCommands(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "productctl", "config.yaml")
+	productctl := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		root := NewRootCommand(&out)
+		root.SetArgs(append([]string{"--config", path}, args...))
+		err := root.ExecuteContext(context.Background())
+		return out.String(), err
+	}
+
+	_, err := productctl("profile", "set", "staging", "--token", "secret")
+	assert.ErrorContains(t, err, "needs --server")
+
+	_, err = productctl("profile", "set", "staging", "--server", "https://staging.example.com", "--token", "secret", "--tenant", "acme")
+	require.NoError(t, err)
+	_, err = productctl("profile", "set", "staging", "--tenant", "globex", "--use")
+	require.NoError(t, err)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm(), "profiles hold tokens")
+	config, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, "staging", config.Current)
+	assert.Equal(t, Profile{Server: "https://staging.example.com", Token: "secret", Tenant: "globex"}, config.Profiles["staging"],
+		"settings left out are kept")
+
+	out, err := productctl("profile", "list")
+	require.NoError(t, err)
+	assert.Contains(t, out, "*        staging  https://staging.example.com  globex")
+
+	_, err = productctl("profile", "use", "prod")
+	assert.EqualError(t, err, `no profile named "prod"`)
+
+	_, err = productctl("profile", "delete", "staging")
+	require.NoError(t, err)
+	config, err = LoadConfig(path)
+	require.NoError(t, err)
+	assert.Empty(t, config.Profiles)
+	assert.Empty(t, config.Current)
+}
@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+func newProfileCommand(s *session) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Manage the servers productctl talks to",
+	}
+	cmd.AddCommand(newProfileListCommand(s), newProfileSetCommand(s), newProfileUseCommand(s), newProfileDeleteCommand(s))
+	return cmd
+}
+
+func newProfileListCommand(s *session) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the profiles, marking the current one",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, config, err := s.config()
+			if err != nil {
+				return err
+			}
+			current := config.Current
+			if current == "" {
+				current = DefaultProfile
+			}
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "CURRENT\tNAME\tSERVER\tTENANT")
+			for _, name := range config.Names() {
+				mark := ""
+				if name == current {
+					mark = "*"
+				}
+				profile := config.Profiles[name]
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", mark, name, profile.Server, profile.Tenant)
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func newProfileSetCommand(s *session) *cobra.Command {
+	var (
+		profile Profile
+		use     bool
+	)
+	cmd := &cobra.Command{
+		Use:   "set <name>",
+		Short: "Create a profile or change some of its settings",
+		Example: `  productctl profile set staging --server https://staging.example.com --token "$TOKEN" --use
+  productctl profile set staging --tenant acme`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, config, err := s.config()
+			if err != nil {
+				return err
+			}
+			name := args[0]
+			existing, ok := config.Profiles[name]
+			if !ok && profile.Server == "" {
+				return fmt.Errorf("profile %q is new, it needs --server", name)
+			}
+			flags := cmd.Flags()
+			for flag, field := range map[string]*string{
+				"server":        &existing.Server,
+				"token":         &existing.Token,
+				"admin-key":     &existing.AdminKey,
+				"tenant":        &existing.Tenant,
+				"tenant-header": &existing.TenantHeader,
+				"output":        &existing.Output,
+			} {
+				if flags.Changed(flag) {
+					*field, _ = flags.GetString(flag)
+				}
+			}
+			switch existing.Output {
+			case "", OutputTable, OutputJSON:
+			default:
+				return fmt.Errorf("unknown output format %q, want table or json", existing.Output)
+			}
+			config.Profiles[name] = existing
+			if use {
+				config.Current = name
+			}
+			return config.Save(path)
+		},
+	}
+	// These shadow the global --server, --token and --output, which only
+	// override the profile for one command
+	flags := cmd.Flags()
+	flags.StringVar(&profile.Server, "server", "", "base URL of the API, such as https://api.example.com")
+	flags.StringVar(&profile.Token, "token", "", "bearer token")
+	flags.StringVar(&profile.AdminKey, "admin-key", "", "X-Admin-Key for admin endpoints and fields")
+	flags.StringVar(&profile.Tenant, "tenant", "", "tenant to send requests as")
+	flags.StringVar(&profile.TenantHeader, "tenant-header", "", "header carrying the tenant (default X-Tenant-ID)")
+	flags.StringVarP(&profile.Output, "output", "o", "", "default output format: table or json")
+	flags.BoolVar(&use, "use", false, "make it the current profile")
+	return cmd
+}
+
+func newProfileUseCommand(s *session) *cobra.Command {
+	return &cobra.Command{
+		Use:   "use <name>",
+		Short: "Make a profile the current one",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, config, err := s.config()
+			if err != nil {
+				return err
+			}
+			if _, ok := config.Profiles[args[0]]; !ok {
+				return fmt.Errorf("no profile named %q", args[0])
+			}
+			config.Current = args[0]
+			return config.Save(path)
+		},
+	}
+}
+
+func newProfileDeleteCommand(s *session) *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, config, err := s.config()
+			if err != nil {
+				return err
+			}
+			if _, ok := config.Profiles[args[0]]; !ok {
+				return fmt.Errorf("no profile named %q", args[0])
+			}
+			delete(config.Profiles, args[0])
+			if config.Current == args[0] {
+				config.Current = ""
+			}
+			return config.Save(path)
+		},
+	}
+}
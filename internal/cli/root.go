@@ -0,0 +1,123 @@
+// Package cli is productctl, a command line client of the product API.
+// Commands reach the server of a profile kept in a YAML file, and print
+// tables for people or JSON for scripts.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/buildinfo"
+)
+
+// session is what every command runs with, resolved from the flags and
+// the profile before it runs
+type session struct {
+	configPath  string
+	profileName string
+	server      string
+	token       string
+	output      string
+	timeout     time.Duration
+
+	client *Client
+	out    printer
+}
+
+// NewRootCommand builds productctl, writing results to out
+func NewRootCommand(out io.Writer) *cobra.Command {
+	s := &session{}
+	root := &cobra.Command{
+		Use:           "productctl",
+		Short:         "Manage products through the product API",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Version:       buildinfo.Get().Version,
+	}
+	root.SetOut(out)
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&s.configPath, "config", "", "profiles file (default $PRODUCTCTL_CONFIG or productctl/config.yaml in the user config directory)")
+	flags.StringVar(&s.profileName, "profile", os.Getenv("PRODUCTCTL_PROFILE"), "profile to use instead of the current one")
+	flags.StringVar(&s.server, "server", "", "base URL of the API, overriding the profile's")
+	flags.StringVar(&s.token, "token", "", "bearer token, overriding the profile's and $PRODUCTCTL_TOKEN")
+	flags.StringVarP(&s.output, "output", "o", "", "output format: table or json (default the profile's, or table)")
+	flags.DurationVar(&s.timeout, "timeout", 30*time.Second, "timeout of each API call")
+
+	// Commands reaching the API resolve the profile first
+	connect := func(cmd *cobra.Command, args []string) error {
+		return s.connect(cmd.OutOrStdout())
+	}
+	for _, command := range []*cobra.Command{
+		newCreateCommand(s), newGetCommand(s), newListCommand(s), newUpdateCommand(s),
+		newDeleteCommand(s), newImportCommand(s), newExportCommand(s),
+	} {
+		command.PreRunE = connect
+		root.AddCommand(command)
+	}
+	root.AddCommand(newProfileCommand(s))
+	return root
+}
+
+// Execute runs productctl with the process arguments, returning its exit
+// code
+func Execute(ctx context.Context) int {
+	root := NewRootCommand(os.Stdout)
+	if err := root.ExecuteContext(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	return 0
+}
+
+// config returns the path of the profiles file and its profiles
+func (s *session) config() (string, Config, error) {
+	path := s.configPath
+	if path == "" {
+		var err error
+		if path, err = ConfigPath(); err != nil {
+			return "", Config{}, err
+		}
+	}
+	config, err := LoadConfig(path)
+	return path, config, err
+}
+
+func (s *session) connect(out io.Writer) error {
+	_, config, err := s.config()
+	if err != nil {
+		return err
+	}
+	profile, err := config.Profile(s.profileName)
+	if err != nil {
+		return err
+	}
+	if token := os.Getenv("PRODUCTCTL_TOKEN"); token != "" {
+		profile.Token = token
+	}
+	if s.token != "" {
+		profile.Token = s.token
+	}
+	if s.server != "" {
+		profile.Server = s.server
+	}
+	format := s.output
+	if format == "" {
+		format = profile.Output
+	}
+	switch format {
+	case "":
+		format = OutputTable
+	case OutputTable, OutputJSON:
+	default:
+		return fmt.Errorf("unknown output format %q, want table or json", format)
+	}
+
+	s.client = NewClient(profile, s.timeout)
+	s.out = printer{out: out, format: format}
+	return nil
+}
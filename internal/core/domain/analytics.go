@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"time"
+)
+
+// Usage event types sent to the analytics pipeline
+const (
+	EventProductViewed  = "product.viewed"
+	EventProductsSearch = "products.searched"
+	EventProductsListed = "products.listed"
+)
+
+// Lifecycle event types
+const (
+	EventProductCreated        = "product.created"
+	EventProductFlagged        = "product.moderation_flagged"
+	EventProductRejected       = "product.moderation_rejected"
+	EventProductPublished      = "product.published"
+	EventProductArchiveWarning = "product.archive_warning"
+	EventProductArchived       = "product.archived"
+	EventProductDiscontinued   = "product.discontinued"
+	EventProductExpired        = "product.expired"
+)
+
+// AnalyticsEvent is a behavioral event describing how the catalog is used
+type AnalyticsEvent struct {
+	Type       string                 `json:"type"`
+	ProductID  string                 `json:"product_id,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+	OccurredAt time.Time              `json:"occurred_at"`
+}
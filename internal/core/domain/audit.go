@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audited write operations
+const (
+	AuditCreate = "create"
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+)
+
+// ActorAnonymous is recorded for writes made without an authenticated caller
+const ActorAnonymous = "anonymous"
+
+// FieldChange is one product attribute changed by a write, with its JSON
+// values before and after it; a missing value means the field was unset
+type FieldChange struct {
+	Field  string          `json:"field"`
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+	// Redacted changes are confidential fields, such as the cost price,
+	// recorded without their values
+	Redacted bool `json:"redacted,omitempty"`
+}
+
+// AuditEntry records who changed a product, when, and what changed
+type AuditEntry struct {
+	ID         string        `json:"id"`
+	ProductID  string        `json:"product_id"`
+	TenantID   string        `json:"tenant_id,omitempty"`
+	Action     string        `json:"action"`
+	Actor      string        `json:"actor"`
+	OccurredAt time.Time     `json:"occurred_at"`
+	Changes    []FieldChange `json:"changes"`
+}
+
+// NewAuditEntry records a write that turned before into after. before is
+// nil for creations and after is nil for deletions.
+func NewAuditEntry(action, actor string, before, after *Product, occurredAt time.Time) (AuditEntry, error) {
+	subject := after
+	if subject == nil {
+		subject = before
+	}
+	if subject == nil {
+		return AuditEntry{}, fmt.Errorf("audit entry for %s has no product", action)
+	}
+	if actor == "" {
+		actor = ActorAnonymous
+	}
+
+	changes, err := DiffProducts(before, after)
+	if err != nil {
+		return AuditEntry{}, err
+	}
+	return AuditEntry{
+		ID:         uuid.New().String(),
+		ProductID:  subject.ID,
+		TenantID:   subject.TenantID,
+		Action:     action,
+		Actor:      actor,
+		OccurredAt: occurredAt.UTC(),
+		Changes:    changes,
+	}, nil
+}
+
+// DiffProducts lists the fields that differ between before and after, by
+// JSON name in alphabetical order. Either may be nil.
+func DiffProducts(before, after *Product) ([]FieldChange, error) {
+	beforeFields, err := productFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := productFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(beforeFields)+len(afterFields))
+	for name := range beforeFields {
+		names = append(names, name)
+	}
+	for name := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	changes := []FieldChange{}
+	for _, name := range names {
+		if !bytes.Equal(beforeFields[name], afterFields[name]) {
+			changes = append(changes, FieldChange{Field: name, Before: beforeFields[name], After: afterFields[name]})
+		}
+	}
+	// The cost price is never serialized, so only the fact that it changed
+	// is kept
+	if !sameCost(before, after) {
+		changes = append(changes, FieldChange{Field: "cost_price", Redacted: true})
+	}
+	return changes, nil
+}
+
+func productFields(product *Product) (map[string]json.RawMessage, error) {
+	fields := map[string]json.RawMessage{}
+	if product == nil {
+		return fields, nil
+	}
+	data, err := json.Marshal(product)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode product %s: %w", product.ID, err)
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to decode product %s: %w", product.ID, err)
+	}
+	return fields, nil
+}
+
+func sameCost(before, after *Product) bool {
+	var from, to *Money
+	if before != nil {
+		from = before.CostPrice
+	}
+	if after != nil {
+		to = after.CostPrice
+	}
+	if from == nil || to == nil {
+		return from == to
+	}
+	return *from == *to
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffProducts(t *testing.T) {
+	before, err := NewProduct(testID, "Laptop", "Gaming laptop", Money{Amount: 99900, Currency: "USD"})
+	require.NoError(t, err)
+	after := *before
+	after.Price = Money{Amount: 89900, Currency: "USD"}
+	after.CategoryID = "electronics"
+	cost := Money{Amount: 50000, Currency: "USD"}
+	after.CostPrice = &cost
+
+	changes, err := DiffProducts(before, &after)
+	require.NoError(t, err)
+	assert.Equal(t, []FieldChange{
+		{Field: "category_id", After: json.RawMessage(`"electronics"`)},
+		{Field: "price", Before: json.RawMessage(`{"amount":99900,"currency":"USD"}`), After: json.RawMessage(`{"amount":89900,"currency":"USD"}`)},
+		{Field: "cost_price", Redacted: true},
+	}, changes)
+
+	unchanged, err := DiffProducts(before, before)
+	require.NoError(t, err)
+	assert.Empty(t, unchanged)
+}
+
+func TestNewAuditEntry(t *testing.T) {
+	product, err := NewProduct(testID, "Laptop", "", Money{Amount: 99900, Currency: "USD"})
+	require.NoError(t, err)
+	product.TenantID = "acme"
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	entry, err := NewAuditEntry(AuditDelete, "", product, nil, now)
+	require.NoError(t, err)
+	assert.Equal(t, product.ID, entry.ProductID)
+	assert.Equal(t, "acme", entry.TenantID)
+	assert.Equal(t, ActorAnonymous, entry.Actor)
+	assert.Equal(t, now, entry.OccurredAt)
+	for _, change := range entry.Changes {
+		assert.Nil(t, change.After, change.Field)
+	}
+
+	_, err = NewAuditEntry(AuditCreate, "user-1", nil, nil, now)
+	assert.Error(t, err)
+}
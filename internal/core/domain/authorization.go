@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"errors"
+)
+
+// ErrForbidden is returned when a principal lacks the role for an action
+var ErrForbidden = errors.New("not allowed to perform this action")
+
+// Roles granted to principals by the identity provider
+const (
+	RoleAdmin  = "admin"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
+// Actions on products that routes are authorized against
+const (
+	ActionReadProduct   = "products:read"
+	ActionCreateProduct = "products:create"
+	ActionUpdateProduct = "products:update"
+	ActionDeleteProduct = "products:delete"
+	// ActionReadAudit covers reading who changed a product and how
+	ActionReadAudit = "products:audit"
+	// ActionManageCategories covers creating, renaming and deleting categories
+	ActionManageCategories = "categories:write"
+	// ActionWriteReview covers reviewing products; only the author of a
+	// review may change it afterwards
+	ActionWriteReview = "reviews:write"
+)
+
+// Principal is the authenticated caller an action is authorized for
+type Principal struct {
+	Subject string
+	Roles   []string
+}
+
+// Policy lists the actions each role may perform
+type Policy map[string][]string
+
+// DefaultPolicy lets viewers read and review products, editors also create
+// and update products, manage categories and read the audit history, and
+// admins also delete products
+func DefaultPolicy() Policy {
+	return Policy{
+		RoleViewer: {ActionReadProduct, ActionWriteReview},
+		RoleEditor: {ActionReadProduct, ActionCreateProduct, ActionUpdateProduct, ActionManageCategories, ActionReadAudit, ActionWriteReview},
+		RoleAdmin:  {ActionReadProduct, ActionCreateProduct, ActionUpdateProduct, ActionDeleteProduct, ActionManageCategories, ActionReadAudit, ActionWriteReview},
+	}
+}
+
+// Allows reports whether any of roles grants action
+func (p Policy) Allows(roles []string, action string) bool {
+	for _, role := range roles {
+		for _, allowed := range p[role] {
+			if allowed == action {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultPolicy(t *testing.T) {
+	policy := DefaultPolicy()
+
+	tests := []struct {
+		roles   []string
+		action  string
+		allowed bool
+	}{
+		{[]string{RoleViewer}, ActionReadProduct, true},
+		{[]string{RoleViewer}, ActionCreateProduct, false},
+		{[]string{RoleEditor}, ActionUpdateProduct, true},
+		{[]string{RoleEditor}, ActionDeleteProduct, false},
+		{[]string{RoleViewer, RoleAdmin}, ActionDeleteProduct, true},
+		{[]string{RoleEditor}, ActionManageCategories, true},
+		{[]string{RoleViewer}, ActionManageCategories, false},
+		{[]string{RoleEditor}, ActionReadAudit, true},
+		{[]string{RoleViewer}, ActionReadAudit, false},
+		{[]string{RoleViewer}, ActionWriteReview, true},
+		{[]string{"unknown"}, ActionReadProduct, false},
+		{nil, ActionReadProduct, false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.allowed, policy.Allows(tt.roles, tt.action), "%v %s", tt.roles, tt.action)
+	}
+}
@@ -0,0 +1,35 @@
+package domain
+
+import "fmt"
+
+// MaxBulkDelete bounds the products one bulk delete may remove
+const MaxBulkDelete = 1000
+
+var (
+	// ErrBulkDeleteTooLarge is returned when the selection of a bulk delete
+	// matches more than MaxBulkDelete products
+	ErrBulkDeleteTooLarge = NewError(KindValidation, fmt.Sprintf("a bulk delete cannot remove more than %d products, narrow the selection", MaxBulkDelete))
+	// ErrBulkDeleteChanged is returned when a confirmed bulk delete no
+	// longer matches the products its dry run previewed
+	ErrBulkDeleteChanged = NewError(KindConflict, "the products selected changed since the dry run")
+	// ErrConfirmationRequired is returned by a bulk delete that is not a dry
+	// run and does not carry the token of one
+	ErrConfirmationRequired = NewError(KindValidation, "a bulk delete needs the confirmation_token of a dry run")
+)
+
+// BulkDeleteSummary reports the outcome of a bulk delete. A dry run deletes
+// nothing: Matched counts the products that would be deleted and
+// ConfirmationToken must be sent back to delete them.
+type BulkDeleteSummary struct {
+	DryRun  bool `json:"dry_run"`
+	Matched int  `json:"matched"`
+	Deleted int  `json:"deleted"`
+	// IDs are the products matched, or deleted outside a dry run
+	IDs []string `json:"ids"`
+	// Missing are the requested IDs that matched no product
+	Missing []string `json:"missing,omitempty"`
+	// Skipped are the products left in place because they changed or
+	// disappeared while being deleted
+	Skipped           []string `json:"skipped,omitempty"`
+	ConfirmationToken string   `json:"confirmation_token,omitempty"`
+}
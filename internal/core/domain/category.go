@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrCategoryNotFound = NewError(KindNotFound, "category not found")
+	ErrInvalidCategory  = NewError(KindValidation, "invalid category data")
+	// ErrCategoryInUse is returned when deleting a category products still
+	// belong to
+	ErrCategoryInUse = NewError(KindConflict, "category still has products")
+	// ErrUnknownCategory is returned when a product names a category that
+	// does not exist
+	ErrUnknownCategory = NewError(KindValidation, "category does not exist")
+)
+
+// Category groups products for browsing and reporting
+type Category struct {
+	ID          string    `json:"id" dynamodbav:"id"`
+	Name        string    `json:"name" dynamodbav:"name"`
+	Description string    `json:"description" dynamodbav:"description"`
+	CreatedAt   time.Time `json:"created_at" dynamodbav:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" dynamodbav:"updated_at"`
+	// ProductCount is how many products, in any status, belong to the
+	// category. Product writes maintain it, never the category's own.
+	ProductCount int `json:"product_count" dynamodbav:"product_count"`
+}
+
+// NewCategory creates a category with a fresh ID
+func NewCategory(name, description string) (*Category, error) {
+	category := &Category{ID: uuid.New().String()}
+	if err := category.Rename(name, description); err != nil {
+		return nil, err
+	}
+	category.CreatedAt = category.UpdatedAt
+	return category, nil
+}
+
+// Rename replaces the category's name and description
+func (c *Category) Rename(name, description string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ErrInvalidCategory
+	}
+	c.Name = name
+	c.Description = description
+	c.UpdatedAt = time.Now().UTC()
+	return nil
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCategory(t *testing.T) {
+	category, err := NewCategory("  Kitchen ", "Pots and pans")
+	require.NoError(t, err)
+	assert.NotEmpty(t, category.ID)
+	assert.Equal(t, "Kitchen", category.Name)
+	assert.Equal(t, category.CreatedAt, category.UpdatedAt)
+
+	_, err = NewCategory(" ", "")
+	assert.ErrorIs(t, err, ErrInvalidCategory)
+}
+
+func TestCategory_Rename(t *testing.T) {
+	category, err := NewCategory("Kitchen", "")
+	require.NoError(t, err)
+
+	require.NoError(t, category.Rename("Cookware", "Pots"))
+	assert.Equal(t, "Cookware", category.Name)
+	assert.Equal(t, "Pots", category.Description)
+	assert.ErrorIs(t, category.Rename("", ""), ErrInvalidCategory)
+	assert.Equal(t, "Cookware", category.Name)
+}
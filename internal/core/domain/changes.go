@@ -0,0 +1,20 @@
+package domain
+
+// MaxChanges bounds the changes one read of the change feed returns
+const MaxChanges = 1000
+
+// ProductChanges are the IDs of the products created, updated and deleted
+// since a change feed checkpoint. A product created and then updated is
+// only listed as created; one deleted since is only listed as deleted.
+type ProductChanges struct {
+	Created []string `json:"created"`
+	Updated []string `json:"updated"`
+	// Hidden are the products changed since the checkpoint that the catalog
+	// does not list anymore: unpublished, archived, held by content
+	// moderation or expired
+	Hidden  []string `json:"hidden"`
+	Deleted []string `json:"deleted"`
+	// HasMore is set when the read stopped at its limit, so reading again
+	// from the next checkpoint returns more changes
+	HasMore bool `json:"has_more"`
+}
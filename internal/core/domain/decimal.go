@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an exact decimal number, such as a price in major units when
+// no currency fixes its decimal places. The zero value is 0.
+type Decimal struct {
+	unscaled int64
+	// scale is the number of decimal places of unscaled
+	scale int
+}
+
+// NewDecimal returns unscaled * 10^-scale, e.g. NewDecimal(1299, 2) is
+// 12.99
+func NewDecimal(unscaled int64, scale int) Decimal {
+	return Decimal{unscaled: unscaled, scale: max(scale, 0)}
+}
+
+// ParseDecimal reads a number written in decimal notation, e.g. "12.99"
+// or "-3", without exponents
+func ParseDecimal(value string) (Decimal, error) {
+	digits, negative := strings.TrimSpace(value), false
+	if strings.HasPrefix(digits, "-") {
+		digits, negative = digits[1:], true
+	}
+	whole, fraction, _ := strings.Cut(digits, ".")
+	if whole == "" || strings.Trim(whole+fraction, "0123456789") != "" {
+		return Decimal{}, fmt.Errorf("%q is not a decimal number", value)
+	}
+	fraction = strings.TrimRight(fraction, "0")
+	unscaled, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return Decimal{}, fmt.Errorf("%q is out of range", value)
+	}
+	if negative {
+		unscaled = -unscaled
+	}
+	return Decimal{unscaled: unscaled, scale: len(fraction)}, nil
+}
+
+// Sign returns -1, 0 or 1 as d is negative, zero or positive
+func (d Decimal) Sign() int {
+	switch {
+	case d.unscaled < 0:
+		return -1
+	case d.unscaled > 0:
+		return 1
+	}
+	return 0
+}
+
+// IsZero reports whether d is 0
+func (d Decimal) IsZero() bool {
+	return d.unscaled == 0
+}
+
+// Cmp returns -1, 0 or 1 as d is less than, equal to or greater than other
+func (d Decimal) Cmp(other Decimal) int {
+	// Decimals of the same scale, such as prices in one currency, compare
+	// without allocating; sorting by price compares them n log n times
+	if d.scale == other.scale {
+		return cmp.Compare(d.unscaled, other.unscaled)
+	}
+	return d.rat().Cmp(other.rat())
+}
+
+// String writes d without trailing zeros, e.g. "12.5"
+func (d Decimal) String() string {
+	s := d.rat().FloatString(d.scale)
+	if d.scale > 0 {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+func (d Decimal) rat() *big.Rat {
+	return new(big.Rat).SetFrac(big.NewInt(d.unscaled), pow10(d.scale))
+}
+
+// MarshalJSON writes d as a JSON number
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a string holding one
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	decimal, err := ParseDecimal(string(bytes.Trim(data, `"`)))
+	if err != nil {
+		return err
+	}
+	*d = decimal
+	return nil
+}
+
+// UnmarshalParam reads d from a query parameter when gin binds a request
+func (d *Decimal) UnmarshalParam(param string) error {
+	if param == "" {
+		*d = Decimal{}
+		return nil
+	}
+	decimal, err := ParseDecimal(param)
+	if err != nil {
+		return err
+	}
+	*d = decimal
+	return nil
+}
+
+// roundRat rounds r to an integer, halves away from zero
+func roundRat(r *big.Rat) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if remainder.Sign() != 0 && new(big.Int).Mul(new(big.Int).Abs(remainder), big.NewInt(2)).Cmp(r.Denom()) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(r.Sign())))
+	}
+	return quotient
+}
+
+func pow10(exponent int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil)
+}
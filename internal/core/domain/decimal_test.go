@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"12.99", "12.99"},
+		{"12.50", "12.5"},
+		{"12.", "12"},
+		{"0.000", "0"},
+		{"-3.10", "-3.1"},
+		{"007", "7"},
+	}
+	for _, tt := range tests {
+		decimal, err := ParseDecimal(tt.value)
+		require.NoError(t, err, tt.value)
+		assert.Equal(t, tt.want, decimal.String())
+	}
+
+	for _, bad := range []string{"", ".5", "1e3", "abc", "1.2.3", "NaN", "99999999999999999999"} {
+		_, err := ParseDecimal(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestDecimal_Cmp(t *testing.T) {
+	parse := func(value string) Decimal {
+		decimal, err := ParseDecimal(value)
+		require.NoError(t, err)
+		return decimal
+	}
+	assert.Zero(t, parse("0.30").Cmp(parse("0.3")))
+	assert.Equal(t, -1, parse("0.29").Cmp(parse("0.3")))
+	assert.Equal(t, 1, parse("100").Cmp(parse("99.999")))
+	assert.Equal(t, -1, parse("-1").Cmp(Decimal{}))
+	assert.Equal(t, -1, NewDecimal(-5, 2).Cmp(NewDecimal(3, 2)))
+	assert.Equal(t, 1, NewDecimal(1300, 2).Cmp(NewDecimal(1299, 2)))
+	assert.Zero(t, Money{Amount: 1250, Currency: "USD"}.Major().Cmp(parse("12.5")))
+	assert.Equal(t, 1, Money{Amount: 1001, Currency: "KWD"}.Major().Cmp(Money{Amount: 100, Currency: "USD"}.Major()))
+}
+
+func TestDecimal_JSON(t *testing.T) {
+	data, err := json.Marshal(struct{ Price Decimal }{NewDecimal(1250, 2)})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"Price":12.5}`, string(data))
+
+	var decoded struct{ Price Decimal }
+	require.NoError(t, json.Unmarshal([]byte(`{"Price":"0.1"}`), &decoded))
+	assert.Equal(t, "0.1", decoded.Price.String())
+	assert.Error(t, json.Unmarshal([]byte(`{"Price":1e2}`), &decoded))
+}
+
+func TestMoneyFromDecimal(t *testing.T) {
+	tests := []struct {
+		value    string
+		currency string
+		want     int64
+	}{
+		{"12.99", "USD", 1299},
+		{"0.005", "USD", 1},
+		{"0.0049", "USD", 0},
+		{"-0.005", "USD", -1},
+		{"1499.5", "JPY", 1500},
+		{"1.2345", "KWD", 1235},
+	}
+	for _, tt := range tests {
+		value, err := ParseDecimal(tt.value)
+		require.NoError(t, err)
+		money, err := MoneyFromDecimal(value, tt.currency)
+		require.NoError(t, err, tt.value)
+		assert.Equal(t, tt.want, money.Amount, tt.value)
+	}
+
+	_, err := MoneyFromDecimal(NewDecimal(1, 0), "XYZ")
+	assert.ErrorIs(t, err, ErrUnsupportedCurrency)
+	_, err = MoneyFromDecimal(NewDecimal(1<<62, 0), "USD")
+	assert.Error(t, err)
+}
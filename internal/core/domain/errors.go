@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"errors"
+	"maps"
+)
+
+// Kind classifies an error by how callers should react to it, whatever
+// adapter or rule produced it
+type Kind string
+
+const (
+	// KindNotFound means the thing asked for does not exist
+	KindNotFound Kind = "not_found"
+	// KindConflict means the request clashes with the current state, such
+	// as a concurrent write or a value held by another product
+	KindConflict Kind = "conflict"
+	// KindValidation means the request itself is invalid
+	KindValidation Kind = "validation"
+	// KindUnavailable means a dependency failed in a way that retrying
+	// later may fix, such as throttling or a timeout
+	KindUnavailable Kind = "unavailable"
+)
+
+// ErrUnavailable is matched by storage and other dependency failures that
+// are worth retrying
+var ErrUnavailable = NewError(KindUnavailable, "service temporarily unavailable")
+
+// Error is a domain error of a known Kind. The sentinels of this package
+// are *Error values; errors derived from one with With or Wrap still match
+// it with errors.Is, so callers never compare errors with ==.
+type Error struct {
+	Kind Kind
+	// Message is safe to show to clients; the cause is not
+	Message string
+	// Metadata describes what failed, such as the ID involved
+	Metadata map[string]string
+	// Err is the underlying cause, if any
+	Err error
+	// sentinel is the error this one was derived from
+	sentinel *Error
+}
+
+func NewError(kind Kind, message string) *Error {
+	return &Error{Kind: kind, Message: message}
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches the sentinel e was derived from
+func (e *Error) Is(target error) bool {
+	sentinel, ok := target.(*Error)
+	return ok && sentinel == e.origin()
+}
+
+// With returns a copy of e carrying one more metadata entry
+func (e *Error) With(key, value string) *Error {
+	derived := e.derive()
+	derived.Metadata[key] = value
+	return derived
+}
+
+// Wrap returns a copy of e caused by err
+func (e *Error) Wrap(err error) *Error {
+	derived := e.derive()
+	derived.Err = err
+	return derived
+}
+
+func (e *Error) derive() *Error {
+	derived := *e
+	derived.sentinel = e.origin()
+	derived.Metadata = maps.Clone(e.Metadata)
+	if derived.Metadata == nil {
+		derived.Metadata = map[string]string{}
+	}
+	return &derived
+}
+
+func (e *Error) origin() *Error {
+	if e.sentinel != nil {
+		return e.sentinel
+	}
+	return e
+}
+
+// KindOf returns the kind of the first *Error in err's chain, or "" when
+// err is not classified
+func KindOf(err error) Kind {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Kind
+	}
+	return ""
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError(t *testing.T) {
+	cause := errors.New("connection reset")
+	err := fmt.Errorf("get product: %w", ErrNotFound.With("id", "p1").Wrap(cause))
+
+	assert.ErrorIs(t, err, ErrNotFound, "derived errors match their sentinel")
+	assert.ErrorIs(t, err, cause)
+	assert.NotErrorIs(t, err, ErrCategoryNotFound, "sentinels of the same kind stay distinct")
+	assert.Equal(t, KindNotFound, KindOf(err))
+	assert.Equal(t, "get product: product not found: connection reset", err.Error())
+
+	var domainErr *Error
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, map[string]string{"id": "p1"}, domainErr.Metadata)
+	assert.Equal(t, "product not found", domainErr.Message)
+	assert.Empty(t, ErrNotFound.Metadata, "deriving leaves the sentinel untouched")
+
+	assert.Equal(t, KindConflict, KindOf(&DuplicateError{Field: FieldSKU, Value: "KB-01"}))
+	assert.Equal(t, Kind(""), KindOf(cause))
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Product change event types published to other services
+const (
+	EventProductUpdated = "product.updated"
+	EventProductDeleted = "product.deleted"
+)
+
+// ProductEvent announces a committed change to a product. Consumers receive
+// the product as it was after the change; deletes carry no snapshot.
+type ProductEvent struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	ProductID  string    `json:"product_id"`
+	Product    *Product  `json:"product,omitempty"`
+	ReplacedBy string    `json:"replaced_by,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// NewProductEvent records a change to product. Pass a nil product for
+// deletes, where only the ID is known.
+func NewProductEvent(eventType, productID string, product *Product, occurredAt time.Time) ProductEvent {
+	return ProductEvent{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		ProductID:  productID,
+		Product:    product,
+		OccurredAt: occurredAt,
+	}
+}
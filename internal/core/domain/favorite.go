@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNoUser is returned for favorites requested without an authenticated
+// user to keep them for
+var ErrNoUser = errors.New("favorites require an authenticated user")
+
+// Favorite marks a product on a user's wish list
+type Favorite struct {
+	UserID    string    `json:"user_id"`
+	ProductID string    `json:"product_id"`
+	TenantID  string    `json:"tenant_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
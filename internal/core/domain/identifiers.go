@@ -0,0 +1,181 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Unique product identifiers. Within a tenant, each SKU and each barcode
+// belongs to at most one product; IDs are unique across tenants.
+const (
+	FieldID            = "id"
+	FieldSKU           = "sku"
+	FieldBarcode       = "barcode"
+	MaxSKULength       = 64
+	MaxProductIDLength = 128
+)
+
+// ErrDuplicate is matched by every *DuplicateError
+var ErrDuplicate = NewError(KindConflict, "value is already used by another product")
+
+// DuplicateError is returned when a write would give a product an SKU or
+// barcode that another product of its tenant already has, or create a
+// product with the ID of an existing one
+type DuplicateError struct {
+	Field string
+	Value string
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("%s %q is already used by another product", e.Field, e.Value)
+}
+
+func (e *DuplicateError) Unwrap() error {
+	return ErrDuplicate
+}
+
+// NormalizeSKU trims and uppercases an SKU. SKUs are letters, digits, '-',
+// '_' and '.'; an empty one means the product has none.
+func NormalizeSKU(sku string) (string, error) {
+	sku = strings.ToUpper(strings.TrimSpace(sku))
+	if len(sku) > MaxSKULength {
+		return "", fmt.Errorf("sku cannot be longer than %d characters", MaxSKULength)
+	}
+	for _, r := range sku {
+		if !(r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return "", fmt.Errorf("sku %q may only contain letters, digits, '-', '_' and '.'", sku)
+		}
+	}
+	return sku, nil
+}
+
+// NormalizeBarcode checks a GTIN barcode: 8, 12, 13 or 14 digits (EAN-8,
+// UPC-A, EAN-13 or GTIN-14) ending in a valid check digit. An empty one
+// means the product has none.
+func NormalizeBarcode(barcode string) (string, error) {
+	barcode = strings.TrimSpace(barcode)
+	if barcode == "" {
+		return "", nil
+	}
+	switch len(barcode) {
+	case 8, 12, 13, 14:
+	default:
+		return "", fmt.Errorf("barcode must have 8, 12, 13 or 14 digits")
+	}
+
+	if strings.Trim(barcode, "0123456789") != "" {
+		return "", fmt.Errorf("barcode %q may only contain digits", barcode)
+	}
+
+	// Weights alternate 3, 1 leftwards from the digit before the check digit
+	body, check := barcode[:len(barcode)-1], int(barcode[len(barcode)-1]-'0')
+	sum := 0
+	for i := range len(body) {
+		digit := int(body[len(body)-1-i] - '0')
+		if i%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	if (10-sum%10)%10 != check {
+		return "", fmt.Errorf("barcode %q has an invalid check digit", barcode)
+	}
+	return barcode, nil
+}
+
+// ProductIDFormat is the format client-chosen product IDs must have. The
+// zero value accepts lowercase UUIDs, like the generated ones.
+type ProductIDFormat struct {
+	pattern *regexp.Regexp
+}
+
+// uuidPattern matches UUIDs in their canonical lowercase form
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// urlSafeID keeps IDs usable in URLs and keys whatever the pattern allows
+var urlSafeID = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// NewProductIDFormat accepts IDs matching pattern as a whole, or UUIDs when
+// pattern is empty
+func NewProductIDFormat(pattern string) (ProductIDFormat, error) {
+	if pattern == "" {
+		return ProductIDFormat{}, nil
+	}
+	compiled, err := regexp.Compile(`^(?:` + pattern + `)$`)
+	if err != nil {
+		return ProductIDFormat{}, fmt.Errorf("invalid product ID pattern: %w", err)
+	}
+	return ProductIDFormat{pattern: compiled}, nil
+}
+
+// Validate checks a client-chosen ID. Whatever the pattern, IDs are at most
+// MaxProductIDLength letters, digits, '-', '_' and '.'.
+func (f ProductIDFormat) Validate(id string) error {
+	if len(id) > MaxProductIDLength {
+		return fmt.Errorf("id cannot be longer than %d characters", MaxProductIDLength)
+	}
+	if f.pattern == nil {
+		if !uuidPattern.MatchString(id) {
+			return fmt.Errorf("id %q must be a lowercase UUID", id)
+		}
+		return nil
+	}
+	if !urlSafeID.MatchString(id) || !f.pattern.MatchString(id) {
+		return fmt.Errorf("id %q does not match the product ID pattern %s", id, f)
+	}
+	return nil
+}
+
+// String returns the pattern IDs must match
+func (f ProductIDFormat) String() string {
+	if f.pattern == nil {
+		return uuidPattern.String()
+	}
+	return f.pattern.String()
+}
+
+// SetID names a new product with a client-chosen ID in format instead of
+// its generated one; empty keeps the generated ID
+func (p *Product) SetID(id string, format ProductIDFormat) error {
+	if id == "" {
+		return nil
+	}
+	if err := format.Validate(id); err != nil {
+		return err
+	}
+	p.ID = id
+	return nil
+}
+
+// SetSKU replaces the product's SKU; empty removes it
+func (p *Product) SetSKU(sku string) error {
+	normalized, err := NormalizeSKU(sku)
+	if err != nil {
+		return err
+	}
+	p.SKU = normalized
+	return nil
+}
+
+// SetBarcode replaces the product's barcode; empty removes it
+func (p *Product) SetBarcode(barcode string) error {
+	normalized, err := NormalizeBarcode(barcode)
+	if err != nil {
+		return err
+	}
+	p.Barcode = normalized
+	return nil
+}
+
+// UniqueValues are the unique identifiers the product holds, by field
+func (p Product) UniqueValues() map[string]string {
+	values := map[string]string{}
+	if p.SKU != "" {
+		values[FieldSKU] = p.SKU
+	}
+	if p.Barcode != "" {
+		values[FieldBarcode] = p.Barcode
+	}
+	return values
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeSKU(t *testing.T) {
+	sku, err := NormalizeSKU("  lap-1_b.2 ")
+	assert.NoError(t, err)
+	assert.Equal(t, "LAP-1_B.2", sku)
+
+	sku, err = NormalizeSKU("")
+	assert.NoError(t, err)
+	assert.Empty(t, sku)
+
+	_, err = NormalizeSKU("LAP 1")
+	assert.Error(t, err)
+	_, err = NormalizeSKU(strings.Repeat("A", MaxSKULength+1))
+	assert.Error(t, err)
+}
+
+func TestNormalizeBarcode(t *testing.T) {
+	for _, valid := range []string{"4006381333931", "036000291452", "96385074", "10012345678902"} {
+		barcode, err := NormalizeBarcode(" " + valid + " ")
+		assert.NoError(t, err, valid)
+		assert.Equal(t, valid, barcode)
+	}
+
+	for _, invalid := range []string{"4006381333932", "40063813339", "40063813339a1"} {
+		_, err := NormalizeBarcode(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestProductIDFormat(t *testing.T) {
+	uuids := ProductIDFormat{}
+	assert.NoError(t, uuids.Validate("6f1c2b1e-8d3a-4b5c-9e7f-0a1b2c3d4e5f"))
+	for _, invalid := range []string{"6F1C2B1E-8D3A-4B5C-9E7F-0A1B2C3D4E5F", "{6f1c2b1e-8d3a-4b5c-9e7f-0a1b2c3d4e5f}", "erp-1042"} {
+		assert.Error(t, uuids.Validate(invalid), invalid)
+	}
+
+	erp, err := NewProductIDFormat(`erp-[0-9]+`)
+	require.NoError(t, err)
+	assert.NoError(t, erp.Validate("erp-1042"))
+	assert.Error(t, erp.Validate("erp-1042x"), "the pattern must match the whole ID")
+	assert.Error(t, erp.Validate("6f1c2b1e-8d3a-4b5c-9e7f-0a1b2c3d4e5f"))
+
+	anything, err := NewProductIDFormat(`.+`)
+	require.NoError(t, err)
+	assert.NoError(t, anything.Validate("ERP_1042.b"))
+	assert.Error(t, anything.Validate("erp/1042"), "IDs stay URL-safe whatever the pattern")
+	assert.Error(t, anything.Validate(strings.Repeat("a", MaxProductIDLength+1)))
+
+	_, err = NewProductIDFormat(`erp-[`)
+	assert.Error(t, err)
+}
+
+func TestProduct_SetID(t *testing.T) {
+	product := Product{ID: "generated"}
+	assert.NoError(t, product.SetID("", ProductIDFormat{}))
+	assert.Equal(t, "generated", product.ID)
+	assert.Error(t, product.SetID("erp-1042", ProductIDFormat{}))
+	assert.Equal(t, "generated", product.ID)
+	assert.NoError(t, product.SetID("6f1c2b1e-8d3a-4b5c-9e7f-0a1b2c3d4e5f", ProductIDFormat{}))
+	assert.Equal(t, "6f1c2b1e-8d3a-4b5c-9e7f-0a1b2c3d4e5f", product.ID)
+}
+
+func TestDuplicateError(t *testing.T) {
+	err := error(&DuplicateError{Field: FieldSKU, Value: "LAP-1"})
+	assert.True(t, errors.Is(err, ErrDuplicate))
+	assert.Equal(t, `sku "LAP-1" is already used by another product`, err.Error())
+}
+
+func TestProduct_UniqueValues(t *testing.T) {
+	assert.Empty(t, Product{}.UniqueValues())
+	assert.Equal(t, map[string]string{FieldSKU: "LAP-1"}, Product{SKU: "LAP-1"}.UniqueValues())
+}
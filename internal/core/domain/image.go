@@ -0,0 +1,63 @@
+package domain
+
+import "time"
+
+var (
+	ErrUnsupportedImageType = NewError(KindValidation, "image content type must be image/jpeg, image/png, image/webp or image/gif")
+	ErrTooManyImages        = NewError(KindConflict, "product already has the maximum number of images")
+)
+
+// MaxProductImages bounds the images of one product, keeping the item well
+// under the DynamoDB item size limit
+const MaxProductImages = 10
+
+// imageExtensions are the accepted image content types and the file
+// extension their objects are stored with
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+	"image/gif":  ".gif",
+}
+
+// ProductImage is an image stored in blob storage under Key. Order is its
+// position in the product's gallery, starting at 0.
+type ProductImage struct {
+	Key         string    `json:"key" dynamodbav:"key"`
+	URL         string    `json:"url" dynamodbav:"url"`
+	ContentType string    `json:"content_type" dynamodbav:"content_type"`
+	Order       int       `json:"order" dynamodbav:"order"`
+	CreatedAt   time.Time `json:"created_at" dynamodbav:"created_at"`
+}
+
+// ImageExtension returns the file extension for an accepted image content
+// type
+func ImageExtension(contentType string) (string, error) {
+	extension, ok := imageExtensions[contentType]
+	if !ok {
+		return "", ErrUnsupportedImageType
+	}
+	return extension, nil
+}
+
+// AddImage appends an image at the end of the product's gallery
+func (p *Product) AddImage(key, url, contentType string, now time.Time) (ProductImage, error) {
+	if _, err := ImageExtension(contentType); err != nil {
+		return ProductImage{}, err
+	}
+	if len(p.Images) >= MaxProductImages {
+		return ProductImage{}, ErrTooManyImages
+	}
+
+	image := ProductImage{
+		Key:         key,
+		URL:         url,
+		ContentType: contentType,
+		Order:       len(p.Images),
+		CreatedAt:   now,
+	}
+	// Copy so a product read earlier keeps its own gallery
+	p.Images = append(append([]ProductImage(nil), p.Images...), image)
+	p.UpdatedAt = now
+	return image, nil
+}
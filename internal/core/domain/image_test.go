@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddImage(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	product, err := NewProduct(testID, "Laptop", "", Money{Amount: 99900, Currency: "USD"})
+	require.NoError(t, err)
+	before := *product
+
+	first, err := product.AddImage("a.png", "https://cdn/a.png", "image/png", now)
+	require.NoError(t, err)
+	second, err := product.AddImage("b.jpg", "https://cdn/b.jpg", "image/jpeg", now)
+	require.NoError(t, err)
+	assert.Equal(t, 0, first.Order)
+	assert.Equal(t, 1, second.Order)
+	assert.Len(t, product.Images, 2)
+	assert.Empty(t, before.Images)
+	assert.Equal(t, now, product.UpdatedAt)
+
+	_, err = product.AddImage("c.svg", "https://cdn/c.svg", "image/svg+xml", now)
+	assert.ErrorIs(t, err, ErrUnsupportedImageType)
+
+	for len(product.Images) < MaxProductImages {
+		_, err = product.AddImage("x.png", "https://cdn/x.png", "image/png", now)
+		require.NoError(t, err)
+	}
+	_, err = product.AddImage("y.png", "https://cdn/y.png", "image/png", now)
+	assert.ErrorIs(t, err, ErrTooManyImages)
+}
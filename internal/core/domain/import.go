@@ -0,0 +1,52 @@
+package domain
+
+import "errors"
+
+// MaxImportRows bounds the rows of one bulk import request
+const MaxImportRows = 5000
+
+// ImportRowError explains why a row of a bulk import was skipped. Row is
+// the 1-based data row of the file, not counting a CSV header.
+type ImportRowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// ErrNotImported is the row error of the valid rows an import that failed
+// part way through did not get to write
+var ErrNotImported = errors.New("not imported: the import failed before this row was written, send it again")
+
+// ImportSummary reports the outcome of a bulk import. In a dry run nothing
+// is written and Imported counts the rows that would have been. When the
+// import fails part way through, Written lists the IDs of the products
+// already created and the rows left are reported with ErrNotImported, so
+// that sending only those rows again completes it.
+type ImportSummary struct {
+	Imported int              `json:"imported"`
+	Skipped  int              `json:"skipped"`
+	DryRun   bool             `json:"dry_run"`
+	Errors   []ImportRowError `json:"errors"`
+	Written  []string         `json:"written,omitempty"`
+}
+
+// Skip records a row that will not be imported
+func (s *ImportSummary) Skip(row int, err error) {
+	s.Skipped++
+	s.Errors = append(s.Errors, ImportRowError{Row: row, Error: err.Error()})
+}
+
+// BatchWriteError is returned by a batch write that failed part way
+// through. Written lists the IDs of the products saved before the failure,
+// which stay saved.
+type BatchWriteError struct {
+	Written []string
+	Err     error
+}
+
+func (e *BatchWriteError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *BatchWriteError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// ErrInvalidTransition is returned for a status change the lifecycle does
+// not allow
+var ErrInvalidTransition = NewError(KindConflict, "invalid status transition")
+
+// statusTransitions lists the statuses each status may move to. Drafts go
+// live or nowhere, archived products may come back, and discontinued
+// products are retired for good.
+var statusTransitions = map[string][]string{
+	StatusDraft:     {StatusPublished},
+	StatusPublished: {StatusArchived, StatusDiscontinued},
+	StatusArchived:  {StatusPublished, StatusDiscontinued},
+}
+
+// StatusActive is accepted as input wherever a status is given, as another
+// name for StatusPublished. Products are stored and returned as published.
+const StatusActive = "active"
+
+// NormalizeStatus maps the StatusActive alias to StatusPublished and
+// returns other statuses unchanged
+func NormalizeStatus(status string) string {
+	if status == StatusActive {
+		return StatusPublished
+	}
+	return status
+}
+
+// IsValidStatus reports whether status is one of the product statuses
+func IsValidStatus(status string) bool {
+	switch status {
+	case StatusDraft, StatusPublished, StatusArchived, StatusDiscontinued:
+		return true
+	}
+	return false
+}
+
+// CanTransition reports whether a product may move from one status to
+// another. An empty status is published.
+func CanTransition(from, to string) bool {
+	if from == "" {
+		from = StatusPublished
+	}
+	return slices.Contains(statusTransitions[from], to)
+}
+
+// TransitionTo moves the product to status. Publishing drops the publish
+// schedule and leaving published drops the archive schedule, since both
+// only apply to the status they leave.
+func (p *Product) TransitionTo(status string, now time.Time) error {
+	if !CanTransition(p.Status, status) {
+		from := p.Status
+		if from == "" {
+			from = StatusPublished
+		}
+		return fmt.Errorf("%w: cannot move a product from %q to %q", ErrInvalidTransition, from, status)
+	}
+	if status == StatusPublished {
+		p.PublishAt = nil
+	} else {
+		p.AutoArchiveAt = nil
+		p.ArchiveWarnedAt = nil
+	}
+	p.Status = status
+	p.UpdatedAt = now
+	return nil
+}
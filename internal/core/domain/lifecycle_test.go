@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{StatusDraft, StatusPublished, true},
+		{StatusDraft, StatusArchived, false},
+		{StatusDraft, StatusDiscontinued, false},
+		{StatusPublished, StatusArchived, true},
+		{StatusPublished, StatusDiscontinued, true},
+		{StatusPublished, StatusPublished, false},
+		{"", StatusArchived, true},
+		{StatusArchived, StatusPublished, true},
+		{StatusArchived, StatusDiscontinued, true},
+		{StatusDiscontinued, StatusPublished, false},
+		{StatusDiscontinued, StatusArchived, false},
+		{StatusPublished, "deleted", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, CanTransition(tt.from, tt.to), "%q -> %q", tt.from, tt.to)
+	}
+}
+
+func TestTransitionTo(t *testing.T) {
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	product, err := NewProduct(testID, "Kettle", "", Money{Amount: 2500, Currency: "USD"})
+	require.NoError(t, err)
+	endOfSeason := now.Add(30 * 24 * time.Hour)
+	require.NoError(t, product.SetAutoArchive(&endOfSeason, now))
+
+	require.NoError(t, product.TransitionTo(StatusArchived, now))
+	assert.Equal(t, StatusArchived, product.Status)
+	assert.Nil(t, product.AutoArchiveAt)
+	assert.Equal(t, now, product.UpdatedAt)
+
+	require.NoError(t, product.TransitionTo(StatusPublished, now))
+	require.NoError(t, product.TransitionTo(StatusDiscontinued, now))
+	err = product.TransitionTo(StatusPublished, now)
+	assert.ErrorIs(t, err, ErrInvalidTransition)
+	assert.Equal(t, StatusDiscontinued, product.Status)
+
+	relaunch := now.Add(time.Hour)
+	assert.ErrorIs(t, product.SchedulePublish(&relaunch, now), ErrInvalidTransition)
+}
+
+func TestIsValidStatus(t *testing.T) {
+	assert.True(t, IsValidStatus(StatusDiscontinued))
+	assert.False(t, IsValidStatus(StatusActive))
+	assert.False(t, IsValidStatus(""))
+}
+
+func TestNormalizeStatus(t *testing.T) {
+	assert.Equal(t, StatusPublished, NormalizeStatus(StatusActive))
+	assert.Equal(t, StatusDraft, NormalizeStatus(StatusDraft))
+	assert.Equal(t, "", NormalizeStatus(""))
+}
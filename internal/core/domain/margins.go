@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// UncategorizedCategory groups products that have no category
+const UncategorizedCategory = "uncategorized"
+
+// ErrReportNotReady is returned before a report has been generated
+var ErrReportNotReady = NewError(KindNotFound, "report has not been generated yet")
+
+// ProductMargin is one product's margin at report time. The price, cost
+// and profit are in the same currency.
+type ProductMargin struct {
+	ProductID string  `json:"product_id"`
+	Name      string  `json:"name"`
+	Category  string  `json:"category"`
+	Price     Money   `json:"price"`
+	CostPrice Money   `json:"cost_price"`
+	Profit    Money   `json:"profit"`
+	Margin    float64 `json:"margin"`
+}
+
+// CategoryMargins summarizes the margins of one category
+type CategoryMargins struct {
+	Category      string  `json:"category"`
+	Products      int     `json:"products"`
+	LowMargin     int     `json:"low_margin"`
+	AverageMargin float64 `json:"average_margin"`
+}
+
+// MarginReport is the profitability summary produced by the background job
+type MarginReport struct {
+	GeneratedAt       time.Time         `json:"generated_at"`
+	Threshold         float64           `json:"threshold"`
+	Categories        []CategoryMargins `json:"categories"`
+	LowMarginProducts []ProductMargin   `json:"low_margin_products"`
+}
+
+// BuildMarginReport summarizes the margins of products with a known cost by
+// category, listing up to limit products whose margin is below threshold,
+// lowest first. category returns the category a product is grouped under.
+func BuildMarginReport(products []Product, category func(Product) string, threshold float64, limit int, now time.Time) MarginReport {
+	totals := make(map[string]*CategoryMargins)
+	sums := make(map[string]float64)
+	var low []ProductMargin
+
+	for _, product := range products {
+		margin, ok := product.Margin()
+		if !ok {
+			continue
+		}
+		name := category(product)
+		if name == "" {
+			name = UncategorizedCategory
+		}
+
+		summary, ok := totals[name]
+		if !ok {
+			summary = &CategoryMargins{Category: name}
+			totals[name] = summary
+		}
+		summary.Products++
+		sums[name] += margin
+
+		if margin < threshold {
+			summary.LowMargin++
+			profit, _ := product.Profit()
+			low = append(low, ProductMargin{
+				ProductID: product.ID,
+				Name:      product.Name,
+				Category:  name,
+				Price:     product.Price,
+				CostPrice: *product.CostPrice,
+				Profit:    profit,
+				Margin:    margin,
+			})
+		}
+	}
+
+	categories := make([]CategoryMargins, 0, len(totals))
+	for name, summary := range totals {
+		summary.AverageMargin = sums[name] / float64(summary.Products)
+		categories = append(categories, *summary)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].Category < categories[j].Category
+	})
+
+	sort.Slice(low, func(i, j int) bool {
+		if low[i].Margin != low[j].Margin {
+			return low[i].Margin < low[j].Margin
+		}
+		return low[i].ProductID < low[j].ProductID
+	})
+	if len(low) > limit {
+		low = low[:limit]
+	}
+	if low == nil {
+		low = []ProductMargin{}
+	}
+
+	return MarginReport{
+		GeneratedAt:       now,
+		Threshold:         threshold,
+		Categories:        categories,
+		LowMarginProducts: low,
+	}
+}
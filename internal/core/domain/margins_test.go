@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMargin(t *testing.T) {
+	cost := Money{Amount: 7500, Currency: "USD"}
+	product := Product{Price: Money{Amount: 10000, Currency: "USD"}, CostPrice: &cost}
+	margin, ok := product.Margin()
+	assert.True(t, ok)
+	assert.Equal(t, 0.25, margin)
+	profit, ok := product.Profit()
+	assert.True(t, ok)
+	assert.Equal(t, Money{Amount: 2500, Currency: "USD"}, profit)
+
+	// Amounts that floats cannot hold exactly still subtract exactly
+	cost = Money{Amount: 20, Currency: "USD"}
+	profit, _ = Product{Price: Money{Amount: 30, Currency: "USD"}, CostPrice: &cost}.Profit()
+	assert.Equal(t, Money{Amount: 10, Currency: "USD"}, profit)
+
+	_, ok = Product{Price: Money{Amount: 10000, Currency: "USD"}}.Margin()
+	assert.False(t, ok)
+	_, ok = Product{Price: Money{Amount: 0, Currency: "USD"}, CostPrice: &cost}.Margin()
+	assert.False(t, ok)
+	_, ok = Product{Price: Money{Amount: 10000, Currency: "EUR"}, CostPrice: &cost}.Margin()
+	assert.False(t, ok, "a cost in another currency says nothing about the margin")
+}
+
+func TestSetCostPrice(t *testing.T) {
+	product := Product{Price: Money{Amount: 1500, Currency: "JPY"}}
+	cost := NewDecimal(900, 0)
+	require.NoError(t, product.SetCostPrice(&cost))
+	assert.Equal(t, &Money{Amount: 900, Currency: "JPY"}, product.CostPrice)
+
+	fraction := NewDecimal(9005, 1)
+	assert.Error(t, product.SetCostPrice(&fraction), "yen have no minor unit")
+	negative := NewDecimal(-1, 0)
+	assert.Error(t, product.SetCostPrice(&negative))
+
+	require.NoError(t, product.SetCostPrice(nil))
+	assert.Equal(t, &Money{Amount: 900, Currency: "JPY"}, product.CostPrice, "nil keeps the cost")
+
+	product.Price = Money{Amount: 1000, Currency: "USD"}
+	assert.Error(t, product.SetCostPrice(nil), "a cost in yen is no cost for a price in dollars")
+	dollars := NewDecimal(650, 2)
+	require.NoError(t, product.SetCostPrice(&dollars))
+	assert.Equal(t, &Money{Amount: 650, Currency: "USD"}, product.CostPrice)
+}
+
+func TestBuildMarginReport(t *testing.T) {
+	cost := func(cents int64) *Money { return &Money{Amount: cents, Currency: "USD"} }
+	products := []Product{
+		{ID: "a", Name: "A", Price: Money{Amount: 10000, Currency: "USD"}, CostPrice: cost(9500)},
+		{ID: "b", Name: "B", Price: Money{Amount: 10000, Currency: "USD"}, CostPrice: cost(5000)},
+		{ID: "c", Name: "C", Price: Money{Amount: 1000, Currency: "USD"}, CostPrice: cost(1200)},
+		{ID: "d", Name: "D", Price: Money{Amount: 1000, Currency: "USD"}},
+	}
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	report := BuildMarginReport(products, func(Product) string { return "" }, 0.2, 10, now)
+
+	assert.Equal(t, now, report.GeneratedAt)
+	if assert.Len(t, report.Categories, 1) {
+		assert.Equal(t, UncategorizedCategory, report.Categories[0].Category)
+		assert.Equal(t, 3, report.Categories[0].Products)
+		assert.Equal(t, 2, report.Categories[0].LowMargin)
+	}
+	if assert.Len(t, report.LowMarginProducts, 2) {
+		// Loss-making products come first
+		assert.Equal(t, "c", report.LowMarginProducts[0].ProductID)
+		assert.Equal(t, Money{Amount: -200, Currency: "USD"}, report.LowMarginProducts[0].Profit)
+		assert.Equal(t, "a", report.LowMarginProducts[1].ProductID)
+	}
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+// Moderation statuses of a product. Only approved products, and items
+// written before moderation existed, appear in listings.
+const (
+	ModerationApproved      = "approved"
+	ModerationPendingReview = "pending_review"
+	ModerationRejected      = "rejected"
+)
+
+// Moderation decisions returned by content screening
+const (
+	DecisionAllow  = "allow"
+	DecisionFlag   = "flag"
+	DecisionReject = "reject"
+)
+
+var (
+	// ErrContentRejected is matched by every *ContentRejectedError
+	ErrContentRejected  = errors.New("content rejected by moderation")
+	ErrNotPendingReview = NewError(KindConflict, "product is not pending review")
+)
+
+// ModerationVerdict is the outcome of screening a product's text
+type ModerationVerdict struct {
+	Decision string
+	Reasons  []string
+}
+
+// ContentRejectedError is returned when screening rejects a product outright
+type ContentRejectedError struct {
+	Reasons []string
+}
+
+func (e *ContentRejectedError) Error() string {
+	return ErrContentRejected.Error() + ": " + strings.Join(e.Reasons, "; ")
+}
+
+func (e *ContentRejectedError) Unwrap() error {
+	return ErrContentRejected
+}
+
+// ApplyModeration records a screening verdict: allowed content is approved,
+// flagged content waits for manual review and rejected content is refused
+func (p *Product) ApplyModeration(verdict ModerationVerdict) error {
+	switch verdict.Decision {
+	case DecisionReject:
+		return &ContentRejectedError{Reasons: verdict.Reasons}
+	case DecisionFlag:
+		p.ModerationStatus = ModerationPendingReview
+		p.ModerationReasons = verdict.Reasons
+	default:
+		p.ModerationStatus = ModerationApproved
+		p.ModerationReasons = nil
+	}
+	return nil
+}
+
+// IsModerationApproved reports whether moderation lets the product be listed
+func (p Product) IsModerationApproved() bool {
+	return p.ModerationStatus == "" || p.ModerationStatus == ModerationApproved
+}
+
+// ResolveReview settles a manual review. Rejected products stay hidden until
+// their owner edits them and they pass screening again.
+func (p *Product) ResolveReview(approved bool, reason string) error {
+	if p.ModerationStatus != ModerationPendingReview {
+		return ErrNotPendingReview
+	}
+	if approved {
+		p.ModerationStatus = ModerationApproved
+		p.ModerationReasons = nil
+		return nil
+	}
+	p.ModerationStatus = ModerationRejected
+	if reason != "" {
+		p.ModerationReasons = append(p.ModerationReasons, reason)
+	}
+	return nil
+}
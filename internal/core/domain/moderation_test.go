@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyModeration(t *testing.T) {
+	var product Product
+
+	err := product.ApplyModeration(ModerationVerdict{Decision: DecisionReject, Reasons: []string{"blocked term"}})
+	var rejected *ContentRejectedError
+	require.True(t, errors.As(err, &rejected))
+	assert.ErrorIs(t, err, ErrContentRejected)
+	assert.Equal(t, []string{"blocked term"}, rejected.Reasons)
+
+	require.NoError(t, product.ApplyModeration(ModerationVerdict{Decision: DecisionFlag, Reasons: []string{"flagged term"}}))
+	assert.Equal(t, ModerationPendingReview, product.ModerationStatus)
+	assert.False(t, product.IsModerationApproved())
+
+	require.NoError(t, product.ApplyModeration(ModerationVerdict{Decision: DecisionAllow}))
+	assert.True(t, product.IsModerationApproved())
+	assert.Nil(t, product.ModerationReasons)
+}
+
+func TestResolveReview(t *testing.T) {
+	product := Product{ModerationStatus: ModerationPendingReview, ModerationReasons: []string{"flagged term"}}
+
+	require.NoError(t, product.ResolveReview(false, "counterfeit"))
+	assert.Equal(t, ModerationRejected, product.ModerationStatus)
+	assert.Equal(t, []string{"flagged term", "counterfeit"}, product.ModerationReasons)
+
+	// Only pending products can be reviewed
+	assert.ErrorIs(t, product.ResolveReview(true, ""), ErrNotPendingReview)
+}
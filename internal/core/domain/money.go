@@ -0,0 +1,218 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrUnsupportedCurrency = NewError(KindValidation, "currency must be a supported ISO 4217 code")
+	ErrNoExchangeRate      = NewError(KindValidation, "no exchange rate for the requested currency")
+)
+
+// DefaultCurrency prices products stored or sent before prices carried a
+// currency
+const DefaultCurrency = "USD"
+
+// currencyExponents are the supported ISO 4217 currencies and the number of
+// decimal places of their minor unit
+var currencyExponents = map[string]int{
+	"ARS": 2, "AUD": 2, "BHD": 3, "BRL": 2, "CAD": 2, "CHF": 2, "CLP": 0,
+	"CNY": 2, "COP": 2, "CZK": 2, "DKK": 2, "EUR": 2, "GBP": 2, "HKD": 2,
+	"HUF": 2, "IDR": 2, "ILS": 2, "INR": 2, "ISK": 0, "JOD": 3, "JPY": 0,
+	"KRW": 0, "KWD": 3, "MXN": 2, "NOK": 2, "NZD": 2, "OMR": 3, "PEN": 2,
+	"PLN": 2, "PYG": 0, "SEK": 2, "SGD": 2, "TND": 3, "TRY": 2, "USD": 2,
+	"UYU": 2, "VND": 0, "ZAR": 2,
+}
+
+// Money is an amount in the minor unit of its currency, e.g. cents for USD,
+// so arithmetic never suffers floating point rounding
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// NewMoney returns amount minor units of currency. The code is
+// case-insensitive.
+func NewMoney(amount int64, currency string) (Money, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if _, ok := currencyExponents[currency]; !ok {
+		return Money{}, ErrUnsupportedCurrency
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// MoneyFromDecimal converts an amount in major units, e.g. 12.99, rounding
+// it half away from zero to the currency's minor unit. Input from clients
+// should go through ParseMoney, which rejects amounts that would need
+// rounding.
+func MoneyFromDecimal(value Decimal, currency string) (Money, error) {
+	money, err := NewMoney(0, currency)
+	if err != nil {
+		return Money{}, err
+	}
+	return money.fromMajor(value.rat())
+}
+
+// fromMajor sets m's amount to major units of its currency, rounded to the
+// minor unit
+func (m Money) fromMajor(major *big.Rat) (Money, error) {
+	scaled := new(big.Rat).Mul(major, new(big.Rat).SetInt(pow10(currencyExponents[m.Currency])))
+	minor := roundRat(scaled)
+	if !minor.IsInt64() {
+		return Money{}, fmt.Errorf("amount %s is out of range", major.FloatString(currencyExponents[m.Currency]))
+	}
+	m.Amount = minor.Int64()
+	return m, nil
+}
+
+// ParseMoney reads an amount in major units written as a decimal, e.g.
+// "12.99", rejecting more decimal places than the currency has
+func ParseMoney(amount, currency string) (Money, error) {
+	money, err := NewMoney(0, currency)
+	if err != nil {
+		return Money{}, err
+	}
+	exponent := currencyExponents[money.Currency]
+
+	digits, negative := strings.TrimSpace(amount), false
+	if strings.HasPrefix(digits, "-") {
+		digits, negative = digits[1:], true
+	}
+	whole, fraction, _ := strings.Cut(digits, ".")
+	if whole == "" || strings.Trim(whole+fraction, "0123456789") != "" {
+		return Money{}, fmt.Errorf("amount %q is not a decimal number", amount)
+	}
+	if len(fraction) > exponent {
+		return Money{}, fmt.Errorf("amount %q has more than %d decimal places for %s", amount, exponent, money.Currency)
+	}
+	minor, err := strconv.ParseInt(whole+fraction+strings.Repeat("0", exponent-len(fraction)), 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("amount %q is out of range", amount)
+	}
+	if negative {
+		minor = -minor
+	}
+	money.Amount = minor
+	return money, nil
+}
+
+// CurrencyExponent returns the decimal places of a supported currency's
+// minor unit
+func CurrencyExponent(currency string) (int, bool) {
+	exponent, ok := currencyExponents[strings.ToUpper(currency)]
+	return exponent, ok
+}
+
+// Validate checks that the currency is supported and the amount is not
+// negative
+func (m Money) Validate() error {
+	if _, ok := currencyExponents[m.Currency]; !ok {
+		return ErrUnsupportedCurrency
+	}
+	if m.Amount < 0 {
+		return errors.New("price cannot be negative")
+	}
+	return nil
+}
+
+// Major is the amount in major units, exactly. Compare prices in
+// different currencies with it.
+func (m Money) Major() Decimal {
+	return Decimal{unscaled: m.Amount, scale: currencyExponents[m.Currency]}
+}
+
+// Decimal is the amount in major units as a float. It is for ratios and
+// display; comparisons should use Major and arithmetic Amount.
+func (m Money) Decimal() float64 {
+	return float64(m.Amount) / math.Pow10(currencyExponents[m.Currency])
+}
+
+// DecimalString formats the amount in major units with exactly the
+// currency's decimal places, e.g. "12.50"
+func (m Money) DecimalString() string {
+	exponent := currencyExponents[m.Currency]
+	sign, amount := "", m.Amount
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	digits := strconv.FormatInt(amount, 10)
+	if exponent == 0 {
+		return sign + digits
+	}
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-exponent] + "." + digits[len(digits)-exponent:]
+}
+
+func (m Money) String() string {
+	return m.DecimalString() + " " + m.Currency
+}
+
+// Convert returns the amount in currency to, given how many units of to
+// one unit of m's currency buys, rounded to the target's minor unit
+func (m Money) Convert(to string, rate float64) (Money, error) {
+	if rate <= 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+		return Money{}, ErrNoExchangeRate
+	}
+	converted, err := NewMoney(0, to)
+	if err != nil {
+		return Money{}, err
+	}
+	return converted.fromMajor(new(big.Rat).Mul(m.Major().rat(), new(big.Rat).SetFloat64(rate)))
+}
+
+// UnmarshalJSON accepts {"amount": 1299, "currency": "USD"}, and for
+// clients written before currencies a bare number of major units in
+// DefaultCurrency
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && (data[0] == '-' || (data[0] >= '0' && data[0] <= '9')) {
+		money, err := ParseMoney(string(data), DefaultCurrency)
+		if err != nil {
+			return err
+		}
+		*m = money
+		return nil
+	}
+
+	if len(data) == 0 || data[0] != '{' {
+		return &json.UnmarshalTypeError{Value: jsonKind(data), Type: reflect.TypeOf(Money{})}
+	}
+	type plain Money
+	var decoded plain
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	decoded.Currency = strings.ToUpper(strings.TrimSpace(decoded.Currency))
+	if decoded.Currency == "" {
+		decoded.Currency = DefaultCurrency
+	}
+	*m = Money(decoded)
+	return nil
+}
+
+// jsonKind names the kind of a JSON value for type errors
+func jsonKind(data []byte) string {
+	switch {
+	case len(data) == 0:
+		return "empty"
+	case data[0] == '"':
+		return "string"
+	case data[0] == '[':
+		return "array"
+	default:
+		return "bool"
+	}
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		want     Money
+	}{
+		{"12.99", "usd", Money{Amount: 1299, Currency: "USD"}},
+		{"12.5", "EUR", Money{Amount: 1250, Currency: "EUR"}},
+		{"12", "EUR", Money{Amount: 1200, Currency: "EUR"}},
+		{"1500", "JPY", Money{Amount: 1500, Currency: "JPY"}},
+		{"1.234", "KWD", Money{Amount: 1234, Currency: "KWD"}},
+		{"-3.10", "USD", Money{Amount: -310, Currency: "USD"}},
+	}
+	for _, tt := range tests {
+		money, err := ParseMoney(tt.amount, tt.currency)
+		require.NoError(t, err, tt.amount)
+		assert.Equal(t, tt.want, money)
+	}
+
+	for _, bad := range [][2]string{{"12.999", "USD"}, {"1.5", "JPY"}, {"abc", "USD"}, {".5", "USD"}, {"1", "XYZ"}} {
+		_, err := ParseMoney(bad[0], bad[1])
+		assert.Error(t, err, bad)
+	}
+	_, err := ParseMoney("1", "XYZ")
+	assert.ErrorIs(t, err, ErrUnsupportedCurrency)
+}
+
+func TestMoney_DecimalString(t *testing.T) {
+	assert.Equal(t, "12.50", Money{Amount: 1250, Currency: "USD"}.DecimalString())
+	assert.Equal(t, "0.05", Money{Amount: 5, Currency: "USD"}.DecimalString())
+	assert.Equal(t, "-0.05", Money{Amount: -5, Currency: "USD"}.DecimalString())
+	assert.Equal(t, "1500", Money{Amount: 1500, Currency: "JPY"}.DecimalString())
+	assert.Equal(t, "1.234 KWD", Money{Amount: 1234, Currency: "KWD"}.String())
+}
+
+func TestMoney_Convert(t *testing.T) {
+	converted, err := Money{Amount: 1000, Currency: "USD"}.Convert("JPY", 149.526)
+	require.NoError(t, err)
+	assert.Equal(t, Money{Amount: 1495, Currency: "JPY"}, converted)
+
+	_, err = Money{Amount: 1000, Currency: "USD"}.Convert("EUR", 0)
+	assert.ErrorIs(t, err, ErrNoExchangeRate)
+}
+
+func TestMoney_UnmarshalJSON(t *testing.T) {
+	var money Money
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":1999,"currency":"eur"}`), &money))
+	assert.Equal(t, Money{Amount: 1999, Currency: "EUR"}, money)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":1999}`), &money))
+	assert.Equal(t, Money{Amount: 1999, Currency: DefaultCurrency}, money)
+
+	require.NoError(t, json.Unmarshal([]byte(`19.99`), &money))
+	assert.Equal(t, Money{Amount: 1999, Currency: DefaultCurrency}, money)
+
+	var typeErr *json.UnmarshalTypeError
+	assert.ErrorAs(t, json.Unmarshal([]byte(`"19.99"`), &money), &typeErr)
+	assert.Error(t, json.Unmarshal([]byte(`19.999`), &money))
+
+	data, err := json.Marshal(Money{Amount: 1999, Currency: "EUR"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount":1999,"currency":"EUR"}`, string(data))
+}
+
+func TestNewProduct_ValidatesPrice(t *testing.T) {
+	_, err := NewProduct(testID, "Hat", "", Money{Amount: 1000, Currency: "XYZ"})
+	assert.ErrorIs(t, err, ErrUnsupportedCurrency)
+	_, err = NewProduct(testID, "Hat", "", Money{Amount: -1, Currency: "USD"})
+	assert.Error(t, err)
+}
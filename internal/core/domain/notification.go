@@ -0,0 +1,165 @@
+package domain
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"text/template"
+	"time"
+)
+
+// ErrInvalidNotificationRule is returned for rules that cannot be used
+var ErrInvalidNotificationRule = NewError(KindValidation, "invalid notification rule")
+
+// Notification is a rendered message ready to be sent
+type Notification struct {
+	Rule    string
+	To      []string
+	Subject string
+	Body    string
+}
+
+// NotificationRule emails Recipients whenever a product event of type Event
+// is committed. When MinPrice is set, in major units of Currency (USD by
+// default), the product must also be priced in Currency at no less than
+// that; products in other currencies never match. Subject and Body are
+// text/template strings executed against the event's NotificationData, e.g.
+// {{.ProductID}} or {{.Properties.name}}.
+type NotificationRule struct {
+	Name       string   `json:"name"`
+	Event      string   `json:"event"`
+	MinPrice   *Decimal `json:"min_price,omitempty"`
+	Currency   string   `json:"currency,omitempty"`
+	Recipients []string `json:"recipients"`
+	Subject    string   `json:"subject"`
+	Body       string   `json:"body"`
+
+	minPrice *Money
+	subject  *template.Template
+	body     *template.Template
+}
+
+// ParseNotificationRules decodes a JSON array of rules and compiles their
+// templates, so a bad rule is reported at startup rather than when it fires
+func ParseNotificationRules(data []byte) ([]NotificationRule, error) {
+	var rules []NotificationRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidNotificationRule, err)
+	}
+
+	for i := range rules {
+		if err := rules[i].compile(); err != nil {
+			return nil, err
+		}
+	}
+	return rules, nil
+}
+
+func (r *NotificationRule) compile() error {
+	if r.Name == "" || r.Event == "" {
+		return fmt.Errorf("%w: name and event are required", ErrInvalidNotificationRule)
+	}
+	if len(r.Recipients) == 0 {
+		return fmt.Errorf("%w: rule %q has no recipients", ErrInvalidNotificationRule, r.Name)
+	}
+	if r.Currency != "" && r.MinPrice == nil {
+		return fmt.Errorf("%w: rule %q has a currency but no min_price", ErrInvalidNotificationRule, r.Name)
+	}
+	if r.MinPrice != nil {
+		minPrice, err := ParseMoney(r.MinPrice.String(), cmp.Or(r.Currency, DefaultCurrency))
+		if err != nil {
+			return fmt.Errorf("%w: rule %q min_price: %v", ErrInvalidNotificationRule, r.Name, err)
+		}
+		r.minPrice = &minPrice
+	}
+
+	var err error
+	if r.subject, err = template.New(r.Name + ".subject").Parse(r.Subject); err != nil {
+		return fmt.Errorf("%w: rule %q subject: %v", ErrInvalidNotificationRule, r.Name, err)
+	}
+	if r.body, err = template.New(r.Name + ".body").Parse(r.Body); err != nil {
+		return fmt.Errorf("%w: rule %q body: %v", ErrInvalidNotificationRule, r.Name, err)
+	}
+	return nil
+}
+
+// NotificationData is what rule templates are executed against: the
+// event's type, product and time, and the product's name, price,
+// currency, status, moderation_status, moderation reasons and, while
+// scheduled, auto_archive_at as Properties
+type NotificationData struct {
+	Type       string
+	ProductID  string
+	Properties map[string]interface{}
+	OccurredAt time.Time
+
+	price *Money
+}
+
+// NewNotificationData describes event for rule templates. Deletes carry no
+// product, and so only replaced_by among the properties.
+func NewNotificationData(event ProductEvent) NotificationData {
+	properties := map[string]interface{}{}
+	if event.Product != nil {
+		product := event.Product
+		properties["name"] = product.Name
+		properties["price"] = product.Price.Decimal()
+		properties["currency"] = product.Price.Currency
+		properties["status"] = product.Status
+		properties["moderation_status"] = product.ModerationStatus
+		properties["reasons"] = product.ModerationReasons
+		if product.AutoArchiveAt != nil {
+			properties["auto_archive_at"] = *product.AutoArchiveAt
+		}
+	}
+	if event.ReplacedBy != "" {
+		properties["replaced_by"] = event.ReplacedBy
+	}
+	data := NotificationData{
+		Type:       event.Type,
+		ProductID:  event.ProductID,
+		Properties: properties,
+		OccurredAt: event.OccurredAt,
+	}
+	if event.Product != nil {
+		data.price = &event.Product.Price
+	}
+	return data
+}
+
+// Matches reports whether the rule fires for event
+func (r NotificationRule) Matches(event NotificationData) bool {
+	if event.Type != r.Event {
+		return false
+	}
+	if r.minPrice != nil {
+		price := event.price
+		if price == nil || price.Currency != r.minPrice.Currency || price.Amount < r.minPrice.Amount {
+			return false
+		}
+	}
+	return true
+}
+
+// Render executes the rule's templates against event
+func (r NotificationRule) Render(event NotificationData) (Notification, error) {
+	if r.subject == nil || r.body == nil {
+		return Notification{}, fmt.Errorf("%w: rule %q was not parsed", ErrInvalidNotificationRule, r.Name)
+	}
+
+	var subject, body bytes.Buffer
+	if err := r.subject.Execute(&subject, event); err != nil {
+		return Notification{}, fmt.Errorf("failed to render subject of rule %q: %w", r.Name, err)
+	}
+	if err := r.body.Execute(&body, event); err != nil {
+		return Notification{}, fmt.Errorf("failed to render body of rule %q: %w", r.Name, err)
+	}
+
+	return Notification{
+		Rule:    r.Name,
+		To:      r.Recipients,
+		Subject: subject.String(),
+		Body:    body.String(),
+	}, nil
+}
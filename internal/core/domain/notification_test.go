@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNotificationRules(t *testing.T) {
+	rules, err := ParseNotificationRules([]byte(`[
+		{
+			"name": "expensive-products",
+			"event": "product.created",
+			"min_price": 1000,
+			"recipients": ["catalog@example.com"],
+			"subject": "New product: {{.Properties.name}}",
+			"body": "{{.ProductID}} was created at {{.Properties.price}}"
+		}
+	]`))
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+
+	rule := rules[0]
+	cheap := Product{ID: "prod-2", Name: "Mug", Price: Money{Amount: 99900, Currency: "USD"}}
+	expensive := Product{ID: "prod-1", Name: "Espresso Machine", Price: Money{Amount: 150000, Currency: "USD"}}
+	// 1000 USD is 100000 cents, so one cent less is below the minimum
+	justBelow := Product{ID: "prod-4", Name: "Grinder", Price: Money{Amount: 99999, Currency: "USD"}}
+	inYen := Product{ID: "prod-5", Name: "Kettle", Price: Money{Amount: 500000, Currency: "JPY"}}
+	assert.False(t, rule.Matches(NewNotificationData(NewProductEvent(EventProductCreated, cheap.ID, &cheap, time.Now()))))
+	assert.False(t, rule.Matches(NewNotificationData(NewProductEvent(EventProductCreated, justBelow.ID, &justBelow, time.Now()))))
+	assert.False(t, rule.Matches(NewNotificationData(NewProductEvent(EventProductCreated, inYen.ID, &inYen, time.Now()))))
+	assert.False(t, rule.Matches(NewNotificationData(NewProductEvent(EventProductUpdated, expensive.ID, &expensive, time.Now()))))
+	assert.False(t, rule.Matches(NewNotificationData(NewProductEvent(EventProductCreated, "prod-3", nil, time.Now()))))
+
+	event := NewNotificationData(NewProductEvent(EventProductCreated, expensive.ID, &expensive, time.Now()))
+	require.True(t, rule.Matches(event))
+
+	notification, err := rule.Render(event)
+	require.NoError(t, err)
+	assert.Equal(t, "expensive-products", notification.Rule)
+	assert.Equal(t, []string{"catalog@example.com"}, notification.To)
+	assert.Equal(t, "New product: Espresso Machine", notification.Subject)
+	assert.Equal(t, "prod-1 was created at 1500", notification.Body)
+}
+
+func TestNotificationRule_MinPriceCurrency(t *testing.T) {
+	rules, err := ParseNotificationRules([]byte(`[
+		{"name": "expensive-in-euros", "event": "product.created", "min_price": "49.90", "currency": "eur",
+		 "recipients": ["catalog@example.com"], "subject": "{{.ProductID}}", "body": ""}
+	]`))
+	require.NoError(t, err)
+	rule := rules[0]
+
+	matches := func(price Money) bool {
+		product := Product{ID: "prod-1", Price: price}
+		return rule.Matches(NewNotificationData(NewProductEvent(EventProductCreated, product.ID, &product, time.Now())))
+	}
+	assert.True(t, matches(Money{Amount: 4990, Currency: "EUR"}))
+	assert.False(t, matches(Money{Amount: 4989, Currency: "EUR"}))
+	assert.False(t, matches(Money{Amount: 9990, Currency: "USD"}))
+}
+
+func TestParseNotificationRules_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"malformed JSON":        `{`,
+		"missing event":         `[{"name":"a","recipients":["x@example.com"]}]`,
+		"no recipients":         `[{"name":"a","event":"product.created"}]`,
+		"broken template":       `[{"name":"a","event":"product.created","recipients":["x@example.com"],"subject":"{{.Type"}]`,
+		"unknown currency":      `[{"name":"a","event":"product.created","min_price":10,"currency":"XYZ","recipients":["x@example.com"]}]`,
+		"min_price too precise": `[{"name":"a","event":"product.created","min_price":"10.5","currency":"JPY","recipients":["x@example.com"]}]`,
+		"currency alone":        `[{"name":"a","event":"product.created","currency":"EUR","recipients":["x@example.com"]}]`,
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseNotificationRules([]byte(data))
+			assert.ErrorIs(t, err, ErrInvalidNotificationRule)
+		})
+	}
+}
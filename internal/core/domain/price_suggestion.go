@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+var (
+	ErrNoPriceSuggestion  = NewError(KindNotFound, "no price suggestion for this product")
+	ErrPricingUnavailable = NewError(KindUnavailable, "price suggestions are temporarily unavailable")
+)
+
+// PriceSuggestion is a price recommended for a product by an external
+// pricing service, next to the price it has now
+type PriceSuggestion struct {
+	ProductID      string `json:"product_id"`
+	CurrentPrice   Money  `json:"current_price"`
+	SuggestedPrice Money  `json:"suggested_price"`
+	// Confidence is the service's confidence in the suggestion, from 0 to
+	// 1, when it reports one
+	Confidence  *float64  `json:"confidence,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+	SuggestedAt time.Time `json:"suggested_at"`
+}
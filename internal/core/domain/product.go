@@ -1,42 +1,267 @@
 package domain
 
 import (
+	"cmp"
 	"errors"
+	"fmt"
 	"time"
-
-	"github.com/google/uuid"
 )
 
 var (
-	ErrInvalidProduct = errors.New("invalid product data")
-	ErrNotFound       = errors.New("product not found")
+	ErrInvalidProduct = NewError(KindValidation, "invalid product data")
+	ErrNotFound       = NewError(KindNotFound, "product not found")
+	ErrForbiddenQuery = NewError(KindValidation, "only read-only SELECT statements are allowed")
+	ErrInvalidCursor  = NewError(KindValidation, "invalid pagination cursor")
+	ErrConflict       = NewError(KindConflict, "product was modified concurrently")
+	// ErrProductID is a failure of the ID generator, not of the product
+	ErrProductID = errors.New("failed to generate product ID")
+)
+
+// Product statuses. Items written before statuses existed have none and are
+// treated as published. Published is the active status: the only one shown
+// in listings. See CanTransition for the moves between them.
+const (
+	StatusDraft        = "draft"
+	StatusPublished    = "published"
+	StatusArchived     = "archived"
+	StatusDiscontinued = "discontinued"
 )
 
 type Product struct {
-	ID          string    `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Price       float64   `json:"price"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          string    `json:"id" dynamodbav:"id"`
+	Name        string    `json:"name" dynamodbav:"name"`
+	Description string    `json:"description" dynamodbav:"description"`
+	Price       Money     `json:"price" dynamodbav:"-"`
+	CreatedAt   time.Time `json:"created_at" dynamodbav:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" dynamodbav:"updated_at"`
+	// ExpiresAt is stored as epoch seconds so DynamoDB TTL can purge the item
+	ExpiresAt *time.Time `json:"expires_at,omitempty" dynamodbav:"expires_at,omitempty,unixtime"`
+	Status    string     `json:"status" dynamodbav:"status,omitempty"`
+	// PublishAt is when a draft goes live, stored as epoch seconds so the
+	// publishing job can compare it in a filter expression
+	PublishAt *time.Time `json:"publish_at,omitempty" dynamodbav:"publish_at,omitempty,unixtime"`
+	// AutoArchiveAt is when a seasonal product is archived automatically.
+	// ArchiveWarnedAt records the warning sent ahead of it.
+	AutoArchiveAt   *time.Time `json:"auto_archive_at,omitempty" dynamodbav:"auto_archive_at,omitempty,unixtime"`
+	ArchiveWarnedAt *time.Time `json:"archive_warned_at,omitempty" dynamodbav:"archive_warned_at,omitempty,unixtime"`
+	// ModerationStatus and ModerationReasons track content screening
+	ModerationStatus  string   `json:"moderation_status,omitempty" dynamodbav:"moderation_status,omitempty"`
+	ModerationReasons []string `json:"moderation_reasons,omitempty" dynamodbav:"moderation_reasons,omitempty"`
+	// CostPrice is confidential and never serialized to JSON; admin
+	// responses expose it explicitly. It is always in the price's currency
+	// and, like the price, stored as a number in major units.
+	CostPrice *Money `json:"-" dynamodbav:"-"`
+	// Version counts the writes to the product for optimistic locking.
+	// Items written before versioning existed have none.
+	Version int64 `json:"version" dynamodbav:"version,omitempty"`
+	// CategoryID references a Category; empty leaves the product uncategorized
+	CategoryID string `json:"category_id,omitempty" dynamodbav:"category_id,omitempty"`
+	// Stock is the quantity on hand. It only changes through atomic stock
+	// adjustments, never through product updates.
+	Stock int64 `json:"stock" dynamodbav:"stock"`
+	// RatingCount and RatingSum total the ratings of the product's reviews.
+	// Like the stock, they only change atomically with review writes.
+	RatingCount int64 `json:"rating_count,omitempty" dynamodbav:"rating_count,omitempty"`
+	RatingSum   int64 `json:"rating_sum,omitempty" dynamodbav:"rating_sum,omitempty"`
+	// FavoriteCount is how many users have the product on their wish list,
+	// kept only when favorite counts are enabled
+	FavoriteCount int64 `json:"favorite_count,omitempty" dynamodbav:"favorite_count,omitempty"`
+	// TenantID owns the product; empty for the default tenant
+	TenantID string `json:"tenant_id,omitempty" dynamodbav:"tenant_id,omitempty"`
+	// Images is the product's gallery, in display order
+	Images []ProductImage `json:"images,omitempty" dynamodbav:"images,omitempty"`
+	// Tags are lowercase labels for filtering, kept in the order given
+	Tags []string `json:"tags,omitempty" dynamodbav:"tags,omitempty"`
+	// SKU and Barcode are optional and unique within the tenant
+	SKU     string `json:"sku,omitempty" dynamodbav:"sku,omitempty"`
+	Barcode string `json:"barcode,omitempty" dynamodbav:"barcode,omitempty"`
+	// Translations holds the name and description in other locales, keyed
+	// by canonical BCP 47 tag
+	Translations map[string]Translation `json:"translations,omitempty" dynamodbav:"translations,omitempty"`
 }
 
-// NewProduct Factory para crear un producto válido
-func NewProduct(name, description string, price float64) (*Product, error) {
+// NewProduct Factory para crear un producto válido, named by newID
+func NewProduct(newID func() (string, error), name, description string, price Money) (*Product, error) {
 	if name == "" {
 		return nil, errors.New("name is required")
 	}
-	if price < 0 {
-		return nil, errors.New("price cannot be negative")
+	if err := price.Validate(); err != nil {
+		return nil, err
+	}
+	id, err := newID()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrProductID, err)
 	}
 
 	now := time.Now().UTC()
 	return &Product{
-		ID:          uuid.New().String(),
+		ID:          id,
 		Name:        name,
 		Description: description,
 		Price:       price,
 		CreatedAt:   now,
 		UpdatedAt:   now,
+		Status:      StatusPublished,
+		Version:     1,
 	}, nil
 }
+
+// SetPrice replaces the product's price
+func (p *Product) SetPrice(price Money) error {
+	if err := price.Validate(); err != nil {
+		return err
+	}
+	p.Price = price
+	return nil
+}
+
+// SetExpiration sets when a time-limited product stops being visible.
+// A nil value removes the expiration.
+func (p *Product) SetExpiration(expiresAt *time.Time, now time.Time) error {
+	if expiresAt == nil {
+		p.ExpiresAt = nil
+		return nil
+	}
+	if !expiresAt.After(now) {
+		return errors.New("expires_at must be in the future")
+	}
+	utc := expiresAt.UTC()
+	p.ExpiresAt = &utc
+	return nil
+}
+
+// IsExpired reports whether the product has passed its expiration. DynamoDB
+// purges expired items lazily, so reads must check this themselves.
+func (p Product) IsExpired(now time.Time) bool {
+	return p.ExpiresAt != nil && !p.ExpiresAt.After(now)
+}
+
+// SchedulePublish keeps a draft unpublished until publishAt. A nil value
+// publishes a draft right away and leaves other statuses alone. Only drafts
+// can be scheduled: no transition leads back to draft, so a product that
+// was ever published leaves listings by being archived instead.
+func (p *Product) SchedulePublish(publishAt *time.Time, now time.Time) error {
+	if publishAt == nil {
+		p.PublishAt = nil
+		if p.Status == StatusDraft {
+			return p.TransitionTo(StatusPublished, now)
+		}
+		return nil
+	}
+	if p.Status != StatusDraft {
+		return fmt.Errorf("%w: only a draft can be scheduled for publishing, not a %s product", ErrInvalidTransition, cmp.Or(p.Status, StatusPublished))
+	}
+	if !publishAt.After(now) {
+		return errors.New("publish_at must be in the future")
+	}
+	utc := publishAt.UTC()
+	p.PublishAt = &utc
+	return nil
+}
+
+// IsPublished reports whether the product is visible in listings
+func (p Product) IsPublished() bool {
+	return p.Status == "" || p.Status == StatusPublished
+}
+
+// IsDueForPublishing reports whether a scheduled draft has reached its
+// publish_at
+func (p Product) IsDueForPublishing(now time.Time) bool {
+	return p.Status == StatusDraft && p.PublishAt != nil && !p.PublishAt.After(now)
+}
+
+// IsLiveAt reports whether the product is listed at now by its schedule:
+// published and not yet due for archival, or a draft due for publishing.
+// The stored status only catches up when the publishing and archiving
+// jobs run.
+func (p Product) IsLiveAt(now time.Time) bool {
+	live := p.IsPublished() || p.IsDueForPublishing(now)
+	return live && (p.AutoArchiveAt == nil || p.AutoArchiveAt.After(now))
+}
+
+// Publish makes a draft visible
+func (p *Product) Publish(now time.Time) {
+	p.Status = StatusPublished
+	p.PublishAt = nil
+	p.UpdatedAt = now
+}
+
+// SetAutoArchive schedules the product's archival. A nil value cancels it.
+// Moving the date resets the pre-archive warning so owners are warned again.
+func (p *Product) SetAutoArchive(autoArchiveAt *time.Time, now time.Time) error {
+	if autoArchiveAt == nil {
+		p.AutoArchiveAt = nil
+		p.ArchiveWarnedAt = nil
+		return nil
+	}
+	if !autoArchiveAt.After(now) {
+		return errors.New("auto_archive_at must be in the future")
+	}
+	utc := autoArchiveAt.UTC()
+	if p.AutoArchiveAt == nil || !p.AutoArchiveAt.Equal(utc) {
+		p.ArchiveWarnedAt = nil
+	}
+	p.AutoArchiveAt = &utc
+	return nil
+}
+
+// IsDueForArchival reports whether a published product has reached its
+// auto_archive_at
+func (p Product) IsDueForArchival(now time.Time) bool {
+	return p.IsPublished() && p.AutoArchiveAt != nil && !p.AutoArchiveAt.After(now)
+}
+
+// NeedsArchiveWarning reports whether a published product will be archived
+// within window and its owners have not been warned yet
+func (p Product) NeedsArchiveWarning(now time.Time, window time.Duration) bool {
+	return p.IsPublished() && p.AutoArchiveAt != nil && p.ArchiveWarnedAt == nil &&
+		!p.AutoArchiveAt.After(now.Add(window))
+}
+
+// Archive retires a published product. Drafts are never archived, they are
+// simply not published.
+func (p *Product) Archive(now time.Time) error {
+	return p.TransitionTo(StatusArchived, now)
+}
+
+// SetCostPrice records what the product costs, given in major units of the
+// price's currency, so set the price first. A nil value leaves the current
+// cost untouched, since only admins may send one, unless the price has
+// moved to another currency than the cost's.
+func (p *Product) SetCostPrice(costPrice *Decimal) error {
+	if costPrice == nil {
+		if p.CostPrice != nil && p.CostPrice.Currency != p.Price.Currency {
+			return fmt.Errorf("cost_price must be given again when the price changes to %s", p.Price.Currency)
+		}
+		return nil
+	}
+	if costPrice.Sign() < 0 {
+		return errors.New("cost_price cannot be negative")
+	}
+	cost, err := ParseMoney(costPrice.String(), p.Price.Currency)
+	if err != nil {
+		return fmt.Errorf("invalid cost_price: %w", err)
+	}
+	p.CostPrice = &cost
+	return nil
+}
+
+// Margin is the share of the price left after cost, e.g. 0.25 for 25%,
+// worked out from the amounts in minor units. It reports false when the
+// cost is unknown or the product is free.
+func (p Product) Margin() (float64, bool) {
+	profit, ok := p.Profit()
+	if !ok || p.Price.Amount <= 0 {
+		return 0, false
+	}
+	return float64(profit.Amount) / float64(p.Price.Amount), true
+}
+
+// Profit is the price less the cost, in the price's currency. It reports
+// false when the cost is unknown.
+func (p Product) Profit() (Money, bool) {
+	if p.CostPrice == nil || p.CostPrice.Currency != p.Price.Currency {
+		return Money{}, false
+	}
+	return Money{Amount: p.Price.Amount - p.CostPrice.Amount, Currency: p.Price.Currency}, true
+}
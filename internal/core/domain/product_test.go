@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testID() (string, error) {
+	return "0b4c1d3e-5f6a-4b7c-8d9e-0f1a2b3c4d5e", nil
+}
+
+func TestNewProduct_GeneratorFailure(t *testing.T) {
+	failing := func() (string, error) { return "", errors.New("no entropy") }
+
+	_, err := NewProduct(failing, "Launch", "", Money{Amount: 1000, Currency: "USD"})
+	assert.ErrorIs(t, err, ErrProductID)
+	_, err = NewProduct(failing, "", "", Money{Amount: 1000, Currency: "USD"})
+	assert.NotErrorIs(t, err, ErrProductID, "invalid products fail before an ID is generated")
+}
+
+func TestSchedulePublish(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	product, err := NewProduct(testID, "Launch", "", Money{Amount: 1000, Currency: "USD"})
+	require.NoError(t, err)
+	assert.True(t, product.IsPublished())
+
+	// Published products never go back to draft
+	launch := now.Add(48 * time.Hour)
+	assert.ErrorIs(t, product.SchedulePublish(&launch, now), ErrInvalidTransition)
+	assert.True(t, product.IsPublished())
+	product.Status = StatusArchived
+	assert.ErrorIs(t, product.SchedulePublish(&launch, now), ErrInvalidTransition)
+	assert.Equal(t, StatusArchived, product.Status)
+
+	product.Status = StatusDraft
+	require.NoError(t, product.SchedulePublish(&launch, now))
+	assert.False(t, product.IsPublished())
+	assert.False(t, product.IsDueForPublishing(now))
+	assert.True(t, product.IsDueForPublishing(launch))
+
+	product.Publish(launch)
+	assert.True(t, product.IsPublished())
+	assert.Nil(t, product.PublishAt)
+	assert.Equal(t, launch, product.UpdatedAt)
+
+	past := now.Add(-time.Minute)
+	product.Status = StatusDraft
+	assert.Error(t, product.SchedulePublish(&past, now))
+
+	// Without a schedule a draft is published right away
+	require.NoError(t, product.SchedulePublish(nil, now))
+	assert.Equal(t, StatusPublished, product.Status)
+	assert.Equal(t, now, product.UpdatedAt)
+}
+
+func TestIsLiveAt(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Minute), now.Add(time.Minute)
+
+	assert.True(t, Product{}.IsLiveAt(now))
+	assert.True(t, Product{Status: StatusDraft, PublishAt: &past}.IsLiveAt(now))
+	assert.False(t, Product{Status: StatusDraft, PublishAt: &future}.IsLiveAt(now))
+	assert.False(t, Product{Status: StatusDraft}.IsLiveAt(now))
+	assert.False(t, Product{Status: StatusPublished, AutoArchiveAt: &past}.IsLiveAt(now))
+	assert.True(t, Product{Status: StatusPublished, AutoArchiveAt: &future}.IsLiveAt(now))
+	assert.False(t, Product{Status: StatusArchived}.IsLiveAt(now))
+}
+
+func TestIsPublishedWithoutStatus(t *testing.T) {
+	// Items stored before statuses existed are live
+	assert.True(t, Product{}.IsPublished())
+}
+
+func TestAutoArchive(t *testing.T) {
+	now := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	product, err := NewProduct(testID, "Summer Hat", "", Money{Amount: 1500, Currency: "USD"})
+	require.NoError(t, err)
+
+	endOfSeason := now.Add(10 * 24 * time.Hour)
+	require.NoError(t, product.SetAutoArchive(&endOfSeason, now))
+	assert.False(t, product.NeedsArchiveWarning(now, 72*time.Hour))
+	assert.True(t, product.NeedsArchiveWarning(endOfSeason.Add(-time.Hour), 72*time.Hour))
+
+	// Extending the window resets the warning
+	warned := endOfSeason.Add(-time.Hour)
+	product.ArchiveWarnedAt = &warned
+	extended := endOfSeason.Add(7 * 24 * time.Hour)
+	require.NoError(t, product.SetAutoArchive(&extended, now))
+	assert.Nil(t, product.ArchiveWarnedAt)
+
+	assert.False(t, product.IsDueForArchival(endOfSeason))
+	assert.True(t, product.IsDueForArchival(extended))
+	require.NoError(t, product.Archive(extended))
+	assert.Equal(t, StatusArchived, product.Status)
+	assert.False(t, product.IsPublished())
+	assert.Nil(t, product.AutoArchiveAt)
+}
+
+func TestArchiveRequiresPublished(t *testing.T) {
+	now := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	launch := now.Add(time.Hour)
+	product, err := NewProduct(testID, "Preview", "", Money{Amount: 1500, Currency: "USD"})
+	require.NoError(t, err)
+	product.Status = StatusDraft
+	require.NoError(t, product.SchedulePublish(&launch, now))
+
+	assert.Error(t, product.Archive(now))
+	assert.Equal(t, StatusDraft, product.Status)
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"math"
+	"sort"
+)
+
+// Recommendation is a product suggested alongside another one, scored by the
+// recommender that produced it; higher is more relevant
+type Recommendation struct {
+	ProductID string  `json:"product_id"`
+	Score     float64 `json:"score"`
+}
+
+// RankRecommendations orders scores from most to least relevant, breaking ties
+// by product ID so the ranking is deterministic, and keeps the top n
+func RankRecommendations(scores map[string]float64, n int) []Recommendation {
+	ranked := make([]Recommendation, 0, len(scores))
+	for id, score := range scores {
+		ranked = append(ranked, Recommendation{ProductID: id, Score: score})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].ProductID < ranked[j].ProductID
+	})
+	if len(ranked) > n {
+		ranked = ranked[:n]
+	}
+	return ranked
+}
+
+// SimilarityScore rates how alike candidate is to product from catalog
+// attributes alone: 1 for sharing its category, plus up to 1 for a price
+// in the same currency within band, a fraction of product's price. The
+// price part falls linearly to 0 at the edge of the band.
+func SimilarityScore(product, candidate Product, band float64) float64 {
+	score := 0.0
+	if product.CategoryID != "" && candidate.CategoryID == product.CategoryID {
+		score++
+	}
+	if band > 0 && product.Price.Amount > 0 && candidate.Price.Currency == product.Price.Currency {
+		width := band * float64(product.Price.Amount)
+		distance := math.Abs(float64(candidate.Price.Amount - product.Price.Amount))
+		if distance < width {
+			score += 1 - distance/width
+		}
+	}
+	return math.Round(score*1000) / 1000
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRankRecommendations(t *testing.T) {
+	scores := map[string]float64{"a": 2, "b": 7, "c": 2, "d": 0.5}
+
+	assert.Equal(t, []Recommendation{
+		{ProductID: "b", Score: 7},
+		{ProductID: "a", Score: 2},
+	}, RankRecommendations(scores, 2))
+
+	assert.Len(t, RankRecommendations(scores, 10), 4)
+	assert.Empty(t, RankRecommendations(map[string]float64{}, 10))
+}
+
+func TestSimilarityScore(t *testing.T) {
+	product := Product{ID: "p", CategoryID: "audio", Price: Money{Amount: 10000, Currency: "USD"}}
+
+	tests := []struct {
+		name      string
+		candidate Product
+		score     float64
+	}{
+		{"same category and price", Product{CategoryID: "audio", Price: Money{Amount: 10000, Currency: "USD"}}, 2},
+		{"same category, price outside band", Product{CategoryID: "audio", Price: Money{Amount: 50000, Currency: "USD"}}, 1},
+		{"price halfway to the band edge", Product{CategoryID: "video", Price: Money{Amount: 11000, Currency: "USD"}}, 0.5},
+		{"cheaper within band", Product{Price: Money{Amount: 9500, Currency: "USD"}}, 0.75},
+		{"other currency", Product{Price: Money{Amount: 10000, Currency: "EUR"}}, 0},
+		{"unrelated", Product{CategoryID: "video", Price: Money{Amount: 100, Currency: "USD"}}, 0},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.score, SimilarityScore(product, tt.candidate, 0.2), tt.name)
+	}
+
+	assert.Equal(t, 0.0, SimilarityScore(Product{Price: product.Price}, Product{}, 0.2), "no category matches nothing")
+	assert.Equal(t, 1.0, SimilarityScore(product, Product{CategoryID: "audio", Price: product.Price}, 0), "a zero band ignores prices")
+}
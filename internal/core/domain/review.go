@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrReviewNotFound = NewError(KindNotFound, "review not found")
+	ErrInvalidRating  = fmt.Errorf("rating must be between %d and %d", MinRating, MaxRating)
+	ErrCommentTooLong = fmt.Errorf("comment cannot be longer than %d characters", MaxReviewCommentLength)
+	// ErrNotReviewAuthor is returned when a caller edits or deletes a
+	// review someone else wrote
+	ErrNotReviewAuthor = errors.New("only the author may change a review")
+)
+
+const (
+	MinRating = 1
+	MaxRating = 5
+	// MaxReviewCommentLength is in characters
+	MaxReviewCommentLength = 2000
+)
+
+// Review is a customer's rating of a product, with an optional comment.
+// IDs are time-ordered, so sorting by ID sorts by creation.
+type Review struct {
+	ID        string    `json:"id"`
+	ProductID string    `json:"product_id"`
+	TenantID  string    `json:"tenant_id,omitempty"`
+	Rating    int       `json:"rating"`
+	Comment   string    `json:"comment,omitempty"`
+	Author    string    `json:"author"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RatingSummary is the average of a product's review ratings, rounded to two
+// decimals, and how many there are
+type RatingSummary struct {
+	Average float64 `json:"average"`
+	Count   int64   `json:"count"`
+}
+
+// NewReview creates a review by author, recorded as ActorAnonymous when
+// empty
+func NewReview(productID, tenantID, author string, rating int, comment string, now time.Time) (Review, error) {
+	comment, err := validateReview(rating, comment)
+	if err != nil {
+		return Review{}, err
+	}
+	if author == "" {
+		author = ActorAnonymous
+	}
+	id, err := uuid.NewV7()
+	if err != nil {
+		return Review{}, fmt.Errorf("failed to generate review ID: %w", err)
+	}
+	return Review{
+		ID:        id.String(),
+		ProductID: productID,
+		TenantID:  tenantID,
+		Rating:    rating,
+		Comment:   comment,
+		Author:    author,
+		CreatedAt: now.UTC(),
+		UpdatedAt: now.UTC(),
+	}, nil
+}
+
+// Edit replaces the rating and comment of the review
+func (r *Review) Edit(rating int, comment string, now time.Time) error {
+	comment, err := validateReview(rating, comment)
+	if err != nil {
+		return err
+	}
+	r.Rating = rating
+	r.Comment = comment
+	r.UpdatedAt = now.UTC()
+	return nil
+}
+
+// validateReview returns the comment trimmed of surrounding whitespace
+func validateReview(rating int, comment string) (string, error) {
+	if rating < MinRating || rating > MaxRating {
+		return "", ErrInvalidRating
+	}
+	comment = strings.TrimSpace(comment)
+	if utf8.RuneCountInString(comment) > MaxReviewCommentLength {
+		return "", ErrCommentTooLong
+	}
+	return comment, nil
+}
+
+// Rating summarizes the ratings of the product's reviews
+func (p Product) Rating() RatingSummary {
+	if p.RatingCount <= 0 {
+		return RatingSummary{}
+	}
+	average := float64(p.RatingSum) / float64(p.RatingCount)
+	return RatingSummary{Average: math.Round(average*100) / 100, Count: p.RatingCount}
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReview(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	review, err := NewReview("p1", "acme", "", 4, "  Solid build  ", now)
+	require.NoError(t, err)
+	assert.Equal(t, "p1", review.ProductID)
+	assert.Equal(t, "acme", review.TenantID)
+	assert.Equal(t, ActorAnonymous, review.Author)
+	assert.Equal(t, "Solid build", review.Comment)
+	assert.Equal(t, now, review.CreatedAt)
+
+	later, err := NewReview("p1", "", "user-1", 5, "", now)
+	require.NoError(t, err)
+	assert.Less(t, review.ID, later.ID, "IDs sort by creation")
+
+	for _, rating := range []int{0, 6, -1} {
+		_, err := NewReview("p1", "", "user-1", rating, "", now)
+		assert.ErrorIs(t, err, ErrInvalidRating)
+	}
+	_, err = NewReview("p1", "", "user-1", 3, strings.Repeat("é", MaxReviewCommentLength+1), now)
+	assert.ErrorIs(t, err, ErrCommentTooLong)
+}
+
+func TestReview_Edit(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	review, err := NewReview("p1", "", "user-1", 2, "meh", now)
+	require.NoError(t, err)
+
+	require.NoError(t, review.Edit(5, "grew on me", now.Add(time.Hour)))
+	assert.Equal(t, 5, review.Rating)
+	assert.Equal(t, "grew on me", review.Comment)
+	assert.Equal(t, now, review.CreatedAt)
+	assert.Equal(t, now.Add(time.Hour), review.UpdatedAt)
+
+	assert.ErrorIs(t, review.Edit(9, "", now), ErrInvalidRating)
+	assert.Equal(t, 5, review.Rating)
+}
+
+func TestProduct_Rating(t *testing.T) {
+	assert.Equal(t, RatingSummary{}, Product{}.Rating())
+	assert.Equal(t, RatingSummary{Average: 4.33, Count: 3}, Product{RatingCount: 3, RatingSum: 13}.Rating())
+	assert.Equal(t, RatingSummary{Average: 5, Count: 1}, Product{RatingCount: 1, RatingSum: 5}.Rating())
+}
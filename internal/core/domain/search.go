@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+)
+
+// ErrInvalidSearch is returned for empty search queries
+var ErrInvalidSearch = NewError(KindValidation, "search query must not be empty")
+
+// SearchHit is a product matching a search, with its relevance score.
+// Higher scores rank first; scores are only comparable within one search.
+// Highlights holds, by field, the fragments that matched with the matched
+// terms wrapped in <em> tags, when the search backend provides them.
+type SearchHit struct {
+	Product    Product
+	Score      float64
+	Highlights map[string][]string
+}
+
+// Match scores for the plain substring search: a hit in the name ranks
+// above a hit only in the description
+const (
+	nameMatchScore        = 2
+	descriptionMatchScore = 1
+)
+
+// MatchScore scores a product against a substring query, case
+// insensitively, returning 0 when neither name nor description contains it
+func MatchScore(product Product, query string) float64 {
+	query = strings.ToLower(query)
+	var score float64
+	if strings.Contains(strings.ToLower(product.Name), query) {
+		score += nameMatchScore
+	}
+	if strings.Contains(strings.ToLower(product.Description), query) {
+		score += descriptionMatchScore
+	}
+	return score
+}
+
+// RankSearchHits orders hits by score, then by name, and keeps at most limit
+func RankSearchHits(hits []SearchHit, limit int) []SearchHit {
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Product.Name < hits[j].Product.Name
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+)
+
+// ErrInvalidRange is returned for report periods that are empty, reversed or
+// too long
+var ErrInvalidRange = NewError(KindValidation, "invalid time range")
+
+// SearchTermStats counts how often a term was searched in a period and how
+// many of those searches found nothing
+type SearchTermStats struct {
+	Term        string `json:"term"`
+	Searches    int64  `json:"searches"`
+	ZeroResults int64  `json:"zero_results"`
+}
+
+// NormalizeSearchTerm folds case and whitespace so "Laptop  pro" and
+// "laptop pro" are counted as the same term
+func NormalizeSearchTerm(term string) string {
+	return strings.ToLower(strings.Join(strings.Fields(term), " "))
+}
+
+// MergeSearchTerms adds up the counters of each term across periods
+func MergeSearchTerms(periods ...[]SearchTermStats) map[string]SearchTermStats {
+	totals := make(map[string]SearchTermStats)
+	for _, period := range periods {
+		for _, stats := range period {
+			total := totals[stats.Term]
+			total.Term = stats.Term
+			total.Searches += stats.Searches
+			total.ZeroResults += stats.ZeroResults
+			totals[stats.Term] = total
+		}
+	}
+	return totals
+}
+
+// RankSearchTerms orders terms from most to least searched, or by zero-result
+// searches when zeroResultsOnly is set (dropping terms that always matched),
+// breaking ties by term and keeping the top n
+func RankSearchTerms(totals map[string]SearchTermStats, zeroResultsOnly bool, n int) []SearchTermStats {
+	ranked := make([]SearchTermStats, 0, len(totals))
+	for _, stats := range totals {
+		if zeroResultsOnly && stats.ZeroResults == 0 {
+			continue
+		}
+		ranked = append(ranked, stats)
+	}
+
+	count := func(stats SearchTermStats) int64 {
+		if zeroResultsOnly {
+			return stats.ZeroResults
+		}
+		return stats.Searches
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if count(ranked[i]) != count(ranked[j]) {
+			return count(ranked[i]) > count(ranked[j])
+		}
+		return ranked[i].Term < ranked[j].Term
+	})
+	if len(ranked) > n {
+		ranked = ranked[:n]
+	}
+	return ranked
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeSearchTerm(t *testing.T) {
+	assert.Equal(t, "laptop pro", NormalizeSearchTerm("  Laptop   PRO "))
+	assert.Equal(t, "", NormalizeSearchTerm("   "))
+}
+
+func TestRankSearchTerms(t *testing.T) {
+	totals := MergeSearchTerms(
+		[]SearchTermStats{{Term: "laptop", Searches: 4}, {Term: "tablet", Searches: 2, ZeroResults: 2}},
+		[]SearchTermStats{{Term: "laptop", Searches: 1}, {Term: "phone", Searches: 5, ZeroResults: 1}},
+	)
+
+	assert.Equal(t, []SearchTermStats{
+		{Term: "laptop", Searches: 5},
+		{Term: "phone", Searches: 5, ZeroResults: 1},
+		{Term: "tablet", Searches: 2, ZeroResults: 2},
+	}, RankSearchTerms(totals, false, 10))
+
+	assert.Equal(t, []SearchTermStats{
+		{Term: "tablet", Searches: 2, ZeroResults: 2},
+	}, RankSearchTerms(totals, true, 1))
+}
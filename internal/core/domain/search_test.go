@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchScore(t *testing.T) {
+	product := Product{Name: "Gaming Laptop", Description: "A fast laptop for games"}
+
+	assert.Equal(t, 3.0, MatchScore(product, "LAPTOP"))
+	assert.Equal(t, 2.0, MatchScore(product, "gaming"))
+	assert.Equal(t, 1.0, MatchScore(product, "fast"))
+	assert.Equal(t, 0.0, MatchScore(product, "phone"))
+}
+
+func TestRankSearchHits(t *testing.T) {
+	hits := []SearchHit{
+		{Product: Product{ID: "1", Name: "Sleeve"}, Score: 1},
+		{Product: Product{ID: "2", Name: "Laptop Pro"}, Score: 3},
+		{Product: Product{ID: "3", Name: "Laptop Air"}, Score: 3},
+	}
+
+	ranked := RankSearchHits(hits, 2)
+	if assert.Len(t, ranked, 2) {
+		assert.Equal(t, "3", ranked[0].Product.ID)
+		assert.Equal(t, "2", ranked[1].Product.ID)
+	}
+}
@@ -0,0 +1,15 @@
+package domain
+
+var (
+	// ErrInsufficientStock is returned when a decrement would take stock
+	// below zero
+	ErrInsufficientStock = NewError(KindConflict, "insufficient stock")
+	// ErrInvalidStockAdjustment is returned for adjustments of zero
+	ErrInvalidStockAdjustment = NewError(KindValidation, "stock adjustment must be non-zero")
+)
+
+// StockLevel is the quantity on hand of a product
+type StockLevel struct {
+	ProductID string `json:"product_id"`
+	Stock     int64  `json:"stock"`
+}
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Tag match modes for listings filtered by several tags
+const (
+	TagMatchAny = "any"
+	TagMatchAll = "all"
+)
+
+const (
+	// MaxProductTags bounds the tags of one product
+	MaxProductTags = 20
+	// MaxTagLength bounds the length of one tag, in characters
+	MaxTagLength = 50
+)
+
+// TagCount is a tag and the number of products carrying it
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// NormalizeTags trims and lowercases tags and drops blanks and duplicates,
+// keeping the first occurrence of each. Tags may not contain commas, which
+// separate them in query strings.
+func NormalizeTags(tags []string) ([]string, error) {
+	var normalized []string
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if strings.Contains(tag, ",") {
+			return nil, fmt.Errorf("tag %q cannot contain a comma", tag)
+		}
+		if len([]rune(tag)) > MaxTagLength {
+			return nil, fmt.Errorf("tags cannot be longer than %d characters", MaxTagLength)
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > MaxProductTags {
+		return nil, fmt.Errorf("a product cannot have more than %d tags", MaxProductTags)
+	}
+	return normalized, nil
+}
+
+// SetTags replaces the product's tags. Nil or empty removes them all.
+func (p *Product) SetTags(tags []string) error {
+	normalized, err := NormalizeTags(tags)
+	if err != nil {
+		return err
+	}
+	p.Tags = normalized
+	return nil
+}
+
+// SortTagCounts turns counts into a list, most used first and alphabetically
+// among equals
+func SortTagCounts(counts map[string]int) []TagCount {
+	tags := make([]TagCount, 0, len(counts))
+	for tag, count := range counts {
+		tags = append(tags, TagCount{Tag: tag, Count: count})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		return tags[i].Tag < tags[j].Tag
+	})
+	return tags
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tags, err := NormalizeTags([]string{" Gaming ", "laptop", "", "GAMING", "sale"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gaming", "laptop", "sale"}, tags)
+
+	tags, err = NormalizeTags(nil)
+	require.NoError(t, err)
+	assert.Nil(t, tags)
+
+	_, err = NormalizeTags([]string{"a,b"})
+	assert.Error(t, err)
+	_, err = NormalizeTags([]string{strings.Repeat("x", MaxTagLength+1)})
+	assert.Error(t, err)
+
+	tooMany := make([]string, MaxProductTags+1)
+	for i := range tooMany {
+		tooMany[i] = strings.Repeat("t", i+1)
+	}
+	_, err = NormalizeTags(tooMany)
+	assert.Error(t, err)
+}
+
+func TestSortTagCounts(t *testing.T) {
+	tags := SortTagCounts(map[string]int{"sale": 2, "gaming": 5, "audio": 2})
+	assert.Equal(t, []TagCount{
+		{Tag: "gaming", Count: 5},
+		{Tag: "audio", Count: 2},
+		{Tag: "sale", Count: 2},
+	}, tags)
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "regexp"
+
+// ErrInvalidTenant is returned for tenant IDs that cannot be used as part of
+// a storage key
+var ErrInvalidTenant = NewError(KindValidation, "tenant ID must be 1-64 letters, digits, '-' or '_'")
+
+// validTenantID keeps '#', the separator of composite keys, out of tenant IDs
+var validTenantID = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// ValidateTenantID checks a tenant ID taken from a request
+func ValidateTenantID(tenant string) error {
+	if !validTenantID.MatchString(tenant) {
+		return ErrInvalidTenant
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrGone is matched by every *TombstoneError
+	ErrGone               = errors.New("product no longer exists")
+	ErrInvalidReplacement = NewError(KindValidation, "replacement product must exist and differ from the deleted one")
+	ErrNotInColdStorage   = NewError(KindNotFound, "product is not in cold storage")
+)
+
+// Tombstone remembers a deleted product ID and, when it was merged into or
+// replaced by another product, its successor
+type Tombstone struct {
+	ID         string    `json:"id" dynamodbav:"id"`
+	ReplacedBy string    `json:"replaced_by,omitempty" dynamodbav:"replaced_by,omitempty"`
+	DeletedAt  time.Time `json:"deleted_at" dynamodbav:"deleted_at"`
+	// TenantID is the tenant the deleted product belonged to
+	TenantID string `json:"tenant_id,omitempty" dynamodbav:"tenant_id,omitempty"`
+	// ArchiveKey is the cold storage object holding the product, when it
+	// was moved there rather than deleted
+	ArchiveKey string `json:"archive_key,omitempty" dynamodbav:"archive_key,omitempty"`
+}
+
+// TombstoneError is returned when reading a deleted product. ReplacedBy is
+// the live successor at the end of the redirect chain, empty when there is
+// none.
+type TombstoneError struct {
+	ID         string
+	ReplacedBy string
+}
+
+func (e *TombstoneError) Error() string {
+	if e.ReplacedBy != "" {
+		return fmt.Sprintf("product %s was replaced by %s", e.ID, e.ReplacedBy)
+	}
+	return fmt.Sprintf("product %s was deleted", e.ID)
+}
+
+func (e *TombstoneError) Unwrap() error {
+	return ErrGone
+}
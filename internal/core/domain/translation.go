@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+var ErrInvalidLocale = NewError(KindValidation, "locale must be a BCP 47 language tag such as es or pt-BR")
+
+// MaxProductTranslations bounds the locales one product is translated into
+const MaxProductTranslations = 30
+
+// Translation is a product's name and description in another locale than
+// the one they were written in
+type Translation struct {
+	Name        string `json:"name" dynamodbav:"name"`
+	Description string `json:"description,omitempty" dynamodbav:"description,omitempty"`
+}
+
+// NormalizeLocale returns the canonical form of a BCP 47 language tag,
+// e.g. "pt-BR" for "pt_br"
+func NormalizeLocale(locale string) (string, error) {
+	tag, err := language.Parse(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+	if err != nil || tag == language.Und {
+		return "", ErrInvalidLocale
+	}
+	return tag.String(), nil
+}
+
+// SetTranslation adds or replaces the product's name and description in
+// locale. A translation without a description shows the product's own.
+func (p *Product) SetTranslation(locale string, translation Translation) error {
+	locale, err := NormalizeLocale(locale)
+	if err != nil {
+		return err
+	}
+	translation.Name = strings.TrimSpace(translation.Name)
+	if translation.Name == "" {
+		return errors.New("name is required")
+	}
+	if _, ok := p.Translations[locale]; !ok && len(p.Translations) >= MaxProductTranslations {
+		return fmt.Errorf("a product cannot have more than %d translations", MaxProductTranslations)
+	}
+	if p.Translations == nil {
+		p.Translations = map[string]Translation{}
+	}
+	p.Translations[locale] = translation
+	return nil
+}
+
+// Locales lists the locales the product is translated into, sorted
+func (p Product) Locales() []string {
+	locales := make([]string, 0, len(p.Translations))
+	for locale := range p.Translations {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Translated returns the product with its name and description in locale,
+// or unchanged when it has no translation into it
+func (p Product) Translated(locale string) Product {
+	translation, ok := p.Translations[locale]
+	if !ok {
+		return p
+	}
+	p.Name = translation.Name
+	if translation.Description != "" {
+		p.Description = translation.Description
+	}
+	return p
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeLocale(t *testing.T) {
+	for input, want := range map[string]string{"es": "es", "pt_br": "pt-BR", " EN-gb ": "en-GB", "zh-hant": "zh-Hant"} {
+		locale, err := NormalizeLocale(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, locale)
+	}
+	for _, bad := range []string{"", "und", "not a locale", "x"} {
+		_, err := NormalizeLocale(bad)
+		assert.ErrorIs(t, err, ErrInvalidLocale, bad)
+	}
+}
+
+func TestProduct_SetTranslation(t *testing.T) {
+	product := Product{Name: "Laptop", Description: "Fast"}
+	require.NoError(t, product.SetTranslation("es", Translation{Name: " Portátil "}))
+	require.NoError(t, product.SetTranslation("fr", Translation{Name: "Ordinateur", Description: "Rapide"}))
+	assert.Equal(t, []string{"es", "fr"}, product.Locales())
+
+	assert.Error(t, product.SetTranslation("de", Translation{}))
+
+	spanish := product.Translated("es")
+	assert.Equal(t, "Portátil", spanish.Name)
+	assert.Equal(t, "Fast", spanish.Description, "a missing description falls back to the product's own")
+	assert.Equal(t, "Rapide", product.Translated("fr").Description)
+	assert.Equal(t, "Laptop", product.Translated("de").Name)
+
+	for i := len(product.Translations); i < MaxProductTranslations; i++ {
+		require.NoError(t, product.SetTranslation(fmt.Sprintf("es-x-t%d", i), Translation{Name: "x"}))
+	}
+	assert.Error(t, product.SetTranslation("it", Translation{Name: "Portatile"}))
+	assert.NoError(t, product.SetTranslation("es", Translation{Name: "Portátil"}), "replacing a translation is allowed at the limit")
+}
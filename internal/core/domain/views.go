@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// ProductViews is the number of times a product was viewed in a period
+type ProductViews struct {
+	ProductID string `json:"product_id"`
+	Views     int64  `json:"views"`
+}
+
+// TrendingSnapshot is the ranking produced by a rollup over a sliding window
+// of daily view counters
+type TrendingSnapshot struct {
+	GeneratedAt time.Time      `json:"generated_at"`
+	WindowDays  int            `json:"window_days"`
+	Items       []ProductViews `json:"items"`
+}
+
+// RankByViews orders view totals from most to least viewed, breaking ties by
+// product ID so the ranking is deterministic, and keeps the top n
+func RankByViews(totals map[string]int64, n int) []ProductViews {
+	ranked := make([]ProductViews, 0, len(totals))
+	for id, views := range totals {
+		ranked = append(ranked, ProductViews{ProductID: id, Views: views})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Views != ranked[j].Views {
+			return ranked[i].Views > ranked[j].Views
+		}
+		return ranked[i].ProductID < ranked[j].ProductID
+	})
+	if len(ranked) > n {
+		ranked = ranked[:n]
+	}
+	return ranked
+}
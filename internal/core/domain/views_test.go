@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRankByViews(t *testing.T) {
+	totals := map[string]int64{"a": 5, "b": 12, "c": 5, "d": 1}
+
+	assert.Equal(t, []ProductViews{
+		{ProductID: "b", Views: 12},
+		{ProductID: "a", Views: 5},
+		{ProductID: "c", Views: 5},
+	}, RankByViews(totals, 3))
+
+	assert.Len(t, RankByViews(totals, 10), 4)
+	assert.Empty(t, RankByViews(map[string]int64{}, 10))
+}
@@ -0,0 +1,29 @@
+package ports
+
+import (
+	"context"
+)
+
+// StatementQuery is a parameterized, read-only PartiQL statement
+type StatementQuery struct {
+	Statement  string
+	Parameters []interface{}
+	Limit      int
+	NextToken  string
+}
+
+// StatementResult contains one page of rows returned by a statement
+type StatementResult struct {
+	Items     []map[string]interface{}
+	NextToken string
+}
+
+// StatementRepository executes ad-hoc statements against the products table
+type StatementRepository interface {
+	ExecuteStatement(ctx context.Context, query StatementQuery) (*StatementResult, error)
+}
+
+// AdminQueryService runs support queries on behalf of administrators
+type AdminQueryService interface {
+	Query(ctx context.Context, query StatementQuery, fields []string) (*StatementResult, error)
+}
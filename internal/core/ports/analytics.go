@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// AnalyticsPublisher ships usage events to the data pipeline. Track must not
+// block the caller or fail the request; delivery is best effort.
+type AnalyticsPublisher interface {
+	Track(ctx context.Context, event domain.AnalyticsEvent)
+}
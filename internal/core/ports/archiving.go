@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// ArchivingRepository finds products scheduled for archival and moves them
+// through the warning and archived steps. Both steps are conditional on the
+// version read and return domain.ErrConflict when the product changed in
+// the meantime.
+type ArchivingRepository interface {
+	ListScheduledForArchival(ctx context.Context, until time.Time) ([]domain.Product, error)
+	MarkArchiveWarned(ctx context.Context, product domain.Product, now time.Time) error
+	MarkArchived(ctx context.Context, product domain.Product, now time.Time) error
+}
+
+// ArchivingService archives seasonal products once their auto_archive_at
+// has passed, warning ahead of time
+type ArchivingService interface {
+	ArchiveDue(ctx context.Context) error
+}
@@ -0,0 +1,35 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// AuditLogger keeps the change history of every product, including deleted
+// ones
+type AuditLogger interface {
+	Record(ctx context.Context, entry domain.AuditEntry) error
+	// History returns up to limit entries of the product in the tenant of
+	// ctx, newest first
+	History(ctx context.Context, productID string, limit int) ([]domain.AuditEntry, error)
+}
+
+// AuditService reads the change history of products
+type AuditService interface {
+	History(ctx context.Context, productID string, limit int) ([]domain.AuditEntry, error)
+}
+
+type actorKey struct{}
+
+// WithActor records actor as the caller responsible for the writes made
+// with ctx
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// Actor returns the caller set with WithActor, empty when there is none
+func Actor(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
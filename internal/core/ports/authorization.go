@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// Authorizer decides whether a principal may perform an action, returning
+// domain.ErrForbidden when it may not. Implementations differ in where the
+// role policy comes from.
+type Authorizer interface {
+	Authorize(ctx context.Context, principal domain.Principal, action string) error
+}
@@ -0,0 +1,31 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// ProductBatchDeleter removes many products at once, grouping deletes that
+// Delete would write one by one. Every delete is conditional on the version
+// of the product given, and the outbox events attached to ctx with
+// WithOutboxEvents are written along with it. Products that changed or
+// disappeared in the meantime are kept and their IDs returned.
+type ProductBatchDeleter interface {
+	DeleteBatch(ctx context.Context, products []domain.Product) (skipped []string, err error)
+}
+
+// BulkDeleteSelection picks the products of a bulk delete, either by ID or
+// by the listing filters; exactly one of them is set
+type BulkDeleteSelection struct {
+	IDs     []string
+	Filters *ProductFilters
+}
+
+type BulkDeleteService interface {
+	// BulkDelete previews the products selected when dryRun is set, and
+	// deletes them otherwise. A delete must carry the confirmation token of
+	// a dry run and fails with domain.ErrBulkDeleteChanged when the
+	// selection no longer matches it.
+	BulkDelete(ctx context.Context, selection BulkDeleteSelection, dryRun bool, confirmation string) (domain.BulkDeleteSummary, error)
+}
@@ -0,0 +1,44 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// CategoryRepository stores categories, returning domain.ErrCategoryNotFound
+// for unknown IDs
+type CategoryRepository interface {
+	Save(ctx context.Context, category domain.Category) error
+	GetByID(ctx context.Context, id string) (domain.Category, error)
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context) ([]domain.Category, error)
+}
+
+// CategoryCounter keeps the number of products in each category, adjusted
+// in the same unit of work as the product writes that change it
+type CategoryCounter interface {
+	// AdjustProductCount adds delta to the category's product count,
+	// returning domain.ErrCategoryNotFound for unknown categories
+	AdjustProductCount(ctx context.Context, categoryID string, delta int) error
+}
+
+// CategoryUsage tells whether any product still belongs to a category
+type CategoryUsage interface {
+	HasProductsInCategory(ctx context.Context, categoryID string) (bool, error)
+}
+
+type CategoryInput struct {
+	Name        string
+	Description string
+}
+
+type CategoryService interface {
+	Create(ctx context.Context, input CategoryInput) (domain.Category, error)
+	Get(ctx context.Context, id string) (domain.Category, error)
+	Update(ctx context.Context, id string, input CategoryInput) (domain.Category, error)
+	// Delete refuses with domain.ErrCategoryInUse while products belong to
+	// the category
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context) ([]domain.Category, error)
+}
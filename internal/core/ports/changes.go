@@ -0,0 +1,44 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// ChangeCheckpoint is where a consumer of the change feed left off: the
+// position of the last product update and of the last deletion it was
+// sent, each ordered by time and then by ID
+type ChangeCheckpoint struct {
+	Updated SortKey `json:"u"`
+	Deleted SortKey `json:"d"`
+}
+
+// CheckpointAt starts the change feed at t, including the changes made at
+// t itself
+func CheckpointAt(t time.Time) ChangeCheckpoint {
+	position := SortKey{Value: formatSortTime(t)}
+	return ChangeCheckpoint{Updated: position, Deleted: position}
+}
+
+// TombstoneLister reads the tombstones of the tenant of ctx in order of
+// deletion time and then ID: those strictly after the position and deleted
+// no later than until, at most limit of them
+type TombstoneLister interface {
+	ListDeleted(ctx context.Context, after SortKey, until time.Time, limit int) ([]domain.Tombstone, error)
+}
+
+type ChangeService interface {
+	// Changes returns up to limit product updates and up to limit deletions
+	// after the checkpoint, along with the checkpoint to read from next.
+	// Changes of the last few seconds are held back until the indexes they
+	// are read from have caught up with them.
+	Changes(ctx context.Context, from ChangeCheckpoint, limit int) (domain.ProductChanges, ChangeCheckpoint, error)
+}
+
+// TombstoneSortKey returns the position of tombstone in the deletions of
+// the change feed
+func TombstoneSortKey(tombstone domain.Tombstone) SortKey {
+	return SortKey{Value: formatSortTime(tombstone.DeletedAt), ID: tombstone.ID}
+}
@@ -0,0 +1,29 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// ColdStorage keeps products moved out of the table. Store writes a batch
+// as one object and returns its key; Load reads one product back from it,
+// or returns domain.ErrNotFound.
+type ColdStorage interface {
+	Store(ctx context.Context, products []domain.Product) (string, error)
+	Load(ctx context.Context, key, id string) (domain.Product, error)
+}
+
+// ColdStorageRepository finds archived products of every tenant last
+// updated before a time, at most limit of them
+type ColdStorageRepository interface {
+	ListArchivedBefore(ctx context.Context, before time.Time, limit int) ([]domain.Product, error)
+}
+
+// ColdStorageService moves long archived products to cold storage and
+// brings them back on request
+type ColdStorageService interface {
+	MoveArchived(ctx context.Context) error
+	Restore(ctx context.Context, id string) (domain.Product, error)
+}
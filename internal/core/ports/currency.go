@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// ExchangeRates quotes how many units of currency to one unit of from buys
+type ExchangeRates interface {
+	Rate(ctx context.Context, from, to string) (float64, error)
+}
+
+// CurrencyService converts prices into the currency a client displays them in
+type CurrencyService interface {
+	Convert(ctx context.Context, price domain.Money, to string) (domain.Money, error)
+}
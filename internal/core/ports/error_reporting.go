@@ -0,0 +1,27 @@
+package ports
+
+import (
+	"context"
+)
+
+// PanicReport is a panic recovered while handling a request
+type PanicReport struct {
+	// Value is what the handler panicked with
+	Value any
+	Stack []byte
+	// RequestID correlates the report with the request's logs and the
+	// problem returned to the client
+	RequestID string
+	Method    string
+	// Route is the matched route pattern, such as /api/v1/products/:id
+	Route  string
+	Path   string
+	Tenant string
+}
+
+// ErrorReporter ships recovered panics to an error tracker. ReportPanic is
+// called on the request's goroutine, so it must not block it; delivery is
+// best effort.
+type ErrorReporter interface {
+	ReportPanic(ctx context.Context, report PanicReport)
+}
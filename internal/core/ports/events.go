@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// EventPublisher announces product changes to other services. Unlike
+// analytics, delivery failures are reported to the caller.
+type EventPublisher interface {
+	Publish(ctx context.Context, event domain.ProductEvent) error
+}
@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// ProductStreamer reads every product matching the filters a page at a
+// time, so callers never hold the whole set in memory. Sorting and
+// pagination fields of the filters are ignored, Fields is honored, and pages
+// come in no particular order; StreamProducts stops at the first error fn
+// returns.
+type ProductStreamer interface {
+	StreamProducts(ctx context.Context, filters ProductFilters, fn func(page []domain.Product) error) error
+}
+
+type ExportService interface {
+	// Export calls fn with each product matching filters, in no particular
+	// order
+	Export(ctx context.Context, filters ProductFilters, fn func(domain.Product) error) error
+}
@@ -0,0 +1,42 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// FavoriteRepository keeps each user's favorite products together, in one
+// item collection per user
+type FavoriteRepository interface {
+	// Add returns false when the product already was a favorite
+	Add(ctx context.Context, favorite domain.Favorite) (bool, error)
+	// Remove returns false when the product was not a favorite
+	Remove(ctx context.Context, userID, productID string) (bool, error)
+	// List returns up to limit of the user's favorites in the tenant of
+	// ctx, by product ID, starting after the product ID after when it is
+	// set
+	List(ctx context.Context, userID, after string, limit int) ([]domain.Favorite, error)
+}
+
+// FavoriteProduct is a favorite product and when it was favorited
+type FavoriteProduct struct {
+	Product     domain.Product
+	FavoritedAt time.Time
+}
+
+// FavoritePage is a page of a user's favorites. NextAfter is the product ID
+// to continue from, empty on the last page.
+type FavoritePage struct {
+	Favorites []FavoriteProduct
+	NextAfter string
+}
+
+// FavoriteService manages the wish list of the authenticated user of ctx,
+// returning domain.ErrNoUser when there is none
+type FavoriteService interface {
+	Add(ctx context.Context, productID string) (domain.Favorite, error)
+	Remove(ctx context.Context, productID string) error
+	List(ctx context.Context, after string, limit int) (FavoritePage, error)
+}
@@ -0,0 +1,7 @@
+package ports
+
+// IDGenerator names new products. IDs must be unique across tenants and at
+// most domain.MaxProductIDLength letters, digits, '-', '_' and '.'.
+type IDGenerator interface {
+	NewID() (string, error)
+}
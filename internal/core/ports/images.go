@@ -0,0 +1,31 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// PresignedUpload lets a client upload one object straight to blob storage
+// by sending Method to URL with Headers, until ExpiresAt
+type PresignedUpload struct {
+	URL       string            `json:"url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// BlobStorage holds uploaded files such as product images
+type BlobStorage interface {
+	// PresignUpload authorizes an upload of contentType to key
+	PresignUpload(ctx context.Context, key, contentType string) (PresignedUpload, error)
+	// URL is where the object at key is served from once uploaded
+	URL(key string) string
+}
+
+type ImageService interface {
+	// AddImage records a new image on the product and returns the upload
+	// the client must complete for it
+	AddImage(ctx context.Context, productID, contentType string) (domain.ProductImage, PresignedUpload, error)
+}
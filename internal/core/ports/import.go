@@ -0,0 +1,34 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// ProductBatchWriter creates many products at once, without the per-item
+// transaction Save uses. The outbox events attached to ctx with
+// WithOutboxEvent are written in the same batches as the products, so a
+// failure part way through may leave some products written without their
+// events or the other way round. Products holding an SKU or barcode are
+// saved on their own so their reservations are transactional; those whose
+// values are already taken are left out and returned by product ID. A
+// failure part way through is a *domain.BatchWriteError naming the
+// products already saved.
+type ProductBatchWriter interface {
+	SaveBatch(ctx context.Context, products []domain.Product) (map[string]*domain.DuplicateError, error)
+}
+
+// ImportRow is one row of a bulk import file. Err is set when the row could
+// not be parsed and Input is meaningless.
+type ImportRow struct {
+	Row   int
+	Input ProductInput
+	Err   error
+}
+
+type ImportService interface {
+	// Import validates every row and creates a product for each valid one;
+	// with dryRun it only validates
+	Import(ctx context.Context, rows []ImportRow, dryRun bool) (domain.ImportSummary, error)
+}
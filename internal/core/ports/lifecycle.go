@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// LifecycleService moves products between statuses on request, returning
+// domain.ErrInvalidTransition for moves the lifecycle does not allow
+type LifecycleService interface {
+	Transition(ctx context.Context, id, status string) (domain.Product, error)
+}
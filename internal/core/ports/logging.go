@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+	"log/slog"
+)
+
+type loggerKey struct{}
+
+// WithLogger stores the logger scoped to a request in ctx, carrying the
+// attributes that identify the request, so the services and repositories
+// handling it log with them
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// Logger returns the logger stored by WithLogger, or fallback when ctx has
+// none, as in background jobs
+func Logger(ctx context.Context, fallback *slog.Logger) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return fallback
+}
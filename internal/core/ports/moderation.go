@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// ContentModerator screens product text before it is stored
+type ContentModerator interface {
+	Screen(ctx context.Context, name, description string) (domain.ModerationVerdict, error)
+}
+
+// ModerationRepository lists the products waiting for manual review
+type ModerationRepository interface {
+	ListPendingReview(ctx context.Context) ([]domain.Product, error)
+}
+
+// ModerationService exposes the manual review queue
+type ModerationService interface {
+	PendingReview(ctx context.Context) ([]domain.Product, error)
+	Approve(ctx context.Context, id string) (domain.Product, error)
+	Reject(ctx context.Context, id, reason string) (domain.Product, error)
+}
@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// Notifier delivers rendered notifications to their recipients
+type Notifier interface {
+	Send(ctx context.Context, notification domain.Notification) error
+}
@@ -0,0 +1,45 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// OutboxRepository holds the product events committed together with their
+// writes until the relay has published them
+type OutboxRepository interface {
+	// ListPending returns up to limit unpublished events, oldest first
+	ListPending(ctx context.Context, limit int) ([]domain.ProductEvent, error)
+	MarkSent(ctx context.Context, id string, sentAt time.Time) error
+	// Append stores an event that comes with no product write, such as the
+	// rejection of a submission moderation refused
+	Append(ctx context.Context, event domain.ProductEvent) error
+}
+
+// OutboxService delivers outbox events to the EventPublisher
+type OutboxService interface {
+	RelayPending(ctx context.Context) error
+}
+
+type outboxEventsKey struct{}
+
+// WithOutboxEvent returns a context asking the repository to store event in
+// the outbox atomically with the next product write made with it
+func WithOutboxEvent(ctx context.Context, event domain.ProductEvent) context.Context {
+	return WithOutboxEvents(ctx, []domain.ProductEvent{event})
+}
+
+// WithOutboxEvents attaches several events at once, such as those of a
+// batch write
+func WithOutboxEvents(ctx context.Context, events []domain.ProductEvent) context.Context {
+	return context.WithValue(ctx, outboxEventsKey{}, append(OutboxEvents(ctx), events...))
+}
+
+// OutboxEvents returns the events attached to ctx with WithOutboxEvent
+func OutboxEvents(ctx context.Context) []domain.ProductEvent {
+	events, _ := ctx.Value(outboxEventsKey{}).([]domain.ProductEvent)
+	// Copy so contexts derived from the same parent do not share a backing array
+	return append([]domain.ProductEvent(nil), events...)
+}
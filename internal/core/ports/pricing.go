@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// PriceSuggester asks a pricing service what a product should cost. It
+// returns domain.ErrNoPriceSuggestion when the service has none, and an
+// error of kind domain.KindUnavailable when it cannot be reached.
+type PriceSuggester interface {
+	SuggestPrice(ctx context.Context, product domain.Product) (domain.PriceSuggestion, error)
+}
+
+type PriceSuggestionService interface {
+	// Suggest reads the product and asks for a price suggestion for it
+	Suggest(ctx context.Context, id string) (domain.PriceSuggestion, error)
+}
@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+)
+
+type backgroundPriorityKey struct{}
+
+// WithBackgroundPriority marks ctx as bulk work that should yield storage
+// capacity to interactive requests
+func WithBackgroundPriority(ctx context.Context) context.Context {
+	return context.WithValue(ctx, backgroundPriorityKey{}, true)
+}
+
+// BackgroundPriority reports whether ctx carries bulk, low-priority work
+func BackgroundPriority(ctx context.Context) bool {
+	background, _ := ctx.Value(backgroundPriorityKey{}).(bool)
+	return background
+}
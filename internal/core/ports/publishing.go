@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// PublishingRepository finds scheduled drafts and publishes them
+type PublishingRepository interface {
+	ListDueForPublishing(ctx context.Context, now time.Time) ([]domain.Product, error)
+	// MarkPublished publishes the draft only if it is still due at now and
+	// at the version read, returning domain.ErrConflict when it was
+	// rescheduled, edited or already published in the meantime
+	MarkPublished(ctx context.Context, product domain.Product, now time.Time) error
+}
+
+// PublishingService moves drafts live once their publish_at has passed
+type PublishingService interface {
+	PublishDue(ctx context.Context) error
+}
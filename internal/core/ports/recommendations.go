@@ -0,0 +1,25 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// Recommender learns from what a session interacts with and suggests related
+// products. Implementations range from the co-occurrence counters shipped
+// here to managed services such as Amazon Personalize.
+type Recommender interface {
+	RecordInteraction(ctx context.Context, sessionID, productID string) error
+	Recommend(ctx context.Context, productID string, limit int) ([]domain.Recommendation, error)
+}
+
+// RecommendedProduct is a product together with its recommendation score
+type RecommendedProduct struct {
+	Product domain.Product
+	Score   float64
+}
+
+type RecommendationService interface {
+	Recommendations(ctx context.Context, productID string, limit int) ([]RecommendedProduct, error)
+}
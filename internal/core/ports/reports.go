@@ -0,0 +1,25 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// CostedProductRepository lists every product that has a cost price
+type CostedProductRepository interface {
+	ListCosted(ctx context.Context) ([]domain.Product, error)
+}
+
+// ReportRepository stores the latest generated reports. Latest reads return
+// domain.ErrReportNotReady until the first report is saved.
+type ReportRepository interface {
+	SaveMarginReport(ctx context.Context, report domain.MarginReport) error
+	LatestMarginReport(ctx context.Context) (domain.MarginReport, error)
+}
+
+// ReportService generates reports in the background and serves the latest
+type ReportService interface {
+	GenerateMarginReport(ctx context.Context) error
+	MarginReport(ctx context.Context) (domain.MarginReport, error)
+}
@@ -2,31 +2,130 @@ package ports
 
 import (
 	"context"
+	"slices"
+	"time"
+
 	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
 )
 
 type ProductRepository interface {
+	// Save and Update return a *domain.DuplicateError when the product's
+	// SKU or barcode is held by another product of its tenant
 	Save(ctx context.Context, product domain.Product) error
 	GetByID(ctx context.Context, id string) (domain.Product, error)
+	// GetByIDs returns the products among ids, in no particular order.
+	// IDs that GetByID would report as missing are left out.
+	GetByIDs(ctx context.Context, ids []string) ([]domain.Product, error)
+	// GetBySKU finds the product of the context's tenant holding a
+	// normalized SKU
+	GetBySKU(ctx context.Context, sku string) (domain.Product, error)
+	// Update stores product as version product.Version+1, returning
+	// domain.ErrConflict when the stored version is no longer product.Version
 	Update(ctx context.Context, product domain.Product) error
-	Delete(ctx context.Context, id string) error
+	// Delete removes the product if it is still at version, returning
+	// domain.ErrConflict when it changed since and domain.ErrNotFound when
+	// it is gone
+	Delete(ctx context.Context, id string, version int64) error
 	List(ctx context.Context) ([]domain.Product, error)
 	ListWithFilters(ctx context.Context, filters ProductFilters) (*ProductListResult, error)
+	// Count returns how many products match the filters, ignoring their
+	// pagination and sorting, without reading the products themselves
+	Count(ctx context.Context, filters ProductFilters) (int, error)
 }
 
 // ProductFilters represents filtering options for product queries
 type ProductFilters struct {
-	Name      string
-	MinPrice  float64
-	MaxPrice  float64
-	SortBy    string
-	SortOrder string
-	Offset    int
-	Limit     int
+	Name string
+	// MinPrice and MaxPrice bound the price in major units of
+	// PriceCurrency(); zero leaves that side open
+	MinPrice domain.Decimal
+	MaxPrice domain.Decimal
+	// Currency restricts the listing to prices in one ISO 4217 currency
+	Currency string
+	// CategoryID restricts the listing to one category
+	CategoryID string
+	// Status lists the products in one status instead of the published ones
+	Status string
+	// IncludeHidden lists the tenant's products whatever their status,
+	// moderation and expiration, ignoring Status
+	IncludeHidden bool
+	SortBy        string
+	SortOrder     string
+	Page          int
+	Offset        int
+	Limit         int
+	// ThenBy orders products tied on SortBy, field by field, in the same
+	// SortOrder. Listings sorted by several fields page by offset only.
+	ThenBy []string
+	// StartKey resumes a previous listing from its ProductListResult.NextKey
+	StartKey []byte
+	// After continues a keyset listing strictly after this position in the
+	// sort order, instead of skipping Offset items
+	After *SortKey
+	// Fields limits the attributes read from storage, which DynamoDB reads
+	// as a ProjectionExpression; empty reads them all. The ID and the sort
+	// field are always included. Listings set it from the fields query
+	// parameter, and internal readers such as the change feed and the tag
+	// counts to the attributes they use.
+	Fields []string
+	// Explain asks the repository to report the access path it used
+	Explain bool
+	// Tags restricts the listing to products carrying any of the tags, or
+	// all of them when TagMatch is domain.TagMatchAll
+	Tags     []string
+	TagMatch string
+	// CreatedAfter, CreatedBefore, UpdatedAfter and UpdatedBefore bound the
+	// creation and update times, inclusively; zero leaves that side open
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	UpdatedAfter  time.Time
+	UpdatedBefore time.Time
+}
+
+// PriceCurrency is the currency listed products must be priced in: Currency,
+// or domain.DefaultCurrency when the filters bound or sort by price without
+// one, since amounts in different currencies do not compare. Empty lists
+// every currency.
+func (f ProductFilters) PriceCurrency() string {
+	if f.Currency != "" {
+		return f.Currency
+	}
+	if !f.MinPrice.IsZero() || !f.MaxPrice.IsZero() || f.SortBy == "price" || slices.Contains(f.ThenBy, "price") {
+		return domain.DefaultCurrency
+	}
+	return ""
 }
 
 // ProductListResult contains the result of a filtered product query
 type ProductListResult struct {
 	Products   []domain.Product
 	TotalItems int
+	// NextKey is an opaque, repository-specific resume position for the
+	// next page; nil when there are no more items or cursors are unsupported
+	NextKey []byte
+	// Plan is only set when ProductFilters.Explain was requested
+	Plan *QueryPlan
+}
+
+// QueryPlan describes how a repository served a filtered query
+type QueryPlan struct {
+	Operation    string
+	Index        string
+	Partitions   int
+	SortInMemory bool
+	Reason       string
+}
+
+type consistentReadKey struct{}
+
+// WithConsistentRead returns a context asking repositories to use strongly
+// consistent reads, for callers that must observe their own recent writes
+func WithConsistentRead(ctx context.Context) context.Context {
+	return context.WithValue(ctx, consistentReadKey{}, true)
+}
+
+// ConsistentRead reports whether strongly consistent reads were requested on ctx
+func ConsistentRead(ctx context.Context) bool {
+	consistent, _ := ctx.Value(consistentReadKey{}).(bool)
+	return consistent
 }
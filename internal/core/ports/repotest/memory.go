@@ -0,0 +1,226 @@
+package repotest
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// MemoryRepository is an in-memory ports.ProductRepository held to the same
+// contract as the storage adapters, for tests that need a working
+// repository. It does not support cursors, so listings never return a
+// NextKey.
+type MemoryRepository struct {
+	mu       sync.Mutex
+	products map[string]domain.Product
+}
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{products: make(map[string]domain.Product)}
+}
+
+func (r *MemoryRepository) Save(ctx context.Context, product domain.Product) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.products[product.ID]; ok {
+		return &domain.DuplicateError{Field: domain.FieldID, Value: product.ID}
+	}
+	if err := r.checkUnique(product); err != nil {
+		return err
+	}
+	r.products[product.ID] = cloneProduct(product)
+	return nil
+}
+
+func (r *MemoryRepository) GetByID(ctx context.Context, id string) (domain.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	product, ok := r.products[id]
+	if !ok || product.TenantID != ports.TenantID(ctx) || product.IsExpired(time.Now().UTC()) {
+		return domain.Product{}, domain.ErrNotFound
+	}
+	return cloneProduct(product), nil
+}
+
+func (r *MemoryRepository) GetByIDs(ctx context.Context, ids []string) ([]domain.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now().UTC()
+	products := make([]domain.Product, 0, len(ids))
+	for _, id := range ids {
+		product, ok := r.products[id]
+		if ok && product.TenantID == ports.TenantID(ctx) && !product.IsExpired(now) {
+			products = append(products, cloneProduct(product))
+		}
+	}
+	return products, nil
+}
+
+func (r *MemoryRepository) GetBySKU(ctx context.Context, sku string) (domain.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now().UTC()
+	for _, product := range r.products {
+		if product.SKU == sku && product.TenantID == ports.TenantID(ctx) && !product.IsExpired(now) {
+			return cloneProduct(product), nil
+		}
+	}
+	return domain.Product{}, domain.ErrNotFound
+}
+
+func (r *MemoryRepository) Update(ctx context.Context, product domain.Product) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.products[product.ID]
+	if !ok || stored.TenantID != product.TenantID || stored.Version != product.Version {
+		return domain.ErrConflict
+	}
+	if err := r.checkUnique(product); err != nil {
+		return err
+	}
+	product.Version++
+	r.products[product.ID] = cloneProduct(product)
+	return nil
+}
+
+func (r *MemoryRepository) Delete(ctx context.Context, id string, version int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	product, ok := r.products[id]
+	if !ok || product.TenantID != ports.TenantID(ctx) {
+		return domain.ErrNotFound
+	}
+	if product.Version != version {
+		return domain.ErrConflict
+	}
+	delete(r.products, id)
+	return nil
+}
+
+func (r *MemoryRepository) List(ctx context.Context) ([]domain.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.matching(ctx, ports.ProductFilters{}), nil
+}
+
+func (r *MemoryRepository) ListWithFilters(ctx context.Context, filters ports.ProductFilters) (*ports.ProductListResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	products := r.matching(ctx, filters)
+	total := len(products)
+	sortBy := append([]string{filters.SortBy}, filters.ThenBy...)
+	slices.SortStableFunc(products, func(a, b domain.Product) int {
+		if filters.SortOrder == "desc" {
+			return ports.CompareProductsBy(&b, &a, sortBy)
+		}
+		return ports.CompareProductsBy(&a, &b, sortBy)
+	})
+	if filters.After != nil {
+		products = slices.DeleteFunc(products, func(product domain.Product) bool {
+			c := filters.After.Compare(product, filters.SortBy)
+			return c == 0 || (c < 0) == (filters.SortOrder != "desc")
+		})
+	}
+
+	products = products[min(filters.Offset, len(products)):]
+	products = products[:min(filters.Limit, len(products))]
+	return &ports.ProductListResult{Products: products, TotalItems: total}, nil
+}
+
+func (r *MemoryRepository) Count(ctx context.Context, filters ports.ProductFilters) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.matching(ctx, filters)), nil
+}
+
+// matching returns the listed products of the context's tenant that pass
+// filters, ignoring pagination: published, or in the filtered status,
+// approved and unexpired ones unless hidden ones are included
+func (r *MemoryRepository) matching(ctx context.Context, filters ports.ProductFilters) []domain.Product {
+	now := time.Now().UTC()
+	var products []domain.Product
+	for _, product := range r.products {
+		switch {
+		case product.TenantID != ports.TenantID(ctx),
+			!filters.IncludeHidden && (product.IsExpired(now) ||
+				!matchesStatus(product, filters.Status, now) ||
+				!product.IsModerationApproved()),
+			!strings.Contains(product.Name, filters.Name),
+			filters.CategoryID != "" && product.CategoryID != filters.CategoryID,
+			filters.PriceCurrency() != "" && product.Price.Currency != filters.PriceCurrency(),
+			!filters.MinPrice.IsZero() && product.Price.Major().Cmp(filters.MinPrice) < 0,
+			!filters.MaxPrice.IsZero() && product.Price.Major().Cmp(filters.MaxPrice) > 0,
+			!matchesTags(product.Tags, filters.Tags, filters.TagMatch),
+			!filters.CreatedAfter.IsZero() && product.CreatedAt.Before(filters.CreatedAfter),
+			!filters.CreatedBefore.IsZero() && product.CreatedAt.After(filters.CreatedBefore),
+			!filters.UpdatedAfter.IsZero() && product.UpdatedAt.Before(filters.UpdatedAfter),
+			!filters.UpdatedBefore.IsZero() && product.UpdatedAt.After(filters.UpdatedBefore):
+			continue
+		}
+		products = append(products, cloneProduct(product))
+	}
+	return products
+}
+
+// matchesStatus lists live products by their schedule unless another
+// status was asked for
+func matchesStatus(product domain.Product, status string, now time.Time) bool {
+	if status == "" || status == domain.StatusPublished {
+		return product.IsLiveAt(now)
+	}
+	return product.Status == status
+}
+
+func matchesTags(tags, wanted []string, match string) bool {
+	if len(wanted) == 0 {
+		return true
+	}
+	for _, tag := range wanted {
+		found := slices.Contains(tags, tag)
+		if found && match != domain.TagMatchAll {
+			return true
+		}
+		if !found && match == domain.TagMatchAll {
+			return false
+		}
+	}
+	return match == domain.TagMatchAll
+}
+
+// checkUnique rejects a product whose SKU or barcode another product of its
+// tenant holds
+func (r *MemoryRepository) checkUnique(product domain.Product) error {
+	for field, value := range product.UniqueValues() {
+		for _, other := range r.products {
+			if other.ID != product.ID && other.TenantID == product.TenantID && other.UniqueValues()[field] == value {
+				return &domain.DuplicateError{Field: field, Value: value}
+			}
+		}
+	}
+	return nil
+}
+
+// cloneProduct copies the slices of product so callers cannot modify the
+// stored one
+func cloneProduct(product domain.Product) domain.Product {
+	product.Tags = slices.Clone(product.Tags)
+	product.Images = slices.Clone(product.Images)
+	product.ModerationReasons = slices.Clone(product.ModerationReasons)
+	product.Translations = maps.Clone(product.Translations)
+	return product
+}
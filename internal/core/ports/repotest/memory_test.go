@@ -0,0 +1,13 @@
+package repotest
+
+import (
+	"testing"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	TestProductRepository(t, func(t *testing.T) ports.ProductRepository {
+		return NewMemoryRepository()
+	})
+}
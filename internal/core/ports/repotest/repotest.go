@@ -0,0 +1,512 @@
+// Package repotest is the conformance suite every ports.ProductRepository
+// implementation must pass, so filtering, sorting, pagination and error
+// semantics stay the same whichever adapter stores the catalog. An adapter
+// runs it from its own tests:
+//
+//	func TestContract(t *testing.T) {
+//		repotest.TestProductRepository(t, func(t *testing.T) ports.ProductRepository {
+//			return newRepository(t)
+//		})
+//	}
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// Factory returns an empty repository; the suite calls it once per subtest
+type Factory func(t *testing.T) ports.ProductRepository
+
+// TestProductRepository runs the repository contract against the
+// repositories newRepository returns
+func TestProductRepository(t *testing.T, newRepository Factory) {
+	tests := []struct {
+		name string
+		run  func(*testing.T, ports.ProductRepository)
+	}{
+		{"CRUD", testCRUD},
+		{"UpdateConflict", testUpdateConflict},
+		{"GetByIDs", testGetByIDs},
+		{"UniqueSKU", testUniqueSKU},
+		{"TenantIsolation", testTenantIsolation},
+		{"Filters", testFilters},
+		{"PriceCurrency", testPriceCurrency},
+		{"Schedule", testSchedule},
+		{"Sorting", testSorting},
+		{"SecondarySort", testSecondarySort},
+		{"TimeRange", testTimeRange},
+		{"OffsetPagination", testOffsetPagination},
+		{"CursorPagination", testCursorPagination},
+		{"KeysetPagination", testKeysetPagination},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, newRepository(t))
+		})
+	}
+}
+
+// base is the creation time of the fixtures, whole seconds so every
+// adapter stores it exactly
+var base = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func newProduct(name string, cents int64) domain.Product {
+	return domain.Product{
+		ID:        uuid.NewString(),
+		Name:      name,
+		Price:     domain.Money{Amount: cents, Currency: "USD"},
+		CreatedAt: base,
+		UpdatedAt: base,
+		Status:    domain.StatusPublished,
+		Version:   1,
+	}
+}
+
+// seedCatalog saves five listed products, whose names, prices, creation
+// and update times each sort them differently, next to products that must
+// never be listed: a draft, an expired one, one pending moderation and one
+// of another tenant
+func seedCatalog(t *testing.T, repo ports.ProductRepository) {
+	ctx := context.Background()
+	for _, p := range []struct {
+		name     string
+		cents    int64
+		created  int
+		updated  int
+		category string
+		tags     []string
+	}{
+		{"Desk Chair", 15000, 1, 5, "furniture", []string{"office", "ergonomic"}},
+		{"Desk Lamp", 2500, 3, 1, "furniture", []string{"office", "lighting"}},
+		{"Monitor", 30000, 2, 2, "electronics", []string{"office"}},
+		{"Monitor Arm", 8000, 5, 4, "furniture", []string{"ergonomic"}},
+		{"Webcam", 6000, 4, 3, "electronics", nil},
+	} {
+		product := newProduct(p.name, p.cents)
+		product.CreatedAt = base.Add(time.Duration(p.created) * time.Hour)
+		product.UpdatedAt = base.Add(time.Duration(p.updated) * time.Hour)
+		product.CategoryID = p.category
+		product.Tags = p.tags
+		require.NoError(t, repo.Save(ctx, product))
+	}
+
+	draft := newProduct("Desk Draft", 1000)
+	draft.Status = domain.StatusDraft
+	expired := newProduct("Desk Expired", 1000)
+	expiresAt := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	expired.ExpiresAt = &expiresAt
+	pending := newProduct("Desk Pending", 1000)
+	pending.ModerationStatus = domain.ModerationPendingReview
+	foreign := newProduct("Desk Foreign", 1000)
+	foreign.TenantID = "other"
+	for _, product := range []domain.Product{draft, expired, pending} {
+		require.NoError(t, repo.Save(ctx, product))
+	}
+	require.NoError(t, repo.Save(ports.WithTenant(ctx, "other"), foreign))
+}
+
+func names(products []domain.Product) []string {
+	names := make([]string, len(products))
+	for i, product := range products {
+		names[i] = product.Name
+	}
+	return names
+}
+
+func testCRUD(t *testing.T, repo ports.ProductRepository) {
+	ctx := context.Background()
+
+	_, err := repo.GetByID(ctx, uuid.NewString())
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	product := newProduct("Laptop", 99900)
+	product.Description = "14 inch"
+	product.Tags = []string{"computers"}
+	require.NoError(t, repo.Save(ctx, product))
+
+	// Saving creates; it never replaces an existing product
+	replacement := newProduct("Replacement", 100)
+	replacement.ID = product.ID
+	var duplicate *domain.DuplicateError
+	require.ErrorAs(t, repo.Save(ctx, replacement), &duplicate)
+	assert.Equal(t, domain.FieldID, duplicate.Field)
+
+	stored, err := repo.GetByID(ctx, product.ID)
+	require.NoError(t, err)
+	assert.Equal(t, product.Name, stored.Name)
+	assert.Equal(t, product.Description, stored.Description)
+	assert.Equal(t, product.Price, stored.Price)
+	assert.Equal(t, product.Tags, stored.Tags)
+	assert.True(t, product.CreatedAt.Equal(stored.CreatedAt), "created_at %s, stored %s", product.CreatedAt, stored.CreatedAt)
+	assert.Equal(t, int64(1), stored.Version)
+
+	stored.Name = "Gaming Laptop"
+	require.NoError(t, repo.Update(ctx, stored))
+	updated, err := repo.GetByID(ctx, product.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Gaming Laptop", updated.Name)
+	assert.Equal(t, int64(2), updated.Version, "updates increment the version")
+
+	listed, err := repo.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Gaming Laptop"}, names(listed))
+
+	assert.ErrorIs(t, repo.Delete(ctx, product.ID, 1), domain.ErrConflict, "a delete based on a stale version is rejected")
+	require.NoError(t, repo.Delete(ctx, product.ID, 2))
+	_, err = repo.GetByID(ctx, product.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.ErrorIs(t, repo.Delete(ctx, product.ID, 2), domain.ErrNotFound)
+}
+
+func testUpdateConflict(t *testing.T, repo ports.ProductRepository) {
+	ctx := context.Background()
+
+	product := newProduct("Keyboard", 4900)
+	require.NoError(t, repo.Save(ctx, product))
+
+	fresh, stale := product, product
+	fresh.Name = "Mechanical Keyboard"
+	require.NoError(t, repo.Update(ctx, fresh))
+	stale.Name = "Wireless Keyboard"
+	assert.ErrorIs(t, repo.Update(ctx, stale), domain.ErrConflict, "a write based on a stale version is rejected")
+
+	stored, err := repo.GetByID(ctx, product.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Mechanical Keyboard", stored.Name)
+
+	assert.ErrorIs(t, repo.Update(ctx, newProduct("Ghost", 100)), domain.ErrConflict, "a missing product cannot be updated")
+}
+
+func testGetByIDs(t *testing.T, repo ports.ProductRepository) {
+	ctx := context.Background()
+
+	desk, chair := newProduct("Desk", 20000), newProduct("Chair", 9000)
+	require.NoError(t, repo.Save(ctx, desk))
+	require.NoError(t, repo.Save(ctx, chair))
+	foreign := newProduct("Anvil", 5000)
+	foreign.TenantID = "acme"
+	require.NoError(t, repo.Save(ports.WithTenant(ctx, "acme"), foreign))
+
+	found, err := repo.GetByIDs(ctx, []string{desk.ID, uuid.NewString(), chair.ID, foreign.ID})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"Desk", "Chair"}, names(found), "missing IDs and other tenants' products are left out")
+
+	found, err = repo.GetByIDs(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, found)
+}
+
+func testUniqueSKU(t *testing.T, repo ports.ProductRepository) {
+	ctx := context.Background()
+
+	product := newProduct("Keyboard", 4900)
+	product.SKU = "KB-01"
+	require.NoError(t, repo.Save(ctx, product))
+
+	found, err := repo.GetBySKU(ctx, "KB-01")
+	require.NoError(t, err)
+	assert.Equal(t, product.ID, found.ID)
+	_, err = repo.GetBySKU(ctx, "KB-02")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	other := newProduct("Mouse", 1900)
+	other.SKU = "KB-01"
+	var duplicate *domain.DuplicateError
+	require.ErrorAs(t, repo.Save(ctx, other), &duplicate)
+	assert.Equal(t, domain.FieldSKU, duplicate.Field)
+	assert.Equal(t, "KB-01", duplicate.Value)
+
+	// Other tenants have their own SKUs
+	foreign := other
+	foreign.TenantID = "other"
+	require.NoError(t, repo.Save(ports.WithTenant(ctx, "other"), foreign))
+
+	// Deleting the product releases its SKU
+	require.NoError(t, repo.Delete(ctx, product.ID, product.Version))
+	other.ID = uuid.NewString()
+	assert.NoError(t, repo.Save(ctx, other))
+}
+
+func testTenantIsolation(t *testing.T, repo ports.ProductRepository) {
+	ctx := context.Background()
+	acme := ports.WithTenant(ctx, "acme")
+
+	product := newProduct("Anvil", 5000)
+	product.TenantID = "acme"
+	require.NoError(t, repo.Save(acme, product))
+
+	_, err := repo.GetByID(acme, product.ID)
+	require.NoError(t, err)
+	_, err = repo.GetByID(ctx, product.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound, "other tenants' products are missing")
+	assert.ErrorIs(t, repo.Delete(ctx, product.ID, product.Version), domain.ErrNotFound)
+
+	result, err := repo.ListWithFilters(ctx, ports.ProductFilters{SortBy: "price", SortOrder: "asc", Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, result.Products)
+	assert.Zero(t, result.TotalItems)
+
+	result, err = repo.ListWithFilters(acme, ports.ProductFilters{SortBy: "price", SortOrder: "asc", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Anvil"}, names(result.Products))
+}
+
+func testFilters(t *testing.T, repo ports.ProductRepository) {
+	seedCatalog(t, repo)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		filters ports.ProductFilters
+		want    []string
+	}{
+		{"everything", ports.ProductFilters{}, []string{"Desk Chair", "Desk Lamp", "Monitor", "Monitor Arm", "Webcam"}},
+		{"name contains", ports.ProductFilters{Name: "Desk"}, []string{"Desk Chair", "Desk Lamp"}},
+		{"price range", ports.ProductFilters{MinPrice: domain.NewDecimal(50, 0), MaxPrice: domain.NewDecimal(200, 0)}, []string{"Desk Chair", "Monitor Arm", "Webcam"}},
+		{"minimum price", ports.ProductFilters{MinPrice: domain.NewDecimal(150, 0)}, []string{"Desk Chair", "Monitor"}},
+		{"category", ports.ProductFilters{CategoryID: "furniture"}, []string{"Desk Chair", "Desk Lamp", "Monitor Arm"}},
+		{"any tag", ports.ProductFilters{Tags: []string{"ergonomic", "lighting"}}, []string{"Desk Chair", "Desk Lamp", "Monitor Arm"}},
+		{"all tags", ports.ProductFilters{Tags: []string{"office", "ergonomic"}, TagMatch: domain.TagMatchAll}, []string{"Desk Chair"}},
+		{"combined", ports.ProductFilters{Name: "Monitor", MaxPrice: domain.NewDecimal(100, 0)}, []string{"Monitor Arm"}},
+		{"status", ports.ProductFilters{Status: domain.StatusDraft}, []string{"Desk Draft"}},
+		{"published status", ports.ProductFilters{Name: "Desk", Status: domain.StatusPublished}, []string{"Desk Chair", "Desk Lamp"}},
+		{"hidden included", ports.ProductFilters{Name: "Desk", IncludeHidden: true}, []string{"Desk Chair", "Desk Draft", "Desk Expired", "Desk Lamp", "Desk Pending"}},
+		{"created range", ports.ProductFilters{CreatedAfter: base.Add(2 * time.Hour), CreatedBefore: base.Add(4 * time.Hour)}, []string{"Desk Lamp", "Monitor", "Webcam"}},
+		{"updated since", ports.ProductFilters{UpdatedAfter: base.Add(4 * time.Hour)}, []string{"Desk Chair", "Monitor Arm"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters := tt.filters
+			filters.SortBy, filters.SortOrder, filters.Limit = "name", "asc", 10
+			result, err := repo.ListWithFilters(ctx, filters)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, names(result.Products))
+			assert.Equal(t, len(tt.want), result.TotalItems)
+
+			count, err := repo.Count(ctx, tt.filters)
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.want), count)
+		})
+	}
+}
+
+// testSchedule checks that listings follow publish_at and auto_archive_at
+// without waiting for the jobs that update the stored status
+func testPriceCurrency(t *testing.T, repo ports.ProductRepository) {
+	seedCatalog(t, repo)
+	ctx := context.Background()
+	euros := newProduct("Desk Mat", 9000)
+	euros.Price.Currency = "EUR"
+	require.NoError(t, repo.Save(ctx, euros))
+
+	tests := []struct {
+		name    string
+		filters ports.ProductFilters
+		want    []string
+	}{
+		{"price range in the default currency", ports.ProductFilters{MinPrice: domain.NewDecimal(50, 0), MaxPrice: domain.NewDecimal(100, 0)}, []string{"Monitor Arm", "Webcam"}},
+		{"price range in another currency", ports.ProductFilters{MinPrice: domain.NewDecimal(50, 0), Currency: "EUR"}, []string{"Desk Mat"}},
+		{"currency alone", ports.ProductFilters{Currency: "EUR"}, []string{"Desk Mat"}},
+		{"no price criteria", ports.ProductFilters{Name: "Desk"}, []string{"Desk Chair", "Desk Lamp", "Desk Mat"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters := tt.filters
+			filters.SortBy, filters.SortOrder, filters.Limit = "name", "asc", 10
+			result, err := repo.ListWithFilters(ctx, filters)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, names(result.Products))
+
+			count, err := repo.Count(ctx, tt.filters)
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.want), count)
+		})
+	}
+
+	// A price sort lists one currency
+	result, err := repo.ListWithFilters(ctx, ports.ProductFilters{SortBy: "price", SortOrder: "desc", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Monitor", "Desk Chair", "Monitor Arm", "Webcam", "Desk Lamp"}, names(result.Products))
+}
+
+func testSchedule(t *testing.T, repo ports.ProductRepository) {
+	ctx := context.Background()
+	past := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	future := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+
+	due := newProduct("Due Draft", 1000)
+	due.Status, due.PublishAt = domain.StatusDraft, &past
+	scheduled := newProduct("Scheduled Draft", 1000)
+	scheduled.Status, scheduled.PublishAt = domain.StatusDraft, &future
+	ended := newProduct("Ended Season", 1000)
+	ended.AutoArchiveAt = &past
+	running := newProduct("Running Season", 1000)
+	running.AutoArchiveAt = &future
+	for _, product := range []domain.Product{due, scheduled, ended, running} {
+		require.NoError(t, repo.Save(ctx, product))
+	}
+
+	result, err := repo.ListWithFilters(ctx, ports.ProductFilters{SortBy: "name", SortOrder: "asc", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Due Draft", "Running Season"}, names(result.Products))
+
+	result, err = repo.ListWithFilters(ctx, ports.ProductFilters{Status: domain.StatusDraft, SortBy: "name", SortOrder: "asc", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Due Draft", "Scheduled Draft"}, names(result.Products))
+}
+
+func testSorting(t *testing.T, repo ports.ProductRepository) {
+	seedCatalog(t, repo)
+	ctx := context.Background()
+
+	ascending := map[string][]string{
+		"name":       {"Desk Chair", "Desk Lamp", "Monitor", "Monitor Arm", "Webcam"},
+		"price":      {"Desk Lamp", "Webcam", "Monitor Arm", "Desk Chair", "Monitor"},
+		"created_at": {"Desk Chair", "Monitor", "Desk Lamp", "Webcam", "Monitor Arm"},
+		"updated_at": {"Desk Lamp", "Monitor", "Webcam", "Monitor Arm", "Desk Chair"},
+	}
+	for sortBy, want := range ascending {
+		t.Run(sortBy, func(t *testing.T) {
+			result, err := repo.ListWithFilters(ctx, ports.ProductFilters{SortBy: sortBy, SortOrder: "asc", Limit: 10})
+			require.NoError(t, err)
+			assert.Equal(t, want, names(result.Products))
+
+			result, err = repo.ListWithFilters(ctx, ports.ProductFilters{SortBy: sortBy, SortOrder: "desc", Limit: 10})
+			require.NoError(t, err)
+			assert.Equal(t, reversed(want), names(result.Products))
+		})
+	}
+}
+
+// testSecondarySort orders products tied on price by name, whatever order
+// they were saved in
+func testSecondarySort(t *testing.T, repo ports.ProductRepository) {
+	ctx := context.Background()
+	for _, name := range []string{"Mug", "Cap", "Pen", "Bag"} {
+		cents := int64(1000)
+		if name == "Pen" {
+			cents = 500
+		}
+		require.NoError(t, repo.Save(ctx, newProduct(name, cents)))
+	}
+
+	filters := ports.ProductFilters{SortBy: "price", ThenBy: []string{"name"}, SortOrder: "asc", Limit: 10}
+	result, err := repo.ListWithFilters(ctx, filters)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Pen", "Bag", "Cap", "Mug"}, names(result.Products))
+
+	filters.SortOrder = "desc"
+	result, err = repo.ListWithFilters(ctx, filters)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Mug", "Cap", "Bag", "Pen"}, names(result.Products))
+
+	filters.SortOrder, filters.Offset, filters.Limit = "asc", 1, 2
+	result, err = repo.ListWithFilters(ctx, filters)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Bag", "Cap"}, names(result.Products))
+	assert.Equal(t, 4, result.TotalItems)
+}
+
+// testTimeRange lists the products changed within a time range in the
+// order of the bounded time, inclusive of the bounds
+func testTimeRange(t *testing.T, repo ports.ProductRepository) {
+	seedCatalog(t, repo)
+	ctx := context.Background()
+
+	result, err := repo.ListWithFilters(ctx, ports.ProductFilters{UpdatedAfter: base.Add(3 * time.Hour), SortBy: "updated_at", SortOrder: "asc", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Webcam", "Monitor Arm", "Desk Chair"}, names(result.Products))
+	assert.Equal(t, 3, result.TotalItems)
+
+	result, err = repo.ListWithFilters(ctx, ports.ProductFilters{
+		CreatedAfter:  base.Add(2 * time.Hour),
+		CreatedBefore: base.Add(4 * time.Hour),
+		SortBy:        "created_at",
+		SortOrder:     "desc",
+		Limit:         2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Webcam", "Desk Lamp"}, names(result.Products))
+	assert.Equal(t, 3, result.TotalItems)
+}
+
+func testOffsetPagination(t *testing.T, repo ports.ProductRepository) {
+	seedCatalog(t, repo)
+	ctx := context.Background()
+
+	for sortBy, want := range map[string][]string{
+		"name":       {"Monitor", "Monitor Arm"},
+		"created_at": {"Desk Lamp", "Webcam"},
+	} {
+		result, err := repo.ListWithFilters(ctx, ports.ProductFilters{SortBy: sortBy, SortOrder: "asc", Offset: 2, Limit: 2})
+		require.NoError(t, err)
+		assert.Equal(t, want, names(result.Products), "sorted by %s", sortBy)
+		assert.Equal(t, 5, result.TotalItems, "the total ignores pagination")
+	}
+
+	result, err := repo.ListWithFilters(ctx, ports.ProductFilters{SortBy: "name", SortOrder: "asc", Offset: 5, Limit: 2})
+	require.NoError(t, err)
+	assert.Empty(t, result.Products, "pages past the end are empty")
+}
+
+// testCursorPagination follows NextKey, which adapters without cursors
+// never return: the pages read must then still be a prefix of the listing
+func testCursorPagination(t *testing.T, repo ports.ProductRepository) {
+	seedCatalog(t, repo)
+	ctx := context.Background()
+
+	var read []string
+	filters := ports.ProductFilters{SortBy: "price", SortOrder: "desc", Limit: 2}
+	for {
+		page, err := repo.ListWithFilters(ctx, filters)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(page.Products), 2)
+		read = append(read, names(page.Products)...)
+		if page.NextKey == nil {
+			break
+		}
+		require.Less(t, len(read), 10, "cursors must stop once every product was read")
+		filters.StartKey = page.NextKey
+	}
+	all := []string{"Monitor", "Desk Chair", "Monitor Arm", "Webcam", "Desk Lamp"}
+	require.LessOrEqual(t, len(read), len(all))
+	assert.Equal(t, all[:len(read)], read)
+}
+
+func testKeysetPagination(t *testing.T, repo ports.ProductRepository) {
+	seedCatalog(t, repo)
+	ctx := context.Background()
+
+	for _, order := range []string{"asc", "desc"} {
+		for _, sortBy := range []string{"name", "price", "created_at"} {
+			filters := ports.ProductFilters{SortBy: sortBy, SortOrder: order, Limit: 10}
+			full, err := repo.ListWithFilters(ctx, filters)
+			require.NoError(t, err)
+			require.Len(t, full.Products, 5)
+
+			after := ports.ProductSortKey(full.Products[1], sortBy)
+			filters.After = &after
+			filters.Limit = 2
+			page, err := repo.ListWithFilters(ctx, filters)
+			require.NoError(t, err)
+			assert.Equal(t, names(full.Products[2:4]), names(page.Products), "%s %s after %s", sortBy, order, full.Products[1].Name)
+		}
+	}
+}
+
+func reversed(values []string) []string {
+	out := make([]string, len(values))
+	for i, value := range values {
+		out[len(values)-1-i] = value
+	}
+	return out
+}
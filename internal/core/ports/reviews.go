@@ -0,0 +1,50 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// ReviewRepository stores reviews under their product and keeps the
+// product's rating totals in step with them: every write adjusts the
+// totals in the same transaction. Writes return domain.ErrNotFound when
+// the product is gone and domain.ErrConflict when the review changed since
+// it was read.
+type ReviewRepository interface {
+	Create(ctx context.Context, review domain.Review) error
+	// Get returns domain.ErrReviewNotFound for reviews missing from the
+	// tenant of ctx
+	Get(ctx context.Context, productID, id string) (domain.Review, error)
+	// List returns up to limit reviews of the product, newest first,
+	// starting after the review with ID after when it is set
+	List(ctx context.Context, productID, after string, limit int) ([]domain.Review, error)
+	// Update saves review, which was read with previousRating
+	Update(ctx context.Context, review domain.Review, previousRating int) error
+	Delete(ctx context.Context, review domain.Review) error
+}
+
+// ReviewInput is the rating and comment a caller submits
+type ReviewInput struct {
+	Rating  int
+	Comment string
+}
+
+// ReviewPage is a page of a product's reviews with its rating summary.
+// NextAfter is the ID to continue from, empty on the last page.
+type ReviewPage struct {
+	ProductID string               `json:"product_id"`
+	Rating    domain.RatingSummary `json:"rating"`
+	Reviews   []domain.Review      `json:"reviews"`
+	NextAfter string               `json:"next_after,omitempty"`
+}
+
+// ReviewService manages product reviews. Reviews are written by the caller
+// of ctx, and only their author may edit or delete them.
+type ReviewService interface {
+	Create(ctx context.Context, productID string, input ReviewInput) (domain.Review, error)
+	Get(ctx context.Context, productID, id string) (domain.Review, error)
+	List(ctx context.Context, productID, after string, limit int) (ReviewPage, error)
+	Update(ctx context.Context, productID, id string, input ReviewInput) (domain.Review, error)
+	Delete(ctx context.Context, productID, id string) error
+}
@@ -0,0 +1,31 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// SearchQuery is a free-text product search
+type SearchQuery struct {
+	Text  string
+	Limit int
+}
+
+// SearchRepository finds the visible products matching a free-text query,
+// most relevant first
+type SearchRepository interface {
+	Search(ctx context.Context, query SearchQuery) ([]domain.SearchHit, error)
+}
+
+// SearchIndexer keeps a search index in step with the products table.
+// Documents carry the product's version, so a change applied late never
+// replaces a newer one.
+type SearchIndexer interface {
+	Index(ctx context.Context, product domain.Product) error
+	Remove(ctx context.Context, productID string) error
+}
+
+type SearchService interface {
+	Search(ctx context.Context, query SearchQuery) ([]domain.SearchHit, error)
+}
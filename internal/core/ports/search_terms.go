@@ -0,0 +1,27 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// SearchTermRepository stores per-day search counters for each term
+type SearchTermRepository interface {
+	IncrementSearch(ctx context.Context, term string, day time.Time, zeroResults bool) error
+	DailySearchTerms(ctx context.Context, day time.Time) ([]domain.SearchTermStats, error)
+}
+
+// SearchTermQuery selects the days, inclusive, and terms of a report
+type SearchTermQuery struct {
+	From            time.Time
+	To              time.Time
+	ZeroResultsOnly bool
+	Limit           int
+}
+
+type SearchTermService interface {
+	RecordSearch(ctx context.Context, term string, results int64)
+	Report(ctx context.Context, query SearchTermQuery) ([]domain.SearchTermStats, error)
+}
@@ -2,14 +2,84 @@ package ports
 
 import (
 	"context"
+	"time"
+
 	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
 )
 
+// ProductInput carries the client-editable attributes of a product
+type ProductInput struct {
+	// ID names a new product instead of a generated UUID; ignored on update
+	ID          string
+	Name        string
+	Description string
+	Price       domain.Money
+	ExpiresAt   *time.Time
+	// PublishAt keeps the product as a draft until then; nil publishes now
+	PublishAt *time.Time
+	// AutoArchiveAt archives the product automatically; nil cancels it
+	AutoArchiveAt *time.Time
+	// CostPrice is in major units of the price's currency and only
+	// accepted from admins; nil keeps the stored cost
+	CostPrice *domain.Decimal
+	// Version, when set on update, must match the stored version
+	Version *int64
+	// CategoryID must name an existing category; empty removes it
+	CategoryID string
+	// Tags replace the product's tags; empty removes them
+	Tags []string
+	// SKU and Barcode must not be held by another product of the tenant;
+	// empty removes them
+	SKU     string
+	Barcode string
+}
+
+// CloneInput changes the copy of a product. Nil fields keep the value of
+// the original; NameSuffix is appended to the name either way. The SKU,
+// barcode and schedule are never copied, since they belong to one product.
+type CloneInput struct {
+	// ID names the copy instead of a generated UUID
+	ID          string
+	NameSuffix  string
+	Name        *string
+	Description *string
+	Price       *domain.Money
+	CategoryID  *string
+	Tags        []string
+	// CostPrice is in major units of the price's currency and only
+	// accepted from admins
+	CostPrice     *domain.Decimal
+	ExpiresAt     *time.Time
+	PublishAt     *time.Time
+	AutoArchiveAt *time.Time
+	SKU           string
+	Barcode       string
+}
+
 type ProductService interface {
-	Create(ctx context.Context, name, description string, price float64) (domain.Product, error)
+	Create(ctx context.Context, input ProductInput) (domain.Product, error)
 	Get(ctx context.Context, id string) (domain.Product, error)
-	Update(ctx context.Context, id, name, description string, price float64) (domain.Product, error)
-	Delete(ctx context.Context, id string) error
+	// RecordView tracks that a client was sent the product. Only the HTTP
+	// read calls it, so internal reads and 304s are not counted as views.
+	RecordView(ctx context.Context, id string)
+	// GetBySKU finds the tenant's product with an SKU, in any letter case
+	GetBySKU(ctx context.Context, sku string) (domain.Product, error)
+	// GetMany reads several products at once, returning them in the order
+	// of ids along with the IDs that were not found
+	GetMany(ctx context.Context, ids []string) ([]domain.Product, []string, error)
+	// Clone creates a new product from an existing one, with its own ID,
+	// timestamps and version and the changes of input
+	Clone(ctx context.Context, id string, input CloneInput) (domain.Product, error)
+	Update(ctx context.Context, id string, input ProductInput) (domain.Product, error)
+	// SetTranslation adds or replaces the product's name and description in
+	// one locale. A non-nil version must match the stored one.
+	SetTranslation(ctx context.Context, id, locale string, translation domain.Translation, version *int64) (domain.Product, error)
+	// Delete removes a product and leaves a tombstone redirecting its ID to
+	// replacedBy, or marking it gone when replacedBy is empty. A non-nil
+	// version must match the stored one.
+	Delete(ctx context.Context, id, replacedBy string, version *int64) error
 	List(ctx context.Context) ([]domain.Product, error)
 	ListWithFilters(ctx context.Context, filters ProductFilters) (*ProductListResult, error)
+	// Count returns how many products match the listing filters
+	Count(ctx context.Context, filters ProductFilters) (int, error)
 }
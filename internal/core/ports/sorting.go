@@ -0,0 +1,123 @@
+package ports
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// SortKey is a position in a product listing for keyset pagination: the
+// sort field value and ID of the last product already seen. The ID breaks
+// ties between products with the same value, so every product has exactly
+// one position.
+type SortKey struct {
+	Value string
+	ID    string
+}
+
+// ProductSortKey returns the position of product in a listing sorted by
+// sortBy. Names are kept as is, prices are in major units and times are
+// RFC 3339 in UTC, the way they are stored.
+func ProductSortKey(product domain.Product, sortBy string) SortKey {
+	key := SortKey{ID: product.ID}
+	switch sortBy {
+	case "name":
+		key.Value = product.Name
+	case "price":
+		key.Value = product.Price.DecimalString()
+	case "updated_at":
+		key.Value = formatSortTime(product.UpdatedAt)
+	default:
+		key.Value = formatSortTime(product.CreatedAt)
+	}
+	return key
+}
+
+// ParseSortKey checks a position sent back by a client for a listing
+// sorted by sortBy and normalizes its value to the ProductSortKey format
+func ParseSortKey(sortBy, value, id string) (SortKey, error) {
+	switch sortBy {
+	case "name":
+	case "price":
+		price, err := domain.ParseDecimal(value)
+		if err != nil || price.Sign() < 0 {
+			return SortKey{}, fmt.Errorf("a price position must be a non-negative number, got %q", value)
+		}
+		value = price.String()
+	default:
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return SortKey{}, fmt.Errorf("a %s position must be an RFC 3339 time, got %q", sortBy, value)
+		}
+		value = formatSortTime(t)
+	}
+	return SortKey{Value: value, ID: id}, nil
+}
+
+// Compare orders product against the position in ascending order of
+// sortBy: negative when the product comes first, positive when it comes
+// after and zero only for the product at the position
+func (k SortKey) Compare(product domain.Product, sortBy string) int {
+	var c int
+	switch sortBy {
+	case "name":
+		c = strings.Compare(product.Name, k.Value)
+	case "price":
+		// Listings sorted by price hold a single currency, see
+		// ProductFilters.PriceCurrency
+		price, _ := domain.ParseDecimal(k.Value)
+		c = product.Price.Major().Cmp(price)
+	case "updated_at":
+		t, _ := time.Parse(time.RFC3339Nano, k.Value)
+		c = product.UpdatedAt.Compare(t)
+	default:
+		t, _ := time.Parse(time.RFC3339Nano, k.Value)
+		c = product.CreatedAt.Compare(t)
+	}
+	if c != 0 {
+		return c
+	}
+	return strings.Compare(product.ID, k.ID)
+}
+
+// CompareProducts orders two products by sortBy ascending, breaking ties
+// by ID the same way SortKey.Compare does
+func CompareProducts(a, b domain.Product, sortBy string) int {
+	return CompareProductsBy(&a, &b, []string{sortBy})
+}
+
+// CompareProductsBy orders two products ascending by each of the sort
+// fields in turn, breaking the remaining ties by ID. It takes pointers, as
+// sorts compare each product many times.
+func CompareProductsBy(a, b *domain.Product, sortBy []string) int {
+	for _, field := range sortBy {
+		if c := compareField(a, b, field); c != 0 {
+			return c
+		}
+	}
+	return strings.Compare(a.ID, b.ID)
+}
+
+func compareField(a, b *domain.Product, field string) int {
+	switch field {
+	case "name":
+		return strings.Compare(a.Name, b.Name)
+	case "price":
+		// Amounts in different currencies do not compare; keep them apart
+		// rather than interleave them
+		if a.Price.Currency != b.Price.Currency {
+			return strings.Compare(a.Price.Currency, b.Price.Currency)
+		}
+		return a.Price.Major().Cmp(b.Price.Major())
+	case "updated_at":
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	default:
+		return a.CreatedAt.Compare(b.CreatedAt)
+	}
+}
+
+func formatSortTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,23 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// StockRepository adjusts stock atomically. AdjustStock applies delta to
+// the product as read, writing the outbox events of ctx with it, and
+// returns the new quantity, domain.ErrNotFound for unknown products,
+// domain.ErrConflict when the product changed since it was read and
+// domain.ErrInsufficientStock when stock would go negative.
+type StockRepository interface {
+	AdjustStock(ctx context.Context, product domain.Product, delta int64, now time.Time) (int64, error)
+}
+
+type StockService interface {
+	// Adjust adds delta, which may be negative, to the product's stock
+	Adjust(ctx context.Context, id string, delta int64) (domain.StockLevel, error)
+	Get(ctx context.Context, id string) (domain.StockLevel, error)
+}
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+type TagService interface {
+	// ListTags returns the distinct tags of the tenant's live products with
+	// how many products carry each, most used first
+	ListTags(ctx context.Context) ([]domain.TagCount, error)
+}
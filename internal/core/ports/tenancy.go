@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+)
+
+type tenantKey struct{}
+
+// WithTenant scopes every product read and write made with ctx to tenant.
+// An empty tenant is the default one, which owns the products written
+// before multi-tenancy existed.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantID returns the tenant ctx is scoped to, empty for the default one
+func TenantID(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
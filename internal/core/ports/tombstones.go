@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// TombstoneRepository keeps the IDs of deleted products. Get returns
+// domain.ErrNotFound for IDs that were never deleted; Delete forgets an ID
+// whose product was restored.
+type TombstoneRepository interface {
+	Save(ctx context.Context, tombstone domain.Tombstone) error
+	Get(ctx context.Context, id string) (domain.Tombstone, error)
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,61 @@
+package ports
+
+import (
+	"context"
+	"sync"
+)
+
+// UnitOfWork commits the writes of several repositories together. The
+// repositories taking part hold back the writes made with the context
+// passed to fn, and Do commits them all at once after fn returns nil;
+// when fn or the commit fails, none of them is applied. Reads made
+// inside fn see the state from before the unit of work.
+//
+// Errors a write would have returned on its own, such as
+// domain.ErrConflict or a *domain.DuplicateError, are returned by Do
+// when the commit fails on that write.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type commitHooksKey struct{}
+
+// CommitHooks are the functions AfterCommit held back until a unit of work
+// commits
+type CommitHooks struct {
+	mu  sync.Mutex
+	fns []func()
+}
+
+// WithCommitHooks makes AfterCommit hold functions back in hooks. A
+// UnitOfWork passes fn such a context and runs the hooks once it commits;
+// a nil hooks makes AfterCommit run functions right away again, for writes
+// that do not take part in the unit of work.
+func WithCommitHooks(ctx context.Context, hooks *CommitHooks) context.Context {
+	return context.WithValue(ctx, commitHooksKey{}, hooks)
+}
+
+// Run calls the functions held back, in the order they were added
+func (h *CommitHooks) Run() {
+	h.mu.Lock()
+	fns := h.fns
+	h.fns = nil
+	h.mu.Unlock()
+	for _, fn := range fns {
+		fn()
+	}
+}
+
+// AfterCommit runs fn once the unit of work ctx belongs to commits, never
+// if it fails, and right away outside of one. Caches use it to drop their
+// copies only once the write they follow is visible.
+func AfterCommit(ctx context.Context, fn func()) {
+	hooks, _ := ctx.Value(commitHooksKey{}).(*CommitHooks)
+	if hooks == nil {
+		fn()
+		return
+	}
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+	hooks.fns = append(hooks.fns, fn)
+}
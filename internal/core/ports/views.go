@@ -0,0 +1,30 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+// ViewRepository stores per-day view counters and trending snapshots
+type ViewRepository interface {
+	IncrementViews(ctx context.Context, productID string, day time.Time) error
+	DailyViews(ctx context.Context, day time.Time) ([]domain.ProductViews, error)
+	SaveTrending(ctx context.Context, snapshot domain.TrendingSnapshot) error
+	LatestTrending(ctx context.Context) (domain.TrendingSnapshot, error)
+}
+
+// TrendingProduct is a product together with its views in the trending window
+type TrendingProduct struct {
+	Product domain.Product
+	Views   int64
+}
+
+type ViewService interface {
+	// RecordView counts a view; a non-empty sessionID also feeds the
+	// recommender
+	RecordView(ctx context.Context, productID, sessionID string) error
+	Trending(ctx context.Context, limit int) ([]TrendingProduct, error)
+	RollupTrending(ctx context.Context) error
+}
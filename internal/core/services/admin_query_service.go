@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+const maxStatementLimit = 100
+
+type adminQueryService struct {
+	repo   ports.StatementRepository
+	logger *slog.Logger
+}
+
+func NewAdminQueryService(repo ports.StatementRepository, logger *slog.Logger) ports.AdminQueryService {
+	return &adminQueryService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (s *adminQueryService) Query(ctx context.Context, query ports.StatementQuery, fields []string) (*ports.StatementResult, error) {
+	if !isReadOnlyStatement(query.Statement) {
+		s.logger.WarnContext(ctx, "rejected admin statement", "statement", query.Statement)
+		return nil, domain.ErrForbiddenQuery
+	}
+	if query.Limit <= 0 || query.Limit > maxStatementLimit {
+		query.Limit = maxStatementLimit
+	}
+
+	s.logger.InfoContext(ctx, "executing admin statement", "statement", query.Statement, "limit", query.Limit)
+
+	result, err := s.repo.ExecuteStatement(ctx, query)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to execute admin statement", "error", err)
+		return nil, err
+	}
+
+	if len(fields) > 0 {
+		result.Items = shapeItems(result.Items, fields)
+	}
+	return result, nil
+}
+
+// isReadOnlyStatement accepts a single SELECT statement. PartiQL writes
+// (INSERT, UPDATE, DELETE) never start with SELECT, and a trailing
+// semicolon is the only one allowed.
+func isReadOnlyStatement(statement string) bool {
+	trimmed := strings.TrimSuffix(strings.TrimSpace(statement), ";")
+	if strings.Contains(trimmed, ";") {
+		return false
+	}
+	fields := strings.Fields(trimmed)
+	return len(fields) > 0 && strings.EqualFold(fields[0], "SELECT")
+}
+
+// shapeItems keeps only the requested attributes of each item
+func shapeItems(items []map[string]interface{}, fields []string) []map[string]interface{} {
+	shaped := make([]map[string]interface{}, len(items))
+	for i, item := range items {
+		row := make(map[string]interface{}, len(fields))
+		for _, field := range fields {
+			if value, ok := item[field]; ok {
+				row[field] = value
+			}
+		}
+		shaped[i] = row
+	}
+	return shaped
+}
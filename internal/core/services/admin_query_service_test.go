@@ -0,0 +1,45 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsReadOnlyStatement(t *testing.T) {
+	tests := []struct {
+		name      string
+		statement string
+		expected  bool
+	}{
+		{"select", `SELECT * FROM "products"`, true},
+		{"lowercase select", `select id FROM "products" WHERE price > ?`, true},
+		{"trailing semicolon", `SELECT * FROM "products";`, true},
+		{"leading whitespace", "  \nSELECT * FROM \"products\"", true},
+		{"update", `UPDATE "products" SET price = 1 WHERE id = 'x'`, false},
+		{"delete", `DELETE FROM "products" WHERE id = 'x'`, false},
+		{"insert", `INSERT INTO "products" VALUE {'id': 'x'}`, false},
+		{"stacked statements", `SELECT * FROM "products"; DELETE FROM "products" WHERE id = 'x'`, false},
+		{"empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isReadOnlyStatement(tt.statement))
+		})
+	}
+}
+
+func TestShapeItems(t *testing.T) {
+	items := []map[string]interface{}{
+		{"id": "1", "name": "Laptop", "price": 10.0},
+		{"id": "2", "price": 20.0},
+	}
+
+	shaped := shapeItems(items, []string{"id", "name"})
+
+	assert.Equal(t, []map[string]interface{}{
+		{"id": "1", "name": "Laptop"},
+		{"id": "2"},
+	}, shaped)
+}
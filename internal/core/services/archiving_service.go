@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+type archivingService struct {
+	repo          ports.ArchivingRepository
+	analytics     ports.AnalyticsPublisher
+	warningWindow time.Duration
+	logger        *slog.Logger
+	now           func() time.Time
+}
+
+func NewArchivingService(repo ports.ArchivingRepository, analytics ports.AnalyticsPublisher, warningWindow time.Duration, logger *slog.Logger) ports.ArchivingService {
+	return &archivingService{
+		repo:          repo,
+		analytics:     analytics,
+		warningWindow: warningWindow,
+		logger:        logger,
+		now:           time.Now,
+	}
+}
+
+// ArchiveDue archives products past their auto_archive_at and warns about
+// the ones that will be archived within the warning window, so owners can
+// push the date back. Each step writes a product.updated event and a
+// product.archived or product.archive_warning event with it. Failures are
+// retried on the next run.
+func (s *archivingService) ArchiveDue(ctx context.Context) error {
+	now := s.now().UTC()
+	scheduled, err := s.repo.ListScheduledForArchival(ctx, now.Add(s.warningWindow))
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, product := range scheduled {
+		// Jobs see every tenant; each write is scoped to the product's
+		productCtx := ports.WithTenant(ctx, product.TenantID)
+		autoArchiveAt := product.AutoArchiveAt
+		updated := product
+		updated.Version++
+		var eventType string
+		switch {
+		case product.IsDueForArchival(now):
+			if err := updated.Archive(now); err != nil {
+				s.logger.WarnContext(ctx, "product cannot be archived", "id", product.ID, "error", err)
+				continue
+			}
+			updated.AutoArchiveAt = nil
+			updated.ArchiveWarnedAt = nil
+			eventType = domain.EventProductArchived
+			err = s.repo.MarkArchived(withLifecycleEvent(productCtx, updated, eventType, now), product, now)
+		case product.NeedsArchiveWarning(now, s.warningWindow):
+			warnedAt := now
+			updated.ArchiveWarnedAt = &warnedAt
+			eventType = domain.EventProductArchiveWarning
+			err = s.repo.MarkArchiveWarned(withLifecycleEvent(productCtx, updated, eventType, now), product, now)
+		default:
+			continue
+		}
+
+		if err != nil {
+			// Conflicts mean the product was edited or handled by another run
+			if errors.Is(err, domain.ErrConflict) {
+				s.logger.DebugContext(ctx, "product changed before archival step, skipping", "id", product.ID)
+				continue
+			}
+			s.logger.ErrorContext(ctx, "failed to archive product", "id", product.ID, "step", eventType, "error", err)
+			errs = append(errs, err)
+			continue
+		}
+
+		s.analytics.Track(ctx, domain.AnalyticsEvent{
+			Type:       eventType,
+			ProductID:  product.ID,
+			Properties: map[string]interface{}{"auto_archive_at": autoArchiveAt},
+			OccurredAt: now,
+		})
+	}
+	return errors.Join(errs...)
+}
+
+// withLifecycleEvent attaches the product.updated event of the snapshot a
+// conditional write leaves behind, and an event of eventType carrying the
+// same snapshot, so both are written with it
+func withLifecycleEvent(ctx context.Context, updated domain.Product, eventType string, now time.Time) context.Context {
+	return ports.WithOutboxEvents(ctx, []domain.ProductEvent{
+		domain.NewProductEvent(domain.EventProductUpdated, updated.ID, &updated, now),
+		domain.NewProductEvent(eventType, updated.ID, &updated, now),
+	})
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+type fakeArchivingRepository struct {
+	scheduled []domain.Product
+	warned    []string
+	archived  []string
+	events    []domain.ProductEvent
+}
+
+func (f *fakeArchivingRepository) ListScheduledForArchival(ctx context.Context, until time.Time) ([]domain.Product, error) {
+	return f.scheduled, nil
+}
+
+func (f *fakeArchivingRepository) MarkArchiveWarned(ctx context.Context, product domain.Product, now time.Time) error {
+	f.warned = append(f.warned, product.ID)
+	f.events = append(f.events, ports.OutboxEvents(ctx)...)
+	return nil
+}
+
+func (f *fakeArchivingRepository) MarkArchived(ctx context.Context, product domain.Product, now time.Time) error {
+	f.archived = append(f.archived, product.ID)
+	f.events = append(f.events, ports.OutboxEvents(ctx)...)
+	return nil
+}
+
+func TestArchiveDue(t *testing.T) {
+	now := time.Date(2024, 8, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	soon := now.Add(24 * time.Hour)
+	repo := &fakeArchivingRepository{
+		scheduled: []domain.Product{
+			{ID: "due", Status: domain.StatusPublished, AutoArchiveAt: &past},
+			{ID: "soon", Status: domain.StatusPublished, AutoArchiveAt: &soon},
+			{ID: "already-warned", Status: domain.StatusPublished, AutoArchiveAt: &soon, ArchiveWarnedAt: &past},
+		},
+	}
+	events := &recordingPublisher{}
+	service := &archivingService{
+		repo:          repo,
+		analytics:     events,
+		warningWindow: 72 * time.Hour,
+		logger:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+		now:           func() time.Time { return now },
+	}
+
+	assert.NoError(t, service.ArchiveDue(context.Background()))
+	assert.Equal(t, []string{"due"}, repo.archived)
+	assert.Equal(t, []string{"soon"}, repo.warned)
+	if assert.Len(t, events.events, 2) {
+		assert.Equal(t, domain.EventProductArchived, events.events[0].Type)
+		assert.Equal(t, domain.EventProductArchiveWarning, events.events[1].Type)
+	}
+	// Each step is written with the product it leaves behind, announced
+	// as an update and as the step itself
+	if assert.Len(t, repo.events, 4) {
+		assert.Equal(t, domain.EventProductUpdated, repo.events[0].Type)
+		assert.Equal(t, domain.StatusArchived, repo.events[0].Product.Status)
+		assert.Nil(t, repo.events[0].Product.AutoArchiveAt)
+		assert.Equal(t, domain.EventProductArchived, repo.events[1].Type)
+		assert.Equal(t, repo.events[0].Product, repo.events[1].Product)
+		assert.Equal(t, &now, repo.events[2].Product.ArchiveWarnedAt)
+		assert.Equal(t, int64(1), repo.events[2].Product.Version)
+		assert.Equal(t, domain.EventProductArchiveWarning, repo.events[3].Type)
+	}
+}
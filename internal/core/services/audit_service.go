@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+type auditService struct {
+	auditLog ports.AuditLogger
+	products ports.ProductRepository
+	logger   *slog.Logger
+}
+
+func NewAuditService(auditLog ports.AuditLogger, products ports.ProductRepository, logger *slog.Logger) ports.AuditService {
+	return &auditService{
+		auditLog: auditLog,
+		products: products,
+		logger:   logger,
+	}
+}
+
+// History returns the product's change history. Deleted products keep
+// theirs; a live product written before auditing began has an empty one.
+func (s *auditService) History(ctx context.Context, productID string, limit int) ([]domain.AuditEntry, error) {
+	entries, err := s.auditLog.History(ctx, productID, limit)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to read audit history", "id", productID, "error", err)
+		return nil, err
+	}
+	if len(entries) > 0 {
+		return entries, nil
+	}
+
+	// Without entries, tell a product that was never audited from one
+	// that does not exist
+	if _, err := s.products.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// recordAudit records a committed write in the audit log. The write already
+// happened, so a failure is logged rather than returned.
+func recordAudit(ctx context.Context, auditLog ports.AuditLogger, logger *slog.Logger, action string, before, after *domain.Product, occurredAt time.Time) {
+	entry, err := domain.NewAuditEntry(action, ports.Actor(ctx), before, after, occurredAt)
+	if err == nil {
+		err = auditLog.Record(ctx, entry)
+	}
+	if err != nil {
+		logger.ErrorContext(ctx, "failed to record audit entry", "action", action, "error", err)
+	}
+}
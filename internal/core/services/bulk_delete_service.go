@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+// errStopStream ends a stream that already matched too many products
+var errStopStream = errors.New("stop stream")
+
+type bulkDeleteService struct {
+	products   ports.ProductRepository
+	streamer   ports.ProductStreamer
+	deleter    ports.ProductBatchDeleter
+	counts     ports.CategoryCounter
+	tombstones ports.TombstoneRepository
+	auditLog   ports.AuditLogger
+	logger     *slog.Logger
+}
+
+func NewBulkDeleteService(products ports.ProductRepository, streamer ports.ProductStreamer, deleter ports.ProductBatchDeleter, counts ports.CategoryCounter, tombstones ports.TombstoneRepository, auditLog ports.AuditLogger, logger *slog.Logger) ports.BulkDeleteService {
+	return &bulkDeleteService{
+		products:   products,
+		streamer:   streamer,
+		deleter:    deleter,
+		counts:     counts,
+		tombstones: tombstones,
+		auditLog:   auditLog,
+		logger:     logger,
+	}
+}
+
+// BulkDelete selects the products again on every call, so the confirmation
+// token of a dry run only deletes the exact products and versions it
+// previewed. The deletes are batched and conditional on those versions: a
+// product updated between the selection and its batch is kept, and so is
+// its tombstone dropped again. Like imports, bulk deletes yield storage
+// capacity to interactive requests.
+func (s *bulkDeleteService) BulkDelete(ctx context.Context, selection ports.BulkDeleteSelection, dryRun bool, confirmation string) (domain.BulkDeleteSummary, error) {
+	if !dryRun && confirmation == "" {
+		return domain.BulkDeleteSummary{}, domain.ErrConfirmationRequired
+	}
+	ctx = ports.WithBackgroundPriority(ctx)
+	products, missing, err := s.selectProducts(ctx, selection)
+	if err != nil {
+		if !errors.Is(err, domain.ErrBulkDeleteTooLarge) {
+			s.logger.ErrorContext(ctx, "failed to select products to delete", "error", err)
+		}
+		return domain.BulkDeleteSummary{}, err
+	}
+
+	summary := domain.BulkDeleteSummary{DryRun: dryRun, Matched: len(products), IDs: productIDs(products), Missing: missing}
+	token := confirmationToken(ports.TenantID(ctx), products)
+	if dryRun {
+		summary.ConfirmationToken = token
+		s.logger.InfoContext(ctx, "bulk delete previewed", "matched", summary.Matched)
+		return summary, nil
+	}
+	if confirmation != token {
+		return domain.BulkDeleteSummary{}, domain.ErrBulkDeleteChanged
+	}
+	if len(products) == 0 {
+		return summary, nil
+	}
+
+	// Tombstones go first, as for a single delete
+	deletedAt := time.Now().UTC()
+	events := make([]domain.ProductEvent, len(products))
+	for i, product := range products {
+		tombstone := domain.Tombstone{ID: product.ID, DeletedAt: deletedAt, TenantID: ports.TenantID(ctx)}
+		if err := s.tombstones.Save(ctx, tombstone); err != nil {
+			s.logger.ErrorContext(ctx, "failed to save tombstone", "id", product.ID, "error", err)
+			return domain.BulkDeleteSummary{}, err
+		}
+		events[i] = domain.NewProductEvent(domain.EventProductDeleted, product.ID, nil, deletedAt)
+	}
+	skipped, err := s.deleter.DeleteBatch(ports.WithOutboxEvents(ctx, events), products)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to delete products", "count", len(products), "error", err)
+		return domain.BulkDeleteSummary{}, err
+	}
+
+	kept := make(map[string]bool, len(skipped))
+	for _, id := range skipped {
+		kept[id] = true
+		s.dropTombstone(ctx, id)
+	}
+	summary.IDs = summary.IDs[:0]
+	deleted := make([]domain.Product, 0, len(products))
+	for i := range products {
+		product := &products[i]
+		if kept[product.ID] {
+			continue
+		}
+		summary.IDs = append(summary.IDs, product.ID)
+		deleted = append(deleted, *product)
+		recordAudit(ctx, s.auditLog, s.logger, domain.AuditDelete, product, nil, deletedAt)
+	}
+	adjustCategoryCounts(ctx, s.counts, s.logger, deleted, -1)
+	summary.Deleted = len(summary.IDs)
+	summary.Skipped = skipped
+
+	s.logger.InfoContext(ctx, "products bulk deleted", "deleted", summary.Deleted, "skipped", len(skipped))
+	return summary, nil
+}
+
+// dropTombstone removes the tombstone saved for a product the batch kept,
+// unless the product is gone after all: someone else deleted it in the
+// meantime, and then the tombstone is right
+func (s *bulkDeleteService) dropTombstone(ctx context.Context, id string) {
+	_, err := s.products.GetByID(ports.WithConsistentRead(ctx), id)
+	if errors.Is(err, domain.ErrNotFound) {
+		return
+	}
+	if err == nil {
+		err = s.tombstones.Delete(ctx, id)
+	}
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to drop the tombstone of a kept product", "id", id, "error", err)
+	}
+}
+
+// selectProducts reads the products of the selection sorted by ID, along
+// with the requested IDs that were not found
+func (s *bulkDeleteService) selectProducts(ctx context.Context, selection ports.BulkDeleteSelection) ([]domain.Product, []string, error) {
+	var products []domain.Product
+	var missing []string
+	if selection.Filters != nil {
+		err := s.streamer.StreamProducts(ctx, *selection.Filters, func(page []domain.Product) error {
+			if len(products)+len(page) > domain.MaxBulkDelete {
+				return errStopStream
+			}
+			products = append(products, page...)
+			return nil
+		})
+		if errors.Is(err, errStopStream) {
+			return nil, nil, domain.ErrBulkDeleteTooLarge
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		ids := uniqueIDs(selection.IDs)
+		if len(ids) > domain.MaxBulkDelete {
+			return nil, nil, domain.ErrBulkDeleteTooLarge
+		}
+		found, err := s.products.GetByIDs(ports.WithConsistentRead(ctx), ids)
+		if err != nil {
+			return nil, nil, err
+		}
+		products = found
+		seen := make(map[string]bool, len(found))
+		for _, product := range found {
+			seen[product.ID] = true
+		}
+		missing = []string{}
+		for _, id := range ids {
+			if !seen[id] {
+				missing = append(missing, id)
+			}
+		}
+	}
+	sort.Slice(products, func(i, j int) bool { return products[i].ID < products[j].ID })
+	return products, missing, nil
+}
+
+// uniqueIDs drops repeated IDs, keeping the first occurrence of each
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+func productIDs(products []domain.Product) []string {
+	ids := make([]string, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+	}
+	return ids
+}
+
+// confirmationToken fingerprints the products of a selection and their
+// versions, so a dry run only confirms the exact state it previewed
+func confirmationToken(tenant string, products []domain.Product) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\n", tenant)
+	for _, product := range products {
+		fmt.Fprintf(hash, "%s:%d\n", product.ID, product.Version)
+	}
+	return hex.EncodeToString(hash.Sum(nil)[:16])
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// DeleteBatch keeps the products whose stored version differs, like the
+// conditional deletes of products holding an SKU or barcode
+func (f *fakeProductRepository) DeleteBatch(ctx context.Context, products []domain.Product) ([]string, error) {
+	var skipped []string
+	for _, product := range products {
+		stored, ok := f.products[product.ID]
+		if !ok || stored.Version != product.Version {
+			skipped = append(skipped, product.ID)
+			continue
+		}
+		delete(f.products, product.ID)
+	}
+	f.outbox = append(f.outbox, ports.OutboxEvents(ctx)...)
+	return skipped, nil
+}
+
+func newTestBulkDeleteService(repo *fakeProductRepository, streamer ports.ProductStreamer, tombstones ports.TombstoneRepository, auditLog ports.AuditLogger) ports.BulkDeleteService {
+	return NewBulkDeleteService(repo, streamer, repo, nil, tombstones, auditLog, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestBulkDeleteService_ByIDs(t *testing.T) {
+	repo := newFakeProductRepository()
+	repo.products["1"] = domain.Product{ID: "1", Name: "Laptop", Version: 1}
+	repo.products["2"] = domain.Product{ID: "2", Name: "Mouse", Version: 3}
+	repo.products["3"] = domain.Product{ID: "3", Name: "Desk", Version: 1}
+	tombstones := &fakeTombstoneRepository{tombstones: map[string]domain.Tombstone{}}
+	auditLog := &fakeAuditLog{}
+	service := newTestBulkDeleteService(repo, &fakeStreamer{}, tombstones, auditLog)
+	selection := ports.BulkDeleteSelection{IDs: []string{"2", "1", "missing", "1"}}
+
+	preview, err := service.BulkDelete(context.Background(), selection, true, "")
+	require.NoError(t, err)
+	assert.True(t, preview.DryRun)
+	assert.Equal(t, 2, preview.Matched)
+	assert.Equal(t, 0, preview.Deleted)
+	assert.Equal(t, []string{"1", "2"}, preview.IDs)
+	assert.Equal(t, []string{"missing"}, preview.Missing)
+	assert.NotEmpty(t, preview.ConfirmationToken)
+	assert.Len(t, repo.products, 3, "a dry run deletes nothing")
+
+	summary, err := service.BulkDelete(context.Background(), selection, false, preview.ConfirmationToken)
+	require.NoError(t, err)
+	assert.False(t, summary.DryRun)
+	assert.Equal(t, 2, summary.Deleted)
+	assert.Equal(t, []string{"1", "2"}, summary.IDs)
+	assert.Empty(t, summary.ConfirmationToken)
+	assert.Len(t, repo.products, 1)
+	assert.Contains(t, repo.products, "3")
+	assert.Contains(t, tombstones.tombstones, "1")
+	assert.Contains(t, tombstones.tombstones, "2")
+	require.Len(t, repo.outbox, 2)
+	assert.Equal(t, domain.EventProductDeleted, repo.outbox[0].Type)
+	require.Len(t, auditLog.entries, 2)
+	assert.Equal(t, domain.AuditDelete, auditLog.entries[0].Action)
+}
+
+func TestBulkDeleteService_RequiresMatchingConfirmation(t *testing.T) {
+	repo := newFakeProductRepository()
+	repo.products["1"] = domain.Product{ID: "1", Version: 1}
+	service := newTestBulkDeleteService(repo, &fakeStreamer{}, &fakeTombstoneRepository{tombstones: map[string]domain.Tombstone{}}, &fakeAuditLog{})
+	selection := ports.BulkDeleteSelection{IDs: []string{"1"}}
+
+	_, err := service.BulkDelete(context.Background(), selection, false, "")
+	assert.ErrorIs(t, err, domain.ErrConfirmationRequired)
+
+	preview, err := service.BulkDelete(context.Background(), selection, true, "")
+	require.NoError(t, err)
+
+	// An update after the preview changes what would be deleted
+	product := repo.products["1"]
+	product.Version++
+	repo.products["1"] = product
+	_, err = service.BulkDelete(context.Background(), selection, false, preview.ConfirmationToken)
+	assert.ErrorIs(t, err, domain.ErrBulkDeleteChanged)
+	assert.Contains(t, repo.products, "1")
+}
+
+func TestBulkDeleteService_ByFilter(t *testing.T) {
+	streamer := &fakeStreamer{}
+	for i := 0; i < domain.MaxBulkDelete+1; i++ {
+		streamer.products = append(streamer.products, domain.Product{ID: fmt.Sprintf("%04d", i)})
+	}
+	service := newTestBulkDeleteService(newFakeProductRepository(), streamer, &fakeTombstoneRepository{tombstones: map[string]domain.Tombstone{}}, &fakeAuditLog{})
+	filters := ports.ProductFilters{CategoryID: "electronics"}
+
+	_, err := service.BulkDelete(context.Background(), ports.BulkDeleteSelection{Filters: &filters}, true, "")
+	assert.ErrorIs(t, err, domain.ErrBulkDeleteTooLarge)
+	assert.Equal(t, "electronics", streamer.filters.CategoryID)
+
+	streamer.products = streamer.products[:3]
+	preview, err := service.BulkDelete(context.Background(), ports.BulkDeleteSelection{Filters: &filters}, true, "")
+	require.NoError(t, err)
+	assert.Equal(t, 3, preview.Matched)
+	assert.Nil(t, preview.Missing)
+}
+
+// changingDeleter updates the products in changed right before deleting,
+// as a concurrent edit between the selection and the batch would
+type changingDeleter struct {
+	*fakeProductRepository
+	changed []string
+}
+
+func (d changingDeleter) DeleteBatch(ctx context.Context, products []domain.Product) ([]string, error) {
+	for _, id := range d.changed {
+		product := d.products[id]
+		product.Version++
+		d.products[id] = product
+	}
+	return d.fakeProductRepository.DeleteBatch(ctx, products)
+}
+
+func TestBulkDeleteService_KeepsChangedProducts(t *testing.T) {
+	repo := newFakeProductRepository()
+	repo.products["1"] = domain.Product{ID: "1", Version: 1}
+	repo.products["2"] = domain.Product{ID: "2", Version: 1}
+	tombstones := &fakeTombstoneRepository{tombstones: map[string]domain.Tombstone{}}
+	service := NewBulkDeleteService(repo, &fakeStreamer{}, changingDeleter{repo, []string{"2"}}, nil, tombstones, &fakeAuditLog{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	selection := ports.BulkDeleteSelection{IDs: []string{"1", "2"}}
+
+	preview, err := service.BulkDelete(context.Background(), selection, true, "")
+	require.NoError(t, err)
+	summary, err := service.BulkDelete(context.Background(), selection, false, preview.ConfirmationToken)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"1"}, summary.IDs)
+	assert.Equal(t, []string{"2"}, summary.Skipped)
+	assert.Contains(t, repo.products, "2")
+	assert.Contains(t, tombstones.tombstones, "1")
+	assert.NotContains(t, tombstones.tombstones, "2", "the kept product has no tombstone")
+}
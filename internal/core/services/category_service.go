@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"sort"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+type categoryService struct {
+	repo   ports.CategoryRepository
+	usage  ports.CategoryUsage
+	logger *slog.Logger
+}
+
+func NewCategoryService(repo ports.CategoryRepository, usage ports.CategoryUsage, logger *slog.Logger) ports.CategoryService {
+	return &categoryService{
+		repo:   repo,
+		usage:  usage,
+		logger: logger,
+	}
+}
+
+func (s *categoryService) Create(ctx context.Context, input ports.CategoryInput) (domain.Category, error) {
+	category, err := domain.NewCategory(input.Name, input.Description)
+	if err != nil {
+		return domain.Category{}, err
+	}
+	if err := s.repo.Save(ctx, *category); err != nil {
+		s.logger.ErrorContext(ctx, "failed to save category", "error", err)
+		return domain.Category{}, err
+	}
+	return *category, nil
+}
+
+func (s *categoryService) Get(ctx context.Context, id string) (domain.Category, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *categoryService) Update(ctx context.Context, id string, input ports.CategoryInput) (domain.Category, error) {
+	category, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return domain.Category{}, err
+	}
+	if err := category.Rename(input.Name, input.Description); err != nil {
+		return domain.Category{}, err
+	}
+	if err := s.repo.Save(ctx, category); err != nil {
+		s.logger.ErrorContext(ctx, "failed to update category", "id", id, "error", err)
+		return domain.Category{}, err
+	}
+	return category, nil
+}
+
+// Delete trusts a positive product count without scanning. A zero count
+// is confirmed by a scan, since expired products are only discounted by
+// a recount.
+func (s *categoryService) Delete(ctx context.Context, id string) error {
+	category, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if category.ProductCount > 0 {
+		return domain.ErrCategoryInUse
+	}
+	inUse, err := s.usage.HasProductsInCategory(ctx, id)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to check category usage", "id", id, "error", err)
+		return err
+	}
+	if inUse {
+		return domain.ErrCategoryInUse
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		s.logger.ErrorContext(ctx, "failed to delete category", "id", id, "error", err)
+		return err
+	}
+	s.logger.InfoContext(ctx, "category deleted", "id", id)
+	return nil
+}
+
+// List returns every category ordered by name
+func (s *categoryService) List(ctx context.Context) ([]domain.Category, error) {
+	categories, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].Name < categories[j].Name })
+	return categories, nil
+}
+
+// transact runs fn in uow, or directly when there is none
+func transact(ctx context.Context, uow ports.UnitOfWork, fn func(ctx context.Context) error) error {
+	if uow == nil {
+		return fn(ctx)
+	}
+	return uow.Do(ctx, fn)
+}
+
+// moveCategoryCount moves a product from one category's count to
+// another's; an empty ID stands for no category
+func moveCategoryCount(ctx context.Context, counts ports.CategoryCounter, from, to string) error {
+	if counts == nil || from == to {
+		return nil
+	}
+	if from != "" {
+		if err := counts.AdjustProductCount(ctx, from, -1); err != nil {
+			return err
+		}
+	}
+	if to != "" {
+		return counts.AdjustProductCount(ctx, to, 1)
+	}
+	return nil
+}
+
+// adjustCategoryCounts adds delta to the count of each category for every
+// one of products in it. It follows batch writes, which are not atomic, so
+// like the audit a failure is logged rather than returned.
+func adjustCategoryCounts(ctx context.Context, counts ports.CategoryCounter, logger *slog.Logger, products []domain.Product, delta int) {
+	if counts == nil {
+		return
+	}
+	perCategory := map[string]int{}
+	for _, product := range products {
+		if product.CategoryID != "" {
+			perCategory[product.CategoryID] += delta
+		}
+	}
+	for categoryID, adjustment := range perCategory {
+		if err := counts.AdjustProductCount(ctx, categoryID, adjustment); err != nil {
+			logger.ErrorContext(ctx, "failed to adjust category product count", "category_id", categoryID, "delta", adjustment, "error", err)
+		}
+	}
+}
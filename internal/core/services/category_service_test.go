@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// fakeCategoryRepository keeps categories in memory
+type fakeCategoryRepository struct {
+	ports.CategoryRepository
+	categories map[string]domain.Category
+}
+
+func (f *fakeCategoryRepository) GetByID(ctx context.Context, id string) (domain.Category, error) {
+	category, ok := f.categories[id]
+	if !ok {
+		return domain.Category{}, domain.ErrCategoryNotFound
+	}
+	return category, nil
+}
+
+func (f *fakeCategoryRepository) Delete(ctx context.Context, id string) error {
+	delete(f.categories, id)
+	return nil
+}
+
+// scanningUsage reports the categories in use and counts its scans
+type scanningUsage struct {
+	inUse map[string]bool
+	scans int
+}
+
+func (u *scanningUsage) HasProductsInCategory(ctx context.Context, categoryID string) (bool, error) {
+	u.scans++
+	return u.inUse[categoryID], nil
+}
+
+func TestCategoryService_Delete(t *testing.T) {
+	repo := &fakeCategoryRepository{categories: map[string]domain.Category{
+		"counted": {ID: "counted", ProductCount: 3},
+		"stale":   {ID: "stale"},
+		"empty":   {ID: "empty"},
+	}}
+	usage := &scanningUsage{inUse: map[string]bool{"stale": true}}
+	service := NewCategoryService(repo, usage, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	assert.ErrorIs(t, service.Delete(ctx, "counted"), domain.ErrCategoryInUse)
+	assert.Zero(t, usage.scans, "a positive count needs no scan")
+
+	assert.ErrorIs(t, service.Delete(ctx, "stale"), domain.ErrCategoryInUse, "a zero count is confirmed by a scan")
+	assert.NoError(t, service.Delete(ctx, "empty"))
+	assert.NotContains(t, repo.categories, "empty")
+	assert.ErrorIs(t, service.Delete(ctx, "unknown"), domain.ErrCategoryNotFound)
+}
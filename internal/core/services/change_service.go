@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+// changeSettleDelay holds back the most recent changes: the updated_at and
+// deleted_at indexes are updated asynchronously, so a change is only read
+// once every change made before it has had time to show up there too.
+// Without it a checkpoint could move past a change that was not indexed
+// yet, and the consumer would never be sent it.
+const changeSettleDelay = 5 * time.Second
+
+// changeFields are the only product attributes the change feed reads:
+// the times and what decides whether the catalog lists the product
+var changeFields = []string{"id", "created_at", "updated_at", "expires_at", "status", "publish_at", "auto_archive_at", "moderation_status"}
+
+type changeService struct {
+	products   ports.ProductRepository
+	tombstones ports.TombstoneLister
+	logger     *slog.Logger
+	now        func() time.Time
+}
+
+func NewChangeService(products ports.ProductRepository, tombstones ports.TombstoneLister, logger *slog.Logger) ports.ChangeService {
+	return &changeService{
+		products:   products,
+		tombstones: tombstones,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// Changes reads the products updated after the checkpoint from the
+// updated_at index and the deletions from the tombstones. The index is
+// read whatever the status of the products, so one the catalog no longer
+// lists is reported as hidden rather than left out. Of the others, a
+// product whose creation is no older than the checkpoint is reported as
+// created, the rest as updated. The two are read independently, so each advances its
+// half of the checkpoint on its own.
+func (s *changeService) Changes(ctx context.Context, from ports.ChangeCheckpoint, limit int) (domain.ProductChanges, ports.ChangeCheckpoint, error) {
+	until := s.now().Add(-changeSettleDelay)
+	since, err := time.Parse(time.RFC3339Nano, from.Updated.Value)
+	if err != nil {
+		return domain.ProductChanges{}, from, domain.NewError(domain.KindValidation, "invalid change checkpoint")
+	}
+
+	changes := domain.ProductChanges{Created: []string{}, Updated: []string{}, Hidden: []string{}, Deleted: []string{}}
+	if since.After(until) {
+		// A checkpoint taken moments ago has nothing settled after it yet
+		return changes, from, nil
+	}
+	next := from
+	// The time bounds narrow the index read; the position skips the
+	// products already sent at the checkpoint's time
+	result, err := s.products.ListWithFilters(ctx, ports.ProductFilters{
+		UpdatedAfter:  since,
+		UpdatedBefore: until,
+		After:         &from.Updated,
+		SortBy:        "updated_at",
+		SortOrder:     "asc",
+		Limit:         limit,
+		Fields:        changeFields,
+		IncludeHidden: true,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to read product updates", "error", err)
+		return domain.ProductChanges{}, from, err
+	}
+	now := s.now()
+	for _, product := range result.Products {
+		if !product.IsLiveAt(now) || !product.IsModerationApproved() || product.IsExpired(now) {
+			changes.Hidden = append(changes.Hidden, product.ID)
+		} else if product.CreatedAt.Before(since) {
+			changes.Updated = append(changes.Updated, product.ID)
+		} else {
+			changes.Created = append(changes.Created, product.ID)
+		}
+		next.Updated = ports.ProductSortKey(product, "updated_at")
+	}
+
+	tombstones, err := s.tombstones.ListDeleted(ctx, from.Deleted, until, limit)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to read product deletions", "error", err)
+		return domain.ProductChanges{}, from, err
+	}
+	for _, tombstone := range tombstones {
+		changes.Deleted = append(changes.Deleted, tombstone.ID)
+		next.Deleted = ports.TombstoneSortKey(tombstone)
+	}
+
+	changes.HasMore = len(result.Products) == limit || len(tombstones) == limit
+	s.logger.DebugContext(ctx, "product changes read", "created", len(changes.Created), "updated", len(changes.Updated), "hidden", len(changes.Hidden), "deleted", len(changes.Deleted))
+	return changes, next, nil
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports/repotest"
+)
+
+// ListDeleted orders the tombstones like the deletion index of the
+// repository
+func (f *fakeTombstoneRepository) ListDeleted(ctx context.Context, after ports.SortKey, until time.Time, limit int) ([]domain.Tombstone, error) {
+	from, err := time.Parse(time.RFC3339Nano, after.Value)
+	if err != nil {
+		return nil, err
+	}
+	var tombstones []domain.Tombstone
+	for _, tombstone := range f.tombstones {
+		if tombstone.DeletedAt.After(until) || tombstone.DeletedAt.Before(from) || (tombstone.DeletedAt.Equal(from) && tombstone.ID <= after.ID) {
+			continue
+		}
+		tombstones = append(tombstones, tombstone)
+	}
+	slices.SortFunc(tombstones, func(a, b domain.Tombstone) int {
+		if c := a.DeletedAt.Compare(b.DeletedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return tombstones[:min(limit, len(tombstones))], nil
+}
+
+func TestChangeService_Changes(t *testing.T) {
+	since := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	products := repotest.NewMemoryRepository()
+	for _, product := range []domain.Product{
+		{ID: "old", CreatedAt: since.Add(-time.Hour), UpdatedAt: since.Add(-time.Minute)},
+		{ID: "edited", CreatedAt: since.Add(-time.Hour), UpdatedAt: since},
+		{ID: "new", CreatedAt: since.Add(time.Minute), UpdatedAt: since.Add(2 * time.Minute)},
+		{ID: "fresh", CreatedAt: since.Add(3 * time.Minute), UpdatedAt: since.Add(3 * time.Minute)},
+		// No longer listed, so reported as hidden
+		{ID: "unpublished", Status: domain.StatusDraft, CreatedAt: since.Add(-time.Hour), UpdatedAt: since.Add(4 * time.Minute)},
+		{ID: "rejected", ModerationStatus: domain.ModerationRejected, CreatedAt: since.Add(-time.Hour), UpdatedAt: since.Add(5 * time.Minute)},
+		// Not settled yet, so held back
+		{ID: "latest", CreatedAt: since.Add(time.Hour), UpdatedAt: since.Add(time.Hour)},
+	} {
+		require.NoError(t, products.Save(context.Background(), product))
+	}
+	tombstones := &fakeTombstoneRepository{tombstones: map[string]domain.Tombstone{
+		"gone":   {ID: "gone", DeletedAt: since.Add(time.Minute)},
+		"before": {ID: "before", DeletedAt: since.Add(-time.Minute)},
+	}}
+	service := NewChangeService(products, tombstones, slog.New(slog.NewTextHandler(io.Discard, nil))).(*changeService)
+	service.now = func() time.Time { return since.Add(time.Hour) }
+
+	changes, next, err := service.Changes(context.Background(), ports.CheckpointAt(since), 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"new"}, changes.Created)
+	assert.Equal(t, []string{"edited"}, changes.Updated, "changes at the checkpoint time are included")
+	assert.Equal(t, []string{"gone"}, changes.Deleted)
+	assert.True(t, changes.HasMore)
+
+	changes, next, err = service.Changes(context.Background(), next, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"fresh"}, changes.Created)
+	assert.Empty(t, changes.Updated)
+	assert.Equal(t, []string{"unpublished"}, changes.Hidden)
+	assert.Empty(t, changes.Deleted)
+	assert.True(t, changes.HasMore)
+
+	changes, next, err = service.Changes(context.Background(), next, 2)
+	require.NoError(t, err)
+	assert.Empty(t, changes.Created)
+	assert.Equal(t, []string{"rejected"}, changes.Hidden)
+	assert.False(t, changes.HasMore)
+
+	// Nothing new: the checkpoint stays where it was
+	changes, again, err := service.Changes(context.Background(), next, 2)
+	require.NoError(t, err)
+	assert.Empty(t, changes.Created)
+	assert.Equal(t, next, again)
+}
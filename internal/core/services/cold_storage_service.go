@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+// coldStorageBatchSize caps the products moved per run, each run writing
+// a single object
+const coldStorageBatchSize = 500
+
+type coldStorageService struct {
+	repo       ports.ProductRepository
+	archived   ports.ColdStorageRepository
+	storage    ports.ColdStorage
+	tombstones ports.TombstoneRepository
+	counts     ports.CategoryCounter
+	uow        ports.UnitOfWork
+	after      time.Duration
+	logger     *slog.Logger
+	now        func() time.Time
+}
+
+// NewColdStorageService moves products archived for longer than afterDays
+func NewColdStorageService(repo ports.ProductRepository, archived ports.ColdStorageRepository, storage ports.ColdStorage, tombstones ports.TombstoneRepository, counts ports.CategoryCounter, uow ports.UnitOfWork, afterDays int, logger *slog.Logger) ports.ColdStorageService {
+	return &coldStorageService{
+		repo:       repo,
+		archived:   archived,
+		storage:    storage,
+		tombstones: tombstones,
+		counts:     counts,
+		uow:        uow,
+		after:      time.Duration(afterDays) * 24 * time.Hour,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// MoveArchived writes the archived products last updated before the cutoff
+// to cold storage, then deletes them from the table. Each deleted product
+// leaves a tombstone pointing at its object, so reading it answers 410
+// until it is restored. A product changed after it was listed is kept in
+// the table; its copy in the object is never pointed at.
+func (s *coldStorageService) MoveArchived(ctx context.Context) error {
+	now := s.now().UTC()
+	products, err := s.archived.ListArchivedBefore(ctx, now.Add(-s.after), coldStorageBatchSize)
+	if err != nil || len(products) == 0 {
+		return err
+	}
+	key, err := s.storage.Store(ctx, products)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	moved := 0
+	for _, product := range products {
+		tenantCtx := ports.WithTenant(ctx, product.TenantID)
+		tombstone := domain.Tombstone{ID: product.ID, DeletedAt: now, TenantID: product.TenantID, ArchiveKey: key}
+		if err := s.tombstones.Save(tenantCtx, tombstone); err != nil {
+			s.logger.ErrorContext(ctx, "failed to save tombstone", "id", product.ID, "error", err)
+			errs = append(errs, err)
+			continue
+		}
+
+		event := domain.NewProductEvent(domain.EventProductDeleted, product.ID, nil, now)
+		err := transact(tenantCtx, s.uow, func(ctx context.Context) error {
+			if err := s.repo.Delete(ports.WithOutboxEvent(ctx, event), product.ID, product.Version); err != nil {
+				return err
+			}
+			return moveCategoryCount(ctx, s.counts, product.CategoryID, "")
+		})
+		if err != nil {
+			if err := s.tombstones.Delete(tenantCtx, product.ID); err != nil {
+				s.logger.ErrorContext(ctx, "failed to remove tombstone of product kept in the table", "id", product.ID, "error", err)
+			}
+			// The product was edited or deleted since it was listed
+			if errors.Is(err, domain.ErrConflict) || errors.Is(err, domain.ErrNotFound) {
+				s.logger.DebugContext(ctx, "product changed before cold storage, skipping", "id", product.ID)
+				continue
+			}
+			s.logger.ErrorContext(ctx, "failed to delete product moved to cold storage", "id", product.ID, "error", err)
+			errs = append(errs, err)
+			continue
+		}
+		moved++
+	}
+
+	s.logger.InfoContext(ctx, "archived products moved to cold storage", "key", key, "moved", moved)
+	return errors.Join(errs...)
+}
+
+// Restore puts a product moved to cold storage back in the table as it was
+// when moved, still archived
+func (s *coldStorageService) Restore(ctx context.Context, id string) (domain.Product, error) {
+	tombstone, err := s.tombstones.Get(ctx, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.Product{}, domain.ErrNotInColdStorage
+	}
+	if err != nil {
+		return domain.Product{}, err
+	}
+	if tombstone.ArchiveKey == "" || tombstone.TenantID != ports.TenantID(ctx) {
+		return domain.Product{}, domain.ErrNotInColdStorage
+	}
+
+	product, err := s.storage.Load(ctx, tombstone.ArchiveKey, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		s.logger.ErrorContext(ctx, "product missing from its cold storage object", "id", id, "key", tombstone.ArchiveKey)
+		return domain.Product{}, domain.ErrNotInColdStorage
+	}
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to load product from cold storage", "id", id, "error", err)
+		return domain.Product{}, err
+	}
+
+	restored := product
+	event := domain.NewProductEvent(domain.EventProductCreated, id, &restored, s.now().UTC())
+	err = transact(ctx, s.uow, func(ctx context.Context) error {
+		if err := s.repo.Save(ports.WithOutboxEvent(ctx, event), product); err != nil {
+			return err
+		}
+		return moveCategoryCount(ctx, s.counts, "", product.CategoryID)
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to restore product", "id", id, "error", err)
+		return domain.Product{}, err
+	}
+	// The product is readable again either way; a tombstone left behind
+	// only makes a later restore answer a duplicate
+	if err := s.tombstones.Delete(ctx, id); err != nil {
+		s.logger.ErrorContext(ctx, "failed to remove tombstone of restored product", "id", id, "error", err)
+	}
+
+	s.logger.InfoContext(ctx, "product restored from cold storage", "id", id, "key", tombstone.ArchiveKey)
+	return product, nil
+}
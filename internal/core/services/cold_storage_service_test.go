@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// fakeColdStorageRepository lists the archived products of the fake
+// product repository, checking the cutoff like the scan filter
+type fakeColdStorageRepository struct {
+	repo   *fakeProductRepository
+	before time.Time
+}
+
+func (f *fakeColdStorageRepository) ListArchivedBefore(ctx context.Context, before time.Time, limit int) ([]domain.Product, error) {
+	f.before = before
+	var products []domain.Product
+	for _, product := range f.repo.products {
+		if product.Status == domain.StatusArchived && product.UpdatedAt.Before(before) && len(products) < limit {
+			products = append(products, product)
+		}
+	}
+	return products, nil
+}
+
+// fakeColdStorage keeps each stored batch under a numbered key
+type fakeColdStorage struct {
+	objects map[string][]domain.Product
+}
+
+func (f *fakeColdStorage) Store(ctx context.Context, products []domain.Product) (string, error) {
+	key := fmt.Sprintf("batch-%d", len(f.objects)+1)
+	f.objects[key] = products
+	return key, nil
+}
+
+func (f *fakeColdStorage) Load(ctx context.Context, key, id string) (domain.Product, error) {
+	for _, product := range f.objects[key] {
+		if product.ID == id {
+			return product, nil
+		}
+	}
+	return domain.Product{}, domain.ErrNotFound
+}
+
+func TestColdStorageService_MoveAndRestore(t *testing.T) {
+	now := time.Date(2024, 8, 1, 12, 0, 0, 0, time.UTC)
+	repo := newFakeProductRepository()
+	repo.products["old"] = domain.Product{ID: "old", Status: domain.StatusArchived, UpdatedAt: now.AddDate(0, 0, -91), Version: 4, TenantID: "acme", CategoryID: "kitchen"}
+	repo.products["recent"] = domain.Product{ID: "recent", Status: domain.StatusArchived, UpdatedAt: now.AddDate(0, 0, -10), Version: 2}
+	repo.products["live"] = domain.Product{ID: "live", Status: domain.StatusPublished, UpdatedAt: now.AddDate(0, 0, -200), Version: 1}
+	archived := &fakeColdStorageRepository{repo: repo}
+	coldStorage := &fakeColdStorage{objects: map[string][]domain.Product{}}
+	tombstones := &fakeTombstoneRepository{tombstones: map[string]domain.Tombstone{}}
+	counts := &fakeCategoryCounts{counts: map[string]int{"kitchen": 1}}
+	service := NewColdStorageService(repo, archived, coldStorage, tombstones, counts, snapshotUnitOfWork{repo}, 90, slog.New(slog.NewTextHandler(io.Discard, nil))).(*coldStorageService)
+	service.now = func() time.Time { return now }
+
+	require.NoError(t, service.MoveArchived(context.Background()))
+	assert.Equal(t, now.AddDate(0, 0, -90), archived.before)
+	assert.NotContains(t, repo.products, "old")
+	assert.Contains(t, repo.products, "recent")
+	assert.Contains(t, repo.products, "live")
+	require.Contains(t, tombstones.tombstones, "old")
+	assert.Equal(t, "batch-1", tombstones.tombstones["old"].ArchiveKey)
+	assert.Equal(t, "acme", tombstones.tombstones["old"].TenantID)
+	require.Len(t, repo.outbox, 1)
+	assert.Equal(t, domain.EventProductDeleted, repo.outbox[0].Type)
+	assert.Equal(t, 0, counts.counts["kitchen"], "products in cold storage leave their category's count")
+
+	// Products are restored within their own tenant only
+	_, err := service.Restore(context.Background(), "old")
+	assert.ErrorIs(t, err, domain.ErrNotInColdStorage)
+
+	ctx := ports.WithTenant(context.Background(), "acme")
+	restored, err := service.Restore(ctx, "old")
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusArchived, restored.Status)
+	assert.Equal(t, int64(4), restored.Version)
+	assert.Contains(t, repo.products, "old")
+	assert.NotContains(t, tombstones.tombstones, "old")
+	require.Len(t, repo.outbox, 2)
+	assert.Equal(t, domain.EventProductCreated, repo.outbox[1].Type)
+	assert.Equal(t, 1, counts.counts["kitchen"])
+
+	_, err = service.Restore(ctx, "old")
+	assert.ErrorIs(t, err, domain.ErrNotInColdStorage)
+}
+
+func TestColdStorageService_RestoreIgnoresDeletedProducts(t *testing.T) {
+	tombstones := &fakeTombstoneRepository{tombstones: map[string]domain.Tombstone{
+		"deleted": {ID: "deleted"},
+	}}
+	service := NewColdStorageService(newFakeProductRepository(), nil, &fakeColdStorage{}, tombstones, nil, nil, 90, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	_, err := service.Restore(context.Background(), "deleted")
+	assert.ErrorIs(t, err, domain.ErrNotInColdStorage)
+	_, err = service.Restore(context.Background(), "unknown")
+	assert.ErrorIs(t, err, domain.ErrNotInColdStorage)
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+type currencyService struct {
+	rates  ports.ExchangeRates
+	logger *slog.Logger
+}
+
+func NewCurrencyService(rates ports.ExchangeRates, logger *slog.Logger) ports.CurrencyService {
+	return &currencyService{
+		rates:  rates,
+		logger: logger,
+	}
+}
+
+// Convert returns price in currency to. Converted amounts are for display
+// only; products keep the price they were given.
+func (s *currencyService) Convert(ctx context.Context, price domain.Money, to string) (domain.Money, error) {
+	to = strings.ToUpper(to)
+	if _, ok := domain.CurrencyExponent(to); !ok {
+		return domain.Money{}, domain.ErrUnsupportedCurrency
+	}
+	if price.Currency == to {
+		return price, nil
+	}
+
+	rate, err := s.rates.Rate(ctx, price.Currency, to)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNoExchangeRate) {
+			s.logger.ErrorContext(ctx, "failed to get exchange rate", "from", price.Currency, "to", to, "error", err)
+		}
+		return domain.Money{}, err
+	}
+	return price.Convert(to, rate)
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+type exportService struct {
+	products ports.ProductStreamer
+	logger   *slog.Logger
+}
+
+func NewExportService(products ports.ProductStreamer, logger *slog.Logger) ports.ExportService {
+	return &exportService{
+		products: products,
+		logger:   logger,
+	}
+}
+
+func (s *exportService) Export(ctx context.Context, filters ports.ProductFilters, fn func(domain.Product) error) error {
+	exported := 0
+	err := s.products.StreamProducts(ctx, filters, func(page []domain.Product) error {
+		for _, product := range page {
+			if err := fn(product); err != nil {
+				return err
+			}
+			exported++
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "product export failed", "exported", exported, "error", err)
+		return err
+	}
+
+	s.logger.InfoContext(ctx, "products exported", "count", exported)
+	return nil
+}
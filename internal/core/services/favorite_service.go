@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+type favoriteService struct {
+	favorites ports.FavoriteRepository
+	products  ports.ProductRepository
+	logger    *slog.Logger
+}
+
+func NewFavoriteService(favorites ports.FavoriteRepository, products ports.ProductRepository, logger *slog.Logger) ports.FavoriteService {
+	return &favoriteService{
+		favorites: favorites,
+		products:  products,
+		logger:    logger,
+	}
+}
+
+// Add puts a product on the caller's wish list. Adding a favorite twice
+// changes nothing.
+func (s *favoriteService) Add(ctx context.Context, productID string) (domain.Favorite, error) {
+	userID := ports.Actor(ctx)
+	if userID == "" {
+		return domain.Favorite{}, domain.ErrNoUser
+	}
+	if _, err := s.products.GetByID(ctx, productID); err != nil {
+		return domain.Favorite{}, err
+	}
+
+	favorite := domain.Favorite{
+		UserID:    userID,
+		ProductID: productID,
+		TenantID:  ports.TenantID(ctx),
+		CreatedAt: time.Now().UTC(),
+	}
+	added, err := s.favorites.Add(ctx, favorite)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			s.logger.ErrorContext(ctx, "failed to add favorite", "product_id", productID, "error", err)
+		}
+		return domain.Favorite{}, err
+	}
+	if added {
+		s.logger.InfoContext(ctx, "favorite added", "product_id", productID, "user_id", userID)
+	}
+	return favorite, nil
+}
+
+// Remove takes a product off the caller's wish list, if it was on it
+func (s *favoriteService) Remove(ctx context.Context, productID string) error {
+	userID := ports.Actor(ctx)
+	if userID == "" {
+		return domain.ErrNoUser
+	}
+	removed, err := s.favorites.Remove(ctx, userID, productID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to remove favorite", "product_id", productID, "error", err)
+		return err
+	}
+	if removed {
+		s.logger.InfoContext(ctx, "favorite removed", "product_id", productID, "user_id", userID)
+	}
+	return nil
+}
+
+// List returns a page of the caller's favorites with their products.
+// Favorites of products that are gone or no longer visible are left out
+// of the page but still move the cursor.
+func (s *favoriteService) List(ctx context.Context, after string, limit int) (ports.FavoritePage, error) {
+	userID := ports.Actor(ctx)
+	if userID == "" {
+		return ports.FavoritePage{}, domain.ErrNoUser
+	}
+	favorites, err := s.favorites.List(ctx, userID, after, limit)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list favorites", "error", err)
+		return ports.FavoritePage{}, err
+	}
+
+	page := ports.FavoritePage{Favorites: []ports.FavoriteProduct{}}
+	for _, favorite := range favorites {
+		product, err := s.products.GetByID(ctx, favorite.ProductID)
+		if errors.Is(err, domain.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			s.logger.ErrorContext(ctx, "failed to load favorite product", "product_id", favorite.ProductID, "error", err)
+			return ports.FavoritePage{}, err
+		}
+		page.Favorites = append(page.Favorites, ports.FavoriteProduct{Product: product, FavoritedAt: favorite.CreatedAt})
+	}
+	if len(favorites) == limit {
+		page.NextAfter = favorites[len(favorites)-1].ProductID
+	}
+	return page, nil
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// fakeFavoriteRepository keeps the favorite counts on the products of a
+// fakeProductRepository, as the DynamoDB repository does when counts are
+// enabled
+type fakeFavoriteRepository struct {
+	products  *fakeProductRepository
+	favorites map[string]domain.Favorite
+}
+
+func newFakeFavoriteRepository(products *fakeProductRepository) *fakeFavoriteRepository {
+	return &fakeFavoriteRepository{products: products, favorites: map[string]domain.Favorite{}}
+}
+
+func (f *fakeFavoriteRepository) adjust(productID string, delta int64) {
+	if product, ok := f.products.products[productID]; ok {
+		product.FavoriteCount += delta
+		f.products.products[productID] = product
+	}
+}
+
+func (f *fakeFavoriteRepository) Add(ctx context.Context, favorite domain.Favorite) (bool, error) {
+	key := favorite.UserID + "/" + favorite.ProductID
+	if _, ok := f.favorites[key]; ok {
+		return false, nil
+	}
+	f.favorites[key] = favorite
+	f.adjust(favorite.ProductID, 1)
+	return true, nil
+}
+
+func (f *fakeFavoriteRepository) Remove(ctx context.Context, userID, productID string) (bool, error) {
+	key := userID + "/" + productID
+	if _, ok := f.favorites[key]; !ok {
+		return false, nil
+	}
+	delete(f.favorites, key)
+	f.adjust(productID, -1)
+	return true, nil
+}
+
+func (f *fakeFavoriteRepository) List(ctx context.Context, userID, after string, limit int) ([]domain.Favorite, error) {
+	favorites := []domain.Favorite{}
+	for _, favorite := range f.favorites {
+		if favorite.UserID == userID && favorite.TenantID == ports.TenantID(ctx) && favorite.ProductID > after {
+			favorites = append(favorites, favorite)
+		}
+	}
+	sort.Slice(favorites, func(i, j int) bool { return favorites[i].ProductID < favorites[j].ProductID })
+	if len(favorites) > limit {
+		favorites = favorites[:limit]
+	}
+	return favorites, nil
+}
+
+func TestFavoriteService(t *testing.T) {
+	products := newFakeProductRepository()
+	products.products["p1"] = domain.Product{ID: "p1", Name: "Laptop"}
+	products.products["p2"] = domain.Product{ID: "p2", Name: "Mouse"}
+	favorites := newFakeFavoriteRepository(products)
+	service := NewFavoriteService(favorites, products, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	alice := ports.WithActor(context.Background(), "alice")
+	bob := ports.WithActor(context.Background(), "bob")
+
+	favorite, err := service.Add(alice, "p1")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", favorite.UserID)
+	_, err = service.Add(alice, "p1")
+	require.NoError(t, err)
+	_, err = service.Add(alice, "p2")
+	require.NoError(t, err)
+	_, err = service.Add(bob, "p1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), products.products["p1"].FavoriteCount)
+
+	page, err := service.List(alice, "", 1)
+	require.NoError(t, err)
+	require.Len(t, page.Favorites, 1)
+	assert.Equal(t, "p1", page.Favorites[0].Product.ID)
+	assert.Equal(t, "p1", page.NextAfter)
+	page, err = service.List(alice, page.NextAfter, 1)
+	require.NoError(t, err)
+	require.Len(t, page.Favorites, 1)
+	assert.Equal(t, "p2", page.Favorites[0].Product.ID)
+
+	// Favorites of deleted products are skipped
+	delete(products.products, "p2")
+	page, err = service.List(alice, "", 10)
+	require.NoError(t, err)
+	require.Len(t, page.Favorites, 1)
+	assert.Empty(t, page.NextAfter)
+
+	require.NoError(t, service.Remove(alice, "p1"))
+	require.NoError(t, service.Remove(alice, "p1"))
+	assert.Equal(t, int64(1), products.products["p1"].FavoriteCount)
+}
+
+func TestFavoriteService_Rejects(t *testing.T) {
+	products := newFakeProductRepository()
+	products.products["p1"] = domain.Product{ID: "p1", Name: "Laptop"}
+	service := NewFavoriteService(newFakeFavoriteRepository(products), products, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	_, err := service.Add(ctx, "p1")
+	assert.ErrorIs(t, err, domain.ErrNoUser)
+	assert.ErrorIs(t, service.Remove(ctx, "p1"), domain.ErrNoUser)
+	_, err = service.List(ctx, "", 10)
+	assert.ErrorIs(t, err, domain.ErrNoUser)
+
+	alice := ports.WithActor(ctx, "alice")
+	_, err = service.Add(alice, "missing")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = service.Add(ports.WithTenant(alice, "other"), "p1")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
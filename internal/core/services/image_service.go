@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+type imageService struct {
+	repo     ports.ProductRepository
+	storage  ports.BlobStorage
+	auditLog ports.AuditLogger
+	logger   *slog.Logger
+}
+
+func NewImageService(repo ports.ProductRepository, storage ports.BlobStorage, auditLog ports.AuditLogger, logger *slog.Logger) ports.ImageService {
+	return &imageService{
+		repo:     repo,
+		storage:  storage,
+		auditLog: auditLog,
+		logger:   logger,
+	}
+}
+
+// AddImage stores the image metadata before the client uploads it, so the
+// image is listed as soon as the upload URL is handed out
+func (s *imageService) AddImage(ctx context.Context, productID, contentType string) (domain.ProductImage, ports.PresignedUpload, error) {
+	extension, err := domain.ImageExtension(contentType)
+	if err != nil {
+		return domain.ProductImage{}, ports.PresignedUpload{}, err
+	}
+	product, err := s.repo.GetByID(ports.WithConsistentRead(ctx), productID)
+	if err != nil {
+		return domain.ProductImage{}, ports.PresignedUpload{}, err
+	}
+	before := product
+
+	now := time.Now().UTC()
+	key := imageKey(product, uuid.New().String()+extension)
+	image, err := product.AddImage(key, s.storage.URL(key), contentType, now)
+	if err != nil {
+		s.logger.WarnContext(ctx, "product image rejected", "id", productID, "error", err)
+		return domain.ProductImage{}, ports.PresignedUpload{}, err
+	}
+	upload, err := s.storage.PresignUpload(ctx, key, contentType)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to presign image upload", "id", productID, "key", key, "error", err)
+		return domain.ProductImage{}, ports.PresignedUpload{}, err
+	}
+
+	updated := product
+	updated.Version++
+	event := domain.NewProductEvent(domain.EventProductUpdated, productID, &updated, now)
+	if err := s.repo.Update(ports.WithOutboxEvent(ctx, event), product); err != nil {
+		if !errors.Is(err, domain.ErrConflict) {
+			s.logger.ErrorContext(ctx, "failed to save product image", "id", productID, "error", err)
+		}
+		return domain.ProductImage{}, ports.PresignedUpload{}, err
+	}
+	recordAudit(ctx, s.auditLog, s.logger, domain.AuditUpdate, &before, &updated, now)
+
+	s.logger.InfoContext(ctx, "product image added", "id", productID, "key", key, "order", image.Order)
+	return image, upload, nil
+}
+
+// imageKey places a product's images under its own prefix, within its
+// tenant's when it has one
+func imageKey(product domain.Product, name string) string {
+	if product.TenantID == "" {
+		return fmt.Sprintf("products/%s/%s", product.ID, name)
+	}
+	return fmt.Sprintf("tenants/%s/products/%s/%s", product.TenantID, product.ID, name)
+}
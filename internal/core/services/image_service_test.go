@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+type fakeBlobStorage struct{}
+
+func (fakeBlobStorage) PresignUpload(ctx context.Context, key, contentType string) (ports.PresignedUpload, error) {
+	return ports.PresignedUpload{
+		URL:       "https://bucket/" + key + "?signed",
+		Method:    "PUT",
+		Headers:   map[string]string{"Content-Type": contentType},
+		ExpiresAt: time.Now().Add(time.Minute),
+	}, nil
+}
+
+func (fakeBlobStorage) URL(key string) string {
+	return "https://cdn/" + key
+}
+
+func TestImageService_AddImage(t *testing.T) {
+	repo := newFakeProductRepository()
+	auditLog := &fakeAuditLog{}
+	products := newAuditedProductService(repo, auditLog)
+	images := NewImageService(repo, fakeBlobStorage{}, auditLog, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := ports.WithTenant(context.Background(), "acme")
+
+	created, err := products.Create(ctx, ports.ProductInput{Name: "Laptop", Price: domain.Money{Amount: 99900, Currency: "USD"}})
+	require.NoError(t, err)
+
+	image, upload, err := images.AddImage(ctx, created.ID, "image/png")
+	require.NoError(t, err)
+	assert.Regexp(t, `^tenants/acme/products/`+created.ID+`/[0-9a-f-]+\.png$`, image.Key)
+	assert.Equal(t, "https://cdn/"+image.Key, image.URL)
+	assert.Equal(t, "PUT", upload.Method)
+	assert.Equal(t, "image/png", upload.Headers["Content-Type"])
+
+	stored := repo.products[created.ID]
+	assert.Equal(t, []domain.ProductImage{image}, stored.Images)
+	assert.Equal(t, created.Version+1, stored.Version)
+	require.Len(t, repo.outbox, 2)
+	assert.Equal(t, domain.EventProductUpdated, repo.outbox[1].Type)
+	require.Len(t, auditLog.entries, 2)
+	assert.Equal(t, "images", auditLog.entries[1].Changes[0].Field)
+
+	_, _, err = images.AddImage(ctx, created.ID, "text/plain")
+	assert.ErrorIs(t, err, domain.ErrUnsupportedImageType)
+	_, _, err = images.AddImage(context.Background(), created.ID, "image/png")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+type importService struct {
+	productRules
+	writer   ports.ProductBatchWriter
+	counts   ports.CategoryCounter
+	auditLog ports.AuditLogger
+}
+
+func NewImportService(writer ports.ProductBatchWriter, moderator ports.ContentModerator, analytics ports.AnalyticsPublisher, outbox ports.OutboxRepository, categories ports.CategoryRepository, counts ports.CategoryCounter, auditLog ports.AuditLogger, ids ports.IDGenerator, logger *slog.Logger) ports.ImportService {
+	return &importService{
+		productRules: productRules{
+			moderator:  moderator,
+			analytics:  analytics,
+			outbox:     outbox,
+			categories: categories,
+			ids:        ids,
+			logger:     logger,
+		},
+		writer:   writer,
+		counts:   counts,
+		auditLog: auditLog,
+	}
+}
+
+// Import checks each row with the same rules as a single create. Rows that
+// fail are reported and skipped; the others are written together. A storage
+// failure part way through fails the import, but also returns the summary
+// of the products already written, the rows left being reported as not
+// imported so the caller can send just those again. Its reads and writes
+// yield storage capacity to interactive requests.
+func (s *importService) Import(ctx context.Context, rows []ports.ImportRow, dryRun bool) (domain.ImportSummary, error) {
+	ctx = ports.WithBackgroundPriority(ctx)
+	summary := domain.ImportSummary{DryRun: dryRun, Errors: []domain.ImportRowError{}}
+	// Most rows of a file share a handful of categories
+	rules := s.productRules
+	rules.categories = &categoryMemo{CategoryRepository: s.categories, known: map[string]error{}}
+
+	products := make([]domain.Product, 0, len(rows))
+	productRows := make([]int, 0, len(rows))
+	// claimed maps each SKU and barcode of the file to the row holding it
+	claimed := map[string]int{}
+	for _, row := range rows {
+		if row.Err != nil {
+			summary.Skip(row.Row, row.Err)
+			continue
+		}
+		product, err := rules.newProduct(ctx, row.Input)
+		if err != nil {
+			if !isRowError(err) {
+				return domain.ImportSummary{}, fmt.Errorf("row %d: %w", row.Row, err)
+			}
+			summary.Skip(row.Row, err)
+			continue
+		}
+		if err := claimUniqueValues(claimed, product, row.Row); err != nil {
+			summary.Skip(row.Row, err)
+			continue
+		}
+		products = append(products, *product)
+		productRows = append(productRows, row.Row)
+	}
+	summary.Imported = len(products)
+	if dryRun || len(products) == 0 {
+		s.logger.InfoContext(ctx, "product import checked", "valid", summary.Imported, "skipped", summary.Skipped, "dry_run", dryRun)
+		return summary, nil
+	}
+
+	events := make([]domain.ProductEvent, 0, len(products))
+	for i := range products {
+		created := products[i]
+		events = append(events, domain.NewProductEvent(domain.EventProductCreated, created.ID, &created, created.CreatedAt))
+		events = append(events, flaggedEvents(created, created.CreatedAt)...)
+	}
+	rejected, err := s.writer.SaveBatch(ports.WithOutboxEvents(ctx, events), products)
+	var partial *domain.BatchWriteError
+	if err != nil && !errors.As(err, &partial) {
+		s.logger.ErrorContext(ctx, "failed to import products", "count", len(products), "error", err)
+		return domain.ImportSummary{}, err
+	}
+	var written map[string]bool
+	if partial != nil {
+		written = make(map[string]bool, len(partial.Written))
+		for _, id := range partial.Written {
+			written[id] = true
+		}
+		summary.Written = partial.Written
+	}
+	saved := make([]domain.Product, 0, len(products))
+	for i := range products {
+		product := &products[i]
+		if duplicate, ok := rejected[product.ID]; ok {
+			summary.Imported--
+			summary.Skip(productRows[i], duplicate)
+			continue
+		}
+		if written != nil && !written[product.ID] {
+			summary.Imported--
+			summary.Skip(productRows[i], domain.ErrNotImported)
+			continue
+		}
+		saved = append(saved, *product)
+		recordAudit(ctx, s.auditLog, s.logger, domain.AuditCreate, nil, product, product.CreatedAt)
+		s.analytics.Track(ctx, domain.AnalyticsEvent{
+			Type:       domain.EventProductCreated,
+			ProductID:  product.ID,
+			Properties: map[string]interface{}{"name": product.Name, "price": product.Price.Decimal(), "currency": product.Price.Currency},
+			OccurredAt: product.CreatedAt,
+		})
+	}
+
+	adjustCategoryCounts(ctx, s.counts, s.logger, saved, 1)
+
+	// Rows rejected by the write are reported along with the others
+	sort.SliceStable(summary.Errors, func(i, j int) bool { return summary.Errors[i].Row < summary.Errors[j].Row })
+
+	if partial != nil {
+		s.logger.ErrorContext(ctx, "product import failed part way", "imported", summary.Imported, "not_imported", len(products)-summary.Imported, "error", err)
+		return summary, err
+	}
+	s.logger.InfoContext(ctx, "products imported", "imported", summary.Imported, "skipped", summary.Skipped)
+	return summary, nil
+}
+
+// claimUniqueValues records the SKU and barcode of the product on row in
+// claimed, failing when an earlier row of the file holds one of them
+func claimUniqueValues(claimed map[string]int, product *domain.Product, row int) error {
+	values := product.UniqueValues()
+	for field, value := range values {
+		if first, ok := claimed[field+"#"+value]; ok {
+			return fmt.Errorf("%w (row %d)", &domain.DuplicateError{Field: field, Value: value}, first)
+		}
+	}
+	for field, value := range values {
+		claimed[field+"#"+value] = row
+	}
+	return nil
+}
+
+// isRowError reports whether err is caused by the row's content, rather
+// than by a dependency that would fail every row
+func isRowError(err error) bool {
+	return errors.Is(err, domain.ErrInvalidProduct) || errors.Is(err, domain.ErrUnknownCategory) || errors.Is(err, domain.ErrContentRejected)
+}
+
+// categoryMemo remembers the categories looked up during one import
+type categoryMemo struct {
+	ports.CategoryRepository
+	known map[string]error
+}
+
+func (m *categoryMemo) GetByID(ctx context.Context, id string) (domain.Category, error) {
+	if err, ok := m.known[id]; ok {
+		return domain.Category{ID: id}, err
+	}
+	category, err := m.CategoryRepository.GetByID(ctx, id)
+	if err == nil || errors.Is(err, domain.ErrCategoryNotFound) {
+		m.known[id] = err
+	}
+	return category, err
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// fakeBatchWriter records the products and outbox events of each batch,
+// rejecting products whose SKU is in takenSKUs. With err it fails, after
+// writing the first failAfter products.
+type fakeBatchWriter struct {
+	products  []domain.Product
+	outbox    []domain.ProductEvent
+	takenSKUs map[string]bool
+	err       error
+	failAfter int
+}
+
+func (f *fakeBatchWriter) SaveBatch(ctx context.Context, products []domain.Product) (map[string]*domain.DuplicateError, error) {
+	if f.err != nil && f.failAfter == 0 {
+		return nil, f.err
+	}
+	rejected := map[string]*domain.DuplicateError{}
+	var written []string
+	for _, product := range products {
+		if f.takenSKUs[product.SKU] {
+			rejected[product.ID] = &domain.DuplicateError{Field: domain.FieldSKU, Value: product.SKU}
+			continue
+		}
+		if f.err != nil && len(written) == f.failAfter {
+			return nil, &domain.BatchWriteError{Written: written, Err: f.err}
+		}
+		f.products = append(f.products, product)
+		written = append(written, product.ID)
+	}
+	f.outbox = append(f.outbox, ports.OutboxEvents(ctx)...)
+	return rejected, nil
+}
+
+// countingCategories knows a single category and counts its lookups
+type countingCategories struct {
+	ports.CategoryRepository
+	lookups int
+}
+
+func (f *countingCategories) GetByID(ctx context.Context, id string) (domain.Category, error) {
+	f.lookups++
+	if id != "electronics" {
+		return domain.Category{}, domain.ErrCategoryNotFound
+	}
+	return domain.Category{ID: id}, nil
+}
+
+func newTestImportService(writer ports.ProductBatchWriter, categories ports.CategoryRepository, auditLog ports.AuditLogger) ports.ImportService {
+	return NewImportService(writer, allowAllModerator{}, &recordingPublisher{}, nil, categories, nil, auditLog, randomIDs{},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestImportService_SkipsInvalidRows(t *testing.T) {
+	writer := &fakeBatchWriter{}
+	categories := &countingCategories{}
+	auditLog := &fakeAuditLog{}
+	service := newTestImportService(writer, categories, auditLog)
+
+	summary, err := service.Import(context.Background(), []ports.ImportRow{
+		{Row: 1, Input: ports.ProductInput{Name: "Laptop", Price: domain.Money{Amount: 99900, Currency: "USD"}, CategoryID: "electronics"}},
+		{Row: 2, Err: errors.New("price is required and must be greater than 0")},
+		{Row: 3, Input: ports.ProductInput{Name: "Mouse", Price: domain.Money{Amount: 2500, Currency: "USD"}, CategoryID: "electronics"}},
+		{Row: 4, Input: ports.ProductInput{Name: "Sofa", Price: domain.Money{Amount: 30000, Currency: "USD"}, CategoryID: "furniture"}},
+	}, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, summary.Imported)
+	assert.Equal(t, 2, summary.Skipped)
+	assert.False(t, summary.DryRun)
+	require.Len(t, summary.Errors, 2)
+	assert.Equal(t, 2, summary.Errors[0].Row)
+	assert.Equal(t, 4, summary.Errors[1].Row)
+	assert.Equal(t, domain.ErrUnknownCategory.Error(), summary.Errors[1].Error)
+
+	require.Len(t, writer.products, 2)
+	require.Len(t, writer.outbox, 2)
+	assert.Equal(t, domain.EventProductCreated, writer.outbox[0].Type)
+	assert.Equal(t, writer.products[0].ID, writer.outbox[0].ProductID)
+	assert.Len(t, auditLog.entries, 2)
+	// Both imported rows share a category, which is looked up once
+	assert.Equal(t, 2, categories.lookups)
+}
+
+func TestImportService_DryRunWritesNothing(t *testing.T) {
+	writer := &fakeBatchWriter{}
+	auditLog := &fakeAuditLog{}
+	service := newTestImportService(writer, &countingCategories{}, auditLog)
+
+	summary, err := service.Import(context.Background(), []ports.ImportRow{
+		{Row: 1, Input: ports.ProductInput{Name: "Laptop", Price: domain.Money{Amount: 99900, Currency: "USD"}}},
+		{Row: 2, Input: ports.ProductInput{Name: "", Price: domain.Money{Amount: 1000, Currency: "USD"}}},
+	}, true)
+	require.NoError(t, err)
+
+	assert.True(t, summary.DryRun)
+	assert.Equal(t, 1, summary.Imported)
+	assert.Equal(t, 1, summary.Skipped)
+	assert.Empty(t, writer.products)
+	assert.Empty(t, auditLog.entries)
+}
+
+func TestImportService_StorageFailureFailsImport(t *testing.T) {
+	writer := &fakeBatchWriter{err: errors.New("throttled")}
+	service := newTestImportService(writer, &countingCategories{}, &fakeAuditLog{})
+
+	_, err := service.Import(context.Background(), []ports.ImportRow{
+		{Row: 1, Input: ports.ProductInput{Name: "Laptop", Price: domain.Money{Amount: 99900, Currency: "USD"}}},
+	}, false)
+	assert.Error(t, err)
+}
+
+func TestImportService_PartialFailureReportsWrittenProducts(t *testing.T) {
+	writer := &fakeBatchWriter{err: errors.New("throttled"), failAfter: 1}
+	auditLog := &fakeAuditLog{}
+	service := newTestImportService(writer, &countingCategories{}, auditLog)
+
+	summary, err := service.Import(context.Background(), []ports.ImportRow{
+		{Row: 1, Input: ports.ProductInput{Name: "Laptop", Price: domain.Money{Amount: 99900, Currency: "USD"}}},
+		{Row: 2, Err: errors.New("price is required and must be greater than 0")},
+		{Row: 3, Input: ports.ProductInput{Name: "Mouse", Price: domain.Money{Amount: 2500, Currency: "USD"}}},
+	}, false)
+	require.Error(t, err)
+
+	require.Len(t, writer.products, 1)
+	assert.Equal(t, []string{writer.products[0].ID}, summary.Written)
+	assert.Equal(t, 1, summary.Imported)
+	assert.Equal(t, 2, summary.Skipped)
+	require.Len(t, summary.Errors, 2)
+	assert.Equal(t, 2, summary.Errors[0].Row)
+	assert.Equal(t, domain.ImportRowError{Row: 3, Error: domain.ErrNotImported.Error()}, summary.Errors[1])
+	assert.Len(t, auditLog.entries, 1, "only the written product is audited")
+}
+
+func TestImportService_SkipsDuplicateIdentifiers(t *testing.T) {
+	writer := &fakeBatchWriter{takenSKUs: map[string]bool{"TAKEN-1": true}}
+	auditLog := &fakeAuditLog{}
+	service := newTestImportService(writer, &countingCategories{}, auditLog)
+
+	price := domain.Money{Amount: 1000, Currency: "USD"}
+	summary, err := service.Import(context.Background(), []ports.ImportRow{
+		{Row: 1, Input: ports.ProductInput{Name: "Laptop", Price: price, SKU: "lap-1"}},
+		{Row: 2, Input: ports.ProductInput{Name: "Laptop copy", Price: price, SKU: "LAP-1"}},
+		{Row: 3, Input: ports.ProductInput{Name: "Mouse", Price: price, SKU: "taken-1"}},
+		{Row: 4, Input: ports.ProductInput{Name: "Cable", Price: price}},
+	}, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, summary.Imported)
+	assert.Equal(t, 2, summary.Skipped)
+	require.Len(t, summary.Errors, 2)
+	assert.Equal(t, 2, summary.Errors[0].Row)
+	assert.Contains(t, summary.Errors[0].Error, "(row 1)")
+	assert.Equal(t, 3, summary.Errors[1].Row)
+	assert.Equal(t, `sku "TAKEN-1" is already used by another product`, summary.Errors[1].Error)
+	assert.Len(t, writer.products, 2)
+	assert.Len(t, auditLog.entries, 2)
+}
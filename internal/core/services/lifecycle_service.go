@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+// transitionEvents are the events written and tracked when a product
+// enters a status
+var transitionEvents = map[string]string{
+	domain.StatusPublished:    domain.EventProductPublished,
+	domain.StatusArchived:     domain.EventProductArchived,
+	domain.StatusDiscontinued: domain.EventProductDiscontinued,
+}
+
+type lifecycleService struct {
+	repo      ports.ProductRepository
+	analytics ports.AnalyticsPublisher
+	auditLog  ports.AuditLogger
+	logger    *slog.Logger
+}
+
+func NewLifecycleService(repo ports.ProductRepository, analytics ports.AnalyticsPublisher, auditLog ports.AuditLogger, logger *slog.Logger) ports.LifecycleService {
+	return &lifecycleService{
+		repo:      repo,
+		analytics: analytics,
+		auditLog:  auditLog,
+		logger:    logger,
+	}
+}
+
+// Transition moves the product to status, writing it like any other update
+// so it is versioned, audited and announced on the outbox
+func (s *lifecycleService) Transition(ctx context.Context, id, status string) (domain.Product, error) {
+	product, err := s.repo.GetByID(ports.WithConsistentRead(ctx), id)
+	if err != nil {
+		return domain.Product{}, err
+	}
+	before := product
+
+	now := time.Now().UTC()
+	if err := product.TransitionTo(status, now); err != nil {
+		s.logger.InfoContext(ctx, "status transition rejected", "id", id, "from", before.Status, "to", status)
+		return domain.Product{}, err
+	}
+
+	updated := product
+	updated.Version++
+	if err := s.repo.Update(withLifecycleEvent(ctx, updated, transitionEvents[status], now), product); err != nil {
+		if !errors.Is(err, domain.ErrConflict) {
+			s.logger.ErrorContext(ctx, "failed to change product status", "id", id, "status", status, "error", err)
+		}
+		return domain.Product{}, err
+	}
+	product.Version++
+	recordAudit(ctx, s.auditLog, s.logger, domain.AuditUpdate, &before, &product, now)
+
+	s.analytics.Track(ctx, domain.AnalyticsEvent{
+		Type:       transitionEvents[status],
+		ProductID:  id,
+		Properties: map[string]interface{}{"from": before.Status},
+		OccurredAt: now,
+	})
+	s.logger.InfoContext(ctx, "product status changed", "id", id, "from", before.Status, "to", status)
+	return product, nil
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+func TestLifecycleService_Transition(t *testing.T) {
+	repo := newFakeProductRepository()
+	repo.products["p1"] = domain.Product{ID: "p1", Name: "Kettle", Status: domain.StatusDraft, Version: 1}
+	events := &recordingPublisher{}
+	auditLog := &fakeAuditLog{}
+	service := NewLifecycleService(repo, events, auditLog, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	product, err := service.Transition(ctx, "p1", domain.StatusPublished)
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusPublished, product.Status)
+	assert.Equal(t, int64(2), product.Version)
+	assert.Equal(t, product, repo.products["p1"])
+
+	_, err = service.Transition(ctx, "p1", domain.StatusDiscontinued)
+	require.NoError(t, err)
+	_, err = service.Transition(ctx, "p1", domain.StatusPublished)
+	assert.ErrorIs(t, err, domain.ErrInvalidTransition)
+	assert.Equal(t, domain.StatusDiscontinued, repo.products["p1"].Status)
+
+	require.Len(t, events.events, 2)
+	assert.Equal(t, domain.EventProductPublished, events.events[0].Type)
+	assert.Equal(t, domain.EventProductDiscontinued, events.events[1].Type)
+	assert.Len(t, auditLog.entries, 2)
+	// Each transition is written as an update and as the transition
+	require.Len(t, repo.outbox, 4)
+	assert.Equal(t, domain.EventProductUpdated, repo.outbox[0].Type)
+	assert.Equal(t, domain.EventProductPublished, repo.outbox[1].Type)
+	assert.Equal(t, domain.EventProductDiscontinued, repo.outbox[3].Type)
+
+	_, err = service.Transition(ctx, "missing", domain.StatusArchived)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
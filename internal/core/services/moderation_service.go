@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+type moderationService struct {
+	products ports.ProductRepository
+	queue    ports.ModerationRepository
+	logger   *slog.Logger
+}
+
+func NewModerationService(products ports.ProductRepository, queue ports.ModerationRepository, logger *slog.Logger) ports.ModerationService {
+	return &moderationService{
+		products: products,
+		queue:    queue,
+		logger:   logger,
+	}
+}
+
+func (s *moderationService) PendingReview(ctx context.Context) ([]domain.Product, error) {
+	return s.queue.ListPendingReview(ctx)
+}
+
+func (s *moderationService) Approve(ctx context.Context, id string) (domain.Product, error) {
+	return s.resolve(ctx, id, true, "")
+}
+
+func (s *moderationService) Reject(ctx context.Context, id, reason string) (domain.Product, error) {
+	return s.resolve(ctx, id, false, reason)
+}
+
+func (s *moderationService) resolve(ctx context.Context, id string, approved bool, reason string) (domain.Product, error) {
+	product, err := s.products.GetByID(ports.WithConsistentRead(ctx), id)
+	if err != nil {
+		return domain.Product{}, err
+	}
+	if err := product.ResolveReview(approved, reason); err != nil {
+		return domain.Product{}, err
+	}
+	now := time.Now().UTC()
+	product.UpdatedAt = now
+
+	updated := product
+	updated.Version++
+	event := domain.NewProductEvent(domain.EventProductUpdated, id, &updated, now)
+	if err := s.products.Update(ports.WithOutboxEvent(ctx, event), product); err != nil {
+		s.logger.ErrorContext(ctx, "failed to save review decision", "id", id, "error", err)
+		return domain.Product{}, err
+	}
+	product.Version++
+
+	s.logger.InfoContext(ctx, "product review resolved", "id", id, "moderation_status", product.ModerationStatus)
+	return product, nil
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+const (
+	// notificationTimeout bounds a single delivery attempt
+	notificationTimeout = 10 * time.Second
+	// notificationWorkers send notifications concurrently, and up to
+	// notificationQueueSize more wait for them
+	notificationWorkers   = 4
+	notificationQueueSize = 100
+)
+
+// errNotificationsClosed is returned for events published after Close, so
+// the outbox keeps them for the next instance
+var errNotificationsClosed = errors.New("notification service is closed")
+
+// pendingNotification is a rendered notification waiting for a worker
+type pendingNotification struct {
+	notification domain.Notification
+	productID    string
+}
+
+// NotificationService turns product events into notifications according to
+// the configured rules. It receives events as a ports.EventPublisher of the
+// outbox relay, so it only notifies of committed changes, and hands them to
+// a fixed pool of workers so a slow mail provider holds the relay up only
+// once the queue is full.
+type NotificationService struct {
+	notifier ports.Notifier
+	rules    []domain.NotificationRule
+	logger   *slog.Logger
+
+	// mu guards closed and sends on queue, which Close closes
+	mu      sync.RWMutex
+	closed  bool
+	queue   chan pendingNotification
+	workers sync.WaitGroup
+}
+
+func NewNotificationService(notifier ports.Notifier, rules []domain.NotificationRule, logger *slog.Logger) *NotificationService {
+	s := &NotificationService{
+		notifier: notifier,
+		rules:    rules,
+		logger:   logger,
+		queue:    make(chan pendingNotification, notificationQueueSize),
+	}
+	s.workers.Add(notificationWorkers)
+	for range notificationWorkers {
+		go s.work()
+	}
+	return s
+}
+
+// Publish queues a notification for every rule event matches, waiting for
+// room in the queue until ctx is done
+func (s *NotificationService) Publish(ctx context.Context, event domain.ProductEvent) error {
+	data := domain.NewNotificationData(event)
+	for _, rule := range s.rules {
+		if !rule.Matches(data) {
+			continue
+		}
+
+		notification, err := rule.Render(data)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "failed to render notification", "rule", rule.Name, "event", event.Type, "error", err)
+			continue
+		}
+		if err := s.enqueue(ctx, pendingNotification{notification: notification, productID: event.ProductID}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *NotificationService) enqueue(ctx context.Context, pending pendingNotification) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return errNotificationsClosed
+	}
+	select {
+	case s.queue <- pending:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// work sends queued notifications until the queue is closed and drained
+func (s *NotificationService) work() {
+	defer s.workers.Done()
+	for pending := range s.queue {
+		// Detached from the relay run that queued it
+		ctx, cancel := context.WithTimeout(context.Background(), notificationTimeout)
+		if err := s.notifier.Send(ctx, pending.notification); err != nil {
+			s.logger.ErrorContext(ctx, "failed to send notification", "rule", pending.notification.Rule, "product_id", pending.productID, "error", err)
+		}
+		cancel()
+	}
+}
+
+// Close stops accepting events and waits for the queued notifications to
+// be sent, giving up when ctx is done
+func (s *NotificationService) Close(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
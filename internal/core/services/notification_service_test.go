@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"log/slog"
+)
+
+type recordingNotifier struct {
+	mu   sync.Mutex
+	sent []domain.Notification
+}
+
+func (r *recordingNotifier) Send(ctx context.Context, notification domain.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sent = append(r.sent, notification)
+	return nil
+}
+
+func TestNotificationService_SendsMatchingRules(t *testing.T) {
+	rules, err := domain.ParseNotificationRules([]byte(`[
+		{"name": "rejected", "event": "product.moderation_rejected", "recipients": ["trust@example.com"],
+		 "subject": "Rejected: {{.Properties.name}}", "body": "{{.Properties.reasons}}"},
+		{"name": "expensive", "event": "product.created", "min_price": 500, "recipients": ["catalog@example.com"],
+		 "subject": "{{.Properties.name}}", "body": "{{.ProductID}}"}
+	]`))
+	require.NoError(t, err)
+
+	notifier := &recordingNotifier{}
+	service := NewNotificationService(notifier, rules, slog.Default())
+
+	cheap := domain.Product{ID: "cheap", Name: "Mug", Price: domain.Money{Amount: 1200, Currency: "USD"}}
+	bad := domain.Product{ID: "bad", Name: "Scam kit", ModerationStatus: domain.ModerationRejected, ModerationReasons: []string{`blocked term "scam"`}}
+	require.NoError(t, service.Publish(context.Background(), domain.NewProductEvent(domain.EventProductCreated, cheap.ID, &cheap, time.Now())))
+	require.NoError(t, service.Publish(context.Background(), domain.NewProductEvent(domain.EventProductRejected, bad.ID, &bad, time.Now())))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, service.Close(ctx))
+
+	require.Len(t, notifier.sent, 1)
+	assert.Equal(t, []string{"trust@example.com"}, notifier.sent[0].To)
+	assert.Equal(t, "Rejected: Scam kit", notifier.sent[0].Subject)
+	assert.Equal(t, `[blocked term "scam"]`, notifier.sent[0].Body)
+}
+
+func TestNotificationService_RefusesEventsAfterClose(t *testing.T) {
+	rules, err := domain.ParseNotificationRules([]byte(`[
+		{"name": "created", "event": "product.created", "recipients": ["catalog@example.com"], "subject": "{{.ProductID}}", "body": ""}
+	]`))
+	require.NoError(t, err)
+	notifier := &recordingNotifier{}
+	service := NewNotificationService(notifier, rules, slog.Default())
+	require.NoError(t, service.Close(context.Background()))
+
+	// The relay keeps the event pending for the next instance
+	event := domain.NewProductEvent(domain.EventProductCreated, "p1", &domain.Product{ID: "p1"}, time.Now())
+	assert.ErrorIs(t, service.Publish(context.Background(), event), errNotificationsClosed)
+	assert.Empty(t, notifier.sent)
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+type outboxService struct {
+	outbox    ports.OutboxRepository
+	publisher ports.EventPublisher
+	batchSize int
+	logger    *slog.Logger
+	now       func() time.Time
+}
+
+func NewOutboxService(outbox ports.OutboxRepository, publisher ports.EventPublisher, batchSize int, logger *slog.Logger) ports.OutboxService {
+	return &outboxService{
+		outbox:    outbox,
+		publisher: publisher,
+		batchSize: batchSize,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// RelayPending publishes pending events oldest first and marks each one
+// sent. It stops at the first failure so no event overtakes an earlier one;
+// the rest are retried on the next run. An event published but not marked
+// is published again, so delivery is at least once.
+func (s *outboxService) RelayPending(ctx context.Context) error {
+	for {
+		pending, err := s.outbox.ListPending(ctx, s.batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range pending {
+			if err := s.publisher.Publish(ctx, event); err != nil {
+				s.logger.ErrorContext(ctx, "failed to relay product event", "event_id", event.ID, "type", event.Type, "id", event.ProductID, "error", err)
+				return err
+			}
+			if err := s.outbox.MarkSent(ctx, event.ID, s.now().UTC()); err != nil {
+				s.logger.ErrorContext(ctx, "failed to mark product event sent", "event_id", event.ID, "error", err)
+				return err
+			}
+		}
+
+		if len(pending) > 0 {
+			s.logger.DebugContext(ctx, "relayed product events", "count", len(pending))
+		}
+		if len(pending) < s.batchSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+type fakeOutboxRepository struct {
+	pending []domain.ProductEvent
+	sent    []string
+}
+
+func (f *fakeOutboxRepository) ListPending(ctx context.Context, limit int) ([]domain.ProductEvent, error) {
+	if len(f.pending) < limit {
+		limit = len(f.pending)
+	}
+	return append([]domain.ProductEvent(nil), f.pending[:limit]...), nil
+}
+
+func (f *fakeOutboxRepository) MarkSent(ctx context.Context, id string, sentAt time.Time) error {
+	f.sent = append(f.sent, id)
+	for i, event := range f.pending {
+		if event.ID == id {
+			f.pending = append(f.pending[:i], f.pending[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (f *fakeOutboxRepository) Append(ctx context.Context, event domain.ProductEvent) error {
+	f.pending = append(f.pending, event)
+	return nil
+}
+
+type recordingEventPublisher struct {
+	events []domain.ProductEvent
+	err    error
+}
+
+func (r *recordingEventPublisher) Publish(ctx context.Context, event domain.ProductEvent) error {
+	r.events = append(r.events, event)
+	return r.err
+}
+
+func TestRelayPending(t *testing.T) {
+	outbox := &fakeOutboxRepository{pending: []domain.ProductEvent{{ID: "e1"}, {ID: "e2"}, {ID: "e3"}}}
+	publisher := &recordingEventPublisher{}
+	service := NewOutboxService(outbox, publisher, 2, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	assert.NoError(t, service.RelayPending(context.Background()))
+	assert.Equal(t, []string{"e1", "e2", "e3"}, outbox.sent)
+	assert.Len(t, publisher.events, 3)
+	assert.Empty(t, outbox.pending)
+}
+
+func TestRelayPending_StopsAtFirstFailure(t *testing.T) {
+	outbox := &fakeOutboxRepository{pending: []domain.ProductEvent{{ID: "e1"}, {ID: "e2"}}}
+	publisher := &recordingEventPublisher{err: errors.New("topic unavailable")}
+	service := NewOutboxService(outbox, publisher, 10, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	assert.Error(t, service.RelayPending(context.Background()))
+	assert.Len(t, publisher.events, 1)
+	assert.Empty(t, outbox.sent)
+	assert.Len(t, outbox.pending, 2)
+}
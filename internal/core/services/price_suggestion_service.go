@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+type priceSuggestionService struct {
+	products  ports.ProductRepository
+	suggester ports.PriceSuggester
+	logger    *slog.Logger
+}
+
+func NewPriceSuggestionService(products ports.ProductRepository, suggester ports.PriceSuggester, logger *slog.Logger) ports.PriceSuggestionService {
+	return &priceSuggestionService{
+		products:  products,
+		suggester: suggester,
+		logger:    logger,
+	}
+}
+
+// Suggest only reads the suggestion; applying it is an ordinary update
+func (s *priceSuggestionService) Suggest(ctx context.Context, id string) (domain.PriceSuggestion, error) {
+	product, err := s.products.GetByID(ctx, id)
+	if err != nil {
+		return domain.PriceSuggestion{}, err
+	}
+
+	suggestion, err := s.suggester.SuggestPrice(ctx, product)
+	if err != nil {
+		if errors.Is(err, domain.ErrPricingUnavailable) {
+			s.logger.WarnContext(ctx, "price suggestion service unavailable", "id", id, "error", err)
+		} else if !errors.Is(err, domain.ErrNoPriceSuggestion) {
+			s.logger.ErrorContext(ctx, "failed to get price suggestion", "id", id, "error", err)
+		}
+		return domain.PriceSuggestion{}, err
+	}
+	return suggestion, nil
+}
@@ -2,6 +2,9 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"maps"
 	"time"
 
 	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
@@ -9,58 +12,624 @@ import (
 	"log/slog"
 )
 
+// maxRedirectHops bounds how many merges a tombstone lookup follows
+const maxRedirectHops = 5
+
 type service struct {
-	repo   ports.ProductRepository
-	logger *slog.Logger
+	productRules
+	repo        ports.ProductRepository
+	tombstones  ports.TombstoneRepository
+	searchTerms ports.SearchTermService
+	auditLog    ports.AuditLogger
+	// counts and uow may be nil, leaving category counts alone and
+	// running each write on its own
+	counts ports.CategoryCounter
+	uow    ports.UnitOfWork
+}
+
+// productRules validates new products the same way for single creates and
+// bulk imports
+type productRules struct {
+	moderator ports.ContentModerator
+	analytics ports.AnalyticsPublisher
+	// outbox records moderation rejections, which write no product; nil
+	// leaves them to analytics alone
+	outbox     ports.OutboxRepository
+	categories ports.CategoryRepository
+	ids        ports.IDGenerator
+	idFormat   domain.ProductIDFormat
+	logger     *slog.Logger
 }
 
-func NewProductService(repo ports.ProductRepository, logger *slog.Logger) ports.ProductService {
+// log returns the logger of the request ctx belongs to, falling back to
+// the service's own outside of requests
+func (r productRules) log(ctx context.Context) *slog.Logger {
+	return ports.Logger(ctx, r.logger)
+}
+
+// NewProductService names new products with ids, unless clients choose
+// their IDs, which must be in idFormat
+func NewProductService(repo ports.ProductRepository, tombstones ports.TombstoneRepository, moderator ports.ContentModerator, analytics ports.AnalyticsPublisher, outbox ports.OutboxRepository, searchTerms ports.SearchTermService, categories ports.CategoryRepository, counts ports.CategoryCounter, uow ports.UnitOfWork, auditLog ports.AuditLogger, ids ports.IDGenerator, idFormat domain.ProductIDFormat, logger *slog.Logger) ports.ProductService {
 	return &service{
-		repo:   repo,
-		logger: logger,
+		productRules: productRules{
+			moderator:  moderator,
+			analytics:  analytics,
+			outbox:     outbox,
+			categories: categories,
+			ids:        ids,
+			idFormat:   idFormat,
+			logger:     logger,
+		},
+		repo:        repo,
+		tombstones:  tombstones,
+		searchTerms: searchTerms,
+		auditLog:    auditLog,
+		counts:      counts,
+		uow:         uow,
 	}
 }
 
-func (s *service) Create(ctx context.Context, name, description string, price float64) (domain.Product, error) {
-	product, err := domain.NewProduct(name, description, price)
+func (s *service) Create(ctx context.Context, input ports.ProductInput) (domain.Product, error) {
+	product, err := s.newProduct(ctx, input)
 	if err != nil {
-		s.logger.Warn("invalid product creation attempt", "error", err)
-		return domain.Product{}, domain.ErrInvalidProduct
+		if errors.Is(err, domain.ErrInvalidProduct) {
+			s.log(ctx).WarnContext(ctx, "invalid product creation attempt", "error", err)
+			return domain.Product{}, domain.ErrInvalidProduct
+		}
+		return domain.Product{}, err
 	}
 
-	if err := s.repo.Save(ctx, *product); err != nil {
-		s.logger.Error("failed to save product", "error", err)
+	return s.insert(ctx, product)
+}
+
+// Clone copies the client-editable attributes and translations of a
+// product into a new one, checked like any other create. Stock, images,
+// reviews and the moderation state start over.
+func (s *service) Clone(ctx context.Context, id string, input ports.CloneInput) (domain.Product, error) {
+	source, err := s.repo.GetByID(ctx, id)
+	if err != nil {
 		return domain.Product{}, err
 	}
 
+	cloned := ports.ProductInput{
+		ID:            input.ID,
+		Name:          source.Name,
+		Description:   source.Description,
+		Price:         source.Price,
+		CategoryID:    source.CategoryID,
+		Tags:          source.Tags,
+		ExpiresAt:     input.ExpiresAt,
+		PublishAt:     input.PublishAt,
+		AutoArchiveAt: input.AutoArchiveAt,
+		SKU:           input.SKU,
+		Barcode:       input.Barcode,
+	}
+	if input.Name != nil {
+		cloned.Name = *input.Name
+	}
+	cloned.Name += input.NameSuffix
+	if input.Description != nil {
+		cloned.Description = *input.Description
+	}
+	if input.Price != nil {
+		cloned.Price = *input.Price
+	}
+	if input.CategoryID != nil {
+		cloned.CategoryID = *input.CategoryID
+	}
+	if input.Tags != nil {
+		cloned.Tags = input.Tags
+	}
+	switch {
+	case input.CostPrice != nil:
+		cloned.CostPrice = input.CostPrice
+	case source.CostPrice == nil:
+	case source.CostPrice.Currency != cloned.Price.Currency:
+		// The original's cost says nothing about a price in another currency
+		err := fmt.Errorf("%w: cost_price must be given when the copy is priced in %s", domain.ErrInvalidProduct, cloned.Price.Currency)
+		s.log(ctx).WarnContext(ctx, "invalid product clone attempt", "source_id", id, "error", err)
+		return domain.Product{}, err
+	default:
+		cost := source.CostPrice.Major()
+		cloned.CostPrice = &cost
+	}
+
+	product, err := s.newProduct(ctx, cloned)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidProduct) {
+			s.log(ctx).WarnContext(ctx, "invalid product clone attempt", "source_id", id, "error", err)
+		}
+		return domain.Product{}, err
+	}
+	product.Translations = maps.Clone(source.Translations)
+
+	created, err := s.insert(ctx, product)
+	if err != nil {
+		return domain.Product{}, err
+	}
+	s.log(ctx).InfoContext(ctx, "product cloned", "source_id", id, "id", created.ID)
+	return created, nil
+}
+
+// insert saves a new product with its creation event, audit entry and
+// analytics
+func (s *service) insert(ctx context.Context, product *domain.Product) (domain.Product, error) {
+	created := *product
+	events := append([]domain.ProductEvent{domain.NewProductEvent(domain.EventProductCreated, product.ID, &created, product.CreatedAt)},
+		flaggedEvents(created, product.CreatedAt)...)
+	err := transact(ctx, s.uow, func(ctx context.Context) error {
+		if err := s.repo.Save(ports.WithOutboxEvents(ctx, events), *product); err != nil {
+			return err
+		}
+		return moveCategoryCount(ctx, s.counts, "", product.CategoryID)
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrDuplicate) {
+			s.log(ctx).InfoContext(ctx, "duplicate product identifier rejected", "error", err)
+			return domain.Product{}, err
+		}
+		s.log(ctx).ErrorContext(ctx, "failed to save product", "error", err)
+		return domain.Product{}, err
+	}
+	s.audit(ctx, domain.AuditCreate, nil, &created, product.CreatedAt)
+
+	s.analytics.Track(ctx, domain.AnalyticsEvent{
+		Type:       domain.EventProductCreated,
+		ProductID:  product.ID,
+		Properties: map[string]interface{}{"name": product.Name, "price": product.Price.Decimal(), "currency": product.Price.Currency},
+		OccurredAt: product.CreatedAt,
+	})
 	return *product, nil
 }
 
 func (s *service) Get(ctx context.Context, id string) (domain.Product, error) {
-	return s.repo.GetByID(ctx, id)
+	product, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.Product{}, s.resolveTombstone(ctx, id)
+		}
+		return domain.Product{}, err
+	}
+	return product, nil
+}
+
+// RecordView reports to analytics that a client was sent the product
+func (s *service) RecordView(ctx context.Context, id string) {
+	s.analytics.Track(ctx, domain.AnalyticsEvent{
+		Type:       domain.EventProductViewed,
+		ProductID:  id,
+		OccurredAt: time.Now().UTC(),
+	})
+}
+
+// GetBySKU finds the product holding an SKU, matched case-insensitively
+func (s *service) GetBySKU(ctx context.Context, sku string) (domain.Product, error) {
+	normalized, err := domain.NormalizeSKU(sku)
+	if err != nil || normalized == "" {
+		return domain.Product{}, domain.ErrNotFound
+	}
+	return s.repo.GetBySKU(ctx, normalized)
+}
+
+// GetMany reads every product in a single repository batch. Repeated IDs
+// are read and returned once.
+func (s *service) GetMany(ctx context.Context, ids []string) ([]domain.Product, []string, error) {
+	unique := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	found, err := s.repo.GetByIDs(ctx, unique)
+	if err != nil {
+		s.log(ctx).ErrorContext(ctx, "failed to get products", "count", len(unique), "error", err)
+		return nil, nil, err
+	}
+	byID := make(map[string]domain.Product, len(found))
+	for _, product := range found {
+		byID[product.ID] = product
+	}
+
+	products := make([]domain.Product, 0, len(found))
+	missing := []string{}
+	for _, id := range unique {
+		if product, ok := byID[id]; ok {
+			products = append(products, product)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	return products, missing, nil
 }
 
-func (s *service) Update(ctx context.Context, id, name, description string, price float64) (domain.Product, error) {
-	existing, err := s.repo.GetByID(ctx, id)
+func (s *service) Update(ctx context.Context, id string, input ports.ProductInput) (domain.Product, error) {
+	// Read-modify-write must start from the latest committed item
+	existing, err := s.repo.GetByID(ports.WithConsistentRead(ctx), id)
 	if err != nil {
 		return domain.Product{}, err
 	}
+	if input.Version != nil && *input.Version != existing.Version {
+		s.log(ctx).InfoContext(ctx, "stale product update rejected", "id", id, "version", *input.Version, "current", existing.Version)
+		return domain.Product{}, domain.ErrConflict
+	}
+	before := existing
 
-	existing.Name = name
-	existing.Description = description
-	existing.Price = price
-	existing.UpdatedAt = time.Now().UTC()
+	now := time.Now().UTC()
+	if err := existing.SetExpiration(input.ExpiresAt, now); err != nil {
+		s.log(ctx).WarnContext(ctx, "invalid product update attempt", "id", id, "error", err)
+		return domain.Product{}, domain.ErrInvalidProduct
+	}
+	wasDraft := !existing.IsPublished()
+	if err := existing.SchedulePublish(input.PublishAt, now); err != nil {
+		s.log(ctx).WarnContext(ctx, "invalid product update attempt", "id", id, "error", err)
+		if errors.Is(err, domain.ErrInvalidTransition) {
+			return domain.Product{}, err
+		}
+		return domain.Product{}, domain.ErrInvalidProduct
+	}
+	if err := existing.SetAutoArchive(input.AutoArchiveAt, now); err != nil {
+		s.log(ctx).WarnContext(ctx, "invalid product update attempt", "id", id, "error", err)
+		return domain.Product{}, domain.ErrInvalidProduct
+	}
+	if err := existing.SetTags(input.Tags); err != nil {
+		s.log(ctx).WarnContext(ctx, "invalid product update attempt", "id", id, "error", err)
+		return domain.Product{}, domain.ErrInvalidProduct
+	}
+	if err := existing.SetPrice(input.Price); err != nil {
+		s.log(ctx).WarnContext(ctx, "invalid product update attempt", "id", id, "error", err)
+		return domain.Product{}, domain.ErrInvalidProduct
+	}
+	// The cost is in the price's currency
+	if err := existing.SetCostPrice(input.CostPrice); err != nil {
+		s.log(ctx).WarnContext(ctx, "invalid product update attempt", "id", id, "error", err)
+		return domain.Product{}, domain.ErrInvalidProduct
+	}
+	if err := existing.SetSKU(input.SKU); err != nil {
+		s.log(ctx).WarnContext(ctx, "invalid product update attempt", "id", id, "error", err)
+		return domain.Product{}, domain.ErrInvalidProduct
+	}
+	if err := existing.SetBarcode(input.Barcode); err != nil {
+		s.log(ctx).WarnContext(ctx, "invalid product update attempt", "id", id, "error", err)
+		return domain.Product{}, domain.ErrInvalidProduct
+	}
+	if input.CategoryID != existing.CategoryID {
+		if err := s.checkCategory(ctx, input.CategoryID); err != nil {
+			return domain.Product{}, err
+		}
+	}
+	existing.CategoryID = input.CategoryID
 
-	if err := s.repo.Update(ctx, existing); err != nil {
-		s.logger.Error("failed to update product", "id", id, "error", err)
+	// Text that passed screening or manual review is only screened again
+	// when it changes
+	textChanged := existing.Name != input.Name || existing.Description != input.Description
+	existing.Name = input.Name
+	existing.Description = input.Description
+	existing.UpdatedAt = now
+	if textChanged {
+		if err := s.screen(ctx, &existing); err != nil {
+			return domain.Product{}, err
+		}
+	}
+
+	// The event carries the product as it will be once the write bumps its version
+	updated := existing
+	updated.Version++
+	events := []domain.ProductEvent{domain.NewProductEvent(domain.EventProductUpdated, id, &updated, now)}
+	if textChanged {
+		events = append(events, flaggedEvents(updated, now)...)
+	}
+	err = transact(ctx, s.uow, func(ctx context.Context) error {
+		if err := s.repo.Update(ports.WithOutboxEvents(ctx, events), existing); err != nil {
+			return err
+		}
+		return moveCategoryCount(ctx, s.counts, before.CategoryID, existing.CategoryID)
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			s.log(ctx).InfoContext(ctx, "concurrent product update rejected", "id", id, "version", existing.Version)
+			return domain.Product{}, err
+		}
+		if errors.Is(err, domain.ErrDuplicate) {
+			s.log(ctx).InfoContext(ctx, "duplicate product identifier rejected", "id", id, "error", err)
+			return domain.Product{}, err
+		}
+		s.log(ctx).ErrorContext(ctx, "failed to update product", "id", id, "error", err)
 		return domain.Product{}, err
 	}
+	existing.Version++
+	s.audit(ctx, domain.AuditUpdate, &before, &existing, now)
+
+	// Clearing publish_at on a draft publishes it immediately
+	if wasDraft && existing.IsPublished() {
+		s.analytics.Track(ctx, domain.AnalyticsEvent{
+			Type:       domain.EventProductPublished,
+			ProductID:  id,
+			OccurredAt: now,
+		})
+	}
 
 	return existing, nil
 }
 
-func (s *service) Delete(ctx context.Context, id string) error {
-	return s.repo.Delete(ctx, id)
+// SetTranslation screens the translated text like the product's own, so a
+// translation cannot publish what the original would not
+func (s *service) SetTranslation(ctx context.Context, id, locale string, translation domain.Translation, version *int64) (domain.Product, error) {
+	locale, err := domain.NormalizeLocale(locale)
+	if err != nil {
+		return domain.Product{}, err
+	}
+	existing, err := s.repo.GetByID(ports.WithConsistentRead(ctx), id)
+	if err != nil {
+		return domain.Product{}, err
+	}
+	if version != nil && *version != existing.Version {
+		s.log(ctx).InfoContext(ctx, "stale product translation rejected", "id", id, "version", *version, "current", existing.Version)
+		return domain.Product{}, domain.ErrConflict
+	}
+	before := existing
+	// The stored map is shared with before
+	existing.Translations = maps.Clone(existing.Translations)
+
+	if err := existing.SetTranslation(locale, translation); err != nil {
+		s.log(ctx).WarnContext(ctx, "invalid product translation", "id", id, "locale", locale, "error", err)
+		return domain.Product{}, fmt.Errorf("%w: %v", domain.ErrInvalidProduct, err)
+	}
+	// Like the product's own text, an unchanged translation is not
+	// screened again
+	translated := existing.Translations[locale]
+	screened := before.Translations[locale] != translated
+	if screened {
+		if err := s.screenText(ctx, &existing, translated.Name, translated.Description); err != nil {
+			return domain.Product{}, err
+		}
+	}
+	now := time.Now().UTC()
+	existing.UpdatedAt = now
+
+	updated := existing
+	updated.Version++
+	events := []domain.ProductEvent{domain.NewProductEvent(domain.EventProductUpdated, id, &updated, now)}
+	if screened {
+		events = append(events, flaggedEvents(updated, now)...)
+	}
+	if err := s.repo.Update(ports.WithOutboxEvents(ctx, events), existing); err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			s.log(ctx).InfoContext(ctx, "concurrent product translation rejected", "id", id, "version", existing.Version)
+			return domain.Product{}, err
+		}
+		s.log(ctx).ErrorContext(ctx, "failed to save product translation", "id", id, "locale", locale, "error", err)
+		return domain.Product{}, err
+	}
+	existing.Version++
+	s.audit(ctx, domain.AuditUpdate, &before, &existing, now)
+
+	s.log(ctx).InfoContext(ctx, "product translation saved", "id", id, "locale", locale)
+	return existing, nil
+}
+
+func (s *service) Delete(ctx context.Context, id, replacedBy string, version *int64) error {
+	existing, err := s.repo.GetByID(ports.WithConsistentRead(ctx), id)
+	if err != nil {
+		return err
+	}
+	if version != nil && *version != existing.Version {
+		return domain.ErrConflict
+	}
+	if replacedBy != "" {
+		if replacedBy == id {
+			return domain.ErrInvalidReplacement
+		}
+		// Read the replacement as stored, so one created moments ago is
+		// accepted and one deleted moments ago is refused
+		if _, err := s.repo.GetByID(ports.WithConsistentRead(ctx), replacedBy); err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return domain.ErrInvalidReplacement
+			}
+			return err
+		}
+	}
+
+	// The tombstone goes first: if the delete then fails the product is
+	// still served, whereas the reverse order could leave a bare 404
+	tombstone := domain.Tombstone{ID: id, ReplacedBy: replacedBy, DeletedAt: time.Now().UTC(), TenantID: ports.TenantID(ctx)}
+	if err := s.tombstones.Save(ctx, tombstone); err != nil {
+		s.log(ctx).ErrorContext(ctx, "failed to save tombstone", "id", id, "error", err)
+		return err
+	}
+	event := domain.NewProductEvent(domain.EventProductDeleted, id, nil, tombstone.DeletedAt)
+	event.ReplacedBy = replacedBy
+	// The delete is conditional on the version read above, so a product
+	// changed in between is kept and its audit entry stays accurate
+	err = transact(ctx, s.uow, func(ctx context.Context) error {
+		if err := s.repo.Delete(ports.WithOutboxEvent(ctx, event), id, existing.Version); err != nil {
+			return err
+		}
+		return moveCategoryCount(ctx, s.counts, existing.CategoryID, "")
+	})
+	if err != nil {
+		s.log(ctx).ErrorContext(ctx, "failed to delete product", "id", id, "error", err)
+		return err
+	}
+	s.audit(ctx, domain.AuditDelete, &existing, nil, tombstone.DeletedAt)
+
+	s.log(ctx).InfoContext(ctx, "product deleted", "id", id, "replaced_by", replacedBy)
+	return nil
+}
+
+func (s *service) audit(ctx context.Context, action string, before, after *domain.Product, occurredAt time.Time) {
+	recordAudit(ctx, s.auditLog, s.log(ctx), action, before, after, occurredAt)
+}
+
+// newProduct builds a product of the context's tenant from input, checking
+// its attributes, its category and its content. Invalid attributes are
+// reported as domain.ErrInvalidProduct wrapped with the reason.
+func (s productRules) newProduct(ctx context.Context, input ports.ProductInput) (*domain.Product, error) {
+	product, err := domain.NewProduct(s.ids.NewID, input.Name, input.Description, input.Price)
+	if errors.Is(err, domain.ErrProductID) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidProduct, err)
+	}
+	if err := product.SetID(input.ID, s.idFormat); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidProduct, err)
+	}
+	if err := product.SetExpiration(input.ExpiresAt, product.CreatedAt); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidProduct, err)
+	}
+	if input.PublishAt != nil {
+		// A product created with a schedule starts out as a draft
+		product.Status = domain.StatusDraft
+	}
+	if err := product.SchedulePublish(input.PublishAt, product.CreatedAt); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidProduct, err)
+	}
+	if err := product.SetAutoArchive(input.AutoArchiveAt, product.CreatedAt); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidProduct, err)
+	}
+	if err := product.SetCostPrice(input.CostPrice); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidProduct, err)
+	}
+	if err := product.SetTags(input.Tags); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidProduct, err)
+	}
+	if err := product.SetSKU(input.SKU); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidProduct, err)
+	}
+	if err := product.SetBarcode(input.Barcode); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidProduct, err)
+	}
+	if err := s.checkCategory(ctx, input.CategoryID); err != nil {
+		return nil, err
+	}
+	product.CategoryID = input.CategoryID
+	product.TenantID = ports.TenantID(ctx)
+	if err := s.screen(ctx, product); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// checkCategory verifies that a product's category exists. An empty ID
+// leaves the product uncategorized.
+func (s productRules) checkCategory(ctx context.Context, categoryID string) error {
+	if categoryID == "" {
+		return nil
+	}
+	if _, err := s.categories.GetByID(ctx, categoryID); err != nil {
+		if errors.Is(err, domain.ErrCategoryNotFound) {
+			s.log(ctx).WarnContext(ctx, "product references unknown category", "category_id", categoryID)
+			return domain.ErrUnknownCategory
+		}
+		return err
+	}
+	return nil
+}
+
+// screen runs content moderation on the product's text. When the moderator
+// is unavailable the product is held for manual review rather than either
+// blocking the write or letting unscreened text through.
+func (s productRules) screen(ctx context.Context, product *domain.Product) error {
+	return s.screenText(ctx, product, product.Name, product.Description)
+}
+
+// screenText applies the verdict on a name and description, the product's
+// own or a translation of them, to the product
+func (s productRules) screenText(ctx context.Context, product *domain.Product, name, description string) error {
+	verdict, err := s.moderator.Screen(ctx, name, description)
+	if err != nil {
+		s.log(ctx).ErrorContext(ctx, "content moderation failed, holding product for review", "id", product.ID, "error", err)
+		verdict = domain.ModerationVerdict{Decision: domain.DecisionFlag, Reasons: []string{"moderation unavailable"}}
+	}
+
+	event := domain.AnalyticsEvent{
+		ProductID:  product.ID,
+		Properties: map[string]interface{}{"name": name, "reasons": verdict.Reasons},
+		OccurredAt: time.Now().UTC(),
+	}
+	if err := product.ApplyModeration(verdict); err != nil {
+		s.log(ctx).WarnContext(ctx, "product rejected by moderation", "id", product.ID, "reasons", verdict.Reasons)
+		event.Type = domain.EventProductRejected
+		s.analytics.Track(ctx, event)
+		s.recordRejection(ctx, *product, verdict.Reasons, event.OccurredAt)
+		return err
+	}
+	if verdict.Decision == domain.DecisionFlag {
+		s.log(ctx).InfoContext(ctx, "product flagged for review", "id", product.ID, "reasons", verdict.Reasons)
+		event.Type = domain.EventProductFlagged
+		s.analytics.Track(ctx, event)
+	}
+	return nil
+}
+
+// recordRejection puts the rejection of a submission in the outbox, with
+// the product as submitted, since no product write carries it there
+func (s productRules) recordRejection(ctx context.Context, submitted domain.Product, reasons []string, now time.Time) {
+	if s.outbox == nil {
+		return
+	}
+	submitted.ModerationStatus = domain.ModerationRejected
+	submitted.ModerationReasons = reasons
+	event := domain.NewProductEvent(domain.EventProductRejected, submitted.ID, &submitted, now)
+	if err := s.outbox.Append(ctx, event); err != nil {
+		s.log(ctx).ErrorContext(ctx, "failed to record moderation rejection", "id", submitted.ID, "error", err)
+	}
+}
+
+// flaggedEvents returns the event announcing that screening held product
+// for review, if it did, to be written with the product so it is only
+// announced once the product is stored
+func flaggedEvents(product domain.Product, now time.Time) []domain.ProductEvent {
+	if product.ModerationStatus != domain.ModerationPendingReview {
+		return nil
+	}
+	return []domain.ProductEvent{domain.NewProductEvent(domain.EventProductFlagged, product.ID, &product, now)}
+}
+
+// resolveTombstone explains why id is missing: a *domain.TombstoneError
+// pointing at the live end of its redirect chain, or domain.ErrNotFound when
+// the ID never existed
+func (s *service) resolveTombstone(ctx context.Context, id string) error {
+	tombstone, err := s.tombstones.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	// Other tenants' deleted IDs are as unknown as their live ones
+	if tombstone.TenantID != ports.TenantID(ctx) {
+		return domain.ErrNotFound
+	}
+
+	successor := tombstone.ReplacedBy
+	for hops := 0; successor != "" && hops < maxRedirectHops; hops++ {
+		next, err := s.tombstones.Get(ctx, successor)
+		if errors.Is(err, domain.ErrNotFound) {
+			return s.redirectTo(ctx, id, successor)
+		}
+		if err != nil {
+			return err
+		}
+		// The successor was itself merged away or deleted
+		successor = next.ReplacedBy
+	}
+	// The chain ends in a product deleted without a replacement, or is
+	// longer than maxRedirectHops, or loops: the ID is just gone
+	return &domain.TombstoneError{ID: id}
+}
+
+// redirectTo points the deleted id at successor, which has no tombstone,
+// as long as it is still stored; one that expired leaves id gone
+func (s *service) redirectTo(ctx context.Context, id, successor string) error {
+	_, err := s.repo.GetByID(ctx, successor)
+	if errors.Is(err, domain.ErrNotFound) {
+		return &domain.TombstoneError{ID: id}
+	}
+	if err != nil {
+		return err
+	}
+	return &domain.TombstoneError{ID: id, ReplacedBy: successor}
 }
 
 func (s *service) List(ctx context.Context) ([]domain.Product, error) {
@@ -68,10 +637,11 @@ func (s *service) List(ctx context.Context) ([]domain.Product, error) {
 }
 
 func (s *service) ListWithFilters(ctx context.Context, filters ports.ProductFilters) (*ports.ProductListResult, error) {
-	s.logger.Info("listing products with filters",
+	s.log(ctx).InfoContext(ctx, "listing products with filters",
 		"name", filters.Name,
 		"min_price", filters.MinPrice,
 		"max_price", filters.MaxPrice,
+		"tags", filters.Tags,
 		"sort_by", filters.SortBy,
 		"sort_order", filters.SortOrder,
 		"offset", filters.Offset,
@@ -80,10 +650,52 @@ func (s *service) ListWithFilters(ctx context.Context, filters ports.ProductFilt
 
 	result, err := s.repo.ListWithFilters(ctx, filters)
 	if err != nil {
-		s.logger.Error("failed to list products with filters", "error", err)
+		s.log(ctx).ErrorContext(ctx, "failed to list products with filters", "error", err)
 		return nil, err
 	}
 
-	s.logger.Info("successfully listed products", "count", len(result.Products), "total", result.TotalItems)
+	s.log(ctx).InfoContext(ctx, "successfully listed products", "count", len(result.Products), "total", result.TotalItems)
+	s.trackListing(ctx, filters, result)
+	// Only the first page counts as a search, later pages are the same one
+	if filters.Name != "" && filters.Offset == 0 && filters.StartKey == nil {
+		s.searchTerms.RecordSearch(ctx, filters.Name, int64(result.TotalItems))
+	}
 	return result, nil
 }
+
+func (s *service) Count(ctx context.Context, filters ports.ProductFilters) (int, error) {
+	count, err := s.repo.Count(ctx, filters)
+	if err != nil {
+		s.log(ctx).ErrorContext(ctx, "failed to count products", "error", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// trackListing reports the listing to analytics, and a search event as well
+// when a name filter was used
+func (s *service) trackListing(ctx context.Context, filters ports.ProductFilters, result *ports.ProductListResult) {
+	now := time.Now().UTC()
+	properties := map[string]interface{}{
+		"min_price":  filters.MinPrice,
+		"max_price":  filters.MaxPrice,
+		"sort_by":    filters.SortBy,
+		"sort_order": filters.SortOrder,
+		"page":       filters.Page,
+		"limit":      filters.Limit,
+		"results":    result.TotalItems,
+	}
+	if filters.Name != "" {
+		properties["name"] = filters.Name
+		s.analytics.Track(ctx, domain.AnalyticsEvent{
+			Type:       domain.EventProductsSearch,
+			Properties: map[string]interface{}{"query": filters.Name, "results": result.TotalItems},
+			OccurredAt: now,
+		})
+	}
+	s.analytics.Track(ctx, domain.AnalyticsEvent{
+		Type:       domain.EventProductsListed,
+		Properties: properties,
+		OccurredAt: now,
+	})
+}
@@ -0,0 +1,574 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"maps"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// fakeProductRepository keeps products in memory; methods the tests do not
+// use are left to the embedded nil interface
+type fakeProductRepository struct {
+	ports.ProductRepository
+	products map[string]domain.Product
+	// outbox collects the events attached to each write
+	outbox []domain.ProductEvent
+}
+
+func newFakeProductRepository() *fakeProductRepository {
+	return &fakeProductRepository{products: map[string]domain.Product{}}
+}
+
+func (f *fakeProductRepository) Save(ctx context.Context, product domain.Product) error {
+	if _, ok := f.products[product.ID]; ok {
+		return &domain.DuplicateError{Field: domain.FieldID, Value: product.ID}
+	}
+	f.products[product.ID] = product
+	f.outbox = append(f.outbox, ports.OutboxEvents(ctx)...)
+	return nil
+}
+
+func (f *fakeProductRepository) GetByID(ctx context.Context, id string) (domain.Product, error) {
+	product, ok := f.products[id]
+	if !ok || product.TenantID != ports.TenantID(ctx) {
+		return domain.Product{}, domain.ErrNotFound
+	}
+	return product, nil
+}
+
+func (f *fakeProductRepository) GetByIDs(ctx context.Context, ids []string) ([]domain.Product, error) {
+	var products []domain.Product
+	for _, id := range ids {
+		if product, err := f.GetByID(ctx, id); err == nil {
+			products = append(products, product)
+		}
+	}
+	return products, nil
+}
+
+func (f *fakeProductRepository) GetBySKU(ctx context.Context, sku string) (domain.Product, error) {
+	for _, product := range f.products {
+		if product.SKU == sku && product.TenantID == ports.TenantID(ctx) {
+			return product, nil
+		}
+	}
+	return domain.Product{}, domain.ErrNotFound
+}
+
+func (f *fakeProductRepository) Update(ctx context.Context, product domain.Product) error {
+	if f.products[product.ID].Version != product.Version {
+		return domain.ErrConflict
+	}
+	product.Version++
+	f.products[product.ID] = product
+	f.outbox = append(f.outbox, ports.OutboxEvents(ctx)...)
+	return nil
+}
+
+func (f *fakeProductRepository) Delete(ctx context.Context, id string, version int64) error {
+	if f.products[id].Version != version {
+		return domain.ErrConflict
+	}
+	delete(f.products, id)
+	f.outbox = append(f.outbox, ports.OutboxEvents(ctx)...)
+	return nil
+}
+
+type fakeTombstoneRepository struct {
+	tombstones map[string]domain.Tombstone
+}
+
+func (f *fakeTombstoneRepository) Save(ctx context.Context, tombstone domain.Tombstone) error {
+	f.tombstones[tombstone.ID] = tombstone
+	return nil
+}
+
+func (f *fakeTombstoneRepository) Get(ctx context.Context, id string) (domain.Tombstone, error) {
+	tombstone, ok := f.tombstones[id]
+	if !ok {
+		return domain.Tombstone{}, domain.ErrNotFound
+	}
+	return tombstone, nil
+}
+
+func (f *fakeTombstoneRepository) Delete(ctx context.Context, id string) error {
+	delete(f.tombstones, id)
+	return nil
+}
+
+// fakeAuditLog keeps the recorded entries in order
+type fakeAuditLog struct {
+	entries []domain.AuditEntry
+}
+
+func (f *fakeAuditLog) Record(ctx context.Context, entry domain.AuditEntry) error {
+	f.entries = append(f.entries, entry)
+	return nil
+}
+
+func (f *fakeAuditLog) History(ctx context.Context, productID string, limit int) ([]domain.AuditEntry, error) {
+	var history []domain.AuditEntry
+	for i := len(f.entries) - 1; i >= 0 && len(history) < limit; i-- {
+		if entry := f.entries[i]; entry.ProductID == productID && entry.TenantID == ports.TenantID(ctx) {
+			history = append(history, entry)
+		}
+	}
+	return history, nil
+}
+
+// randomIDs names products with random UUIDs
+type randomIDs struct{}
+
+func (randomIDs) NewID() (string, error) {
+	return uuid.NewString(), nil
+}
+
+type allowAllModerator struct{}
+
+func (allowAllModerator) Screen(ctx context.Context, name, description string) (domain.ModerationVerdict, error) {
+	return domain.ModerationVerdict{Decision: domain.DecisionAllow}, nil
+}
+
+func newTestProductService(repo ports.ProductRepository) ports.ProductService {
+	return newAuditedProductService(repo, &fakeAuditLog{})
+}
+
+func newAuditedProductService(repo ports.ProductRepository, auditLog ports.AuditLogger) ports.ProductService {
+	return NewProductService(repo, &fakeTombstoneRepository{tombstones: map[string]domain.Tombstone{}}, allowAllModerator{},
+		&recordingPublisher{}, nil, nil, nil, nil, nil, auditLog, randomIDs{}, domain.ProductIDFormat{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestProductService_GetMany(t *testing.T) {
+	repo := newFakeProductRepository()
+	repo.products["p1"] = domain.Product{ID: "p1", Name: "Laptop"}
+	repo.products["p2"] = domain.Product{ID: "p2", Name: "Mouse"}
+	service := newTestProductService(repo)
+
+	products, missing, err := service.GetMany(context.Background(), []string{"p2", "gone", "p1", "p2"})
+	require.NoError(t, err)
+	require.Len(t, products, 2)
+	assert.Equal(t, "p2", products[0].ID, "products keep the order they were asked for")
+	assert.Equal(t, "p1", products[1].ID)
+	assert.Equal(t, []string{"gone"}, missing)
+}
+
+type fixedIDs string
+
+func (f fixedIDs) NewID() (string, error) {
+	return string(f), nil
+}
+
+func TestProductService_Create_GeneratedID(t *testing.T) {
+	repo := newFakeProductRepository()
+	service := NewProductService(repo, &fakeTombstoneRepository{tombstones: map[string]domain.Tombstone{}}, allowAllModerator{},
+		&recordingPublisher{}, nil, nil, nil, nil, nil, &fakeAuditLog{}, fixedIDs("prod_01ARYZ6S41TSV4RRFFQ69G5FAV"), domain.ProductIDFormat{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	price := domain.Money{Amount: 99900, Currency: "USD"}
+
+	created, err := service.Create(context.Background(), ports.ProductInput{Name: "Laptop", Price: price})
+	require.NoError(t, err)
+	assert.Equal(t, "prod_01ARYZ6S41TSV4RRFFQ69G5FAV", created.ID)
+	assert.Contains(t, repo.products, created.ID)
+}
+
+func TestProductService_Create_ClientID(t *testing.T) {
+	repo := newFakeProductRepository()
+	format, err := domain.NewProductIDFormat(`erp-[0-9]+`)
+	require.NoError(t, err)
+	service := NewProductService(repo, &fakeTombstoneRepository{tombstones: map[string]domain.Tombstone{}}, allowAllModerator{},
+		&recordingPublisher{}, nil, nil, nil, nil, nil, &fakeAuditLog{}, randomIDs{}, format, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+	price := domain.Money{Amount: 99900, Currency: "USD"}
+
+	created, err := service.Create(ctx, ports.ProductInput{ID: "erp-1042", Name: "Laptop", Price: price})
+	require.NoError(t, err)
+	assert.Equal(t, "erp-1042", created.ID)
+
+	_, err = service.Create(ctx, ports.ProductInput{ID: "erp-1042", Name: "Laptop", Price: price})
+	assert.ErrorIs(t, err, domain.ErrDuplicate)
+	_, err = service.Create(ctx, ports.ProductInput{ID: "sku-1042", Name: "Laptop", Price: price})
+	assert.ErrorIs(t, err, domain.ErrInvalidProduct)
+
+	generated, err := service.Create(ctx, ports.ProductInput{Name: "Mouse", Price: price})
+	require.NoError(t, err)
+	assert.NotEqual(t, "", generated.ID)
+}
+
+func TestProductService_LogsWithRequestLogger(t *testing.T) {
+	service := newTestProductService(newFakeProductRepository())
+	var buf strings.Builder
+	requestLogger := slog.New(slog.NewTextHandler(&buf, nil)).With("route", "/api/v1/products")
+	ctx := ports.WithLogger(context.Background(), requestLogger)
+
+	_, err := service.Create(ctx, ports.ProductInput{Name: ""})
+	require.Error(t, err)
+	assert.Contains(t, buf.String(), "invalid product creation attempt")
+	assert.Contains(t, buf.String(), "route=/api/v1/products")
+}
+
+func TestProductService_Clone(t *testing.T) {
+	repo := newFakeProductRepository()
+	service := newTestProductService(repo)
+	ctx := context.Background()
+	cost := domain.NewDecimal(750, 2)
+	original, err := service.Create(ctx, ports.ProductInput{
+		Name: "Hat", Description: "Wool hat", Price: domain.Money{Amount: 1999, Currency: "USD"},
+		Tags: []string{"winter"}, SKU: "HAT-RED", CostPrice: &cost,
+	})
+	require.NoError(t, err)
+	original, err = service.SetTranslation(ctx, original.ID, "es", domain.Translation{Name: "Gorro"}, nil)
+	require.NoError(t, err)
+
+	price := domain.Money{Amount: 2499, Currency: "USD"}
+	clone, err := service.Clone(ctx, original.ID, ports.CloneInput{NameSuffix: " (blue)", Price: &price, SKU: "HAT-BLUE"})
+	require.NoError(t, err)
+
+	assert.NotEqual(t, original.ID, clone.ID)
+	assert.Equal(t, "Hat (blue)", clone.Name)
+	assert.Equal(t, "Wool hat", clone.Description)
+	assert.Equal(t, price, clone.Price)
+	assert.Equal(t, &domain.Money{Amount: 750, Currency: "USD"}, clone.CostPrice)
+	assert.Equal(t, []string{"winter"}, clone.Tags)
+	assert.Equal(t, "HAT-BLUE", clone.SKU)
+	assert.Equal(t, int64(1), clone.Version)
+	assert.Equal(t, "Gorro", clone.Translations["es"].Name)
+	assert.True(t, clone.CreatedAt.After(original.CreatedAt) || clone.CreatedAt.Equal(original.CreatedAt))
+	assert.Contains(t, repo.products, clone.ID)
+
+	// A cost in dollars is not carried over to a price in euros
+	euros := domain.Money{Amount: 2299, Currency: "EUR"}
+	_, err = service.Clone(ctx, original.ID, ports.CloneInput{Price: &euros})
+	assert.ErrorIs(t, err, domain.ErrInvalidProduct)
+	eurosCost := domain.NewDecimal(7, 0)
+	clone, err = service.Clone(ctx, original.ID, ports.CloneInput{Price: &euros, CostPrice: &eurosCost})
+	require.NoError(t, err)
+	assert.Equal(t, &domain.Money{Amount: 700, Currency: "EUR"}, clone.CostPrice)
+
+	_, err = service.Clone(ctx, "missing", ports.CloneInput{})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	empty := ""
+	_, err = service.Clone(ctx, original.ID, ports.CloneInput{Name: &empty})
+	assert.ErrorIs(t, err, domain.ErrInvalidProduct)
+}
+
+func TestProductService_WritesLifecycleEventsToOutbox(t *testing.T) {
+	repo := newFakeProductRepository()
+	service := newTestProductService(repo)
+	ctx := context.Background()
+
+	created, err := service.Create(ctx, ports.ProductInput{Name: "Laptop", Price: domain.Money{Amount: 99900, Currency: "USD"}})
+	require.NoError(t, err)
+	updated, err := service.Update(ctx, created.ID, ports.ProductInput{Name: "Laptop Pro", Price: domain.Money{Amount: 129900, Currency: "USD"}})
+	require.NoError(t, err)
+	require.NoError(t, service.Delete(ctx, created.ID, "", nil))
+
+	events := repo.outbox
+	require.Len(t, events, 3)
+	assert.Equal(t, domain.EventProductCreated, events[0].Type)
+	assert.Equal(t, "Laptop", events[0].Product.Name)
+	assert.Equal(t, domain.EventProductUpdated, events[1].Type)
+	assert.Equal(t, "Laptop Pro", events[1].Product.Name)
+	assert.Equal(t, updated.Version, events[1].Product.Version)
+	assert.Equal(t, domain.EventProductDeleted, events[2].Type)
+	assert.Nil(t, events[2].Product)
+	for _, event := range events {
+		assert.Equal(t, created.ID, event.ProductID)
+		assert.NotEmpty(t, event.ID)
+	}
+}
+
+func TestProductService_Delete_Version(t *testing.T) {
+	repo := newFakeProductRepository()
+	service := newTestProductService(repo)
+	ctx := context.Background()
+
+	created, err := service.Create(ctx, ports.ProductInput{Name: "Laptop", Price: domain.Money{Amount: 99900, Currency: "USD"}})
+	require.NoError(t, err)
+	_, err = service.Update(ctx, created.ID, ports.ProductInput{Name: "Laptop Pro", Price: domain.Money{Amount: 129900, Currency: "USD"}})
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, service.Delete(ctx, created.ID, "", &created.Version), domain.ErrConflict, "the product changed since it was read")
+	_, err = service.Get(ctx, created.ID)
+	require.NoError(t, err)
+
+	current := created.Version + 1
+	require.NoError(t, service.Delete(ctx, created.ID, "", &current))
+	_, err = repo.GetByID(ctx, created.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestProductService_Get_FollowsTombstones(t *testing.T) {
+	repo := newFakeProductRepository()
+	repo.products["live"] = domain.Product{ID: "live", Name: "Laptop"}
+	tombstones := &fakeTombstoneRepository{tombstones: map[string]domain.Tombstone{
+		"merged":  {ID: "merged", ReplacedBy: "merged2"},
+		"merged2": {ID: "merged2", ReplacedBy: "live"},
+		"gone":    {ID: "gone", ReplacedBy: "deleted"},
+		"deleted": {ID: "deleted"},
+		"stale":   {ID: "stale", ReplacedBy: "expired"},
+		"loop-a":  {ID: "loop-a", ReplacedBy: "loop-b"},
+		"loop-b":  {ID: "loop-b", ReplacedBy: "loop-a"},
+	}}
+	service := NewProductService(repo, tombstones, allowAllModerator{},
+		&recordingPublisher{}, nil, nil, nil, nil, nil, &fakeAuditLog{}, randomIDs{}, domain.ProductIDFormat{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	tests := []struct {
+		id         string
+		replacedBy string
+	}{
+		{"merged", "live"},
+		{"gone", ""},    // the successor was deleted without a replacement
+		{"stale", ""},   // the successor expired, leaving no tombstone
+		{"loop-a", ""},  // the chain never reaches a stored product
+		{"deleted", ""}, // deleted without a replacement
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			_, err := service.Get(context.Background(), tt.id)
+			var tombstone *domain.TombstoneError
+			require.ErrorAs(t, err, &tombstone)
+			assert.Equal(t, tt.replacedBy, tombstone.ReplacedBy)
+		})
+	}
+}
+
+func TestProductService_FailedWriteLeavesNoEvent(t *testing.T) {
+	repo := newFakeProductRepository()
+	service := newTestProductService(repo)
+
+	created, err := service.Create(context.Background(), ports.ProductInput{Name: "Laptop", Price: domain.Money{Amount: 99900, Currency: "USD"}})
+	require.NoError(t, err)
+	stale := created.Version - 1
+	_, err = service.Update(context.Background(), created.ID, ports.ProductInput{Name: "Laptop Pro", Price: domain.Money{Amount: 129900, Currency: "USD"}, Version: &stale})
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	assert.Len(t, repo.outbox, 1)
+}
+
+func TestProductService_TenantIsolation(t *testing.T) {
+	repo := newFakeProductRepository()
+	service := newTestProductService(repo)
+	acme := ports.WithTenant(context.Background(), "acme")
+	globex := ports.WithTenant(context.Background(), "globex")
+
+	created, err := service.Create(acme, ports.ProductInput{Name: "Laptop", Price: domain.Money{Amount: 99900, Currency: "USD"}})
+	require.NoError(t, err)
+	assert.Equal(t, "acme", created.TenantID)
+
+	_, err = service.Get(globex, created.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = service.Get(context.Background(), created.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.ErrorIs(t, service.Delete(globex, created.ID, "", nil), domain.ErrNotFound)
+
+	// A deleted product is gone for its tenant and unknown to the others
+	require.NoError(t, service.Delete(acme, created.ID, "", nil))
+	_, err = service.Get(acme, created.ID)
+	assert.ErrorIs(t, err, domain.ErrGone)
+	_, err = service.Get(globex, created.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestProductService_AuditsWrites(t *testing.T) {
+	repo := newFakeProductRepository()
+	auditLog := &fakeAuditLog{}
+	service := newAuditedProductService(repo, auditLog)
+	ctx := ports.WithActor(context.Background(), "user-1")
+
+	created, err := service.Create(ctx, ports.ProductInput{Name: "Laptop", Price: domain.Money{Amount: 99900, Currency: "USD"}})
+	require.NoError(t, err)
+	_, err = service.Update(ctx, created.ID, ports.ProductInput{Name: "Laptop", Price: domain.Money{Amount: 89900, Currency: "USD"}})
+	require.NoError(t, err)
+	require.NoError(t, service.Delete(context.Background(), created.ID, "", nil))
+
+	require.Len(t, auditLog.entries, 3)
+	create, update, remove := auditLog.entries[0], auditLog.entries[1], auditLog.entries[2]
+	assert.Equal(t, domain.AuditCreate, create.Action)
+	assert.Equal(t, "user-1", create.Actor)
+	assert.Equal(t, domain.AuditUpdate, update.Action)
+	assert.Contains(t, update.Changes, domain.FieldChange{Field: "price", Before: []byte(`{"amount":99900,"currency":"USD"}`), After: []byte(`{"amount":89900,"currency":"USD"}`)})
+	assert.Equal(t, domain.AuditDelete, remove.Action)
+	assert.Equal(t, domain.ActorAnonymous, remove.Actor)
+	for _, entry := range auditLog.entries {
+		assert.Equal(t, created.ID, entry.ProductID)
+	}
+
+	// The history outlives the product
+	history, err := NewAuditService(auditLog, repo, slog.New(slog.NewTextHandler(io.Discard, nil))).History(context.Background(), created.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.Equal(t, domain.AuditDelete, history[0].Action)
+}
+
+func TestProductService_NormalizesTags(t *testing.T) {
+	repo := newFakeProductRepository()
+	service := newTestProductService(repo)
+	ctx := context.Background()
+
+	created, err := service.Create(ctx, ports.ProductInput{Name: "Headset", Price: domain.Money{Amount: 8000, Currency: "USD"}, Tags: []string{"Gaming", " audio ", "gaming"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gaming", "audio"}, repo.products[created.ID].Tags)
+
+	// Updates replace the tags, so omitting them clears them
+	updated, err := service.Update(ctx, created.ID, ports.ProductInput{Name: "Headset", Price: domain.Money{Amount: 8000, Currency: "USD"}})
+	require.NoError(t, err)
+	assert.Empty(t, updated.Tags)
+
+	_, err = service.Create(ctx, ports.ProductInput{Name: "Cable", Price: domain.Money{Amount: 500, Currency: "USD"}, Tags: []string{"usb,c"}})
+	assert.ErrorIs(t, err, domain.ErrInvalidProduct)
+}
+
+func TestProductService_SchedulePublish(t *testing.T) {
+	repo := newFakeProductRepository()
+	service := newTestProductService(repo)
+	ctx := context.Background()
+	price := domain.Money{Amount: 8000, Currency: "USD"}
+	launch := time.Now().Add(time.Hour)
+
+	draft, err := service.Create(ctx, ports.ProductInput{Name: "Headset", Price: price, PublishAt: &launch})
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusDraft, draft.Status)
+
+	published, err := service.Update(ctx, draft.ID, ports.ProductInput{Name: "Headset", Price: price})
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusPublished, published.Status)
+
+	// Scheduling would take a published product back to draft
+	_, err = service.Update(ctx, draft.ID, ports.ProductInput{Name: "Headset", Price: price, PublishAt: &launch})
+	assert.ErrorIs(t, err, domain.ErrInvalidTransition)
+	assert.Equal(t, domain.StatusPublished, repo.products[draft.ID].Status)
+}
+
+func TestProductService_SKU(t *testing.T) {
+	repo := newFakeProductRepository()
+	service := newTestProductService(repo)
+	ctx := context.Background()
+
+	created, err := service.Create(ctx, ports.ProductInput{Name: "Laptop", Price: domain.Money{Amount: 99900, Currency: "USD"}, SKU: " lap-1 ", Barcode: "4006381333931"})
+	require.NoError(t, err)
+	assert.Equal(t, "LAP-1", created.SKU)
+
+	found, err := service.GetBySKU(ctx, "Lap-1")
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, found.ID)
+	_, err = service.GetBySKU(ctx, "not a sku")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	_, err = service.Create(ctx, ports.ProductInput{Name: "Mouse", Price: domain.Money{Amount: 2500, Currency: "USD"}, Barcode: "4006381333932"})
+	assert.ErrorIs(t, err, domain.ErrInvalidProduct)
+}
+
+// blockingModerator rejects any text containing its term
+type blockingModerator string
+
+func (m blockingModerator) Screen(ctx context.Context, name, description string) (domain.ModerationVerdict, error) {
+	if strings.Contains(name+" "+description, string(m)) {
+		return domain.ModerationVerdict{Decision: domain.DecisionReject, Reasons: []string{"blocked: " + string(m)}}, nil
+	}
+	return domain.ModerationVerdict{Decision: domain.DecisionAllow}, nil
+}
+
+func TestProductService_SetTranslation(t *testing.T) {
+	repo := newFakeProductRepository()
+	repo.products["p1"] = domain.Product{ID: "p1", Name: "Laptop", Description: "Fast", Version: 3}
+	auditLog := &fakeAuditLog{}
+	outbox := &fakeOutboxRepository{}
+	service := NewProductService(repo, &fakeTombstoneRepository{tombstones: map[string]domain.Tombstone{}}, blockingModerator("scam"),
+		&recordingPublisher{}, outbox, nil, nil, nil, nil, auditLog, randomIDs{}, domain.ProductIDFormat{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	updated, err := service.SetTranslation(ctx, "p1", "pt_br", domain.Translation{Name: " Portátil ", Description: "Rápido"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]domain.Translation{"pt-BR": {Name: "Portátil", Description: "Rápido"}}, repo.products["p1"].Translations)
+	assert.Equal(t, int64(4), updated.Version)
+	assert.Equal(t, "Laptop", updated.Name, "the product's own text is kept")
+	require.Len(t, auditLog.entries, 1)
+
+	version := int64(3)
+	_, err = service.SetTranslation(ctx, "p1", "es", domain.Translation{Name: "Portátil"}, &version)
+	assert.ErrorIs(t, err, domain.ErrConflict)
+
+	_, err = service.SetTranslation(ctx, "p1", "not a locale", domain.Translation{Name: "x"}, nil)
+	assert.ErrorIs(t, err, domain.ErrInvalidLocale)
+	_, err = service.SetTranslation(ctx, "p1", "es", domain.Translation{Name: " "}, nil)
+	assert.ErrorIs(t, err, domain.ErrInvalidProduct)
+	_, err = service.SetTranslation(ctx, "missing", "es", domain.Translation{Name: "x"}, nil)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	// Translations are screened like the product's own text
+	_, err = service.SetTranslation(ctx, "p1", "es", domain.Translation{Name: "Portátil", Description: "scam"}, nil)
+	var rejected *domain.ContentRejectedError
+	assert.ErrorAs(t, err, &rejected)
+	assert.NotContains(t, repo.products["p1"].Translations, "es")
+	// The rejection is recorded for notifications, though nothing was written
+	if assert.Len(t, outbox.pending, 1) {
+		assert.Equal(t, domain.EventProductRejected, outbox.pending[0].Type)
+		assert.Equal(t, []string{"blocked: scam"}, outbox.pending[0].Product.ModerationReasons)
+	}
+}
+
+// snapshotUnitOfWork undoes the fake repository's writes when fn fails
+type snapshotUnitOfWork struct {
+	repo *fakeProductRepository
+}
+
+func (u snapshotUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	products, outbox := maps.Clone(u.repo.products), len(u.repo.outbox)
+	if err := fn(ctx); err != nil {
+		u.repo.products, u.repo.outbox = products, u.repo.outbox[:outbox]
+		return err
+	}
+	return nil
+}
+
+// fakeCategoryCounts keeps counts per category, failing with err when set
+type fakeCategoryCounts struct {
+	counts map[string]int
+	err    error
+}
+
+func (f *fakeCategoryCounts) AdjustProductCount(ctx context.Context, categoryID string, delta int) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.counts[categoryID] += delta
+	return nil
+}
+
+func TestProductService_CategoryCounts(t *testing.T) {
+	repo := newFakeProductRepository()
+	counts := &fakeCategoryCounts{counts: map[string]int{}}
+	service := NewProductService(repo, &fakeTombstoneRepository{tombstones: map[string]domain.Tombstone{}}, allowAllModerator{},
+		&recordingPublisher{}, nil, nil, &countingCategories{}, counts, snapshotUnitOfWork{repo}, &fakeAuditLog{}, randomIDs{}, domain.ProductIDFormat{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+	price := domain.Money{Amount: 99900, Currency: "USD"}
+
+	created, err := service.Create(ctx, ports.ProductInput{Name: "Laptop", Price: price, CategoryID: "electronics"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, counts.counts["electronics"])
+
+	_, err = service.Update(ctx, created.ID, ports.ProductInput{Name: "Laptop", Price: price})
+	require.NoError(t, err)
+	assert.Equal(t, 0, counts.counts["electronics"], "leaving the category")
+
+	_, err = service.Update(ctx, created.ID, ports.ProductInput{Name: "Laptop", Price: price, CategoryID: "electronics"})
+	require.NoError(t, err)
+	require.NoError(t, service.Delete(ctx, created.ID, "", nil))
+	assert.Equal(t, 0, counts.counts["electronics"])
+
+	// A failed count undoes the product write with it
+	counts.err = errors.New("count failed")
+	_, err = service.Create(ctx, ports.ProductInput{Name: "Mouse", Price: price, CategoryID: "electronics"})
+	assert.ErrorIs(t, err, counts.err)
+	assert.Empty(t, repo.products)
+	assert.Len(t, repo.outbox, 4, "only the events of the committed writes")
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+type publishingService struct {
+	repo      ports.PublishingRepository
+	analytics ports.AnalyticsPublisher
+	logger    *slog.Logger
+	now       func() time.Time
+}
+
+func NewPublishingService(repo ports.PublishingRepository, analytics ports.AnalyticsPublisher, logger *slog.Logger) ports.PublishingService {
+	return &publishingService{
+		repo:      repo,
+		analytics: analytics,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// PublishDue publishes every draft whose publish_at has passed, writing a
+// product.updated and a product.published event with each. A failure on one product does not stop
+// the others; the job retries them on its next run.
+func (s *publishingService) PublishDue(ctx context.Context) error {
+	now := s.now().UTC()
+	due, err := s.repo.ListDueForPublishing(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	published := 0
+	for _, product := range due {
+		// Jobs see every tenant; each write is scoped to the product's
+		updated := product
+		updated.Publish(now)
+		updated.Version++
+		productCtx := withLifecycleEvent(ports.WithTenant(ctx, product.TenantID), updated, domain.EventProductPublished, now)
+		if err := s.repo.MarkPublished(productCtx, product, now); err != nil {
+			if errors.Is(err, domain.ErrConflict) {
+				s.logger.DebugContext(ctx, "draft no longer due, skipping", "id", product.ID)
+				continue
+			}
+			s.logger.ErrorContext(ctx, "failed to publish product", "id", product.ID, "error", err)
+			errs = append(errs, err)
+			continue
+		}
+
+		published++
+		s.analytics.Track(ctx, domain.AnalyticsEvent{
+			Type:       domain.EventProductPublished,
+			ProductID:  product.ID,
+			Properties: map[string]interface{}{"publish_at": product.PublishAt},
+			OccurredAt: now,
+		})
+	}
+
+	if published > 0 {
+		s.logger.InfoContext(ctx, "published scheduled products", "count", published)
+	}
+	return errors.Join(errs...)
+}
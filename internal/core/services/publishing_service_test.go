@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+type fakePublishingRepository struct {
+	due       []domain.Product
+	failures  map[string]error
+	published []string
+	events    []domain.ProductEvent
+}
+
+func (f *fakePublishingRepository) ListDueForPublishing(ctx context.Context, now time.Time) ([]domain.Product, error) {
+	return f.due, nil
+}
+
+func (f *fakePublishingRepository) MarkPublished(ctx context.Context, product domain.Product, now time.Time) error {
+	if err := f.failures[product.ID]; err != nil {
+		return err
+	}
+	f.published = append(f.published, product.ID)
+	f.events = append(f.events, ports.OutboxEvents(ctx)...)
+	return nil
+}
+
+type recordingPublisher struct {
+	events []domain.AnalyticsEvent
+}
+
+func (r *recordingPublisher) Track(ctx context.Context, event domain.AnalyticsEvent) {
+	r.events = append(r.events, event)
+}
+
+func TestPublishDue(t *testing.T) {
+	repo := &fakePublishingRepository{
+		due: []domain.Product{{ID: "a", Status: domain.StatusDraft, Version: 3}, {ID: "b"}, {ID: "c"}},
+		failures: map[string]error{
+			"b": domain.ErrConflict,
+			"c": errors.New("throttled"),
+		},
+	}
+	events := &recordingPublisher{}
+	service := NewPublishingService(repo, events, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	err := service.PublishDue(context.Background())
+
+	// Conflicts are skipped, other failures are reported for the next run
+	assert.ErrorContains(t, err, "throttled")
+	assert.Equal(t, []string{"a"}, repo.published)
+	if assert.Len(t, events.events, 1) {
+		assert.Equal(t, domain.EventProductPublished, events.events[0].Type)
+		assert.Equal(t, "a", events.events[0].ProductID)
+	}
+	// The update and the publication are written with it
+	if assert.Len(t, repo.events, 2) {
+		assert.Equal(t, domain.EventProductUpdated, repo.events[0].Type)
+		assert.Equal(t, domain.StatusPublished, repo.events[0].Product.Status)
+		assert.Equal(t, int64(4), repo.events[0].Product.Version)
+		assert.Equal(t, domain.EventProductPublished, repo.events[1].Type)
+	}
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+type recommendationService struct {
+	recommender ports.Recommender
+	products    ports.ProductRepository
+	logger      *slog.Logger
+}
+
+func NewRecommendationService(recommender ports.Recommender, products ports.ProductRepository, logger *slog.Logger) ports.RecommendationService {
+	return &recommendationService{
+		recommender: recommender,
+		products:    products,
+		logger:      logger,
+	}
+}
+
+func (s *recommendationService) Recommendations(ctx context.Context, productID string, limit int) ([]ports.RecommendedProduct, error) {
+	if _, err := s.products.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	// Ask for a few extra so deleted or expired products can be skipped
+	// without returning a short list
+	recommendations, err := s.recommender.Recommend(ctx, productID, limit*2)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to get recommendations", "id", productID, "error", err)
+		return nil, err
+	}
+
+	recommended := make([]ports.RecommendedProduct, 0, limit)
+	for _, item := range recommendations {
+		if len(recommended) == limit {
+			break
+		}
+		product, err := s.products.GetByID(ctx, item.ProductID)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		recommended = append(recommended, ports.RecommendedProduct{Product: product, Score: item.Score})
+	}
+	return recommended, nil
+}
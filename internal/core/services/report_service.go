@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+// maxLowMarginProducts bounds the product list stored in a margin report
+const maxLowMarginProducts = 100
+
+type reportService struct {
+	products           ports.CostedProductRepository
+	categories         ports.CategoryRepository
+	reports            ports.ReportRepository
+	lowMarginThreshold float64
+	logger             *slog.Logger
+	now                func() time.Time
+}
+
+func NewReportService(products ports.CostedProductRepository, categories ports.CategoryRepository, reports ports.ReportRepository, lowMarginThreshold float64, logger *slog.Logger) ports.ReportService {
+	return &reportService{
+		products:           products,
+		categories:         categories,
+		reports:            reports,
+		lowMarginThreshold: lowMarginThreshold,
+		logger:             logger,
+		now:                time.Now,
+	}
+}
+
+// GenerateMarginReport summarizes the margins of every costed product and
+// stores the result as the latest margin report
+func (s *reportService) GenerateMarginReport(ctx context.Context) error {
+	products, err := s.products.ListCosted(ctx)
+	if err != nil {
+		return err
+	}
+
+	categories, err := s.categories.List(ctx)
+	if err != nil {
+		return err
+	}
+	names := make(map[string]string, len(categories))
+	for _, category := range categories {
+		names[category.ID] = category.Name
+	}
+	// Products in a deleted or unknown category fall back to
+	// domain.UncategorizedCategory
+	productCategory := func(product domain.Product) string {
+		return names[product.CategoryID]
+	}
+
+	report := domain.BuildMarginReport(products, productCategory, s.lowMarginThreshold, maxLowMarginProducts, s.now().UTC())
+	if err := s.reports.SaveMarginReport(ctx, report); err != nil {
+		return err
+	}
+
+	s.logger.InfoContext(ctx, "margin report generated", "products", len(products), "low_margin", len(report.LowMarginProducts))
+	return nil
+}
+
+func (s *reportService) MarginReport(ctx context.Context) (domain.MarginReport, error) {
+	return s.reports.LatestMarginReport(ctx)
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+type reviewService struct {
+	reviews  ports.ReviewRepository
+	products ports.ProductRepository
+	logger   *slog.Logger
+}
+
+func NewReviewService(reviews ports.ReviewRepository, products ports.ProductRepository, logger *slog.Logger) ports.ReviewService {
+	return &reviewService{
+		reviews:  reviews,
+		products: products,
+		logger:   logger,
+	}
+}
+
+// Create records a review by the caller and adds its rating to the
+// product's totals
+func (s *reviewService) Create(ctx context.Context, productID string, input ports.ReviewInput) (domain.Review, error) {
+	if _, err := s.products.GetByID(ctx, productID); err != nil {
+		return domain.Review{}, err
+	}
+	review, err := domain.NewReview(productID, ports.TenantID(ctx), ports.Actor(ctx), input.Rating, input.Comment, time.Now())
+	if err != nil {
+		return domain.Review{}, err
+	}
+
+	if err := s.reviews.Create(ctx, review); err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			s.logger.ErrorContext(ctx, "failed to create review", "product_id", productID, "error", err)
+		}
+		return domain.Review{}, err
+	}
+	s.logger.InfoContext(ctx, "review created", "product_id", productID, "review_id", review.ID, "rating", review.Rating)
+	return review, nil
+}
+
+func (s *reviewService) Get(ctx context.Context, productID, id string) (domain.Review, error) {
+	if _, err := s.products.GetByID(ctx, productID); err != nil {
+		return domain.Review{}, err
+	}
+	return s.reviews.Get(ctx, productID, id)
+}
+
+// List returns a page of the product's reviews, newest first, with the
+// rating summary kept on the product
+func (s *reviewService) List(ctx context.Context, productID, after string, limit int) (ports.ReviewPage, error) {
+	product, err := s.products.GetByID(ctx, productID)
+	if err != nil {
+		return ports.ReviewPage{}, err
+	}
+	reviews, err := s.reviews.List(ctx, productID, after, limit)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list reviews", "product_id", productID, "error", err)
+		return ports.ReviewPage{}, err
+	}
+
+	page := ports.ReviewPage{ProductID: productID, Rating: product.Rating(), Reviews: reviews}
+	if len(reviews) == limit {
+		page.NextAfter = reviews[len(reviews)-1].ID
+	}
+	return page, nil
+}
+
+// Update replaces the rating and comment of one of the caller's reviews,
+// moving the product's totals by the change in rating
+func (s *reviewService) Update(ctx context.Context, productID, id string, input ports.ReviewInput) (domain.Review, error) {
+	review, err := s.authoredReview(ctx, productID, id)
+	if err != nil {
+		return domain.Review{}, err
+	}
+	previousRating := review.Rating
+	if err := review.Edit(input.Rating, input.Comment, time.Now()); err != nil {
+		return domain.Review{}, err
+	}
+
+	if err := s.reviews.Update(ctx, review, previousRating); err != nil {
+		if !errors.Is(err, domain.ErrNotFound) && !errors.Is(err, domain.ErrConflict) {
+			s.logger.ErrorContext(ctx, "failed to update review", "product_id", productID, "review_id", id, "error", err)
+		}
+		return domain.Review{}, err
+	}
+	s.logger.InfoContext(ctx, "review updated", "product_id", productID, "review_id", id, "rating", review.Rating)
+	return review, nil
+}
+
+// Delete removes one of the caller's reviews and its rating from the
+// product's totals
+func (s *reviewService) Delete(ctx context.Context, productID, id string) error {
+	review, err := s.authoredReview(ctx, productID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.reviews.Delete(ctx, review); err != nil {
+		if !errors.Is(err, domain.ErrNotFound) && !errors.Is(err, domain.ErrConflict) {
+			s.logger.ErrorContext(ctx, "failed to delete review", "product_id", productID, "review_id", id, "error", err)
+		}
+		return err
+	}
+	s.logger.InfoContext(ctx, "review deleted", "product_id", productID, "review_id", id)
+	return nil
+}
+
+// authoredReview reads a review of the product for the caller to change.
+// Without authentication every caller is anonymous, and so is every author.
+func (s *reviewService) authoredReview(ctx context.Context, productID, id string) (domain.Review, error) {
+	review, err := s.Get(ports.WithConsistentRead(ctx), productID, id)
+	if err != nil {
+		return domain.Review{}, err
+	}
+	author := ports.Actor(ctx)
+	if author == "" {
+		author = domain.ActorAnonymous
+	}
+	if review.Author != author {
+		return domain.Review{}, domain.ErrNotReviewAuthor
+	}
+	return review, nil
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// fakeReviewRepository keeps the rating totals on the products of a
+// fakeProductRepository, as the DynamoDB repository does
+type fakeReviewRepository struct {
+	products *fakeProductRepository
+	reviews  map[string]domain.Review
+}
+
+func newFakeReviewRepository(products *fakeProductRepository) *fakeReviewRepository {
+	return &fakeReviewRepository{products: products, reviews: map[string]domain.Review{}}
+}
+
+func (f *fakeReviewRepository) adjust(productID string, count, sum int64) error {
+	product, ok := f.products.products[productID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	product.RatingCount += count
+	product.RatingSum += sum
+	product.Version++
+	f.products.products[productID] = product
+	return nil
+}
+
+func (f *fakeReviewRepository) Create(ctx context.Context, review domain.Review) error {
+	if err := f.adjust(review.ProductID, 1, int64(review.Rating)); err != nil {
+		return err
+	}
+	f.reviews[review.ID] = review
+	return nil
+}
+
+func (f *fakeReviewRepository) Get(ctx context.Context, productID, id string) (domain.Review, error) {
+	review, ok := f.reviews[id]
+	if !ok || review.ProductID != productID || review.TenantID != ports.TenantID(ctx) {
+		return domain.Review{}, domain.ErrReviewNotFound
+	}
+	return review, nil
+}
+
+func (f *fakeReviewRepository) List(ctx context.Context, productID, after string, limit int) ([]domain.Review, error) {
+	reviews := []domain.Review{}
+	for _, review := range f.reviews {
+		if review.ProductID == productID && (after == "" || review.ID < after) {
+			reviews = append(reviews, review)
+		}
+	}
+	sort.Slice(reviews, func(i, j int) bool { return reviews[i].ID > reviews[j].ID })
+	if len(reviews) > limit {
+		reviews = reviews[:limit]
+	}
+	return reviews, nil
+}
+
+func (f *fakeReviewRepository) Update(ctx context.Context, review domain.Review, previousRating int) error {
+	if f.reviews[review.ID].Rating != previousRating {
+		return domain.ErrConflict
+	}
+	if err := f.adjust(review.ProductID, 0, int64(review.Rating-previousRating)); err != nil {
+		return err
+	}
+	f.reviews[review.ID] = review
+	return nil
+}
+
+func (f *fakeReviewRepository) Delete(ctx context.Context, review domain.Review) error {
+	if err := f.adjust(review.ProductID, -1, -int64(review.Rating)); err != nil {
+		return err
+	}
+	delete(f.reviews, review.ID)
+	return nil
+}
+
+func TestReviewService(t *testing.T) {
+	products := newFakeProductRepository()
+	products.products["p1"] = domain.Product{ID: "p1", Name: "Laptop", TenantID: "acme", Version: 1}
+	service := NewReviewService(newFakeReviewRepository(products), products, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	alice := ports.WithActor(ports.WithTenant(context.Background(), "acme"), "alice")
+	bob := ports.WithActor(ports.WithTenant(context.Background(), "acme"), "bob")
+
+	first, err := service.Create(alice, "p1", ports.ReviewInput{Rating: 5, Comment: "Great"})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", first.Author)
+	assert.Equal(t, "acme", first.TenantID)
+	second, err := service.Create(bob, "p1", ports.ReviewInput{Rating: 2})
+	require.NoError(t, err)
+
+	page, err := service.List(alice, "p1", "", 1)
+	require.NoError(t, err)
+	assert.Equal(t, domain.RatingSummary{Average: 3.5, Count: 2}, page.Rating)
+	assert.Equal(t, []domain.Review{second}, page.Reviews)
+	assert.Equal(t, second.ID, page.NextAfter)
+	page, err = service.List(alice, "p1", page.NextAfter, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Review{first}, page.Reviews)
+
+	_, err = service.Update(bob, "p1", first.ID, ports.ReviewInput{Rating: 1})
+	assert.ErrorIs(t, err, domain.ErrNotReviewAuthor)
+	updated, err := service.Update(alice, "p1", first.ID, ports.ReviewInput{Rating: 3, Comment: "Fine"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, updated.Rating)
+	assert.Equal(t, domain.RatingSummary{Average: 2.5, Count: 2}, products.products["p1"].Rating())
+
+	assert.ErrorIs(t, service.Delete(alice, "p1", second.ID), domain.ErrNotReviewAuthor)
+	require.NoError(t, service.Delete(bob, "p1", second.ID))
+	assert.Equal(t, domain.RatingSummary{Average: 3, Count: 1}, products.products["p1"].Rating())
+	_, err = service.Get(alice, "p1", second.ID)
+	assert.ErrorIs(t, err, domain.ErrReviewNotFound)
+}
+
+func TestReviewService_Rejects(t *testing.T) {
+	products := newFakeProductRepository()
+	products.products["p1"] = domain.Product{ID: "p1", Name: "Laptop", Version: 1}
+	service := NewReviewService(newFakeReviewRepository(products), products, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	_, err := service.Create(ctx, "p1", ports.ReviewInput{Rating: 6})
+	assert.ErrorIs(t, err, domain.ErrInvalidRating)
+	_, err = service.Create(ctx, "missing", ports.ReviewInput{Rating: 4})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = service.Create(ports.WithTenant(ctx, "other"), "p1", ports.ReviewInput{Rating: 4})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	review, err := service.Create(ctx, "p1", ports.ReviewInput{Rating: 4})
+	require.NoError(t, err)
+	assert.Equal(t, domain.ActorAnonymous, review.Author)
+	_, err = service.Update(ctx, "p1", review.ID, ports.ReviewInput{Rating: 0})
+	assert.ErrorIs(t, err, domain.ErrInvalidRating)
+	assert.Equal(t, int64(4), products.products["p1"].RatingSum)
+}
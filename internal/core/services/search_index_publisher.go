@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+type searchIndexPublisher struct {
+	indexer ports.SearchIndexer
+	logger  *slog.Logger
+}
+
+// NewSearchIndexPublisher applies product events to the search index, so
+// the index follows the outbox relay or the table's stream like any other
+// consumer
+func NewSearchIndexPublisher(indexer ports.SearchIndexer, logger *slog.Logger) ports.EventPublisher {
+	return &searchIndexPublisher{
+		indexer: indexer,
+		logger:  logger,
+	}
+}
+
+// Publish indexes the product carried by a create or update, or removes it
+// from the index for a delete or an expiry. Other events, such as moderation outcomes,
+// come with an update of their own or describe no stored product, and are
+// skipped. Hidden products are indexed too; searches filter them out.
+func (p *searchIndexPublisher) Publish(ctx context.Context, event domain.ProductEvent) error {
+	var err error
+	switch event.Type {
+	case domain.EventProductCreated, domain.EventProductUpdated:
+		err = p.indexer.Index(ctx, *event.Product)
+	case domain.EventProductDeleted, domain.EventProductExpired:
+		err = p.indexer.Remove(ctx, event.ProductID)
+	default:
+		return nil
+	}
+	if err != nil {
+		p.logger.ErrorContext(ctx, "failed to update search index", "product_id", event.ProductID, "type", event.Type, "error", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+)
+
+type fakeSearchIndexer struct {
+	documents map[string]domain.Product
+	err       error
+}
+
+func (f *fakeSearchIndexer) Index(ctx context.Context, product domain.Product) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.documents[product.ID] = product
+	return nil
+}
+
+func (f *fakeSearchIndexer) Remove(ctx context.Context, productID string) error {
+	if f.err != nil {
+		return f.err
+	}
+	delete(f.documents, productID)
+	return nil
+}
+
+func TestSearchIndexPublisher(t *testing.T) {
+	indexer := &fakeSearchIndexer{documents: map[string]domain.Product{}}
+	publisher := NewSearchIndexPublisher(indexer, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	product := domain.Product{ID: "p1", Name: "Laptop", Version: 1}
+	require.NoError(t, publisher.Publish(ctx, domain.NewProductEvent(domain.EventProductCreated, "p1", &product, now)))
+	product.Name, product.Version = "Laptop Pro", 2
+	require.NoError(t, publisher.Publish(ctx, domain.NewProductEvent(domain.EventProductUpdated, "p1", &product, now)))
+	assert.Equal(t, "Laptop Pro", indexer.documents["p1"].Name)
+
+	// A rejected submission is not what is stored
+	rejected := domain.Product{ID: "p1", Name: "Scam kit", ModerationStatus: domain.ModerationRejected}
+	require.NoError(t, publisher.Publish(ctx, domain.NewProductEvent(domain.EventProductRejected, "p1", &rejected, now)))
+	assert.Equal(t, "Laptop Pro", indexer.documents["p1"].Name)
+
+	require.NoError(t, publisher.Publish(ctx, domain.NewProductEvent(domain.EventProductDeleted, "p1", nil, now)))
+	assert.Empty(t, indexer.documents)
+
+	// TTL expiries reported by the stream remove the product too
+	expiring := domain.Product{ID: "p2", Name: "Flash Sale Hat"}
+	require.NoError(t, publisher.Publish(ctx, domain.NewProductEvent(domain.EventProductCreated, "p2", &expiring, now)))
+	require.NoError(t, publisher.Publish(ctx, domain.NewProductEvent(domain.EventProductExpired, "p2", &expiring, now)))
+	assert.Empty(t, indexer.documents)
+
+	// Failures are returned so the event is delivered again
+	indexer.err = errors.New("cluster unavailable")
+	assert.Error(t, publisher.Publish(ctx, domain.NewProductEvent(domain.EventProductUpdated, "p1", &product, now)))
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+type searchService struct {
+	repo        ports.SearchRepository
+	searchTerms ports.SearchTermService
+	logger      *slog.Logger
+}
+
+func NewSearchService(repo ports.SearchRepository, searchTerms ports.SearchTermService, logger *slog.Logger) ports.SearchService {
+	return &searchService{
+		repo:        repo,
+		searchTerms: searchTerms,
+		logger:      logger,
+	}
+}
+
+// Search runs a free-text search and records the term for the search
+// analytics report, like a name-filtered listing does
+func (s *searchService) Search(ctx context.Context, query ports.SearchQuery) ([]domain.SearchHit, error) {
+	query.Text = strings.TrimSpace(query.Text)
+	if query.Text == "" {
+		return nil, domain.ErrInvalidSearch
+	}
+
+	hits, err := s.repo.Search(ctx, query)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "product search failed", "query", query.Text, "error", err)
+		return nil, err
+	}
+
+	s.searchTerms.RecordSearch(ctx, query.Text, int64(len(hits)))
+	return hits, nil
+}
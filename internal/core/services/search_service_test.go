@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+type fakeSearchRepository struct {
+	hits    []domain.SearchHit
+	queries []ports.SearchQuery
+}
+
+func (f *fakeSearchRepository) Search(ctx context.Context, query ports.SearchQuery) ([]domain.SearchHit, error) {
+	f.queries = append(f.queries, query)
+	return f.hits, nil
+}
+
+type recordedSearch struct {
+	term    string
+	results int64
+}
+
+type recordingSearchTerms struct {
+	ports.SearchTermService
+	searches []recordedSearch
+}
+
+func (r *recordingSearchTerms) RecordSearch(ctx context.Context, term string, results int64) {
+	r.searches = append(r.searches, recordedSearch{term, results})
+}
+
+func TestSearch(t *testing.T) {
+	repo := &fakeSearchRepository{hits: []domain.SearchHit{{Product: domain.Product{ID: "1"}, Score: 2}}}
+	terms := &recordingSearchTerms{}
+	service := NewSearchService(repo, terms, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	hits, err := service.Search(context.Background(), ports.SearchQuery{Text: "  laptop ", Limit: 5})
+	require.NoError(t, err)
+	assert.Len(t, hits, 1)
+	assert.Equal(t, []ports.SearchQuery{{Text: "laptop", Limit: 5}}, repo.queries)
+	assert.Equal(t, []recordedSearch{{"laptop", 1}}, terms.searches)
+
+	_, err = service.Search(context.Background(), ports.SearchQuery{Text: " ", Limit: 5})
+	assert.ErrorIs(t, err, domain.ErrInvalidSearch)
+	assert.Len(t, repo.queries, 1)
+}
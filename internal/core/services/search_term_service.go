@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+// maxSearchTermDays bounds a report, each day costs one index query
+const maxSearchTermDays = 90
+
+type searchTermService struct {
+	terms  ports.SearchTermRepository
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+func NewSearchTermService(terms ports.SearchTermRepository, logger *slog.Logger) ports.SearchTermService {
+	return &searchTermService{
+		terms:  terms,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// RecordSearch counts one search for term. Failures are only logged, losing
+// a counter must never fail the listing that triggered it.
+func (s *searchTermService) RecordSearch(ctx context.Context, term string, results int64) {
+	term = domain.NormalizeSearchTerm(term)
+	if term == "" {
+		return
+	}
+
+	if err := s.terms.IncrementSearch(ctx, term, s.now().UTC(), results == 0); err != nil {
+		s.logger.WarnContext(ctx, "failed to record search term", "term", term, "error", err)
+	}
+}
+
+func (s *searchTermService) Report(ctx context.Context, query ports.SearchTermQuery) ([]domain.SearchTermStats, error) {
+	from := truncateDay(query.From)
+	to := truncateDay(query.To)
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: from must not be after to", domain.ErrInvalidRange)
+	}
+	days := int(to.Sub(from).Hours()/24) + 1
+	if days > maxSearchTermDays {
+		return nil, fmt.Errorf("%w: at most %d days can be reported", domain.ErrInvalidRange, maxSearchTermDays)
+	}
+
+	periods := make([][]domain.SearchTermStats, 0, days)
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		daily, err := s.terms.DailySearchTerms(ctx, day)
+		if err != nil {
+			return nil, err
+		}
+		periods = append(periods, daily)
+	}
+
+	return domain.RankSearchTerms(domain.MergeSearchTerms(periods...), query.ZeroResultsOnly, query.Limit), nil
+}
+
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
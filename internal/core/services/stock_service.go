@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+// stockAttempts bounds the reads and writes of one stock adjustment
+const stockAttempts = 3
+
+type stockService struct {
+	products ports.ProductRepository
+	stock    ports.StockRepository
+	logger   *slog.Logger
+}
+
+func NewStockService(products ports.ProductRepository, stock ports.StockRepository, logger *slog.Logger) ports.StockService {
+	return &stockService{
+		products: products,
+		stock:    stock,
+		logger:   logger,
+	}
+}
+
+func (s *stockService) Adjust(ctx context.Context, id string, delta int64) (domain.StockLevel, error) {
+	if delta == 0 {
+		return domain.StockLevel{}, domain.ErrInvalidStockAdjustment
+	}
+
+	stock, err := s.adjust(ctx, id, delta)
+	if err != nil {
+		if errors.Is(err, domain.ErrInsufficientStock) {
+			s.logger.InfoContext(ctx, "stock adjustment rejected", "id", id, "delta", delta)
+		} else if !errors.Is(err, domain.ErrNotFound) {
+			s.logger.ErrorContext(ctx, "failed to adjust stock", "id", id, "delta", delta, "error", err)
+		}
+		return domain.StockLevel{}, err
+	}
+
+	s.logger.InfoContext(ctx, "stock adjusted", "id", id, "delta", delta, "stock", stock)
+	return domain.StockLevel{ProductID: id, Stock: stock}, nil
+}
+
+// adjust applies delta to the product as read, with the product.updated
+// event of the result. Adjustments racing on the same product conflict, so
+// it reads again and retries a few times before giving up.
+func (s *stockService) adjust(ctx context.Context, id string, delta int64) (int64, error) {
+	for attempt := 1; ; attempt++ {
+		product, err := s.products.GetByID(ports.WithConsistentRead(ctx), id)
+		if err != nil {
+			return 0, err
+		}
+		if product.Stock+delta < 0 {
+			return 0, domain.ErrInsufficientStock
+		}
+
+		now := time.Now().UTC()
+		updated := product
+		updated.Stock += delta
+		updated.UpdatedAt = now
+		updated.Version++
+		event := domain.NewProductEvent(domain.EventProductUpdated, id, &updated, now)
+		stock, err := s.stock.AdjustStock(ports.WithOutboxEvent(ctx, event), product, delta, now)
+		if errors.Is(err, domain.ErrConflict) && attempt < stockAttempts {
+			continue
+		}
+		return stock, err
+	}
+}
+
+func (s *stockService) Get(ctx context.Context, id string) (domain.StockLevel, error) {
+	product, err := s.products.GetByID(ctx, id)
+	if err != nil {
+		return domain.StockLevel{}, err
+	}
+	return domain.StockLevel{ProductID: id, Stock: product.Stock}, nil
+}
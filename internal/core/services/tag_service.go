@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+// tagFields is the only attribute read when counting tags
+var tagFields = []string{"tags"}
+
+// tagService counts tags by reading every live product, so each tenant's
+// counts are kept for cacheTTL rather than recounted on every request
+type tagService struct {
+	products ports.ProductStreamer
+	cacheTTL time.Duration
+	logger   *slog.Logger
+	now      func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedTags
+}
+
+type cachedTags struct {
+	tags      []domain.TagCount
+	expiresAt time.Time
+}
+
+func NewTagService(products ports.ProductStreamer, cacheTTL time.Duration, logger *slog.Logger) ports.TagService {
+	return &tagService{
+		products: products,
+		cacheTTL: cacheTTL,
+		logger:   logger,
+		now:      time.Now,
+		cache:    make(map[string]cachedTags),
+	}
+}
+
+func (s *tagService) ListTags(ctx context.Context) ([]domain.TagCount, error) {
+	tenant := ports.TenantID(ctx)
+	now := s.now()
+	s.mu.Lock()
+	cached, ok := s.cache[tenant]
+	s.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.tags, nil
+	}
+
+	counts := map[string]int{}
+	err := s.products.StreamProducts(ctx, ports.ProductFilters{Fields: tagFields}, func(page []domain.Product) error {
+		for _, product := range page {
+			for _, tag := range product.Tags {
+				counts[tag]++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to count tags", "error", err)
+		return nil, err
+	}
+
+	tags := domain.SortTagCounts(counts)
+	s.mu.Lock()
+	s.cache[tenant] = cachedTags{tags: tags, expiresAt: now.Add(s.cacheTTL)}
+	s.mu.Unlock()
+	return tags, nil
+}
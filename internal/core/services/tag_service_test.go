@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+)
+
+// fakeStreamer streams its products in pages of one and counts the streams
+type fakeStreamer struct {
+	products []domain.Product
+	streams  int
+	filters  ports.ProductFilters
+}
+
+func (f *fakeStreamer) StreamProducts(ctx context.Context, filters ports.ProductFilters, fn func(page []domain.Product) error) error {
+	f.streams++
+	f.filters = filters
+	for _, product := range f.products {
+		if product.TenantID != ports.TenantID(ctx) {
+			continue
+		}
+		if err := fn([]domain.Product{product}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestTagService_ListTags(t *testing.T) {
+	streamer := &fakeStreamer{products: []domain.Product{
+		{ID: "1", Tags: []string{"gaming", "audio"}},
+		{ID: "2", Tags: []string{"audio"}},
+		{ID: "3"},
+		{ID: "4", Tags: []string{"other"}, TenantID: "acme"},
+	}}
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	service := NewTagService(streamer, time.Minute, slog.New(slog.NewTextHandler(io.Discard, nil))).(*tagService)
+	service.now = func() time.Time { return now }
+
+	tags, err := service.ListTags(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []domain.TagCount{{Tag: "audio", Count: 2}, {Tag: "gaming", Count: 1}}, tags)
+	assert.Equal(t, []string{"tags"}, streamer.filters.Fields)
+
+	// Counts are reused per tenant until the cache expires
+	_, err = service.ListTags(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, streamer.streams)
+
+	tags, err = service.ListTags(ports.WithTenant(context.Background(), "acme"))
+	require.NoError(t, err)
+	assert.Equal(t, []domain.TagCount{{Tag: "other", Count: 1}}, tags)
+	assert.Equal(t, 2, streamer.streams)
+
+	now = now.Add(2 * time.Minute)
+	_, err = service.ListTags(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, streamer.streams)
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/domain"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/core/ports"
+	"log/slog"
+)
+
+// trendingSnapshotSize is how many products a rollup keeps, an upper bound
+// for the trending endpoint's limit
+const trendingSnapshotSize = 100
+
+type viewService struct {
+	views       ports.ViewRepository
+	products    ports.ProductRepository
+	recommender ports.Recommender
+	windowDays  int
+	logger      *slog.Logger
+	now         func() time.Time
+}
+
+func NewViewService(views ports.ViewRepository, products ports.ProductRepository, recommender ports.Recommender, windowDays int, logger *slog.Logger) ports.ViewService {
+	return &viewService{
+		views:       views,
+		products:    products,
+		recommender: recommender,
+		windowDays:  windowDays,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+func (s *viewService) RecordView(ctx context.Context, productID, sessionID string) error {
+	if _, err := s.products.GetByID(ctx, productID); err != nil {
+		return err
+	}
+
+	if err := s.views.IncrementViews(ctx, productID, s.now().UTC()); err != nil {
+		s.logger.ErrorContext(ctx, "failed to record product view", "id", productID, "error", err)
+		return err
+	}
+
+	// The view is already counted, recommendations are best effort
+	if sessionID != "" {
+		if err := s.recommender.RecordInteraction(ctx, sessionID, productID); err != nil {
+			s.logger.WarnContext(ctx, "failed to record interaction", "id", productID, "error", err)
+		}
+	}
+	return nil
+}
+
+func (s *viewService) Trending(ctx context.Context, limit int) ([]ports.TrendingProduct, error) {
+	snapshot, err := s.views.LatestTrending(ctx)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return []ports.TrendingProduct{}, nil
+		}
+		return nil, err
+	}
+
+	trending := make([]ports.TrendingProduct, 0, limit)
+	for _, item := range snapshot.Items {
+		if len(trending) == limit {
+			break
+		}
+		product, err := s.products.GetByID(ctx, item.ProductID)
+		if err != nil {
+			// Products deleted or expired since the rollup are skipped
+			if errors.Is(err, domain.ErrNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		trending = append(trending, ports.TrendingProduct{Product: product, Views: item.Views})
+	}
+	return trending, nil
+}
+
+// RollupTrending sums the daily counters of the sliding window and stores
+// the resulting ranking as the latest trending snapshot
+func (s *viewService) RollupTrending(ctx context.Context) error {
+	now := s.now().UTC()
+	totals := make(map[string]int64)
+	for i := 0; i < s.windowDays; i++ {
+		daily, err := s.views.DailyViews(ctx, now.AddDate(0, 0, -i))
+		if err != nil {
+			return err
+		}
+		for _, item := range daily {
+			totals[item.ProductID] += item.Views
+		}
+	}
+
+	snapshot := domain.TrendingSnapshot{
+		GeneratedAt: now,
+		WindowDays:  s.windowDays,
+		Items:       domain.RankByViews(totals, trendingSnapshotSize),
+	}
+	if err := s.views.SaveTrending(ctx, snapshot); err != nil {
+		return err
+	}
+
+	s.logger.InfoContext(ctx, "trending rollup finished", "window_days", s.windowDays, "products", len(totals))
+	return nil
+}
@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testIssuer = "https://issuer.example.com/"
+
+func newTestVerifier(t *testing.T) (*Verifier, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	keyfunc := func(token *jwt.Token) (interface{}, error) { return &key.PublicKey, nil }
+	return NewVerifier(keyfunc, testIssuer, "product-api"), key
+}
+
+func sign(t *testing.T, key interface{}, method jwt.SigningMethod, claims Claims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	require.NoError(t, err)
+	return token
+}
+
+func validClaims() Claims {
+	return Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "user-1",
+			Issuer:    testIssuer,
+			Audience:  jwt.ClaimStrings{"product-api"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Email: "user@example.com",
+	}
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	verifier, key := newTestVerifier(t)
+
+	claims, err := verifier.Verify(sign(t, key, jwt.SigningMethodES256, validClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.Subject)
+	assert.Equal(t, "user@example.com", claims.Email)
+}
+
+func TestVerifier_Rejects(t *testing.T) {
+	verifier, key := newTestVerifier(t)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	expired := validClaims()
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+	wrongIssuer := validClaims()
+	wrongIssuer.Issuer = "https://evil.example.com/"
+	wrongAudience := validClaims()
+	wrongAudience.Audience = jwt.ClaimStrings{"another-api"}
+	noExpiry := validClaims()
+	noExpiry.ExpiresAt = nil
+
+	tokens := map[string]string{
+		"garbage":        "not-a-token",
+		"expired":        sign(t, key, jwt.SigningMethodES256, expired),
+		"wrong issuer":   sign(t, key, jwt.SigningMethodES256, wrongIssuer),
+		"wrong audience": sign(t, key, jwt.SigningMethodES256, wrongAudience),
+		"no expiry":      sign(t, key, jwt.SigningMethodES256, noExpiry),
+		"unknown key":    sign(t, otherKey, jwt.SigningMethodES256, validClaims()),
+		"symmetric":      sign(t, []byte("secret"), jwt.SigningMethodHS256, validClaims()),
+	}
+
+	for name, token := range tokens {
+		t.Run(name, func(t *testing.T) {
+			_, err := verifier.Verify(token)
+			assert.ErrorIs(t, err, ErrInvalidToken)
+		})
+	}
+}
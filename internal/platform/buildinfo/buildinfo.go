@@ -0,0 +1,79 @@
+// Package buildinfo holds the version stamped into the binary at link time:
+//
+//	go build -ldflags "-X github.com/tu-usuario/product-crud-hexagonal/internal/platform/buildinfo.Version=1.4.0 \
+//	  -X github.com/tu-usuario/product-crud-hexagonal/internal/platform/buildinfo.Commit=$(git rev-parse HEAD) \
+//	  -X github.com/tu-usuario/product-crud-hexagonal/internal/platform/buildinfo.Date=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/api
+//
+// Binaries built without the flags report version "dev" and fall back to
+// the VCS stamp the go command embeds when building inside a repository.
+package buildinfo
+
+import (
+	"log/slog"
+	"runtime"
+	"runtime/debug"
+	"sync"
+)
+
+// Set with -ldflags -X
+var (
+	Version = "dev"
+	Commit  = ""
+	Date    = ""
+)
+
+// Info describes the running binary
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	Date      string `json:"date,omitempty"`
+	Modified  bool   `json:"modified,omitempty"`
+	GoVersion string `json:"go_version"`
+}
+
+var (
+	once sync.Once
+	info Info
+)
+
+// Get returns the build information, read once
+func Get() Info {
+	once.Do(func() {
+		info = read(Version, Commit, Date)
+	})
+	return info
+}
+
+func read(version, commit, date string) Info {
+	info := Info{Version: version, Commit: commit, Date: date, GoVersion: runtime.Version()}
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if info.Commit == "" {
+				info.Commit = setting.Value
+			}
+		case "vcs.time":
+			if info.Date == "" {
+				info.Date = setting.Value
+			}
+		case "vcs.modified":
+			// Only meaningful for the revision the go command stamped
+			info.Modified = commit == "" && setting.Value == "true"
+		}
+	}
+	return info
+}
+
+// LogValue logs the information as a group, as in
+// logger.Info("starting", "build", buildinfo.Get())
+func (i Info) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("version", i.Version),
+		slog.String("commit", i.Commit),
+		slog.String("date", i.Date),
+	)
+}
@@ -0,0 +1,20 @@
+package buildinfo
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRead_LinkerValuesWin(t *testing.T) {
+	info := read("1.4.0", "abc123", "2025-01-01T00:00:00Z")
+	assert.Equal(t, Info{Version: "1.4.0", Commit: "abc123", Date: "2025-01-01T00:00:00Z", GoVersion: runtime.Version()}, info)
+}
+
+func TestGet_Defaults(t *testing.T) {
+	info := Get()
+	assert.Equal(t, "dev", info.Version)
+	assert.Equal(t, runtime.Version(), info.GoVersion)
+	assert.Equal(t, "dev", info.LogValue().Group()[0].Value.String())
+}
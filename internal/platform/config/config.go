@@ -1,7 +1,10 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"time"
 )
 
 type Config struct {
@@ -9,20 +12,361 @@ type Config struct {
 	AWSRegion     string
 	DynamoDBTable string
 	LogLevel      string
+	AdminAPIKey   string
+	VerifySchema  bool
+	// LogFormat is json or text. LogFile writes the logs to that file
+	// instead of stdout, rotated once it reaches LogMaxSizeMB and keeping
+	// LogMaxBackups rotated files.
+	LogFormat     string
+	LogFile       string
+	LogMaxSizeMB  int
+	LogMaxBackups int
+	// LogSampleInitial and LogSampleThereafter sample info and debug
+	// records: past the first LogSampleInitial records of a message in a
+	// second, only one in LogSampleThereafter is logged. Zero thereafter
+	// logs everything.
+	LogSampleInitial    int
+	LogSampleThereafter int
+	// MigrateOnStart creates the products table, its indexes and TTL at
+	// boot when they are missing
+	MigrateOnStart bool
+	// IndexShards spreads the index partition key of a products table over
+	// that many shards; TableIndexShards overrides it for the tables it
+	// names, so only the tables taking hot writes pay for the read fan-out.
+	// A table's indexes all share the sharded attribute, and so its count.
+	IndexShards      int
+	TableIndexShards map[string]int
+	// ScanSegments splits full scans (exports and counts) into segments
+	// read concurrently by up to ScanWorkers goroutines
+	ScanSegments int
+	ScanWorkers  int
+	CursorSecret string
+	CursorTTL    time.Duration
+	// ThrottleRate enables the adaptive client-side throttle, in capacity
+	// units per second; zero disables it
+	ThrottleRate    float64
+	ThrottleMaxRate float64
+	// DynamoDBTimeout bounds each DynamoDB call, retries included; zero
+	// leaves calls bounded only by their request
+	DynamoDBTimeout time.Duration
+	// RedisURL enables the read-through product cache when set
+	RedisURL string
+	CacheTTL time.Duration
+	// CacheWarmProducts is how many of each tenant's most recently updated
+	// products a leader preloads into the cache; zero disables warming
+	CacheWarmProducts int
+	CacheWarmInterval time.Duration
+	// CacheWarmTenants are warmed besides the default tenant
+	CacheWarmTenants []string
+	// SearchProvider picks the search backend: dynamodb or opensearch
+	SearchProvider  string
+	OpenSearchURL   string
+	OpenSearchIndex string
+	// SearchIndexing feeds the OpenSearch index from the outbox relay of
+	// cmd/api or the stream read by cmd/streams; none leaves it to others
+	SearchIndexing string
+	// Views and trending
+	ViewsTable             string
+	TrendingWindowDays     int
+	TrendingRollupInterval time.Duration
+	SearchTermsTable       string
+	// RecommendationsTable holds co-occurrence counters and session histories
+	RecommendationsTable string
+	// RelatedPriceBand is how far, as a fraction of a product's price, the
+	// prices of its related products may be
+	RelatedPriceBand float64
+	// TombstonesTable remembers deleted product IDs and their successors
+	TombstonesTable string
+	// AuditTable keeps the change history of every product write
+	AuditTable string
+	// FavoritesTable holds each user's wish list. With FavoriteCounts the
+	// number of users favoriting each product is also kept on its item.
+	FavoritesTable string
+	FavoriteCounts bool
+	// Product images are uploaded to ImagesBucket through presigned URLs
+	// valid for ImageUploadExpiry and served from ImagesBaseURL; an empty
+	// bucket disables uploads
+	ImagesBucket      string
+	ImagesBaseURL     string
+	ImageUploadExpiry time.Duration
+	// Scheduled publishing; jobs run on the instance holding their lease in
+	// LocksTable
+	PublishInterval time.Duration
+	LocksTable      string
+	// Automatic archival and the warning sent ahead of it
+	ArchiveInterval      time.Duration
+	ArchiveWarningWindow time.Duration
+	// Archived products left untouched for ColdStorageAfterDays are moved
+	// to ColdStorageBucket every ColdStorageInterval; an empty bucket keeps
+	// them in the table
+	ColdStorageBucket    string
+	ColdStorageAfterDays int
+	ColdStorageInterval  time.Duration
+	// TagsCacheTTL is how long each tenant's tag counts are kept
+	TagsCacheTTL time.Duration
+	// ProductIDPattern is the regular expression client-chosen product IDs
+	// must match; empty accepts UUIDs only
+	ProductIDPattern string
+	// ProductIDStrategy generates the IDs of new products: uuidv4, or the
+	// time-sortable uuidv7 and ulid. ProductIDPrefix goes before each one.
+	ProductIDStrategy string
+	ProductIDPrefix   string
+	// UpsertOnPut lets PUT /products/:id without preconditions create the
+	// product when it does not exist
+	UpsertOnPut bool
+	// DefaultLocale is the language products' own names and descriptions
+	// are written in; Accept-Language picks translations into others
+	DefaultLocale string
+	// ExchangeRates are CODE=RATE pairs quoted against the default currency,
+	// used to show prices in another currency with ?currency=
+	ExchangeRates []string
+	// Price suggestions come from the service at PricingURL, sent
+	// PricingToken as a bearer token when set; an empty URL disables them.
+	// After PricingBreakerFailures failures in a row the service is left
+	// alone for PricingBreakerCooldown.
+	PricingURL             string
+	PricingToken           string
+	PricingTimeout         time.Duration
+	PricingBreakerFailures int
+	PricingBreakerCooldown time.Duration
+	// Margin report
+	ReportsTable         string
+	MarginReportInterval time.Duration
+	LowMarginThreshold   float64
+	// Content moderation: "wordlist" (default) or "comprehend"
+	ModerationProvider        string
+	ModerationBlockedTerms    []string
+	ModerationFlaggedTerms    []string
+	ModerationRejectThreshold float64
+	ModerationFlagThreshold   float64
+	// Analytics; an empty stream name disables delivery
+	AnalyticsStream        string
+	AnalyticsBufferSize    int
+	AnalyticsFlushInterval time.Duration
+	// EventsTopicARN receives product change events; empty disables them
+	EventsTopicARN string
+	// OutboxTable holds events written with their product change until the
+	// relay job publishes them
+	OutboxTable         string
+	OutboxRelayInterval time.Duration
+	OutboxBatchSize     int
+	// cmd/streams forwards the products table's stream to StreamTopicARN,
+	// reading shards open at startup from StreamStartPosition
+	StreamTopicARN      string
+	StreamPollInterval  time.Duration
+	StreamStartPosition string
+	// UniqueKeysTable reserves product SKUs and barcodes so no two
+	// products of a tenant share one
+	UniqueKeysTable string
+	// cmd/worker creates products from the messages of ImportQueueURL;
+	// messages that can never succeed go to ImportDLQURL when set
+	ImportQueueURL          string
+	ImportDLQURL            string
+	WorkerConcurrency       int
+	WorkerVisibilityTimeout time.Duration
+	// JWT authentication for product writes; an empty JWKS URL leaves them
+	// open
+	AuthJWKSURL  string
+	AuthIssuer   string
+	AuthAudience string
+	// Role-based access control: "static" (built-in policy) or "dynamodb"
+	AuthzProvider string
+	AuthzTable    string
+	AuthzCacheTTL time.Duration
+	// TenantHeader names the tenant a request is scoped to; without it
+	// requests use the default tenant
+	TenantHeader string
+	// MetricsEnabled exposes Prometheus metrics on /metrics
+	MetricsEnabled bool
+	// HealthCheckTimeout bounds each dependency check of /health/ready
+	HealthCheckTimeout time.Duration
+	// HealthHistorySize is how many recent results of each check are kept
+	// for /health/details. A check that passed less than
+	// HealthDegradedSuccessRate of them reports DEGRADED.
+	HealthHistorySize         int
+	HealthDegradedSuccessRate float64
+	// TLS serves HTTPS with TLSCertFile and TLSKeyFile, or with a generated
+	// certificate when TLSSelfSigned is set for development. TLSClientCAFile
+	// turns on mutual TLS: clients must present a certificate it signed.
+	TLSCertFile     string
+	TLSKeyFile      string
+	TLSSelfSigned   bool
+	TLSClientCAFile string
+	// HTTP2Cleartext accepts HTTP/2 without TLS (h2c), for proxies and
+	// meshes that terminate TLS in front of the service
+	HTTP2Cleartext bool
+	// RequestValidation checks requests against the OpenAPI spec: off,
+	// report (log only) or enforce (reject with 400)
+	RequestValidation string
+	// APIGatewayPayloadVersion is the event format cmd/lambda receives: 1.0
+	// for REST APIs, 2.0 for HTTP APIs
+	APIGatewayPayloadVersion string
+	// Tracing exports OpenTelemetry spans to OTEL_EXPORTER_OTLP_ENDPOINT
+	TracingEnabled     bool
+	TracingServiceName string
+	TracingSampleRatio float64
+	// SentryDSN reports panics recovered from handlers to Sentry, tagged
+	// with SentryEnvironment; empty only logs them
+	SentryDSN         string
+	SentryEnvironment string
+	// Notifications; an empty rules file disables them, and an empty sender
+	// logs them instead of emailing through SES
+	NotificationRulesFile string
+	NotificationFrom      string
+	// RuntimeAdminKey authenticates the /admin introspection routes,
+	// separately from AdminAPIKey; empty leaves them unmounted
+	RuntimeAdminKey string
+	// AdminUIEnabled serves the embedded admin UI on /admin/ui. The page
+	// calls the API with the operator's own credentials, so it exposes
+	// nothing the API does not.
+	AdminUIEnabled bool
+	// ConfigReloadInterval re-reads the configuration on a timer, on top of
+	// every SIGHUP; zero reloads on SIGHUP only
+	ConfigReloadInterval time.Duration
 }
 
-func LoadConfig() *Config {
-	return &Config{
-		Port:          getEnv("PORT", "8080"),
-		AWSRegion:     getEnv("AWS_REGION", "us-east-1"),
-		DynamoDBTable: getEnv("DYNAMODB_TABLE", "products"),
-		LogLevel:      getEnv("LOG_LEVEL", "info"),
+// LoadConfig reads the settings from the environment, falling back to the
+// YAML or TOML file named by CONFIG_FILE and then to the defaults. Every
+// malformed or out-of-range setting is reported at once in the returned
+// error.
+func LoadConfig() (*Config, error) {
+	l := &loader{}
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		file, err := readConfigFile(path)
+		if err != nil {
+			return nil, err
+		}
+		l.file = file
 	}
+
+	cfg := &Config{
+		Port:                      l.string("PORT", "8080"),
+		AWSRegion:                 l.string("AWS_REGION", "us-east-1"),
+		DynamoDBTable:             l.string("DYNAMODB_TABLE", "products"),
+		LogLevel:                  l.string("LOG_LEVEL", "info"),
+		LogFormat:                 l.string("LOG_FORMAT", "json"),
+		LogFile:                   l.string("LOG_FILE", ""),
+		LogMaxSizeMB:              l.int("LOG_MAX_SIZE_MB", 100),
+		LogMaxBackups:             l.int("LOG_MAX_BACKUPS", 5),
+		LogSampleInitial:          l.int("LOG_SAMPLE_INITIAL", 100),
+		LogSampleThereafter:       l.int("LOG_SAMPLE_THEREAFTER", 0),
+		AdminAPIKey:               l.string("ADMIN_API_KEY", ""),
+		VerifySchema:              l.bool("VERIFY_SCHEMA_ON_START", false),
+		MigrateOnStart:            l.bool("MIGRATE_ON_START", false),
+		IndexShards:               l.int("INDEX_SHARDS", 1),
+		TableIndexShards:          l.counts("INDEX_SHARDS_BY_TABLE"),
+		ScanSegments:              l.int("SCAN_SEGMENTS", 1),
+		ScanWorkers:               l.int("SCAN_WORKERS", 0),
+		CursorSecret:              l.string("CURSOR_SECRET", ""),
+		CursorTTL:                 l.duration("CURSOR_TTL", 15*time.Minute),
+		ThrottleRate:              l.float("DYNAMODB_THROTTLE_RATE", 0),
+		ThrottleMaxRate:           l.float("DYNAMODB_THROTTLE_MAX_RATE", 0),
+		DynamoDBTimeout:           l.duration("DYNAMODB_TIMEOUT", 5*time.Second),
+		RedisURL:                  l.string("REDIS_URL", ""),
+		CacheTTL:                  l.duration("CACHE_TTL", time.Minute),
+		CacheWarmProducts:         l.int("CACHE_WARM_PRODUCTS", 0),
+		CacheWarmInterval:         l.duration("CACHE_WARM_INTERVAL", time.Minute),
+		CacheWarmTenants:          l.list("CACHE_WARM_TENANTS"),
+		SearchProvider:            l.string("SEARCH_PROVIDER", "dynamodb"),
+		OpenSearchURL:             l.string("OPENSEARCH_URL", ""),
+		OpenSearchIndex:           l.string("OPENSEARCH_INDEX", "products"),
+		SearchIndexing:            l.string("SEARCH_INDEXING", "none"),
+		ViewsTable:                l.string("VIEWS_TABLE", "product_views"),
+		TrendingWindowDays:        l.int("TRENDING_WINDOW_DAYS", 7),
+		TrendingRollupInterval:    l.duration("TRENDING_ROLLUP_INTERVAL", 24*time.Hour),
+		SearchTermsTable:          l.string("SEARCH_TERMS_TABLE", "search_terms"),
+		RecommendationsTable:      l.string("RECOMMENDATIONS_TABLE", "product_cooccurrence"),
+		RelatedPriceBand:          l.float("RELATED_PRICE_BAND", 0.2),
+		TombstonesTable:           l.string("TOMBSTONES_TABLE", "product_tombstones"),
+		AuditTable:                l.string("AUDIT_TABLE", "product_audit"),
+		FavoritesTable:            l.string("FAVORITES_TABLE", "product_favorites"),
+		FavoriteCounts:            l.bool("FAVORITE_COUNTS", false),
+		ImagesBucket:              l.string("IMAGES_BUCKET", ""),
+		ImagesBaseURL:             l.string("IMAGES_BASE_URL", ""),
+		ImageUploadExpiry:         l.duration("IMAGE_UPLOAD_EXPIRY", 15*time.Minute),
+		PublishInterval:           l.duration("PUBLISH_INTERVAL", time.Minute),
+		LocksTable:                l.string("LOCKS_TABLE", "scheduler_locks"),
+		ArchiveInterval:           l.duration("ARCHIVE_INTERVAL", time.Hour),
+		ArchiveWarningWindow:      l.duration("ARCHIVE_WARNING_WINDOW", 72*time.Hour),
+		ColdStorageBucket:         l.string("COLD_STORAGE_BUCKET", ""),
+		ColdStorageAfterDays:      l.int("COLD_STORAGE_AFTER_DAYS", 90),
+		ColdStorageInterval:       l.duration("COLD_STORAGE_INTERVAL", 24*time.Hour),
+		TagsCacheTTL:              l.duration("TAGS_CACHE_TTL", time.Minute),
+		ProductIDPattern:          l.string("PRODUCT_ID_PATTERN", ""),
+		ProductIDStrategy:         l.string("PRODUCT_ID_STRATEGY", "uuidv4"),
+		ProductIDPrefix:           l.string("PRODUCT_ID_PREFIX", ""),
+		UpsertOnPut:               l.bool("UPSERT_ON_PUT", false),
+		DefaultLocale:             l.string("DEFAULT_LOCALE", "en"),
+		ExchangeRates:             l.list("EXCHANGE_RATES"),
+		PricingURL:                l.string("PRICING_URL", ""),
+		PricingToken:              l.string("PRICING_TOKEN", ""),
+		PricingTimeout:            l.duration("PRICING_TIMEOUT", 2*time.Second),
+		PricingBreakerFailures:    l.int("PRICING_BREAKER_FAILURES", 5),
+		PricingBreakerCooldown:    l.duration("PRICING_BREAKER_COOLDOWN", 30*time.Second),
+		ReportsTable:              l.string("REPORTS_TABLE", "reports"),
+		MarginReportInterval:      l.duration("MARGIN_REPORT_INTERVAL", time.Hour),
+		LowMarginThreshold:        l.float("LOW_MARGIN_THRESHOLD", 0.2),
+		ModerationProvider:        l.string("MODERATION_PROVIDER", "wordlist"),
+		ModerationBlockedTerms:    l.list("MODERATION_BLOCKED_TERMS"),
+		ModerationFlaggedTerms:    l.list("MODERATION_FLAGGED_TERMS"),
+		ModerationRejectThreshold: l.float("MODERATION_REJECT_THRESHOLD", 0.9),
+		ModerationFlagThreshold:   l.float("MODERATION_FLAG_THRESHOLD", 0.5),
+		AnalyticsStream:           l.string("ANALYTICS_STREAM", ""),
+		AnalyticsBufferSize:       l.int("ANALYTICS_BUFFER_SIZE", 10000),
+		AnalyticsFlushInterval:    l.duration("ANALYTICS_FLUSH_INTERVAL", 5*time.Second),
+		EventsTopicARN:            l.string("EVENTS_TOPIC_ARN", ""),
+		OutboxTable:               l.string("OUTBOX_TABLE", "product_outbox"),
+		OutboxRelayInterval:       l.duration("OUTBOX_RELAY_INTERVAL", 2*time.Second),
+		OutboxBatchSize:           l.int("OUTBOX_BATCH_SIZE", 25),
+		StreamTopicARN:            l.string("STREAM_TOPIC_ARN", ""),
+		StreamPollInterval:        l.duration("STREAM_POLL_INTERVAL", time.Second),
+		StreamStartPosition:       l.string("STREAM_START_POSITION", "LATEST"),
+		UniqueKeysTable:           l.string("UNIQUE_KEYS_TABLE", "product_unique_keys"),
+		ImportQueueURL:            l.string("IMPORT_QUEUE_URL", ""),
+		ImportDLQURL:              l.string("IMPORT_DLQ_URL", ""),
+		WorkerConcurrency:         l.int("WORKER_CONCURRENCY", 4),
+		WorkerVisibilityTimeout:   l.duration("WORKER_VISIBILITY_TIMEOUT", 30*time.Second),
+		AuthJWKSURL:               l.string("AUTH_JWKS_URL", ""),
+		AuthIssuer:                l.string("AUTH_ISSUER", ""),
+		AuthAudience:              l.string("AUTH_AUDIENCE", ""),
+		AuthzProvider:             l.string("AUTHZ_PROVIDER", "static"),
+		AuthzTable:                l.string("AUTHZ_TABLE", "role_permissions"),
+		AuthzCacheTTL:             l.duration("AUTHZ_CACHE_TTL", time.Minute),
+		TenantHeader:              l.string("TENANT_HEADER", "X-Tenant-ID"),
+		MetricsEnabled:            l.bool("METRICS_ENABLED", false),
+		HealthCheckTimeout:        l.duration("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+		HealthHistorySize:         l.int("HEALTH_HISTORY_SIZE", 60),
+		HealthDegradedSuccessRate: l.float("HEALTH_DEGRADED_SUCCESS_RATE", 0.9),
+		TLSCertFile:               l.string("TLS_CERT_FILE", ""),
+		TLSKeyFile:                l.string("TLS_KEY_FILE", ""),
+		TLSSelfSigned:             l.bool("TLS_SELF_SIGNED", false),
+		TLSClientCAFile:           l.string("TLS_CLIENT_CA_FILE", ""),
+		HTTP2Cleartext:            l.bool("HTTP2_CLEARTEXT", false),
+		RequestValidation:         l.string("OPENAPI_VALIDATION", "off"),
+		APIGatewayPayloadVersion:  l.string("API_GATEWAY_PAYLOAD_VERSION", "1.0"),
+		TracingEnabled:            l.bool("TRACING_ENABLED", false),
+		TracingServiceName:        l.string("OTEL_SERVICE_NAME", "product-api"),
+		TracingSampleRatio:        l.float("TRACING_SAMPLE_RATIO", 1),
+		SentryDSN:                 l.string("SENTRY_DSN", ""),
+		SentryEnvironment:         l.string("SENTRY_ENVIRONMENT", "production"),
+		NotificationRulesFile:     l.string("NOTIFICATION_RULES_FILE", ""),
+		NotificationFrom:          l.string("NOTIFICATION_FROM", ""),
+		RuntimeAdminKey:           l.string("RUNTIME_ADMIN_KEY", ""),
+		AdminUIEnabled:            l.bool("ADMIN_UI_ENABLED", false),
+		ConfigReloadInterval:      l.duration("CONFIG_RELOAD_INTERVAL", 0),
+	}
+
+	if err := errors.Join(l.finish(), cfg.Validate()); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return cfg, nil
 }
 
-func getEnv(key, fallback string) string {
-	if value, ok := os.LookupEnv(key); ok {
-		return value
+// IndexShardsFor is the number of index shards of the products table named
+// table
+func (c *Config) IndexShardsFor(table string) int {
+	if shards, ok := c.TableIndexShards[table]; ok {
+		return shards
 	}
-	return fallback
+	return c.IndexShards
 }
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// readConfigFile reads the settings of a YAML (.yaml, .yml) or TOML (.toml)
+// file. Keys are the environment variable names in any letter case, and
+// nested tables join their keys with an underscore, so outbox.table sets
+// OUTBOX_TABLE. Lists become comma-separated values.
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	settings := make(map[string]string)
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		var document yaml.Node
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+		if len(document.Content) > 0 {
+			if err := flattenYAML("", document.Content[0], settings); err != nil {
+				return nil, fmt.Errorf("config file %s: %w", path, err)
+			}
+		}
+	case ".toml":
+		var document map[string]any
+		if err := toml.Unmarshal(data, &document); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+		flattenTOML("", document, settings)
+	default:
+		return nil, fmt.Errorf("config file %s: unsupported format %q, use .yaml, .yml or .toml", path, ext)
+	}
+	return settings, nil
+}
+
+func settingKey(prefix, key string) string {
+	key = strings.ToUpper(key)
+	if prefix == "" {
+		return key
+	}
+	return prefix + "_" + key
+}
+
+// flattenYAML keeps scalars as written, so values such as 1.0 or 08 are not
+// reformatted as numbers
+func flattenYAML(prefix string, node *yaml.Node, settings map[string]string) error {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if err := flattenYAML(settingKey(prefix, node.Content[i].Value), node.Content[i+1], settings); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		values := make([]string, len(node.Content))
+		for i, item := range node.Content {
+			if item.Kind != yaml.ScalarNode {
+				return fmt.Errorf("%s: lists may only hold plain values", prefix)
+			}
+			values[i] = item.Value
+		}
+		settings[prefix] = strings.Join(values, ",")
+	case yaml.ScalarNode:
+		if node.Tag != "!!null" {
+			settings[prefix] = node.Value
+		}
+	case yaml.AliasNode:
+		return flattenYAML(prefix, node.Alias, settings)
+	}
+	return nil
+}
+
+func flattenTOML(prefix string, table map[string]any, settings map[string]string) {
+	for key, value := range table {
+		key = settingKey(prefix, key)
+		switch value := value.(type) {
+		case map[string]any:
+			flattenTOML(key, value, settings)
+		case []any:
+			values := make([]string, len(value))
+			for i, item := range value {
+				values[i] = tomlScalar(item)
+			}
+			settings[key] = strings.Join(values, ",")
+		default:
+			settings[key] = tomlScalar(value)
+		}
+	}
+}
+
+func tomlScalar(value any) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return fmt.Sprint(value)
+	}
+}
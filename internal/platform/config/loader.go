@@ -0,0 +1,145 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// loader resolves each setting from its environment variable, then from
+// the config file, then from its default, collecting the values that do
+// not parse instead of silently using the default. Typed settings left
+// empty, as in KEY=, keep their default.
+type loader struct {
+	file map[string]string
+	used map[string]bool
+	errs []error
+}
+
+// lookup returns the raw value of a setting and whether one was given
+func (l *loader) lookup(key string) (string, bool) {
+	if l.used == nil {
+		l.used = make(map[string]bool)
+	}
+	l.used[key] = true
+	if value, ok := os.LookupEnv(key); ok {
+		return value, true
+	}
+	value, ok := l.file[key]
+	return value, ok
+}
+
+func (l *loader) invalid(key, value, kind string) {
+	l.errs = append(l.errs, fmt.Errorf("%s: %q is not a valid %s", key, value, kind))
+}
+
+func (l *loader) string(key, fallback string) string {
+	if value, ok := l.lookup(key); ok {
+		return value
+	}
+	return fallback
+}
+
+func (l *loader) bool(key string, fallback bool) bool {
+	value, ok := l.lookup(key)
+	if !ok || value == "" {
+		return fallback
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		l.invalid(key, value, "boolean")
+		return fallback
+	}
+	return parsed
+}
+
+func (l *loader) int(key string, fallback int) int {
+	value, ok := l.lookup(key)
+	if !ok || value == "" {
+		return fallback
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		l.invalid(key, value, "integer")
+		return fallback
+	}
+	return parsed
+}
+
+func (l *loader) duration(key string, fallback time.Duration) time.Duration {
+	value, ok := l.lookup(key)
+	if !ok || value == "" {
+		return fallback
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		l.invalid(key, value, "duration such as 30s or 5m")
+		return fallback
+	}
+	return parsed
+}
+
+func (l *loader) float(key string, fallback float64) float64 {
+	value, ok := l.lookup(key)
+	if !ok || value == "" {
+		return fallback
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		l.invalid(key, value, "number")
+		return fallback
+	}
+	return parsed
+}
+
+// list splits a comma-separated setting, dropping empty entries
+func (l *loader) list(key string) []string {
+	value, _ := l.lookup(key)
+	var values []string
+	for _, value := range strings.Split(value, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// counts parses a comma-separated setting of name=count entries, such as
+// products=8,products-eu=4
+func (l *loader) counts(key string) map[string]int {
+	entries := l.list(key)
+	if len(entries) == 0 {
+		return nil
+	}
+	counts := make(map[string]int, len(entries))
+	for _, entry := range entries {
+		name, value, ok := strings.Cut(entry, "=")
+		parsed, err := strconv.Atoi(strings.TrimSpace(value))
+		if !ok || strings.TrimSpace(name) == "" || err != nil {
+			l.invalid(key, entry, "name=count entry")
+			continue
+		}
+		counts[strings.TrimSpace(name)] = parsed
+	}
+	return counts
+}
+
+// finish reports the parse errors, and the config file settings that no
+// field reads, which are most likely misspelled
+func (l *loader) finish() error {
+	var unknown []string
+	for key := range l.file {
+		if !l.used[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	slices.Sort(unknown)
+	for _, key := range unknown {
+		l.errs = append(l.errs, fmt.Errorf("%s: unknown setting in the config file", key))
+	}
+	return errors.Join(l.errs...)
+}
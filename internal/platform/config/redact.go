@@ -0,0 +1,53 @@
+package config
+
+import (
+	"net/url"
+	"reflect"
+	"time"
+)
+
+// redacted replaces the value of secret settings that are set
+const redacted = "[REDACTED]"
+
+// secretFields hold credentials; secretURLFields are URLs that may embed
+// a password, which is masked while the rest of the URL is kept
+var (
+	secretFields    = map[string]bool{"AdminAPIKey": true, "CursorSecret": true, "RuntimeAdminKey": true, "PricingToken": true, "SentryDSN": true}
+	secretURLFields = map[string]bool{"RedisURL": true, "OpenSearchURL": true, "PricingURL": true}
+)
+
+// Redacted returns the settings by field name with secrets masked, for
+// display. Durations are formatted as in the environment, e.g. 1m30s.
+func (c *Config) Redacted() map[string]any {
+	v := reflect.ValueOf(c).Elem()
+	settings := make(map[string]any, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		name := v.Type().Field(i).Name
+		value := v.Field(i).Interface()
+		switch value := value.(type) {
+		case time.Duration:
+			settings[name] = value.String()
+		case string:
+			settings[name] = redactString(name, value)
+		default:
+			settings[name] = value
+		}
+	}
+	return settings
+}
+
+func redactString(name, value string) string {
+	switch {
+	case value == "":
+		return value
+	case secretFields[name]:
+		return redacted
+	case secretURLFields[name]:
+		parsed, err := url.Parse(value)
+		if err != nil {
+			return redacted
+		}
+		return parsed.Redacted()
+	}
+	return value
+}
@@ -0,0 +1,177 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// idPrefixChars keep prefixed product IDs usable in URLs and keys
+const idPrefixChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_."
+
+// Validate checks that required settings are set and that values are in
+// range, reporting every problem found. Errors name the environment
+// variable of each setting.
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	if port, err := strconv.Atoi(c.Port); err != nil || port < 1 || port > 65535 {
+		v.fail("PORT", "must be a port number between 1 and 65535, got %q", c.Port)
+	}
+	v.required("AWS_REGION", c.AWSRegion)
+	v.required("DYNAMODB_TABLE", c.DynamoDBTable)
+	v.oneOf("LOG_LEVEL", c.LogLevel, "debug", "info", "warn", "error")
+	v.oneOf("LOG_FORMAT", c.LogFormat, "json", "text")
+	v.atLeast("LOG_MAX_SIZE_MB", c.LogMaxSizeMB, 1)
+	v.atLeast("LOG_MAX_BACKUPS", c.LogMaxBackups, 0)
+	v.atLeast("LOG_SAMPLE_INITIAL", c.LogSampleInitial, 0)
+	v.atLeast("LOG_SAMPLE_THEREAFTER", c.LogSampleThereafter, 0)
+
+	v.atLeast("INDEX_SHARDS", c.IndexShards, 1)
+	for _, table := range slices.Sorted(maps.Keys(c.TableIndexShards)) {
+		if shards := c.TableIndexShards[table]; shards < 1 {
+			v.fail("INDEX_SHARDS_BY_TABLE", "must be at least 1 for table %q, got %d", table, shards)
+		}
+	}
+	v.atLeast("SCAN_SEGMENTS", c.ScanSegments, 1)
+	v.atLeast("SCAN_WORKERS", c.ScanWorkers, 0)
+	v.positive("CURSOR_TTL", c.CursorTTL)
+	if c.ThrottleRate < 0 {
+		v.fail("DYNAMODB_THROTTLE_RATE", "cannot be negative")
+	}
+	if c.ThrottleMaxRate < 0 {
+		v.fail("DYNAMODB_THROTTLE_MAX_RATE", "cannot be negative")
+	}
+	if c.DynamoDBTimeout < 0 {
+		v.fail("DYNAMODB_TIMEOUT", "cannot be negative")
+	}
+	v.positive("CACHE_TTL", c.CacheTTL)
+	v.atLeast("CACHE_WARM_PRODUCTS", c.CacheWarmProducts, 0)
+	v.positive("CACHE_WARM_INTERVAL", c.CacheWarmInterval)
+
+	v.oneOf("SEARCH_PROVIDER", c.SearchProvider, "dynamodb", "opensearch")
+	if c.SearchProvider == "opensearch" {
+		v.required("OPENSEARCH_URL", c.OpenSearchURL)
+	}
+	v.oneOf("SEARCH_INDEXING", c.SearchIndexing, "none", "outbox", "stream")
+	if c.SearchIndexing != "none" && c.SearchProvider != "opensearch" {
+		v.fail("SEARCH_INDEXING", "requires SEARCH_PROVIDER=opensearch")
+	}
+	v.atLeast("TRENDING_WINDOW_DAYS", c.TrendingWindowDays, 1)
+	v.positive("TRENDING_ROLLUP_INTERVAL", c.TrendingRollupInterval)
+	v.positive("IMAGE_UPLOAD_EXPIRY", c.ImageUploadExpiry)
+	v.positive("PUBLISH_INTERVAL", c.PublishInterval)
+	v.positive("ARCHIVE_INTERVAL", c.ArchiveInterval)
+	v.atLeast("COLD_STORAGE_AFTER_DAYS", c.ColdStorageAfterDays, 1)
+	v.positive("COLD_STORAGE_INTERVAL", c.ColdStorageInterval)
+	if c.TagsCacheTTL < 0 {
+		v.fail("TAGS_CACHE_TTL", "cannot be negative")
+	}
+	v.oneOf("PRODUCT_ID_STRATEGY", c.ProductIDStrategy, "uuidv4", "uuidv7", "ulid")
+	if len(c.ProductIDPrefix) > 32 || strings.Trim(c.ProductIDPrefix, idPrefixChars) != "" {
+		v.fail("PRODUCT_ID_PREFIX", "must be at most 32 letters, digits, '-', '_' and '.'")
+	}
+	if c.PricingURL != "" {
+		if u, err := url.Parse(c.PricingURL); err != nil || u.Scheme == "" || u.Host == "" {
+			v.fail("PRICING_URL", "must be an absolute URL")
+		}
+	}
+	v.positive("PRICING_TIMEOUT", c.PricingTimeout)
+	v.atLeast("PRICING_BREAKER_FAILURES", c.PricingBreakerFailures, 1)
+	v.positive("PRICING_BREAKER_COOLDOWN", c.PricingBreakerCooldown)
+	v.positive("MARGIN_REPORT_INTERVAL", c.MarginReportInterval)
+	v.fraction("LOW_MARGIN_THRESHOLD", c.LowMarginThreshold)
+	v.fraction("RELATED_PRICE_BAND", c.RelatedPriceBand)
+
+	v.oneOf("MODERATION_PROVIDER", c.ModerationProvider, "wordlist", "comprehend")
+	v.fraction("MODERATION_REJECT_THRESHOLD", c.ModerationRejectThreshold)
+	v.fraction("MODERATION_FLAG_THRESHOLD", c.ModerationFlagThreshold)
+	if c.ModerationFlagThreshold > c.ModerationRejectThreshold {
+		v.fail("MODERATION_FLAG_THRESHOLD", "cannot be greater than MODERATION_REJECT_THRESHOLD")
+	}
+
+	v.atLeast("ANALYTICS_BUFFER_SIZE", c.AnalyticsBufferSize, 1)
+	v.positive("ANALYTICS_FLUSH_INTERVAL", c.AnalyticsFlushInterval)
+	v.positive("OUTBOX_RELAY_INTERVAL", c.OutboxRelayInterval)
+	v.atLeast("OUTBOX_BATCH_SIZE", c.OutboxBatchSize, 1)
+	v.positive("STREAM_POLL_INTERVAL", c.StreamPollInterval)
+	v.oneOf("STREAM_START_POSITION", c.StreamStartPosition, "LATEST", "TRIM_HORIZON")
+	v.required("UNIQUE_KEYS_TABLE", c.UniqueKeysTable)
+	v.atLeast("WORKER_CONCURRENCY", c.WorkerConcurrency, 1)
+	v.positive("WORKER_VISIBILITY_TIMEOUT", c.WorkerVisibilityTimeout)
+
+	v.oneOf("AUTHZ_PROVIDER", c.AuthzProvider, "static", "dynamodb")
+	v.positive("AUTHZ_CACHE_TTL", c.AuthzCacheTTL)
+	v.required("TENANT_HEADER", c.TenantHeader)
+	if c.RuntimeAdminKey != "" && c.RuntimeAdminKey == c.AdminAPIKey {
+		v.fail("RUNTIME_ADMIN_KEY", "must differ from ADMIN_API_KEY")
+	}
+	v.positive("HEALTH_CHECK_TIMEOUT", c.HealthCheckTimeout)
+	v.atLeast("HEALTH_HISTORY_SIZE", c.HealthHistorySize, 1)
+	v.fraction("HEALTH_DEGRADED_SUCCESS_RATE", c.HealthDegradedSuccessRate)
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		v.fail("TLS_KEY_FILE", "must be set together with TLS_CERT_FILE")
+	}
+	if c.TLSSelfSigned && c.TLSCertFile != "" {
+		v.fail("TLS_SELF_SIGNED", "cannot be combined with TLS_CERT_FILE")
+	}
+	if c.TLSClientCAFile != "" && c.TLSCertFile == "" && !c.TLSSelfSigned {
+		v.fail("TLS_CLIENT_CA_FILE", "requires TLS_CERT_FILE or TLS_SELF_SIGNED")
+	}
+	v.oneOf("OPENAPI_VALIDATION", c.RequestValidation, "off", "report", "enforce")
+	v.oneOf("API_GATEWAY_PAYLOAD_VERSION", c.APIGatewayPayloadVersion, "1.0", "2.0")
+	v.fraction("TRACING_SAMPLE_RATIO", c.TracingSampleRatio)
+	if c.ConfigReloadInterval < 0 {
+		v.fail("CONFIG_RELOAD_INTERVAL", "cannot be negative")
+	}
+	if tag, err := language.Parse(c.DefaultLocale); err != nil || tag == language.Und {
+		v.fail("DEFAULT_LOCALE", "must be a BCP 47 language tag, got %q", c.DefaultLocale)
+	}
+
+	return errors.Join(v.errs...)
+}
+
+type validator struct {
+	errs []error
+}
+
+func (v *validator) fail(key, format string, args ...any) {
+	v.errs = append(v.errs, fmt.Errorf("%s: "+format, append([]any{key}, args...)...))
+}
+
+func (v *validator) required(key, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.fail(key, "is required")
+	}
+}
+
+func (v *validator) oneOf(key, value string, allowed ...string) {
+	if !slices.Contains(allowed, value) {
+		v.fail(key, "must be one of %s, got %q", strings.Join(allowed, ", "), value)
+	}
+}
+
+func (v *validator) atLeast(key string, value, minimum int) {
+	if value < minimum {
+		v.fail(key, "must be at least %d, got %d", minimum, value)
+	}
+}
+
+func (v *validator) positive(key string, value time.Duration) {
+	if value <= 0 {
+		v.fail(key, "must be a positive duration, got %s", value)
+	}
+}
+
+func (v *validator) fraction(key string, value float64) {
+	if value < 0 || value > 1 {
+		v.fail(key, "must be between 0 and 1, got %g", value)
+	}
+}
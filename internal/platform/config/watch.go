@@ -0,0 +1,63 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"reflect"
+	"syscall"
+	"time"
+)
+
+// Watch reloads the configuration on every SIGHUP and, when interval is
+// positive, on every tick until ctx is done. apply receives the previous and
+// the new configuration whenever a reload changes any setting; a reload that
+// fails is passed to fail and the running configuration is kept.
+func Watch(ctx context.Context, current *Config, interval time.Duration, apply func(previous, next *Config), fail func(error)) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	var ticks <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+
+	watch(ctx, current, hangup, ticks, LoadConfig, apply, fail)
+}
+
+func watch(ctx context.Context, current *Config, hangup <-chan os.Signal, ticks <-chan time.Time, load func() (*Config, error), apply func(previous, next *Config), fail func(error)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+		case <-ticks:
+		}
+
+		next, err := load()
+		if err != nil {
+			fail(err)
+			continue
+		}
+		if len(Changed(current, next)) == 0 {
+			continue
+		}
+		apply(current, next)
+		current = next
+	}
+}
+
+// Changed returns the names of the Config fields that differ between a and b
+func Changed(a, b *Config) []string {
+	av, bv := reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem()
+	var fields []string
+	for i := 0; i < av.NumField(); i++ {
+		if !reflect.DeepEqual(av.Field(i).Interface(), bv.Field(i).Interface()) {
+			fields = append(fields, av.Type().Field(i).Name)
+		}
+	}
+	return fields
+}
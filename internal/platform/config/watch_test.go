@@ -0,0 +1,58 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChanged(t *testing.T) {
+	a := &Config{LogLevel: "info", ExchangeRates: []string{"EUR=0.92"}}
+	b := &Config{LogLevel: "debug", ExchangeRates: []string{"EUR=0.92"}, ThrottleRate: 50}
+
+	assert.Equal(t, []string{"LogLevel", "ThrottleRate"}, Changed(a, b))
+	assert.Empty(t, Changed(a, a))
+}
+
+func TestWatch_AppliesChangedConfigs(t *testing.T) {
+	hangup := make(chan os.Signal)
+	ticks := make(chan time.Time)
+	loads := []func() (*Config, error){
+		func() (*Config, error) { return &Config{LogLevel: "info"}, nil },
+		func() (*Config, error) { return nil, errors.New("invalid configuration") },
+		func() (*Config, error) { return &Config{LogLevel: "debug"}, nil },
+	}
+	load := func() (*Config, error) {
+		next := loads[0]
+		loads = loads[1:]
+		return next()
+	}
+
+	var applied [][2]string
+	var failures []error
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		watch(ctx, &Config{LogLevel: "info"}, hangup, ticks, load, func(previous, next *Config) {
+			applied = append(applied, [2]string{previous.LogLevel, next.LogLevel})
+		}, func(err error) {
+			failures = append(failures, err)
+		})
+	}()
+
+	ticks <- time.Now()      // unchanged: not applied
+	hangup <- syscall.SIGHUP // fails to load: kept
+	ticks <- time.Now()      // changed: applied
+	cancel()
+	<-done
+
+	assert.Equal(t, [][2]string{{"info", "debug"}}, applied)
+	assert.Len(t, failures, 1)
+	assert.Empty(t, loads)
+}
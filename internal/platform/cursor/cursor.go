@@ -0,0 +1,114 @@
+package cursor
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalid        = errors.New("invalid cursor")
+	ErrExpired        = errors.New("cursor has expired")
+	ErrFilterMismatch = errors.New("cursor does not match the current filters")
+)
+
+// Codec turns repository resume positions into opaque tokens. Tokens are
+// sealed with AES-GCM, so clients can neither read nor alter them, and they
+// carry a hash of the filters they were issued for plus an expiry.
+type Codec struct {
+	aead cipher.AEAD
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+type payload struct {
+	Key        []byte `json:"k"`
+	FilterHash string `json:"f"`
+	ExpiresAt  int64  `json:"e"`
+}
+
+// NewCodec derives the sealing key from secret. An empty secret yields a
+// random key, so tokens stop working when the process restarts.
+func NewCodec(secret string, ttl time.Duration) (*Codec, error) {
+	var key [32]byte
+	if secret == "" {
+		if _, err := rand.Read(key[:]); err != nil {
+			return nil, fmt.Errorf("failed to generate cursor key: %w", err)
+		}
+	} else {
+		key = sha256.Sum256([]byte(secret))
+	}
+
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cursor cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cursor cipher: %w", err)
+	}
+
+	return &Codec{aead: aead, ttl: ttl, now: time.Now}, nil
+}
+
+// HashFilters fingerprints the query parameters a cursor is bound to
+func HashFilters(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil)[:12])
+}
+
+// Encode seals key into a token valid for the codec's TTL
+func (c *Codec) Encode(key []byte, filterHash string) (string, error) {
+	plaintext, err := json.Marshal(payload{
+		Key:        key,
+		FilterHash: filterHash,
+		ExpiresAt:  c.now().Add(c.ttl).Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	sealed := c.aead.Seal(nonce, nonce, plaintext, nil)
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// Decode opens a token and returns the key it carries, provided the token
+// is authentic, unexpired and was issued for the same filters
+func (c *Codec) Decode(token, filterHash string) ([]byte, error) {
+	sealed, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(sealed) < c.aead.NonceSize() {
+		return nil, ErrInvalid
+	}
+
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrInvalid
+	}
+
+	var p payload
+	if err := json.Unmarshal(plaintext, &p); err != nil {
+		return nil, ErrInvalid
+	}
+	if c.now().Unix() > p.ExpiresAt {
+		return nil, ErrExpired
+	}
+	if p.FilterHash != filterHash {
+		return nil, ErrFilterMismatch
+	}
+	return p.Key, nil
+}
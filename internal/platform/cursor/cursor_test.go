@@ -0,0 +1,55 @@
+package cursor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodec(t *testing.T) {
+	codec, err := NewCodec("secret", time.Minute)
+	require.NoError(t, err)
+
+	filters := HashFilters("laptop", "10", "price", "asc")
+	token, err := codec.Encode([]byte("resume-here"), filters)
+	require.NoError(t, err)
+
+	t.Run("round trip", func(t *testing.T) {
+		key, err := codec.Decode(token, filters)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("resume-here"), key)
+	})
+
+	t.Run("different filters", func(t *testing.T) {
+		_, err := codec.Decode(token, HashFilters("phone", "10", "price", "asc"))
+		assert.ErrorIs(t, err, ErrFilterMismatch)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		tampered := []byte(token)
+		tampered[len(tampered)/2] ^= 'x' ^ 'y'
+		_, err := codec.Decode(string(tampered), filters)
+		assert.ErrorIs(t, err, ErrInvalid)
+	})
+
+	t.Run("garbage", func(t *testing.T) {
+		_, err := codec.Decode("not a cursor", filters)
+		assert.ErrorIs(t, err, ErrInvalid)
+	})
+
+	t.Run("other secret", func(t *testing.T) {
+		other, err := NewCodec("other", time.Minute)
+		require.NoError(t, err)
+		_, err = other.Decode(token, filters)
+		assert.ErrorIs(t, err, ErrInvalid)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		codec.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+		defer func() { codec.now = time.Now }()
+		_, err := codec.Decode(token, filters)
+		assert.ErrorIs(t, err, ErrExpired)
+	})
+}
@@ -0,0 +1,166 @@
+// Package health runs the dependency checks behind the readiness probe and
+// keeps their recent results, so a flapping dependency shows up before it
+// goes down for good.
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	StatusUp = "UP"
+	// StatusDegraded is a service still able to serve requests: an optional
+	// dependency is down, or a critical one keeps failing now and then
+	StatusDegraded = "DEGRADED"
+	StatusDown     = "DOWN"
+)
+
+// CheckFunc reports whether a dependency can serve requests
+type CheckFunc func(ctx context.Context) error
+
+type check struct {
+	name     string
+	critical bool
+	run      CheckFunc
+}
+
+// Result is the outcome of one dependency check
+type Result struct {
+	// Status is DOWN when the check failed, and DEGRADED when it passed but
+	// its recent success rate is below the checker's threshold
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	// Critical dependencies take the whole service down when they fail;
+	// the others, such as a cache that reads bypass when it is down, only
+	// degrade it
+	Critical bool   `json:"critical"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Report is the outcome of every check: DOWN if a critical one failed,
+// DEGRADED if any other is not UP
+type Report struct {
+	Status    string            `json:"status"`
+	Timestamp time.Time         `json:"timestamp"`
+	Checks    map[string]Result `json:"checks"`
+}
+
+// Checker runs the registered checks concurrently, each bounded by timeout,
+// remembering the latest historySize results of each. A check passing less
+// than degradedBelow of the time it is remembered for is degraded.
+type Checker struct {
+	checks        []check
+	timeout       time.Duration
+	historySize   int
+	degradedBelow float64
+
+	mu        sync.Mutex
+	histories map[string]*history
+}
+
+func NewChecker(timeout time.Duration, historySize int, degradedBelow float64) *Checker {
+	return &Checker{
+		timeout:       timeout,
+		historySize:   max(historySize, 1),
+		degradedBelow: degradedBelow,
+		histories:     map[string]*history{},
+	}
+}
+
+// Add registers a dependency that must be up for the service to be ready
+func (c *Checker) Add(name string, run CheckFunc) {
+	c.checks = append(c.checks, check{name: name, critical: true, run: run})
+}
+
+// AddOptional registers a dependency whose failure degrades the service
+// without making it unready
+func (c *Checker) AddOptional(name string, run CheckFunc) {
+	c.checks = append(c.checks, check{name: name, critical: false, run: run})
+}
+
+// Run checks every dependency, recording the results, and reports their
+// status
+func (c *Checker) Run(ctx context.Context) Report {
+	report, _ := c.runAll(ctx, false)
+	return report
+}
+
+// Details checks every dependency like Run, and reports each along with
+// the success rate and latencies of its recent runs
+func (c *Checker) Details(ctx context.Context) Details {
+	report, details := c.runAll(ctx, true)
+	return Details{Status: report.Status, Timestamp: report.Timestamp, Checks: details}
+}
+
+func (c *Checker) runAll(ctx context.Context, withDetails bool) (Report, map[string]CheckDetails) {
+	now := time.Now().UTC()
+	results := make([]Result, len(c.checks))
+	var wg sync.WaitGroup
+	for i, ch := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.run(ctx, ch)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{
+		Status:    StatusUp,
+		Timestamp: now,
+		Checks:    make(map[string]Result, len(c.checks)),
+	}
+	var details map[string]CheckDetails
+	if withDetails {
+		details = make(map[string]CheckDetails, len(c.checks))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, ch := range c.checks {
+		h := c.histories[ch.name]
+		if h == nil {
+			h = &history{samples: make([]Sample, 0, c.historySize)}
+			c.histories[ch.name] = h
+		}
+		result := results[i]
+		h.add(Sample{Time: now, Status: result.Status, LatencyMs: result.LatencyMs, Error: result.Error})
+
+		samples := h.list()
+		rate := successRate(samples)
+		if result.Status == StatusUp && rate < c.degradedBelow {
+			result.Status = StatusDegraded
+		}
+		report.Checks[ch.name] = result
+		switch {
+		case result.Status == StatusDown && ch.critical:
+			report.Status = StatusDown
+		case result.Status != StatusUp && report.Status == StatusUp:
+			report.Status = StatusDegraded
+		}
+		if withDetails {
+			details[ch.name] = describe(result, samples, rate)
+		}
+	}
+	return report, details
+}
+
+func (c *Checker) run(ctx context.Context, ch check) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := ch.run(ctx)
+	result := Result{
+		Status:    StatusUp,
+		LatencyMs: time.Since(start).Milliseconds(),
+		Critical:  ch.critical,
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
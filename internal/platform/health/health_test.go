@@ -0,0 +1,129 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecker_Run(t *testing.T) {
+	up := func(ctx context.Context) error { return nil }
+	down := func(ctx context.Context) error { return errors.New("connection refused") }
+	hang := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	t.Run("all up", func(t *testing.T) {
+		checker := NewChecker(time.Second, 10, 0.9)
+		checker.Add("dynamodb", up)
+		checker.AddOptional("redis", up)
+
+		report := checker.Run(context.Background())
+		assert.Equal(t, StatusUp, report.Status)
+		assert.Equal(t, StatusUp, report.Checks["dynamodb"].Status)
+		assert.True(t, report.Checks["dynamodb"].Critical)
+		assert.False(t, report.Checks["redis"].Critical)
+	})
+
+	t.Run("optional dependency down", func(t *testing.T) {
+		checker := NewChecker(time.Second, 10, 0.9)
+		checker.Add("dynamodb", up)
+		checker.AddOptional("redis", down)
+
+		report := checker.Run(context.Background())
+		assert.Equal(t, StatusDegraded, report.Status)
+		assert.Equal(t, StatusDown, report.Checks["redis"].Status)
+		assert.Equal(t, "connection refused", report.Checks["redis"].Error)
+	})
+
+	t.Run("critical dependency times out", func(t *testing.T) {
+		checker := NewChecker(20*time.Millisecond, 10, 0.9)
+		checker.Add("dynamodb", hang)
+		checker.AddOptional("redis", up)
+
+		report := checker.Run(context.Background())
+		assert.Equal(t, StatusDown, report.Status)
+		assert.Equal(t, StatusDown, report.Checks["dynamodb"].Status)
+		assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["dynamodb"].Error)
+	})
+}
+
+func TestChecker_History(t *testing.T) {
+	// flaky fails the runs listed in failing, counting from zero
+	flaky := func(failing ...int) CheckFunc {
+		run := 0
+		return func(ctx context.Context) error {
+			defer func() { run++ }()
+			for _, n := range failing {
+				if n == run {
+					return errors.New("throttled")
+				}
+			}
+			return nil
+		}
+	}
+
+	t.Run("flapping critical dependency degrades", func(t *testing.T) {
+		checker := NewChecker(time.Second, 4, 0.75)
+		checker.Add("dynamodb", flaky(0, 1))
+
+		assert.Equal(t, StatusDown, checker.Run(context.Background()).Status)
+		assert.Equal(t, StatusDown, checker.Run(context.Background()).Status)
+		report := checker.Run(context.Background())
+		assert.Equal(t, StatusDegraded, report.Status, "up again, but passed 1 of 3 runs")
+		assert.Equal(t, StatusDegraded, report.Checks["dynamodb"].Status)
+		assert.Empty(t, report.Checks["dynamodb"].Error)
+
+		assert.Equal(t, StatusDegraded, checker.Run(context.Background()).Status, "passed 2 of 4")
+		assert.Equal(t, StatusUp, checker.Run(context.Background()).Status, "passed 3 of the last 4, not under 0.75")
+	})
+
+	t.Run("details", func(t *testing.T) {
+		checker := NewChecker(time.Second, 3, 0.5)
+		checker.Add("dynamodb", flaky(1))
+		checker.AddOptional("redis", flaky())
+
+		for range 3 {
+			checker.Run(context.Background())
+		}
+		details := checker.Details(context.Background())
+		assert.Equal(t, StatusUp, details.Status, "2 of 3 runs passed, above 0.5")
+
+		dynamodb := details.Checks["dynamodb"]
+		assert.True(t, dynamodb.Critical)
+		assert.Equal(t, StatusUp, dynamodb.Status)
+		assert.Equal(t, 3, dynamodb.Samples, "the ring keeps the latest 3 of 4 runs")
+		assert.Equal(t, 0.667, dynamodb.SuccessRate)
+		require.Len(t, dynamodb.History, 3)
+		assert.Equal(t, StatusDown, dynamodb.History[0].Status, "oldest first")
+		assert.Equal(t, "throttled", dynamodb.History[0].Error)
+		require.NotNil(t, dynamodb.LastSuccess)
+		require.NotNil(t, dynamodb.LastFailure)
+		assert.True(t, dynamodb.LastFailure.Before(*dynamodb.LastSuccess))
+
+		redis := details.Checks["redis"]
+		assert.Equal(t, 1.0, redis.SuccessRate)
+		assert.Nil(t, redis.LastFailure)
+		assert.LessOrEqual(t, redis.Latency.P50Ms, redis.Latency.MaxMs)
+	})
+}
+
+func TestHistory_Ring(t *testing.T) {
+	h := &history{samples: make([]Sample, 0, 3)}
+	for i := range 5 {
+		h.add(Sample{LatencyMs: int64(i)})
+	}
+	var latencies []int64
+	for _, sample := range h.list() {
+		latencies = append(latencies, sample.LatencyMs)
+	}
+	assert.Equal(t, []int64{2, 3, 4}, latencies)
+
+	details := describe(Result{Status: StatusUp}, h.list(), 1)
+	assert.Equal(t, Latency{AvgMs: 3, P50Ms: 3, P95Ms: 4, MaxMs: 4}, details.Latency)
+}
@@ -0,0 +1,116 @@
+package health
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// Sample is one past run of a check
+type Sample struct {
+	Time      time.Time `json:"time"`
+	Status    string    `json:"status"`
+	LatencyMs int64     `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Latency summarizes the latencies of a check's recent runs
+type Latency struct {
+	AvgMs int64 `json:"avg_ms"`
+	P50Ms int64 `json:"p50_ms"`
+	P95Ms int64 `json:"p95_ms"`
+	MaxMs int64 `json:"max_ms"`
+}
+
+// CheckDetails is the latest result of a check and how its recent runs
+// went, oldest first in History
+type CheckDetails struct {
+	Result
+	// Samples is how many runs SuccessRate and Latency cover
+	Samples     int        `json:"samples"`
+	SuccessRate float64    `json:"success_rate"`
+	Latency     Latency    `json:"latency"`
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+	LastFailure *time.Time `json:"last_failure,omitempty"`
+	History     []Sample   `json:"history"`
+}
+
+// Details is a Report with the history of every check
+type Details struct {
+	Status    string                  `json:"status"`
+	Timestamp time.Time               `json:"timestamp"`
+	Checks    map[string]CheckDetails `json:"checks"`
+}
+
+// history is a ring buffer of a check's latest samples
+type history struct {
+	samples []Sample
+	// next is where the following sample goes once the buffer is full
+	next int
+}
+
+func (h *history) add(sample Sample) {
+	if len(h.samples) < cap(h.samples) {
+		h.samples = append(h.samples, sample)
+		return
+	}
+	h.samples[h.next] = sample
+	h.next = (h.next + 1) % len(h.samples)
+}
+
+// list copies the samples, oldest first
+func (h *history) list() []Sample {
+	list := make([]Sample, 0, len(h.samples))
+	list = append(list, h.samples[h.next:]...)
+	return append(list, h.samples[:h.next]...)
+}
+
+func successRate(samples []Sample) float64 {
+	if len(samples) == 0 {
+		return 1
+	}
+	passed := 0
+	for _, sample := range samples {
+		if sample.Status == StatusUp {
+			passed++
+		}
+	}
+	return float64(passed) / float64(len(samples))
+}
+
+func describe(result Result, samples []Sample, rate float64) CheckDetails {
+	details := CheckDetails{
+		Result:      result,
+		Samples:     len(samples),
+		SuccessRate: math.Round(rate*1000) / 1000,
+		History:     samples,
+	}
+	latencies := make([]int64, len(samples))
+	var total int64
+	for i, sample := range samples {
+		latencies[i] = sample.LatencyMs
+		total += sample.LatencyMs
+		at := sample.Time
+		if sample.Status == StatusUp {
+			details.LastSuccess = &at
+		} else {
+			details.LastFailure = &at
+		}
+	}
+	if len(latencies) > 0 {
+		slices.Sort(latencies)
+		details.Latency = Latency{
+			AvgMs: total / int64(len(latencies)),
+			P50Ms: percentile(latencies, 0.50),
+			P95Ms: percentile(latencies, 0.95),
+			MaxMs: latencies[len(latencies)-1],
+		}
+	}
+	return details
+}
+
+// percentile is the nearest-rank percentile p of sorted values
+func percentile(sorted []int64, p float64) int64 {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[max(rank, 0)]
+}
@@ -0,0 +1,29 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextHandler_AddsRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(contextHandler{slog.NewJSONHandler(&buf, nil)}).With("component", "test")
+
+	logger.InfoContext(WithRequestID(context.Background(), "req-1"), "with id")
+	logger.InfoContext(context.Background(), "without id")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	var withID, withoutID map[string]any
+	require.NoError(t, json.Unmarshal(lines[0], &withID))
+	require.NoError(t, json.Unmarshal(lines[1], &withoutID))
+	assert.Equal(t, "req-1", withID["request_id"])
+	assert.Equal(t, "test", withID["component"])
+	assert.NotContains(t, withoutID, "request_id")
+}
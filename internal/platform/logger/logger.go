@@ -1,31 +1,90 @@
 package logger
 
 import (
+	"context"
+	"io"
 	"log/slog"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/config"
 )
 
-func NewLogger(cfg *config.Config) *slog.Logger {
-	var level slog.Level
-	switch cfg.LogLevel {
+// level is shared by every logger NewLogger builds, so SetLevel changes
+// them all while the service runs
+var level slog.LevelVar
+
+// NewLogger builds the service logger from the LOG_* settings: JSON or text
+// records, written to stdout or to a rotating LOG_FILE, with info and debug
+// records sampled once LOG_SAMPLE_THEREAFTER is set
+func NewLogger(cfg *config.Config) (*slog.Logger, error) {
+	SetLevel(cfg.LogLevel)
+
+	var out io.Writer = os.Stdout
+	if cfg.LogFile != "" {
+		file, err := openRotatingFile(cfg.LogFile, int64(cfg.LogMaxSizeMB)<<20, cfg.LogMaxBackups)
+		if err != nil {
+			return nil, err
+		}
+		out = file
+	}
+
+	opts := &slog.HandlerOptions{Level: &level}
+	var handler slog.Handler = slog.NewJSONHandler(out, opts)
+	if cfg.LogFormat == "text" {
+		handler = slog.NewTextHandler(out, opts)
+	}
+	if cfg.LogSampleThereafter > 0 {
+		handler = samplingHandler{handler, &sampler{
+			initial:    cfg.LogSampleInitial,
+			thereafter: cfg.LogSampleThereafter,
+			now:        time.Now,
+			counts:     map[string]int{},
+		}}
+	}
+
+	logger := slog.New(contextHandler{handler})
+	slog.SetDefault(logger)
+	return logger, nil
+}
+
+// SetLevel switches the minimum level logged to debug, info, warn or error;
+// any other value logs from info
+func SetLevel(name string) {
+	switch name {
 	case "debug":
-		level = slog.LevelDebug
-	case "info":
-		level = slog.LevelInfo
+		level.Set(slog.LevelDebug)
 	case "warn":
-		level = slog.LevelWarn
+		level.Set(slog.LevelWarn)
 	case "error":
-		level = slog.LevelError
+		level.Set(slog.LevelError)
 	default:
-		level = slog.LevelInfo
+		level.Set(slog.LevelInfo)
 	}
+}
 
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-		Level: level,
-	}))
+// Level returns the name of the minimum level currently logged
+func Level() string {
+	return strings.ToLower(level.Level().String())
+}
 
-	slog.SetDefault(logger)
-	return logger
+// Writer adapts logger for libraries that print lines, such as gin's debug
+// and error output: each line written becomes one record at the given level
+func Writer(logger *slog.Logger, level slog.Level) io.Writer {
+	return lineWriter{logger, level}
+}
+
+type lineWriter struct {
+	logger *slog.Logger
+	level  slog.Level
+}
+
+func (w lineWriter) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			w.logger.Log(context.Background(), w.level, line)
+		}
+	}
+	return len(p), nil
 }
@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFile_KeepsBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api.log")
+	file, err := openRotatingFile(path, 10, 2)
+	require.NoError(t, err)
+	defer file.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := file.Write([]byte(line))
+		require.NoError(t, err)
+	}
+
+	read := func(name string) string {
+		data, err := os.ReadFile(name)
+		require.NoError(t, err)
+		return string(data)
+	}
+	assert.Equal(t, "fourth\n", read(path))
+	assert.Equal(t, "third\n", read(path+".1"))
+	assert.Equal(t, "second\n", read(path+".2"))
+	assert.NoFileExists(t, path+".3")
+}
+
+func TestSamplingHandler_SamplesInfoPerMessage(t *testing.T) {
+	var buf bytes.Buffer
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := &sampler{initial: 2, thereafter: 3, now: func() time.Time { return now }, counts: map[string]int{}}
+	logger := slog.New(samplingHandler{slog.NewTextHandler(&buf, nil), s}).With("component", "test")
+
+	for range 8 {
+		logger.Info("busy")
+	}
+	logger.Info("quiet")
+	logger.Warn("busy")
+	now = now.Add(time.Second)
+	logger.Info("busy")
+
+	// busy is kept for its 1st, 2nd, 5th and 8th records, then again once
+	// the second turns over
+	assert.Equal(t, 6, bytes.Count(buf.Bytes(), []byte("msg=busy")))
+	assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("msg=quiet")))
+	assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("level=WARN")))
+}
+
+func TestWriter_LogsEachLine(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	_, err := Writer(logger, slog.LevelError).Write([]byte("[GIN] first\n\n[GIN] second\n"))
+	require.NoError(t, err)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	assert.Contains(t, string(lines[0]), `level=ERROR msg="[GIN] first"`)
+	assert.Contains(t, string(lines[1]), `msg="[GIN] second"`)
+}
@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// sampler counts the info and debug records of each message per second.
+// Past the first initial records of a message in a second it keeps one in
+// thereafter; warnings and errors are always kept.
+type sampler struct {
+	initial    int
+	thereafter int
+	now        func() time.Time
+
+	mu     sync.Mutex
+	second time.Time
+	counts map[string]int
+}
+
+func (s *sampler) keep(r slog.Record) bool {
+	if s.thereafter == 0 || r.Level >= slog.LevelWarn {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if second := s.now().Truncate(time.Second); !second.Equal(s.second) {
+		s.second = second
+		clear(s.counts)
+	}
+	s.counts[r.Message]++
+	n := s.counts[r.Message]
+	return n <= s.initial || (n-s.initial)%s.thereafter == 0
+}
+
+// samplingHandler drops the records its sampler does not keep. Handlers
+// derived with attributes or groups share the sampler, so a message is
+// counted the same whichever logger writes it.
+type samplingHandler struct {
+	slog.Handler
+	sampler *sampler
+}
+
+func (h samplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if !h.sampler.keep(r) {
+		return nil
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return samplingHandler{h.Handler.WithAttrs(attrs), h.sampler}
+}
+
+func (h samplingHandler) WithGroup(name string) slog.Handler {
+	return samplingHandler{h.Handler.WithGroup(name), h.sampler}
+}
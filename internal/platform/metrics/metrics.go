@@ -0,0 +1,128 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "product_api"
+
+// Metrics holds the Prometheus collectors for HTTP traffic, DynamoDB calls
+// and the product cache, registered on a private registry served by Handler
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	inFlight *prometheus.GaugeVec
+
+	dynamoDBDuration  *prometheus.HistogramVec
+	dynamoDBThrottles *prometheus.CounterVec
+
+	cacheLookups *prometheus.CounterVec
+	cacheWarmed  prometheus.Gauge
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by route, method and status code.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by route and method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "http_requests_in_flight",
+			Help:      "HTTP requests currently being served by route and method.",
+		}, []string{"method", "route"}),
+		dynamoDBDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "dynamodb_call_duration_seconds",
+			Help:      "DynamoDB call latency by operation, including SDK retries.",
+			Buckets:   []float64{.002, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation"}),
+		dynamoDBThrottles: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "dynamodb_throttled_attempts_total",
+			Help:      "DynamoDB attempts rejected by throttling, by operation.",
+		}, []string{"operation"}),
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_lookups_total",
+			Help:      "Product cache lookups by result, hit or miss.",
+		}, []string{"result"}),
+		cacheWarmed: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cache_warmed_products",
+			Help:      "Products written to the cache by the last warming run.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.duration,
+		m.inFlight,
+		m.dynamoDBDuration,
+		m.dynamoDBThrottles,
+		m.cacheLookups,
+		m.cacheWarmed,
+	)
+	return m
+}
+
+// Handler serves the registry in the Prometheus exposition format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// StartRequest counts a request as in flight and returns the function that
+// records its outcome once it has been served
+func (m *Metrics) StartRequest(method, route string) func(status int) {
+	start := time.Now()
+	inFlight := m.inFlight.WithLabelValues(method, route)
+	inFlight.Inc()
+
+	return func(status int) {
+		inFlight.Dec()
+		m.duration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+		m.requests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	}
+}
+
+// ObserveDynamoDBCall records the latency of a DynamoDB call
+func (m *Metrics) ObserveDynamoDBCall(operation string, duration time.Duration) {
+	m.dynamoDBDuration.WithLabelValues(operation).Observe(duration.Seconds())
+}
+
+// ObserveDynamoDBThrottle counts a throttled DynamoDB attempt
+func (m *Metrics) ObserveDynamoDBThrottle(operation string) {
+	m.dynamoDBThrottles.WithLabelValues(operation).Inc()
+}
+
+// ObserveCacheLookup counts a product cache lookup as a hit or a miss
+func (m *Metrics) ObserveCacheLookup(hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.cacheLookups.WithLabelValues(result).Inc()
+}
+
+// SetCacheWarmed records how many products the last warming run cached
+func (m *Metrics) SetCacheWarmed(products int) {
+	m.cacheWarmed.Set(float64(products))
+}
@@ -0,0 +1,171 @@
+// Package migrations provisions the products table so a fresh environment
+// can boot without running cmd/migrate or Terraform first.
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/repository"
+)
+
+// tableActiveTimeout bounds how long a new table may take to become active
+const tableActiveTimeout = 5 * time.Minute
+
+// EnsureTable creates the table with the schema's keys, indexes, stream and
+// TTL settings when DescribeTable reports it missing. On an existing table
+// with the schema's keys it creates missing indexes and enables TTL,
+// leaving everything else alone; keys cannot change in place, so a table
+// keyed otherwise is reported. It reports whether the table was created.
+func EnsureTable(ctx context.Context, client *dynamodb.Client, tableName string, schema repository.TableSchema, logger *slog.Logger) (bool, error) {
+	table, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(tableName),
+	})
+	var notFound *types.ResourceNotFoundException
+	switch {
+	case errors.As(err, &notFound):
+		if err := createTable(ctx, client, tableName, schema, logger); err != nil {
+			return false, err
+		}
+		return true, nil
+	case err != nil:
+		return false, fmt.Errorf("failed to describe table %q: %w", tableName, err)
+	}
+	if mismatches := repository.KeyMismatches(schema.Keys, table.Table.KeySchema); len(mismatches) > 0 {
+		return false, &repository.SchemaMismatchError{Table: tableName, Mismatches: mismatches}
+	}
+
+	if err := repository.EnsureIndexes(ctx, client, tableName, schema.Indexes, logger); err != nil {
+		return false, err
+	}
+	if err := ensureTTL(ctx, client, tableName, schema.TTLAttribute, logger); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+func createTable(ctx context.Context, client *dynamodb.Client, tableName string, schema repository.TableSchema, logger *slog.Logger) error {
+	logger.Info("creating table", "table", tableName, "indexes", len(schema.Indexes))
+	if _, err := client.CreateTable(ctx, createTableInput(tableName, schema)); err != nil {
+		return fmt.Errorf("failed to create table %q: %w", tableName, err)
+	}
+
+	waiter := dynamodb.NewTableExistsWaiter(client)
+	if err := waiter.Wait(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(tableName)}, tableActiveTimeout); err != nil {
+		return fmt.Errorf("waiting for table %q: %w", tableName, err)
+	}
+	logger.Info("table active", "table", tableName)
+
+	return ensureTTL(ctx, client, tableName, schema.TTLAttribute, logger)
+}
+
+// createTableInput builds an on-demand table holding every declared index,
+// so a new table never needs the one-at-a-time index builds of EnsureIndexes
+func createTableInput(tableName string, schema repository.TableSchema) *dynamodb.CreateTableInput {
+	attributes := map[string]types.ScalarAttributeType{schema.Keys.HashKey: types.ScalarAttributeTypeS}
+	keySchema := []types.KeySchemaElement{
+		{AttributeName: aws.String(schema.Keys.HashKey), KeyType: types.KeyTypeHash},
+	}
+	if schema.Keys.RangeKey != "" {
+		attributes[schema.Keys.RangeKey] = types.ScalarAttributeTypeS
+		keySchema = append(keySchema, types.KeySchemaElement{AttributeName: aws.String(schema.Keys.RangeKey), KeyType: types.KeyTypeRange})
+	}
+
+	indexes := make([]types.GlobalSecondaryIndex, 0, len(schema.Indexes))
+	for _, index := range schema.Indexes {
+		attributes[index.Keys.HashKey] = types.ScalarAttributeTypeS
+		attributes[index.Keys.RangeKey] = index.RangeKeyType
+		indexes = append(indexes, types.GlobalSecondaryIndex{
+			IndexName: aws.String(index.Name),
+			KeySchema: []types.KeySchemaElement{
+				{AttributeName: aws.String(index.Keys.HashKey), KeyType: types.KeyTypeHash},
+				{AttributeName: aws.String(index.Keys.RangeKey), KeyType: types.KeyTypeRange},
+			},
+			Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+		})
+	}
+
+	// Attribute definitions in a stable order: table keys first, then by index
+	var definitions []types.AttributeDefinition
+	defined := map[string]bool{}
+	for _, name := range []string{schema.Keys.HashKey, schema.Keys.RangeKey} {
+		if name != "" {
+			defined[name] = true
+			definitions = append(definitions, types.AttributeDefinition{AttributeName: aws.String(name), AttributeType: attributes[name]})
+		}
+	}
+	for _, index := range schema.Indexes {
+		for _, name := range []string{index.Keys.HashKey, index.Keys.RangeKey} {
+			if defined[name] {
+				continue
+			}
+			defined[name] = true
+			definitions = append(definitions, types.AttributeDefinition{AttributeName: aws.String(name), AttributeType: attributes[name]})
+		}
+	}
+
+	input := &dynamodb.CreateTableInput{
+		TableName:            aws.String(tableName),
+		BillingMode:          types.BillingModePayPerRequest,
+		KeySchema:            keySchema,
+		AttributeDefinitions: definitions,
+		SSESpecification:     &types.SSESpecification{Enabled: aws.Bool(true)},
+	}
+	if len(indexes) > 0 {
+		input.GlobalSecondaryIndexes = indexes
+	}
+	if schema.StreamView != "" {
+		input.StreamSpecification = &types.StreamSpecification{
+			StreamEnabled:  aws.Bool(true),
+			StreamViewType: schema.StreamView,
+		}
+	}
+	return input
+}
+
+// ensureTTL enables time to live on attribute unless it already is. A table
+// with TTL on another attribute is reported, since switching it requires
+// disabling TTL first.
+func ensureTTL(ctx context.Context, client *dynamodb.Client, tableName, attribute string, logger *slog.Logger) error {
+	if attribute == "" {
+		return nil
+	}
+
+	output, err := client.DescribeTimeToLive(ctx, &dynamodb.DescribeTimeToLiveInput{
+		TableName: aws.String(tableName),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe time to live for %q: %w", tableName, err)
+	}
+	if ttl := output.TimeToLiveDescription; ttl != nil {
+		switch ttl.TimeToLiveStatus {
+		case types.TimeToLiveStatusEnabled, types.TimeToLiveStatusEnabling:
+			if current := aws.ToString(ttl.AttributeName); current != attribute {
+				return &repository.SchemaMismatchError{
+					Table:      tableName,
+					Mismatches: []string{fmt.Sprintf("time to live attribute is %q, expected %q", current, attribute)},
+				}
+			}
+			return nil
+		}
+	}
+
+	logger.Info("enabling time to live", "table", tableName, "attribute", attribute)
+	_, err = client.UpdateTimeToLive(ctx, &dynamodb.UpdateTimeToLiveInput{
+		TableName: aws.String(tableName),
+		TimeToLiveSpecification: &types.TimeToLiveSpecification{
+			AttributeName: aws.String(attribute),
+			Enabled:       aws.Bool(true),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to enable time to live for %q: %w", tableName, err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package migrations
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/tu-usuario/product-crud-hexagonal/internal/adapters/repository"
+)
+
+func TestCreateTableInput(t *testing.T) {
+	input := createTableInput("products", repository.ExpectedSchema())
+
+	assert.Equal(t, "products", aws.ToString(input.TableName))
+	assert.Equal(t, types.BillingModePayPerRequest, input.BillingMode)
+	assert.Equal(t, []types.KeySchemaElement{
+		{AttributeName: aws.String("pk"), KeyType: types.KeyTypeHash},
+		{AttributeName: aws.String("sk"), KeyType: types.KeyTypeRange},
+	}, input.KeySchema)
+	assert.Len(t, input.GlobalSecondaryIndexes, len(repository.ProductIndexes()))
+	assert.Nil(t, input.StreamSpecification)
+
+	// Every key attribute is defined exactly once, with the index's type
+	definitions := make(map[string]types.ScalarAttributeType)
+	for _, definition := range input.AttributeDefinitions {
+		name := aws.ToString(definition.AttributeName)
+		assert.NotContains(t, definitions, name)
+		definitions[name] = definition.AttributeType
+	}
+	assert.Equal(t, map[string]types.ScalarAttributeType{
+		"pk":         types.ScalarAttributeTypeS,
+		"sk":         types.ScalarAttributeTypeS,
+		"gsi_pk":     types.ScalarAttributeTypeS,
+		"price":      types.ScalarAttributeTypeN,
+		"created_at": types.ScalarAttributeTypeS,
+		"updated_at": types.ScalarAttributeTypeS,
+	}, definitions)
+}
+
+func TestCreateTableInput_Stream(t *testing.T) {
+	schema := repository.TableSchema{
+		Keys:       repository.KeySchema{HashKey: "id"},
+		StreamView: types.StreamViewTypeNewAndOldImages,
+	}
+	input := createTableInput("products", schema)
+
+	assert.Nil(t, input.GlobalSecondaryIndexes)
+	if assert.NotNil(t, input.StreamSpecification) {
+		assert.True(t, aws.ToBool(input.StreamSpecification.StreamEnabled))
+		assert.Equal(t, types.StreamViewTypeNewAndOldImages, input.StreamSpecification.StreamViewType)
+	}
+}
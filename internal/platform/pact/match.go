@@ -0,0 +1,315 @@
+package pact
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// matcher is one matching rule. Type matching, with its min and max for
+// arrays, applies to everything below the path it is declared on.
+type matcher struct {
+	Match string `json:"match"`
+	Regex string `json:"regex"`
+	Min   *int   `json:"min"`
+	Max   *int   `json:"max"`
+	Value string `json:"value"`
+}
+
+// cascades reports whether the matcher also applies below its path
+func (m matcher) cascades() bool {
+	return m.Match == "type" || (m.Match == "" && (m.Min != nil || m.Max != nil))
+}
+
+// check matches actual against the expected example
+func (m matcher) check(expected, actual any) error {
+	switch m.Match {
+	case "type", "":
+		if kind(expected) != kind(actual) {
+			return fmt.Errorf("expected a %s, got %s", kind(expected), describe(actual))
+		}
+		if items, ok := actual.([]any); ok {
+			if m.Min != nil && len(items) < *m.Min {
+				return fmt.Errorf("expected at least %d items, got %d", *m.Min, len(items))
+			}
+			if m.Max != nil && len(items) > *m.Max {
+				return fmt.Errorf("expected at most %d items, got %d", *m.Max, len(items))
+			}
+		}
+	case "regex":
+		pattern, err := regexp.Compile("^(?:" + m.Regex + ")$")
+		if err != nil {
+			return fmt.Errorf("invalid regex %q: %w", m.Regex, err)
+		}
+		text, ok := scalarText(actual)
+		if !ok || !pattern.MatchString(text) {
+			return fmt.Errorf("expected to match %q, got %s", m.Regex, describe(actual))
+		}
+	case "integer":
+		if number, ok := actual.(float64); !ok || number != math.Trunc(number) {
+			return fmt.Errorf("expected an integer, got %s", describe(actual))
+		}
+	case "decimal", "number":
+		if _, ok := actual.(float64); !ok {
+			return fmt.Errorf("expected a number, got %s", describe(actual))
+		}
+	case "boolean":
+		if _, ok := actual.(bool); !ok {
+			return fmt.Errorf("expected a boolean, got %s", describe(actual))
+		}
+	case "date", "time", "timestamp", "datetime":
+		// Formats are the consumer's; the provider only promises a string
+		if _, ok := actual.(string); !ok {
+			return fmt.Errorf("expected a %s string, got %s", m.Match, describe(actual))
+		}
+	case "include":
+		if text, ok := actual.(string); !ok || !strings.Contains(text, m.Value) {
+			return fmt.Errorf("expected to include %q, got %s", m.Value, describe(actual))
+		}
+	case "equality":
+		if !reflect.DeepEqual(expected, actual) {
+			return fmt.Errorf("expected %s, got %s", describe(expected), describe(actual))
+		}
+	case "null":
+		if actual != nil {
+			return fmt.Errorf("expected null, got %s", describe(actual))
+		}
+	default:
+		return fmt.Errorf("unsupported matcher %q", m.Match)
+	}
+	return nil
+}
+
+// ruleSet maps the paths of a response's body to their matchers
+type ruleSet []pathRule
+
+type pathRule struct {
+	path     []string
+	matchers []matcher
+}
+
+// rules are the matching rules of a response, by part
+type rules struct {
+	body ruleSet
+	// header is keyed by lowercase header name
+	header map[string][]matcher
+}
+
+// parseRules reads version 2 rules, keyed by paths such as $.body.id and
+// $.headers.Content-Type, and version 3 rules, grouped by body and header
+func parseRules(raw json.RawMessage) (rules, error) {
+	parsed := rules{header: map[string][]matcher{}}
+	if len(raw) == 0 || string(raw) == "null" {
+		return parsed, nil
+	}
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &sections); err != nil {
+		return rules{}, fmt.Errorf("invalid matching rules: %w", err)
+	}
+
+	for key, value := range sections {
+		switch {
+		case key == "body" || key == "header":
+			var entries map[string]struct {
+				Matchers []matcher `json:"matchers"`
+			}
+			if err := json.Unmarshal(value, &entries); err != nil {
+				return rules{}, fmt.Errorf("invalid %s matching rules: %w", key, err)
+			}
+			for path, entry := range entries {
+				if key == "header" {
+					parsed.header[strings.ToLower(path)] = entry.Matchers
+					continue
+				}
+				parsed.body = append(parsed.body, pathRule{path: parsePath(path), matchers: entry.Matchers})
+			}
+		case strings.HasPrefix(key, "$.headers."):
+			var m matcher
+			if err := json.Unmarshal(value, &m); err != nil {
+				return rules{}, fmt.Errorf("invalid matching rule %s: %w", key, err)
+			}
+			name := strings.ToLower(strings.TrimPrefix(key, "$.headers."))
+			parsed.header[name] = append(parsed.header[name], m)
+		case key == "$.body" || strings.HasPrefix(key, "$.body.") || strings.HasPrefix(key, "$.body["):
+			var m matcher
+			if err := json.Unmarshal(value, &m); err != nil {
+				return rules{}, fmt.Errorf("invalid matching rule %s: %w", key, err)
+			}
+			parsed.body = append(parsed.body, pathRule{path: parsePath("$" + strings.TrimPrefix(key, "$.body")), matchers: []matcher{m}})
+		}
+		// Rules on other parts, such as the status, are not checked
+	}
+	return parsed, nil
+}
+
+// parsePath splits a JSON path such as $.items[*].id or $['a b'] into its
+// keys and indexes, with * for wildcards
+func parsePath(path string) []string {
+	path = strings.TrimPrefix(path, "$")
+	var tokens []string
+	for len(path) > 0 {
+		switch path[0] {
+		case '.':
+			path = path[1:]
+			end := strings.IndexAny(path, ".[")
+			if end < 0 {
+				end = len(path)
+			}
+			tokens = append(tokens, path[:end])
+			path = path[end:]
+		case '[':
+			end := strings.IndexByte(path, ']')
+			if end < 0 {
+				return append(tokens, path)
+			}
+			tokens = append(tokens, strings.Trim(path[1:end], `'"`))
+			path = path[end+1:]
+		default:
+			return append(tokens, path)
+		}
+	}
+	return tokens
+}
+
+// at returns the matchers of the most specific rule covering path; exact
+// keys beat wildcards
+func (s ruleSet) at(path []string) []matcher {
+	var best []matcher
+	bestScore := -1
+	for _, rule := range s {
+		if len(rule.path) != len(path) {
+			continue
+		}
+		score := 0
+		for i, token := range rule.path {
+			if token == path[i] {
+				score++
+			} else if token != "*" {
+				score = -1
+				break
+			}
+		}
+		if score > bestScore {
+			best, bestScore = rule.matchers, score
+		}
+	}
+	return best
+}
+
+// compare matches actual against expected at path, under the matchers
+// inherited from above, returning every mismatch
+func (s ruleSet) compare(expected, actual any, path []string, inherited []matcher) []error {
+	matchers := s.at(path)
+	if matchers == nil {
+		matchers = inherited
+	}
+	var cascade []matcher
+	var mismatches []error
+	for _, m := range matchers {
+		if err := m.check(expected, actual); err != nil {
+			mismatches = append(mismatches, fmt.Errorf("body %s: %w", formatPath(path), err))
+		}
+		// Bounds on an array's length hold at their own path only
+		if m.cascades() {
+			cascade = append(cascade, matcher{Match: "type"})
+		}
+	}
+	if len(mismatches) > 0 {
+		return mismatches
+	}
+
+	switch want := expected.(type) {
+	case map[string]any:
+		got, ok := actual.(map[string]any)
+		if !ok {
+			return []error{fmt.Errorf("body %s: expected an object, got %s", formatPath(path), describe(actual))}
+		}
+		// Providers may return more than the consumer reads
+		for key, value := range want {
+			child := append(append([]string(nil), path...), key)
+			present, ok := got[key]
+			if !ok {
+				mismatches = append(mismatches, fmt.Errorf("body %s: missing", formatPath(child)))
+				continue
+			}
+			mismatches = append(mismatches, s.compare(value, present, child, cascade)...)
+		}
+	case []any:
+		got, ok := actual.([]any)
+		if !ok {
+			return []error{fmt.Errorf("body %s: expected an array, got %s", formatPath(path), describe(actual))}
+		}
+		// Under type matching every item follows the example's first one
+		if len(cascade) == 0 && len(got) != len(want) {
+			return []error{fmt.Errorf("body %s: expected %d items, got %d", formatPath(path), len(want), len(got))}
+		}
+		if len(want) == 0 {
+			break
+		}
+		for i, value := range got {
+			example := want[min(i, len(want)-1)]
+			child := append(append([]string(nil), path...), strconv.Itoa(i))
+			mismatches = append(mismatches, s.compare(example, value, child, cascade)...)
+		}
+	default:
+		if len(matchers) == 0 && !reflect.DeepEqual(expected, actual) {
+			mismatches = append(mismatches, fmt.Errorf("body %s: expected %s, got %s", formatPath(path), describe(expected), describe(actual)))
+		}
+	}
+	return mismatches
+}
+
+func formatPath(path []string) string {
+	var b strings.Builder
+	b.WriteString("$")
+	for _, token := range path {
+		if _, err := strconv.Atoi(token); err == nil {
+			b.WriteString("[" + token + "]")
+			continue
+		}
+		b.WriteString("." + token)
+	}
+	return b.String()
+}
+
+// kind names the JSON type of a decoded value
+func kind(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func scalarText(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	return "", false
+}
+
+func describe(value any) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return kind(value)
+	}
+	return string(encoded)
+}
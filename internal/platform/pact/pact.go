@@ -0,0 +1,175 @@
+// Package pact verifies a provider against consumer-driven contracts in
+// the JSON format of the Pact specification, versions 2 and 3. Consumers
+// publish one pact file per consumer and provider; the provider replays
+// each interaction against its HTTP handler, after putting itself in the
+// interaction's provider states, and checks the response with the pact's
+// matching rules.
+package pact
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// File is one consumer's contract with one provider
+type File struct {
+	Consumer     Participant   `json:"consumer"`
+	Provider     Participant   `json:"provider"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+type Participant struct {
+	Name string `json:"name"`
+}
+
+// Interaction is a request the consumer makes and the response it relies on
+type Interaction struct {
+	Description string `json:"description"`
+	// ProviderState is the single state of version 2 pacts
+	ProviderState  string          `json:"providerState"`
+	ProviderStates []ProviderState `json:"providerStates"`
+	Request        Request         `json:"request"`
+	Response       Response        `json:"response"`
+}
+
+// ProviderState names a precondition of an interaction, such as a product
+// that must exist, with the values the consumer relies on
+type ProviderState struct {
+	Name   string         `json:"name"`
+	Params map[string]any `json:"params"`
+}
+
+// States returns the interaction's provider states, whichever version of
+// the specification the pact follows
+func (i Interaction) States() []ProviderState {
+	if len(i.ProviderStates) > 0 {
+		return i.ProviderStates
+	}
+	if i.ProviderState != "" {
+		return []ProviderState{{Name: i.ProviderState}}
+	}
+	return nil
+}
+
+type Request struct {
+	Method  string          `json:"method"`
+	Path    string          `json:"path"`
+	Query   Query           `json:"query"`
+	Headers Headers         `json:"headers"`
+	Body    json.RawMessage `json:"body"`
+}
+
+type Response struct {
+	Status        int             `json:"status"`
+	Headers       Headers         `json:"headers"`
+	Body          json.RawMessage `json:"body"`
+	MatchingRules json.RawMessage `json:"matchingRules"`
+}
+
+// Query is the query string of a request, written as a string in version
+// 2 pacts and as a map of values in version 3
+type Query url.Values
+
+func (q *Query) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		values, err := url.ParseQuery(raw)
+		if err != nil {
+			return fmt.Errorf("invalid query %q: %w", raw, err)
+		}
+		*q = Query(values)
+		return nil
+	}
+	var values map[string][]string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("invalid query: %w", err)
+	}
+	*q = Query(values)
+	return nil
+}
+
+// Headers maps header names to their values; a header given as a list of
+// values is joined with commas
+type Headers map[string]string
+
+func (h *Headers) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("invalid headers: %w", err)
+	}
+	*h = make(Headers, len(raw))
+	for name, value := range raw {
+		var single string
+		if err := json.Unmarshal(value, &single); err == nil {
+			(*h)[name] = single
+			continue
+		}
+		var values []string
+		if err := json.Unmarshal(value, &values); err != nil {
+			return fmt.Errorf("invalid value of header %s: %w", name, err)
+		}
+		(*h)[name] = strings.Join(values, ", ")
+	}
+	return nil
+}
+
+// Load reads the pact files of source: a file, a directory of .json files
+// or an http(s) URL, such as a pact broker's link to the latest pact
+func Load(source string) ([]File, error) {
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		response, err := http.Get(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch pact %s: %w", source, err)
+		}
+		defer response.Body.Close()
+		if response.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to fetch pact %s: status %d", source, response.StatusCode)
+		}
+		file, err := decode(response.Body)
+		if err != nil {
+			return nil, fmt.Errorf("pact %s: %w", source, err)
+		}
+		return []File{file}, nil
+	}
+
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, err
+	}
+	paths := []string{source}
+	if info.IsDir() {
+		if paths, err = filepath.Glob(filepath.Join(source, "*.json")); err != nil {
+			return nil, err
+		}
+		sort.Strings(paths)
+	}
+	files := make([]File, 0, len(paths))
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		file, err := decode(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("pact %s: %w", path, err)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+func decode(r io.Reader) (File, error) {
+	var file File
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return File{}, fmt.Errorf("invalid pact: %w", err)
+	}
+	return file, nil
+}
@@ -0,0 +1,85 @@
+package pact
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifier(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Write([]byte(`{"id":"p-42","price":19.5,"tags":["a","b","c"],"extra":true}`))
+	})
+	interaction := func(body, rules string) Interaction {
+		return Interaction{
+			Request: Request{Method: http.MethodGet, Path: "/products/p-42"},
+			Response: Response{
+				Status:        200,
+				Headers:       Headers{"Content-Type": "application/json"},
+				Body:          json.RawMessage(body),
+				MatchingRules: json.RawMessage(rules),
+			},
+		}
+	}
+
+	t.Run("matching rules", func(t *testing.T) {
+		verifier := Verifier{Handler: handler}
+		err := verifier.Verify(interaction(`{"id":"p-1","price":1,"tags":["x"]}`, `{
+			"$.body.id": {"match": "regex", "regex": "p-\\d+"},
+			"$.body.price": {"match": "decimal"},
+			"$.body.tags": {"min": 1, "match": "type"}
+		}`))
+		assert.NoError(t, err)
+	})
+
+	t.Run("version 3 rules", func(t *testing.T) {
+		verifier := Verifier{Handler: handler}
+		err := verifier.Verify(interaction(`{"id":"p-1","tags":["x"]}`, `{
+			"body": {"$": {"matchers": [{"match": "type"}]}}
+		}`))
+		assert.NoError(t, err)
+	})
+
+	t.Run("mismatches", func(t *testing.T) {
+		verifier := Verifier{Handler: handler}
+		err := verifier.Verify(interaction(`{"id":"p-1","price":"19.5","tags":["x"],"name":"n"}`, `{
+			"$.body.tags": {"max": 2, "match": "type"}
+		}`))
+		require.Error(t, err)
+		assert.ErrorContains(t, err, `body $.id: expected "p-1", got "p-42"`)
+		assert.ErrorContains(t, err, "body $.price")
+		assert.ErrorContains(t, err, "expected at most 2 items, got 3")
+		assert.ErrorContains(t, err, "body $.name: missing")
+	})
+
+	t.Run("provider states", func(t *testing.T) {
+		var got []string
+		verifier := Verifier{
+			Handler: handler,
+			States: map[string]StateHandler{
+				"product exists": func(params map[string]any) error {
+					got = append(got, params["id"].(string))
+					return nil
+				},
+			},
+		}
+		exists := interaction(`{"id":"p-42"}`, "")
+		exists.ProviderStates = []ProviderState{{Name: "product exists", Params: map[string]any{"id": "p-42"}}}
+		assert.NoError(t, verifier.Verify(exists))
+		assert.Equal(t, []string{"p-42"}, got)
+
+		unknown := interaction(`{"id":"p-42"}`, "")
+		unknown.ProviderState = "product missing"
+		assert.ErrorContains(t, verifier.Verify(unknown), `unknown provider state "product missing"`)
+	})
+}
+
+func TestParsePath(t *testing.T) {
+	assert.Equal(t, []string{"items", "*", "id"}, parsePath("$.items[*].id"))
+	assert.Equal(t, []string{"a b", "0"}, parsePath("$['a b'][0]"))
+	assert.Nil(t, parsePath("$"))
+}
@@ -0,0 +1,148 @@
+package pact
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+)
+
+// StateHandler puts the provider in a provider state, using the values
+// the consumer gave for it
+type StateHandler func(params map[string]any) error
+
+// Verifier replays interactions against Handler in-process
+type Verifier struct {
+	Handler http.Handler
+	// States sets up each provider state pacts may name; an interaction
+	// naming a state missing here fails
+	States map[string]StateHandler
+	// Reset, when set, runs before every interaction so none sees the
+	// state left by another
+	Reset func()
+}
+
+// Verify replays interaction, returning every way the response breaks it
+func (v Verifier) Verify(interaction Interaction) error {
+	if v.Reset != nil {
+		v.Reset()
+	}
+	for _, state := range interaction.States() {
+		setUp, ok := v.States[state.Name]
+		if !ok {
+			return fmt.Errorf("unknown provider state %q", state.Name)
+		}
+		if err := setUp(state.Params); err != nil {
+			return fmt.Errorf("failed to set up provider state %q: %w", state.Name, err)
+		}
+	}
+
+	request, err := newRequest(interaction.Request)
+	if err != nil {
+		return err
+	}
+	recorder := httptest.NewRecorder()
+	v.Handler.ServeHTTP(recorder, request)
+	return check(interaction.Response, recorder.Result())
+}
+
+func newRequest(spec Request) (*http.Request, error) {
+	var body io.Reader
+	if len(spec.Body) > 0 && string(spec.Body) != "null" {
+		raw := []byte(spec.Body)
+		// A string body of a request that is not JSON is sent as the text
+		var text string
+		if !isJSON(spec.Headers["Content-Type"]) && json.Unmarshal(raw, &text) == nil {
+			raw = []byte(text)
+		}
+		body = bytes.NewReader(raw)
+	}
+	target := spec.Path
+	if len(spec.Query) > 0 {
+		target += "?" + url.Values(spec.Query).Encode()
+	}
+	request, err := http.NewRequest(spec.Method, target, body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	for name, value := range spec.Headers {
+		request.Header.Set(name, value)
+	}
+	return request, nil
+}
+
+func check(expected Response, actual *http.Response) error {
+	rules, err := parseRules(expected.MatchingRules)
+	if err != nil {
+		return err
+	}
+
+	var mismatches []error
+	if expected.Status != 0 && actual.StatusCode != expected.Status {
+		mismatches = append(mismatches, fmt.Errorf("status: expected %d, got %d", expected.Status, actual.StatusCode))
+	}
+	for name, value := range expected.Headers {
+		got := actual.Header.Get(name)
+		if matchers := rules.header[strings.ToLower(name)]; len(matchers) > 0 {
+			for _, m := range matchers {
+				if err := m.check(value, got); err != nil {
+					mismatches = append(mismatches, fmt.Errorf("header %s: %w", name, err))
+				}
+			}
+			continue
+		}
+		if !headerMatches(name, value, got) {
+			mismatches = append(mismatches, fmt.Errorf("header %s: expected %q, got %q", name, value, got))
+		}
+	}
+
+	if len(expected.Body) > 0 {
+		var want any
+		if err := json.Unmarshal(expected.Body, &want); err != nil {
+			return fmt.Errorf("invalid expected body: %w", err)
+		}
+		var got any
+		body, _ := io.ReadAll(actual.Body)
+		if err := json.Unmarshal(body, &got); err != nil {
+			mismatches = append(mismatches, fmt.Errorf("body: not JSON: %q", body))
+		} else {
+			mismatches = append(mismatches, rules.body.compare(want, got, nil, nil)...)
+		}
+	}
+	return errors.Join(mismatches...)
+}
+
+// headerMatches compares header values ignoring the spaces after commas.
+// A content type only needs the parameters the pact names, so
+// application/json accepts application/json; charset=utf-8.
+func headerMatches(name, expected, actual string) bool {
+	if strings.EqualFold(name, "Content-Type") {
+		wantType, wantParams, err := mime.ParseMediaType(expected)
+		if err != nil {
+			return expected == actual
+		}
+		gotType, gotParams, err := mime.ParseMediaType(actual)
+		if err != nil || wantType != gotType {
+			return false
+		}
+		for param, value := range wantParams {
+			if !strings.EqualFold(gotParams[param], value) {
+				return false
+			}
+		}
+		return true
+	}
+	normalize := func(value string) string { return strings.ReplaceAll(value, ", ", ",") }
+	return normalize(expected) == normalize(actual)
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
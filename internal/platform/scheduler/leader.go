@@ -0,0 +1,37 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Lock is a lease shared by every instance of the service. Acquire returns
+// true when the caller holds the lease named name for the next ttl, either
+// by taking a free or expired lease or by renewing its own.
+type Lock interface {
+	Acquire(ctx context.Context, name string, ttl time.Duration) (bool, error)
+}
+
+// leaseIntervals is how many job intervals a lease lasts. Renewing a lease
+// that would expire right at the next tick races the other instances, and
+// a late tick or a run overlapping it would hand the job over to them.
+const leaseIntervals = 2
+
+// Leader wraps job so that only the instance holding the lease runs it. The
+// lease lasts leaseIntervals intervals and is renewed on every run, so the
+// leader keeps it while it is healthy and another instance takes over once
+// it has missed a run.
+func Leader(lock Lock, name string, interval time.Duration, logger *slog.Logger, job func(context.Context) error) func(context.Context) error {
+	return func(ctx context.Context) error {
+		acquired, err := lock.Acquire(ctx, name, leaseIntervals*interval)
+		if err != nil {
+			return err
+		}
+		if !acquired {
+			logger.Debug("not the leader, skipping scheduled job", "job", name)
+			return nil
+		}
+		return job(ctx)
+	}
+}
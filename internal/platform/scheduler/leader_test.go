@@ -0,0 +1,39 @@
+package scheduler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLock grants the lease while held is false and records the TTLs asked for
+type fakeLock struct {
+	held bool
+	ttls []time.Duration
+}
+
+func (l *fakeLock) Acquire(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	l.ttls = append(l.ttls, ttl)
+	return !l.held, nil
+}
+
+func TestLeader(t *testing.T) {
+	lock := &fakeLock{}
+	runs := 0
+	job := Leader(lock, "job", time.Minute, slog.New(slog.NewTextHandler(io.Discard, nil)), func(context.Context) error {
+		runs++
+		return nil
+	})
+
+	require.NoError(t, job(context.Background()))
+	lock.held = true
+	require.NoError(t, job(context.Background()))
+
+	assert.Equal(t, 1, runs, "only the lease holder runs the job")
+	assert.Equal(t, []time.Duration{2 * time.Minute, 2 * time.Minute}, lock.ttls, "the lease outlives the next tick")
+}
@@ -0,0 +1,29 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Run executes job immediately and then every interval until ctx is done.
+// Failures are logged and retried on the next tick.
+func Run(ctx context.Context, name string, interval time.Duration, logger *slog.Logger, job func(context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		start := time.Now()
+		if err := job(ctx); err != nil && ctx.Err() == nil {
+			logger.Error("scheduled job failed", "job", name, "error", err)
+		} else {
+			logger.Debug("scheduled job finished", "job", name, "duration", time.Since(start))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// Package server builds the HTTP server of cmd/api: plain HTTP or HTTPS,
+// with HTTP/2 and optional verification of client certificates for
+// service-to-service calls.
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/config"
+)
+
+// selfSignedValidity is how long a generated development certificate lasts
+const selfSignedValidity = 365 * 24 * time.Hour
+
+// New returns a server for handler on cfg.Port. It serves HTTPS when a
+// certificate is configured or self-signed is requested, and HTTP/2 over
+// TLS always, or over cleartext (h2c) when HTTP2_CLEARTEXT is set.
+func New(cfg *config.Config, handler http.Handler) (*http.Server, error) {
+	srv := &http.Server{
+		Addr:      ":" + cfg.Port,
+		Handler:   handler,
+		Protocols: new(http.Protocols),
+	}
+	srv.Protocols.SetHTTP1(true)
+	srv.Protocols.SetHTTP2(true)
+
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	srv.TLSConfig = tlsConfig
+	if tlsConfig == nil && cfg.HTTP2Cleartext {
+		srv.Protocols.SetUnencryptedHTTP2(true)
+	}
+	return srv, nil
+}
+
+// ListenAndServe serves srv over TLS when it has a TLS config and over
+// plain HTTP otherwise
+func ListenAndServe(srv *http.Server) error {
+	if srv.TLSConfig != nil {
+		return srv.ListenAndServeTLS("", "")
+	}
+	return srv.ListenAndServe()
+}
+
+// newTLSConfig returns nil when TLS is not configured
+func newTLSConfig(cfg *config.Config) (*tls.Config, error) {
+	var certificate tls.Certificate
+	var err error
+	switch {
+	case cfg.TLSCertFile != "":
+		certificate, err = tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+		}
+	case cfg.TLSSelfSigned:
+		certificate, err = selfSignedCertificate(x509.ExtKeyUsageServerAuth)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate self-signed certificate: %w", err)
+		}
+	default:
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{certificate},
+	}
+	if cfg.TLSClientCAFile != "" {
+		data, err := os.ReadFile(cfg.TLSClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, errors.New("client CA file holds no PEM certificates")
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
+
+// selfSignedCertificate generates a certificate for localhost, 127.0.0.1
+// and ::1, for development only: clients have to trust it explicitly
+func selfSignedCertificate(usage x509.ExtKeyUsage) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "localhost", Organization: []string{"product-api development"}},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(selfSignedValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{usage},
+		BasicConstraintsValid: true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
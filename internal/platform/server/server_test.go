@@ -0,0 +1,96 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/config"
+)
+
+// serve starts srv on a loopback port and returns its HTTPS URL
+func serve(t *testing.T, srv *http.Server) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = srv.ServeTLS(listener, "", "") }()
+	t.Cleanup(func() { _ = srv.Close() })
+	return "https://" + listener.Addr().String()
+}
+
+func client(certificates ...tls.Certificate) *http.Client {
+	return &http.Client{Transport: &http.Transport{
+		ForceAttemptHTTP2: true,
+		TLSClientConfig:   &tls.Config{InsecureSkipVerify: true, Certificates: certificates},
+	}}
+}
+
+var ok = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNoContent)
+})
+
+func TestNew_PlainHTTP(t *testing.T) {
+	srv, err := New(&config.Config{Port: "8080"}, ok)
+	require.NoError(t, err)
+	assert.Equal(t, ":8080", srv.Addr)
+	assert.Nil(t, srv.TLSConfig)
+	assert.False(t, srv.Protocols.UnencryptedHTTP2())
+
+	srv, err = New(&config.Config{Port: "8080", HTTP2Cleartext: true}, ok)
+	require.NoError(t, err)
+	assert.True(t, srv.Protocols.UnencryptedHTTP2())
+}
+
+func TestNew_SelfSignedServesHTTP2(t *testing.T) {
+	srv, err := New(&config.Config{Port: "0", TLSSelfSigned: true}, ok)
+	require.NoError(t, err)
+	url := serve(t, srv)
+
+	resp, err := client().Get(url)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, 2, resp.ProtoMajor)
+}
+
+func TestNew_MutualTLS(t *testing.T) {
+	clientCert, err := selfSignedCertificate(x509.ExtKeyUsageClientAuth)
+	require.NoError(t, err)
+	caFile := filepath.Join(t.TempDir(), "clients.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: clientCert.Certificate[0]}), 0o600))
+
+	srv, err := New(&config.Config{Port: "0", TLSSelfSigned: true, TLSClientCAFile: caFile}, ok)
+	require.NoError(t, err)
+	url := serve(t, srv)
+
+	_, err = client().Get(url)
+	assert.Error(t, err, "clients without a certificate are rejected")
+
+	other, err := selfSignedCertificate(x509.ExtKeyUsageClientAuth)
+	require.NoError(t, err)
+	_, err = client(other).Get(url)
+	assert.Error(t, err, "certificates from unknown CAs are rejected")
+
+	resp, err := client(clientCert).Get(url)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
+
+func TestNew_InvalidFiles(t *testing.T) {
+	dir := t.TempDir()
+	_, err := New(&config.Config{TLSCertFile: filepath.Join(dir, "missing.pem"), TLSKeyFile: filepath.Join(dir, "missing.key")}, ok)
+	assert.ErrorContains(t, err, "failed to load TLS certificate")
+
+	caFile := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, []byte("not a certificate"), 0o600))
+	_, err = New(&config.Config{TLSSelfSigned: true, TLSClientCAFile: caFile}, ok)
+	assert.ErrorContains(t, err, "no PEM certificates")
+}
@@ -0,0 +1,62 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-sdk-go-v2/otelaws"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
+
+	"github.com/tu-usuario/product-crud-hexagonal/internal/platform/config"
+)
+
+// Setup installs a global tracer provider that batches spans to an OTLP/HTTP
+// collector, and the W3C trace context propagator so traces continue across
+// services. The collector address comes from the standard
+// OTEL_EXPORTER_OTLP_ENDPOINT variable. The returned function flushes
+// pending spans and must be called on shutdown.
+func Setup(ctx context.Context, cfg *config.Config) (func(context.Context) error, error) {
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(cfg.TracingServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build telemetry resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		// Follow the caller's decision so a trace is never recorded in part
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.TracingSampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return provider.Shutdown, nil
+}
+
+// Middleware starts a server span per request and puts it in the request
+// context, which handlers already hand down to services and repositories
+func Middleware(serviceName string) gin.HandlerFunc {
+	return otelgin.Middleware(serviceName)
+}
+
+// InstrumentAWS adds a client span to every AWS SDK call made with awsCfg,
+// as a child of the span in the call's context
+func InstrumentAWS(awsCfg *aws.Config) {
+	otelaws.AppendMiddlewares(&awsCfg.APIOptions)
+}
@@ -0,0 +1,66 @@
+package telemetry
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type emptyItemTransport struct{}
+
+func (emptyItemTransport) Do(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/x-amz-json-1.0"}},
+		Body:       io.NopCloser(strings.NewReader(`{}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestRequestTraceReachesDynamoDB(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	awsCfg := aws.Config{Region: "us-east-1", Credentials: aws.AnonymousCredentials{}, HTTPClient: emptyItemTransport{}}
+	InstrumentAWS(&awsCfg)
+	client := dynamodb.NewFromConfig(awsCfg)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Middleware("product-api"))
+	router.GET("/products/:id", func(c *gin.Context) {
+		_, err := client.GetItem(c.Request.Context(), &dynamodb.GetItemInput{
+			TableName: aws.String("products"),
+			Key:       map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: c.Param("id")}},
+		})
+		require.NoError(t, err)
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/products/1", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	dynamoSpan, serverSpan := spans[0], spans[1]
+	assert.Equal(t, "GET /products/:id", serverSpan.Name())
+	assert.Equal(t, "DynamoDB.GetItem", dynamoSpan.Name())
+	assert.Equal(t, serverSpan.SpanContext().TraceID(), dynamoSpan.SpanContext().TraceID())
+	assert.Equal(t, serverSpan.SpanContext().SpanID(), dynamoSpan.Parent().SpanID())
+}
@@ -30,6 +30,92 @@ resource "random_string" "suffix" {
   upper   = false
 }
 
+# Single table for products, their reviews and categories; the key layout
+# is documented in internal/adapters/repository/keys.go
+resource "aws_dynamodb_table" "catalog" {
+  name         = "${var.catalog_table_name}-${random_string.suffix.result}"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "pk"
+  range_key    = "sk"
+
+  attribute {
+    name = "pk"
+    type = "S"
+  }
+
+  attribute {
+    name = "sk"
+    type = "S"
+  }
+
+  # Sort indexes mirror the spec in internal/adapters/repository/indexes.go;
+  # keep both in sync (cmd/migrate provisions them on existing tables)
+  attribute {
+    name = "gsi_pk"
+    type = "S"
+  }
+
+  attribute {
+    name = "price"
+    type = "N"
+  }
+
+  attribute {
+    name = "created_at"
+    type = "S"
+  }
+
+  attribute {
+    name = "updated_at"
+    type = "S"
+  }
+
+  global_secondary_index {
+    name            = "price-index"
+    hash_key        = "gsi_pk"
+    range_key       = "price"
+    projection_type = "ALL"
+  }
+
+  global_secondary_index {
+    name            = "created_at-index"
+    hash_key        = "gsi_pk"
+    range_key       = "created_at"
+    projection_type = "ALL"
+  }
+
+  global_secondary_index {
+    name            = "updated_at-index"
+    hash_key        = "gsi_pk"
+    range_key       = "updated_at"
+    projection_type = "ALL"
+  }
+
+  ttl {
+    attribute_name = "expires_at"
+    enabled        = true
+  }
+
+  # Read by cmd/streams, which publishes every change to product_changes
+  stream_enabled   = true
+  stream_view_type = "NEW_AND_OLD_IMAGES"
+
+  server_side_encryption {
+    enabled = true
+  }
+
+  point_in_time_recovery {
+    enabled = true
+  }
+
+  tags = {
+    Name = "Catalog Table"
+  }
+}
+
+# Tables from before the single-table layout. They are kept, and protected
+# from being destroyed, until cmd/migrate has copied them into the catalog
+# table (see README); a follow-up change removes them.
 resource "aws_dynamodb_table" "products" {
   name         = "${var.table_name}-${random_string.suffix.result}"
   billing_mode = "PAY_PER_REQUEST"
@@ -40,6 +126,55 @@ resource "aws_dynamodb_table" "products" {
     type = "S"
   }
 
+  attribute {
+    name = "gsi_pk"
+    type = "S"
+  }
+
+  attribute {
+    name = "price"
+    type = "N"
+  }
+
+  attribute {
+    name = "created_at"
+    type = "S"
+  }
+
+  attribute {
+    name = "updated_at"
+    type = "S"
+  }
+
+  global_secondary_index {
+    name            = "price-index"
+    hash_key        = "gsi_pk"
+    range_key       = "price"
+    projection_type = "ALL"
+  }
+
+  global_secondary_index {
+    name            = "created_at-index"
+    hash_key        = "gsi_pk"
+    range_key       = "created_at"
+    projection_type = "ALL"
+  }
+
+  global_secondary_index {
+    name            = "updated_at-index"
+    hash_key        = "gsi_pk"
+    range_key       = "updated_at"
+    projection_type = "ALL"
+  }
+
+  ttl {
+    attribute_name = "expires_at"
+    enabled        = true
+  }
+
+  stream_enabled   = true
+  stream_view_type = "NEW_AND_OLD_IMAGES"
+
   server_side_encryption {
     enabled = true
   }
@@ -48,11 +183,471 @@ resource "aws_dynamodb_table" "products" {
     enabled = true
   }
 
+  lifecycle {
+    prevent_destroy = true
+  }
+
   tags = {
     Name = "Products Table"
   }
 }
 
+resource "aws_dynamodb_table" "product_reviews" {
+  name         = "${var.reviews_table_name}-${random_string.suffix.result}"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "product_id"
+  range_key    = "id"
+
+  attribute {
+    name = "product_id"
+    type = "S"
+  }
+
+  # Time-ordered UUIDs, so a product's reviews sort by creation
+  attribute {
+    name = "id"
+    type = "S"
+  }
+
+  point_in_time_recovery {
+    enabled = true
+  }
+
+  server_side_encryption {
+    enabled = true
+  }
+
+  lifecycle {
+    prevent_destroy = true
+  }
+
+  tags = {
+    Name = "Product Reviews Table"
+  }
+}
+
+resource "aws_dynamodb_table" "categories" {
+  name         = "${var.categories_table_name}-${random_string.suffix.result}"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "id"
+
+  attribute {
+    name = "id"
+    type = "S"
+  }
+
+  server_side_encryption {
+    enabled = true
+  }
+
+  lifecycle {
+    prevent_destroy = true
+  }
+
+  tags = {
+    Name = "Categories Table"
+  }
+}
+
+resource "aws_dynamodb_table" "product_views" {
+  name         = "${var.views_table_name}-${random_string.suffix.result}"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "product_id"
+  range_key    = "day"
+
+  attribute {
+    name = "product_id"
+    type = "S"
+  }
+
+  attribute {
+    name = "day"
+    type = "S"
+  }
+
+  global_secondary_index {
+    name            = "day-index"
+    hash_key        = "day"
+    range_key       = "product_id"
+    projection_type = "ALL"
+  }
+
+  server_side_encryption {
+    enabled = true
+  }
+
+  tags = {
+    Name = "Product Views Table"
+  }
+}
+
+resource "aws_dynamodb_table" "search_terms" {
+  name         = "${var.search_terms_table_name}-${random_string.suffix.result}"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "term"
+  range_key    = "day"
+
+  attribute {
+    name = "term"
+    type = "S"
+  }
+
+  attribute {
+    name = "day"
+    type = "S"
+  }
+
+  global_secondary_index {
+    name            = "day-index"
+    hash_key        = "day"
+    range_key       = "term"
+    projection_type = "ALL"
+  }
+
+  server_side_encryption {
+    enabled = true
+  }
+
+  tags = {
+    Name = "Search Terms Table"
+  }
+}
+
+resource "aws_dynamodb_table" "product_cooccurrence" {
+  name         = "${var.recommendations_table_name}-${random_string.suffix.result}"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "product_id"
+  range_key    = "related_id"
+
+  attribute {
+    name = "product_id"
+    type = "S"
+  }
+
+  attribute {
+    name = "related_id"
+    type = "S"
+  }
+
+  # Session histories expire, co-occurrence counters have no expires_at
+  ttl {
+    attribute_name = "expires_at"
+    enabled        = true
+  }
+
+  server_side_encryption {
+    enabled = true
+  }
+
+  tags = {
+    Name = "Product Co-occurrence Table"
+  }
+}
+
+resource "aws_dynamodb_table" "product_tombstones" {
+  name         = "${var.tombstones_table_name}-${random_string.suffix.result}"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "id"
+
+  attribute {
+    name = "id"
+    type = "S"
+  }
+
+  attribute {
+    name = "gsi_pk"
+    type = "S"
+  }
+
+  attribute {
+    name = "deleted_at"
+    type = "S"
+  }
+
+  # Each tenant's deletions in time order, read by the change feed
+  global_secondary_index {
+    name            = "deleted_at-index"
+    hash_key        = "gsi_pk"
+    range_key       = "deleted_at"
+    projection_type = "ALL"
+  }
+
+  server_side_encryption {
+    enabled = true
+  }
+
+  tags = {
+    Name = "Product Tombstones Table"
+  }
+}
+
+resource "aws_dynamodb_table" "product_audit" {
+  name         = "${var.audit_table_name}-${random_string.suffix.result}"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "product_id"
+  range_key    = "sk"
+
+  attribute {
+    name = "product_id"
+    type = "S"
+  }
+
+  # occurred_at#id, so a product's entries sort by time
+  attribute {
+    name = "sk"
+    type = "S"
+  }
+
+  point_in_time_recovery {
+    enabled = true
+  }
+
+  server_side_encryption {
+    enabled = true
+  }
+
+  tags = {
+    Name = "Product Audit Table"
+  }
+}
+
+resource "aws_dynamodb_table" "product_favorites" {
+  name         = "${var.favorites_table_name}-${random_string.suffix.result}"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "user_id"
+  range_key    = "product_id"
+
+  attribute {
+    name = "user_id"
+    type = "S"
+  }
+
+  attribute {
+    name = "product_id"
+    type = "S"
+  }
+
+  point_in_time_recovery {
+    enabled = true
+  }
+
+  server_side_encryption {
+    enabled = true
+  }
+
+  tags = {
+    Name = "Product Favorites Table"
+  }
+}
+
+resource "aws_dynamodb_table" "reports" {
+  name         = "${var.reports_table_name}-${random_string.suffix.result}"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "name"
+
+  attribute {
+    name = "name"
+    type = "S"
+  }
+
+  server_side_encryption {
+    enabled = true
+  }
+
+  tags = {
+    Name = "Reports Table"
+  }
+}
+
+resource "aws_dynamodb_table" "role_permissions" {
+  name         = "${var.authz_table_name}-${random_string.suffix.result}"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "role"
+
+  attribute {
+    name = "role"
+    type = "S"
+  }
+
+  server_side_encryption {
+    enabled = true
+  }
+
+  tags = {
+    Name = "Role Permissions Table"
+  }
+}
+
+resource "aws_dynamodb_table" "scheduler_locks" {
+  name         = "${var.locks_table_name}-${random_string.suffix.result}"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "name"
+
+  attribute {
+    name = "name"
+    type = "S"
+  }
+
+  ttl {
+    attribute_name = "expires_at"
+    enabled        = true
+  }
+
+  server_side_encryption {
+    enabled = true
+  }
+
+  tags = {
+    Name = "Scheduler Locks Table"
+  }
+}
+
+resource "aws_dynamodb_table" "product_outbox" {
+  name         = "${var.outbox_table_name}-${random_string.suffix.result}"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "id"
+
+  attribute {
+    name = "id"
+    type = "S"
+  }
+
+  attribute {
+    name = "pending"
+    type = "S"
+  }
+
+  attribute {
+    name = "occurred_at"
+    type = "S"
+  }
+
+  # Sparse: only events the relay has not published yet carry "pending"
+  global_secondary_index {
+    name            = "pending-index"
+    hash_key        = "pending"
+    range_key       = "occurred_at"
+    projection_type = "ALL"
+  }
+
+  ttl {
+    attribute_name = "expires_at"
+    enabled        = true
+  }
+
+  server_side_encryption {
+    enabled = true
+  }
+
+  tags = {
+    Name = "Product Outbox Table"
+  }
+}
+
+# One item per SKU or barcode in use, written in the same transaction as
+# the product so no two products of a tenant share a value
+resource "aws_dynamodb_table" "product_unique_keys" {
+  name         = "${var.unique_keys_table_name}-${random_string.suffix.result}"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "id"
+
+  attribute {
+    name = "id"
+    type = "S"
+  }
+
+  ttl {
+    attribute_name = "expires_at"
+    enabled        = true
+  }
+
+  point_in_time_recovery {
+    enabled = true
+  }
+
+  server_side_encryption {
+    enabled = true
+  }
+
+  tags = {
+    Name = "Product Unique Keys Table"
+  }
+}
+
+resource "aws_sns_topic" "product_events" {
+  name              = "${var.events_topic_name}-${random_string.suffix.result}"
+  kms_master_key_id = "alias/aws/sns"
+
+  tags = {
+    Name = "Product Events Topic"
+  }
+}
+
+resource "aws_sns_topic" "product_changes" {
+  name              = "${var.changes_topic_name}-${random_string.suffix.result}"
+  kms_master_key_id = "alias/aws/sns"
+
+  tags = {
+    Name = "Product Changes Topic"
+  }
+}
+
+resource "aws_sqs_queue" "product_imports_dlq" {
+  name                      = "${var.import_queue_name}-dlq-${random_string.suffix.result}"
+  message_retention_seconds = 1209600
+  sqs_managed_sse_enabled   = true
+
+  tags = {
+    Name = "Product Imports Dead-Letter Queue"
+  }
+}
+
+resource "aws_sqs_queue" "product_imports" {
+  name                       = "${var.import_queue_name}-${random_string.suffix.result}"
+  visibility_timeout_seconds = 30
+  receive_wait_time_seconds  = 20
+  sqs_managed_sse_enabled    = true
+
+  redrive_policy = jsonencode({
+    deadLetterTargetArn = aws_sqs_queue.product_imports_dlq.arn
+    maxReceiveCount     = var.import_max_receive_count
+  })
+
+  tags = {
+    Name = "Product Imports Queue"
+  }
+}
+
+resource "aws_s3_bucket" "product_images" {
+  bucket = "${var.images_bucket_name}-${random_string.suffix.result}"
+
+  tags = {
+    Name = "Product Images Bucket"
+  }
+}
+
+resource "aws_s3_bucket_server_side_encryption_configuration" "product_images" {
+  bucket = aws_s3_bucket.product_images.id
+
+  rule {
+    apply_server_side_encryption_by_default {
+      sse_algorithm = "AES256"
+    }
+  }
+}
+
+# Browsers upload straight to the bucket with the presigned PUT URLs
+resource "aws_s3_bucket_cors_configuration" "product_images" {
+  bucket = aws_s3_bucket.product_images.id
+
+  cors_rule {
+    allowed_methods = ["PUT"]
+    allowed_origins = var.images_allowed_origins
+    allowed_headers = ["Content-Type"]
+    max_age_seconds = 3600
+  }
+}
+
 resource "aws_iam_role" "lambda_role" {
   name = "${var.project_name}-lambda-role-${random_string.suffix.result}"
 
@@ -80,16 +675,96 @@ resource "aws_iam_policy" "dynamodb_policy" {
         Effect = "Allow"
         Action = [
           "dynamodb:GetItem",
+          "dynamodb:BatchGetItem",
           "dynamodb:PutItem",
           "dynamodb:UpdateItem",
           "dynamodb:DeleteItem",
+          "dynamodb:BatchWriteItem",
           "dynamodb:Scan",
-          "dynamodb:Query"
+          "dynamodb:Query",
+          "dynamodb:DescribeTable",
+          "dynamodb:DescribeTimeToLive"
+        ]
+        Resource = [
+          aws_dynamodb_table.catalog.arn,
+          "${aws_dynamodb_table.catalog.arn}/*",
+          aws_dynamodb_table.product_views.arn,
+          "${aws_dynamodb_table.product_views.arn}/*",
+          aws_dynamodb_table.search_terms.arn,
+          "${aws_dynamodb_table.search_terms.arn}/*",
+          aws_dynamodb_table.product_cooccurrence.arn,
+          aws_dynamodb_table.product_tombstones.arn,
+          "${aws_dynamodb_table.product_tombstones.arn}/*",
+          aws_dynamodb_table.product_audit.arn,
+          aws_dynamodb_table.product_favorites.arn,
+          aws_dynamodb_table.reports.arn,
+          aws_dynamodb_table.role_permissions.arn,
+          aws_dynamodb_table.scheduler_locks.arn,
+          aws_dynamodb_table.product_outbox.arn,
+          "${aws_dynamodb_table.product_outbox.arn}/*",
+          aws_dynamodb_table.product_unique_keys.arn
         ]
+      },
+      {
+        # Admin PartiQL queries may only read the products table
+        Effect = "Allow"
+        Action = ["dynamodb:PartiQLSelect"]
+        Resource = [
+          aws_dynamodb_table.catalog.arn,
+          "${aws_dynamodb_table.catalog.arn}/index/*"
+        ]
+      },
+      {
+        Effect   = "Allow"
+        Action   = ["comprehend:DetectToxicContent"]
+        Resource = "*"
+      },
+      {
+        Effect   = "Allow"
+        Action   = ["ses:SendEmail"]
+        Resource = "*"
+      },
+      {
+        Effect   = "Allow"
+        Action   = ["firehose:PutRecordBatch"]
+        Resource = "arn:aws:firehose:${var.aws_region}:*:deliverystream/${var.analytics_stream_name}"
+      },
+      {
+        Effect   = "Allow"
+        Action   = ["sns:Publish"]
         Resource = [
-          aws_dynamodb_table.products.arn,
-          "${aws_dynamodb_table.products.arn}/*"
+          aws_sns_topic.product_events.arn,
+          aws_sns_topic.product_changes.arn
+        ]
+      },
+      {
+        Effect = "Allow"
+        Action = [
+          "dynamodb:DescribeStream",
+          "dynamodb:GetShardIterator",
+          "dynamodb:GetRecords"
         ]
+        Resource = aws_dynamodb_table.catalog.stream_arn
+      },
+      {
+        Effect = "Allow"
+        Action = [
+          "sqs:ReceiveMessage",
+          "sqs:DeleteMessage",
+          "sqs:ChangeMessageVisibility"
+        ]
+        Resource = aws_sqs_queue.product_imports.arn
+      },
+      {
+        Effect   = "Allow"
+        Action   = ["sqs:SendMessage"]
+        Resource = aws_sqs_queue.product_imports_dlq.arn
+      },
+      {
+        # Presigned upload URLs act with the permissions of the role that signed them
+        Effect   = "Allow"
+        Action   = ["s3:PutObject"]
+        Resource = "${aws_s3_bucket.product_images.arn}/*"
       }
     ]
   })
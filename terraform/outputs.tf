@@ -1,11 +1,107 @@
 output "dynamodb_table_name" {
-  description = "DynamoDB table name"
-  value       = aws_dynamodb_table.products.name
+  description = "DynamoDB table name (DYNAMODB_TABLE): products, their reviews and categories"
+  value       = aws_dynamodb_table.catalog.name
 }
 
 output "dynamodb_table_arn" {
   description = "DynamoDB table ARN"
-  value       = aws_dynamodb_table.products.arn
+  value       = aws_dynamodb_table.catalog.arn
+}
+
+# Sources of cmd/migrate -copy-*-from until the legacy tables are removed
+output "legacy_products_table_name" {
+  description = "DynamoDB table of products from before the single-table layout"
+  value       = aws_dynamodb_table.products.name
+}
+
+output "legacy_reviews_table_name" {
+  description = "DynamoDB table of reviews from before the single-table layout"
+  value       = aws_dynamodb_table.product_reviews.name
+}
+
+output "legacy_categories_table_name" {
+  description = "DynamoDB table of categories from before the single-table layout"
+  value       = aws_dynamodb_table.categories.name
+}
+
+output "views_table_name" {
+  description = "DynamoDB table name for product view counters"
+  value       = aws_dynamodb_table.product_views.name
+}
+
+output "search_terms_table_name" {
+  description = "DynamoDB table name for search term counters"
+  value       = aws_dynamodb_table.search_terms.name
+}
+
+output "recommendations_table_name" {
+  description = "DynamoDB table name for product co-occurrence counters"
+  value       = aws_dynamodb_table.product_cooccurrence.name
+}
+
+output "tombstones_table_name" {
+  description = "DynamoDB table name for deleted product IDs"
+  value       = aws_dynamodb_table.product_tombstones.name
+}
+
+output "audit_table_name" {
+  description = "DynamoDB table name for the product change history"
+  value       = aws_dynamodb_table.product_audit.name
+}
+
+output "favorites_table_name" {
+  description = "DynamoDB table name for user favorites"
+  value       = aws_dynamodb_table.product_favorites.name
+}
+
+output "reports_table_name" {
+  description = "DynamoDB table name for generated reports"
+  value       = aws_dynamodb_table.reports.name
+}
+
+output "authz_table_name" {
+  description = "DynamoDB table name for the actions each role may perform"
+  value       = aws_dynamodb_table.role_permissions.name
+}
+
+output "locks_table_name" {
+  description = "DynamoDB table name for scheduler leases"
+  value       = aws_dynamodb_table.scheduler_locks.name
+}
+
+output "outbox_table_name" {
+  description = "DynamoDB table name for product events awaiting publication"
+  value       = aws_dynamodb_table.product_outbox.name
+}
+
+output "unique_keys_table_name" {
+  description = "DynamoDB table name for SKU and barcode reservations (UNIQUE_KEYS_TABLE)"
+  value       = aws_dynamodb_table.product_unique_keys.name
+}
+
+output "events_topic_arn" {
+  description = "SNS topic ARN for product change events (EVENTS_TOPIC_ARN)"
+  value       = aws_sns_topic.product_events.arn
+}
+
+output "changes_topic_arn" {
+  description = "SNS topic ARN for table changes published by cmd/streams (STREAM_TOPIC_ARN)"
+  value       = aws_sns_topic.product_changes.arn
+}
+
+output "import_queue_url" {
+  description = "SQS queue URL consumed by cmd/worker (IMPORT_QUEUE_URL)"
+  value       = aws_sqs_queue.product_imports.url
+}
+
+output "import_dlq_url" {
+  description = "SQS dead-letter queue URL for rejected imports (IMPORT_DLQ_URL)"
+  value       = aws_sqs_queue.product_imports_dlq.url
+}
+
+output "images_bucket_name" {
+  description = "S3 bucket for product images (IMAGES_BUCKET)"
+  value       = aws_s3_bucket.product_images.bucket
 }
 
 output "iam_role_arn" {
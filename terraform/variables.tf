@@ -16,8 +16,133 @@ variable "project_name" {
   default     = "product-crud"
 }
 
+variable "catalog_table_name" {
+  description = "DynamoDB table name for products, their reviews and categories"
+  type        = string
+  default     = "products-v2"
+}
+
 variable "table_name" {
-  description = "DynamoDB table name"
+  description = "DynamoDB table name of products from before the single-table layout"
   type        = string
   default     = "products"
-}
\ No newline at end of file
+}
+
+variable "reviews_table_name" {
+  description = "DynamoDB table name of reviews from before the single-table layout"
+  type        = string
+  default     = "product_reviews"
+}
+
+variable "categories_table_name" {
+  description = "DynamoDB table name of categories from before the single-table layout"
+  type        = string
+  default     = "categories"
+}
+
+variable "views_table_name" {
+  description = "DynamoDB table name for product view counters"
+  type        = string
+  default     = "product_views"
+}
+variable "search_terms_table_name" {
+  description = "DynamoDB table name for search term counters"
+  type        = string
+  default     = "search_terms"
+}
+
+variable "recommendations_table_name" {
+  description = "DynamoDB table name for product co-occurrence counters"
+  type        = string
+  default     = "product_cooccurrence"
+}
+
+variable "tombstones_table_name" {
+  description = "DynamoDB table name for deleted product IDs"
+  type        = string
+  default     = "product_tombstones"
+}
+
+variable "audit_table_name" {
+  description = "DynamoDB table name for the product change history"
+  type        = string
+  default     = "product_audit"
+}
+
+variable "favorites_table_name" {
+  description = "DynamoDB table name for user favorites"
+  type        = string
+  default     = "product_favorites"
+}
+
+variable "reports_table_name" {
+  description = "DynamoDB table name for generated reports"
+  type        = string
+  default     = "reports"
+}
+
+variable "authz_table_name" {
+  description = "DynamoDB table name for the actions each role may perform"
+  type        = string
+  default     = "role_permissions"
+}
+
+variable "locks_table_name" {
+  description = "DynamoDB table name for scheduler leases"
+  type        = string
+  default     = "scheduler_locks"
+}
+
+variable "outbox_table_name" {
+  description = "DynamoDB table name for product events awaiting publication"
+  type        = string
+  default     = "product_outbox"
+}
+
+variable "unique_keys_table_name" {
+  description = "DynamoDB table name for SKU and barcode reservations"
+  type        = string
+  default     = "product_unique_keys"
+}
+
+variable "analytics_stream_name" {
+  description = "Firehose delivery stream that receives analytics events"
+  type        = string
+  default     = "product-analytics"
+}
+
+variable "events_topic_name" {
+  description = "Base name for the SNS topic that receives product change events"
+  type        = string
+  default     = "product-events"
+}
+
+variable "changes_topic_name" {
+  description = "Base name for the SNS topic that cmd/streams publishes table changes to"
+  type        = string
+  default     = "product-changes"
+}
+
+variable "import_queue_name" {
+  description = "Base name for the SQS queue that feeds product imports to the worker"
+  type        = string
+  default     = "product-imports"
+}
+
+variable "import_max_receive_count" {
+  description = "Deliveries of an import message before SQS moves it to the dead-letter queue"
+  type        = number
+  default     = 5
+}
+
+variable "images_bucket_name" {
+  description = "S3 bucket name prefix for product images"
+  type        = string
+  default     = "product-images"
+}
+
+variable "images_allowed_origins" {
+  description = "Origins allowed to upload product images from a browser"
+  type        = list(string)
+  default     = ["*"]
+}